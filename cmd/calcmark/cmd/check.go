@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	calcmark "github.com/CalcMark/go-calcmark"
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+	"github.com/spf13/cobra"
+)
+
+var checkFormat string
+
+var checkCmd = &cobra.Command{
+	Use:   "check <file.cm>...",
+	Short: "Report CalcMark files' errors, warnings, and hints",
+	Long: `Evaluate CalcMark files and list their diagnostics, one per line as
+file:line:column: severity: message [code], or with --quiet as JSON lines
+on stderr. With --format=sarif, write them as a SARIF 2.1.0 log instead,
+with a run per file, for CI systems and code review tools that annotate
+source files. Text output counts columns as displayed, with tabs expanded
+to files.tab_width; SARIF counts a tab as one character.
+
+On a terminal, a long run shows its progress on stderr; elsewhere, a run
+over several files logs a line per file to stderr.
+
+Exits with status 1 if any file has errors.
+
+Examples:
+  cm check budget.cm                             List problems
+  cm check docs/*.cm                             Check every document
+  cm check --format=sarif budget.cm > out.sarif  SARIF for CI annotations`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCheck(os.Stdout, args)
+	},
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&checkFormat, "format", "f", "text", "Output format: text or sarif")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// runCheck handles the check subcommand
+func runCheck(w io.Writer, filenames []string) error {
+	if checkFormat != "text" && checkFormat != "sarif" {
+		return usageError(fmt.Errorf("invalid format %q (valid: text, sarif)", checkFormat))
+	}
+	var cfg *config.Config
+	if checkFormat == "text" {
+		var err error
+		if cfg, err = config.Load(); err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+	}
+
+	p := newProgress("check", len(filenames))
+	defer p.close()
+
+	sarif := &semantic.SARIFLog{Schema: semantic.SARIFSchema, Version: semantic.SARIFVersion}
+	var failed []error
+	for _, filename := range filenames {
+		p.begin(filename)
+		diags, content, err := checkFile(filename, p)
+		if err != nil {
+			p.finish(filename, err)
+			return err
+		}
+
+		errors := 0
+		for _, d := range diags {
+			if d.Severity == semantic.Error {
+				errors++
+			}
+		}
+		if errors > 0 {
+			err = evalError(fmt.Errorf("%s: %d error(s)", filename, errors))
+			failed = append(failed, err)
+		}
+		p.finish(filename, err)
+
+		if checkFormat == "sarif" {
+			log := semantic.ToSARIF(filepath.ToSlash(filename), diags)
+			log.Runs[0].Tool.Driver.Version = calcmark.Version
+			sarif.Runs = append(sarif.Runs, log.Runs...)
+			continue
+		}
+		if quiet {
+			writeCheckJSON(os.Stderr, filename, content, diags, cfg)
+			continue
+		}
+		p.pause(func() { writeCheckText(w, filename, content, diags, cfg) })
+	}
+
+	if checkFormat == "sarif" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(sarif); err != nil {
+			return err
+		}
+	}
+	switch {
+	case len(failed) == 0:
+		return nil
+	case len(filenames) == 1:
+		return failed[0]
+	}
+	return evalError(fmt.Errorf("%d of %d files have errors", len(failed), len(filenames)))
+}
+
+// checkFile reads and evaluates a file, returning its problems and content.
+func checkFile(filename string, p *progress) ([]semantic.Diagnostic, string, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, "", ioError(fmt.Errorf("invalid file: %w", err))
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", ioError(fmt.Errorf("read file: %w", err))
+	}
+	eval, err := newFileEvaluator(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	diags, blocks := implDoc.CheckSource(string(content), eval)
+	p.evaluated(blocks)
+	return diags, string(content), nil
+}
+
+// writeCheckText writes a file's problems as
+// file:line:column: severity: message [code].
+func writeCheckText(w io.Writer, filename, content string, diags []semantic.Diagnostic, cfg *config.Config) {
+	for _, d := range diags {
+		line, column := displayPosition(content, d, cfg)
+		fmt.Fprintf(w, "%s:%d:%d: %s: %s", filename, line, column, strings.ToLower(d.Severity.String()), d.Message)
+		if d.Code != "" {
+			fmt.Fprintf(w, " [%s]", d.Code)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// checkDiagnostic is a problem as --quiet writes it, one JSON object a line.
+type checkDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message"`
+}
+
+// writeCheckJSON writes a file's problems as JSON lines.
+func writeCheckJSON(w io.Writer, filename, content string, diags []semantic.Diagnostic, cfg *config.Config) {
+	enc := json.NewEncoder(w)
+	for _, d := range diags {
+		line, column := displayPosition(content, d, cfg)
+		_ = enc.Encode(checkDiagnostic{
+			File:     filename,
+			Line:     line,
+			Column:   column,
+			Severity: strings.ToLower(d.Severity.String()),
+			Code:     d.Code,
+			Message:  d.Message,
+		})
+	}
+}
+
+// displayPosition returns the line and column of a diagnostic in content,
+// 1-indexed. Columns count a tab as one character; report them as displayed.
+func displayPosition(content string, d semantic.Diagnostic, cfg *config.Config) (int, int) {
+	line, column := 1, 1
+	if d.Range != nil {
+		line, column = d.Range.Start.Line, max(d.Range.Start.Column, 1)
+	}
+	if lines := strings.Split(document.NormalizeText(content), "\n"); line <= len(lines) {
+		column = document.DisplayColumn(lines[line-1], column, cfg.Files.Tabs())
+	}
+	return line, column
+}
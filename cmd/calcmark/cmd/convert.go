@@ -3,29 +3,70 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/format/notebook"
+	"github.com/CalcMark/go-calcmark/format/pdf"
+	"github.com/CalcMark/go-calcmark/format/xlsx"
 	implDoc "github.com/CalcMark/go-calcmark/impl/document"
 	"github.com/CalcMark/go-calcmark/spec/document"
 	"github.com/spf13/cobra"
 )
 
 var (
-	convertFormat   string
-	convertOutput   string
-	convertTemplate string
+	convertFormat     string
+	convertOutput     string
+	convertTemplate   string
+	convertProvenance bool
+	convertResults    string
+	convertHeader     string
+	convertWASM       string
+	convertSet        []string
+	convertAllowData  []string
+	convertExpandEnv  bool
 )
 
 var convertCmd = &cobra.Command{
 	Use:   "convert <file.cm>",
 	Short: "Convert CalcMark to another format",
-	Long: `Convert a CalcMark file to HTML, Markdown, JSON, text, or CalcMark format.
+	Long: `Convert a CalcMark file to HTML, Markdown, JSON, text, or CalcMark format,
+render a compact report of its headline variables for email or Slack,
+typeset it as a printable PDF, export its variables and results as a
+CSV or Excel table, or share it as a single HTML file that recalculates
+in the browser.
 
 Examples:
   cm convert doc.cm --to=html              Convert to HTML (stdout)
   cm convert doc.cm --to=md -o doc.md      Convert to Markdown file
   cm convert doc.cm --to=json              Convert to JSON
-  cm convert doc.cm --to=html -T tpl.html  Use custom HTML template`,
+  cm convert doc.cm --to=html -T tpl.html  Use custom HTML template
+  cm convert doc.cm --to=html --results=table  Show results in a table per block
+  cm convert doc.cm --to=cm --provenance   Annotate results with their inputs
+  cm convert doc.cm --to=report            HTML fragment of exported variables
+  cm convert doc.cm --to=report-text       Plain-text report for Slack or email
+  cm convert doc.cm --to=csv               Variables, expressions, and values as CSV
+  cm convert doc.cm --to=xlsx -o doc.xlsx  The same as an Excel workbook
+  cm convert doc.cm --to=pdf -o doc.pdf    Printable report with page headers
+  cm convert doc.cm --to=pdf -o doc.pdf --header='{title}|Page {page} of {pages}'
+  cm convert doc.cm --to=html-interactive -o doc.html  Notebook readers can tweak offline
+  cm convert doc.cm --to=report --set tax_rate=0.28  Report with another global value
+
+PDF page headers read title, author, and date from the document's
+frontmatter. A header has up to three '|'-separated sections (left,
+center, right) using {title}, {author}, {date}, {page}, and {pages}.
+
+Interactive notebooks embed the document and the CalcMark WASM engine, so
+readers can change its frontmatter globals and see every result update,
+offline. Release builds of cm have the engine built in. Others read it
+from --wasm, by default the directory holding cm (build it there with
+'task build:wasm:component'), or build it in with 'task build:notebook'.
+--wasm also overrides a built-in engine.
+
+--set overrides a frontmatter global or exchange rate for this conversion,
+as in cm eval; so do CALCMARK_SET_<name> environment variables.
+
+On a terminal, a long conversion shows its progress on stderr.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runConvert(args[0])
@@ -33,10 +74,19 @@ Examples:
 }
 
 func init() {
-	convertCmd.Flags().StringVarP(&convertFormat, "to", "t", "", "Output format: html, md, json, text, cm (required)")
+	convertCmd.Flags().StringVarP(&convertFormat, "to", "t", "", "Output format: html, html-interactive, md, json, text, cm, report, report-text, csv, xlsx, pdf (required)")
 	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "Write to file instead of stdout")
-	convertCmd.Flags().StringVarP(&convertTemplate, "template", "T", "", "Custom Go template (html only)")
+	convertCmd.Flags().StringVarP(&convertTemplate, "template", "T", "", "Custom Go template (html, report)")
+	convertCmd.Flags().StringVar(&convertResults, "results", "", "Where HTML shows results: inline (default) or table (html only)")
+	convertCmd.Flags().StringVar(&convertHeader, "header", "", "Page header, default '"+pdf.DefaultHeader+"' (pdf only)")
+	convertCmd.Flags().BoolVar(&convertProvenance, "provenance", false, "Append '# = ...' comments showing each result's inputs (cm, md only)")
+	convertCmd.Flags().StringVar(&convertWASM, "wasm", "", "Directory with calcmark.wasm and wasm_exec.js, default the built-in engine or cm's own directory (html-interactive only)")
+	convertCmd.Flags().StringArrayVar(&convertSet, "set", nil, setFlagUsage)
+	convertCmd.Flags().StringArrayVar(&convertAllowData, "allow-data", nil, allowDataFlagUsage)
+	convertCmd.Flags().BoolVar(&convertExpandEnv, "expand-env", false, expandEnvFlagUsage)
 	_ = convertCmd.MarkFlagRequired("to")
+	format.RegisterFormatter("xlsx", &xlsx.Formatter{})
+	format.RegisterFormatter("pdf", &pdf.Formatter{})
 	rootCmd.AddCommand(convertCmd)
 }
 
@@ -44,30 +94,62 @@ func init() {
 func runConvert(filename string) error {
 	// Validate file path
 	if err := validateFilePath(filename); err != nil {
-		return fmt.Errorf("invalid file: %w", err)
+		return ioError(fmt.Errorf("invalid file: %w", err))
 	}
 
 	// Read input file
 	content, err := os.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("read file: %w", err)
+		return ioError(fmt.Errorf("read file: %w", err))
 	}
 
+	p := newProgress("convert", 1)
+	defer p.close()
+	p.begin(filename)
+
 	// Parse document
 	doc, err := document.NewDocument(string(content))
 	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		return parseError(fmt.Errorf("parse error: %w", err))
+	}
+	if err := applyOverrides(doc, convertSet); err != nil {
+		return err
 	}
 
 	// Evaluate
-	eval := implDoc.NewEvaluator()
+	eval, err := newFileEvaluator(filename)
+	if err != nil {
+		return err
+	}
+	allowData(eval, filename, convertAllowData)
+	expandEnv(eval, convertExpandEnv)
 	if err := eval.Evaluate(doc); err != nil {
-		return fmt.Errorf("evaluation error: %w", err)
+		return evalError(fmt.Errorf("evaluation error: %w", err))
 	}
+	p.evaluated(len(doc.GetBlocks()))
 
 	// Validate template option
-	if convertTemplate != "" && convertFormat != "html" {
-		return fmt.Errorf("--template is only valid with --to=html")
+	if convertTemplate != "" && convertFormat != "html" && convertFormat != "report" {
+		return usageError(fmt.Errorf("--template is only valid with --to=html or --to=report"))
+	}
+
+	if convertResults != "" && convertFormat != "html" {
+		return usageError(fmt.Errorf("--results is only valid with --to=html"))
+	}
+	if convertResults != "" && convertResults != format.ResultsInline && convertResults != format.ResultsTable {
+		return usageError(fmt.Errorf("unknown --results layout: %s (valid: %s, %s)", convertResults, format.ResultsInline, format.ResultsTable))
+	}
+
+	if convertHeader != "" && convertFormat != "pdf" {
+		return usageError(fmt.Errorf("--header is only valid with --to=pdf"))
+	}
+
+	if convertWASM != "" && convertFormat != "html-interactive" {
+		return usageError(fmt.Errorf("--wasm is only valid with --to=html-interactive"))
+	}
+
+	if convertProvenance && convertFormat != "cm" && convertFormat != "md" {
+		return usageError(fmt.Errorf("--provenance is only valid with --to=cm or --to=md"))
 	}
 
 	// Load custom template if provided
@@ -75,7 +157,7 @@ func runConvert(filename string) error {
 	if convertTemplate != "" {
 		tplContent, err := os.ReadFile(convertTemplate)
 		if err != nil {
-			return fmt.Errorf("read template: %w", err)
+			return ioError(fmt.Errorf("read template: %w", err))
 		}
 		templateContent = string(tplContent)
 	}
@@ -83,20 +165,35 @@ func runConvert(filename string) error {
 	// Validate format name
 	validFormats := map[string]bool{
 		"html": true, "md": true, "json": true, "text": true, "cm": true,
+		"report": true, "report-text": true, "csv": true, "xlsx": true, "pdf": true,
+		"html-interactive": true,
 	}
 	if !validFormats[convertFormat] {
-		return fmt.Errorf("unknown format: %s (valid: html, md, json, text, cm)", convertFormat)
+		return usageError(fmt.Errorf("unknown format: %s (valid: html, html-interactive, md, json, text, cm, report, report-text, csv, xlsx, pdf)", convertFormat))
+	}
+	if convertFormat == "xlsx" && convertOutput == "" {
+		return usageError(fmt.Errorf("--to=xlsx writes a binary workbook and requires --output"))
+	}
+	if convertFormat == "pdf" && convertOutput == "" {
+		return usageError(fmt.Errorf("--to=pdf writes a binary document and requires --output"))
 	}
 
 	// Get formatter
 	formatter := format.GetFormatter(convertFormat, convertOutput)
+	if convertFormat == "html-interactive" {
+		engine, err := loadWASMEngine(convertWASM)
+		if err != nil {
+			return err
+		}
+		formatter = &notebook.Formatter{Engine: engine}
+	}
 
 	// Determine output destination
 	var out *os.File
 	if convertOutput != "" {
 		out, err = os.Create(convertOutput)
 		if err != nil {
-			return fmt.Errorf("create output file: %w", err)
+			return ioError(fmt.Errorf("create output file: %w", err))
 		}
 		defer out.Close()
 	} else {
@@ -105,12 +202,42 @@ func runConvert(filename string) error {
 
 	// Format and write
 	opts := format.Options{
-		Verbose:  true,
-		Template: templateContent,
+		Verbose:       true,
+		IncludeErrors: true,
+		Template:      templateContent,
+		Results:       convertResults,
+		Header:        convertHeader,
+		Diagnostics:   implDoc.FormatDiagnostics(eval.Diagnostics()),
+		Provenance:    convertProvenance,
 	}
-	if err := formatter.Format(out, doc, opts); err != nil {
+	// Writing to a file, the spinner keeps turning while slow formats run
+	write := func() { err = formatter.Format(out, doc, opts) }
+	if out == os.Stdout {
+		p.pause(write)
+	} else {
+		write()
+	}
+	p.finish(filename, err)
+	if err != nil {
 		return fmt.Errorf("format error: %w", err)
 	}
 
 	return nil
 }
+
+// loadWASMEngine reads the WASM engine for interactive notebooks from dir,
+// or returns the one built into cm, or reads the one in the directory
+// holding the cm executable.
+func loadWASMEngine(dir string) (*notebook.Engine, error) {
+	if dir == "" && notebook.Embedded != nil {
+		return notebook.Embedded, nil
+	}
+	if dir == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("locate WASM engine: %w (pass --wasm)", err)
+		}
+		dir = filepath.Dir(exe)
+	}
+	return notebook.LoadEngine(dir)
+}
@@ -4,6 +4,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// editTrace is the file --trace records the session to, "" for none.
+var editTrace string
+
 var editCmd = &cobra.Command{
 	Use:   "edit [file.cm]",
 	Short: "Open the CalcMark document editor",
@@ -11,9 +14,15 @@ var editCmd = &cobra.Command{
 
 The editor shows source on the left and computed results on the right.
 
+With --trace, every edit is written to a file along with the results
+after it, so "cm replay" can step through the session later and show
+when a number changed and which edit changed it.
+
 Examples:
   cm edit                   Open editor with file picker
-  cm edit budget.cm         Open specific file in editor`,
+  cm edit budget.cm         Open specific file in editor
+  cm edit --trace session.trace budget.cm
+                            Record the session for cm replay`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
@@ -25,5 +34,6 @@ Examples:
 }
 
 func init() {
+	editCmd.Flags().StringVar(&editTrace, "trace", "", "Record the session to `FILE` for cm replay")
 	rootCmd.AddCommand(editCmd)
 }
@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/CalcMark/go-calcmark/format"
 	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
 	"github.com/CalcMark/go-calcmark/spec/document"
 	"github.com/spf13/cobra"
 )
 
-var evalVerbose bool
+var (
+	evalVerbose   bool
+	evalSet       []string
+	evalAllowData []string
+	evalExpandEnv bool
+)
 
 var evalCmd = &cobra.Command{
 	Use:   "eval [file.cm]",
@@ -22,7 +29,20 @@ var evalCmd = &cobra.Command{
 Examples:
   cm eval calc.cm           Evaluate file and print result
   cm eval -v calc.cm        Evaluate with verbose output (all values)
-  echo "x = 10" | cm eval   Evaluate from stdin`,
+  echo "x = 10" | cm eval   Evaluate from stdin
+  cm eval report.cm --set tax_rate=0.28 --set USD_EUR=0.91
+                            Evaluate with other frontmatter values
+  cm eval quote.cm --allow-data data/
+                            Let lookup() and jsonpath() read files in data/
+  REGIONS=5 cm eval sizing.cm --expand-env
+                            Fill in globals written as ${REGIONS:-3}
+
+--set overrides a global or exchange rate the frontmatter declares, for
+this run only; the file isn't changed. Environment variables do the same
+for every document declaring the name, with flags taking precedence:
+CALCMARK_SET_tax_rate=0.28 cm eval report.cm
+
+lookup() and jsonpath() can only read files under an --allow-data path.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runEval(args)
@@ -31,26 +51,30 @@ Examples:
 
 func init() {
 	evalCmd.Flags().BoolVarP(&evalVerbose, "verbose", "v", false, "Show all intermediate values")
+	evalCmd.Flags().StringArrayVar(&evalSet, "set", nil, setFlagUsage)
+	evalCmd.Flags().StringArrayVar(&evalAllowData, "allow-data", nil, allowDataFlagUsage)
+	evalCmd.Flags().BoolVar(&evalExpandEnv, "expand-env", false, expandEnvFlagUsage)
 	rootCmd.AddCommand(evalCmd)
 }
 
 // runEval handles the eval subcommand - evaluates and prints the result
 func runEval(args []string) error {
 	var input string
+	var filename string
 	var hasFile bool
 
 	if len(args) > 0 {
-		filename := args[0]
+		filename = args[0]
 		hasFile = true
 
 		// Read from file
 		if err := validateFilePath(filename); err != nil {
-			return fmt.Errorf("invalid file: %w", err)
+			return ioError(fmt.Errorf("invalid file: %w", err))
 		}
 
 		bytes, err := os.ReadFile(filename)
 		if err != nil {
-			return fmt.Errorf("read file: %w", err)
+			return ioError(fmt.Errorf("read file: %w", err))
 		}
 		input = string(bytes)
 	}
@@ -59,24 +83,32 @@ func runEval(args []string) error {
 		// Read from stdin
 		bytes, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return fmt.Errorf("read stdin: %w", err)
+			return ioError(fmt.Errorf("read stdin: %w", err))
 		}
 		input = string(bytes)
 
 		if strings.TrimSpace(input) == "" {
-			return fmt.Errorf("no input provided")
+			return usageError(fmt.Errorf("no input provided"))
 		}
 	}
 
 	// Parse and evaluate
 	doc, err := document.NewDocument(input)
 	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		return parseError(fmt.Errorf("parse error: %w", err))
+	}
+	if err := applyOverrides(doc, evalSet); err != nil {
+		return err
 	}
 
-	eval := implDoc.NewEvaluator()
+	eval, err := newFileEvaluator(filename)
+	if err != nil {
+		return err
+	}
+	allowData(eval, filename, evalAllowData)
+	expandEnv(eval, evalExpandEnv)
 	if err := eval.Evaluate(doc); err != nil {
-		return fmt.Errorf("evaluation error: %w", err)
+		return evalError(fmt.Errorf("evaluation error: %w", err))
 	}
 
 	// Use text formatter for eval output
@@ -92,3 +124,55 @@ func runEval(args []string) error {
 
 	return nil
 }
+
+// newFileEvaluator creates an evaluator whose imports load .cm files from
+// the current directory, relative to filename (empty for stdin).
+func newFileEvaluator(filename string) (*implDoc.Evaluator, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine working directory: %w", err)
+	}
+	resolver, err := implDoc.NewFileResolver(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	name := ""
+	if filename != "" {
+		if name, err = filepath.Abs(filename); err != nil {
+			return nil, ioError(fmt.Errorf("invalid file: %w", err))
+		}
+	}
+
+	eval := implDoc.NewEvaluator()
+	eval.SetResolver(resolver, name)
+	return eval, nil
+}
+
+// allowDataFlagUsage describes --allow-data for the commands that take it.
+const allowDataFlagUsage = "Let lookup() and jsonpath() read files in this directory, or this file (repeatable)"
+
+// allowData lets eval's lookup() and jsonpath() read files under paths,
+// naming them relative to filename's directory. Without paths, reading
+// files stays disabled.
+func allowData(eval *implDoc.Evaluator, filename string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	base := ""
+	if filename != "" {
+		base = filepath.Dir(filename)
+	}
+	eval.SetDataPolicy(&interpreter.DataPolicy{AllowedPaths: paths, BaseDir: base})
+}
+
+// expandEnvFlagUsage describes --expand-env for the commands that take it.
+const expandEnvFlagUsage = "Fill in ${NAME} and ${NAME:-default} in frontmatter globals from environment variables"
+
+// expandEnv lets eval fill in frontmatter globals from the environment
+// when enabled.
+func expandEnv(eval *implDoc.Evaluator, enabled bool) {
+	if enabled {
+		eval.SetEnvExpansion(os.LookupEnv)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses. Scripts can tell why cm failed from its status alone:
+// results go to stdout, and errors and diagnostics to stderr, as JSON with
+// --quiet.
+const (
+	exitFailure = 1 // The document has errors, or something else failed
+	exitUsage   = 2 // A bad command, flag, or argument
+	exitParse   = 3 // The document couldn't be parsed
+	exitIO      = 4 // A file couldn't be read or written
+)
+
+// quiet prints errors and diagnostics as JSON, and no progress or usage.
+var quiet bool
+
+// cliError is an error that ends cm with a particular exit status.
+type cliError struct {
+	status int
+	kind   string // Names the failure in JSON output
+	err    error
+}
+
+func (e *cliError) Error() string { return e.err.Error() }
+func (e *cliError) Unwrap() error { return e.err }
+
+// usageError marks err as a bad command line.
+func usageError(err error) error { return &cliError{exitUsage, "usage", err} }
+
+// parseError marks err as a document that couldn't be parsed.
+func parseError(err error) error { return &cliError{exitParse, "parse", err} }
+
+// ioError marks err as a file that couldn't be read or written.
+func ioError(err error) error { return &cliError{exitIO, "io", err} }
+
+// evalError marks err as a document with errors.
+func evalError(err error) error { return &cliError{exitFailure, "evaluation", err} }
+
+// exitStatus returns the status cm ends with after err.
+func exitStatus(err error) int {
+	var ce *cliError
+	if errors.As(err, &ce) {
+		return ce.status
+	}
+	return exitFailure
+}
+
+// reportError writes err to w, as a JSON object with --quiet:
+// {"error": "read file: ...", "kind": "io", "status": 4}.
+func reportError(w io.Writer, cmd *cobra.Command, err error) {
+	kind := "error"
+	var ce *cliError
+	if errors.As(err, &ce) {
+		kind = ce.kind
+	}
+	if quiet {
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": err.Error(), "kind": kind, "status": exitStatus(err)})
+		return
+	}
+	fmt.Fprintln(w, "Error:", err)
+	if kind == "usage" && cmd != nil {
+		fmt.Fprint(w, "\n"+cmd.UsageString())
+	}
+}
+
+// markUsageErrors makes argument and flag errors of cmd and its
+// subcommands usage errors.
+func markUsageErrors(cmd *cobra.Command) {
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, a []string) error {
+			if err := args(cmd, a); err != nil {
+				return usageError(err)
+			}
+			return nil
+		}
+	}
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error { return usageError(err) })
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// requiredFlagsError marks cobra's missing required flag errors, which it
+// returns unwrapped, as usage errors.
+func requiredFlagsError(err error) error {
+	if strings.HasPrefix(err.Error(), "required flag(s)") {
+		return usageError(err)
+	}
+	return err
+}
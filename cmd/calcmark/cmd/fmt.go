@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/spf13/cobra"
+)
+
+var fmtWrite bool
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt <file.cm>",
+	Short: "Format a CalcMark file",
+	Long: `Rewrite a CalcMark file in canonical form: one space around operators,
+canonical thousands separators, and the "=" of assignments aligned within
+each calculation block. Markdown text and frontmatter are left as written,
+and formatting never changes what a document computes. Windows line
+endings and a byte order mark are kept unless the [files] line_endings
+config setting is "normalize".
+
+Examples:
+  cm fmt budget.cm              Print the formatted file
+  cm fmt --write budget.cm      Format the file in place`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFmt(os.Stdout, args[0])
+	},
+}
+
+func init() {
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "Write the result back to the file instead of stdout")
+	rootCmd.AddCommand(fmtCmd)
+}
+
+// runFmt handles the fmt subcommand
+func runFmt(w io.Writer, filename string) error {
+	if err := validateFilePath(filename); err != nil {
+		return ioError(fmt.Errorf("invalid file: %w", err))
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return ioError(fmt.Errorf("read file: %w", err))
+	}
+
+	formatted, err := format.Source(string(content))
+	if err != nil {
+		return parseError(fmt.Errorf("%s: %w", filename, err))
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if !cfg.Files.Normalize() {
+		// Keep the file's byte order mark and line endings
+		formatted = document.DetectTextStyle(string(content)).Apply(formatted)
+	}
+
+	if !fmtWrite {
+		_, err := io.WriteString(w, formatted)
+		return err
+	}
+	if formatted == string(content) {
+		return nil
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, []byte(formatted), info.Mode().Perm())
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/learn"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var learnReset bool
+
+var learnCmd = &cobra.Command{
+	Use:   "learn",
+	Short: "Learn CalcMark with an interactive tutorial",
+	Long: `Walk through CalcMark's syntax, numbers, variables, currencies, units,
+and dates, with short lessons and exercises checked as you type them.
+
+Progress is saved in ~/.config/calcmark/learn.json, so the tutorial picks
+up where you left off.
+
+Examples:
+  cm learn            Start or resume the tutorial
+  cm learn --reset    Start over from the first lesson`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLearn()
+	},
+}
+
+func init() {
+	learnCmd.Flags().BoolVar(&learnReset, "reset", false, "Forget saved progress and start over")
+	rootCmd.AddCommand(learnCmd)
+}
+
+// runLearn handles the learn subcommand
+func runLearn() error {
+	path, err := learn.DefaultProgressPath()
+	if err != nil {
+		return err
+	}
+	if learnReset {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return ioError(fmt.Errorf("reset progress: %w", err))
+		}
+	}
+	progress, err := learn.LoadProgress(path)
+	if err != nil {
+		return ioError(err)
+	}
+
+	p := tea.NewProgram(learn.New(progress), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	return nil
+}
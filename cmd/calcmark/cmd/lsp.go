@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/lsp"
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run the CalcMark language server",
+	Long: `Run a Language Server Protocol server over stdin and stdout, for
+editors such as VS Code and Neovim.
+
+The server evaluates open .cm documents as they change and provides
+diagnostics, hover values, go-to-definition for variables, a per-block
+document outline, and completion for variable names and units.
+
+Example Neovim setup:
+  vim.lsp.start({ name = "calcmark", cmd = { "cm", "lsp" } })`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return lsp.NewServer(os.Stdin, os.Stdout, Version).Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/examples"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newExample string
+	newForce   bool
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new --example <name> [file.cm]",
+	Short: "Create a document from a worked example",
+	Long: `Create a CalcMark document from an annotated example, so you start
+from something that works instead of an empty file. Each example explains
+the features it uses; change the numbers to yours.
+
+Available examples:
+` + exampleList() + `
+The file defaults to <name>.cm in the current directory. Use - to print the
+example instead, and --force to replace an existing file.
+
+Examples:
+  cm new --example budget                Write budget.cm
+  cm new --example invoice march.cm      Write march.cm
+  cm new --example recipe -              Print the recipe example`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := newExample + ".cm"
+		if len(args) > 0 {
+			filename = args[0]
+		}
+		return runNew(os.Stdout, newExample, filename)
+	},
+}
+
+func init() {
+	newCmd.Flags().StringVar(&newExample, "example", "", "Example to start from: "+strings.Join(examples.Names(), ", "))
+	newCmd.Flags().BoolVarP(&newForce, "force", "f", false, "Replace the file if it exists")
+	_ = newCmd.MarkFlagRequired("example")
+	_ = newCmd.RegisterFlagCompletionFunc("example", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return examples.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+	rootCmd.AddCommand(newCmd)
+}
+
+// exampleList formats the examples for help text, one per line.
+func exampleList() string {
+	var b strings.Builder
+	for _, ex := range examples.Examples {
+		fmt.Fprintf(&b, "  %-10s %s\n", ex.Name, ex.Description)
+	}
+	return b.String()
+}
+
+// runNew handles the new subcommand
+func runNew(w io.Writer, example, filename string) error {
+	source, err := examples.Source(example)
+	if err != nil {
+		return usageError(err)
+	}
+	if filename == "-" {
+		_, err := io.WriteString(w, source)
+		return err
+	}
+	// The editor and eval only open these, so a new file must be one
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != ".cm" && ext != ".calcmark" {
+		return usageError(fmt.Errorf("%s: file must end in .cm or .calcmark", filename))
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if newForce {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(filename, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return ioError(fmt.Errorf("%s already exists (use --force to replace it)", filename))
+	}
+	if err != nil {
+		return ioError(fmt.Errorf("create file: %w", err))
+	}
+	if _, err := io.WriteString(f, source); err != nil {
+		f.Close()
+		return ioError(fmt.Errorf("write file: %w", err))
+	}
+	if err := f.Close(); err != nil {
+		return ioError(fmt.Errorf("write file: %w", err))
+	}
+	fmt.Fprintf(w, "Created %s from the %s example. Open it with: cm %s\n", filename, example, filename)
+	return nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// overrideEnvPrefix starts the environment variables that override
+// frontmatter, as CALCMARK_SET_tax_rate=0.28 does.
+const overrideEnvPrefix = "CALCMARK_SET_"
+
+// setFlagUsage describes --set for the commands that take it.
+const setFlagUsage = "Override a frontmatter global or exchange rate, as name=value (repeatable)"
+
+// applyOverrides sets the frontmatter globals and exchange rates named by
+// CALCMARK_SET_ environment variables, then by sets from --set flags, each
+// name=value. An environment variable applies only to documents declaring
+// its name, so CI can set one for a batch of documents; a flag naming
+// something the document doesn't declare is an error.
+func applyOverrides(doc *document.Document, sets []string) error {
+	fm := doc.GetFrontmatter()
+	for _, env := range os.Environ() {
+		name, value, ok := strings.Cut(strings.TrimPrefix(env, overrideEnvPrefix), "=")
+		if !ok || !strings.HasPrefix(env, overrideEnvPrefix) {
+			continue
+		}
+		if !fm.HasGlobal(name) && !fm.HasExchangeRate(name) {
+			continue
+		}
+		if err := doc.Override(name, value); err != nil {
+			return usageError(fmt.Errorf("%s%s: %w", overrideEnvPrefix, name, err))
+		}
+	}
+
+	for _, set := range sets {
+		name, value, ok := strings.Cut(set, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" {
+			return usageError(fmt.Errorf("--set %s: expected name=value", set))
+		}
+		if err := doc.Override(name, value); err != nil {
+			return usageError(fmt.Errorf("--set %s: %w", set, err))
+		}
+	}
+	return nil
+}
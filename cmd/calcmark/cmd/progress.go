@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Progress for long runs: on a terminal, a spinner line on stderr shows the
+// files processed, the blocks evaluated, and the time left, so a run over
+// many files doesn't look hung. Elsewhere, such as in CI, runs over several
+// files log a structured line per file to stderr instead.
+
+const (
+	// progressDelay is how long a run goes before the spinner shows, so
+	// quick runs print nothing extra.
+	progressDelay = 300 * time.Millisecond
+
+	// progressTick is how often the spinner redraws.
+	progressTick = 100 * time.Millisecond
+)
+
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// progress reports a command's way through its files. Its methods are safe
+// to call while the spinner redraws.
+type progress struct {
+	verb  string // What the command does to a file, e.g. "check"
+	total int
+	out   io.Writer
+	log   *slog.Logger // Set when out isn't a terminal
+	start time.Time
+
+	mu      sync.Mutex
+	current string
+	done    int
+	blocks  int
+	before  int // Blocks when the current file began
+	frame   int
+	drawn   bool
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// newProgress starts reporting progress through total files on stderr,
+// unless --quiet. Call close when the run ends.
+func newProgress(verb string, total int) *progress {
+	p := &progress{verb: verb, total: total, out: os.Stderr, start: time.Now()}
+	if quiet {
+		return p
+	}
+	if !isTerminal(os.Stderr) {
+		if total > 1 {
+			p.log = slog.New(slog.NewTextHandler(os.Stderr, nil))
+		}
+		return p
+	}
+	p.stop, p.stopped = make(chan struct{}), make(chan struct{})
+	go p.spin()
+	return p
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// begin notes that work on file has started.
+func (p *progress) begin(file string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = file
+	p.before = p.blocks
+}
+
+// evaluated adds blocks evaluated in the current file.
+func (p *progress) evaluated(blocks int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blocks += blocks
+}
+
+// finish notes that work on file has ended, with err if it failed.
+func (p *progress) finish(file string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.current = ""
+	if p.log == nil {
+		return
+	}
+	attrs := []any{"file", file, "done", p.done, "total", p.total, "blocks", p.blocks - p.before}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	p.log.Info(p.verb, attrs...)
+}
+
+// pause runs write, which prints the command's own output, with the
+// spinner line cleared.
+func (p *progress) pause(write func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	write()
+}
+
+// close stops the spinner, or logs a summary of the run.
+func (p *progress) close() {
+	if p.stop != nil {
+		close(p.stop)
+		<-p.stopped
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	if p.log != nil {
+		p.log.Info(p.verb+" done", "files", p.done, "blocks", p.blocks, "elapsed", time.Since(p.start).Round(time.Millisecond))
+	}
+}
+
+// spin redraws the spinner line until close.
+func (p *progress) spin() {
+	defer close(p.stopped)
+	select {
+	case <-time.After(progressDelay):
+	case <-p.stop:
+		return
+	}
+	ticker := time.NewTicker(progressTick)
+	defer ticker.Stop()
+	for {
+		p.mu.Lock()
+		p.draw()
+		p.mu.Unlock()
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// draw writes the spinner line over the previous one. The caller holds mu.
+func (p *progress) draw() {
+	p.frame++
+	fmt.Fprintf(p.out, "\r\033[K%c %s", spinnerFrames[p.frame%len(spinnerFrames)], p.status())
+	p.drawn = true
+}
+
+// clear erases the spinner line. The caller holds mu.
+func (p *progress) clear() {
+	if p.drawn {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+// status describes the run so far: "check 3/12 files, 1204 blocks, about
+// 4s left: budget.cm". A single file shows its name and blocks only.
+func (p *progress) status() string {
+	s := p.verb
+	if p.total > 1 {
+		s += fmt.Sprintf(" %d/%d files,", p.done, p.total)
+	}
+	s += fmt.Sprintf(" %d blocks", p.blocks)
+	if p.total > 1 && p.done > 0 {
+		perFile := time.Since(p.start) / time.Duration(p.done)
+		s += fmt.Sprintf(", about %s left", (perFile * time.Duration(p.total-p.done)).Round(time.Second))
+	}
+	if p.current != "" {
+		s += ": " + p.current
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/trace"
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/replay"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayPrint bool
+	replayVar   string
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <session.trace>",
+	Short: "Step through an editing session recorded with --trace",
+	Long: `Step forward and back through a session recorded by "cm edit --trace",
+seeing each edit and the results after it. The results each edit changed
+are listed first, so a number that changed unexpectedly can be traced to
+the edit that changed it.
+
+With --var, only the steps that changed that variable are shown. With
+--print, the steps are printed instead of browsed.
+
+Keys: → or n next step, ← or p previous, g and G first and last, j and k
+scroll the results, q quit.
+
+Examples:
+  cm replay session.trace                     Browse the session
+  cm replay --var total session.trace         Stop only where total changed
+  cm replay --print --var total session.trace Print when total changed`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReplay(args[0])
+	},
+}
+
+func init() {
+	replayCmd.Flags().BoolVar(&replayPrint, "print", false, "Print the steps instead of browsing them")
+	replayCmd.Flags().StringVar(&replayVar, "var", "", "Only the steps that changed `NAME`")
+	rootCmd.AddCommand(replayCmd)
+}
+
+// runReplay handles the replay subcommand
+func runReplay(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return ioError(fmt.Errorf("open trace: %w", err))
+	}
+	defer f.Close()
+	tr, err := trace.Read(f)
+	if err != nil {
+		return parseError(fmt.Errorf("read trace: %w", err))
+	}
+
+	if replayPrint {
+		return tr.Print(os.Stdout, replayVar)
+	}
+	p := tea.NewProgram(replay.New(tr, replayVar), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -18,7 +17,21 @@ Examples:
   cm budget.cm                    Open file in editor
   cm eval calc.cm                 Evaluate file and print result
   cm eval < input.cm              Evaluate from stdin
-  cm convert doc.cm --to=html     Convert to HTML`,
+  cm convert doc.cm --to=html     Convert to HTML
+  cm stats --memory doc.cm        Show document size and memory use
+  cm fmt --write doc.cm           Format a file in place
+  cm check --format=sarif doc.cm  Report problems for CI annotations
+  cm watch doc.cm                 Print results as the file changes
+  cm replay session.trace         Step through an edit --trace session
+  cm lsp                          Run the language server for editors
+  cm learn                        Interactive tutorial
+  cm new --example budget         Start from a worked example
+  cm serve --port 8080            Serve an HTTP API for evaluation
+
+Exit status is 0 on success, 1 if a document has errors (or another
+failure), 2 for a bad command line, 3 if a document can't be parsed, and
+4 if a file can't be read or written. Results go to stdout; errors and
+diagnostics go to stderr, as JSON lines with --quiet.`,
 	// Allow 0 or 1 file argument
 	Args: cobra.MaximumNArgs(1),
 	// When called without subcommand, run REPL
@@ -33,15 +46,24 @@ Examples:
 	},
 }
 
-// Execute runs the root command.
+// Execute runs the root command and exits with the status for its error:
+// 2 for usage errors, 3 for parse errors, 4 for I/O errors, and 1 for
+// evaluation errors and other failures.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	markUsageErrors(rootCmd)
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
+		err = requiredFlagsError(err)
+		reportError(os.Stderr, cmd, err)
+		os.Exit(exitStatus(err))
 	}
 }
 
 func init() {
 	// Disable default completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	// Errors are reported by Execute, with usage help for usage errors only
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Write errors and diagnostics to stderr as JSON, with no progress or usage help")
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/screenshot"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/spf13/cobra"
+)
+
+var (
+	screenshotOutput string
+	screenshotWidth  int
+	screenshotSource bool
+)
+
+var screenshotCmd = &cobra.Command{
+	Use:   "screenshot <file.cm>",
+	Short: "Render the document's preview as an SVG image",
+	Long: `Evaluate a CalcMark file and render it as the editor's preview shows it
+(rendered markdown and each calculation's result) into an SVG image, for
+embedding up-to-date results in wikis and pages that can't run CalcMark.
+
+Examples:
+  cm screenshot budget.cm -o budget.svg           Preview pane only
+  cm screenshot budget.cm -o budget.svg --source  Source and preview side by side
+  cm screenshot budget.cm --width=120 > wide.svg  Wider layout, to stdout`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runScreenshot(args[0])
+	},
+}
+
+func init() {
+	screenshotCmd.Flags().StringVarP(&screenshotOutput, "output", "o", "", "Write to file instead of stdout")
+	screenshotCmd.Flags().IntVarP(&screenshotWidth, "width", "w", screenshot.DefaultWidth, "Layout width in columns")
+	screenshotCmd.Flags().BoolVar(&screenshotSource, "source", false, "Show the source beside the preview")
+	rootCmd.AddCommand(screenshotCmd)
+}
+
+// runScreenshot handles the screenshot subcommand
+func runScreenshot(filename string) error {
+	if err := validateFilePath(filename); err != nil {
+		return ioError(fmt.Errorf("invalid file: %w", err))
+	}
+	if screenshotWidth < 20 {
+		return usageError(fmt.Errorf("--width must be at least 20 columns"))
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return ioError(fmt.Errorf("read file: %w", err))
+	}
+	doc, err := document.NewDocument(string(content))
+	if err != nil {
+		return parseError(fmt.Errorf("parse error: %w", err))
+	}
+
+	out := os.Stdout
+	if screenshotOutput != "" {
+		out, err = os.Create(screenshotOutput)
+		if err != nil {
+			return ioError(fmt.Errorf("create output file: %w", err))
+		}
+		defer out.Close()
+	}
+
+	opts := screenshot.Options{Width: screenshotWidth, Source: screenshotSource}
+	if err := screenshot.Render(out, filename, doc, opts); err != nil {
+		return fmt.Errorf("render error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/server"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	servePort         int
+	serveHost         string
+	serveTimeout      time.Duration
+	serveMaxDocuments int
+	serveStore        string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP API for evaluating documents",
+	Long: `Serve a JSON API over HTTP, so other programs can evaluate CalcMark
+without linking Go or running cm per document.
+
+Endpoints (request bodies are JSON, e.g. {"source": "x = 1 + 2"}):
+  POST   /evaluate                      Results, as cm convert --to=json writes them
+  POST   /validate                      Problems, as cm check reports them
+  POST   /convert                       {"source", "format"}: html, md, json, text,
+                                        cm, report, report-text, or csv
+  POST   /documents                     Open a document for editing; returns its id
+  GET    /documents/{id}                Every block of an open document
+  PATCH  /documents/{id}/blocks/{block} {"source"}: replace a block, evaluating
+                                        only the blocks it affects
+  DELETE /documents/{id}                Close a document and delete it from the store
+  GET    /documents/{id}/snapshots      Times of the document's scheduled snapshots
+  GET    /documents/{id}/snapshots/{time}  One snapshot: its results at that time
+
+Open documents are kept in memory unless --store names a directory, a
+SQLite database as sqlite:path/to/file.db, or an S3 bucket as
+s3://bucket/prefix (credentials, region, and endpoint come from the
+AWS_* environment variables). Servers sharing a store
+share its documents. Responses give each document's hash (also its
+ETag); PATCH with If-Match fails with 412 if the document has changed,
+and an edit racing one on another server fails with 409.
+
+POST /documents also takes "tags". Each [[serve.webhooks]] entry in the
+config file is posted {"document", "tags", "hash", "time", "changes"}
+when an edit changes the variables it watches:
+
+  [[serve.webhooks]]
+  url = "https://hooks.example.com/finance"
+  tags = ["finance"]                  # Documents with any of these tags
+  variables = ["projected_burn"]      # Variables it watches
+  threshold = 1000                    # Least change in a number reported
+
+POST /documents also takes a "schedule", a cron expression in UTC such
+as "0 9 * * 1-5" or "@daily". On schedule the document is read from the
+store again and evaluated afresh, picking up edits from other servers
+and values like today(); the results are stored as a dated snapshot and
+webhooks are told what changed. CalcMark has no rate providers or
+@source inputs yet, so nothing external is re-fetched. Servers sharing
+a store run each scheduled evaluation once. cm watch doesn't schedule.
+
+Request bodies are limited to 1 MB, and each request and block evaluation
+to --timeout. Documents can't import files. The server listens on
+localhost unless --host says otherwise; it has no authentication.
+
+Examples:
+  cm serve                                Listen on 127.0.0.1:8080
+  cm serve --port 9000 --timeout 2s
+  cm serve --store ./documents            Keep documents across restarts
+  cm serve --store sqlite:documents.db    Keep them in a SQLite database
+  curl -d '{"source": "x = 2 + 3"}' localhost:8080/evaluate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServe()
+	},
+}
+
+func init() {
+	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Address to listen on; 0.0.0.0 for every interface")
+	serveCmd.Flags().DurationVar(&serveTimeout, "timeout", server.DefaultTimeout, "Longest a request or block evaluation may take")
+	serveCmd.Flags().IntVar(&serveMaxDocuments, "max-documents", server.DefaultMaxDocuments, "Documents open at once")
+	serveCmd.Flags().StringVar(&serveStore, "store", "", "Where to keep open documents: a directory, sqlite:file.db, or s3://bucket/prefix (default memory)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe handles the serve subcommand
+func runServe() error {
+	if serveTimeout <= 0 {
+		return usageError(fmt.Errorf("--timeout must be positive"))
+	}
+	if serveMaxDocuments <= 0 {
+		return usageError(fmt.Errorf("--max-documents must be positive"))
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := openStore(serveStore)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := serveWebhooks(cfg.Serve.Webhooks)
+	if err != nil {
+		return err
+	}
+
+	handler := server.New(server.Options{
+		Timeout:      serveTimeout,
+		MaxDocuments: serveMaxDocuments,
+		Store:        store,
+		Webhooks:     webhooks,
+		Limits: implDoc.Limits{
+			MaxVariables: cfg.Limits.MaxVariables,
+			MaxResults:   cfg.Limits.MaxResults,
+		},
+	})
+	go handler.RunSchedules(context.Background())
+
+	addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return ioError(fmt.Errorf("listen: %w", err))
+	}
+	fmt.Printf("Serving the CalcMark API on http://%s\n", listener.Addr())
+
+	srv := &http.Server{
+		Handler: handler,
+		// Bodies are small; slow clients shouldn't hold connections
+		ReadTimeout:  serveTimeout,
+		WriteTimeout: 2 * serveTimeout,
+	}
+	return srv.Serve(listener)
+}
+
+// openStore returns the document store --store names: nil (memory) when
+// it's empty, SQLite for sqlite:path, S3 for s3://bucket/prefix, and
+// otherwise a directory.
+func openStore(spec string) (server.DocumentStore, error) {
+	switch {
+	case spec == "":
+		return nil, nil
+	case strings.HasPrefix(spec, "s3://"):
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(spec, "s3://"), "/")
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		store, err := server.NewS3Store(server.S3ConfigFromEnv(bucket, prefix))
+		if err != nil {
+			return nil, usageError(err)
+		}
+		return store, nil
+	case strings.HasPrefix(spec, "sqlite:"):
+		store, err := server.NewSQLiteStore(strings.TrimPrefix(spec, "sqlite:"))
+		if err != nil {
+			return nil, ioError(err)
+		}
+		return store, nil
+	}
+	store, err := server.NewFileStore(spec)
+	if err != nil {
+		return nil, ioError(err)
+	}
+	return store, nil
+}
+
+// serveWebhooks returns the server's webhooks from [[serve.webhooks]].
+func serveWebhooks(hooks []config.WebhookConfig) ([]server.Webhook, error) {
+	var webhooks []server.Webhook
+	for _, h := range hooks {
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, usageError(fmt.Errorf("serve.webhooks: url %q must be an http or https URL", h.URL))
+		}
+		webhooks = append(webhooks, server.Webhook{
+			URL:       h.URL,
+			Documents: h.Documents,
+			Tags:      h.Tags,
+			Variables: h.Variables,
+			Threshold: decimal.NewFromFloat(h.Threshold),
+		})
+	}
+	return webhooks, nil
+}
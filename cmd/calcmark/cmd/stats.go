@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/spf13/cobra"
+)
+
+var statsMemory bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats <file.cm>",
+	Short: "Show document size statistics",
+	Long: `Show how many blocks, variables, and results a CalcMark file has.
+
+With --memory, also estimate the memory its environment and results hold,
+and compare against the size limits in the [limits] config section.
+
+Examples:
+  cm stats budget.cm              Block, variable, and result counts
+  cm stats --memory budget.cm     Include estimated memory and limits`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStats(os.Stdout, args[0])
+	},
+}
+
+func init() {
+	statsCmd.Flags().BoolVarP(&statsMemory, "memory", "m", false, "Estimate memory usage and check size limits")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// runStats handles the stats subcommand
+func runStats(w io.Writer, filename string) error {
+	if err := validateFilePath(filename); err != nil {
+		return ioError(fmt.Errorf("invalid file: %w", err))
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return ioError(fmt.Errorf("read file: %w", err))
+	}
+
+	doc, err := document.NewDocument(string(content))
+	if err != nil {
+		return parseError(fmt.Errorf("parse error: %w", err))
+	}
+
+	eval, err := newFileEvaluator(filename)
+	if err != nil {
+		return err
+	}
+	if err := eval.Evaluate(doc); err != nil {
+		return evalError(fmt.Errorf("evaluation error: %w", err))
+	}
+
+	var calcBlocks, textBlocks int
+	for _, node := range doc.GetBlocks() {
+		if node.Block.Type() == document.BlockCalculation {
+			calcBlocks++
+		} else {
+			textBlocks++
+		}
+	}
+
+	stats := eval.MemoryStats(doc)
+	fmt.Fprintf(w, "Blocks:     %d (%d calculation, %d text)\n", calcBlocks+textBlocks, calcBlocks, textBlocks)
+	fmt.Fprintf(w, "Variables:  %d\n", stats.Variables)
+	fmt.Fprintf(w, "Results:    %d\n", stats.Results)
+
+	if !statsMemory {
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	eval.SetLimits(implDoc.Limits{
+		MaxVariables: cfg.Limits.MaxVariables,
+		MaxResults:   cfg.Limits.MaxResults,
+	})
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Memory (estimated):")
+	fmt.Fprintf(w, "  Environment:  %s\n", formatBytes(stats.EnvironmentBytes))
+	fmt.Fprintf(w, "  Results:      %s\n", formatBytes(stats.ResultBytes))
+	fmt.Fprintf(w, "  Total:        %s\n", formatBytes(stats.TotalBytes()))
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Limits:     %s variables, %s results\n",
+		limitString(cfg.Limits.MaxVariables), limitString(cfg.Limits.MaxResults))
+	for _, diag := range eval.CheckLimits(doc) {
+		fmt.Fprintf(w, "  warning: %s\n", diag.Message)
+	}
+	return nil
+}
+
+// formatBytes returns n in B, KB, or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
+
+// limitString returns a configured limit for "Limits: N variables", or
+// "no limit on" when the limit is disabled.
+func limitString(limit int) string {
+	if limit <= 0 {
+		return "no limit on"
+	}
+	return fmt.Sprintf("%d", limit)
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	implDoc "github.com/CalcMark/go-calcmark/impl/document"
 	"github.com/CalcMark/go-calcmark/spec/document"
 
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui"
@@ -27,7 +26,7 @@ func runEdit(filepath string) {
 		doc, err = loadAndEvaluate(filepath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading file: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitStatus(err))
 		}
 	} else {
 		// Start with empty document
@@ -36,6 +35,18 @@ func runEdit(filepath string) {
 
 	// Always use Editor app for edit command
 	app := tui.NewEditorApp(doc, filepath)
+	if editTrace != "" {
+		f, err := os.Create(editTrace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating trace: %v\n", err)
+			os.Exit(exitIO)
+		}
+		defer f.Close()
+		if err := app.TraceTo(f); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing trace: %v\n", err)
+			os.Exit(exitIO)
+		}
+	}
 	runTUIApp(app)
 }
 
@@ -51,22 +62,25 @@ func runTUIApp(app *tui.App) {
 // loadAndEvaluate loads a file and evaluates it
 func loadAndEvaluate(path string) (*document.Document, error) {
 	if err := validateFilePath(path); err != nil {
-		return nil, err
+		return nil, ioError(err)
 	}
 
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("read file: %w", err)
+		return nil, ioError(fmt.Errorf("read file: %w", err))
 	}
 
 	doc, err := document.NewDocument(string(content))
 	if err != nil {
-		return nil, fmt.Errorf("parse document: %w", err)
+		return nil, parseError(fmt.Errorf("parse document: %w", err))
 	}
 
-	eval := implDoc.NewEvaluator()
+	eval, err := newFileEvaluator(path)
+	if err != nil {
+		return nil, err
+	}
 	if err := eval.Evaluate(doc); err != nil {
-		return nil, fmt.Errorf("evaluate: %w", err)
+		return nil, evalError(fmt.Errorf("evaluate: %w", err))
 	}
 
 	return doc, nil
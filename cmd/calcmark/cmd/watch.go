@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/watch"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <file.cm>",
+	Short: "Re-evaluate a file whenever it changes",
+	Long: `Evaluate a CalcMark file, print its results, and then print only the
+results that change each time the file is saved. Edits re-evaluate just
+the changed blocks and the blocks that depend on them.
+
+Each line is a calculation followed by its value, so the output can be
+piped into other tools or left running in a split terminal pane.
+
+Examples:
+  cm watch budget.cm                 Print results as budget.cm changes
+  cm watch budget.cm | tee log.txt   Keep a log of every change`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWatch(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
+
+// runWatch handles the watch subcommand
+func runWatch(filename string) error {
+	if err := validateFilePath(filename); err != nil {
+		return ioError(fmt.Errorf("invalid file: %w", err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	w := watch.New(filename, os.Stdout, func() (*implDoc.Evaluator, error) {
+		return newFileEvaluator(filename)
+	})
+	return w.Run(ctx, os.Stderr)
+}
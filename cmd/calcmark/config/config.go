@@ -7,12 +7,17 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/spf13/viper"
 )
 
 //go:embed defaults.toml
 var defaultsToml string
 
+// ProjectConfigFile is the per-project config file, read from the current
+// directory. It overrides user config.
+const ProjectConfigFile = ".calcmark.toml"
+
 var (
 	cfg     *Config
 	styles  Styles
@@ -28,6 +33,7 @@ func Load() (*Config, error) {
 		cfg, loadErr = load()
 		if cfg != nil {
 			styles = cfg.TUI.Theme.BuildStyles()
+			cfg.Currency.apply()
 		}
 	})
 	return cfg, loadErr
@@ -80,7 +86,13 @@ func load() (*Config, error) {
 		}
 	}
 
-	// 3. Unmarshal into struct
+	// 3. Merge project config (highest priority) so teams can share lint rules
+	if _, statErr := os.Stat(ProjectConfigFile); statErr == nil {
+		v.SetConfigFile(ProjectConfigFile)
+		_ = v.MergeInConfig()
+	}
+
+	// 4. Unmarshal into struct
 	var c Config
 	if err := v.Unmarshal(&c); err != nil {
 		return nil, err
@@ -102,3 +114,11 @@ func Reload() (*Config, error) {
 func Error() error {
 	return loadErr
 }
+
+// apply registers the configured currency precision overrides.
+func (c CurrencyConfig) apply() {
+	for code, places := range c.Precision {
+		// Viper lowercases map keys; currency codes are uppercase
+		types.SetCurrencyDecimals(strings.ToUpper(code), places)
+	}
+}
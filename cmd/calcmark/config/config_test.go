@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
 )
 
 func TestLoad_DefaultsOnly(t *testing.T) {
@@ -26,6 +28,18 @@ func TestLoad_DefaultsOnly(t *testing.T) {
 	if !cfg.TUI.DarkMode {
 		t.Error("expected dark_mode true by default")
 	}
+	if cfg.Lint.MaxExpressionDepth != 4 || cfg.Lint.MaxExpressionOperands != 8 {
+		t.Errorf("expected lint defaults 4/8, got %d/%d", cfg.Lint.MaxExpressionDepth, cfg.Lint.MaxExpressionOperands)
+	}
+	if cfg.Limits.MaxVariables != 10000 || cfg.Limits.MaxResults != 50000 {
+		t.Errorf("expected limits defaults 10000/50000, got %d/%d", cfg.Limits.MaxVariables, cfg.Limits.MaxResults)
+	}
+	if cfg.Files.LineEndings != "preserve" || cfg.Files.Normalize() {
+		t.Errorf("expected line_endings preserve by default, got %q", cfg.Files.LineEndings)
+	}
+	if cfg.Files.TabWidth != 4 || cfg.Files.Tabs() != 4 {
+		t.Errorf("expected tab_width 4 by default, got %d", cfg.Files.TabWidth)
+	}
 }
 
 func TestLoad_UserConfigMerge(t *testing.T) {
@@ -68,6 +82,54 @@ primary = "#ABCDEF"
 	}
 }
 
+func TestLoad_ProjectConfig(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	// User config enables snake_case; project config adds currency suffixes
+	// and overrides the user's depth limit
+	configDir := filepath.Join(tmpHome, ".config", "calcmark")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	userConfig := `[lint]
+max_expression_depth = 6
+
+[lint.naming]
+snake_case = true
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(userConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	projectConfig := `[lint]
+max_expression_depth = 3
+
+[lint.naming]
+currency_suffix = true
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ProjectConfigFile), []byte(projectConfig), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+	t.Chdir(projectDir)
+
+	cfg, err := Reload()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if cfg.Lint.MaxExpressionDepth != 3 {
+		t.Errorf("expected project override 3, got %d", cfg.Lint.MaxExpressionDepth)
+	}
+	if !cfg.Lint.Naming.SnakeCase || !cfg.Lint.Naming.CurrencySuffix {
+		t.Errorf("expected user and project naming rules merged, got %+v", cfg.Lint.Naming)
+	}
+	if len(cfg.Lint.Naming.AllowedSingleLetter) != 2 {
+		t.Errorf("expected default allowed single letters, got %v", cfg.Lint.Naming.AllowedSingleLetter)
+	}
+}
+
 func TestLoad_FallbackConfig(t *testing.T) {
 	// Create temp home directory
 	tmpHome := t.TempDir()
@@ -175,3 +237,64 @@ func TestGetStyles_AfterLoad(t *testing.T) {
 		t.Error("expected non-empty styled output")
 	}
 }
+
+func TestLoad_CurrencyPrecision(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	projectDir := t.TempDir()
+	projectConfig := `[currency.precision]
+ETH = 6
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ProjectConfigFile), []byte(projectConfig), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+	t.Chdir(projectDir)
+	t.Cleanup(func() { types.SetCurrencyDecimals("ETH", types.CryptoCurrencies["ETH"]) })
+
+	if _, err := Reload(); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if got := types.CurrencyDecimals("ETH"); got != 6 {
+		t.Errorf("expected ETH precision 6, got %d", got)
+	}
+	if got := types.CurrencyDecimals("BTC"); got != 8 {
+		t.Errorf("expected default BTC precision 8, got %d", got)
+	}
+}
+
+func TestLoad_Webhooks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	projectDir := t.TempDir()
+	projectConfig := `[[serve.webhooks]]
+url = "https://hooks.example.com/finance"
+tags = ["finance"]
+variables = ["projected_burn"]
+threshold = 1000
+
+[[serve.webhooks]]
+url = "https://hooks.example.com/all"
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ProjectConfigFile), []byte(projectConfig), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+	t.Chdir(projectDir)
+
+	cfg, err := Reload()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	hooks := cfg.Serve.Webhooks
+	if len(hooks) != 2 {
+		t.Fatalf("expected 2 webhooks, got %+v", hooks)
+	}
+	if hooks[0].URL != "https://hooks.example.com/finance" || hooks[0].Threshold != 1000 ||
+		len(hooks[0].Tags) != 1 || len(hooks[0].Variables) != 1 {
+		t.Errorf("unexpected first webhook %+v", hooks[0])
+	}
+	if len(hooks[1].Tags) != 0 || hooks[1].Threshold != 0 {
+		t.Errorf("expected the second webhook to match everything, got %+v", hooks[1])
+	}
+}
@@ -27,6 +27,14 @@ type Styles struct {
 	CurrentLine lipgloss.Style // Current line highlight in normal mode
 	LineNumber  lipgloss.Style // Line number style
 
+	// Calculation syntax styles, by lexer token
+	SyntaxNumber     lipgloss.Style
+	SyntaxCurrency   lipgloss.Style
+	SyntaxUnit       lipgloss.Style
+	SyntaxOperator   lipgloss.Style
+	SyntaxIdentifier lipgloss.Style
+	SyntaxKeyword    lipgloss.Style
+
 	// Markdown preview styles
 	MdText   lipgloss.Style // Body text
 	MdH1     lipgloss.Style // H1 heading
@@ -112,6 +120,25 @@ func (t ThemeConfig) BuildStyles() Styles {
 		LineNumber: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(t.LineNumber)),
 
+		// Calculation syntax styles
+		SyntaxNumber: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.SyntaxNumber)),
+
+		SyntaxCurrency: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.SyntaxCurrency)),
+
+		SyntaxUnit: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.SyntaxUnit)),
+
+		SyntaxOperator: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.SyntaxOperator)),
+
+		SyntaxIdentifier: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.SyntaxIdentifier)),
+
+		SyntaxKeyword: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.SyntaxKeyword)),
+
 		// Markdown preview styles
 		MdText: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(t.MdText)),
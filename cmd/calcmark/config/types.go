@@ -2,16 +2,31 @@
 // Configuration is loaded from TOML files with embedded defaults.
 package config
 
+import "github.com/CalcMark/go-calcmark/spec/document"
+
 // Config is the root configuration structure.
 type Config struct {
 	TUI       TUIConfig       `mapstructure:"tui"`
 	Formatter FormatterConfig `mapstructure:"formatter"`
+	Lint      LintConfig      `mapstructure:"lint"`
+	Limits    LimitsConfig    `mapstructure:"limits"`
+	Currency  CurrencyConfig  `mapstructure:"currency"`
+	Files     FilesConfig     `mapstructure:"files"`
+	Serve     ServeConfig     `mapstructure:"serve"`
 }
 
 // TUIConfig holds TUI-specific settings.
 type TUIConfig struct {
 	Theme    ThemeConfig `mapstructure:"theme"`
 	DarkMode bool        `mapstructure:"dark_mode"`
+
+	// VerifyPrecision re-evaluates the document at full precision when idle
+	// and warns if any displayed result would change.
+	VerifyPrecision bool `mapstructure:"verify_precision"`
+
+	// Mouse lets the editor take mouse input: clicks, the wheel, and drag
+	// selection. Off leaves the terminal's own text selection working.
+	Mouse bool `mapstructure:"mouse"`
 }
 
 // ThemeConfig defines all TUI colors as hex strings.
@@ -35,6 +50,14 @@ type ThemeConfig struct {
 	CurrentLineFg string `mapstructure:"current_line_fg"` // Foreground for current line in normal mode
 	LineNumber    string `mapstructure:"line_number"`     // Line number color
 
+	// Calculation syntax colors in the editor's source pane
+	SyntaxNumber     string `mapstructure:"syntax_number"`     // Numbers, dates, durations, and times
+	SyntaxCurrency   string `mapstructure:"syntax_currency"`   // Currency symbols and codes
+	SyntaxUnit       string `mapstructure:"syntax_unit"`       // Units of quantities, time zones
+	SyntaxOperator   string `mapstructure:"syntax_operator"`   // Arithmetic, comparison, logic, and "="
+	SyntaxIdentifier string `mapstructure:"syntax_identifier"` // Variable names
+	SyntaxKeyword    string `mapstructure:"syntax_keyword"`    // Keywords, functions, and booleans
+
 	// Markdown preview colors
 	MdText    string `mapstructure:"md_text"`    // Markdown body text
 	MdH1Bg    string `mapstructure:"md_h1_bg"`   // H1 background
@@ -52,3 +75,77 @@ type FormatterConfig struct {
 	IncludeErrors bool   `mapstructure:"include_errors"`
 	DefaultFormat string `mapstructure:"default_format"`
 }
+
+// LintConfig holds thresholds for readability hints.
+// A value of 0 disables that check.
+type LintConfig struct {
+	MaxExpressionDepth    int `mapstructure:"max_expression_depth"`    // Nested operation levels
+	MaxExpressionOperands int `mapstructure:"max_expression_operands"` // Literals and variable references
+
+	Naming NamingConfig `mapstructure:"naming"`
+}
+
+// NamingConfig holds optional variable naming conventions, all off by default.
+type NamingConfig struct {
+	SnakeCase           bool     `mapstructure:"snake_case"`            // monthly_cost, not monthlyCost
+	NoSingleLetter      bool     `mapstructure:"no_single_letter"`      // Reject one-letter names...
+	AllowedSingleLetter []string `mapstructure:"allowed_single_letter"` // ...except these
+	CurrencySuffix      bool     `mapstructure:"currency_suffix"`       // price_usd, rent_eur
+}
+
+// LimitsConfig holds document size guardrails, warned about when exceeded.
+// A value of 0 disables that check.
+type LimitsConfig struct {
+	MaxVariables int `mapstructure:"max_variables"` // Variables defined, including constants
+	MaxResults   int `mapstructure:"max_results"`   // Results held across all calculation blocks
+}
+
+// CurrencyConfig holds currency display settings.
+type CurrencyConfig struct {
+	// Precision overrides the decimal places shown per currency code,
+	// e.g. {"ETH": 6}. Codes are case-insensitive.
+	Precision map[string]int32 `mapstructure:"precision"`
+}
+
+// ServeConfig holds settings for cm serve.
+type ServeConfig struct {
+	Webhooks []WebhookConfig `mapstructure:"webhooks"`
+}
+
+// WebhookConfig is a [[serve.webhooks]] entry: a URL cm serve posts to
+// when an edit changes the variables it watches. Empty filters match
+// everything.
+type WebhookConfig struct {
+	URL       string   `mapstructure:"url"`
+	Documents []string `mapstructure:"documents"` // Document IDs
+	Tags      []string `mapstructure:"tags"`      // Tags given when documents are created
+	Variables []string `mapstructure:"variables"`
+	Threshold float64  `mapstructure:"threshold"` // Least change in a number, currency, or quantity reported
+}
+
+// FilesConfig controls how files are written back to disk.
+type FilesConfig struct {
+	// LineEndings is "preserve" to keep a file's byte order mark and
+	// "\r\n" line endings when saving or formatting it, or "normalize" to
+	// write "\n" endings and no byte order mark.
+	LineEndings string `mapstructure:"line_endings"`
+
+	// TabWidth is the number of columns between tab stops when the editor
+	// shows a tab and when errors report a column after one.
+	TabWidth int `mapstructure:"tab_width"`
+}
+
+// Normalize reports whether files are written with "\n" line endings and
+// no byte order mark, whatever they had when read.
+func (c FilesConfig) Normalize() bool {
+	return c.LineEndings == "normalize"
+}
+
+// Tabs returns the tab width, or document.DefaultTabWidth if it isn't
+// positive.
+func (c FilesConfig) Tabs() int {
+	if c.TabWidth < 1 {
+		return document.DefaultTabWidth
+	}
+	return c.TabWidth
+}
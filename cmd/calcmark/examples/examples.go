@@ -0,0 +1,48 @@
+// Package examples holds the annotated documents `cm new --example` starts
+// from, one for each kind of user: budget, invoice, capacity, and recipe.
+package examples
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed *.cm
+var files embed.FS
+
+// Example is an embedded example document.
+type Example struct {
+	Name        string
+	Description string
+}
+
+// Examples lists the examples in the order help text shows them.
+var Examples = []Example{
+	{Name: "budget", Description: "Monthly household budget with savings and an emergency fund"},
+	{Name: "invoice", Description: "Freelance invoice with hourly rates, tax, and a due date"},
+	{Name: "capacity", Description: "Service sizing: traffic, servers, storage, and uptime"},
+	{Name: "recipe", Description: "Recipe scaled to a batch, with conversions and costs"},
+}
+
+// Names returns the example names, in order.
+func Names() []string {
+	names := make([]string, len(Examples))
+	for i, ex := range Examples {
+		names[i] = ex.Name
+	}
+	return names
+}
+
+// Source returns the source of the named example.
+func Source(name string) (string, error) {
+	if !slices.Contains(Names(), name) {
+		return "", fmt.Errorf("unknown example %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	data, err := files.ReadFile(name + ".cm")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,77 @@
+package examples
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// TestExamplesEvaluate checks every example evaluates cleanly, with no
+// errors or warnings, since users start from them.
+func TestExamplesEvaluate(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			source, err := Source(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := document.NewDocument(source)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			eval := implDoc.NewEvaluator()
+			if err := eval.Evaluate(doc); err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+
+			calcBlocks := 0
+			for _, node := range doc.GetBlocks() {
+				cb, ok := node.Block.(*document.CalcBlock)
+				if !ok {
+					continue
+				}
+				calcBlocks++
+				if err := cb.Error(); err != nil {
+					t.Errorf("block %q: %v", strings.Join(cb.Source(), "\n"), err)
+				}
+				for _, d := range cb.Diagnostics() {
+					t.Errorf("block %q: %s", strings.Join(cb.Source(), "\n"), d.Message)
+				}
+			}
+			for _, d := range eval.Diagnostics() {
+				t.Errorf("diagnostic: %s", d.Message)
+			}
+			if calcBlocks < 4 {
+				t.Errorf("only %d calculation blocks; examples should be fully worked", calcBlocks)
+			}
+			if doc.GetFrontmatter() == nil {
+				t.Error("examples should show frontmatter globals")
+			}
+		})
+	}
+}
+
+func TestEveryFileListed(t *testing.T) {
+	matches, err := fs.Glob(files, "*.cm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range matches {
+		if !slices.Contains(Names(), strings.TrimSuffix(m, ".cm")) {
+			t.Errorf("%s is embedded but not in Examples", m)
+		}
+	}
+	if len(matches) != len(Examples) {
+		t.Errorf("%d files for %d examples", len(matches), len(Examples))
+	}
+}
+
+func TestUnknownExample(t *testing.T) {
+	if _, err := Source("payroll"); err == nil || !strings.Contains(err.Error(), "budget, invoice") {
+		t.Errorf("err = %v", err)
+	}
+}
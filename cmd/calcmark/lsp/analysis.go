@@ -0,0 +1,515 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/CalcMark/go-calcmark/spec/units"
+)
+
+// file is an open document and the results of its last evaluation.
+type file struct {
+	uri   string
+	lines []string // The full text, frontmatter included
+	doc   *document.Document
+	eval  *implDoc.Evaluator
+	spans []blockSpan
+	err   error // Set when the document could not be parsed at all
+}
+
+// blockSpan places a block in the file's lines.
+type blockSpan struct {
+	node  *document.BlockNode
+	start int // Index of the block's first line in file.lines
+}
+
+// analyze parses and evaluates text with eval.
+func analyze(uri, text string, eval *implDoc.Evaluator) *file {
+	// Positions exclude line endings and a byte order mark, as in the client
+	f := &file{uri: uri, lines: strings.Split(document.NormalizeText(text), "\n"), eval: eval}
+
+	doc, err := document.NewDocument(text)
+	if err != nil {
+		f.err = err
+		return f
+	}
+	f.doc = doc
+	eval.Evaluate(doc) // Errors are kept on the blocks for diagnostics
+
+	// Blocks cover the lines after the frontmatter, in order
+	bodyLines := 0
+	for _, node := range doc.GetBlocks() {
+		bodyLines += len(node.Block.Source())
+	}
+	line := max(0, len(f.lines)-bodyLines)
+	for _, node := range doc.GetBlocks() {
+		f.spans = append(f.spans, blockSpan{node: node, start: line})
+		line += len(node.Block.Source())
+	}
+	return f
+}
+
+// spanAt returns the block containing line, and the line's index in it.
+func (f *file) spanAt(line int) (blockSpan, int, bool) {
+	for _, span := range f.spans {
+		if n := len(span.node.Block.Source()); line >= span.start && line < span.start+n {
+			return span, line - span.start, true
+		}
+	}
+	return blockSpan{}, 0, false
+}
+
+// statementAt returns the index of the statement on a calc block's line,
+// assuming one statement per non-empty line as the editor does.
+func statementAt(cb *document.CalcBlock, line int) (int, bool) {
+	source := cb.Source()
+	if line >= len(source) || strings.TrimSpace(source[line]) == "" {
+		return 0, false
+	}
+	idx := 0
+	for _, l := range source[:line] {
+		if strings.TrimSpace(l) != "" {
+			idx++
+		}
+	}
+	return idx, true
+}
+
+// lineResult returns the value computed on a line, if any.
+func (f *file) lineResult(line int) (types.Type, ast.Node, bool) {
+	span, i, ok := f.spanAt(line)
+	if !ok {
+		return nil, nil, false
+	}
+	cb, ok := span.node.Block.(*document.CalcBlock)
+	if !ok {
+		return nil, nil, false
+	}
+	idx, ok := statementAt(cb, i)
+	if !ok || idx >= len(cb.Results()) || cb.Results()[idx] == nil {
+		return nil, nil, false
+	}
+	var stmt ast.Node
+	if idx < len(cb.Statements()) {
+		stmt = cb.Statements()[idx]
+	}
+	return cb.Results()[idx], stmt, true
+}
+
+// diagnostics returns the file's problems: syntax and semantic errors,
+// evaluation errors, and the evaluator's warnings and hints.
+func (f *file) diagnostics() []Diagnostic {
+	diags := []Diagnostic{}
+	if f.err != nil {
+		diags = append(diags, f.diagnostic(0, 0, severityError, "", f.err.Error()))
+	}
+	if f.doc == nil {
+		return diags
+	}
+
+	starts := make(map[string]blockSpan, len(f.spans))
+	for _, span := range f.spans {
+		starts[span.node.ID] = span
+		cb, ok := span.node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		for _, d := range cb.Diagnostics() {
+			line := span.start + errorLine(cb, d.Line)
+			diags = append(diags, f.diagnostic(line, d.Column, severityOf(d.Severity), d.Code, d.Message))
+		}
+		if err := cb.Error(); err != nil && len(cb.Diagnostics()) == 0 {
+			line, column := 0, 0
+			var se *interpreter.StatementError
+			if errors.As(err, &se) && se.Range != nil {
+				line, column = se.Range.Start.Line, se.Range.Start.Column
+			}
+			diags = append(diags, f.diagnostic(span.start+errorLine(cb, line), column, severityError, "", err.Error()))
+		}
+	}
+
+	for _, d := range f.eval.Diagnostics() {
+		line := 0
+		if span, ok := starts[d.BlockID]; ok {
+			line = span.start
+			if cb, ok := span.node.Block.(*document.CalcBlock); ok {
+				line += errorLine(cb, d.Line)
+			}
+		}
+		severity := severityWarning
+		switch d.Severity {
+		case implDoc.Error:
+			severity = severityError
+		case implDoc.Hint:
+			severity = severityHint
+		}
+		diags = append(diags, f.diagnostic(line, 0, severity, d.Code, d.Message))
+	}
+	return diags
+}
+
+// errorLine returns the block line (0-indexed) a diagnostic on line
+// (1-indexed, 0 if unknown) belongs on. Diagnostics without a line go on
+// the first non-empty line.
+func errorLine(cb *document.CalcBlock, line int) int {
+	source := cb.Source()
+	if line > 0 && line <= len(source) {
+		return line - 1
+	}
+	for i, l := range source {
+		if strings.TrimSpace(l) != "" {
+			return i
+		}
+	}
+	return 0
+}
+
+// severityOf maps a document.Diagnostic severity to the protocol's.
+func severityOf(s string) int {
+	switch s {
+	case "warning":
+		return severityWarning
+	case "info":
+		return severityInformation
+	case "hint":
+		return severityHint
+	default:
+		return severityError
+	}
+}
+
+// diagnostic builds a Diagnostic spanning line from column (1-indexed
+// runes; 0 for the first non-blank character) to the end of the line.
+func (f *file) diagnostic(line, column, severity int, code, message string) Diagnostic {
+	text := f.line(line)
+	start := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	if column > 0 {
+		start = runeOffset(text, column-1)
+	}
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: line, Character: utf16Len(text[:start])},
+			End:   Position{Line: line, Character: utf16Len(text)},
+		},
+		Severity: severity,
+		Code:     code,
+		Source:   "calcmark",
+		Message:  message,
+	}
+}
+
+// hover describes the identifier or result at pos.
+func (f *file) hover(pos Position) *Hover {
+	if f.doc == nil {
+		return nil
+	}
+	text := f.line(pos.Line)
+	word, start, end := wordAt(text, byteOffset(text, pos.Character))
+
+	var value string
+	result, stmt, hasResult := f.lineResult(pos.Line)
+	if assign, ok := stmt.(*ast.Assignment); ok && hasResult && assign.Name == word {
+		value = fmt.Sprintf("**%s** = %s", word, display.Format(result))
+	} else if v, ok := f.eval.GetEnvironment().Get(word); ok && word != "" {
+		value = fmt.Sprintf("**%s** = %s", word, display.Format(v))
+	} else if hasResult {
+		value = "= " + display.Format(result)
+		start, end = 0, len(text)
+	} else {
+		return nil
+	}
+
+	return &Hover{
+		Contents: markupContent{Kind: "markdown", Value: value},
+		Range: &Range{
+			Start: Position{Line: pos.Line, Character: utf16Len(text[:start])},
+			End:   Position{Line: pos.Line, Character: utf16Len(text[:end])},
+		},
+	}
+}
+
+// definition returns where the variable at pos is first assigned: a
+// calculation line, or its entry under globals in the frontmatter.
+func (f *file) definition(pos Position) *Location {
+	if f.doc == nil {
+		return nil
+	}
+	text := f.line(pos.Line)
+	word, _, _ := wordAt(text, byteOffset(text, pos.Character))
+	if word == "" {
+		return nil
+	}
+
+	for _, span := range f.spans {
+		cb, ok := span.node.Block.(*document.CalcBlock)
+		if !ok || !slices.Contains(cb.Variables(), word) {
+			continue
+		}
+		for i := range cb.Source() {
+			idx, ok := statementAt(cb, i)
+			if !ok || idx >= len(cb.Statements()) {
+				continue
+			}
+			if assign, ok := cb.Statements()[idx].(*ast.Assignment); ok && assign.Name == word {
+				return f.wordLocation(span.start+i, word)
+			}
+		}
+	}
+
+	// Globals are defined in the frontmatter, before the first block
+	if len(f.spans) > 0 {
+		global := regexp.MustCompile(`^\s+` + regexp.QuoteMeta(word) + `\s*:`)
+		for i := range f.spans[0].start {
+			if global.MatchString(f.lines[i]) {
+				return f.wordLocation(i, word)
+			}
+		}
+	}
+	return nil
+}
+
+// wordLocation returns the range of the first occurrence of word on line.
+func (f *file) wordLocation(line int, word string) *Location {
+	text := f.line(line)
+	start := max(0, strings.Index(text, word))
+	return &Location{
+		URI: f.uri,
+		Range: Range{
+			Start: Position{Line: line, Character: utf16Len(text[:start])},
+			End:   Position{Line: line, Character: utf16Len(text[:start]) + utf16Len(word)},
+		},
+	}
+}
+
+// symbols returns one symbol per non-empty block: calculation blocks
+// with their variables as children, and text blocks.
+func (f *file) symbols() []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+	for _, span := range f.spans {
+		source := span.node.Block.Source()
+		first := slices.IndexFunc(source, func(l string) bool { return strings.TrimSpace(l) != "" })
+		if first < 0 {
+			continue
+		}
+		last := len(source) - 1
+		for strings.TrimSpace(source[last]) == "" {
+			last--
+		}
+
+		symbol := DocumentSymbol{
+			Name: symbolName(source[first]),
+			Kind: symbolKindString,
+			Range: Range{
+				Start: Position{Line: span.start + first},
+				End:   Position{Line: span.start + last, Character: utf16Len(source[last])},
+			},
+		}
+		symbol.SelectionRange = lineRange(span.start+first, source[first])
+
+		if cb, ok := span.node.Block.(*document.CalcBlock); ok {
+			symbol.Kind = symbolKindNamespace
+			for i, line := range source {
+				idx, ok := statementAt(cb, i)
+				if !ok || idx >= len(cb.Statements()) {
+					continue
+				}
+				assign, ok := cb.Statements()[idx].(*ast.Assignment)
+				if !ok {
+					continue
+				}
+				child := DocumentSymbol{
+					Name:           assign.Name,
+					Kind:           symbolKindVariable,
+					Range:          lineRange(span.start+i, line),
+					SelectionRange: f.wordLocation(span.start+i, assign.Name).Range,
+				}
+				if idx < len(cb.Results()) && cb.Results()[idx] != nil {
+					child.Detail = display.Format(cb.Results()[idx])
+				}
+				symbol.Children = append(symbol.Children, child)
+			}
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// symbolName shortens a block's first line for the outline.
+func symbolName(line string) string {
+	name := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+	if r := []rune(name); len(r) > 40 {
+		name = string(r[:39]) + "…"
+	}
+	return name
+}
+
+// completion returns variables, and units after a number or a conversion
+// keyword, that start with the word being typed at pos.
+func (f *file) completion(pos Position) []CompletionItem {
+	text := f.line(pos.Line)
+	offset := byteOffset(text, pos.Character)
+	start := offset
+	for start > 0 && isWordByte(text[start-1]) {
+		start--
+	}
+	prefix := strings.ToLower(text[start:offset])
+
+	items := []CompletionItem{}
+	seen := make(map[string]bool)
+	add := func(item CompletionItem) {
+		if !seen[item.Label] && strings.HasPrefix(strings.ToLower(item.Label), prefix) {
+			seen[item.Label] = true
+			items = append(items, item)
+		}
+	}
+
+	if f.doc != nil {
+		env := f.eval.GetEnvironment()
+		for _, span := range f.spans {
+			if cb, ok := span.node.Block.(*document.CalcBlock); ok {
+				for _, name := range cb.Variables() {
+					item := CompletionItem{Label: name, Kind: completionKindVariable}
+					if v, ok := env.Get(name); ok {
+						item.Detail = display.Format(v)
+					}
+					add(item)
+				}
+			}
+		}
+		for name, v := range env.GetAllVariables() {
+			add(CompletionItem{Label: name, Kind: completionKindVariable, Detail: display.Format(v)})
+		}
+	}
+
+	if wantsUnit(text[:start]) {
+		for _, unit := range units.StandardUnits {
+			for _, name := range append([]string{unit.Canonical}, unit.Aliases...) {
+				add(CompletionItem{Label: name, Kind: completionKindUnit, Detail: unit.Description})
+			}
+		}
+	}
+
+	slices.SortStableFunc(items, func(a, b CompletionItem) int {
+		if a.Kind != b.Kind {
+			return b.Kind - a.Kind // Variables before units
+		}
+		return strings.Compare(a.Label, b.Label)
+	})
+	return items
+}
+
+// wantsUnit reports whether text before the word being typed ends where a
+// unit can go: after a number, or after "in", "to", "per", or "/".
+func wantsUnit(before string) bool {
+	before = strings.TrimRightFunc(before, unicode.IsSpace)
+	if before == "" {
+		return false
+	}
+	if last := before[len(before)-1]; last >= '0' && last <= '9' || last == '/' {
+		return true
+	}
+	fields := strings.Fields(before)
+	switch strings.ToLower(fields[len(fields)-1]) {
+	case "in", "to", "per", "as":
+		return true
+	}
+	return false
+}
+
+// line returns line i of the file, or "" if out of range.
+func (f *file) line(i int) string {
+	if i < 0 || i >= len(f.lines) {
+		return ""
+	}
+	return f.lines[i]
+}
+
+// lineRange spans a whole line.
+func lineRange(line int, text string) Range {
+	return Range{
+		Start: Position{Line: line},
+		End:   Position{Line: line, Character: utf16Len(text)},
+	}
+}
+
+// wordAt returns the identifier containing byte offset i of text, with
+// its byte bounds.
+func wordAt(text string, i int) (string, int, int) {
+	start, end := i, i
+	for start > 0 && isWordByte(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isWordByte(text[end]) {
+		end++
+	}
+	word := text[start:end]
+	if word == "" || (word[0] >= '0' && word[0] <= '9') {
+		return "", i, i
+	}
+	return word, start, end
+}
+
+// isWordByte reports whether b can be part of an identifier. Bytes of
+// multi-byte characters count, so identifiers may use any letters.
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
+
+// containsWord reports whether word occurs in text as a whole identifier.
+func containsWord(text, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(text[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isWordByte(text[start-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteOffset converts a UTF-16 offset in text to a byte offset, clamped
+// to the line.
+func byteOffset(text string, char int) int {
+	n := 0
+	for i, r := range text {
+		if n >= char {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(text)
+}
+
+// runeOffset returns the byte offset of rune i in text, clamped to the line.
+func runeOffset(text string, i int) int {
+	for offset := range text {
+		if i == 0 {
+			return offset
+		}
+		i--
+	}
+	return len(text)
+}
@@ -0,0 +1,71 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxMessageSize bounds a single incoming message, so a bad
+// Content-Length can't make the server allocate without limit.
+const maxMessageSize = 64 * 1024 * 1024
+
+// conn reads and writes LSP base-protocol messages: a Content-Length
+// header, a blank line, then a JSON body.
+type conn struct {
+	r *textproto.Reader
+
+	mu sync.Mutex // Serializes writes
+	w  io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: textproto.NewReader(bufio.NewReader(r)), w: w}
+}
+
+// read returns the next message body. It returns io.EOF when the input
+// closes between messages.
+func (c *conn) read() ([]byte, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	if length > maxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds limit of %d", length, maxMessageSize)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+// write sends v as one message.
+func (c *conn) write(v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
@@ -0,0 +1,220 @@
+// Package lsp implements a Language Server Protocol server for CalcMark
+// over stdio, used by `calcmark lsp`. It evaluates each open document on
+// every change and offers diagnostics, hover values, go-to-definition for
+// variables, a per-block outline, and completion of variables and units.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+)
+
+// evalBlockTimeout bounds each block's evaluation so one slow calculation
+// can't stall the editor; timed-out blocks keep their previous results.
+const evalBlockTimeout = 2 * time.Second
+
+// Server is a CalcMark language server for one client connection.
+type Server struct {
+	conn    *conn
+	version string
+	files   map[string]*file // By URI
+
+	initialized bool
+	shutdown    bool
+}
+
+// NewServer creates a server reading requests from r and writing
+// responses and notifications to w. version is reported to the client.
+func NewServer(r io.Reader, w io.Writer, version string) *Server {
+	return &Server{
+		conn:    newConn(r, w),
+		version: version,
+		files:   make(map[string]*file),
+	}
+}
+
+// Run serves requests until the client sends exit or closes the input.
+// It returns an error if the connection fails or the client exits
+// without shutting down first.
+func (s *Server) Run() error {
+	for {
+		body, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.replyError(nil, codeParseError, fmt.Sprintf("invalid JSON: %v", err))
+			continue
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(&msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches one message. Only write errors are returned; request
+// errors are sent to the client.
+func (s *Server) handle(msg *message) error {
+	if msg.Method == "" {
+		return nil // A response to a server request; we send none
+	}
+	if !s.initialized && msg.Method != "initialize" {
+		if msg.ID != nil {
+			return s.replyError(msg.ID, codeServerNotInitted, "server not initialized")
+		}
+		return nil
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.initialized = true
+		return s.reply(msg.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:       textDocumentSyncFull,
+				HoverProvider:          true,
+				DefinitionProvider:     true,
+				DocumentSymbolProvider: true,
+				CompletionProvider:     completionOptions{TriggerCharacters: []string{" "}},
+			},
+			ServerInfo: serverInfo{Name: "calcmark", Version: s.version},
+		})
+	case "initialized":
+		return nil
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(msg.ID, nil)
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil
+		}
+		return s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// Full sync: the last change holds the whole document
+		return s.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil
+		}
+		delete(s.files, params.TextDocument.URI)
+		return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+
+	case "textDocument/hover":
+		return s.positionRequest(msg, func(f *file, pos Position) any {
+			if h := f.hover(pos); h != nil {
+				return h
+			}
+			return nil
+		})
+	case "textDocument/definition":
+		return s.positionRequest(msg, func(f *file, pos Position) any {
+			if loc := f.definition(pos); loc != nil {
+				return loc
+			}
+			return nil
+		})
+	case "textDocument/completion":
+		return s.positionRequest(msg, func(f *file, pos Position) any {
+			return completionList{Items: f.completion(pos)}
+		})
+	case "textDocument/documentSymbol":
+		var params documentSymbolParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.replyError(msg.ID, codeInvalidParams, err.Error())
+		}
+		f, ok := s.files[params.TextDocument.URI]
+		if !ok {
+			return s.reply(msg.ID, []DocumentSymbol{})
+		}
+		return s.reply(msg.ID, f.symbols())
+	}
+
+	if msg.ID != nil {
+		return s.replyError(msg.ID, codeMethodNotFound, "method not supported: "+msg.Method)
+	}
+	return nil // Unknown notifications, such as $/cancelRequest, are ignored
+}
+
+// positionRequest decodes a request about a position in an open document
+// and replies with answer's result, or null for unknown documents.
+func (s *Server) positionRequest(msg *message, answer func(*file, Position) any) error {
+	var params textDocumentPositionParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return s.replyError(msg.ID, codeInvalidParams, err.Error())
+	}
+	f, ok := s.files[params.TextDocument.URI]
+	if !ok {
+		return s.reply(msg.ID, nil)
+	}
+	return s.reply(msg.ID, answer(f, params.Position))
+}
+
+// update re-evaluates a document and publishes its diagnostics.
+func (s *Server) update(uri, text string) error {
+	f := analyze(uri, text, newEvaluator(uri))
+	s.files[uri] = f
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: f.diagnostics(),
+	})
+}
+
+// newEvaluator creates an evaluator for the document at uri. Documents
+// saved as files may import files in the same directory tree.
+func newEvaluator(uri string) *implDoc.Evaluator {
+	eval := implDoc.NewEvaluator()
+	eval.SetBlockTimeout(evalBlockTimeout)
+
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return eval
+	}
+	path := filepath.FromSlash(u.Path)
+	if resolver, err := implDoc.NewFileResolver(filepath.Dir(path)); err == nil {
+		eval.SetResolver(resolver, path)
+	}
+	return eval
+}
+
+func (s *Server) reply(id *json.RawMessage, result any) error {
+	return s.conn.write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, message string) error {
+	return s.conn.write(errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   responseError{Code: code, Message: message},
+	})
+}
+
+func (s *Server) notify(method string, params any) error {
+	return s.conn.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
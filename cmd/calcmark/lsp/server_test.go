@@ -0,0 +1,266 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// client drives a Server over in-memory pipes.
+type client struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *textproto.Reader
+	nextID int
+	done   chan error
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &client{
+		t:    t,
+		in:   inW,
+		out:  textproto.NewReader(bufio.NewReader(outR)),
+		done: make(chan error, 1),
+	}
+	go func() {
+		c.done <- NewServer(inR, outW, "test").Run()
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+
+	c.request("initialize", map[string]any{"capabilities": map[string]any{}})
+	c.notify("initialized", map[string]any{})
+	return c
+}
+
+func (c *client) send(v any) {
+	c.t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// receive reads the next message from the server.
+func (c *client) receive() map[string]json.RawMessage {
+	c.t.Helper()
+	header, err := c.out.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("read header: %v", err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.out.R, body); err != nil {
+		c.t.Fatalf("read body: %v", err)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatalf("invalid response %s: %v", body, err)
+	}
+	return msg
+}
+
+// request sends a request and decodes its result into a generic value.
+func (c *client) request(method string, params any) any {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	msg := c.receive()
+	if e, ok := msg["error"]; ok {
+		c.t.Fatalf("%s failed: %s", method, e)
+	}
+	var result any
+	json.Unmarshal(msg["result"], &result)
+	return result
+}
+
+func (c *client) notify(method string, params any) {
+	c.send(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// open opens a document and returns its published diagnostics.
+func (c *client) open(uri, text string) []Diagnostic {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "calcmark", "version": 1, "text": text},
+	})
+	return c.diagnostics()
+}
+
+func (c *client) diagnostics() []Diagnostic {
+	c.t.Helper()
+	msg := c.receive()
+	var params publishDiagnosticsParams
+	if err := json.Unmarshal(msg["params"], &params); err != nil {
+		c.t.Fatalf("invalid diagnostics: %v", err)
+	}
+	return params.Diagnostics
+}
+
+func position(uri string, line, char int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": char},
+	}
+}
+
+const testURI = "untitled:budget.cm"
+
+const testDoc = `---
+globals:
+  tax: 0.2
+---
+# Budget
+
+rent = $1,200
+food = $400
+
+
+total = rent + food
+after_tax = total * (1 + tax)
+`
+
+func TestDiagnostics(t *testing.T) {
+	c := newClient(t)
+	if diags := c.open(testURI, testDoc); len(diags) != 0 {
+		t.Errorf("diagnostics = %+v, want none", diags)
+	}
+
+	// An undefined variable is reported on the line using it
+	c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": testURI, "version": 2},
+		"contentChanges": []any{map[string]any{"text": strings.Replace(testDoc, "rent + food", "rent + fuel", 1)}},
+	})
+	diags := c.diagnostics()
+	if len(diags) == 0 {
+		t.Fatal("expected a diagnostic for the undefined variable")
+	}
+	if diags[0].Range.Start.Line != 10 || diags[0].Severity != severityError {
+		t.Errorf("diagnostic = %+v, want an error on line 10", diags[0])
+	}
+	if !strings.Contains(diags[0].Message, "fuel") {
+		t.Errorf("message = %q, want it to name fuel", diags[0].Message)
+	}
+
+	c.notify("textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": testURI}})
+	if diags := c.diagnostics(); len(diags) != 0 {
+		t.Errorf("diagnostics after close = %+v, want none", diags)
+	}
+}
+
+func TestHover(t *testing.T) {
+	c := newClient(t)
+	c.open(testURI, testDoc)
+
+	hover := c.request("textDocument/hover", position(testURI, 10, 9)).(map[string]any)
+	value := hover["contents"].(map[string]any)["value"].(string)
+	if value != "**rent** = $1200.00" {
+		t.Errorf("hover over rent = %q", value)
+	}
+
+	hover = c.request("textDocument/hover", position(testURI, 10, 1)).(map[string]any)
+	value = hover["contents"].(map[string]any)["value"].(string)
+	if value != "**total** = $1600.00" {
+		t.Errorf("hover over total = %q", value)
+	}
+
+	if got := c.request("textDocument/hover", position(testURI, 4, 3)); got != nil {
+		t.Errorf("hover over text = %v, want null", got)
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	c := newClient(t)
+	c.open(testURI, testDoc)
+
+	loc := c.request("textDocument/definition", position(testURI, 10, 16)).(map[string]any)
+	start := loc["range"].(map[string]any)["start"].(map[string]any)
+	if start["line"] != 7.0 || start["character"] != 0.0 {
+		t.Errorf("definition of food at %v, want 7:0", start)
+	}
+
+	// Globals are defined in the frontmatter
+	loc = c.request("textDocument/definition", position(testURI, 11, 26)).(map[string]any)
+	start = loc["range"].(map[string]any)["start"].(map[string]any)
+	if start["line"] != 2.0 || start["character"] != 2.0 {
+		t.Errorf("definition of tax at %v, want 2:2", start)
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	c := newClient(t)
+	c.open(testURI, testDoc)
+
+	raw := c.request("textDocument/documentSymbol", map[string]any{
+		"textDocument": map[string]any{"uri": testURI},
+	})
+	var symbols []DocumentSymbol
+	body, _ := json.Marshal(raw)
+	json.Unmarshal(body, &symbols)
+
+	var got []string
+	for _, s := range symbols {
+		entry := s.Name
+		for _, child := range s.Children {
+			entry += fmt.Sprintf(" [%s=%s]", child.Name, child.Detail)
+		}
+		got = append(got, entry)
+	}
+	want := []string{
+		"Budget",
+		"rent = $1,200 [rent=$1200.00] [food=$400.00]",
+		"total = rent + food [total=$1600.00] [after_tax=$1920.00]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("symbols:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	c := newClient(t)
+	c.open(testURI, testDoc+"x = fo\ny = 5 met\n")
+
+	labels := func(result any) []string {
+		var list completionList
+		body, _ := json.Marshal(result)
+		json.Unmarshal(body, &list)
+		var labels []string
+		for _, item := range list.Items {
+			labels = append(labels, item.Label)
+		}
+		return labels
+	}
+
+	if got := labels(c.request("textDocument/completion", position(testURI, 12, 6))); strings.Join(got, ",") != "food" {
+		t.Errorf("completions for fo = %v, want [food]", got)
+	}
+	got := labels(c.request("textDocument/completion", position(testURI, 13, 9)))
+	if len(got) == 0 || !strings.HasPrefix(got[0], "met") {
+		t.Errorf("completions for 5 met = %v, want units", got)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	c := newClient(t)
+
+	c.nextID++
+	c.send(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": "workspace/symbol", "params": map[string]any{}})
+	if msg := c.receive(); !strings.Contains(string(msg["error"]), strconv.Itoa(codeMethodNotFound)) {
+		t.Errorf("unsupported method reply = %v, want method not found", msg)
+	}
+
+	c.request("shutdown", nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Errorf("Run returned %v after shutdown and exit", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Package screenshot renders a CalcMark document as an SVG image of the
+// editor's preview, used by `calcmark screenshot` to embed up-to-date
+// results in wikis and pages that can't run the WASM build.
+//
+// The document is laid out headlessly by the editor (see
+// editor.Model.RenderLines), so the image shows exactly what the TUI
+// shows: rendered markdown and each calculation's result, optionally
+// beside the source with line numbers. Terminal styling becomes SVG
+// colors on a dark background, one monospace cell per column.
+package screenshot
+
+import (
+	"io"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/editor"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+// DefaultWidth is the image width in columns when Options.Width is 0.
+const DefaultWidth = 80
+
+// Options controls the screenshot layout.
+type Options struct {
+	Width  int  // Columns, DefaultWidth if 0
+	Source bool // Show the source pane beside the preview, as the editor does
+}
+
+// Render writes an SVG screenshot of doc, loaded from filename, to w.
+// Imports resolve relative to filename.
+//
+// Render switches lipgloss to 256-color output, as in a color terminal,
+// so results are styled even when stdout is not a terminal.
+func Render(w io.Writer, filename string, doc *document.Document, opts Options) error {
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	lipgloss.SetColorProfile(termenv.ANSI256)
+
+	m := editor.NewWithFile(filename, doc)
+	if opts.Source {
+		sourceWidth, previewWidth := m.GetPaneWidths(width)
+		source, preview := m.RenderLines(sourceWidth, previewWidth)
+		return writeSVG(w, []pane{
+			{lines: source},
+			{col: sourceWidth + 2, lines: preview, divider: true},
+		})
+	}
+
+	// Wide enough that no source line wraps, which would pad the preview
+	sourceWidth := 0
+	for _, line := range m.GetLines() {
+		sourceWidth = max(sourceWidth, runewidth.StringWidth(line))
+	}
+	_, preview := m.RenderLines(sourceWidth+8, width)
+	return writeSVG(w, []pane{{lines: preview}})
+}
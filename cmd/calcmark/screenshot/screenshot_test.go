@@ -0,0 +1,83 @@
+package screenshot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+func init() {
+	// Initialize config for tests
+	config.Load()
+}
+
+func render(t *testing.T, source string, opts Options) string {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, "doc.cm", doc, opts); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRender(t *testing.T) {
+	svg := render(t, "# Budget & Q3\n\nrent = $1200\nfood = $400\ntotal = rent + food\n", Options{})
+
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Fatalf("not an SVG document:\n%s", svg)
+	}
+	for _, want := range []string{
+		`font-weight="bold">Budget &amp; Q3</tspan>`,
+		`>total</tspan>`,
+		`fill="#11a8cd">$1600.00</tspan>`, // Results in the preview's cyan
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("screenshot missing %s:\n%s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "rent + food") {
+		t.Errorf("preview-only screenshot shows source:\n%s", svg)
+	}
+}
+
+func TestRenderSource(t *testing.T) {
+	svg := render(t, "x = 2\ny = x * 3\n", Options{Width: 60, Source: true})
+	for _, want := range []string{"> y = x * 3</tspan>", ">2</tspan>", ">6</tspan>", "<line "} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("screenshot missing %s:\n%s", want, svg)
+		}
+	}
+}
+
+func TestParseANSI(t *testing.T) {
+	runs := parseANSI("a\x1b[1;38;5;6mb→\x1b[0m \x1b[3;48;2;1;2;3mc\x1b[22m")
+	want := []run{
+		{col: 0, text: "a"},
+		{col: 1, text: "b→", style: style{fg: "#11a8cd", bold: true}},
+		{col: 3, text: " "},
+		{col: 4, text: "c", style: style{bg: "#010203", italic: true}},
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("parseANSI() = %+v, want %+v", runs, want)
+	}
+	for i := range want {
+		if runs[i] != want[i] {
+			t.Errorf("run %d = %+v, want %+v", i, runs[i], want[i])
+		}
+	}
+}
+
+func TestANSIColor(t *testing.T) {
+	for n, want := range map[int]string{1: "#cd3131", 16: "#000000", 208: "#ff8700", 240: "#585858", 256: ""} {
+		if got := ansiColor(n); got != want {
+			t.Errorf("ansiColor(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package screenshot
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Image geometry, in pixels.
+const (
+	fontSize   = 14
+	cellWidth  = 8.4 // Advance of a monospace character at fontSize
+	lineHeight = 18
+	padding    = 16
+)
+
+// Default colors, matching a dark terminal theme.
+const (
+	background = "#1e1e1e"
+	foreground = "#d4d4d4"
+	dividerFG  = "#585858"
+)
+
+// pane is a column of styled lines starting at column col.
+type pane struct {
+	col     int
+	lines   []string
+	divider bool // Draw a vertical rule left of the pane
+}
+
+// style is the SGR state of a run of text. Colors are CSS colors, ""
+// for the default.
+type style struct {
+	fg, bg                         string
+	bold, faint, italic, underline bool
+}
+
+// run is text drawn in one style, starting at column col.
+type run struct {
+	col   int
+	text  string
+	style style
+}
+
+// writeSVG draws the panes as an SVG image.
+func writeSVG(w io.Writer, panes []pane) error {
+	rows, cols := 0, 0
+	parsed := make([][][]run, len(panes))
+	for i, p := range panes {
+		lines := trimTrailingBlank(p.lines)
+		for _, line := range lines {
+			runs := parseANSI(line)
+			parsed[i] = append(parsed[i], runs)
+			if n := len(runs); n > 0 {
+				cols = max(cols, p.col+runs[n-1].col+runewidth.StringWidth(runs[n-1].text))
+			}
+		}
+		rows = max(rows, len(lines))
+	}
+	rows = max(rows, 1)
+
+	width := int(float64(cols)*cellWidth) + 2*padding
+	height := rows*lineHeight + 2*padding
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" rx="6" fill="%s"/>`+"\n", background)
+	fmt.Fprintf(bw, `<g font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" fill="%s" xml:space="preserve">`+"\n", fontSize, foreground)
+
+	for i, p := range panes {
+		if p.divider {
+			x := padding + (float64(p.col)-1)*cellWidth
+			fmt.Fprintf(bw, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s"/>`+"\n", x, padding, x, height-padding, dividerFG)
+		}
+		for row, runs := range parsed[i] {
+			writeLine(bw, p.col, row, runs)
+		}
+	}
+
+	bw.WriteString("</g>\n</svg>\n")
+	return bw.Flush()
+}
+
+// writeLine draws one line's runs: backgrounds first, then the text, each
+// run placed at its column so the grid holds in any monospace font.
+func writeLine(w io.Writer, col, row int, runs []run) {
+	top := padding + row*lineHeight
+	for _, r := range runs {
+		if r.style.bg != "" {
+			fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+				x(col+r.col), top, float64(runewidth.StringWidth(r.text))*cellWidth, lineHeight, r.style.bg)
+		}
+	}
+
+	var spans strings.Builder
+	for _, r := range runs {
+		if strings.TrimSpace(r.text) == "" {
+			continue
+		}
+		fmt.Fprintf(&spans, `<tspan x="%.1f"%s>%s</tspan>`, x(col+r.col), r.style.attrs(), html.EscapeString(r.text))
+	}
+	if spans.Len() > 0 {
+		// Baseline about three quarters down the line
+		fmt.Fprintf(w, `<text y="%d">%s</text>`+"\n", top+lineHeight*3/4+1, spans.String())
+	}
+}
+
+// x returns the left edge of column col.
+func x(col int) float64 {
+	return padding + float64(col)*cellWidth
+}
+
+// attrs returns the SVG attributes for s, with a leading space.
+func (s style) attrs() string {
+	var b strings.Builder
+	if s.fg != "" {
+		fmt.Fprintf(&b, ` fill="%s"`, s.fg)
+	}
+	if s.bold {
+		b.WriteString(` font-weight="bold"`)
+	}
+	if s.italic {
+		b.WriteString(` font-style="italic"`)
+	}
+	if s.underline {
+		b.WriteString(` text-decoration="underline"`)
+	}
+	if s.faint {
+		b.WriteString(` fill-opacity="0.6"`)
+	}
+	return b.String()
+}
+
+// parseANSI splits a line into styled runs, interpreting SGR escape
+// sequences. Other escape sequences are dropped.
+func parseANSI(line string) []run {
+	var runs []run
+	var cur style
+	var text strings.Builder
+	col, start := 0, 0
+
+	flush := func() {
+		if text.Len() > 0 {
+			// Renderers restyle word by word; join runs that look the same
+			if n := len(runs); n > 0 && runs[n-1].style == cur {
+				runs[n-1].text += text.String()
+			} else {
+				runs = append(runs, run{col: start, text: text.String(), style: cur})
+			}
+			text.Reset()
+		}
+		start = col
+	}
+
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' && i+1 < len(line) && line[i+1] == '[' {
+			end := i + 2
+			for end < len(line) && (line[end] < 0x40 || line[end] > 0x7e) {
+				end++
+			}
+			if end < len(line) && line[end] == 'm' {
+				flush()
+				cur = cur.apply(line[i+2 : end])
+			}
+			i = end + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		text.WriteRune(r)
+		col += runewidth.RuneWidth(r)
+		i += size
+	}
+	flush()
+	return runs
+}
+
+// apply returns s updated by the parameters of an SGR sequence, e.g.
+// "1;38;5;6".
+func (s style) apply(params string) style {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, _ := strconv.Atoi(codes[i])
+		switch {
+		case code == 0:
+			s = style{}
+		case code == 1:
+			s.bold = true
+		case code == 2:
+			s.faint = true
+		case code == 3:
+			s.italic = true
+		case code == 4:
+			s.underline = true
+		case code == 22:
+			s.bold, s.faint = false, false
+		case code == 23:
+			s.italic = false
+		case code == 24:
+			s.underline = false
+		case code >= 30 && code <= 37:
+			s.fg = ansiColor(code - 30)
+		case code >= 90 && code <= 97:
+			s.fg = ansiColor(code - 90 + 8)
+		case code >= 40 && code <= 47:
+			s.bg = ansiColor(code - 40)
+		case code >= 100 && code <= 107:
+			s.bg = ansiColor(code - 100 + 8)
+		case code == 39:
+			s.fg = ""
+		case code == 49:
+			s.bg = ""
+		case code == 38 || code == 48:
+			color, n := extendedColor(codes[i+1:])
+			i += n
+			if code == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+		}
+	}
+	return s
+}
+
+// extendedColor reads a 256-color ("5;n") or true color ("2;r;g;b")
+// specification, returning the color and the number of codes used.
+func extendedColor(codes []string) (string, int) {
+	num := func(i int) int {
+		if i >= len(codes) {
+			return 0
+		}
+		n, _ := strconv.Atoi(codes[i])
+		return n
+	}
+	switch num(0) {
+	case 5:
+		return ansiColor(num(1)), 2
+	case 2:
+		return fmt.Sprintf("#%02x%02x%02x", num(1), num(2), num(3)), 4
+	}
+	return "", len(codes)
+}
+
+// palette holds the 16 basic terminal colors.
+var palette = [16]string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+// ansiColor returns the CSS color of 256-color palette entry n.
+func ansiColor(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return palette[n]
+	case n < 232:
+		// 6×6×6 color cube
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + 40*v
+		}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	}
+	gray := 8 + 10*(n-232)
+	return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+}
+
+// trimTrailingBlank drops the blank lines at the end of lines.
+func trimTrailingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(stripANSI(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// stripANSI returns line's text without escape sequences.
+func stripANSI(line string) string {
+	var b strings.Builder
+	for _, r := range parseANSI(line) {
+		b.WriteString(r.text)
+	}
+	return b.String()
+}
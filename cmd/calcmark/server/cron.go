@@ -0,0 +1,122 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression saying when a document is
+// re-evaluated: five fields, minute (0-59), hour (0-23), day of the month
+// (1-31), month (1-12), and day of the week (0-6, Sunday is 0 or 7), each
+// "*", a number, a range "1-5", a step "*/15" or "1-30/5", or a list of
+// those, "0,30". When both days are restricted, either may match, as in
+// cron. @hourly, @daily, @weekly, and @monthly are shorthands. Times are
+// in UTC.
+type Schedule struct {
+	expr                         string
+	minute, hour, dom, month, dw uint64 // Bit n set when n matches
+	domAny, dowAny               bool   // The day fields are "*"
+}
+
+// cronShorthands are the @ forms ParseSchedule accepts.
+var cronShorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseSchedule parses a cron expression.
+func ParseSchedule(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if full, ok := cronShorthands[spec]; ok {
+		spec = full
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q: want 5 fields (minute hour day month weekday), got %d", expr, len(fields))
+	}
+	s := &Schedule{expr: expr}
+	var err error
+	for i, f := range []struct {
+		bits     *uint64
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dw, 0, 7},
+	} {
+		if *f.bits, err = parseCronField(fields[i], f.min, f.max); err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", expr, err)
+		}
+	}
+	if s.dw&(1<<7) != 0 {
+		s.dw |= 1 // 7 is Sunday too
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return s, nil
+}
+
+// parseCronField returns the bits of the values field matches.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("bad value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("bad range %q", part)
+				}
+			} else if hasStep {
+				hi = max // "5/15" is 5, 20, 35, 50
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Matches reports whether the schedule runs in t's minute, in UTC.
+func (s *Schedule) Matches(t time.Time) bool {
+	t = t.UTC()
+	has := func(bits uint64, v int) bool { return bits&(1<<v) != 0 }
+	if !has(s.minute, t.Minute()) || !has(s.hour, t.Hour()) || !has(s.month, int(t.Month())) {
+		return false
+	}
+	domOK, dowOK := has(s.dom, t.Day()), has(s.dw, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.expr
+}
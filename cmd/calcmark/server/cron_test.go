@@ -0,0 +1,47 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Friday 16 October 2026, 09:30 UTC
+	fri := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		at   time.Time
+		want bool
+	}{
+		{"* * * * *", fri, true},
+		{"30 9 * * *", fri, true},
+		{"30 9 * * *", fri.Add(time.Minute), false},
+		{"*/15 * * * *", fri, true},
+		{"*/20 * * * *", fri, false},
+		{"10/20 * * * *", fri, true}, // 10, 30, 50
+		{"0,30 8-10 * * 1-5", fri, true},
+		{"30 9 * * 0,6", fri, false},
+		{"30 9 * * 7", fri.AddDate(0, 0, 2), true}, // 7 is Sunday
+		{"30 9 1 * 5", fri, true},                  // Either day field matches
+		{"30 9 1 * *", fri, false},
+		{"@daily", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), true},
+		{"@monthly", fri, false},
+		{"30 9 * * *", fri.In(time.FixedZone("EST", -5*3600)), true}, // Compared in UTC
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Matches(tt.at); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.expr, tt.at, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@yearly"} {
+		if _, err := ParseSchedule(bad); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded", bad)
+		}
+	}
+}
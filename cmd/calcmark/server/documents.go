@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// openDocument is a document kept open by POST /documents: an evaluated
+// copy of a stored document, with the evaluator holding its variables. Its
+// lock serializes edits, which evaluate incrementally on the previous state.
+type openDocument struct {
+	mu       sync.Mutex
+	id       string
+	hash     string // Of the stored version it matches; "" once it may not match any
+	tags     []string
+	schedule string
+	doc      *document.Document
+	eval     *implDoc.Evaluator
+}
+
+// storedDocument is what the server keeps in its DocumentStore for a document.
+type storedDocument struct {
+	Source   string   `json:"source"`
+	Tags     []string `json:"tags,omitempty"`
+	Schedule string   `json:"schedule,omitempty"`
+	Blocks   []string `json:"blocks,omitempty"` // Block IDs in order, which clients refer to
+}
+
+// DocumentBlock is a block of an open document with its results. Index is
+// its position in the document, which depends_on refers to.
+type DocumentBlock struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+	format.JSONBlock
+}
+
+// DocumentResponse describes an open document after it is created, read,
+// or edited. Blocks lists the blocks whose results may have changed, in
+// document order: every block unless a block was edited. Hash identifies
+// the stored version described, for If-Match; it is also the ETag.
+type DocumentResponse struct {
+	ID            string                  `json:"id"`
+	Hash          string                  `json:"hash"`
+	Tags          []string                `json:"tags,omitempty"`
+	Schedule      string                  `json:"schedule,omitempty"`
+	SchemaVersion int                     `json:"schema_version"`
+	ModifiedBlock string                  `json:"modified_block,omitempty"`
+	Blocks        []DocumentBlock         `json:"blocks"`
+	Diagnostics   []format.JSONDiagnostic `json:"diagnostics,omitempty"` // Document-wide, such as size limits
+}
+
+// handleCreateDocument opens a document.
+func (s *Server) handleCreateDocument(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	if req.Schedule != "" {
+		if _, err := ParseSchedule(req.Schedule); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	s.mu.Lock()
+	full := len(s.documents) >= s.opts.MaxDocuments
+	s.mu.Unlock()
+	if full {
+		writeError(w, http.StatusServiceUnavailable, s.documentLimitError())
+		return
+	}
+
+	doc, eval, ok := s.evaluate(w, req.Source)
+	if !ok {
+		return
+	}
+	od := &openDocument{id: newDocumentID(), tags: req.Tags, schedule: req.Schedule, doc: doc, eval: eval}
+	hash, err := s.save(r.Context(), od, "")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	od.hash = hash
+	if !s.keep(od) {
+		_ = s.opts.Store.Delete(r.Context(), od.id)
+		writeError(w, http.StatusServiceUnavailable, s.documentLimitError())
+		return
+	}
+
+	writeDocument(w, http.StatusCreated, od.response("", nil))
+}
+
+// handleGetDocument returns every block of an open document.
+func (s *Server) handleGetDocument(w http.ResponseWriter, r *http.Request) {
+	od, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	od.mu.Lock()
+	defer od.mu.Unlock()
+	writeDocument(w, http.StatusOK, od.response("", nil))
+}
+
+// handleDeleteDocument closes an open document, removing it and its
+// snapshots from the store.
+func (s *Server) handleDeleteDocument(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	err := ErrNotFound
+	if validDocumentID(id) {
+		err = s.opts.Store.Delete(r.Context(), id)
+	}
+	if err == nil {
+		err = s.deleteSnapshots(r.Context(), id)
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, notOpenError(id))
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.mu.Lock()
+	delete(s.documents, id)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePatchBlock replaces a block's source and evaluates the blocks the
+// change affects, returning them. With If-Match, the document must still
+// have that hash.
+func (s *Server) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
+	var req sourceRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	od, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	od.mu.Lock()
+	defer od.mu.Unlock()
+
+	if match := r.Header.Get("If-Match"); match != "" && strings.Trim(match, `"`) != od.hash {
+		writeError(w, http.StatusPreconditionFailed, fmt.Errorf("document %s has changed; get it again", od.id))
+		return
+	}
+	blockID := r.PathValue("block")
+	if _, ok := od.doc.GetBlock(blockID); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("block not found: %s", blockID))
+		return
+	}
+	before := maps.Clone(od.eval.GetEnvironment().GetAllVariables())
+	result, err := od.doc.ApplyEdits([]document.Edit{
+		{Kind: document.EditReplace, BlockID: blockID, Source: strings.Split(req.Source, "\n")},
+	})
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	// Errors are kept on the blocks
+	_ = od.eval.EvaluateAffectedBlocks(od.doc, od.doc.GetBlocksInDependencyOrder(result.AffectedBlockIDs))
+
+	hash, err := s.save(r.Context(), od, od.hash)
+	if err != nil {
+		// The edit isn't stored, so reload the document when it's next used
+		od.hash = ""
+		if errors.Is(err, ErrConflict) {
+			writeError(w, http.StatusConflict, fmt.Errorf("document %s was changed by another client; get it again", od.id))
+		} else {
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	od.hash = hash
+	s.notify(od.id, od.tags, hash, before, od.eval.GetEnvironment().GetAllVariables())
+	writeDocument(w, http.StatusOK, od.response(result.ModifiedBlockID, result.AffectedBlockIDs))
+}
+
+// save writes od's source to the store if the stored version still has
+// ifHash ("" for a new document), returning the new hash.
+func (s *Server) save(ctx context.Context, od *openDocument, ifHash string) (string, error) {
+	stored := storedDocument{Source: od.doc.Serialize(), Tags: od.tags, Schedule: od.schedule}
+	for _, node := range od.doc.GetBlocks() {
+		stored.Blocks = append(stored.Blocks, node.ID)
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return "", err
+	}
+	return s.opts.Store.Put(ctx, od.id, data, ifHash)
+}
+
+// keep adds od to the open documents, replacing any out-of-date copy, and
+// reports false if MaxDocuments others are open.
+func (s *Server) keep(od *openDocument) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.documents[od.id]; !ok && len(s.documents) >= s.opts.MaxDocuments {
+		return false
+	}
+	s.documents[od.id] = od
+	return true
+}
+
+// documentLimitError is the error of creating a document when
+// MaxDocuments are open.
+func (s *Server) documentLimitError() error {
+	return fmt.Errorf("%d documents are open (the limit); delete one first", s.opts.MaxDocuments)
+}
+
+// lookup returns the open document named by the request's path, writing
+// an error response if there is none. A document this server hasn't
+// evaluated, or that another server has changed, is loaded from the store.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*openDocument, bool) {
+	id := r.PathValue("id")
+	if !validDocumentID(id) {
+		writeError(w, http.StatusNotFound, notOpenError(id))
+		return nil, false
+	}
+	data, hash, err := s.opts.Store.Get(r.Context(), id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, notOpenError(id))
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	s.mu.Lock()
+	od := s.documents[id]
+	s.mu.Unlock()
+	if od != nil {
+		od.mu.Lock()
+		current := od.hash == hash
+		od.mu.Unlock()
+		if current {
+			return od, true
+		}
+	}
+
+	var stored storedDocument
+	if err := json.Unmarshal(data, &stored); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("document store: document %s: %w", id, err))
+		return nil, false
+	}
+	doc, eval, ok := s.evaluate(w, stored.Source)
+	if !ok {
+		return nil, false
+	}
+	// Every server gives a document's blocks the same IDs; one written to
+	// the store some other way keeps the IDs it was just given
+	if len(stored.Blocks) > 0 {
+		if err := doc.SetBlockIDs(stored.Blocks); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("document store: document %s: %w", id, err))
+			return nil, false
+		}
+	}
+	od = &openDocument{id: id, hash: hash, tags: stored.Tags, schedule: stored.Schedule, doc: doc, eval: eval}
+	if !s.keep(od) {
+		writeError(w, http.StatusServiceUnavailable, s.documentLimitError())
+		return nil, false
+	}
+	return od, true
+}
+
+// validDocumentID reports whether id can name a document. Unlike other
+// IDs in the store, such as snapshots', it has no dots.
+func validDocumentID(id string) bool {
+	return validID(id) && !strings.Contains(id, ".")
+}
+
+// notOpenError is the error of naming a document that isn't open.
+func notOpenError(id string) error {
+	return fmt.Errorf("no open document with id %s", id)
+}
+
+// writeDocument writes a document response with status, its hash as the ETag.
+func writeDocument(w http.ResponseWriter, status int, resp DocumentResponse) {
+	w.Header().Set("ETag", `"`+resp.Hash+`"`)
+	writeJSON(w, status, resp)
+}
+
+// response describes the document with the blocks in ids, or every block
+// if ids is nil. Diagnostics are the size limits the document exceeds;
+// the rest are on its blocks.
+func (od *openDocument) response(modified string, ids []string) DocumentResponse {
+	jd := (&format.JSONFormatter{}).Document(od.doc, formatOptions(od.eval.CheckLimits(od.doc)))
+	resp := DocumentResponse{
+		ID:            od.id,
+		Hash:          od.hash,
+		Tags:          od.tags,
+		Schedule:      od.schedule,
+		SchemaVersion: jd.SchemaVersion,
+		ModifiedBlock: modified,
+		Blocks:        []DocumentBlock{},
+		Diagnostics:   jd.Diagnostics,
+	}
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for i, node := range od.doc.GetBlocks() {
+		if ids == nil || wanted[node.ID] {
+			resp.Blocks = append(resp.Blocks, DocumentBlock{ID: node.ID, Index: i, JSONBlock: jd.Blocks[i]})
+		}
+	}
+	return resp
+}
+
+// newDocumentID returns a random document ID. IDs are unguessable because
+// every client of the server shares the documents.
+func newDocumentID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,307 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Store keeps each document as an object in an S3 bucket, or in any
+// service speaking the S3 API (MinIO, R2), so servers on several hosts can
+// share documents. The hash of a document is its object's ETag, and writes
+// use S3's conditional requests (If-Match and If-None-Match), which the
+// bucket checks atomically.
+//
+// Requests are signed with AWS Signature Version 4 using the credentials
+// in S3Config; the AWS SDK's credential chain (profiles, instance roles)
+// isn't consulted.
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+}
+
+// S3Config says where an S3Store keeps documents and how it signs in.
+type S3Config struct {
+	Bucket string
+	Prefix string // Prepended to document IDs to form object keys, such as "calcmark/"
+	Region string
+
+	// Endpoint is the service's URL, such as "http://localhost:9000" for
+	// MinIO. Objects are then addressed by path (endpoint/bucket/key). When
+	// empty, the bucket's AWS virtual-hosted endpoint is used.
+	Endpoint string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // For temporary credentials; may be empty
+}
+
+// S3ConfigFromEnv returns the configuration for bucket and prefix with
+// the region, endpoint, and credentials from the standard AWS environment
+// variables: AWS_REGION (or AWS_DEFAULT_REGION), AWS_ENDPOINT_URL_S3 (or
+// AWS_ENDPOINT_URL), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN.
+func S3ConfigFromEnv(bucket, prefix string) S3Config {
+	env := func(names ...string) string {
+		for _, name := range names {
+			if v := os.Getenv(name); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return S3Config{
+		Bucket:          bucket,
+		Prefix:          prefix,
+		Region:          env("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint:        env("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		AccessKeyID:     env("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: env("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    env("AWS_SESSION_TOKEN"),
+	}
+}
+
+// NewS3Store returns an S3Store for cfg.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	switch {
+	case cfg.Bucket == "":
+		return nil, fmt.Errorf("document store: S3 bucket is required")
+	case cfg.Region == "":
+		return nil, fmt.Errorf("document store: S3 region is required (set AWS_REGION)")
+	case cfg.AccessKeyID == "" || cfg.SecretAccessKey == "":
+		return nil, fmt.Errorf("document store: S3 credentials are required (set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	return &S3Store{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+// bucketURL returns the URL of the bucket, ending in "/", to which object
+// keys are appended.
+func (s *S3Store) bucketURL() string {
+	if s.cfg.Endpoint != "" {
+		return strings.TrimSuffix(s.cfg.Endpoint, "/") + "/" + s.cfg.Bucket + "/"
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", s.cfg.Bucket, s.cfg.Region)
+}
+
+// do sends a signed request for a document's object.
+func (s *S3Store) do(ctx context.Context, method, id string, body []byte, header http.Header) (*http.Response, error) {
+	if !validID(id) {
+		return nil, fmt.Errorf("invalid document id %q", id)
+	}
+	return s.send(ctx, method, s.bucketURL()+s.cfg.Prefix+id+".json", body, header)
+}
+
+// send sends a signed request.
+func (s *S3Store) send(ctx context.Context, method, u string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	signV4(req, body, s.cfg, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("document store: %w", err)
+	}
+	return resp, nil
+}
+
+// Get returns a document.
+func (s *S3Store) Get(ctx context.Context, id string) ([]byte, string, error) {
+	resp, err := s.do(ctx, http.MethodGet, id, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", s3Error(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("document store: %w", err)
+	}
+	return data, strings.Trim(resp.Header.Get("ETag"), `"`), nil
+}
+
+// Put writes a document if it hasn't changed since ifHash.
+func (s *S3Store) Put(ctx context.Context, id string, data []byte, ifHash string) (string, error) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	if ifHash == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", `"`+ifHash+`"`)
+	}
+	resp, err := s.do(ctx, http.MethodPut, id, data, header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return strings.Trim(resp.Header.Get("ETag"), `"`), nil
+	case http.StatusPreconditionFailed, http.StatusConflict, http.StatusNotFound:
+		// 409 is a concurrent conditional write; 404 an If-Match on a deleted object
+		return "", ErrConflict
+	}
+	return "", s3Error(resp)
+}
+
+// Delete removes a document.
+func (s *S3Store) Delete(ctx context.Context, id string) error {
+	// S3 deletes missing objects without complaint, so check first
+	if _, _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, id, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// listBucketResult is the part of a ListObjectsV2 response List reads.
+type listBucketResult struct {
+	Contents []struct {
+		Key string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// List returns the IDs starting with prefix.
+func (s *S3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var ids []string
+	query := url.Values{"list-type": {"2"}, "prefix": {s.cfg.Prefix + prefix}}
+	for {
+		resp, err := s.send(ctx, http.MethodGet, s.bucketURL()+"?"+query.Encode(), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := s3Error(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("document store: listing: %w", err)
+		}
+		for _, obj := range page.Contents {
+			// The key of document "a" has prefix "a." too
+			id, ok := strings.CutSuffix(strings.TrimPrefix(obj.Key, s.cfg.Prefix), ".json")
+			if ok && strings.HasPrefix(id, prefix) && validID(id) {
+				ids = append(ids, id)
+			}
+		}
+		if !page.IsTruncated {
+			break
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// s3Error describes a failed S3 response.
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("document store: S3 %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// signV4 signs req for S3 with AWS Signature Version 4, signing the host,
+// the headers set on req, and body's hash.
+func signV4(req *http.Request, body []byte, cfg S3Config, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cfg.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := slices.Sorted(maps.Keys(headers))
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := []byte("AWS4" + cfg.SecretAccessKey)
+	for _, part := range []string{date, cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns a query string with its parameters sorted and
+// encoded as Signature Version 4 requires.
+func canonicalQuery(query url.Values) string {
+	var params []string
+	for name, values := range query {
+		for _, v := range values {
+			params = append(params, awsEscape(name)+"="+awsEscape(v))
+		}
+	}
+	slices.Sort(params)
+	return strings.Join(params, "&")
+}
+
+// awsEscape percent-encodes s, leaving only unreserved characters.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
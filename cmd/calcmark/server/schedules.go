@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/format"
+)
+
+// Snapshot is the result of a scheduled evaluation of a document. It is
+// kept in the store beside the document, under the document's ID, a dot,
+// and its SnapshotTimeFormat time.
+type Snapshot struct {
+	Document string              `json:"document"`
+	Time     time.Time           `json:"time"`
+	Hash     string              `json:"hash"` // Of the version evaluated
+	Results  format.JSONDocument `json:"results"`
+}
+
+// SnapshotTimeFormat is how snapshot times are written in store IDs and
+// in the snapshot endpoints' paths.
+const SnapshotTimeFormat = "20060102T1504Z"
+
+// SnapshotList is the result of GET /documents/{id}/snapshots.
+type SnapshotList struct {
+	Snapshots []string `json:"snapshots"` // Times, oldest first, in SnapshotTimeFormat
+}
+
+// snapshotID returns the store ID of a document's snapshot at t.
+func snapshotID(id string, t time.Time) string {
+	return id + "." + t.UTC().Format(SnapshotTimeFormat)
+}
+
+// RunSchedules re-evaluates documents on their schedules until ctx is
+// done, calling RunDue at the start of every minute.
+func (s *Server) RunSchedules(ctx context.Context) {
+	for {
+		next := time.Now().Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+		s.RunDue(ctx, next)
+	}
+}
+
+// RunDue re-evaluates every stored document whose schedule matches t's
+// minute. Each is read from the store again and evaluated afresh, so it
+// picks up edits made through other servers and values that depend on
+// the date, like today(). The results are stored as a Snapshot, and
+// webhooks are told about variables that changed since the document was
+// last evaluated on this server. When servers share a store, the first
+// to store a run's snapshot does the run and the others skip it.
+// Failures are logged.
+func (s *Server) RunDue(ctx context.Context, t time.Time) {
+	ids, err := s.opts.Store.List(ctx, "")
+	if err != nil {
+		s.opts.ErrorLog.Printf("schedules: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if !validDocumentID(id) {
+			continue // A snapshot
+		}
+		data, hash, err := s.opts.Store.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue // Deleted since listing
+		}
+		var stored storedDocument
+		if err == nil {
+			err = json.Unmarshal(data, &stored)
+		}
+		if err == nil && stored.Schedule != "" {
+			var sched *Schedule
+			if sched, err = ParseSchedule(stored.Schedule); err == nil && sched.Matches(t) {
+				err = s.runScheduled(ctx, id, hash, stored, t)
+			}
+		}
+		if err != nil {
+			s.opts.ErrorLog.Printf("schedules: document %s: %v", id, err)
+		}
+	}
+}
+
+// runScheduled evaluates a stored document, stores the snapshot, and
+// notifies webhooks, unless another server has already stored this run.
+func (s *Server) runScheduled(ctx context.Context, id, hash string, stored storedDocument, t time.Time) error {
+	doc, eval, err := s.evaluateSource(stored.Source)
+	if err != nil {
+		return err
+	}
+	if len(stored.Blocks) > 0 {
+		if err := doc.SetBlockIDs(stored.Blocks); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(Snapshot{
+		Document: id,
+		Time:     t.UTC().Truncate(time.Minute),
+		Hash:     hash,
+		Results:  (&format.JSONFormatter{}).Document(doc, formatOptions(eval.Diagnostics())),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.opts.Store.Put(ctx, snapshotID(id, t), data, ""); errors.Is(err, ErrConflict) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	prev := s.documents[id]
+	s.mu.Unlock()
+	od := &openDocument{id: id, hash: hash, tags: stored.Tags, schedule: stored.Schedule, doc: doc, eval: eval}
+	// Kept if there's room, so the next run has values to compare with
+	s.keep(od)
+	if prev != nil {
+		prev.mu.Lock()
+		before := maps.Clone(prev.eval.GetEnvironment().GetAllVariables())
+		prev.mu.Unlock()
+		s.notify(id, stored.Tags, hash, before, eval.GetEnvironment().GetAllVariables())
+	}
+	return nil
+}
+
+// handleListSnapshots lists a document's snapshots.
+func (s *Server) handleListSnapshots(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.stored(w, r, id) {
+		return
+	}
+	ids, err := s.opts.Store.List(r.Context(), id+".")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	list := SnapshotList{Snapshots: []string{}}
+	for _, snap := range ids {
+		list.Snapshots = append(list.Snapshots, strings.TrimPrefix(snap, id+"."))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleGetSnapshot returns one of a document's snapshots.
+func (s *Server) handleGetSnapshot(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.stored(w, r, id) {
+		return
+	}
+	at, err := time.Parse(SnapshotTimeFormat, r.PathValue("time"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no snapshot of %s at %s", id, r.PathValue("time")))
+		return
+	}
+	data, _, err := s.opts.Store.Get(r.Context(), snapshotID(id, at))
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, fmt.Errorf("no snapshot of %s at %s", id, r.PathValue("time")))
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// stored reports whether a document is in the store, writing an error
+// response if not.
+func (s *Server) stored(w http.ResponseWriter, r *http.Request, id string) bool {
+	err := ErrNotFound
+	if validDocumentID(id) {
+		_, _, err = s.opts.Store.Get(r.Context(), id)
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, notOpenError(id))
+		return false
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+// deleteSnapshots removes a document's snapshots.
+func (s *Server) deleteSnapshots(ctx context.Context, id string) error {
+	ids, err := s.opts.Store.List(ctx, id+".")
+	if err != nil {
+		return err
+	}
+	for _, snap := range ids {
+		if err := s.opts.Store.Delete(ctx, snap); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
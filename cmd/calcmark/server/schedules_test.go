@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	hook := &hookRecorder{}
+	hookSrv := httptest.NewServer(hook)
+	defer hookSrv.Close()
+
+	// Two servers share a store; only a has webhooks
+	store := NewMemoryStore()
+	a := New(Options{Store: store, Webhooks: []Webhook{{URL: hookSrv.URL, Variables: []string{"price"}}}})
+	b := New(Options{Store: store})
+
+	var created DocumentResponse
+	req := createRequest{Source: "price = $10\n", Schedule: "0 9 * * *"}
+	decodeBody(t, do(t, a, "POST", "/documents", req), http.StatusCreated, &created)
+	if created.Schedule != "0 9 * * *" {
+		t.Fatalf("schedule = %q", created.Schedule)
+	}
+
+	// An edit through b is picked up by a's scheduled run
+	path := "/documents/" + created.ID + "/blocks/" + created.Blocks[0].ID
+	decodeBody(t, do(t, b, "PATCH", path, sourceRequest{Source: "price = $20"}), http.StatusOK, &DocumentResponse{})
+
+	ctx := context.Background()
+	nine := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	a.RunDue(ctx, nine.Add(-time.Minute)) // Not scheduled
+	a.RunDue(ctx, nine)
+	b.RunDue(ctx, nine) // Already run by a
+	a.deliveries.Wait()
+
+	if len(hook.events) != 1 {
+		t.Fatalf("events = %+v", hook.events)
+	}
+	if c := hook.events[0].Changes; len(c) != 1 || c[0].Before != "$10.00" || c[0].After != "$20.00" {
+		t.Errorf("changes = %+v", c)
+	}
+
+	var list SnapshotList
+	decodeBody(t, do(t, b, "GET", "/documents/"+created.ID+"/snapshots", nil), http.StatusOK, &list)
+	if len(list.Snapshots) != 1 || list.Snapshots[0] != "20261016T0900Z" {
+		t.Fatalf("snapshots = %v", list.Snapshots)
+	}
+	var snap Snapshot
+	decodeBody(t, do(t, b, "GET", "/documents/"+created.ID+"/snapshots/20261016T0900Z", nil), http.StatusOK, &snap)
+	if !snap.Time.Equal(nine) || snap.Results.Blocks[0].Results[0].RawValue != "20" {
+		t.Errorf("snapshot = %+v", snap)
+	}
+
+	// Snapshots go with their document
+	if rec := do(t, a, "DELETE", "/documents/"+created.ID, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: status = %d", rec.Code)
+	}
+	if ids, _ := store.List(ctx, ""); len(ids) != 0 {
+		t.Errorf("left in the store: %v", ids)
+	}
+
+	var resp errorResponse
+	req.Schedule = "every day"
+	decodeBody(t, do(t, a, "POST", "/documents", req), http.StatusBadRequest, &resp)
+}
@@ -0,0 +1,302 @@
+// Package server implements the HTTP API behind `calcmark serve`. It
+// evaluates, validates, and converts documents posted as JSON, and keeps
+// documents open so clients can change them a block at a time, evaluating
+// only the blocks an edit affects.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// Defaults for the zero values of Options.
+const (
+	DefaultMaxBodyBytes = 1 << 20 // As the CLI limits files it reads
+	DefaultTimeout      = 5 * time.Second
+	DefaultMaxDocuments = 100
+)
+
+// Options configures a Server.
+type Options struct {
+	MaxBodyBytes int64          // Largest request body accepted
+	Timeout      time.Duration  // Time a request may take, and each block's evaluation
+	MaxDocuments int            // Documents open at once
+	Limits       implDoc.Limits // Size limits that add warnings, as in the editor
+	Store        DocumentStore  // Keeps open documents; a MemoryStore if nil
+	Webhooks     []Webhook      // Told when edits change the variables they watch
+	ErrorLog     *log.Logger    // For failures no client sees, such as webhooks; log.Default() if nil
+}
+
+// Server serves the HTTP API. Requests are independent apart from open
+// documents, which are shared by every client knowing their ID, and by
+// every server using the same DocumentStore.
+type Server struct {
+	opts    Options
+	handler http.Handler
+
+	mu        sync.Mutex
+	documents map[string]*openDocument // Evaluated copies of stored documents, by ID
+
+	deliveries sync.WaitGroup // Webhook events being posted
+}
+
+// New creates a server with opts, using the defaults for zero values.
+func New(opts Options) *Server {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxDocuments <= 0 {
+		opts.MaxDocuments = DefaultMaxDocuments
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.ErrorLog == nil {
+		opts.ErrorLog = log.Default()
+	}
+	s := &Server{opts: opts, documents: make(map[string]*openDocument)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /evaluate", s.handleEvaluate)
+	mux.HandleFunc("POST /validate", s.handleValidate)
+	mux.HandleFunc("POST /convert", s.handleConvert)
+	mux.HandleFunc("POST /documents", s.handleCreateDocument)
+	mux.HandleFunc("GET /documents/{id}", s.handleGetDocument)
+	mux.HandleFunc("DELETE /documents/{id}", s.handleDeleteDocument)
+	mux.HandleFunc("PATCH /documents/{id}/blocks/{block}", s.handlePatchBlock)
+	mux.HandleFunc("GET /documents/{id}/snapshots", s.handleListSnapshots)
+	mux.HandleFunc("GET /documents/{id}/snapshots/{time}", s.handleGetSnapshot)
+	s.handler = http.TimeoutHandler(mux, opts.Timeout, `{"error": "request timed out"}`)
+	return s
+}
+
+// ServeHTTP handles a request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Handlers replace this; it stays for timeout responses
+	w.Header().Set("Content-Type", "application/json")
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+	s.handler.ServeHTTP(w, r)
+}
+
+// sourceRequest is the body of most requests.
+type sourceRequest struct {
+	Source string `json:"source"`
+}
+
+// createRequest is the body of POST /documents.
+type createRequest struct {
+	Source   string   `json:"source"`
+	Tags     []string `json:"tags"`     // For webhooks to filter on
+	Schedule string   `json:"schedule"` // Cron expression for re-evaluating it; see Schedule
+}
+
+// convertRequest is the body of POST /convert.
+type convertRequest struct {
+	Source string `json:"source"`
+	Format string `json:"format"`
+}
+
+// ValidateResponse is the result of POST /validate: the problems cm check
+// reports, with lines in the source.
+type ValidateResponse struct {
+	Valid       bool         `json:"valid"` // No errors; warnings and hints are allowed
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Diagnostic is a problem found by POST /validate. Line and column are
+// 1-indexed in the whole source, frontmatter included; column 0 means the
+// whole line.
+type Diagnostic struct {
+	Line        int      `json:"line"`
+	Column      int      `json:"column,omitempty"`
+	Severity    string   `json:"severity"` // "error", "warning", or "hint"
+	Code        string   `json:"code,omitempty"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// errorResponse is the body of every failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// handleEvaluate returns a document's results as cm convert --to=json
+// writes them.
+func (s *Server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	var req sourceRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	doc, eval, ok := s.evaluate(w, req.Source)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, (&format.JSONFormatter{}).Document(doc, formatOptions(eval.Diagnostics())))
+}
+
+// handleValidate returns a document's problems.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var req sourceRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	diags, _ := implDoc.CheckSource(req.Source, s.newEvaluator())
+	resp := ValidateResponse{Valid: true, Diagnostics: []Diagnostic{}}
+	for _, d := range diags {
+		severity := strings.ToLower(d.Severity.String())
+		if severity == "error" {
+			resp.Valid = false
+		}
+		resp.Diagnostics = append(resp.Diagnostics, Diagnostic{
+			Line:        d.Range.Start.Line,
+			Column:      d.Range.Start.Column,
+			Severity:    severity,
+			Code:        d.Code,
+			Message:     d.Message,
+			Suggestions: d.Suggestions,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// contentTypes are the formats POST /convert writes. Those writing files
+// (xlsx, pdf) or reading them (html-interactive) are left to the CLI.
+var contentTypes = map[string]string{
+	"html":        "text/html; charset=utf-8",
+	"report":      "text/html; charset=utf-8",
+	"md":          "text/markdown; charset=utf-8",
+	"json":        "application/json",
+	"csv":         "text/csv; charset=utf-8",
+	"text":        "text/plain; charset=utf-8",
+	"report-text": "text/plain; charset=utf-8",
+	"cm":          "text/plain; charset=utf-8",
+}
+
+// convertFormats are the formats POST /convert accepts, in the order
+// errors list them.
+var convertFormats = []string{"html", "md", "json", "text", "cm", "report", "report-text", "csv"}
+
+// handleConvert writes a document in another format.
+func (s *Server) handleConvert(w http.ResponseWriter, r *http.Request) {
+	var req convertRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	contentType, ok := contentTypes[req.Format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown format %q (valid: %s)", req.Format, strings.Join(convertFormats, ", ")))
+		return
+	}
+	doc, eval, ok := s.evaluate(w, req.Source)
+	if !ok {
+		return
+	}
+	var out strings.Builder
+	if err := format.GetFormatter(req.Format, "").Format(&out, doc, formatOptions(eval.Diagnostics())); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("format error: %w", err))
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write([]byte(out.String()))
+}
+
+// newEvaluator returns an evaluator with the server's limits. It has no
+// resolver, so documents can't import files from the server's disk.
+func (s *Server) newEvaluator() *implDoc.Evaluator {
+	eval := implDoc.NewEvaluator()
+	eval.SetBlockTimeout(s.opts.Timeout)
+	eval.SetLimits(s.opts.Limits)
+	return eval
+}
+
+// evaluate parses and evaluates source, writing an error response if it
+// can't be parsed or evaluated. Errors in blocks are kept on the blocks
+// for the response; other errors, such as bad frontmatter or imports,
+// fail the request.
+func (s *Server) evaluate(w http.ResponseWriter, source string) (*document.Document, *implDoc.Evaluator, bool) {
+	doc, eval, err := s.evaluateSource(source)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return nil, nil, false
+	}
+	return doc, eval, true
+}
+
+// evaluateSource parses and evaluates source, returning an error if it
+// can't be parsed or evaluated; errors in blocks are kept on the blocks.
+func (s *Server) evaluateSource(source string) (*document.Document, *implDoc.Evaluator, error) {
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse error: %w", err)
+	}
+	eval := s.newEvaluator()
+	if err := eval.Evaluate(doc); err != nil && !blockFailed(doc) {
+		return nil, nil, fmt.Errorf("evaluation error: %w", err)
+	}
+	return doc, eval, nil
+}
+
+// blockFailed reports whether a calculation block of doc has an error.
+func blockFailed(doc *document.Document) bool {
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok && cb.Error() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOptions returns the options formatters use for responses, as
+// cm convert does.
+func formatOptions(diags []implDoc.BlockDiagnostic) format.Options {
+	return format.Options{
+		Verbose:       true,
+		IncludeErrors: true,
+		Diagnostics:   implDoc.FormatDiagnostics(diags),
+	}
+}
+
+// decode reads a JSON request body into v, writing an error response if
+// it is too large or malformed.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+		return false
+	case err != nil:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as the response with status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// writeError writes err as the response with status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+)
+
+// do sends a request with a JSON body to s, returning the response.
+func do(t *testing.T, s *Server, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader *strings.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = strings.NewReader("")
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = strings.NewReader(string(data))
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(method, path, reader))
+	return rec
+}
+
+// decodeBody decodes a JSON response, failing unless it has status.
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d; body %s", rec.Code, status, rec.Body)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	s := New(Options{})
+	var doc format.JSONDocument
+	decodeBody(t, do(t, s, "POST", "/evaluate", sourceRequest{Source: "price = $20\ntotal = price * 3\n"}), http.StatusOK, &doc)
+
+	if doc.SchemaVersion != format.JSONSchemaVersion || len(doc.Blocks) != 1 {
+		t.Fatalf("doc = %+v", doc)
+	}
+	results := doc.Blocks[0].Results
+	if len(results) != 2 || results[1].Variable != "total" || results[1].RawValue != "60" {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestEvaluateKeepsBlockErrors(t *testing.T) {
+	s := New(Options{})
+	var doc format.JSONDocument
+	decodeBody(t, do(t, s, "POST", "/evaluate", sourceRequest{Source: "a = 1/0\n"}), http.StatusOK, &doc)
+	if len(doc.Blocks) != 1 || !strings.Contains(doc.Blocks[0].Error, "division by zero") {
+		t.Errorf("blocks = %+v", doc.Blocks)
+	}
+}
+
+func TestEvaluateRejectsImports(t *testing.T) {
+	s := New(Options{})
+	var resp errorResponse
+	source := "---\nimports: [rates.cm]\n---\nx = 1\n"
+	decodeBody(t, do(t, s, "POST", "/evaluate", sourceRequest{Source: source}), http.StatusUnprocessableEntity, &resp)
+	if !strings.Contains(resp.Error, "imports are not available") {
+		t.Errorf("error = %q", resp.Error)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := New(Options{})
+	var resp ValidateResponse
+	decodeBody(t, do(t, s, "POST", "/validate", sourceRequest{Source: "x = 1\ny = x + missing\n"}), http.StatusOK, &resp)
+	if resp.Valid || len(resp.Diagnostics) == 0 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if d := resp.Diagnostics[0]; d.Line != 2 || d.Severity != "error" {
+		t.Errorf("diagnostic = %+v", d)
+	}
+
+	decodeBody(t, do(t, s, "POST", "/validate", sourceRequest{Source: "x = 1\n"}), http.StatusOK, &resp)
+	if !resp.Valid {
+		t.Errorf("valid document: %+v", resp)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	s := New(Options{})
+	rec := do(t, s, "POST", "/convert", convertRequest{Source: "x = 2 + 3\n", Format: "html"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "5") {
+		t.Errorf("body = %s", rec.Body)
+	}
+
+	var resp errorResponse
+	decodeBody(t, do(t, s, "POST", "/convert", convertRequest{Source: "x = 1\n", Format: "pdf"}), http.StatusBadRequest, &resp)
+	if !strings.Contains(resp.Error, "unknown format") {
+		t.Errorf("error = %q", resp.Error)
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	s := New(Options{MaxBodyBytes: 64})
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+	}{
+		{"invalid JSON", "POST", "/evaluate", "{", http.StatusBadRequest},
+		{"unknown field", "POST", "/evaluate", `{"src": "x = 1"}`, http.StatusBadRequest},
+		{"too large", "POST", "/evaluate", sourceRequest{Source: strings.Repeat("x = 1\n", 20)}, http.StatusRequestEntityTooLarge},
+		{"wrong method", "GET", "/evaluate", nil, http.StatusMethodNotAllowed},
+		{"unknown document", "GET", "/documents/nope", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(t, s, tt.method, tt.path, tt.body); rec.Code != tt.status {
+				t.Errorf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := New(Options{Timeout: time.Millisecond})
+	// Enough blocks that evaluating them outlasts the timeout
+	source := strings.Repeat("x = 2 ^ 10 * 3 + 1\n\n", 2000)
+	rec := do(t, s, "POST", "/evaluate", sourceRequest{Source: source})
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "timed out") {
+		t.Errorf("status = %d; body %.200s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	s := New(Options{})
+	var created DocumentResponse
+	decodeBody(t, do(t, s, "POST", "/documents", sourceRequest{Source: "a = 2\n\nSome text.\n\nb = a * 10\n"}), http.StatusCreated, &created)
+	if created.ID == "" || len(created.Blocks) != 3 {
+		t.Fatalf("created = %+v", created)
+	}
+	first, last := created.Blocks[0], created.Blocks[2]
+	if last.Index != 2 || last.Results[0].RawValue != "20" {
+		t.Fatalf("last block = %+v", last)
+	}
+
+	// Editing the first block evaluates it and its dependent, not the text
+	var patched DocumentResponse
+	path := "/documents/" + created.ID + "/blocks/" + first.ID
+	decodeBody(t, do(t, s, "PATCH", path, sourceRequest{Source: "a = 5"}), http.StatusOK, &patched)
+	if patched.ModifiedBlock != first.ID || len(patched.Blocks) != 2 {
+		t.Fatalf("patched = %+v", patched)
+	}
+	if got := patched.Blocks[1]; got.ID != last.ID || got.Results[0].RawValue != "50" {
+		t.Errorf("dependent = %+v", got)
+	}
+
+	var got DocumentResponse
+	decodeBody(t, do(t, s, "GET", "/documents/"+created.ID, nil), http.StatusOK, &got)
+	if len(got.Blocks) != 3 || got.Blocks[0].Source[0] != "a = 5" {
+		t.Errorf("document = %+v", got)
+	}
+
+	rec := do(t, s, "PATCH", "/documents/"+created.ID+"/blocks/nope", sourceRequest{Source: "x = 1"})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown block: status = %d", rec.Code)
+	}
+
+	if rec := do(t, s, "DELETE", "/documents/"+created.ID, nil); rec.Code != http.StatusNoContent {
+		t.Errorf("delete: status = %d", rec.Code)
+	}
+	if rec := do(t, s, "GET", "/documents/"+created.ID, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("after delete: status = %d", rec.Code)
+	}
+}
+
+func TestDocumentLimits(t *testing.T) {
+	s := New(Options{MaxDocuments: 1, Limits: implDoc.Limits{MaxResults: 1}})
+	var created DocumentResponse
+	decodeBody(t, do(t, s, "POST", "/documents", sourceRequest{Source: "a = 1\nb = 2\n"}), http.StatusCreated, &created)
+	if len(created.Diagnostics) != 1 || created.Diagnostics[0].Code != implDoc.DiagSizeLimit {
+		t.Errorf("diagnostics = %+v", created.Diagnostics)
+	}
+
+	var resp errorResponse
+	decodeBody(t, do(t, s, "POST", "/documents", sourceRequest{Source: "c = 3\n"}), http.StatusServiceUnavailable, &resp)
+	if !strings.Contains(resp.Error, "documents are open") {
+		t.Errorf("error = %q", resp.Error)
+	}
+}
+
+func TestDocumentsSharedStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := New(Options{Store: store}), New(Options{Store: store})
+
+	var created DocumentResponse
+	decodeBody(t, do(t, a, "POST", "/documents", sourceRequest{Source: "a = 2\n\nSome text.\n\nb = a * 10\n"}), http.StatusCreated, &created)
+	blockPath := "/documents/" + created.ID + "/blocks/" + created.Blocks[0].ID
+
+	// Another server loads the document from the store
+	var got DocumentResponse
+	rec := do(t, b, "GET", "/documents/"+created.ID, nil)
+	decodeBody(t, rec, http.StatusOK, &got)
+	if got.Hash != created.Hash || rec.Header().Get("ETag") != `"`+created.Hash+`"` {
+		t.Errorf("hash = %q, ETag %q; want %q", got.Hash, rec.Header().Get("ETag"), created.Hash)
+	}
+
+	if got.Blocks[0].ID != created.Blocks[0].ID {
+		t.Errorf("block IDs differ between servers: %s and %s", got.Blocks[0].ID, created.Blocks[0].ID)
+	}
+
+	// An edit on one server is seen by the other, but not an edit based on
+	// the version before it
+	var patched DocumentResponse
+	decodeBody(t, do(t, b, "PATCH", blockPath, sourceRequest{Source: "a = 4"}), http.StatusOK, &patched)
+	decodeBody(t, do(t, a, "PATCH", blockPath, sourceRequest{Source: "a = 5"}), http.StatusOK, &patched)
+	if patched.Hash == created.Hash {
+		t.Fatalf("hash unchanged by edit")
+	}
+	req := httptest.NewRequest("PATCH", blockPath, strings.NewReader(`{"source": "a = 7"}`))
+	req.Header.Set("If-Match", `"`+created.Hash+`"`)
+	rec = httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match: status = %d, body %s", rec.Code, rec.Body)
+	}
+	decodeBody(t, do(t, b, "GET", "/documents/"+created.ID, nil), http.StatusOK, &got)
+	if got.Hash != patched.Hash || got.Blocks[2].Results[0].RawValue != "50" {
+		t.Errorf("document on the other server = %+v", got)
+	}
+
+	// Deleting on one server closes the document on both
+	if rec := do(t, b, "DELETE", "/documents/"+created.ID, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: status = %d", rec.Code)
+	}
+	if rec := do(t, a, "GET", "/documents/"+created.ID, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("after delete: status = %d", rec.Code)
+	}
+}
+
+// conflictStore is a store whose writes to existing documents always conflict,
+// as if another server got there first.
+type conflictStore struct{ *MemoryStore }
+
+func (c conflictStore) Put(ctx context.Context, id string, data []byte, ifHash string) (string, error) {
+	if ifHash != "" {
+		return "", ErrConflict
+	}
+	return c.MemoryStore.Put(ctx, id, data, ifHash)
+}
+
+func TestDocumentsEditConflict(t *testing.T) {
+	s := New(Options{Store: conflictStore{NewMemoryStore()}})
+	var created DocumentResponse
+	decodeBody(t, do(t, s, "POST", "/documents", sourceRequest{Source: "a = 2\n"}), http.StatusCreated, &created)
+
+	var resp errorResponse
+	path := "/documents/" + created.ID + "/blocks/" + created.Blocks[0].ID
+	decodeBody(t, do(t, s, "PATCH", path, sourceRequest{Source: "a = 5"}), http.StatusConflict, &resp)
+	if !strings.Contains(resp.Error, "changed by another client") {
+		t.Errorf("error = %q", resp.Error)
+	}
+
+	// The edit that wasn't stored is dropped
+	var got DocumentResponse
+	decodeBody(t, do(t, s, "GET", "/documents/"+created.ID, nil), http.StatusOK, &got)
+	if got.Blocks[0].Source[0] != "a = 2" {
+		t.Errorf("document = %+v", got)
+	}
+}
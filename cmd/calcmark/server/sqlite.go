@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "modernc.org/sqlite" // Registers the pure Go "sqlite" driver
+)
+
+// SQLiteStore keeps documents in a table of a SQLite database file, for
+// one host that wants its documents in a single file rather than a
+// directory of them. Writes check the hash in the same statement that
+// changes the row, so servers sharing the file on one host can't overwrite
+// each other's changes; across hosts, use S3Store, since SQLite's locking
+// isn't safe over network file systems.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// sqliteBusyTimeout is how long, in milliseconds, a write waits for
+// another server sharing the database to finish its own.
+const sqliteBusyTimeout = 5000
+
+// NewSQLiteStore returns a SQLiteStore keeping documents in the database
+// at path, creating it and its documents table if needed.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)", path, sqliteBusyTimeout)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("document store: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS documents (
+		id   TEXT PRIMARY KEY,
+		data BLOB NOT NULL,
+		hash TEXT NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("document store: %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Get returns a document.
+func (s *SQLiteStore) Get(ctx context.Context, id string) ([]byte, string, error) {
+	if !validID(id) {
+		return nil, "", fmt.Errorf("invalid document id %q", id)
+	}
+	var data []byte
+	var hash string
+	err := s.db.QueryRowContext(ctx, `SELECT data, hash FROM documents WHERE id = ?`, id).Scan(&data, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return data, hash, nil
+}
+
+// Put writes a document if it hasn't changed since ifHash.
+func (s *SQLiteStore) Put(ctx context.Context, id string, data []byte, ifHash string) (string, error) {
+	if !validID(id) {
+		return "", fmt.Errorf("invalid document id %q", id)
+	}
+	hash := contentHash(data)
+	var res sql.Result
+	var err error
+	if ifHash == "" {
+		res, err = s.db.ExecContext(ctx, `INSERT INTO documents (id, data, hash) VALUES (?, ?, ?) ON CONFLICT (id) DO NOTHING`, id, data, hash)
+	} else {
+		res, err = s.db.ExecContext(ctx, `UPDATE documents SET data = ?, hash = ? WHERE id = ? AND hash = ?`, data, hash, id, ifHash)
+	}
+	if err != nil {
+		return "", err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return "", err
+	} else if n == 0 {
+		return "", ErrConflict
+	}
+	return hash, nil
+}
+
+// Delete removes a document.
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return fmt.Errorf("invalid document id %q", id)
+	}
+	res, err := s.db.ExecContext(ctx, `DELETE FROM documents WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List returns the IDs starting with prefix.
+func (s *SQLiteStore) List(ctx context.Context, prefix string) ([]string, error) {
+	// IDs are ASCII, so a prefix of bytes is one of characters, and
+	// SQLite's binary collation sorts them as Go does
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM documents WHERE substr(id, 1, ?) = ? ORDER BY id`, len(prefix), prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
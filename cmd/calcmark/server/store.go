@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Errors DocumentStore implementations return.
+var (
+	ErrNotFound = errors.New("document not found")
+	ErrConflict = errors.New("document changed since it was read")
+)
+
+// DocumentStore keeps the documents a Server has open, so they outlive the
+// process and can be shared by servers on different hosts. A document is
+// opaque data under an ID, with a hash of its content that changes whenever
+// it does. Writes are conditional on that hash, so two servers editing the
+// same document can't overwrite each other's changes: the second write
+// fails with ErrConflict and the server reloads the document.
+//
+// Implementations must be safe for concurrent use.
+type DocumentStore interface {
+	// Get returns a document and its hash, or ErrNotFound.
+	Get(ctx context.Context, id string) (data []byte, hash string, err error)
+
+	// Put writes a document if its hash is still ifHash, or if it doesn't
+	// exist when ifHash is "", and returns the new hash. Otherwise it
+	// returns ErrConflict.
+	Put(ctx context.Context, id string, data []byte, ifHash string) (hash string, err error)
+
+	// Delete removes a document, or returns ErrNotFound.
+	Delete(ctx context.Context, id string) error
+
+	// List returns the IDs starting with prefix, sorted.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// contentHash returns the hash MemoryStore and FileStore give data.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// validID reports whether id can name a stored document: letters, digits,
+// '-', '_', and single dots after the first character, so it's safe as a
+// file name or object key.
+func validID(id string) bool {
+	if id == "" || len(id) > 128 || id[0] == '.' || strings.Contains(id, "..") {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// MemoryStore keeps documents in memory, for a single server whose
+// documents needn't outlive it. It is the default.
+type MemoryStore struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{docs: make(map[string][]byte)}
+}
+
+// Get returns a document.
+func (m *MemoryStore) Get(_ context.Context, id string) ([]byte, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.docs[id]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return data, contentHash(data), nil
+}
+
+// Put writes a document if it hasn't changed since ifHash.
+func (m *MemoryStore) Put(_ context.Context, id string, data []byte, ifHash string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.docs[id]
+	if ok != (ifHash != "") || ok && contentHash(current) != ifHash {
+		return "", ErrConflict
+	}
+	m.docs[id] = data
+	return contentHash(data), nil
+}
+
+// Delete removes a document.
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.docs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.docs, id)
+	return nil
+}
+
+// List returns the IDs starting with prefix.
+func (m *MemoryStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id := range m.docs {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// FileStore keeps each document in a file in a directory. Writes replace
+// files atomically, and the hash check is atomic among the servers sharing
+// the FileStore; servers on other hosts sharing the directory (over NFS,
+// say) can race between the check and the write, so use S3Store for them.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex // Makes checking the hash and writing one step
+}
+
+// NewFileStore returns a FileStore keeping documents in dir, creating it
+// if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("document store: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path returns the file holding a document.
+func (f *FileStore) path(id string) (string, error) {
+	if !validID(id) {
+		return "", fmt.Errorf("invalid document id %q", id)
+	}
+	return filepath.Join(f.dir, id+".json"), nil
+}
+
+// Get returns a document.
+func (f *FileStore) Get(_ context.Context, id string) ([]byte, string, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return data, contentHash(data), nil
+}
+
+// Put writes a document if it hasn't changed since ifHash.
+func (f *FileStore) Put(ctx context.Context, id string, data []byte, ifHash string) (string, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, hash, err := f.Get(ctx, id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		if ifHash != "" {
+			return "", ErrConflict
+		}
+	case err != nil:
+		return "", err
+	case hash != ifHash:
+		return "", ErrConflict
+	}
+
+	// Write beside the file and rename, so readers never see half a document
+	tmp, err := os.CreateTemp(f.dir, id+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return contentHash(data), nil
+}
+
+// Delete removes a document.
+func (f *FileStore) Delete(_ context.Context, id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// List returns the IDs starting with prefix.
+func (f *FileStore) List(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if ok && e.Type().IsRegular() && strings.HasPrefix(id, prefix) && validID(id) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids) // Not quite file name order: "a.json" sorts after "a.b.json"
+	return ids, nil
+}
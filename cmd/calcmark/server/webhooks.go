@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Webhook is a URL the server posts a WebhookEvent to when an edit changes
+// the variables it watches. Filters left empty match everything.
+type Webhook struct {
+	URL       string
+	Documents []string // IDs of the documents it watches
+	Tags      []string // Watches documents with any of these tags
+	Variables []string // Names of the variables it watches
+
+	// Threshold is the least change in a number, currency, or quantity that
+	// is reported, so small fluctuations don't notify anyone. Other values,
+	// and values changing unit, are reported whenever they change.
+	Threshold decimal.Decimal
+}
+
+// WebhookEvent is the JSON body posted to a webhook.
+type WebhookEvent struct {
+	Document string           `json:"document"`
+	Tags     []string         `json:"tags,omitempty"`
+	Hash     string           `json:"hash"` // Of the version that made the changes
+	Time     time.Time        `json:"time"`
+	Changes  []VariableChange `json:"changes"` // Sorted by variable
+}
+
+// VariableChange is a watched variable's value before and after an edit,
+// written out in full, such as "$12060.00". Before is empty for a new
+// variable and After for a removed one.
+type VariableChange struct {
+	Variable string `json:"variable"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
+// webhookTimeout bounds delivering one event.
+const webhookTimeout = 10 * time.Second
+
+// matches reports whether the webhook watches a document with tags.
+func (h Webhook) matches(id string, tags []string) bool {
+	if len(h.Documents) > 0 && !slices.Contains(h.Documents, id) {
+		return false
+	}
+	if len(h.Tags) > 0 && !slices.ContainsFunc(h.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+		return false
+	}
+	return true
+}
+
+// changes returns the watched variables whose values differ between
+// before and after by at least the threshold.
+func (h Webhook) changes(before, after map[string]types.Type) []VariableChange {
+	names := h.Variables
+	if len(names) == 0 {
+		names = slices.Collect(maps.Keys(before))
+		for name := range after {
+			if _, ok := before[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	}
+	names = slices.Compact(slices.Sorted(slices.Values(names)))
+
+	var changes []VariableChange
+	for _, name := range names {
+		oldVal, newVal := before[name], after[name]
+		c := VariableChange{Variable: name}
+		if oldVal != nil {
+			c.Before = oldVal.String()
+		}
+		if newVal != nil {
+			c.After = newVal.String()
+		}
+		if c.Before == c.After || h.Threshold.IsPositive() && !h.exceedsThreshold(oldVal, newVal) {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// exceedsThreshold reports whether a value changed by at least the
+// threshold, or in a way the threshold can't measure.
+func (h Webhook) exceedsThreshold(before, after types.Type) bool {
+	oldAmount, oldUnit, ok1 := amount(before)
+	newAmount, newUnit, ok2 := amount(after)
+	if !ok1 || !ok2 || oldUnit != newUnit {
+		return true
+	}
+	return newAmount.Sub(oldAmount).Abs().GreaterThanOrEqual(h.Threshold)
+}
+
+// amount returns a number, currency, or quantity's value and its currency
+// or unit.
+func amount(v types.Type) (decimal.Decimal, string, bool) {
+	switch v := v.(type) {
+	case *types.Number:
+		return v.Value, "", true
+	case *types.Currency:
+		return v.Value, v.Code, true
+	case *types.Quantity:
+		return v.Value, v.Unit, true
+	}
+	return decimal.Zero, "", false
+}
+
+// notify posts an event to each webhook watching the document whose
+// variables changed from before to after. Events are delivered in the
+// background; failures are logged.
+func (s *Server) notify(id string, tags []string, hash string, before, after map[string]types.Type) {
+	now := time.Now().UTC()
+	for _, hook := range s.opts.Webhooks {
+		if !hook.matches(id, tags) {
+			continue
+		}
+		changes := hook.changes(before, after)
+		if len(changes) == 0 {
+			continue
+		}
+		event := WebhookEvent{Document: id, Tags: tags, Hash: hash, Time: now, Changes: changes}
+		s.deliveries.Add(1)
+		go func() {
+			defer s.deliveries.Done()
+			if err := s.post(hook.URL, event); err != nil {
+				s.opts.ErrorLog.Printf("webhook %s: %v", hook.URL, err)
+			}
+		}()
+	}
+}
+
+// post delivers an event to url.
+func (s *Server) post(url string, event WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// hookRecorder is a webhook endpoint keeping the events posted to it.
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []WebhookEvent
+}
+
+func (h *hookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var event WebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	h.events = append(h.events, event)
+	h.mu.Unlock()
+}
+
+func TestWebhooks(t *testing.T) {
+	budget, other := &hookRecorder{}, &hookRecorder{}
+	budgetSrv, otherSrv := httptest.NewServer(budget), httptest.NewServer(other)
+	defer budgetSrv.Close()
+	defer otherSrv.Close()
+
+	s := New(Options{Webhooks: []Webhook{
+		{URL: budgetSrv.URL, Tags: []string{"finance"}, Variables: []string{"burn"}, Threshold: decimal.NewFromInt(100)},
+		{URL: otherSrv.URL, Tags: []string{"ops"}},
+	}})
+	var created DocumentResponse
+	source := "monthly = $1000\n\nSome text.\n\nburn = monthly * 12\nnote = 1\n"
+	decodeBody(t, do(t, s, "POST", "/documents", createRequest{Source: source, Tags: []string{"finance"}}), http.StatusCreated, &created)
+	if len(created.Tags) != 1 || created.Tags[0] != "finance" {
+		t.Fatalf("tags = %v", created.Tags)
+	}
+	path := "/documents/" + created.ID + "/blocks/" + created.Blocks[0].ID
+
+	// burn changes by $60, under the threshold; then by $1,200
+	var patched DocumentResponse
+	decodeBody(t, do(t, s, "PATCH", path, sourceRequest{Source: "monthly = $1005"}), http.StatusOK, &patched)
+	decodeBody(t, do(t, s, "PATCH", path, sourceRequest{Source: "monthly = $1105"}), http.StatusOK, &patched)
+	s.deliveries.Wait()
+
+	if len(other.events) != 0 {
+		t.Errorf("webhook for other tags got %+v", other.events)
+	}
+	if len(budget.events) != 1 {
+		t.Fatalf("events = %+v", budget.events)
+	}
+	event := budget.events[0]
+	if event.Document != created.ID || event.Hash != patched.Hash || len(event.Tags) != 1 {
+		t.Errorf("event = %+v", event)
+	}
+	want := VariableChange{Variable: "burn", Before: "$12060.00", After: "$13260.00"}
+	if len(event.Changes) != 1 || event.Changes[0] != want {
+		t.Errorf("changes = %+v, want %+v", event.Changes, want)
+	}
+}
@@ -0,0 +1,244 @@
+// Package trace records an editing session, each change to a document and
+// the results after it, and reads the record back for `calcmark replay`.
+//
+// A trace is JSON lines, one Step per line. The first step inserts the
+// document as it was opened; each later one holds the lines an edit
+// replaced and every result once it was evaluated, so any step's document
+// and results can be rebuilt and compared with the step before:
+//
+//	{"step":0,"time":"...","edit":{"at":0,"inserted":["rent = $1200"]},"results":[...]}
+//	{"step":1,"time":"...","edit":{"at":0,"removed":["rent = $1200"],"inserted":["rent = $1250"]},"results":[...]}
+//
+// A step without an edit is a re-evaluation that changed results on its
+// own, such as a timed-out block finishing.
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Step is one recorded change.
+type Step struct {
+	Step    int       `json:"step"`
+	Time    time.Time `json:"time"`
+	Edit    *Edit     `json:"edit,omitempty"` // nil if only results changed
+	Results []Result  `json:"results"`
+}
+
+// Edit replaces the Removed lines at line At, counting from 0, with the
+// Inserted ones.
+type Edit struct {
+	At       int      `json:"at"`
+	Removed  []string `json:"removed,omitempty"`
+	Inserted []string `json:"inserted,omitempty"`
+}
+
+// Result is a calculation line's result after a step.
+type Result struct {
+	Line   int    `json:"line"` // 1-based
+	Source string `json:"source"`
+	Var    string `json:"var,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Name is what identifies the result across steps: its variable, or for
+// an unassigned expression its source.
+func (r Result) Name() string {
+	if r.Var != "" {
+		return r.Var
+	}
+	return strings.TrimSpace(r.Source)
+}
+
+// Display returns the result's value, or its error.
+func (r Result) Display() string {
+	if r.Error != "" {
+		return "error: " + r.Error
+	}
+	return r.Value
+}
+
+// Writer writes the steps of a session as they happen.
+type Writer struct {
+	enc  *json.Encoder
+	step int
+	now  func() time.Time
+}
+
+// NewWriter returns a Writer that writes steps to w. The first step
+// written should insert the whole document.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// Write records the next step: edit, or nil if the document didn't
+// change, and the results after it.
+func (w *Writer) Write(edit *Edit, results []Result) error {
+	step := Step{Step: w.step, Time: w.now(), Edit: edit, Results: results}
+	if step.Results == nil {
+		step.Results = []Result{}
+	}
+	if err := w.enc.Encode(step); err != nil {
+		return fmt.Errorf("write trace step %d: %w", w.step, err)
+	}
+	w.step++
+	return nil
+}
+
+// Trace is a recorded session.
+type Trace struct {
+	Steps []Step
+	lines [][]string // The document after each step
+}
+
+// Read reads a trace written by a Writer. A step cut short at the end,
+// as when the editor was killed mid-write, is dropped rather than failing
+// the whole trace.
+func Read(r io.Reader) (*Trace, error) {
+	t := &Trace{}
+	dec := json.NewDecoder(r)
+	var doc []string
+	for {
+		var step Step
+		err := dec.Decode(&step)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("trace step %d: %w", len(t.Steps), err)
+		}
+		if e := step.Edit; e != nil {
+			if e.At < 0 || e.At+len(e.Removed) > len(doc) || !slices.Equal(doc[e.At:e.At+len(e.Removed)], e.Removed) {
+				return nil, fmt.Errorf("trace step %d: edit doesn't apply to the document before it", len(t.Steps))
+			}
+			doc = slices.Concat(doc[:e.At], e.Inserted, doc[e.At+len(e.Removed):])
+		}
+		t.Steps = append(t.Steps, step)
+		t.lines = append(t.lines, doc)
+	}
+	if len(t.Steps) == 0 {
+		return nil, errors.New("trace is empty")
+	}
+	return t, nil
+}
+
+// Lines returns the document after step i.
+func (t *Trace) Lines(i int) []string {
+	return t.lines[i]
+}
+
+// Change is a result that differs from the step before. Was is empty for
+// a new result, and Now for one that went away.
+type Change struct {
+	Line int // 1-based, in the step's document; in the one before if gone
+	Name string
+	Was  string
+	Now  string
+}
+
+// Changes returns the results of step i that differ from step i-1's, in
+// line order. The first step has none.
+func (t *Trace) Changes(i int) []Change {
+	if i == 0 {
+		return nil
+	}
+	before := make(map[string]Result)
+	for _, r := range t.Steps[i-1].Results {
+		before[r.Name()] = r
+	}
+
+	var changes []Change
+	for _, r := range t.Steps[i].Results {
+		was, ok := before[r.Name()]
+		delete(before, r.Name())
+		if ok && was.Display() == r.Display() {
+			continue
+		}
+		changes = append(changes, Change{Line: r.Line, Name: r.Name(), Was: was.Display(), Now: r.Display()})
+	}
+	for _, r := range before {
+		changes = append(changes, Change{Line: r.Line, Name: r.Name(), Was: r.Display()})
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int { return a.Line - b.Line })
+	return changes
+}
+
+// String describes the change, as "total: $1,650.00 → $1,700.00".
+func (c Change) String() string {
+	switch {
+	case c.Was == "":
+		return fmt.Sprintf("%s: %s (new)", c.Name, c.Now)
+	case c.Now == "":
+		return fmt.Sprintf("%s: %s (gone)", c.Name, c.Was)
+	}
+	return fmt.Sprintf("%s: %s → %s", c.Name, c.Was, c.Now)
+}
+
+// Describe summarizes step i's edit, as "line 4 changed" or "lines 2-3
+// inserted".
+func (t *Trace) Describe(i int) string {
+	e := t.Steps[i].Edit
+	if i == 0 {
+		if n := len(t.lines[0]); n != 1 {
+			return fmt.Sprintf("opened, %d lines", n)
+		}
+		return "opened, 1 line"
+	}
+	if e == nil {
+		return "re-evaluated"
+	}
+	span := func(n int) string {
+		if n == 1 {
+			return fmt.Sprintf("line %d", e.At+1)
+		}
+		return fmt.Sprintf("lines %d-%d", e.At+1, e.At+n)
+	}
+	switch removed, inserted := len(e.Removed), len(e.Inserted); {
+	case removed == 0:
+		return span(inserted) + " inserted"
+	case inserted == 0:
+		return span(removed) + " deleted"
+	case removed == inserted:
+		return span(removed) + " changed"
+	default:
+		return fmt.Sprintf("%s replaced by %d", span(removed), inserted)
+	}
+}
+
+// Print writes each step's edit and the results it changed to w. With
+// name set, only the steps that changed that result are printed, which
+// answers when a number changed and what edit changed it.
+func (t *Trace) Print(w io.Writer, name string) error {
+	start := t.Steps[0].Time
+	for i, step := range t.Steps {
+		changes := t.Changes(i)
+		if name != "" {
+			changes = slices.DeleteFunc(changes, func(c Change) bool { return c.Name != name })
+			if len(changes) == 0 {
+				continue
+			}
+		}
+		if _, err := fmt.Fprintf(w, "step %d  +%s  %s\n", step.Step, step.Time.Sub(start).Round(time.Second), t.Describe(i)); err != nil {
+			return err
+		}
+		if e := step.Edit; e != nil && i > 0 {
+			for _, line := range e.Removed {
+				fmt.Fprintf(w, "  - %s\n", line)
+			}
+			for _, line := range e.Inserted {
+				fmt.Fprintf(w, "  + %s\n", line)
+			}
+		}
+		for _, c := range changes {
+			fmt.Fprintf(w, "    %s\n", c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package trace
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// session writes a three-step trace: opening a budget, changing the rent,
+// and appending a line.
+func session(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	clock := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	w.now = func() time.Time {
+		clock = clock.Add(5 * time.Second)
+		return clock
+	}
+
+	steps := []struct {
+		edit    *Edit
+		results []Result
+	}{
+		{&Edit{Inserted: []string{"rent = $1200", "food = $400", "total = rent + food"}}, []Result{
+			{Line: 1, Source: "rent = $1200", Var: "rent", Value: "$1,200.00"},
+			{Line: 2, Source: "food = $400", Var: "food", Value: "$400.00"},
+			{Line: 3, Source: "total = rent + food", Var: "total", Value: "$1,600.00"},
+		}},
+		{&Edit{At: 0, Removed: []string{"rent = $1200"}, Inserted: []string{"rent = $1250"}}, []Result{
+			{Line: 1, Source: "rent = $1250", Var: "rent", Value: "$1,250.00"},
+			{Line: 2, Source: "food = $400", Var: "food", Value: "$400.00"},
+			{Line: 3, Source: "total = rent + food", Var: "total", Value: "$1,650.00"},
+		}},
+		{&Edit{At: 3, Inserted: []string{"total * 12"}}, []Result{
+			{Line: 1, Source: "rent = $1250", Var: "rent", Value: "$1,250.00"},
+			{Line: 2, Source: "food = $400", Var: "food", Value: "$400.00"},
+			{Line: 3, Source: "total = rent + food", Var: "total", Value: "$1,650.00"},
+			{Line: 4, Source: "total * 12", Value: "$19,800.00"},
+		}},
+	}
+	for _, s := range steps {
+		if err := w.Write(s.edit, s.results); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &buf
+}
+
+func TestReadRebuildsEachStep(t *testing.T) {
+	tr, err := Read(session(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Steps) != 3 {
+		t.Fatalf("read %d steps, want 3", len(tr.Steps))
+	}
+	if got := tr.Lines(0); !slices.Equal(got, []string{"rent = $1200", "food = $400", "total = rent + food"}) {
+		t.Errorf("Lines(0) = %q", got)
+	}
+	if got := tr.Lines(2); !slices.Equal(got, []string{"rent = $1250", "food = $400", "total = rent + food", "total * 12"}) {
+		t.Errorf("Lines(2) = %q", got)
+	}
+	if got := tr.Describe(1); got != "line 1 changed" {
+		t.Errorf("Describe(1) = %q", got)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	tr, err := Read(session(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.Changes(0); got != nil {
+		t.Errorf("Changes(0) = %v, want none", got)
+	}
+
+	var got []string
+	for _, c := range tr.Changes(1) {
+		got = append(got, c.String())
+	}
+	want := []string{"rent: $1,200.00 → $1,250.00", "total: $1,600.00 → $1,650.00"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Changes(1) = %q, want %q", got, want)
+	}
+
+	if c := tr.Changes(2); len(c) != 1 || c[0].Name != "total * 12" || c[0].Was != "" {
+		t.Errorf("Changes(2) = %v, want the new expression", c)
+	}
+}
+
+func TestPrintFiltersByName(t *testing.T) {
+	tr, err := Read(session(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := tr.Print(&out, "total"); err != nil {
+		t.Fatal(err)
+	}
+	want := "step 1  +5s  line 1 changed\n" +
+		"  - rent = $1200\n" +
+		"  + rent = $1250\n" +
+		"    total: $1,600.00 → $1,650.00\n"
+	if out.String() != want {
+		t.Errorf("Print() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestReadDropsTruncatedStep(t *testing.T) {
+	buf := session(t)
+	data := buf.Bytes()
+	tr, err := Read(bytes.NewReader(data[:len(data)-20]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Steps) != 2 {
+		t.Errorf("read %d steps, want the 2 complete ones", len(tr.Steps))
+	}
+}
+
+func TestReadRejectsMismatchedEdit(t *testing.T) {
+	in := `{"step":0,"edit":{"inserted":["a = 1"]},"results":[]}
+{"step":1,"edit":{"at":0,"removed":["b = 2"],"inserted":["b = 3"]},"results":[]}
+`
+	if _, err := Read(strings.NewReader(in)); err == nil {
+		t.Error("Read() accepted an edit removing lines the document doesn't have")
+	}
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"io"
+
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/editor"
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/repl"
@@ -57,13 +59,27 @@ func NewEditorApp(doc *document.Document, filepath string) *App {
 	}
 }
 
+// TraceTo records the editor's session to w, for `calcmark replay`.
+func (a *App) TraceTo(w io.Writer) error {
+	return a.editor.TraceTo(w)
+}
+
 // Init implements tea.Model.
 func (a *App) Init() tea.Cmd {
 	switch a.mode {
 	case shared.ModeREPL:
 		return a.repl.Init()
 	case shared.ModeEditor:
-		return a.editor.Init()
+		return tea.Batch(a.editor.Init(), editorMouse())
+	}
+	return nil
+}
+
+// editorMouse turns on mouse input for the editor when tui.mouse is set.
+// The REPL leaves it off, so the terminal selects its output as text.
+func editorMouse() tea.Cmd {
+	if config.Get().TUI.Mouse {
+		return tea.EnableMouseCellMotion
 	}
 	return nil
 }
@@ -115,12 +131,14 @@ func (a *App) switchMode(msg shared.SwitchModeMsg) (tea.Model, tea.Cmd) {
 			a.editor = editor.New(doc)
 		}
 		a.mode = shared.ModeEditor
+		return a, editorMouse()
 
 	case shared.ModeREPL:
 		// Switch back to REPL mode
 		doc := a.editor.Document()
 		a.repl = repl.New(doc)
 		a.mode = shared.ModeREPL
+		return a, tea.DisableMouse
 	}
 
 	return a, nil
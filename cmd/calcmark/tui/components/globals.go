@@ -13,6 +13,7 @@ type GlobalVar struct {
 	Value      string // Formatted value
 	Expression string // Original expression (if any)
 	IsExchange bool   // Is this an exchange rate?
+	Widget     string // Bound widget type (e.g. "slider"), empty if none
 }
 
 // GlobalsPanelState holds the state for the globals panel.
@@ -103,6 +104,9 @@ func RenderGlobalsPanel(state GlobalsPanelState, width int, style GlobalsPanelSt
 		}
 
 		line += " = " + style.VarValue.Render(g.Value)
+		if g.Widget != "" {
+			line += style.Collapsed.Render(fmt.Sprintf("  [%s: -/+]", g.Widget))
+		}
 
 		// Apply focus styling if this is the focused item
 		if state.Focused && i == state.FocusIndex {
@@ -1,6 +1,7 @@
 package editor
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -388,3 +389,30 @@ func TestAlignedModel_InsertLineScenario(t *testing.T) {
 		t.Error("After insert: ReverseComplete invariant failed")
 	}
 }
+
+func TestComputeAlignedModel_TabsExpanded(t *testing.T) {
+	// 12 characters, but 20 columns once its tabs reach their stops
+	line := "\ta\tb = 1 + 2"
+	input := AlignedModelInput{
+		Lines:              []string{line},
+		Results:            []LineResult{{LineNum: 0, Source: line, BlockID: "b1", IsCalc: false}},
+		SourceContentWidth: 16,
+		PreviewWidth:       40,
+		PreviewMode:        PreviewFull,
+		TabWidth:           4,
+	}
+
+	model := ComputeAlignedModel(input, mockRenderCalcLine, nil)
+
+	if model.TotalVisualLines != 2 {
+		t.Fatalf("TotalVisualLines = %d, want 2 (tabs count as their width)", model.TotalVisualLines)
+	}
+	for _, l := range append(model.SourceLines, model.PreviewLines...) {
+		if strings.Contains(l.Content, "\t") {
+			t.Errorf("line %q still contains a tab", l.Content)
+		}
+	}
+	if got := model.PreviewLines[0].Content; !strings.HasPrefix(got, "    a   b") {
+		t.Errorf("preview = %q, want tabs expanded to stops", got)
+	}
+}
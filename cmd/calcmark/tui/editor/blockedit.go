@@ -0,0 +1,138 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Block editing: E opens every line of the block under the cursor in a
+// multi-line textarea, for restructuring a block rather than changing it a
+// line at a time. Esc applies the edit to the document in one change, so a
+// single undo reverts it.
+
+// blockEditMaxLines is the most lines the block editor holds.
+const blockEditMaxLines = 999
+
+// blockAt returns the block containing source line line, and the index of
+// its first line.
+func (m *Model) blockAt(line int) (*document.BlockNode, int, bool) {
+	start := 0
+	for _, node := range m.doc.GetBlocks() {
+		n := len(node.Block.Source())
+		if line < start+n {
+			return node, start, true
+		}
+		start += n
+	}
+	return nil, 0, false
+}
+
+// openBlockEdit opens the block under the cursor in the block editor, with
+// the textarea's cursor where the editor's was.
+func (m *Model) openBlockEdit() {
+	node, start, ok := m.blockAt(m.cursorLine)
+	if !ok {
+		m.statusMsg = "No block to edit"
+		m.statusIsErr = true
+		return
+	}
+	source := node.Block.Source()
+
+	ta := textarea.New()
+	ta.ShowLineNumbers = true
+	ta.Prompt = ""
+	ta.CharLimit = 0
+	ta.MaxHeight = blockEditMaxLines
+	ta.Cursor.SetMode(cursor.CursorStatic)
+	ta.SetValue(strings.Join(source, "\n"))
+	ta.Focus()
+
+	// SetValue leaves the cursor at the end; move it up to the cursor's line
+	row := m.cursorLine - start
+	for ta.Line() > row {
+		ta.CursorUp()
+	}
+	ta.CursorStart()
+	line := source[row]
+	ta.SetCursor(utf8.RuneCountInString(line[:min(m.cursorCol, len(line))]))
+
+	m.blockEditor = ta
+	m.blockEditID = node.ID
+	m.blockEditStart = start
+	m.mode = ModeBlockEdit
+	m.sizeBlockEditor()
+}
+
+// sizeBlockEditor fits the block editor to the source pane.
+func (m *Model) sizeBlockEditor() {
+	width, _ := m.GetPaneWidths(m.width)
+	m.blockEditor.SetWidth(width)
+	m.blockEditor.SetHeight(m.visibleHeight())
+}
+
+// handleBlockEditKey processes keys in the block editor.
+func (m Model) handleBlockEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc {
+		m.applyBlockEdit()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.blockEditor, cmd = m.blockEditor.Update(msg)
+	return m, cmd
+}
+
+// applyBlockEdit replaces the edited block's source with the block editor's
+// content, re-evaluates, and returns to normal mode with the cursor where
+// the textarea's was.
+func (m *Model) applyBlockEdit() {
+	m.mode = ModeNormal
+	m.blockEditor.Blur()
+
+	node, ok := m.doc.GetBlock(m.blockEditID)
+	if !ok {
+		m.statusMsg = "Block no longer exists"
+		m.statusIsErr = true
+		return
+	}
+
+	lines := strings.Split(m.blockEditor.Value(), "\n")
+	row := m.blockEditor.Line()
+	info := m.blockEditor.LineInfo()
+	col := info.StartColumn + info.ColumnOffset
+
+	prev := m.cursorLine
+	m.cursorLine = m.blockEditStart + row
+	m.cursorCol = len(string([]rune(lines[row])[:min(col, utf8.RuneCountInString(lines[row]))]))
+	m.followCursor(prev)
+
+	if slices.Equal(lines, node.Block.Source()) {
+		return
+	}
+
+	// Clear previous change markers, as starting a line edit does
+	m.changedBlockIDs = make(map[string]bool)
+	result, err := m.doc.ReplaceBlockSource(m.blockEditID, lines)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Block edit failed: %v", err)
+		m.statusIsErr = true
+		return
+	}
+	for _, id := range result.AffectedBlockIDs {
+		m.changedBlockIDs[id] = true
+	}
+	m.modified = true
+	m.pushUndoState()
+
+	// The block may now be several blocks, or text instead of calculations
+	m.redetectBlockTypes()
+	m.reEvaluate()
+	m.statusMsg = fmt.Sprintf("Block updated (%d lines)", len(lines))
+}
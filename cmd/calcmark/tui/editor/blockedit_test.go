@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeText sends text to the model one key at a time, Enter for newlines.
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		switch r {
+		case ' ':
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		case '\n':
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		m = press(m, msg)
+	}
+	return m
+}
+
+func TestBlockEdit(t *testing.T) {
+	doc, _ := document.NewDocument("# Costs\n\nprice = $20\nqty = 3\ntotal = price * qty\n")
+	m := New(doc)
+	m.cursorLine, m.cursorCol = 3, len("qty = 3")
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	if m.mode != ModeBlockEdit {
+		t.Fatalf("E should open the block editor, mode = %v", m.mode)
+	}
+	if got := m.blockEditor.Value(); got != "price = $20\nqty = 3\ntotal = price * qty\n" {
+		t.Fatalf("block editor holds %q, want the whole block with its trailing blank line", got)
+	}
+	if m.blockEditor.Line() != 1 {
+		t.Errorf("block editor cursor on row %d, want 1", m.blockEditor.Line())
+	}
+
+	// Restructure across lines: change qty and add a line between
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m = typeText(m, "4\ntax = 10%")
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnd})
+	m = typeText(m, " * (1 + tax)")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+
+	if m.mode != ModeNormal {
+		t.Fatalf("Esc should apply the edit and close the block editor, mode = %v", m.mode)
+	}
+	want := "# Costs\n\nprice = $20\nqty = 4\ntax = 10%\ntotal = price * qty * (1 + tax)"
+	if got := strings.Join(m.GetLines(), "\n"); !strings.HasPrefix(got, want) {
+		t.Fatalf("document = %q, want %q", got, want)
+	}
+	if total, _ := m.eval.GetEnvironment().Get("total"); total.String() != "$88.00" {
+		t.Errorf("total = %v, want $88.00", total)
+	}
+	if m.cursorLine != 5 || !m.modified {
+		t.Errorf("cursor line %d, modified %v; want the cursor on the last edited line 5 and modified", m.cursorLine, m.modified)
+	}
+
+	// The whole block edit is one undo step
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if got := strings.Join(m.GetLines(), "\n"); !strings.HasPrefix(got, "# Costs\n\nprice = $20\nqty = 3\ntotal = price * qty") {
+		t.Errorf("after undo, document = %q", got)
+	}
+}
+
+// TestBlockEditSplitsBlock checks a block edited into calculations and prose
+// is detected as separate blocks.
+func TestBlockEditSplitsBlock(t *testing.T) {
+	doc, _ := document.NewDocument("a = 1\nb = a + 1\n")
+	m := New(doc)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnd})
+	m = typeText(m, "\n\nThe next line doubles it.\n\nc = a * 2")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+
+	var kinds []string
+	for _, node := range m.doc.GetBlocks() {
+		switch node.Block.(type) {
+		case *document.CalcBlock:
+			kinds = append(kinds, "calc")
+		case *document.TextBlock:
+			kinds = append(kinds, "text")
+		}
+	}
+	if len(kinds) < 3 || kinds[0] != "calc" || kinds[1] != "text" || kinds[2] != "calc" {
+		t.Errorf("blocks = %v, want calc, text, calc", kinds)
+	}
+	if c, _ := m.eval.GetEnvironment().Get("c"); c == nil || c.String() != "2" {
+		t.Errorf("c = %v, want 2", c)
+	}
+}
+
+func TestBlockEditUnchanged(t *testing.T) {
+	doc, _ := document.NewDocument("x = 1\ny = 2\n")
+	m := New(doc)
+	m.cursorLine = 1
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}}, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != ModeNormal || m.modified || len(m.history.undo) != 0 {
+		t.Errorf("closing without changes: mode %v, modified %v, %d undo steps", m.mode, m.modified, len(m.history.undo))
+	}
+	if m.cursorLine != 1 {
+		t.Errorf("cursor line = %d, want 1", m.cursorLine)
+	}
+}
+
+func TestBlockEditQuickCalc(t *testing.T) {
+	doc, _ := document.NewDocument("x = 10\n")
+	m := New(doc)
+	m.width, m.height = 80, 20
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}}, tea.KeyMsg{Type: tea.KeyEnd})
+	m = typeText(m, "\ny = ")
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = typeQuickCalc(m, "x * 3")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.mode != ModeBlockEdit {
+		t.Fatalf("inserting should return to the block editor, mode = %v", m.mode)
+	}
+	if got := m.blockEditor.Value(); got != "x = 10\ny = x * 3\n" {
+		t.Errorf("block editor holds %q", got)
+	}
+	if !strings.Contains(m.View(), "y = x * 3") {
+		t.Error("source pane should show the block editor")
+	}
+}
@@ -30,17 +30,17 @@ x = 10
 y = 20
 z = 30`
 
-	doc, err := document.NewDocument(content)
-	if err != nil {
-		t.Fatalf("Failed to create document: %v", err)
-	}
-
 	datadriven.Walk(t, "testdata", func(t *testing.T, path string) {
 		// Skip compression subdirectory (handled by separate test)
 		if strings.HasPrefix(path, "testdata/compression/") {
 			return
 		}
 
+		// Edits change the document in place, so each file gets its own
+		doc, err := document.NewDocument(content)
+		if err != nil {
+			t.Fatalf("Failed to create document: %v", err)
+		}
 		m := New(doc)
 		m.width = 80
 		m.height = 24
@@ -76,12 +76,12 @@ no_compression = compress(200 MB, none)
 storage_savings = 10 GB - compress(10 GB, gzip)
 compressed_transfer = transfer_time(compress(1 GB, lz4), global, gigabit)`
 
-	doc, err := document.NewDocument(content)
-	if err != nil {
-		t.Fatalf("Failed to create document: %v", err)
-	}
-
 	datadriven.Walk(t, "testdata/compression", func(t *testing.T, path string) {
+		// Edits change the document in place, so each file gets its own
+		doc, err := document.NewDocument(content)
+		if err != nil {
+			t.Fatalf("Failed to create document: %v", err)
+		}
 		m := New(doc)
 		m.width = 80 // Narrower width to test wrapping
 		m.height = 24
@@ -0,0 +1,160 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shopspring/decimal"
+)
+
+// comparison holds the results /compare shows beside the document's, from
+// a JSON export (cm convert --to=json) or another document evaluated as a
+// scenario, such as last quarter's budget.
+type comparison struct {
+	name   string                        // File name, for the status line
+	values map[string]format.VariableRow // By variable; the last assignment wins
+}
+
+// loadComparison reads the results to compare against from path: a JSON
+// export if it ends in .json, otherwise a CalcMark document, which is
+// evaluated as the editor evaluates its own.
+func loadComparison(path string) (*comparison, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &comparison{name: filepath.Base(path), values: make(map[string]format.VariableRow)}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var export format.JSONDocument
+		if err := json.Unmarshal(content, &export); err != nil {
+			return nil, fmt.Errorf("%s is not a JSON export: %w", c.name, err)
+		}
+		for _, block := range export.Blocks {
+			for _, r := range block.Results {
+				if r.Variable == "" {
+					continue
+				}
+				row := format.VariableRow{Name: r.Variable, Value: r.Output, Raw: r.RawValue, Unit: r.Unit, Type: r.Type}
+				if r.Currency != nil {
+					row.Unit = r.Currency.Code
+				}
+				c.values[r.Variable] = row
+			}
+		}
+		return c, nil
+	}
+
+	doc, err := document.NewDocument(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", c.name, err)
+	}
+	_ = newEvaluator(path).Evaluate(doc) // Failed blocks have no values to compare
+	for _, row := range format.VariableRows(doc) {
+		if row.Value != "" {
+			c.values[row.Name] = row
+		}
+	}
+	return c, nil
+}
+
+// delta describes how a variable's current value differs from the
+// comparison's: the earlier value, and for numbers in the same unit the
+// change and percent change, as in "was $1,000.00  +$200.00 (+20.0%)".
+// sign is the change's sign, 0 if there is none to show.
+func (c *comparison) delta(name string, current format.VariableRow, opts display.Options) (text string, sign int) {
+	base, ok := c.values[name]
+	if !ok {
+		return "new", 0
+	}
+	if current.Value == "" || base.Value == current.Value {
+		return "", 0
+	}
+
+	was := "was " + base.Value
+	if base.Type != current.Type || base.Unit != current.Unit {
+		return was, 0
+	}
+	before, err1 := decimal.NewFromString(base.Raw)
+	after, err2 := decimal.NewFromString(current.Raw)
+	if err1 != nil || err2 != nil {
+		return was, 0
+	}
+
+	change := after.Sub(before)
+	sign = change.Sign()
+	if sign == 0 {
+		return "", 0
+	}
+	prefix := "+"
+	if sign < 0 {
+		prefix = "-"
+	}
+	text = was + "  " + prefix + formatChange(change.Abs(), current, opts)
+	if !before.IsZero() {
+		percent := change.Abs().Div(before.Abs()).Mul(decimal.NewFromInt(100))
+		text += " (" + prefix + percent.StringFixed(1) + "%)"
+	}
+	return text, sign
+}
+
+// formatChange formats the size of a change in the current value's type,
+// so a currency's change shows as money.
+func formatChange(change decimal.Decimal, current format.VariableRow, opts display.Options) string {
+	switch current.Type {
+	case "currency":
+		return display.FormatWith(types.NewCurrency(change, types.GetCurrencySymbol(current.Unit)), opts)
+	case "quantity":
+		return display.FormatWith(types.NewQuantity(change, current.Unit), opts)
+	case "number":
+		return display.FormatWith(types.NewNumber(change), opts)
+	}
+	if current.Unit != "" {
+		return change.String() + " " + current.Unit
+	}
+	return change.String()
+}
+
+// compareWith loads path for /compare, or with "off" stops comparing.
+func (m *Model) compareWith(path string) {
+	if path == "" {
+		m.statusMsg = "Usage: /compare <baseline.json|scenario.cm|off>"
+		m.statusIsErr = true
+		return
+	}
+	if path == "off" {
+		m.compare = nil
+		m.statusMsg = "Comparison off"
+		return
+	}
+	c, err := loadComparison(path)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Compare failed: %v", err)
+		m.statusIsErr = true
+		return
+	}
+	m.compare = c
+	m.statusMsg = fmt.Sprintf("Comparing with %s (%d values)", c.name, len(c.values))
+}
+
+// renderCompareColumn appends a line's comparison to its rendered value,
+// starting at the middle of the preview pane so the column lines up.
+func renderCompareColumn(value string, r LineResult, width int) string {
+	column := max(width/2, lipgloss.Width(value)+2)
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	switch {
+	case r.CompareSign > 0:
+		style = style.Foreground(lipgloss.Color("10"))
+	case r.CompareSign < 0:
+		style = style.Foreground(lipgloss.Color("9"))
+	}
+	return padToWidth(value, column) + style.Render(r.Compare)
+}
@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+func TestCompareWithScenario(t *testing.T) {
+	dir := t.TempDir()
+	baseline := filepath.Join(dir, "q3.cm")
+	if err := os.WriteFile(baseline, []byte("price = $20\nqty = 5\nnote = 10 kg\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, _ := document.NewDocument("price = $25\nqty = 5\nnote = 12 m\nextra = 3\n")
+	m := New(doc)
+	m.executeCommand("/compare " + baseline)
+	if m.statusIsErr || m.compare == nil {
+		t.Fatalf("/compare failed: %q", m.statusMsg)
+	}
+	if !strings.Contains(m.statusMsg, "q3.cm") {
+		t.Errorf("status = %q, want the file name", m.statusMsg)
+	}
+
+	results := m.lineResultsInRange(0, 4)
+	want := []struct {
+		text string
+		sign int
+	}{
+		{"was $20.00  +$5.00 (+25.0%)", 1},
+		{"", 0},
+		{"was 10 kg", 0},
+		{"new", 0},
+	}
+	for i, w := range want {
+		if results[i].Compare != w.text || results[i].CompareSign != w.sign {
+			t.Errorf("line %d: compare = %q (sign %d), want %q (sign %d)",
+				i, results[i].Compare, results[i].CompareSign, w.text, w.sign)
+		}
+	}
+
+	m.executeCommand("/compare off")
+	if m.compare != nil || m.lineResultsInRange(0, 1)[0].Compare != "" {
+		t.Error("/compare off left the comparison")
+	}
+}
+
+func TestCompareWithExport(t *testing.T) {
+	export := `{"blocks":[{"type":"calculation","results":[
+		{"variable":"revenue","output":"$1,000.00","raw_value":"1000","type":"currency","currency":{"code":"USD","symbol":"$"}},
+		{"variable":"growth","output":"8","raw_value":"8","type":"number"}]}]}`
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, _ := document.NewDocument("revenue = $800\ngrowth = 8\n")
+	m := New(doc)
+	m.executeCommand("/compare " + path)
+	if m.statusIsErr {
+		t.Fatalf("/compare failed: %q", m.statusMsg)
+	}
+	results := m.lineResultsInRange(0, 2)
+	if got := results[0].Compare; got != "was $1,000.00  -$200.00 (-20.0%)" || results[0].CompareSign != -1 {
+		t.Errorf("revenue compare = %q (sign %d)", got, results[0].CompareSign)
+	}
+	if got := results[1].Compare; got != "" {
+		t.Errorf("unchanged growth compare = %q, want none", got)
+	}
+
+	m.executeCommand("/compare " + filepath.Join(t.TempDir(), "missing.json"))
+	if !m.statusIsErr {
+		t.Error("/compare of a missing file should fail")
+	}
+}
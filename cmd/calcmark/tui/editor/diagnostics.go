@@ -0,0 +1,238 @@
+package editor
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Diagnostics: the problems checking and evaluating the document found are
+// marked where they are. The offending token is underlined in the color of
+// its severity, or the whole line when only the line is known, and the
+// line number takes the same color. ]d and [d jump to the next and
+// previous problem and show it in the status line, and D opens a panel
+// listing them all in line order.
+
+// maxDiagnosticRows is the most problems the panel lists at once; it
+// scrolls to keep the one at the cursor in view.
+const maxDiagnosticRows = 6
+
+// lineDiagnostic is a problem on a source line.
+type lineDiagnostic struct {
+	line     int // Source line index
+	column   int // Column in runes (1-indexed), 0 if only the line is known
+	severity semantic.Severity
+	code     string
+	message  string
+}
+
+// String formats the problem as "warning: message".
+func (d lineDiagnostic) String() string {
+	return strings.ToLower(d.severity.String()) + ": " + d.message
+}
+
+// diagnostics returns the document's problems in line order.
+func (m *Model) diagnostics() []lineDiagnostic {
+	var diags []lineDiagnostic
+	for _, d := range implDoc.DocumentDiagnostics(m.doc, m.eval) {
+		ld := lineDiagnostic{severity: d.Severity, code: d.Code, message: d.Message}
+		if d.Range != nil {
+			ld.line = max(0, d.Range.Start.Line-1)
+			ld.column = d.Range.Start.Column
+		}
+		diags = append(diags, ld)
+	}
+	slices.SortStableFunc(diags, func(a, b lineDiagnostic) int {
+		return cmp.Or(cmp.Compare(a.line, b.line), cmp.Compare(a.column, b.column))
+	})
+	return diags
+}
+
+// diagnosticMarks returns the problem to mark on each line: the most
+// severe, and of those the first.
+func diagnosticMarks(diags []lineDiagnostic) map[int]lineDiagnostic {
+	marks := make(map[int]lineDiagnostic)
+	for _, d := range diags {
+		if mark, ok := marks[d.line]; !ok || d.severity < mark.severity {
+			marks[d.line] = d
+		}
+	}
+	return marks
+}
+
+// jumpToDiagnostic moves the cursor to the next problem after it (dir 1)
+// or the previous one before it (dir -1), wrapping around the document,
+// and shows the problem in the status line.
+func (m *Model) jumpToDiagnostic(dir int) {
+	diags := m.diagnostics()
+	if len(diags) == 0 {
+		m.statusMsg = "No problems"
+		m.statusIsErr = false
+		return
+	}
+
+	lines := m.GetLines()
+	column := 0
+	if m.cursorLine < len(lines) {
+		column = runeColumn(lines[m.cursorLine], m.cursorCol)
+	}
+	// A problem without a column is at the start of its line
+	after := func(d lineDiagnostic) bool {
+		return d.line > m.cursorLine || d.line == m.cursorLine && max(d.column, 1) > column
+	}
+	before := func(d lineDiagnostic) bool {
+		return d.line < m.cursorLine || d.line == m.cursorLine && max(d.column, 1) < column
+	}
+
+	var i int
+	if dir > 0 {
+		if i = slices.IndexFunc(diags, after); i < 0 {
+			i = 0
+		}
+	} else {
+		i = len(diags) - 1
+		for i >= 0 && !before(diags[i]) {
+			i--
+		}
+		if i < 0 {
+			i = len(diags) - 1
+		}
+	}
+
+	d := diags[i]
+	prev := m.cursorLine
+	m.cursorLine = min(d.line, max(0, len(lines)-1))
+	m.cursorCol = 0
+	if d.column > 0 && m.cursorLine < len(lines) {
+		m.cursorCol = runeOffset(lines[m.cursorLine], d.column)
+	}
+	m.followCursor(prev)
+	m.statusMsg = fmt.Sprintf("Problem %d of %d: %s", i+1, len(diags), d)
+	m.statusIsErr = d.severity == semantic.Error
+}
+
+// toggleDiagnostics opens or closes the diagnostics panel.
+func (m *Model) toggleDiagnostics() {
+	m.diagnosticsOpen = !m.diagnosticsOpen
+	m.InvalidateAlignedCache()
+}
+
+// diagnosticsPanelHeight returns the lines the diagnostics panel takes,
+// its header included, or 0 when it is closed.
+func (m Model) diagnosticsPanelHeight() int {
+	if !m.diagnosticsOpen {
+		return 0
+	}
+	return 1 + min(max(1, len(m.diagnostics())), maxDiagnosticRows)
+}
+
+// renderDiagnosticsPanel renders the list of problems, the ones on the
+// cursor line highlighted.
+func (m Model) renderDiagnosticsPanel(width int) string {
+	diags := m.diagnostics()
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("252")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1).
+		Width(width).
+		Render(fmt.Sprintf("Problems (%d)  ]d/[d next/prev  D close", len(diags)))
+	if len(diags) == 0 {
+		return header + "\n" + m.styles.Hint.Render("  No problems")
+	}
+
+	// Scroll to show the first problem at or after the cursor
+	current := slices.IndexFunc(diags, func(d lineDiagnostic) bool { return d.line >= m.cursorLine })
+	if current < 0 {
+		current = len(diags) - 1
+	}
+	start := max(0, min(current-maxDiagnosticRows/2, len(diags)-maxDiagnosticRows))
+	end := min(start+maxDiagnosticRows, len(diags))
+
+	rows := []string{header}
+	for _, d := range diags[start:end] {
+		position := fmt.Sprintf("%d", d.line+1)
+		if d.column > 0 {
+			position += fmt.Sprintf(":%d", d.column)
+		}
+		severity := m.diagnosticStyle(d.severity).Render(fmt.Sprintf("%-7s", strings.ToLower(d.severity.String())))
+		row := fmt.Sprintf("  %-8s %s %s", position, severity, d.message)
+		row = lipgloss.NewStyle().MaxWidth(width).Render(row)
+		if d.line == m.cursorLine {
+			row = m.styles.CurrentLine.Width(width).Render(padToWidth(row, width))
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// diagnosticStyle returns the color problems of a severity are marked in:
+// the theme's error color, its amber for warnings, and dimmed for hints.
+func (m Model) diagnosticStyle(severity semantic.Severity) lipgloss.Style {
+	switch severity {
+	case semantic.Error:
+		return lipgloss.NewStyle().Foreground(m.styles.Error.GetForeground())
+	case semantic.Warning:
+		return lipgloss.NewStyle().Foreground(m.styles.Changed.GetForeground())
+	default:
+		return lipgloss.NewStyle().Foreground(m.styles.Hint.GetForeground())
+	}
+}
+
+// markDiagnostic underlines the part of line d is about, styling the text
+// around it with style (highlighting calculations, say).
+func (m Model) markDiagnostic(line string, d lineDiagnostic, style func(string) string) string {
+	start, end := diagnosticSpan(line, d.column, m.doc.NumberLocale())
+	if start >= end {
+		return style(line)
+	}
+	squiggle := m.diagnosticStyle(d.severity).Underline(true)
+	return style(line[:start]) + squiggle.Render(line[start:end]) + style(line[end:])
+}
+
+// diagnosticSpan returns the bytes of line a problem at column is about:
+// the token starting there, else the rest of the line. A problem without a
+// column is about the whole line but its indentation.
+func diagnosticSpan(line string, column int, loc lexer.NumberLocale) (start, end int) {
+	trimmed := strings.TrimRight(line, " \t")
+	if column <= 0 {
+		return len(trimmed) - len(strings.TrimLeft(trimmed, " \t")), len(trimmed)
+	}
+	start = runeOffset(line, column)
+	if start >= len(trimmed) {
+		return 0, 0
+	}
+	tokens, err := lexer.NewLexerWithLocale(line, loc).Tokenize()
+	if err == nil {
+		for _, tok := range tokens {
+			if tok.Line == 1 && tok.Column == column && tok.EndPos > tok.StartPos {
+				return start, min(runeOffset(line, column+tok.EndPos-tok.StartPos), len(trimmed))
+			}
+		}
+	}
+	return start, len(trimmed)
+}
+
+// runeOffset returns the byte offset of the 1-indexed rune column in s,
+// or len(s) past its end.
+func runeOffset(s string, column int) int {
+	n := 1
+	for i := range s {
+		if n == column {
+			return i
+		}
+		n++
+	}
+	return len(s)
+}
+
+// runeColumn returns the 1-indexed rune column of byte offset i in s.
+func runeColumn(s string, i int) int {
+	return len([]rune(s[:min(i, len(s))])) + 1
+}
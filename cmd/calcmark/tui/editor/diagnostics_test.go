@@ -0,0 +1,117 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+)
+
+// newDiagnosticsModel returns an editor on a document with a warning on
+// line 0 and an error on line 5.
+func newDiagnosticsModel(t *testing.T) Model {
+	t.Helper()
+	doc, err := document.NewDocument("third = 1 / 3\n\nNotes.\n\nx = 5\ny = missing + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(doc)
+}
+
+func TestDiagnosticsInLineOrder(t *testing.T) {
+	m := newDiagnosticsModel(t)
+	diags := m.diagnostics()
+	if len(diags) != 2 {
+		t.Fatalf("got %d problems, want 2: %v", len(diags), diags)
+	}
+	if d := diags[0]; d.line != 0 || d.severity != semantic.Warning {
+		t.Errorf("first problem %+v, want a warning on line 0", d)
+	}
+	if d := diags[1]; d.line != 5 || d.column != 5 || d.severity != semantic.Error || !strings.Contains(d.message, "missing") {
+		t.Errorf("second problem %+v, want the error at 6:5 naming missing", d)
+	}
+}
+
+func TestJumpToDiagnostic(t *testing.T) {
+	m := newDiagnosticsModel(t)
+	m.cursorLine = 2
+
+	m = press(m, runes("]d")...)
+	if m.cursorLine != 5 || m.cursorCol != 4 {
+		t.Errorf("]d moved to %d:%d, want 5:4", m.cursorLine, m.cursorCol)
+	}
+	if !m.statusIsErr || !strings.HasPrefix(m.statusMsg, "Problem 2 of 2: error:") {
+		t.Errorf("]d status %q", m.statusMsg)
+	}
+
+	// Past the last problem, ]d wraps to the first
+	m = press(m, runes("]d")...)
+	if m.cursorLine != 0 || m.statusIsErr || !strings.HasPrefix(m.statusMsg, "Problem 1 of 2: warning:") {
+		t.Errorf("]d at the last problem went to line %d, status %q", m.cursorLine, m.statusMsg)
+	}
+
+	// And [d wraps back
+	m = press(m, runes("[d")...)
+	if m.cursorLine != 5 {
+		t.Errorf("[d at the first problem went to line %d, want 5", m.cursorLine)
+	}
+	m = press(m, runes("[d")...)
+	if m.cursorLine != 0 {
+		t.Errorf("[d went to line %d, want 0", m.cursorLine)
+	}
+}
+
+func TestJumpToDiagnosticWithoutProblems(t *testing.T) {
+	m := newVisualModel(t)
+	m = press(m, runes("]d")...)
+	if m.cursorLine != 0 || m.statusMsg != "No problems" {
+		t.Errorf("]d with no problems: line %d, status %q", m.cursorLine, m.statusMsg)
+	}
+}
+
+func TestDiagnosticsPanel(t *testing.T) {
+	m := newDiagnosticsModel(t)
+	m.height = 30
+	_, before, _ := m.paneHeights()
+
+	m = press(m, runes("D")...)
+	if !m.diagnosticsOpen {
+		t.Fatal("D didn't open the diagnostics panel")
+	}
+	if _, after, _ := m.paneHeights(); after != before-3 {
+		t.Errorf("source pane shows %d lines with the panel open, want %d", after, before-3)
+	}
+
+	view := m.View()
+	for _, want := range []string{"Problems (2)", "1:9      warning", "6:5      error", "Undefined variable"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view doesn't show %q:\n%s", want, view)
+		}
+	}
+
+	m = press(m, runes("D")...)
+	if m.diagnosticsOpen || strings.Contains(m.View(), "Problems (2)") {
+		t.Error("D didn't close the diagnostics panel")
+	}
+}
+
+func TestDiagnosticSpan(t *testing.T) {
+	tests := []struct {
+		line   string
+		column int
+		want   string
+	}{
+		{"y = missing + 1", 5, "missing"},
+		{"  third = 1 / 3  ", 0, "third = 1 / 3"},
+		{"cost = €5 + unknown", 13, "unknown"},
+		{"x = 1", 9, ""},
+	}
+	for _, tt := range tests {
+		start, end := diagnosticSpan(tt.line, tt.column, lexer.NumberLocale{})
+		if got := tt.line[start:end]; got != tt.want {
+			t.Errorf("diagnosticSpan(%q, %d) = %q, want %q", tt.line, tt.column, got, tt.want)
+		}
+	}
+}
@@ -241,3 +241,61 @@ b = 2`
 		t.Errorf("Expected line 1 in edit mode after Up, got line %d mode %v", m.cursorLine, m.mode)
 	}
 }
+
+func TestEditModeWideCharacters(t *testing.T) {
+	doc, err := document.NewDocument("給料 = 5000\n💰_total = 給料 * 12")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	m := New(doc)
+	m.width = 80
+	m.height = 24
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		result, _ := m.Update(msg)
+		m = result.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	press(tea.KeyMsg{Type: tea.KeyHome})
+
+	// Right steps over 給 as one character
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	if m.cursorCol != len("給") {
+		t.Errorf("after Right, cursorCol = %d, want %d", m.cursorCol, len("給"))
+	}
+	press(tea.KeyMsg{Type: tea.KeyRight})
+
+	// Down keeps the display column: after 給料 (4 columns) is after 💰_t
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.editBuf[:m.cursorCol]; got != "💰_t" {
+		t.Errorf("after Down, text before cursor = %q, want %q", got, "💰_t")
+	}
+
+	// Backspace and Delete remove whole characters
+	press(tea.KeyMsg{Type: tea.KeyHome})
+	press(tea.KeyMsg{Type: tea.KeyDelete})
+	if m.editBuf != "_total = 給料 * 12" {
+		t.Errorf("after Delete, editBuf = %q", m.editBuf)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("💰")})
+	press(tea.KeyMsg{Type: tea.KeyEnd})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.editBuf != "💰_total = 給 * 12" {
+		t.Errorf("after Backspace, editBuf = %q", m.editBuf)
+	}
+
+	// Normal-mode column moves land on character boundaries too
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	m.cursorCol = 1
+	m.moveCursor(0, 1)
+	if m.cursorCol != len("💰") {
+		t.Errorf("after moving right from inside 💰, cursorCol = %d, want %d", m.cursorCol, len("💰"))
+	}
+}
@@ -0,0 +1,276 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/components"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Dependency graph: /graph shows which variables each variable feeds, as a
+// tree drawn with box-drawing lines in place of the panes. The roots are
+// the variables that read no others, and each variable's children are the
+// ones reading it. A variable read by several others appears under each;
+// after the first, it is marked ↑ instead of repeating its children.
+//
+// The variable under the cursor, the one its line assigns or else the
+// first it reads, is highlighted with everything downstream of it: what a
+// change to it can change. The header names the blocks the document
+// re-evaluates when it changes, from the document's own dependency graph,
+// which is what to check when a result looks stale.
+
+// graphNode is a variable in the dependency graph.
+type graphNode struct {
+	outlineVariable
+	reads      []int // Nodes it reads
+	dependents []int // Nodes reading it
+}
+
+// graphRow is a line of the graph view.
+type graphRow struct {
+	prefix string // Tree lines before the node
+	node   int
+	seen   bool // The node appeared above, with its dependents
+}
+
+// openGraph shows the dependency graph, scrolled to the variable under the
+// cursor.
+func (m *Model) openGraph() {
+	nodes := m.dependencyGraph()
+	if len(nodes) == 0 {
+		m.statusMsg = "No variables"
+		return
+	}
+	m.mode = ModeGraph
+	m.graphScroll = 0
+	focus := m.graphFocus(nodes)
+	if i := slices.IndexFunc(graphRows(nodes), func(r graphRow) bool { return r.node == focus }); i >= 0 {
+		m.graphScroll = max(0, i-m.graphHeight()/2)
+	}
+}
+
+// handleGraphKey processes keys in the dependency graph view.
+func (m Model) handleGraphKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	height := m.graphHeight()
+	switch msg.String() {
+	case "j", "down":
+		m.graphScroll++
+	case "k", "up":
+		m.graphScroll--
+	case "pgdown":
+		m.graphScroll += height
+	case "pgup":
+		m.graphScroll -= height
+	case "g", "home":
+		m.graphScroll = 0
+	case "G", "end":
+		m.graphScroll = len(graphRows(m.dependencyGraph()))
+	case "esc", "q":
+		m.mode = ModeNormal
+		return m, nil
+	}
+	rows := len(graphRows(m.dependencyGraph()))
+	m.graphScroll = max(0, min(m.graphScroll, rows-height))
+	return m, nil
+}
+
+// graphHeight returns the rows of the graph the view shows, between its
+// header and the separator above the status bar's two lines.
+func (m Model) graphHeight() int {
+	return max(1, m.height-4)
+}
+
+// dependencyGraph returns the document's variables, as the outline lists
+// them, with which of them each reads. A name read on a line is the
+// variable of that name assigned last before it.
+func (m *Model) dependencyGraph() []graphNode {
+	vars := m.outlineVariables()
+	nodes := make([]graphNode, len(vars))
+	index := make(map[string]int) // block/name → node
+	for i, v := range vars {
+		nodes[i].outlineVariable = v
+		index[fmt.Sprintf("%d/%s", v.block, v.name)] = i
+	}
+
+	defined := make(map[string]int) // Name → node last assigning it
+	block, lineNum := 0, 0
+	results := m.GetLineResults()
+	for _, node := range m.doc.GetBlocks() {
+		start := lineNum
+		lineNum += len(node.Block.Source())
+		if _, ok := node.Block.(*document.CalcBlock); !ok {
+			continue
+		}
+		block++
+
+		for _, lr := range results[start:min(lineNum, len(results))] {
+			self, ok := index[fmt.Sprintf("%d/%s", block, lr.VarName)]
+			if lr.VarName == "" || !ok {
+				continue
+			}
+			for _, tok := range readIdentifiers(lr.Source, m.doc.NumberLocale()) {
+				dep, ok := defined[tok.Value]
+				if !ok || dep == self || slices.Contains(nodes[self].reads, dep) {
+					continue
+				}
+				nodes[self].reads = append(nodes[self].reads, dep)
+				nodes[dep].dependents = append(nodes[dep].dependents, self)
+			}
+			defined[lr.VarName] = self
+		}
+	}
+	return nodes
+}
+
+// graphRows lays the graph out as a tree from its roots.
+func graphRows(nodes []graphNode) []graphRow {
+	var rows []graphRow
+	shown := make(map[int]bool)
+	var walk func(i int, prefix, branch, indent string)
+	walk = func(i int, prefix, branch, indent string) {
+		if shown[i] {
+			rows = append(rows, graphRow{prefix: prefix + branch, node: i, seen: len(nodes[i].dependents) > 0})
+			return
+		}
+		shown[i] = true
+		rows = append(rows, graphRow{prefix: prefix + branch, node: i})
+		for j, child := range nodes[i].dependents {
+			if j == len(nodes[i].dependents)-1 {
+				walk(child, prefix+indent, "└── ", "    ")
+			} else {
+				walk(child, prefix+indent, "├── ", "│   ")
+			}
+		}
+	}
+	for i, n := range nodes {
+		if len(n.reads) == 0 {
+			walk(i, "", "", "")
+		}
+	}
+	return rows
+}
+
+// graphFocus returns the node of the variable under the cursor: the one
+// its line assigns, else the first it reads; -1 for none.
+func (m *Model) graphFocus(nodes []graphNode) int {
+	lines := m.GetLines()
+	if m.cursorLine >= len(lines) {
+		return -1
+	}
+	var names []string
+	if results := m.lineResultsInRange(m.cursorLine, m.cursorLine+1); len(results) == 1 && results[0].VarName != "" {
+		names = append(names, results[0].VarName)
+	}
+	for _, tok := range readIdentifiers(lines[m.cursorLine], m.doc.NumberLocale()) {
+		names = append(names, tok.Value)
+	}
+	for _, name := range names {
+		focus := -1
+		for i, n := range nodes {
+			if n.name == name && n.line <= m.cursorLine {
+				focus = i
+			}
+		}
+		if focus >= 0 {
+			return focus
+		}
+	}
+	return -1
+}
+
+// downstream returns the nodes reading focus, directly or through others.
+func downstream(nodes []graphNode, focus int) map[int]bool {
+	affected := make(map[int]bool)
+	if focus < 0 {
+		return affected
+	}
+	queue := slices.Clone(nodes[focus].dependents)
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if !affected[i] {
+			affected[i] = true
+			queue = append(queue, nodes[i].dependents...)
+		}
+	}
+	return affected
+}
+
+// graphSummary describes what a change to the focused variable affects.
+func (m Model) graphSummary(nodes []graphNode, focus int, affected map[int]bool) string {
+	if focus < 0 {
+		return fmt.Sprintf("Dependency graph: %d variables", len(nodes))
+	}
+	name := nodes[focus].name
+	summary := fmt.Sprintf("Dependency graph: %s affects %d variables", name, len(affected))
+
+	ids := m.doc.GetTransitiveDependents([]string{name})
+	var blocks []string
+	block := 0
+	for _, node := range m.doc.GetBlocks() {
+		if _, ok := node.Block.(*document.CalcBlock); !ok {
+			continue
+		}
+		block++
+		if slices.Contains(ids, node.ID) {
+			blocks = append(blocks, fmt.Sprint(block))
+		}
+	}
+	if len(blocks) > 0 {
+		summary += ", re-evaluating blocks " + strings.Join(blocks, ", ")
+	}
+	return summary
+}
+
+// renderGraph renders the dependency graph view.
+func (m Model) renderGraph() string {
+	nodes := m.dependencyGraph()
+	rows := graphRows(nodes)
+	focus := m.graphFocus(nodes)
+	affected := downstream(nodes, focus)
+	width := m.width
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("252")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1).
+		Width(width).
+		MaxWidth(width).
+		Render(m.graphSummary(nodes, focus, affected))
+
+	treeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	affectedStyle := lipgloss.NewStyle().Foreground(m.styles.Changed.GetForeground())
+	lines := []string{header}
+	height := m.graphHeight()
+	for _, r := range rows[min(m.graphScroll, len(rows)):min(m.graphScroll+height, len(rows))] {
+		n := nodes[r.node]
+		label := n.name
+		if n.value != "" {
+			label += " = " + n.value
+		}
+		if r.seen {
+			label += " ↑"
+		}
+		label += fmt.Sprintf("  block %d, line %d", n.block, n.line+1)
+
+		switch {
+		case r.node == focus:
+			label = m.styles.CurrentLine.Bold(true).Render(label)
+		case affected[r.node]:
+			label = affectedStyle.Render(label)
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(width).Render(treeStyle.Render(r.prefix)+label))
+	}
+	for len(lines) < height+1 {
+		lines = append(lines, "")
+	}
+
+	separator := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("─", width))
+	statusBar := components.RenderStatusBar(m.GetStatusBarState(), width, components.DefaultStatusBarStyle())
+	return strings.Join(append(lines, separator, statusBar), "\n")
+}
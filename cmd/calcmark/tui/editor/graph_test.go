@@ -0,0 +1,124 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newGraphModel returns a 90-column editor on a document where price and
+// qty feed total, which feeds tax, and both feed grand; other stands
+// alone.
+func newGraphModel(t *testing.T, height int) Model {
+	t.Helper()
+	doc, err := document.NewDocument("price = $100\nqty = 3\nprice = price * 2\n\nSome notes.\n\n" +
+		"total = price * qty\ntax = total * 10%\ngrand = total + tax\nother = 5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, _ := New(doc).Update(tea.WindowSizeMsg{Width: 90, Height: height})
+	return model.(Model)
+}
+
+func TestDependencyGraph(t *testing.T) {
+	m := newGraphModel(t, 20)
+	nodes := m.dependencyGraph()
+
+	reads := make(map[string][]string)
+	for _, n := range nodes {
+		for _, r := range n.reads {
+			reads[n.name] = append(reads[n.name], nodes[r].name)
+		}
+	}
+	want := map[string]string{"price": "", "qty": "", "total": "price qty", "tax": "total", "grand": "total tax", "other": ""}
+	for name, w := range want {
+		if got := strings.Join(reads[name], " "); got != w {
+			t.Errorf("%s reads %q, want %q", name, got, w)
+		}
+	}
+}
+
+func TestGraphView(t *testing.T) {
+	m := newGraphModel(t, 20)
+	m.executeCommand("/graph")
+	if m.mode != ModeGraph {
+		t.Fatalf("/graph: mode %v, status %q", m.mode, m.statusMsg)
+	}
+
+	view := m.View()
+	lines := strings.Split(view, "\n")
+	if len(lines) != 20 {
+		t.Errorf("graph view is %d lines, want the screen's 20", len(lines))
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > 90 {
+			t.Errorf("line %d is %d columns wide, more than the screen", i, w)
+		}
+	}
+	for _, want := range []string{
+		"Dependency graph: price affects 3 variables, re-evaluating blocks 2",
+		"price = $200.00  block 1, line 1",
+		"└── total = $600.00  block 2, line 7",
+		"    ├── tax = $60.00  block 2, line 8",
+		"    │   └── grand = $660.00  block 2, line 9",
+		"└── total = $600.00 ↑  block 2, line 7",
+		"other = 5  block 2, line 10",
+		"GRAPH",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("graph view doesn't show %q:\n%s", want, view)
+		}
+	}
+
+	m = press(m, keyEsc)
+	if m.mode != ModeNormal || strings.Contains(m.View(), "Dependency graph") {
+		t.Error("Esc didn't close the graph")
+	}
+}
+
+func TestGraphHighlightsDownstream(t *testing.T) {
+	m := newGraphModel(t, 20)
+	nodes := m.dependencyGraph()
+
+	// On tax's line, tax is the focus and only grand is downstream
+	m.cursorLine = 7
+	focus := m.graphFocus(nodes)
+	if focus < 0 || nodes[focus].name != "tax" {
+		t.Fatalf("focus on line 8 is %d, want tax", focus)
+	}
+	affected := downstream(nodes, focus)
+	if len(affected) != 1 || !affected[focus+1] {
+		t.Errorf("downstream of tax = %v, want grand", affected)
+	}
+
+	// A text line has no focus
+	m.cursorLine = 4
+	if focus := m.graphFocus(nodes); focus != -1 {
+		t.Errorf("focus on a text line is %d", focus)
+	}
+}
+
+func TestGraphScrolls(t *testing.T) {
+	m := newGraphModel(t, 7)
+	m.executeCommand("/graph")
+	if m.graphScroll != 0 {
+		t.Fatalf("opened scrolled to %d", m.graphScroll)
+	}
+
+	// 8 rows, 3 shown: G scrolls to the last 3
+	m = press(m, runes("G")...)
+	if m.graphScroll != 5 || !strings.Contains(m.View(), "other = 5") {
+		t.Errorf("G scrolled to %d", m.graphScroll)
+	}
+	m = press(m, runes("jk")...)
+	if m.graphScroll != 4 {
+		t.Errorf("j at the end then k scrolled to %d, want 4", m.graphScroll)
+	}
+	m = press(m, runes("g")...)
+	if m.graphScroll != 0 {
+		t.Errorf("g scrolled to %d", m.graphScroll)
+	}
+}
@@ -0,0 +1,88 @@
+package editor
+
+import (
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/rivo/uniseg"
+)
+
+// The cursor column is a byte offset into the edit buffer that always falls
+// between grapheme clusters: the characters a reader sees, such as 給, 💰,
+// or an emoji sequence joined with zero-width joiners. Moving, deleting, and
+// drawing the cursor go one cluster at a time, and widths are measured in
+// terminal columns, so CJK identifiers and emoji variables keep the cursor
+// where it is drawn.
+
+// nextGrapheme returns the offset just past the grapheme cluster starting at
+// offset i of s, or len(s) at the end.
+func nextGrapheme(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(s[i:], -1)
+	return i + len(cluster)
+}
+
+// prevGrapheme returns the offset of the grapheme cluster ending at offset i
+// of s, or 0 at the start.
+func prevGrapheme(s string, i int) int {
+	prev := 0
+	for j := 0; j < i && j < len(s); j = nextGrapheme(s, j) {
+		prev = j
+	}
+	return prev
+}
+
+// clampGrapheme returns the offset of the grapheme cluster boundary at or
+// before offset i of s, so a column carried over from another line never
+// splits a character.
+func clampGrapheme(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	j := 0
+	for next := nextGrapheme(s, j); next <= i; next = nextGrapheme(s, j) {
+		j = next
+	}
+	return j
+}
+
+// graphemeColumn returns the display column, counted from 0, that offset i of
+// s is drawn at, with tabs expanded to tabWidth.
+func graphemeColumn(s string, i, tabWidth int) int {
+	column := 0
+	for j := 0; j < i && j < len(s); {
+		next := nextGrapheme(s, j)
+		column += clusterWidth(s[j:next], column, tabWidth)
+		j = next
+	}
+	return column
+}
+
+// graphemeOffset returns the offset of the grapheme cluster drawn at display
+// column, counted from 0, in s: the inverse of graphemeColumn. A column
+// inside a wide character maps to its start, and one past the end to len(s).
+func graphemeOffset(s string, column, tabWidth int) int {
+	at := 0
+	for j := 0; j < len(s); {
+		next := nextGrapheme(s, j)
+		w := clusterWidth(s[j:next], at, tabWidth)
+		if at+w > column {
+			return j
+		}
+		at += w
+		j = next
+	}
+	return len(s)
+}
+
+// clusterWidth returns the columns a grapheme cluster takes when drawn at
+// column: a tab reaches the next tab stop.
+func clusterWidth(cluster string, column, tabWidth int) int {
+	if cluster == "\t" {
+		if tabWidth < 1 {
+			tabWidth = document.DefaultTabWidth
+		}
+		return tabWidth - column%tabWidth
+	}
+	return uniseg.StringWidth(cluster)
+}
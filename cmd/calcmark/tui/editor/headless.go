@@ -0,0 +1,35 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderLines lays out the whole document as the editor shows it, without
+// a terminal: the source pane's lines, with line numbers, and the preview
+// pane's lines, aligned one to one. Lines carry ANSI styling for the
+// current lipgloss color profile. No line is marked as the cursor line.
+//
+// This is the headless rendering behind `calcmark screenshot`.
+func (m Model) RenderLines(sourceWidth, previewWidth int) (source, preview []string) {
+	m.cursorLine = -1
+	aligned := m.computeAlignedModelFresh(sourceWidth, previewWidth)
+
+	lineNumWidth := 4
+	blank := m.styles.LineNumber.Width(lineNumWidth).Render("")
+	for _, sl := range aligned.SourceLines {
+		lineNum := blank
+		if sl.Kind == AlignedLineNormal {
+			lineNum = m.styles.LineNumber.
+				Width(lineNumWidth).
+				Align(lipgloss.Right).
+				Render(fmt.Sprintf("%d", sl.LineNum))
+		}
+		source = append(source, lineNum+" "+sl.Content)
+	}
+	for _, pl := range aligned.PreviewLines {
+		preview = append(preview, pl.Content)
+	}
+	return source, preview
+}
@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+func TestRenderLines(t *testing.T) {
+	doc, err := document.NewDocument("# Title\n\nrent = $1200\ntotal = rent * 2\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(doc)
+	m.cursorLine = 2
+
+	source, preview := m.RenderLines(40, 30)
+	if len(source) != len(preview) {
+		t.Fatalf("panes not aligned: %d source lines, %d preview lines", len(source), len(preview))
+	}
+
+	// Tests run without a terminal, so lines carry no styling
+	plain := func(lines []string) string {
+		var out []string
+		for _, line := range lines {
+			out = append(out, strings.TrimRight(line, " "))
+		}
+		return strings.Join(out, "\n")
+	}
+	if got := plain(source); !strings.Contains(got, "   3 rent = $1200\n   4 total = rent * 2") {
+		t.Errorf("source lines missing numbered calculations:\n%s", got)
+	}
+	if got := plain(preview); !strings.Contains(got, "total → $2400.00") {
+		t.Errorf("preview lines missing result:\n%s", got)
+	}
+	if m.cursorLine != 2 {
+		t.Error("RenderLines changed the cursor")
+	}
+}
@@ -0,0 +1,120 @@
+package editor
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Syntax highlighting: calculation lines in the source pane are drawn with
+// a style per lexer token, so numbers, units, and names stand apart as in
+// a code editor. Lines are lexed as they're drawn, which is only the
+// visible ones; a line that doesn't lex, mid-edit say, is drawn plain.
+
+// syntaxSpan is a byte range of a line drawn in a token style.
+type syntaxSpan struct {
+	start, end int
+	style      lipgloss.Style
+}
+
+// highlightCalcLine returns line with its tokens styled. It's unchanged
+// when it doesn't lex.
+func highlightCalcLine(line string, styles config.Styles, loc lexer.NumberLocale) string {
+	spans := syntaxSpans(line, styles, loc)
+	if len(spans) == 0 {
+		return line
+	}
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		b.WriteString(line[pos:s.start])
+		b.WriteString(s.style.Render(line[s.start:s.end]))
+		pos = s.end
+	}
+	b.WriteString(line[pos:])
+	return b.String()
+}
+
+// syntaxSpans lexes line and returns its styled tokens in order.
+func syntaxSpans(line string, styles config.Styles, loc lexer.NumberLocale) []syntaxSpan {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	tokens, err := lexer.NewLexerWithLocale(line, loc).Tokenize()
+	if err != nil {
+		return nil
+	}
+
+	// Token positions count runes; spans are byte offsets
+	offsets := make([]int, 0, len(line)+1)
+	for i := range line {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(line))
+
+	var spans []syntaxSpan
+	pos := 0
+	for _, tok := range tokens {
+		// Spans must be in order, within the line, and not overlap
+		if tok.StartPos < pos || tok.EndPos >= len(offsets) || tok.StartPos >= tok.EndPos {
+			continue
+		}
+		start, end := offsets[tok.StartPos], offsets[tok.EndPos]
+		if tok.Type == lexer.QUANTITY {
+			spans = append(spans, quantitySpans(line, start, end, styles)...)
+		} else if style, ok := tokenStyle(tok.Type, styles); ok {
+			spans = append(spans, syntaxSpan{start, end, style})
+		}
+		pos = tok.EndPos
+	}
+	return spans
+}
+
+// quantitySpans splits a quantity like "3 kg", the bytes of line from
+// start to end, into its number and unit.
+func quantitySpans(line string, start, end int, styles config.Styles) []syntaxSpan {
+	text := line[start:end]
+	unit := strings.IndexFunc(text, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsSymbol(r)
+	})
+	if unit <= 0 {
+		return []syntaxSpan{{start, end, styles.SyntaxUnit}}
+	}
+	number := len(strings.TrimRight(text[:unit], " \u00a0\u202f"))
+	return []syntaxSpan{
+		{start, start + number, styles.SyntaxNumber},
+		{start + unit, end, styles.SyntaxUnit},
+	}
+}
+
+// tokenStyle returns the style a token type is drawn in, if any;
+// punctuation is drawn plain.
+func tokenStyle(t lexer.TokenType, styles config.Styles) (lipgloss.Style, bool) {
+	switch {
+	case t == lexer.IDENTIFIER:
+		return styles.SyntaxIdentifier, true
+	case t == lexer.BOOLEAN, t >= lexer.IF && t <= lexer.DATE_LAST_YEAR:
+		// Keywords, function names, and relative dates, declared together
+		return styles.SyntaxKeyword, true
+	}
+	switch t {
+	case lexer.NUMBER, lexer.NUMBER_PERCENT, lexer.NUMBER_K, lexer.NUMBER_M,
+		lexer.NUMBER_B, lexer.NUMBER_T, lexer.NUMBER_SCI,
+		lexer.DATE_LITERAL, lexer.DURATION_LITERAL, lexer.TIME_LITERAL:
+		return styles.SyntaxNumber, true
+	case lexer.CURRENCY, lexer.CURRENCY_SYM, lexer.CURRENCY_CODE:
+		return styles.SyntaxCurrency, true
+	case lexer.TIMEZONE:
+		return styles.SyntaxUnit, true
+	case lexer.PLUS, lexer.MINUS, lexer.MULTIPLY, lexer.DIVIDE, lexer.MODULUS,
+		lexer.EXPONENT, lexer.ASSIGN, lexer.AT_PREFIX,
+		lexer.GREATER_THAN, lexer.LESS_THAN, lexer.GREATER_EQUAL,
+		lexer.LESS_EQUAL, lexer.EQUAL, lexer.NOT_EQUAL,
+		lexer.AND, lexer.OR, lexer.NOT:
+		return styles.SyntaxOperator, true
+	}
+	return lipgloss.Style{}, false
+}
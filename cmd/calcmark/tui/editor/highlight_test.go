@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// syntaxTheme gives each syntax style a color named for it.
+func syntaxTheme() config.Styles {
+	return config.ThemeConfig{
+		SyntaxNumber:     "number",
+		SyntaxCurrency:   "currency",
+		SyntaxUnit:       "unit",
+		SyntaxOperator:   "operator",
+		SyntaxIdentifier: "identifier",
+		SyntaxKeyword:    "keyword",
+	}.BuildStyles()
+}
+
+func TestSyntaxSpans(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string // Each span's text and color
+	}{
+		{"cost = $1,200 * 3 kg", []string{
+			"cost:identifier", "=:operator", "$:currency", "1,200:number",
+			"*:operator", "3:number", "kg:unit",
+		}},
+		{"y = 12k USD", []string{"y:identifier", "=:operator", "12k:number", "USD:currency"}},
+		{"avg(1, 2) > 3 and true", []string{
+			"avg:keyword", "1:number", "2:number", ">:operator", "3:number",
+			"and:operator", "true:keyword",
+		}},
+		{"größe = 5 €", []string{"größe:identifier", "=:operator", "5:number", "€:currency"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range syntaxSpans(tt.line, syntaxTheme(), lexer.LocaleUS) {
+			color, _ := s.style.GetForeground().(lipgloss.Color)
+			got = append(got, tt.line[s.start:s.end]+":"+string(color))
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("syntaxSpans(%q) = %v, want %v", tt.line, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("syntaxSpans(%q) = %v, want %v", tt.line, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestHighlightCalcLineLeavesUnlexableLinesPlain(t *testing.T) {
+	for _, line := range []string{"", "   ", "x = \"unterminated"} {
+		if got := highlightCalcLine(line, syntaxTheme(), lexer.LocaleUS); got != line {
+			t.Errorf("highlightCalcLine(%q) = %q, want it unchanged", line, got)
+		}
+	}
+}
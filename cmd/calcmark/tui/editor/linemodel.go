@@ -1,6 +1,9 @@
 package editor
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+)
 
 // LineModel computes the visual line layout for source and preview panes.
 // This is a pure computation - no rendering, no side effects.
@@ -232,8 +235,9 @@ func computePreviewLinesForLine(lineIdx int, input LineModelInput) []VisualLine
 }
 
 // WrapText wraps text to fit within maxWidth, preferring word boundaries.
-// Returns a slice of strings, each fitting within maxWidth.
-// Uses lipgloss.Width for robust unicode width handling (CJK, emoji, etc).
+// Returns a slice of strings, each fitting within maxWidth, that join back
+// into text. Lines break between grapheme clusters, measured in terminal
+// columns, so CJK characters and emoji sequences are never split.
 // This is a pure function suitable for unit testing.
 func WrapText(text string, maxWidth int) []string {
 	if maxWidth <= 0 {
@@ -249,46 +253,47 @@ func WrapText(text string, maxWidth int) []string {
 	}
 
 	var result []string
-	runes := []rune(text)
 	start := 0
 
-	for start < len(runes) {
-		// Find how many runes fit within maxWidth
+	for start < len(text) {
+		// Find how many grapheme clusters fit within maxWidth
 		end := start
 		currentWidth := 0
-		lastSpaceIdx := -1
+		lastSpaceEnd := -1
 
-		for end < len(runes) {
-			rw := lipgloss.Width(string(runes[end]))
-			if currentWidth+rw > maxWidth {
+		for end < len(text) {
+			next := nextGrapheme(text, end)
+			cw := uniseg.StringWidth(text[end:next])
+			if currentWidth+cw > maxWidth {
 				break
 			}
-			if runes[end] == ' ' {
-				lastSpaceIdx = end
+			if text[end] == ' ' {
+				lastSpaceEnd = next
 			}
-			currentWidth += rw
-			end++
+			currentWidth += cw
+			end = next
 		}
 
-		// If we've consumed all remaining runes, we're done
-		if end >= len(runes) {
-			result = append(result, string(runes[start:]))
+		// If we've consumed all remaining text, we're done
+		if end >= len(text) {
+			result = append(result, text[start:])
 			break
 		}
 
 		// Prefer breaking at word boundary
-		if lastSpaceIdx > start {
+		if lastSpaceEnd > start+1 {
 			// Break after the space
-			result = append(result, string(runes[start:lastSpaceIdx+1]))
-			start = lastSpaceIdx + 1
+			result = append(result, text[start:lastSpaceEnd])
+			start = lastSpaceEnd
 		} else if end > start {
 			// No space found, hard break
-			result = append(result, string(runes[start:end]))
+			result = append(result, text[start:end])
 			start = end
 		} else {
 			// Single character wider than maxWidth - include it anyway
-			result = append(result, string(runes[start:start+1]))
-			start++
+			next := nextGrapheme(text, start)
+			result = append(result, text[start:next])
+			start = next
 		}
 	}
 
@@ -83,6 +83,20 @@ func TestWrapText(t *testing.T) {
 			// "café ☕" = 7 fits, "café ☕ " = 8 fits
 			want: []string{"café ☕ ", "time"},
 		},
+		{
+			name:     "CJK identifier",
+			text:     "給料給料 = 5000",
+			maxWidth: 6,
+			// 給料給 = 6 fits exactly; the space after 料 is the last break
+			want: []string{"給料給", "料 = ", "5000"},
+		},
+		{
+			name:     "emoji sequence kept whole",
+			text:     "👨‍👩‍👧_total👨‍👩‍👧",
+			maxWidth: 8,
+			// The family emoji is one 2-column grapheme cluster of five runes
+			want: []string{"👨‍👩‍👧_total", "👨‍👩‍👧"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,6 +2,7 @@ package editor
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
@@ -71,6 +72,59 @@ func (m *MarkdownRenderer) RenderLine(line string) []string {
 	return output
 }
 
+// markdownCacheLimit bounds the number of memoized line renderings. Lines
+// are keyed by content, so edits never see stale output; the limit only
+// keeps old versions of edited lines from accumulating.
+const markdownCacheLimit = 4096
+
+// markdownKey identifies a rendered markdown line. The style is fixed
+// (createMinimalStyle), so content and width determine the output.
+type markdownKey struct {
+	line  string
+	width int
+}
+
+// markdownCache memoizes renderers by width and rendered lines by
+// markdownKey, shared by every preview and alignment computation.
+var markdownCache = struct {
+	sync.Mutex
+	renderers map[int]*MarkdownRenderer
+	lines     map[markdownKey][]string
+}{
+	renderers: make(map[int]*MarkdownRenderer),
+	lines:     make(map[markdownKey][]string),
+}
+
+// renderMarkdownLine renders one line of markdown at width, as
+// MarkdownRenderer.RenderLine does, reusing earlier results. Lines are
+// plain-wrapped if no renderer can be created. Callers must not modify the
+// returned slice.
+func renderMarkdownLine(line string, width int) []string {
+	markdownCache.Lock()
+	defer markdownCache.Unlock()
+
+	key := markdownKey{line: line, width: width}
+	if lines, ok := markdownCache.lines[key]; ok {
+		return lines
+	}
+
+	renderer := markdownCache.renderers[width]
+	if renderer == nil {
+		var err error
+		if renderer, err = NewMarkdownRenderer(width); err != nil {
+			return WrapText(line, width)
+		}
+		markdownCache.renderers[width] = renderer
+	}
+
+	if len(markdownCache.lines) >= markdownCacheLimit {
+		clear(markdownCache.lines)
+	}
+	lines := renderer.RenderLine(line)
+	markdownCache.lines[key] = lines
+	return lines
+}
+
 // isHorizontalRule checks if a line is a markdown horizontal rule.
 func isHorizontalRule(line string) bool {
 	if len(line) < 3 {
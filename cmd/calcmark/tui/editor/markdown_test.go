@@ -0,0 +1,52 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderMarkdownLineMatchesRenderer(t *testing.T) {
+	lines := []string{"# Title", "Some *emphasis* and `code`", "- item", "---", "", "> quote"}
+	for _, width := range []int{20, 40} {
+		renderer, err := NewMarkdownRenderer(width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range lines {
+			want := renderer.RenderLine(line)
+			for range 2 { // Rendered, then cached
+				if got := renderMarkdownLine(line, width); !reflect.DeepEqual(got, want) {
+					t.Errorf("renderMarkdownLine(%q, %d) = %q, want %q", line, width, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderMarkdownLineReusesResults(t *testing.T) {
+	first := renderMarkdownLine("Reused **line**", 30)
+	second := renderMarkdownLine("Reused **line**", 30)
+	if &first[0] != &second[0] {
+		t.Error("expected the cached rendering to be returned")
+	}
+	if renderMarkdownLine("Reused **line**", 31)[0] == "" {
+		t.Error("expected a rendering at another width")
+	}
+
+	for i := range markdownCacheLimit + 1 {
+		renderMarkdownLine(string(rune('a'+i%26))+string(rune(i)), 30)
+	}
+	markdownCache.Lock()
+	size := len(markdownCache.lines)
+	markdownCache.Unlock()
+	if size > markdownCacheLimit {
+		t.Errorf("cache holds %d lines, limit is %d", size, markdownCacheLimit)
+	}
+}
+
+func BenchmarkRenderMarkdownLine(b *testing.B) {
+	lines := []string{"# Title", "Some *emphasis* and `code`", "- item", "> quote"}
+	for i := range b.N {
+		renderMarkdownLine(lines[i%len(lines)], 60)
+	}
+}
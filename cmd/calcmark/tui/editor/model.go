@@ -2,17 +2,22 @@ package editor
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/components"
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/shared"
+	"github.com/CalcMark/go-calcmark/format"
 	"github.com/CalcMark/go-calcmark/format/display"
 	implDoc "github.com/CalcMark/go-calcmark/impl/document"
 	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -24,15 +29,55 @@ type evalDebounceMsg struct {
 	editBufSnapshot string // Snapshot of editBuf when timer was started
 }
 
+// Idle delay before the optional full-precision verification pass.
+const verifyIdleDelay = 2 * time.Second
+
+// precisionVerifyMsg is sent after the idle delay to trigger precision verification.
+type precisionVerifyMsg struct {
+	content string // Document content when timer was started
+}
+
+// precisionVerifiedMsg carries the result of a precision verification.
+type precisionVerifiedMsg struct {
+	content    string // Document content that was verified
+	mismatches []implDoc.PrecisionMismatch
+}
+
+// Evaluation budget per block while editing. A slower block shows its
+// previous value marked stale, and is retried with the longer budget off the
+// render loop once the document has been idle for staleRetryDelay.
+const (
+	evalBlockTimeout  = 250 * time.Millisecond
+	staleRetryDelay   = 1 * time.Second
+	staleRetryTimeout = 10 * time.Second
+)
+
+// staleRetryMsg is sent after the idle delay to retry stale blocks.
+type staleRetryMsg struct {
+	content string // Document content when timer was started
+}
+
+// staleRetryDoneMsg carries the result of retrying stale blocks.
+type staleRetryDoneMsg struct {
+	content string             // Document content that was retried
+	doc     *document.Document // Scratch copy evaluated with the longer budget
+	eval    *implDoc.Evaluator
+}
+
 // EditorMode represents the current editor mode.
 type EditorMode int
 
 const (
-	ModeNormal  EditorMode = iota // Normal navigation mode
-	ModeEditing                   // Line editing mode
-	ModeCommand                   // Command palette mode
-	ModeGlobals                   // Globals panel focused
-	ModeHelp                      // Help viewer
+	ModeNormal    EditorMode = iota // Normal navigation mode
+	ModeEditing                     // Line editing mode
+	ModeCommand                     // Command palette mode
+	ModeGlobals                     // Globals panel focused
+	ModeHelp                        // Help viewer
+	ModeQuickCalc                   // Quick calculator overlay (Ctrl+K)
+	ModeBlockEdit                   // Whole-block editing in a textarea (E)
+	ModeVisual                      // Selecting lines for a bulk edit (V)
+	ModeOutline                     // Variable outline panel focused (gO)
+	ModeGraph                       // Dependency graph view (/graph)
 )
 
 // PreviewMode represents the preview pane display mode.
@@ -75,9 +120,9 @@ type Model struct {
 	modified bool
 
 	// Cursor and navigation
-	cursorLine   int // Current line (0-indexed)
-	cursorCol    int // Current column (0-indexed)
-	scrollOffset int // Vertical scroll offset
+	cursorLine int // Current line (0-indexed)
+	cursorCol  int // Current column: byte offset into the line, on a grapheme boundary
+	cursorRow  int // Cursor's screen row in visual lines, the scroll position (see scroll.go)
 
 	// Editor state
 	mode            EditorMode
@@ -85,14 +130,25 @@ type Model struct {
 	lineWrap        bool            // Whether to wrap long lines
 	changedBlockIDs map[string]bool // Track changed blocks for highlighting
 
-	// Undo/redo
-	undoStack []string // Document content snapshots
-	redoStack []string
+	// Undo/redo (see undo.go)
+	history undoHistory
+	typing  bool // Handling an edit-mode key, whose changes group as one undo step
 
 	// Command palette
 	cmdInput   string
 	cmdHistory []string
 
+	// Quick calculator
+	quickInput      string
+	quickResult     string
+	quickIsErr      bool
+	quickReturnMode EditorMode // Mode to return to when the calculator closes
+
+	// Block editor
+	blockEditor    textarea.Model
+	blockEditID    string // Block being edited
+	blockEditStart int    // Source line index of the block's first line
+
 	// Globals panel
 	globalsExpanded bool
 	globalsFocusIdx int
@@ -108,7 +164,28 @@ type Model struct {
 	quitting    bool
 	previewMode PreviewMode // Preview pane mode: Full, Minimal, Hidden
 	pendingKey  rune        // For two-key sequences like gg, dd, yy
-	yankBuffer  string      // Yanked line content for paste
+	yankBuffer  string      // Yanked lines for paste, separated by "\n"
+
+	// Visual line mode (see visual.go)
+	visualAnchor int // Source line the selection started on
+
+	// Mouse drag selection (see mouse.go)
+	dragging   bool // The left button is held after a press in the source pane
+	dragAnchor int  // Source line the drag started on
+
+	// Diagnostics panel (see diagnostics.go)
+	diagnosticsOpen bool
+
+	// Variable outline (see outline.go)
+	outlineIdx int    // Selected variable, or reference when outlineVar is set
+	outlineVar string // Variable whose references are listed, "" for the variables
+
+	// Dependency graph view (see graph.go)
+	graphScroll int // First row of the graph shown
+
+	// Split view (see split.go); nil unless split
+	split    *splitView
+	inactive bool // This is the pane of a split without focus
 
 	// Search state
 	searchTerm    string // Current search term
@@ -119,6 +196,18 @@ type Model struct {
 	statusMsg   string
 	statusIsErr bool
 
+	// Results shown beside the document's, set by /compare; nil for none
+	compare *comparison
+
+	// Idle-time full-precision verification (tui.verify_precision)
+	verifyPrecision bool
+
+	// Columns between tab stops when showing tabs (files.tab_width)
+	tabWidth int
+
+	// Content whose stale blocks were last retried, so each version is retried once
+	staleRetried string
+
 	// Styles
 	styles config.Styles
 
@@ -126,6 +215,12 @@ type Model struct {
 	alignedCache       *AlignedModel
 	alignedCacheKey    alignedCacheKey // Key for cache validation
 	alignedCacheWidths [2]int          // [sourceWidth, previewWidth] used for cache
+
+	// Per-line renderings reused across View() calls; shared by value copies
+	renderCache *renderCache
+
+	// Session trace (see trace.go); nil unless recording, shared by value copies
+	trace *traceRecorder
 }
 
 // New creates a new editor model with an optional document.
@@ -134,7 +229,7 @@ func New(doc *document.Document) Model {
 		doc, _ = document.NewDocument("")
 	}
 
-	eval := implDoc.NewEvaluator()
+	eval := newEvaluator("")
 	_ = eval.Evaluate(doc)
 
 	m := Model{
@@ -144,21 +239,22 @@ func New(doc *document.Document) Model {
 		pinnedVars:      make(map[string]bool),
 		changedVars:     make(map[string]bool),
 		changedBlockIDs: make(map[string]bool),
-		undoStack:       []string{},
-		redoStack:       []string{},
 		cmdHistory:      []string{},
 		width:           80,
 		height:          24,
 		previewMode:     PreviewFull,
 		lineWrap:        true,
 		styles:          config.GetStyles(),
+		verifyPrecision: config.Get().TUI.VerifyPrecision,
+		tabWidth:        config.Get().Files.Tabs(),
+		renderCache:     newRenderCache(),
 	}
 
 	// Auto-pin all variables
 	m.autoPinVariables()
 
-	// Save initial state for undo
-	m.pushUndoState()
+	// Undo history starts from the document as opened
+	m.history = newUndoHistory(m.GetLines())
 
 	return m
 }
@@ -167,6 +263,10 @@ func New(doc *document.Document) Model {
 func NewWithFile(filepath string, doc *document.Document) Model {
 	m := New(doc)
 	m.filepath = filepath
+
+	// Re-evaluate so imports resolve relative to the file
+	m.eval = newEvaluator(filepath)
+	_ = m.eval.Evaluate(doc)
 	return m
 }
 
@@ -181,20 +281,6 @@ func (m *Model) autoPinVariables() {
 	}
 }
 
-// pushUndoState saves current document state for undo.
-func (m *Model) pushUndoState() {
-	content := m.getDocumentContent()
-	if len(m.undoStack) == 0 || m.undoStack[len(m.undoStack)-1] != content {
-		m.undoStack = append(m.undoStack, content)
-		// Limit undo stack size
-		if len(m.undoStack) > 100 {
-			m.undoStack = m.undoStack[1:]
-		}
-		// Clear redo stack on new change
-		m.redoStack = nil
-	}
-}
-
 // getDocumentContent returns the document as a string.
 func (m *Model) getDocumentContent() string {
 	var lines []string
@@ -246,20 +332,73 @@ func (m Model) Init() tea.Cmd {
 
 // Update implements tea.Model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if mm, ok := model.(Model); ok && mm.trace != nil {
+		mm.recordTrace()
+		return mm, cmd
+	}
+	return model, cmd
+}
+
+// update handles a message for Update.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		model, cmd := m.handleKey(msg)
+		if mm, ok := model.(Model); ok {
+			return mm, tea.Batch(cmd, mm.scheduleStaleRetry())
+		}
+		return model, cmd
+
+	case tea.MouseMsg:
+		if m.split != nil {
+			return m.handleSplitMouse(msg), nil
+		}
+		return m.handleMouse(msg), nil
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.split != nil {
+			m.layoutSplit(msg.Width, msg.Height)
+		}
 		m.InvalidateAlignedCache()
+		if m.mode == ModeBlockEdit {
+			m.sizeBlockEditor()
+		}
 
 	case evalDebounceMsg:
 		// Only evaluate if editBuf hasn't changed since the timer was started
 		// This ensures we don't evaluate stale content
 		if m.mode == ModeEditing && m.editBuf == msg.editBufSnapshot {
 			m.liveUpdateCurrentLine()
+			m.warnSizeLimits()
+			return m, tea.Batch(m.scheduleVerifyPrecision(), m.scheduleStaleRetry())
+		}
+
+	case precisionVerifyMsg:
+		// Only verify once the document has been idle since the timer was started
+		if msg.content == m.getDocumentContent() {
+			return m, m.checkPrecision(msg.content)
+		}
+
+	case precisionVerifiedMsg:
+		// Results are only good for the content they were computed from
+		if msg.content == m.getDocumentContent() {
+			m.warnPrecision(msg.mismatches)
+		}
+
+	case staleRetryMsg:
+		// Retry once the document has been idle, unless this version was tried
+		if msg.content == m.getDocumentContent() && msg.content != m.staleRetried {
+			m.staleRetried = msg.content
+			return m, m.retryStaleBlocks(msg.content)
+		}
+
+	case staleRetryDoneMsg:
+		// Results are only good for the content they were computed from
+		if msg.content == m.getDocumentContent() {
+			m.applyRetriedBlocks(msg.doc, msg.eval)
 		}
 	}
 
@@ -284,16 +423,32 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Save (Ctrl+S works in all modes)
 		m.saveFile("")
 		return m, nil
+	case tea.KeyCtrlK:
+		// Quick calculator (Ctrl+K opens it from any mode)
+		if m.mode != ModeQuickCalc {
+			m.openQuickCalc()
+			return m, nil
+		}
 	}
 
 	// Mode-specific handling
 	switch m.mode {
+	case ModeQuickCalc:
+		return m.handleQuickCalcKey(msg)
 	case ModeEditing:
 		return m.handleEditKey(msg)
+	case ModeBlockEdit:
+		return m.handleBlockEditKey(msg)
 	case ModeCommand:
 		return m.handleCommandKey(msg)
 	case ModeGlobals:
 		return m.handleGlobalsKey(msg)
+	case ModeVisual:
+		return m.handleVisualKey(msg)
+	case ModeOutline:
+		return m.handleOutlineKey(msg)
+	case ModeGraph:
+		return m.handleGraphKey(msg)
 	default:
 		return m.handleNormalKey(msg)
 	}
@@ -311,17 +466,19 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyRight:
 		m.moveCursor(0, 1)
 	case tea.KeyPgUp:
-		m.moveCursor(-(m.height - 4), 0)
+		m.movePage(-m.visibleHeight())
 	case tea.KeyPgDown:
-		m.moveCursor(m.height-4, 0)
+		m.movePage(m.visibleHeight())
 	case tea.KeyHome:
 		m.cursorLine = 0
 		m.cursorCol = 0
-		m.scrollOffset = 0
+		m.cursorRow = 0
 	case tea.KeyEnd:
 		total := m.TotalLines()
 		if total > 0 {
+			prev := m.cursorLine
 			m.cursorLine = total - 1
+			m.followCursor(prev)
 		}
 	case tea.KeyEnter:
 		m.enterEditMode()
@@ -332,13 +489,16 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cyclePreviewMode()
 	case tea.KeyCtrlD:
 		// Half-page down
-		m.moveCursor(m.height/2, 0)
+		m.movePage(m.visibleHeight() / 2)
 	case tea.KeyCtrlU:
 		// Half-page up
-		m.moveCursor(-m.height/2, 0)
+		m.movePage(-m.visibleHeight() / 2)
 	case tea.KeyDelete:
 		// Delete current line (same as dd)
 		m.deleteLine()
+	case tea.KeyCtrlW:
+		// Switch split panes
+		return m.switchSplit(), nil
 	case tea.KeyRunes:
 		return m.handleNormalRune(msg.Runes)
 	}
@@ -365,7 +525,12 @@ func (m Model) handleNormalRune(runes []rune) (tea.Model, tea.Cmd) {
 				// gg: go to top
 				m.cursorLine = 0
 				m.cursorCol = 0
-				m.scrollOffset = 0
+				m.cursorRow = 0
+				return m, nil
+			}
+			if key == 'O' {
+				// gO: variable outline
+				m.openOutline()
 				return m, nil
 			}
 			// g followed by anything else: enter globals mode then process key
@@ -378,12 +543,34 @@ func (m Model) handleNormalRune(runes []rune) (tea.Model, tea.Cmd) {
 				m.deleteLine()
 				return m, nil
 			}
+			if key == 'B' {
+				// dB: move the block to the trash
+				m.deleteBlock()
+				return m, nil
+			}
 		case 'y':
 			if key == 'y' {
 				// yy: yank (copy) current line
 				m.yankLine()
 				return m, nil
 			}
+			if key == 'B' {
+				// yB: yank the block under the cursor
+				m.yankBlock()
+				return m, nil
+			}
+		case ']':
+			if key == 'd' {
+				// ]d: next problem
+				m.jumpToDiagnostic(1)
+				return m, nil
+			}
+		case '[':
+			if key == 'd' {
+				// [d: previous problem
+				m.jumpToDiagnostic(-1)
+				return m, nil
+			}
 		}
 		// Invalid sequence, ignore
 		return m, nil
@@ -400,6 +587,9 @@ func (m Model) handleNormalRune(runes []rune) (tea.Model, tea.Cmd) {
 	case 'y':
 		m.pendingKey = 'y'
 		return m, nil
+	case ']', '[':
+		m.pendingKey = key
+		return m, nil
 	}
 
 	// Single key commands
@@ -415,15 +605,27 @@ func (m Model) handleNormalRune(runes []rune) (tea.Model, tea.Cmd) {
 	case 'G': // Go to bottom
 		total := m.TotalLines()
 		if total > 0 {
+			prev := m.cursorLine
 			m.cursorLine = total - 1
+			m.followCursor(prev)
 		}
 	case 'e', 'i': // Enter edit mode
 		m.enterEditMode()
+	case 'E': // Edit the whole block
+		m.openBlockEdit()
+	case 'V': // Select lines
+		m.enterVisualMode()
+	case 'D': // Show or hide the diagnostics panel
+		m.toggleDiagnostics()
 	case 'o': // Insert line below and enter edit mode
+		m.typing = true // The new line undoes with what's typed on it
 		m.insertLineBelow()
+		m.typing = false
 		m.enterEditMode()
 	case 'O': // Insert line above and enter edit mode
+		m.typing = true
 		m.insertLineAbove()
+		m.typing = false
 		m.enterEditMode()
 	case 'u': // Undo
 		m.undo()
@@ -456,10 +658,12 @@ func (m Model) handleNormalRune(runes []rune) (tea.Model, tea.Cmd) {
 // handleEditKey processes keys in edit mode.
 func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	contentChanged := false
+	m.typing = true // Changes join the open undo step
 
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.exitEditMode(true) // Save changes
+		m.history.seal()     // Typing ends with edit mode
 	case tea.KeyEnter:
 		// Split line at cursor position (like a normal text editor)
 		// Text before cursor stays on current line, text after goes to new line
@@ -478,8 +682,9 @@ func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		contentChanged = true
 	case tea.KeyBackspace:
 		if m.cursorCol > 0 && len(m.editBuf) > 0 {
-			m.editBuf = m.editBuf[:m.cursorCol-1] + m.editBuf[m.cursorCol:]
-			m.cursorCol--
+			prev := prevGrapheme(m.editBuf, m.cursorCol)
+			m.editBuf = m.editBuf[:prev] + m.editBuf[m.cursorCol:]
+			m.cursorCol = prev
 			contentChanged = true
 		} else if len(m.editBuf) == 0 && m.cursorLine > 0 {
 			// Empty line with a previous line - delete this line and move to end of previous
@@ -488,13 +693,14 @@ func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.deleteLine()
 			// Move to previous line and enter edit mode at end
 			m.cursorLine = prevLine
+			m.followCursor(prevLine + 1)
 			m.enterEditMode()
 			m.cursorCol = len(m.editBuf)
 		}
 	case tea.KeyDelete:
 		if m.cursorCol < len(m.editBuf) {
 			// Delete character forward
-			m.editBuf = m.editBuf[:m.cursorCol] + m.editBuf[m.cursorCol+1:]
+			m.editBuf = m.editBuf[:m.cursorCol] + m.editBuf[nextGrapheme(m.editBuf, m.cursorCol):]
 			contentChanged = true
 		} else if len(m.editBuf) == 0 {
 			// Empty line - delete it and move to next line (or stay if last line)
@@ -522,13 +728,9 @@ func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.saveCurrentLineAndMoveTo(m.cursorLine + 1)
 		}
 	case tea.KeyLeft:
-		if m.cursorCol > 0 {
-			m.cursorCol--
-		}
+		m.cursorCol = prevGrapheme(m.editBuf, m.cursorCol)
 	case tea.KeyRight:
-		if m.cursorCol < len(m.editBuf) {
-			m.cursorCol++
-		}
+		m.cursorCol = nextGrapheme(m.editBuf, m.cursorCol)
 	case tea.KeyHome:
 		m.cursorCol = 0
 	case tea.KeyEnd:
@@ -539,14 +741,15 @@ func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cursorCol++
 		contentChanged = true
 	case tea.KeyRunes:
-		// Insert character at cursor
-		for _, r := range msg.Runes {
-			m.editBuf = m.editBuf[:m.cursorCol] + string(r) + m.editBuf[m.cursorCol:]
-			m.cursorCol++
-		}
+		// Insert characters at cursor
+		text := string(msg.Runes)
+		m.editBuf = m.editBuf[:m.cursorCol] + text + m.editBuf[m.cursorCol:]
+		m.cursorCol += len(text)
 		contentChanged = true
 	}
 
+	m.typing = false
+
 	// Schedule debounced re-evaluation on content changes
 	// This prevents re-evaluating on every keystroke (per spec: ~50ms debounce)
 	if contentChanged {
@@ -559,6 +762,185 @@ func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// newEvaluator creates a document evaluator with the configured lint rules.
+// Imports load .cm files under the working directory, relative to path.
+func newEvaluator(path string) *implDoc.Evaluator {
+	lint := config.Get().Lint
+	limits := config.Get().Limits
+	eval := implDoc.NewEvaluator()
+	if cwd, err := os.Getwd(); err == nil {
+		if resolver, err := implDoc.NewFileResolver(cwd); err == nil {
+			if path != "" {
+				path, _ = filepath.Abs(path)
+			}
+			eval.SetResolver(resolver, path)
+		}
+	}
+	eval.SetBlockTimeout(evalBlockTimeout)
+	eval.SetLimits(implDoc.Limits{
+		MaxVariables: limits.MaxVariables,
+		MaxResults:   limits.MaxResults,
+	})
+	eval.SetComplexityLimits(semantic.ComplexityLimits{
+		MaxDepth:    lint.MaxExpressionDepth,
+		MaxOperands: lint.MaxExpressionOperands,
+	})
+	eval.SetNamingRules(semantic.NamingRules{
+		SnakeCase:           lint.Naming.SnakeCase,
+		NoSingleLetter:      lint.Naming.NoSingleLetter,
+		AllowedSingleLetter: lint.Naming.AllowedSingleLetter,
+		CurrencySuffix:      lint.Naming.CurrencySuffix,
+	})
+	return eval
+}
+
+// scheduleVerifyPrecision starts the idle timer for the precision check.
+// Returns nil when verification is disabled.
+func (m *Model) scheduleVerifyPrecision() tea.Cmd {
+	if !m.verifyPrecision {
+		return nil
+	}
+	content := m.getDocumentContent()
+	return tea.Tick(verifyIdleDelay, func(t time.Time) tea.Msg {
+		return precisionVerifyMsg{content: content}
+	})
+}
+
+// scheduleStaleRetry starts the idle timer for retrying stale blocks.
+// Returns nil when no block is stale or this content was already retried.
+func (m *Model) scheduleStaleRetry() tea.Cmd {
+	if !m.hasStaleBlocks() {
+		return nil
+	}
+	content := m.getDocumentContent()
+	if content == m.staleRetried {
+		return nil
+	}
+	return tea.Tick(staleRetryDelay, func(t time.Time) tea.Msg {
+		return staleRetryMsg{content: content}
+	})
+}
+
+// hasStaleBlocks reports whether any block timed out in the last evaluation.
+func (m *Model) hasStaleBlocks() bool {
+	for _, node := range m.doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok && cb.IsStale() {
+			return true
+		}
+	}
+	return false
+}
+
+// retryStaleBlocks evaluates a scratch copy of the document with the longer
+// block budget in the background, leaving the editor responsive meanwhile.
+func (m *Model) retryStaleBlocks(content string) tea.Cmd {
+	source := m.doc.GetFrontmatter().Serialize() + content
+	path := m.filepath
+	return func() tea.Msg {
+		doc, err := document.NewDocument(source)
+		if err != nil {
+			return nil
+		}
+		eval := newEvaluator(path)
+		eval.SetBlockTimeout(staleRetryTimeout)
+		_ = eval.Evaluate(doc)
+		return staleRetryDoneMsg{content: content, doc: doc, eval: eval}
+	}
+}
+
+// applyRetriedBlocks copies results from a retried scratch document onto
+// the blocks with the same source, so blocks that used a stale value are
+// brought up to date too, and adopts the retry's environment. Nothing is
+// applied if a block is still stale after the longer budget.
+func (m *Model) applyRetriedBlocks(retried *document.Document, eval *implDoc.Evaluator) {
+	var scratch []*document.CalcBlock
+	for _, node := range retried.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok {
+			if cb.IsStale() {
+				return
+			}
+			scratch = append(scratch, cb)
+		}
+	}
+
+	next := 0
+	for _, node := range m.doc.GetBlocks() {
+		cb, ok := node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		for i := next; i < len(scratch); i++ {
+			fresh := scratch[i]
+			if !slices.Equal(fresh.Source(), cb.Source()) {
+				continue
+			}
+			// Blocks after a failing one are never evaluated
+			if !fresh.IsDirty() || fresh.Error() != nil {
+				cb.SetStatements(fresh.Statements())
+				cb.SetResults(fresh.Results())
+				cb.SetLastValue(fresh.LastValue())
+				cb.SetError(fresh.Error())
+				cb.SetDiagnostics(fresh.Diagnostics())
+				cb.SetStale(false)
+			}
+			next = i + 1
+			break
+		}
+	}
+
+	eval.SetBlockTimeout(evalBlockTimeout)
+	m.eval = eval
+	m.InvalidateAlignedCache()
+}
+
+// checkPrecision evaluates a scratch copy of the document, then checks
+// it at full precision, in the background so the editor stays responsive.
+func (m *Model) checkPrecision(content string) tea.Cmd {
+	source := m.doc.GetFrontmatter().Serialize() + content
+	path := m.filepath
+	return func() tea.Msg {
+		doc, err := document.NewDocument(source)
+		if err != nil {
+			return nil
+		}
+		eval := newEvaluator(path)
+		_ = eval.Evaluate(doc) // Failed blocks have no results to check
+		mismatches, err := eval.VerifyPrecision(doc, display.Format)
+		if err != nil {
+			return nil
+		}
+		return precisionVerifiedMsg{content: content, mismatches: mismatches}
+	}
+}
+
+// warnPrecision warns in the status line if any displayed result differs
+// from its full-precision value.
+func (m *Model) warnPrecision(mismatches []implDoc.PrecisionMismatch) {
+	if len(mismatches) == 0 {
+		return
+	}
+
+	first := mismatches[0]
+	name := first.Name
+	if name == "" {
+		name = "result"
+	}
+	m.statusMsg = fmt.Sprintf("Precision check: %s shows %s, full precision gives %s", name, first.Fast, first.Precise)
+	if len(mismatches) > 1 {
+		m.statusMsg += fmt.Sprintf(" (+%d more)", len(mismatches)-1)
+	}
+	m.statusIsErr = true
+}
+
+// warnSizeLimits warns in the status line if the document has grown past
+// the configured size limits ([limits] in config).
+func (m *Model) warnSizeLimits() {
+	if diags := m.eval.CheckLimits(m.doc); len(diags) > 0 {
+		m.statusMsg = "Document size: " + diags[0].Message
+		m.statusIsErr = true
+	}
+}
+
 // liveUpdateCurrentLine updates the current line and re-evaluates for live preview.
 func (m *Model) liveUpdateCurrentLine() {
 	// Update the line in the document
@@ -574,8 +956,8 @@ func (m Model) handleCommandKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ModeNormal
 		m.cmdInput = ""
 	case tea.KeyEnter:
+		m.mode = ModeNormal // Unless the command opens another
 		m.executeCommand(m.cmdInput)
-		m.mode = ModeNormal
 		m.cmdInput = ""
 		// Check if command requested quit
 		if m.quitting {
@@ -618,11 +1000,17 @@ func (m Model) handleGlobalsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ModeNormal
 	}
 
-	// Handle 'j' for down
-	if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 && msg.Runes[0] == 'j' {
-		globalsCount := m.getGlobalsCount()
-		if m.globalsFocusIdx < globalsCount-1 {
-			m.globalsFocusIdx++
+	if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 {
+		switch msg.Runes[0] {
+		case 'j':
+			globalsCount := m.getGlobalsCount()
+			if m.globalsFocusIdx < globalsCount-1 {
+				m.globalsFocusIdx++
+			}
+		case '+', '=':
+			m.adjustFocusedGlobal(1)
+		case '-':
+			m.adjustFocusedGlobal(-1)
 		}
 	}
 
@@ -649,6 +1037,7 @@ func (m *Model) moveCursor(dLine, dCol int) {
 	}
 
 	// Move line
+	prev := m.cursorLine
 	m.cursorLine += dLine
 	if m.cursorLine < 0 {
 		m.cursorLine = 0
@@ -660,24 +1049,17 @@ func (m *Model) moveCursor(dLine, dCol int) {
 	// Move column
 	lines := m.GetLines()
 	if m.cursorLine < len(lines) {
-		lineLen := len(lines[m.cursorLine])
-		m.cursorCol += dCol
-		if m.cursorCol < 0 {
-			m.cursorCol = 0
+		line := lines[m.cursorLine]
+		m.cursorCol = clampGrapheme(line, m.cursorCol)
+		for ; dCol < 0; dCol++ {
+			m.cursorCol = prevGrapheme(line, m.cursorCol)
 		}
-		if m.cursorCol > lineLen {
-			m.cursorCol = lineLen
+		for ; dCol > 0; dCol-- {
+			m.cursorCol = nextGrapheme(line, m.cursorCol)
 		}
 	}
 
-	// Adjust scroll
-	visibleHeight := m.height - 6 // Account for status bar etc
-	if m.cursorLine < m.scrollOffset {
-		m.scrollOffset = m.cursorLine
-	}
-	if m.cursorLine >= m.scrollOffset+visibleHeight {
-		m.scrollOffset = m.cursorLine - visibleHeight + 1
-	}
+	m.followCursor(prev)
 }
 
 // enterEditMode enters line editing mode.
@@ -694,7 +1076,7 @@ func (m *Model) enterEditMode() {
 		newDoc, err := document.NewDocument("_")
 		if err == nil {
 			m.doc = newDoc
-			m.eval = implDoc.NewEvaluator()
+			m.eval = newEvaluator(m.filepath)
 			_ = m.eval.Evaluate(m.doc)
 			m.pushUndoState()
 			lines = m.GetLines()
@@ -746,11 +1128,15 @@ func (m *Model) saveCurrentLineAndMoveTo(newLine int) {
 	m.updateCurrentLine(m.editBuf)
 	m.modified = true
 
-	// Remember cursor column to try to preserve it
-	savedCol := m.cursorCol
+	// Remember the cursor's display column to try to preserve it, so the
+	// cursor stays visually above or below where it was on lines with wide
+	// characters
+	savedCol := graphemeColumn(m.editBuf, m.cursorCol, m.tabWidth)
 
 	// Move to new line
+	prev := m.cursorLine
 	m.cursorLine = newLine
+	m.followCursor(prev)
 
 	// Load new line into edit buffer
 	lines := m.GetLines()
@@ -761,11 +1147,7 @@ func (m *Model) saveCurrentLineAndMoveTo(newLine int) {
 	}
 
 	// Try to preserve column position, clamp to line length
-	if savedCol > len(m.editBuf) {
-		m.cursorCol = len(m.editBuf)
-	} else {
-		m.cursorCol = savedCol
-	}
+	m.cursorCol = graphemeOffset(m.editBuf, savedCol, m.tabWidth)
 
 	// Stay in edit mode (don't change m.mode)
 }
@@ -776,9 +1158,8 @@ func (m *Model) redetectBlockTypes() {
 	// Get current document content
 	content := m.getDocumentContent()
 
-	// Rebuild document with proper block detection
-	newDoc, err := document.NewDocument(content)
-	if err != nil {
+	// Re-detect blocks, keeping unchanged ones
+	if err := m.reparse(content); err != nil {
 		// If parsing fails, keep the old document
 		return
 	}
@@ -787,13 +1168,6 @@ func (m *Model) redetectBlockTypes() {
 	cursorLine := m.cursorLine
 	cursorCol := m.cursorCol
 
-	// Replace document
-	m.doc = newDoc
-
-	// Re-evaluate the new document
-	m.eval = implDoc.NewEvaluator()
-	_ = m.eval.Evaluate(m.doc)
-
 	// Restore cursor (clamped to valid range)
 	total := m.TotalLines()
 	if cursorLine >= total {
@@ -811,35 +1185,14 @@ func (m *Model) redetectBlockTypes() {
 
 // updateCurrentLine updates the line at cursorLine with new content.
 func (m *Model) updateCurrentLine(newContent string) {
-	lineIdx := 0
-	for _, node := range m.doc.GetBlocks() {
-		var blockLines []string
-		switch b := node.Block.(type) {
-		case *document.CalcBlock:
-			blockLines = b.Source()
-		case *document.TextBlock:
-			blockLines = b.Source()
-		}
-
-		for i := range blockLines {
-			if lineIdx == m.cursorLine {
-				// This is the line to update
-				blockLines[i] = newContent
-
-				// Replace block source
-				result, err := m.doc.ReplaceBlockSource(node.ID, blockLines)
-				if err != nil {
-					return
-				}
+	result, err := m.doc.ReplaceLine(m.cursorLine, newContent)
+	if err != nil {
+		return
+	}
 
-				// Track affected blocks
-				for _, id := range result.AffectedBlockIDs {
-					m.changedBlockIDs[id] = true
-				}
-				return
-			}
-			lineIdx++
-		}
+	// Track affected blocks
+	for _, id := range result.AffectedBlockIDs {
+		m.changedBlockIDs[id] = true
 	}
 }
 
@@ -854,8 +1207,13 @@ func (m *Model) insertLineAbove() {
 }
 
 // insertLine inserts a new empty line at the given position.
-// This rebuilds the document with the new line inserted at the correct position.
 func (m *Model) insertLine(at int) {
+	m.insertLineWith(at, "")
+}
+
+// insertLineWith inserts a line with the given content at the given position.
+// This rebuilds the document with the new line inserted at the correct position.
+func (m *Model) insertLineWith(at int, content string) {
 	lines := m.GetLines()
 
 	// Clamp position
@@ -866,41 +1224,24 @@ func (m *Model) insertLine(at int) {
 		at = len(lines)
 	}
 
-	// Insert empty line at position
+	// Insert the line at position
 	newLines := make([]string, 0, len(lines)+1)
 	newLines = append(newLines, lines[:at]...)
-	newLines = append(newLines, "")
+	newLines = append(newLines, content)
 	newLines = append(newLines, lines[at:]...)
 
-	// Rebuild document with new content
-	content := strings.Join(newLines, "\n")
-	newDoc, err := document.NewDocument(content)
-	if err != nil {
+	// Update document with new content
+	if err := m.reparse(strings.Join(newLines, "\n")); err != nil {
 		return
 	}
 
-	// Replace document
-	m.doc = newDoc
-	m.eval = implDoc.NewEvaluator()
-	_ = m.eval.Evaluate(m.doc)
-
 	// Set cursor to new line
+	prev := m.cursorLine
 	m.cursorLine = at
 	m.cursorCol = 0
 	m.modified = true
 	m.pushUndoState()
-
-	// Adjust scroll to keep cursor visible
-	visibleHeight := m.height - 6 // Account for status bar etc
-	if visibleHeight < 1 {
-		visibleHeight = 1
-	}
-	if m.cursorLine < m.scrollOffset {
-		m.scrollOffset = m.cursorLine
-	}
-	if m.cursorLine >= m.scrollOffset+visibleHeight {
-		m.scrollOffset = m.cursorLine - visibleHeight + 1
-	}
+	m.followCursor(prev)
 
 	// Auto-pin any new variables
 	m.autoPinVariables()
@@ -945,51 +1286,18 @@ func (m *Model) reEvaluate() {
 	// so the view can show which blocks were affected by the last change
 }
 
-// undo reverts to the previous state.
-func (m *Model) undo() {
-	if len(m.undoStack) <= 1 {
-		return
+// reparse replaces the document's content (without frontmatter, as from
+// getDocumentContent), keeping the IDs and results of unchanged blocks,
+// and re-evaluates the document.
+func (m *Model) reparse(content string) error {
+	if _, err := m.doc.Reparse(m.doc.GetFrontmatter().Serialize() + content); err != nil {
+		return err
 	}
-
-	// Save current state to redo
-	current := m.undoStack[len(m.undoStack)-1]
-	m.redoStack = append(m.redoStack, current)
-
-	// Pop and restore previous state
-	m.undoStack = m.undoStack[:len(m.undoStack)-1]
-	prev := m.undoStack[len(m.undoStack)-1]
-
-	// Restore document
-	doc, err := document.NewDocument(prev)
-	if err != nil {
-		return
-	}
-	m.doc = doc
-	m.eval = implDoc.NewEvaluator()
-	_ = m.eval.Evaluate(m.doc)
-	m.modified = true
-}
-
-// redo re-applies an undone change.
-func (m *Model) redo() {
-	if len(m.redoStack) == 0 {
-		return
-	}
-
-	// Pop from redo and apply
-	content := m.redoStack[len(m.redoStack)-1]
-	m.redoStack = m.redoStack[:len(m.redoStack)-1]
-
-	doc, err := document.NewDocument(content)
-	if err != nil {
-		return
-	}
-	m.doc = doc
-	m.eval = implDoc.NewEvaluator()
+	// Removed or reordered definitions can change any value, so evaluate
+	// everything rather than just the affected blocks
+	m.eval = newEvaluator(m.filepath)
 	_ = m.eval.Evaluate(m.doc)
-
-	m.undoStack = append(m.undoStack, content)
-	m.modified = true
+	return nil
 }
 
 // executeCommand executes a slash command.
@@ -1058,8 +1366,20 @@ func (m *Model) executeCommand(cmd string) {
 			m.statusMsg = "Usage: /goto <line>"
 			m.statusIsErr = true
 		}
+	case "compare":
+		m.compareWith(strings.Join(parts[1:], " "))
+	case "split":
+		m.splitFile(strings.Join(parts[1:], " "))
+	case "outline":
+		m.openOutline()
+	case "graph":
+		m.openGraph()
+	case "trash":
+		m.showTrash()
+	case "restore":
+		m.restoreFromTrash(strings.Join(parts[1:], ""))
 	case "help", "h", "?":
-		m.statusMsg = "e=edit j/k=nav n/N=search /save /open /quit /preview /find /goto"
+		m.statusMsg = "e=edit E=block dB=trash block j/k=nav n/N=search ]d/[d=problems D=problem list gO=outline ^K=calc /save /open /quit /preview /find /goto /compare /split /outline /graph /trash /restore"
 	default:
 		m.statusMsg = fmt.Sprintf("Unknown command: %s", parts[0])
 		m.statusIsErr = true
@@ -1091,8 +1411,12 @@ func (m *Model) saveFile(filename string) {
 		return
 	}
 
-	// Get document content
-	content := m.getDocumentContent()
+	// Get document content, frontmatter included; unedited files are
+	// written back byte for byte unless line endings are normalized
+	content := m.doc.Serialize()
+	if config.Get().Files.Normalize() {
+		content = document.NormalizeText(content)
+	}
 
 	// Write file
 	err = os.WriteFile(absPath, []byte(content), 0644)
@@ -1135,7 +1459,7 @@ func (m *Model) openFile(filename string) {
 	}
 
 	// Evaluate
-	eval := implDoc.NewEvaluator()
+	eval := newEvaluator(absPath)
 	if err := eval.Evaluate(doc); err != nil {
 		// Non-fatal - document loaded but has evaluation errors
 		m.statusMsg = fmt.Sprintf("Opened with errors: %v", err)
@@ -1151,12 +1475,10 @@ func (m *Model) openFile(filename string) {
 	m.modified = false
 	m.cursorLine = 0
 	m.cursorCol = 0
-	m.scrollOffset = 0
+	m.cursorRow = 0
 
-	// Reset undo stack
-	m.undoStack = []string{}
-	m.redoStack = []string{}
-	m.pushUndoState()
+	// Reset undo history
+	m.history = newUndoHistory(m.GetLines())
 
 	// Auto-pin variables
 	m.pinnedVars = make(map[string]bool)
@@ -1173,6 +1495,32 @@ func (m *Model) getGlobalsCount() int {
 	return len(fm.Globals) + len(fm.Exchange)
 }
 
+// adjustFocusedGlobal nudges the focused global through its widget (+/- in
+// the globals panel) and re-evaluates, since any block may read it.
+func (m *Model) adjustFocusedGlobal(steps int) {
+	state := m.GetGlobalsPanelState()
+	if m.globalsFocusIdx < 0 || m.globalsFocusIdx >= len(state.Globals) {
+		return
+	}
+	g := state.Globals[m.globalsFocusIdx]
+	if g.Widget == "" {
+		m.statusMsg = fmt.Sprintf("%s has no widget", g.Name)
+		m.statusIsErr = true
+		return
+	}
+
+	value, err := m.doc.AdjustWidget(g.Name, steps)
+	if err != nil {
+		m.statusMsg = err.Error()
+		m.statusIsErr = true
+		return
+	}
+	_ = m.eval.Evaluate(m.doc)
+	m.modified = true
+	m.statusMsg = fmt.Sprintf("%s = %s", g.Name, value)
+	m.statusIsErr = false
+}
+
 // GetStatusBarState returns state for the status bar.
 func (m *Model) GetStatusBarState() components.StatusBarState {
 	modeStr := ""
@@ -1187,6 +1535,16 @@ func (m *Model) GetStatusBarState() components.StatusBarState {
 		modeStr = "GLOBALS"
 	case ModeHelp:
 		modeStr = "HELP"
+	case ModeQuickCalc:
+		modeStr = "CALC"
+	case ModeBlockEdit:
+		modeStr = "BLOCK"
+	case ModeVisual:
+		modeStr = "VISUAL"
+	case ModeOutline:
+		modeStr = "OUTLINE"
+	case ModeGraph:
+		modeStr = "GRAPH"
 	}
 
 	// Build hints with preview mode indicator
@@ -1208,6 +1566,22 @@ func (m *Model) GetStatusBarState() components.StatusBarState {
 		hints = "Esc=done"
 	case ModeCommand:
 		hints = "Enter=run Esc=cancel"
+	case ModeGlobals:
+		hints = "j/k=↑↓ -/+=adjust Esc=done"
+	case ModeQuickCalc:
+		hints = "Enter=insert Esc=close"
+	case ModeBlockEdit:
+		hints = "Esc=apply"
+	case ModeVisual:
+		hints = "d=delete y=yank p=put >/<=indent Esc=cancel"
+	case ModeOutline:
+		hints = "j/k=↑↓ Enter=jump r=refs Esc=back"
+	case ModeGraph:
+		hints = "j/k=scroll Esc=close"
+	}
+	if m.inactive {
+		modeStr = "SPLIT"
+		hints = "^W=focus"
 	}
 
 	return components.StatusBarState{
@@ -1259,7 +1633,7 @@ func (m *Model) collectPinnedVariables() []components.PinnedVar {
 				if m.eval != nil {
 					env := m.eval.GetEnvironment()
 					if val, ok := env.Get(varName); ok {
-						valueStr = display.Format(val)
+						valueStr = display.FormatWith(val, format.DisplayOptions(m.doc))
 					}
 				}
 
@@ -1280,19 +1654,22 @@ func (m *Model) collectPinnedVariables() []components.PinnedVar {
 func (m *Model) GetGlobalsPanelState() components.GlobalsPanelState {
 	var globals []components.GlobalVar
 
+	// Sorted so the focus index names the same global between renders
 	fm := m.doc.GetFrontmatter()
 	if fm != nil {
-		for name, value := range fm.Globals {
+		for _, name := range slices.Sorted(maps.Keys(fm.Globals)) {
+			w, _ := fm.Widget(name)
 			globals = append(globals, components.GlobalVar{
 				Name:       name,
-				Value:      fmt.Sprintf("%v", value),
+				Value:      fm.Globals[name],
 				IsExchange: false,
+				Widget:     w.Type,
 			})
 		}
-		for name, rate := range fm.Exchange {
+		for _, name := range slices.Sorted(maps.Keys(fm.Exchange)) {
 			globals = append(globals, components.GlobalVar{
 				Name:       name,
-				Value:      rate.StringFixed(4),
+				Value:      fm.Exchange[name].StringFixed(4),
 				IsExchange: true,
 			})
 		}
@@ -1313,6 +1690,7 @@ type alignedCacheKey struct {
 	cursorLine  int         // Cursor position affects highlighting
 	previewMode PreviewMode // Affects rendering
 	totalLines  int         // Quick check for document changes
+	compare     *comparison // Adds a column to calculation lines
 }
 
 // computeCacheKey computes a cache key from current model state.
@@ -1334,6 +1712,7 @@ func (m *Model) computeCacheKey() alignedCacheKey {
 		cursorLine:  m.cursorLine,
 		previewMode: m.previewMode,
 		totalLines:  len(lines),
+		compare:     m.compare,
 	}
 }
 
@@ -1366,16 +1745,11 @@ func (m *Model) GetAlignedModel(sourceWidth, previewWidth int) *AlignedModel {
 		PreviewWidth:       previewWidth,
 		CursorLine:         m.cursorLine,
 		PreviewMode:        m.previewMode,
+		TabWidth:           m.tabWidth,
 	}
 
 	// Compute with render functions that match view.go behavior
-	aligned := ComputeAlignedModel(input, m.renderCalcLine, func(line string, width int) []string {
-		mdRenderer, _ := NewMarkdownRenderer(width)
-		if mdRenderer != nil {
-			return mdRenderer.RenderLine(line)
-		}
-		return WrapText(line, width)
-	})
+	aligned := ComputeAlignedModel(input, m.renderCalcLine, renderMarkdownLine)
 
 	// Update cache
 	m.alignedCache = &aligned
@@ -1417,9 +1791,10 @@ func (m Model) CursorCol() int {
 	return m.cursorCol
 }
 
-// ScrollOffset returns the current scroll offset.
+// ScrollOffset returns the first visual line the panes show, counted from
+// the top of the document.
 func (m Model) ScrollOffset() int {
-	return m.scrollOffset
+	return max(0, m.visualDistance(0, m.cursorLine)-m.cursorRow)
 }
 
 // ShowPreview returns whether preview pane is visible (not hidden).
@@ -1517,7 +1892,7 @@ func (m Model) Debug() string {
 			"scrollOffset=%d totalSource=%d totalVisual=%d editBuf=%q "+
 			"sourcePreviewMatch=%v cursorInBounds=%v highlightMatch=%v mappingComplete=%v",
 		m.mode, m.cursorLine, m.cursorCol, cursorVisual, cursorHighlightAt,
-		m.scrollOffset, m.TotalLines(), len(aligned.sourceLines), m.editBuf,
+		m.ScrollOffset(), m.TotalLines(), len(aligned.sourceLines), m.editBuf,
 		sourcePreviewMatch, cursorInBounds, highlightMatchesMapping, mappingComplete,
 	)
 }
@@ -1579,8 +1954,8 @@ func (m *Model) deleteLine() {
 				newLines = append(newLines, blockLines[i+1:]...)
 
 				if len(newLines) == 0 {
-					// Block is now empty - delete it
-					m.doc.DeleteBlock(node.ID)
+					// Block is now empty - move it to the trash
+					m.doc.SoftDeleteBlock(node.ID)
 				} else {
 					// Replace block source
 					m.doc.ReplaceBlockSource(node.ID, newLines)
@@ -1597,13 +1972,8 @@ func (m *Model) deleteLine() {
 					m.cursorLine = total - 1
 				}
 
-				// Adjust scroll offset if it's now past document end
-				if m.scrollOffset > 0 && m.scrollOffset >= total {
-					m.scrollOffset = total - 1
-					if m.scrollOffset < 0 {
-						m.scrollOffset = 0
-					}
-				}
+				// Keep the cursor on screen now the lines above may differ
+				m.clampScroll()
 
 				return
 			}
@@ -1614,12 +1984,18 @@ func (m *Model) deleteLine() {
 
 // yankLine copies the current line to the yank buffer (yy command).
 func (m *Model) yankLine() {
-	lines := m.GetLines()
-	if m.cursorLine >= len(lines) {
+	m.yankLines(m.cursorLine, m.cursorLine)
+}
+
+// yankBlock copies the block under the cursor to the yank buffer (yB
+// command).
+func (m *Model) yankBlock() {
+	node, start, ok := m.blockAt(m.cursorLine)
+	if !ok {
 		return
 	}
-	m.yankBuffer = lines[m.cursorLine]
-	m.statusMsg = "Line yanked"
+	m.yankLines(start, start+len(node.Block.Source())-1)
+	m.statusMsg = fmt.Sprintf("Block yanked (%d lines)", len(node.Block.Source()))
 }
 
 // pasteLine pastes the yank buffer below the current line (p command).
@@ -1628,13 +2004,9 @@ func (m *Model) pasteLine() {
 		return
 	}
 
-	// Insert a new line below cursor with yanked content
-	m.insertLineBelow()
-	m.updateCurrentLine(m.yankBuffer)
-	m.modified = true
-	m.pushUndoState()
-	m.reEvaluate()
-	m.statusMsg = "Line pasted"
+	// Insert the yanked lines below cursor
+	m.insertLineWith(m.cursorLine+1, m.yankBuffer)
+	m.statusMsg = linesStatus(m.yankBuffer, "pasted")
 }
 
 // pasteLineAbove pastes the yank buffer above the current line (P command).
@@ -1643,13 +2015,18 @@ func (m *Model) pasteLineAbove() {
 		return
 	}
 
-	// Insert a new line above cursor with yanked content
-	m.insertLineAbove()
-	m.updateCurrentLine(m.yankBuffer)
-	m.modified = true
-	m.pushUndoState()
-	m.reEvaluate()
-	m.statusMsg = "Line pasted above"
+	// Insert the yanked lines above cursor
+	m.insertLineWith(m.cursorLine, m.yankBuffer)
+	m.statusMsg = linesStatus(m.yankBuffer, "pasted above")
+}
+
+// linesStatus returns the status message for what was done to text's
+// lines, e.g. "Line pasted" or "3 lines pasted".
+func linesStatus(text, what string) string {
+	if n := strings.Count(text, "\n") + 1; n > 1 {
+		return fmt.Sprintf("%d lines %s", n, what)
+	}
+	return "Line " + what
 }
 
 // searchDocument searches for a term and highlights matches.
@@ -1672,11 +2049,12 @@ func (m *Model) searchDocument(term string) {
 	}
 
 	// Jump to first match at or after cursor
+	prev := m.cursorLine
 	for i, lineNum := range m.searchMatches {
 		if lineNum >= m.cursorLine {
 			m.searchIdx = i
 			m.cursorLine = lineNum
-			m.adjustScroll()
+			m.followCursor(prev)
 			break
 		}
 	}
@@ -1684,7 +2062,7 @@ func (m *Model) searchDocument(term string) {
 		// All matches are before cursor, go to first
 		m.searchIdx = 0
 		m.cursorLine = m.searchMatches[0]
-		m.adjustScroll()
+		m.followCursor(prev)
 	}
 
 	m.statusMsg = fmt.Sprintf("Match %d of %d: %s", m.searchIdx+1, len(m.searchMatches), term)
@@ -1699,8 +2077,9 @@ func (m *Model) nextSearchMatch() {
 	}
 
 	m.searchIdx = (m.searchIdx + 1) % len(m.searchMatches)
+	prev := m.cursorLine
 	m.cursorLine = m.searchMatches[m.searchIdx]
-	m.adjustScroll()
+	m.followCursor(prev)
 	m.statusMsg = fmt.Sprintf("Match %d of %d: %s", m.searchIdx+1, len(m.searchMatches), m.searchTerm)
 }
 
@@ -1716,8 +2095,9 @@ func (m *Model) prevSearchMatch() {
 	if m.searchIdx < 0 {
 		m.searchIdx = len(m.searchMatches) - 1
 	}
+	prev := m.cursorLine
 	m.cursorLine = m.searchMatches[m.searchIdx]
-	m.adjustScroll()
+	m.followCursor(prev)
 	m.statusMsg = fmt.Sprintf("Match %d of %d: %s", m.searchIdx+1, len(m.searchMatches), m.searchTerm)
 }
 
@@ -1741,18 +2121,8 @@ func (m *Model) gotoLine(lineStr string) {
 		lineNum = total - 1
 	}
 
+	prev := m.cursorLine
 	m.cursorLine = lineNum
-	m.adjustScroll()
+	m.followCursor(prev)
 	m.statusMsg = fmt.Sprintf("Line %d", lineNum+1)
 }
-
-// adjustScroll ensures cursor is visible.
-func (m *Model) adjustScroll() {
-	visibleHeight := m.height - 6
-	if m.cursorLine < m.scrollOffset {
-		m.scrollOffset = m.cursorLine
-	}
-	if m.cursorLine >= m.scrollOffset+visibleHeight {
-		m.scrollOffset = m.cursorLine - visibleHeight + 1
-	}
-}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/components"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
 	"github.com/CalcMark/go-calcmark/spec/document"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -333,15 +334,15 @@ func TestUndo(t *testing.T) {
 	doc, _ := document.NewDocument("x = 10\n")
 	m := New(doc)
 
-	// Initial undo stack should have one entry
-	if len(m.undoStack) != 1 {
-		t.Errorf("Expected 1 undo entry, got %d", len(m.undoStack))
+	// Initial undo history should be empty
+	if len(m.history.undo) != 0 {
+		t.Errorf("Expected no undo steps, got %d", len(m.history.undo))
 	}
 
 	// Make a change
-	m.pushUndoState() // This will be a duplicate, so shouldn't add
-	if len(m.undoStack) != 1 {
-		t.Error("Duplicate state should not be added")
+	m.pushUndoState() // Nothing changed, so shouldn't add
+	if len(m.history.undo) != 0 {
+		t.Error("Unchanged state should not be added")
 	}
 }
 
@@ -404,6 +405,95 @@ func TestGetLineResults(t *testing.T) {
 	}
 }
 
+func TestGetLineResultsHighlight(t *testing.T) {
+	doc, _ := document.NewDocument("---\nhighlight:\n  burn: {\"> 50000\": red, \"<= 50000\": green}\n---\nburn = 60000\nspend = 70000\n")
+	m := New(doc)
+
+	results := m.GetLineResults()
+	highlights := map[string]string{}
+	for _, r := range results {
+		if r.VarName != "" {
+			highlights[r.VarName] = r.Highlight
+		}
+	}
+	if highlights["burn"] != "red" || highlights["spend"] != "" {
+		t.Errorf("highlights = %v, want burn=red and spend unhighlighted", highlights)
+	}
+}
+
+func TestStaleBlockRetry(t *testing.T) {
+	doc, _ := document.NewDocument("x = 2\n\n\ny = x + 1\n")
+	m := New(doc)
+
+	// As if x's block had run past the editor's budget
+	block := doc.GetBlocks()[0].Block.(*document.CalcBlock)
+	block.SetStale(true)
+	block.SetResults(nil)
+
+	stale := map[string]bool{}
+	for _, r := range m.GetLineResults() {
+		if r.VarName != "" {
+			stale[r.VarName] = r.Stale
+		}
+	}
+	if !stale["x"] || stale["y"] {
+		t.Errorf("stale = %v, want only x", stale)
+	}
+	if m.scheduleStaleRetry() == nil {
+		t.Fatal("scheduleStaleRetry() should start the idle timer")
+	}
+
+	content := m.getDocumentContent()
+	tm, cmd := m.Update(staleRetryMsg{content: content})
+	if cmd == nil {
+		t.Fatal("staleRetryMsg should start the retry")
+	}
+	tm, _ = tm.(Model).Update(cmd())
+	result := tm.(Model)
+
+	if block.IsStale() || len(block.Results()) != 1 || block.Results()[0].String() != "2" {
+		t.Errorf("after retry: stale %v, results %v, want fresh [2]", block.IsStale(), block.Results())
+	}
+	if result.scheduleStaleRetry() != nil {
+		t.Error("no retry should be scheduled once nothing is stale")
+	}
+}
+
+func TestWarnSizeLimits(t *testing.T) {
+	doc, _ := document.NewDocument("a = 1\nb = 2\nc = 3\n")
+	m := New(doc)
+
+	m.warnSizeLimits()
+	if m.statusMsg != "" {
+		t.Errorf("statusMsg = %q, want none within the default limits", m.statusMsg)
+	}
+
+	m.eval.SetLimits(implDoc.Limits{MaxResults: 2})
+	m.warnSizeLimits()
+	if !m.statusIsErr || !strings.Contains(m.statusMsg, "3 results (limit 2)") {
+		t.Errorf("statusMsg = %q, want a results limit warning", m.statusMsg)
+	}
+}
+
+func TestInsertLineKeepsBlockIDs(t *testing.T) {
+	doc, _ := document.NewDocument("---\nglobals:\n  rate: 2\n---\nx = 10\n\n\nSome notes here\n\n\ny = x * rate\n")
+	m := New(doc)
+	before := m.doc.GetBlocks()
+
+	m.insertLine(0)
+
+	// The new line may start a block of its own; existing blocks stay
+	for _, node := range before {
+		if _, ok := m.doc.GetBlock(node.ID); !ok {
+			t.Errorf("block %q lost its ID", node.Block.Source())
+		}
+	}
+	// Frontmatter is not part of the editor's lines but must survive
+	if got := m.doc.GetFrontmatter().Globals["rate"]; got != "2" {
+		t.Errorf("rate = %q after insert, want 2", got)
+	}
+}
+
 func TestTogglePreview(t *testing.T) {
 	m := New(nil)
 
@@ -464,6 +554,58 @@ func TestGlobalsMode(t *testing.T) {
 	}
 }
 
+func TestGlobalsWidgetAdjust(t *testing.T) {
+	content := `---
+globals:
+  rate: 0.05
+  base: 1000
+widgets:
+  rate: {type: slider, min: 0, max: 0.2, step: 0.01}
+---
+cost = base * rate`
+
+	doc, err := document.NewDocument(content)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	m := New(doc)
+	m.mode = ModeGlobals
+	m.globalsExpanded = true
+
+	// Globals are sorted: base, rate
+	state := m.GetGlobalsPanelState()
+	if state.Globals[1].Name != "rate" || state.Globals[1].Widget != "slider" || state.Globals[0].Widget != "" {
+		t.Fatalf("unexpected globals panel state: %+v", state.Globals)
+	}
+
+	// base has no widget
+	tm, _ := m.handleGlobalsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	result := tm.(Model)
+	if !result.statusIsErr || doc.GetFrontmatter().Globals["base"] != "1000" {
+		t.Errorf("+ on a global without a widget should report an error, got %q", result.statusMsg)
+	}
+
+	tm, _ = result.handleGlobalsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	tm, _ = tm.(Model).handleGlobalsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	tm, _ = tm.(Model).handleGlobalsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	result = tm.(Model)
+
+	if got := result.doc.GetFrontmatter().Globals["rate"]; got != "0.07" {
+		t.Errorf("rate = %q after two +, want 0.07", got)
+	}
+	if cost, ok := result.eval.GetEnvironment().Get("cost"); !ok || cost.String() != "70" {
+		t.Errorf("cost = %v, want 70 after re-evaluation", cost)
+	}
+	if !result.modified {
+		t.Error("adjusting a widget should mark the document modified")
+	}
+
+	tm, _ = result.handleGlobalsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	if got := tm.(Model).doc.GetFrontmatter().Globals["rate"]; got != "0.06" {
+		t.Errorf("rate = %q after -, want 0.06", got)
+	}
+}
+
 func TestGgGoToTop(t *testing.T) {
 	doc, _ := document.NewDocument("line1\nline2\nline3\n")
 	m := New(doc)
@@ -3340,9 +3482,9 @@ func truncate(s string, maxLen int) string {
 }
 
 func TestScrollOffset_VisualVsSource(t *testing.T) {
-	// The bug: scrollOffset is set using source line indices
-	// but used as visual line indices in rendering.
-	// When there are wrapped lines, these diverge!
+	// Scrolling once kept a source line index but rendered it as a visual
+	// line index, so with wrapped lines the two diverged. The scroll offset
+	// is now in visual lines throughout.
 
 	content := `short = 1
 this_is_a_long_line_that_will_wrap = 2
@@ -3357,54 +3499,28 @@ short = 5`
 	m.previewMode = PreviewFull
 
 	leftWidth, rightWidth := m.GetPaneWidths(m.width)
-
-	// Check initial visual structure
 	aligned := m.computeAlignedPanes(leftWidth, rightWidth)
-	t.Logf("Visual structure (%d visual lines for %d source lines):",
-		len(aligned.sourceLines), m.TotalLines())
-	for i, sl := range aligned.sourceLines {
-		t.Logf("  Visual[%d]: srcIdx=%d, content=%q",
-			i, sl.sourceLineIdx, truncate(sl.content, 30))
+	if len(aligned.sourceLines) <= m.TotalLines() {
+		t.Fatalf("expected wrapping: %d visual lines for %d source lines", len(aligned.sourceLines), m.TotalLines())
 	}
 
-	// The key insight: if we have wrapping, visual line count > source line count
-	// scrollOffset is set based on source lines but used as visual lines
-	t.Logf("scrollOffset before navigation: %d", m.scrollOffset)
-
-	// Navigate down - this sets scrollOffset based on cursorLine (source)
+	height := m.visibleHeight()
 	for i := 0; i < 4; i++ {
-		m.cursorLine++
-		// Simulate the moveCursor scroll adjustment
-		visibleHeight := m.height - 6
-		if m.cursorLine >= m.scrollOffset+visibleHeight {
-			m.scrollOffset = m.cursorLine - visibleHeight + 1
+		tm, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+		m = tm.(Model)
+
+		visualIdx := aligned.sourceToVisual[m.cursorLine]
+		scroll := m.ScrollOffset()
+		if visualIdx < scroll || visualIdx >= scroll+height {
+			t.Errorf("cursor on source line %d (visual %d) is outside visual lines %d-%d",
+				m.cursorLine, visualIdx, scroll, scroll+height-1)
+		}
+		if got := visualIdx - scroll; got != m.cursorRow {
+			t.Errorf("cursor on source line %d shown on row %d, want row %d", m.cursorLine, got, m.cursorRow)
+		}
+		if scroll >= len(aligned.sourceLines) {
+			t.Errorf("scroll offset (%d) >= visual line count (%d)", scroll, len(aligned.sourceLines))
 		}
-	}
-
-	t.Logf("After navigating to source line %d: scrollOffset=%d",
-		m.cursorLine, m.scrollOffset)
-
-	// Now check: what visual line does this source line map to?
-	visualIdx, _ := aligned.sourceToVisual[m.cursorLine]
-	t.Logf("Source line %d maps to visual line %d", m.cursorLine, visualIdx)
-
-	// THE BUG: if scrollOffset=3 (source), but source line 3 is visual line 5,
-	// then rendering will start at visual line 3 (wrong!)
-	// It should start at a visual line that makes the cursor visible
-
-	// In a correct implementation, scrollOffset should be in visual space
-	// OR the render should convert scrollOffset from source to visual
-
-	// For now, just verify that scrollOffset stays sensible
-	if m.scrollOffset > visualIdx {
-		t.Errorf("BUG: scrollOffset (%d) > cursor visual line (%d)",
-			m.scrollOffset, visualIdx)
-	}
-
-	// Also verify scrollOffset isn't larger than visual line count
-	if m.scrollOffset >= len(aligned.sourceLines) {
-		t.Errorf("scrollOffset (%d) >= visual line count (%d)",
-			m.scrollOffset, len(aligned.sourceLines))
 	}
 }
 
@@ -3731,9 +3847,9 @@ lz4_compressed = compress(100 MB, lz4)`
 	}
 
 	// Should show the computed results
-	if !strings.Contains(view, "341 MB") {
+	if !strings.Contains(view, "333 MB") {
 		t.Logf("VIEW:\n%s", view)
-		t.Error("View should show gzip result '341 MB'")
+		t.Error("View should show gzip result '333 MB'")
 	}
 
 	// View should have reasonable number of lines (not collapsed)
@@ -4032,10 +4148,10 @@ zstd_compressed = compress(500 MB, zstd)`
 	}
 
 	// Also verify all three computed results are visible
-	// gzip: compress(1 GB, gzip) = 1000 MB * 0.341 = 341 MB
-	if !strings.Contains(view, "341 MB") {
+	// gzip: compress(1 GB, gzip) = 1000 MB / 3 ≈ 333 MB
+	if !strings.Contains(view, "333 MB") {
 		t.Logf("VIEW:\n%s", view)
-		t.Error("Preview should show computed result '341 MB' for gzip_compressed")
+		t.Error("Preview should show computed result '333 MB' for gzip_compressed")
 	}
 	// zstd: compress(500 MB, zstd) = 500 * 0.285714 ≈ 142.857 MB
 	if !strings.Contains(view, "142.857") {
@@ -4179,3 +4295,44 @@ func TestViewHeightWithErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestPrecisionVerifyMsgStaleContent(t *testing.T) {
+	doc, _ := document.NewDocument("x = 1 / 3\n")
+	m := New(doc)
+
+	// A verification tick for content that has since changed is ignored
+	updated, _ := m.Update(precisionVerifyMsg{content: "x = 2\n"})
+	if got := updated.(Model).statusMsg; got != "" {
+		t.Errorf("expected no status for stale verification, got %q", got)
+	}
+
+	// Verification is disabled by default, so no timer is scheduled
+	if cmd := m.scheduleVerifyPrecision(); cmd != nil {
+		t.Error("expected no verification timer when verify_precision is off")
+	}
+}
+
+func TestCheckPrecisionInBackground(t *testing.T) {
+	// Rounding 1/3 leaves an error the multiplication makes visible
+	doc, _ := document.NewDocument("x = (1 / 3 * 3 - 1) * 10^20\n")
+	m := New(doc)
+	content := m.getDocumentContent()
+
+	// The check runs in the command, not in Update
+	msg, ok := m.checkPrecision(content)().(precisionVerifiedMsg)
+	if !ok || len(msg.mismatches) != 1 {
+		t.Fatalf("expected one mismatch, got %+v", msg)
+	}
+
+	updated, _ := m.Update(msg)
+	if got := updated.(Model).statusMsg; !strings.Contains(got, "Precision check: x") {
+		t.Errorf("expected precision warning, got %q", got)
+	}
+
+	// A result for content that has since changed is ignored
+	msg.content = "x = 2\n"
+	updated, _ = m.Update(msg)
+	if got := updated.(Model).statusMsg; got != "" {
+		t.Errorf("expected no status for stale result, got %q", got)
+	}
+}
@@ -0,0 +1,161 @@
+package editor
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Mouse input (tui.mouse): a click in the source pane puts the cursor on
+// the character under it, and a click in the preview pane on the source
+// line of the result there. The wheel scrolls, and dragging over source
+// lines selects them, yanking them on release for p and P to paste.
+//
+// The mouse works in normal mode only; the edit line, prompts, and the
+// block editor keep the keyboard's focus.
+
+// wheelRows is the visual lines one wheel notch scrolls.
+const wheelRows = 3
+
+// paneLineNumWidth is the columns of a source line's number and the space
+// after it, before its content.
+const paneLineNumWidth = 5
+
+// mouseTarget is the document position under a screen cell.
+type mouseTarget struct {
+	line    int  // Source line
+	col     int  // Byte offset of the character under the cell
+	row     int  // Screen row of the line's first visual line in the panes
+	preview bool // In the preview pane
+}
+
+// handleMouse handles a mouse event.
+func (m Model) handleMouse(msg tea.MouseMsg) Model {
+	if m.mode != ModeNormal {
+		return m
+	}
+	m.InvalidateAlignedCache()
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.movePage(-wheelRows)
+
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.movePage(wheelRows)
+
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		m.statusMsg = ""
+		m.statusIsErr = false
+		target, ok := m.mouseTarget(msg.X, msg.Y)
+		if !ok {
+			return m
+		}
+		m.moveCursorTo(target)
+		if !target.preview {
+			m.dragging = true
+			m.dragAnchor = target.line
+		}
+
+	case msg.Action == tea.MouseActionMotion && m.dragging:
+		if target, ok := m.mouseTarget(msg.X, m.clampPaneRow(msg.Y)); ok && !target.preview {
+			m.moveCursorTo(target)
+		}
+
+	case msg.Action == tea.MouseActionRelease && m.dragging:
+		m.dragging = false
+		if m.cursorLine != m.dragAnchor {
+			m.yankSelection()
+		}
+	}
+	return m
+}
+
+// moveCursorTo puts the cursor at target without scrolling the panes.
+func (m *Model) moveCursorTo(target mouseTarget) {
+	m.cursorLine = target.line
+	m.cursorCol = 0
+	if !target.preview {
+		m.cursorCol = target.col
+	}
+	m.cursorRow = target.row
+	m.clampScroll()
+}
+
+// selection returns the first and last source lines of the visual mode or
+// drag selection, and false if there's none.
+func (m Model) selection() (first, last int, ok bool) {
+	switch {
+	case m.mode == ModeVisual:
+		return min(m.visualAnchor, m.cursorLine), max(m.visualAnchor, m.cursorLine), true
+	case m.dragging && m.cursorLine != m.dragAnchor:
+		return min(m.dragAnchor, m.cursorLine), max(m.dragAnchor, m.cursorLine), true
+	}
+	return 0, 0, false
+}
+
+// inSelection reports whether source line is selected.
+func (m Model) inSelection(line int) bool {
+	first, last, ok := m.selection()
+	return ok && line >= first && line <= last
+}
+
+// yankSelection yanks the lines from the drag's anchor to the cursor.
+func (m *Model) yankSelection() {
+	m.yankLines(min(m.dragAnchor, m.cursorLine), max(m.dragAnchor, m.cursorLine))
+}
+
+// paneTop returns the screen row of the panes' first document line, below
+// their headers and, with the preview shown, the globals panel.
+func (m Model) paneTop() int {
+	_, _, globals := m.paneHeights()
+	if m.previewMode == PreviewHidden {
+		return 1
+	}
+	return 1 + globals
+}
+
+// clampPaneRow returns screen row y moved onto the panes' document lines,
+// so dragging past their top or bottom selects up to the line there.
+func (m Model) clampPaneRow(y int) int {
+	top := m.paneTop()
+	return max(top, min(y, top+m.visibleHeight()-1))
+}
+
+// mouseTarget returns the document position shown at screen cell (x, y),
+// or false if no document line is shown there.
+func (m Model) mouseTarget(x, y int) (mouseTarget, bool) {
+	pane, height, _ := m.paneHeights()
+	row := y - m.paneTop()
+	if row < 0 || row >= height {
+		return mouseTarget{}, false
+	}
+
+	leftWidth, rightWidth := m.GetPaneWidths(m.width)
+	aligned := m.computeVisiblePanes(leftWidth, rightWidth, pane)
+	i := m.scrollStart(aligned, height) + row
+	if i >= len(aligned.sourceLines) {
+		return mouseTarget{}, false
+	}
+	sl := aligned.sourceLines[i]
+	line := sl.sourceLineIdx
+
+	// The line's first visual line, and the text of those before i
+	first := i
+	for first > 0 && aligned.sourceLines[first-1].sourceLineIdx == line {
+		first--
+	}
+	target := mouseTarget{line: line, row: row - (i - first)}
+	if x >= leftWidth && m.previewMode != PreviewHidden {
+		target.preview = true
+		return target, true
+	}
+
+	var before int
+	for _, prev := range aligned.sourceLines[first:i] {
+		before += lipgloss.Width(prev.content)
+	}
+	if lines := m.GetLines(); line < len(lines) && !sl.isPadding {
+		column := before + max(0, x-paneLineNumWidth)
+		target.col = clampGrapheme(lines[line], graphemeOffset(lines[line], column, m.tabWidth))
+	}
+	return target, true
+}
@@ -0,0 +1,124 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// cellOf returns the screen cell text is first drawn at in m's source pane.
+func cellOf(t *testing.T, m Model, text string) (x, y int) {
+	t.Helper()
+	leftWidth, _ := m.GetPaneWidths(m.width)
+	for y, row := range strings.Split(m.View(), "\n") {
+		if i := strings.Index(row, text); i >= 0 && lipgloss.Width(row[:i]) < leftWidth {
+			return lipgloss.Width(row[:i]), y
+		}
+	}
+	t.Fatalf("%q is not on screen", text)
+	return 0, 0
+}
+
+// click presses and releases the left button at (x, y).
+func click(m Model, x, y int) Model {
+	m = m.handleMouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	return m.handleMouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+}
+
+func TestMouseClickPositionsCursor(t *testing.T) {
+	m := newScrollModel(t, 30)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+
+	// A character of a line above the cursor, which stays where it's drawn
+	x, y := cellOf(t, m, "x24 = 24")
+	m = click(m, x+4, y)
+	if m.cursorLine != 24 || m.cursorCol != 4 {
+		t.Fatalf("cursor at %d:%d, want 24:4", m.cursorLine, m.cursorCol)
+	}
+	if got := m.paneTop() + screenRow(m); got != y {
+		t.Errorf("clicking scrolled the panes: cursor on screen row %d, want %d", got, y)
+	}
+
+	// The second row of a wrapped line
+	x, y = cellOf(t, m, "width of the")
+	m = click(m, x, y)
+	if got := m.GetLines()[m.cursorLine][m.cursorCol:]; !strings.HasPrefix(got, "width of the") {
+		t.Errorf("cursor before %q, want it before \"width of the\"", got)
+	}
+
+	// Past the end of a line
+	_, y = cellOf(t, m, "x27 = 27")
+	m = click(m, 30, y)
+	if m.cursorLine != 27 || m.cursorCol != len("x27 = 27") {
+		t.Errorf("cursor at %d:%d, want the end of line 27", m.cursorLine, m.cursorCol)
+	}
+}
+
+func TestMouseClickPreviewJumpsToSource(t *testing.T) {
+	m := newScrollModel(t, 30)
+	leftWidth, _ := m.GetPaneWidths(m.width)
+	_, y := cellOf(t, m, "x6 = 6")
+	m = click(m, leftWidth+2, y)
+	if m.cursorLine != 6 || m.cursorCol != 0 {
+		t.Errorf("cursor at %d:%d, want 6:0", m.cursorLine, m.cursorCol)
+	}
+}
+
+func TestMouseWheelScrolls(t *testing.T) {
+	m := newScrollModel(t, 30)
+	m = m.handleMouse(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if m.cursorLine == 0 {
+		t.Fatal("wheel down didn't scroll")
+	}
+	m = m.handleMouse(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	if m.cursorLine != 0 {
+		t.Errorf("wheel up left the cursor on line %d, want 0", m.cursorLine)
+	}
+}
+
+func TestMouseDragYanksLines(t *testing.T) {
+	m := newScrollModel(t, 30)
+	x, from := cellOf(t, m, "x3 = 3")
+	_, to := cellOf(t, m, "x5 = 5")
+
+	m = m.handleMouse(tea.MouseMsg{X: x, Y: from, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	m = m.handleMouse(tea.MouseMsg{X: x, Y: to, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft})
+	if !m.inSelection(4) || m.inSelection(2) || m.inSelection(6) {
+		t.Error("selection isn't lines 3 to 5")
+	}
+	m = m.handleMouse(tea.MouseMsg{X: x, Y: to, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+
+	want := strings.Join(m.GetLines()[3:6], "\n")
+	if m.yankBuffer != want {
+		t.Fatalf("yanked %q, want %q", m.yankBuffer, want)
+	}
+	if m.inSelection(4) {
+		t.Error("selection outlived the drag")
+	}
+
+	// The yanked lines paste together, as one undo step
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	lines := m.GetLines()
+	if got := strings.Join(lines[len(lines)-3:], "\n"); got != want {
+		t.Errorf("pasted %q, want %q", got, want)
+	}
+	if m.statusMsg != "3 lines pasted" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+	m.undo()
+	if len(m.GetLines()) != len(lines)-3 {
+		t.Errorf("undo left %d lines, want %d", len(m.GetLines()), len(lines)-3)
+	}
+}
+
+func TestMouseIgnoredWhileEditing(t *testing.T) {
+	m := newScrollModel(t, 30)
+	x, y := cellOf(t, m, "x6 = 6")
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = click(m, x, y)
+	if m.cursorLine != 0 || m.mode != ModeEditing {
+		t.Errorf("click while editing moved the cursor to line %d", m.cursorLine)
+	}
+}
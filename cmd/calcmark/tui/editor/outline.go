@@ -0,0 +1,324 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Outline: gO (or /outline) opens a panel listing the document's
+// variables, grouped by the calculation block defining them, with their
+// current values. j/k select a variable and Enter jumps to the line
+// defining it. r lists the lines using it instead: the block defining it
+// and the blocks the dependency graph says read it are searched for the
+// name, and Enter jumps to one. Esc goes back from the references to the
+// variables, and closes the panel.
+
+// maxOutlineRows is the most rows the outline shows at once, block
+// headings included; it scrolls to keep the selection in view.
+const maxOutlineRows = 8
+
+// outlineVariable is a variable in the outline.
+type outlineVariable struct {
+	block  int    // Calculation block number, counting from 1
+	name   string // Variable name
+	value  string // Value at the end of the block, "" if it has none
+	line   int    // Source line of its first assignment in the block
+	column int    // Rune column of the name on that line (1-indexed)
+}
+
+// outlineReference is a use of a variable.
+type outlineReference struct {
+	line   int    // Source line index
+	column int    // Rune column of the name (1-indexed)
+	source string // The line
+}
+
+// openOutline focuses the outline panel on the variable defined at or
+// before the cursor.
+func (m *Model) openOutline() {
+	vars := m.outlineVariables()
+	if len(vars) == 0 {
+		m.statusMsg = "No variables"
+		return
+	}
+	m.mode = ModeOutline
+	m.outlineVar = ""
+	m.outlineIdx = 0
+	for i, v := range vars {
+		if v.line <= m.cursorLine {
+			m.outlineIdx = i
+		}
+	}
+}
+
+// handleOutlineKey processes keys when the outline panel is focused.
+func (m Model) handleOutlineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "j", "down":
+		m.outlineIdx = min(m.outlineIdx+1, max(0, m.outlineCount()-1))
+	case "k", "up":
+		m.outlineIdx = max(m.outlineIdx-1, 0)
+	case "enter":
+		m.outlineJump()
+	case "r":
+		m.showReferences()
+	case "esc", "q":
+		if m.outlineVar == "" {
+			m.mode = ModeNormal
+			return m, nil
+		}
+		// Back to the variables, at the one whose references these were
+		name := m.outlineVar
+		m.outlineVar = ""
+		m.outlineIdx = max(0, slices.IndexFunc(m.outlineVariables(), func(v outlineVariable) bool { return v.name == name }))
+	}
+	return m, nil
+}
+
+// outlineCount returns the number of rows that can be selected: variables,
+// or references when they are listed.
+func (m *Model) outlineCount() int {
+	if m.outlineVar != "" {
+		return len(m.variableReferences(m.outlineVar))
+	}
+	return len(m.outlineVariables())
+}
+
+// outlineJump moves the cursor to the selected variable's definition or
+// reference and closes the panel.
+func (m *Model) outlineJump() {
+	line, column := -1, 0
+	if m.outlineVar != "" {
+		refs := m.variableReferences(m.outlineVar)
+		if m.outlineIdx < len(refs) {
+			r := refs[m.outlineIdx]
+			line, column = r.line, r.column
+			m.statusMsg = fmt.Sprintf("Reference %d of %d to %s", m.outlineIdx+1, len(refs), m.outlineVar)
+		}
+	} else {
+		vars := m.outlineVariables()
+		if m.outlineIdx < len(vars) {
+			v := vars[m.outlineIdx]
+			line, column = v.line, v.column
+			m.statusMsg = fmt.Sprintf("%s defined in block %d", v.name, v.block)
+		}
+	}
+	if line < 0 {
+		return
+	}
+
+	lines := m.GetLines()
+	prev := m.cursorLine
+	m.cursorLine = min(line, max(0, len(lines)-1))
+	m.cursorCol = 0
+	if m.cursorLine < len(lines) {
+		m.cursorCol = runeOffset(lines[m.cursorLine], column)
+	}
+	m.followCursor(prev)
+	m.mode = ModeNormal
+	m.outlineVar = ""
+}
+
+// showReferences lists the lines using the selected variable.
+func (m *Model) showReferences() {
+	if m.outlineVar != "" {
+		return
+	}
+	vars := m.outlineVariables()
+	if m.outlineIdx >= len(vars) {
+		return
+	}
+	name := vars[m.outlineIdx].name
+	if len(m.variableReferences(name)) == 0 {
+		m.statusMsg = fmt.Sprintf("No references to %s", name)
+		return
+	}
+	m.outlineVar = name
+	m.outlineIdx = 0
+}
+
+// outlineVariables returns the variables each calculation block defines,
+// in document order.
+func (m *Model) outlineVariables() []outlineVariable {
+	results := m.GetLineResults()
+	var vars []outlineVariable
+	block, lineNum := 0, 0
+	for _, node := range m.doc.GetBlocks() {
+		start := lineNum
+		lineNum += len(node.Block.Source())
+		if _, ok := node.Block.(*document.CalcBlock); !ok {
+			continue
+		}
+		block++
+
+		first := len(vars)
+		for _, lr := range results[start:min(lineNum, len(results))] {
+			if lr.VarName == "" {
+				continue
+			}
+			// A reassignment updates the value, and keeps the first line
+			if i := slices.IndexFunc(vars[first:], func(v outlineVariable) bool { return v.name == lr.VarName }); i >= 0 {
+				if lr.Value != "" {
+					vars[first+i].value = lr.Value
+				}
+				continue
+			}
+			column := 1
+			if i := strings.Index(lr.Source, lr.VarName); i >= 0 {
+				column = runeColumn(lr.Source, i)
+			}
+			vars = append(vars, outlineVariable{
+				block:  block,
+				name:   lr.VarName,
+				value:  lr.Value,
+				line:   lr.LineNum,
+				column: column,
+			})
+		}
+	}
+	return vars
+}
+
+// variableReferences returns the lines using name, other than to assign
+// it: in the blocks defining it, and those the dependency graph says read
+// it from other blocks.
+func (m *Model) variableReferences(name string) []outlineReference {
+	var refs []outlineReference
+	lineNum := 0
+	for _, node := range m.doc.GetBlocks() {
+		source := node.Block.Source()
+		start := lineNum
+		lineNum += len(source)
+		cb, ok := node.Block.(*document.CalcBlock)
+		if !ok || !slices.Contains(cb.Variables(), name) && !slices.Contains(cb.Dependencies(), name) {
+			continue
+		}
+		for i, line := range source {
+			for _, column := range identifierColumns(line, name, m.doc.NumberLocale()) {
+				refs = append(refs, outlineReference{line: start + i, column: column, source: line})
+			}
+		}
+	}
+	return refs
+}
+
+// identifierColumns returns the rune columns (1-indexed) where line reads
+// the identifier name, skipping where it is assigned.
+func identifierColumns(line, name string, loc lexer.NumberLocale) []int {
+	if !strings.Contains(line, name) {
+		return nil
+	}
+	var columns []int
+	for _, tok := range readIdentifiers(line, loc) {
+		if tok.Value == name {
+			columns = append(columns, tok.Column)
+		}
+	}
+	return columns
+}
+
+// readIdentifiers returns the identifiers line reads: all of them but the
+// ones it assigns.
+func readIdentifiers(line string, loc lexer.NumberLocale) []lexer.Token {
+	tokens, err := lexer.NewLexerWithLocale(line, loc).Tokenize()
+	if err != nil {
+		return nil
+	}
+	var idents []lexer.Token
+	for i, tok := range tokens {
+		if tok.Type != lexer.IDENTIFIER {
+			continue
+		}
+		if i+1 < len(tokens) && tokens[i+1].Type == lexer.ASSIGN {
+			continue
+		}
+		idents = append(idents, tok)
+	}
+	return idents
+}
+
+// outlinePanelHeight returns the lines the outline panel takes, its header
+// included, or 0 when it is closed.
+func (m Model) outlinePanelHeight() int {
+	if m.mode != ModeOutline {
+		return 0
+	}
+	return 1 + min(len(m.outlineRows()), maxOutlineRows)
+}
+
+// outlineRow is a row of the outline panel.
+type outlineRow struct {
+	text     string
+	heading  bool // A block heading rather than a selectable row
+	selected bool
+}
+
+// outlineRows returns the rows of the outline panel: the variables under
+// headings for their blocks, or the references to one.
+func (m Model) outlineRows() []outlineRow {
+	var rows []outlineRow
+	if m.outlineVar != "" {
+		for i, r := range m.variableReferences(m.outlineVar) {
+			text := fmt.Sprintf("  %-8s %s", fmt.Sprintf("%d:%d", r.line+1, r.column), strings.TrimSpace(r.source))
+			rows = append(rows, outlineRow{text: text, selected: i == m.outlineIdx})
+		}
+		return rows
+	}
+
+	block := 0
+	for i, v := range m.outlineVariables() {
+		if v.block != block {
+			block = v.block
+			rows = append(rows, outlineRow{text: fmt.Sprintf("Block %d", block), heading: true})
+		}
+		value := v.value
+		if value == "" {
+			value = "—"
+		}
+		text := fmt.Sprintf("  %-18s %-16s line %d", v.name, value, v.line+1)
+		rows = append(rows, outlineRow{text: text, selected: i == m.outlineIdx})
+	}
+	return rows
+}
+
+// renderOutlinePanel renders the outline panel, scrolled to keep the
+// selection in view.
+func (m Model) renderOutlinePanel(width int) string {
+	title := "Variables  Enter jump  r references  Esc close"
+	if m.outlineVar != "" {
+		title = fmt.Sprintf("References to %s (%d)  Enter jump  Esc back", m.outlineVar, m.outlineCount())
+	}
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("252")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1).
+		Width(width).
+		Render(title)
+
+	rows := m.outlineRows()
+	selected := max(0, slices.IndexFunc(rows, func(r outlineRow) bool { return r.selected }))
+	start := max(0, min(selected-maxOutlineRows/2, len(rows)-maxOutlineRows))
+	end := min(start+maxOutlineRows, len(rows))
+
+	lines := []string{header}
+	headingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for _, r := range rows[start:end] {
+		text := lipgloss.NewStyle().MaxWidth(width).Render(r.text)
+		switch {
+		case r.heading:
+			text = headingStyle.Render(text)
+		case r.selected:
+			text = m.styles.CurrentLine.Width(width).Render(padToWidth(text, width))
+		}
+		lines = append(lines, text)
+	}
+	return strings.Join(lines, "\n")
+}
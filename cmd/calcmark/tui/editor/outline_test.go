@@ -0,0 +1,121 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+
+// newOutlineModel returns an editor on a document of two calculation
+// blocks, the second reading price from the first.
+func newOutlineModel(t *testing.T) Model {
+	t.Helper()
+	doc, err := document.NewDocument("price = $100\nqty = 3\nprice = price * 2\n\nSome notes.\n\ntotal = price * qty\ntax = total * 10%\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, _ := New(doc).Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	return model.(Model)
+}
+
+func TestOutlineVariables(t *testing.T) {
+	m := newOutlineModel(t)
+	vars := m.outlineVariables()
+
+	want := []struct {
+		block int
+		name  string
+		value string
+		line  int
+	}{
+		{1, "price", "$200.00", 0},
+		{1, "qty", "3", 1},
+		{2, "total", "$600.00", 6},
+		{2, "tax", "$60.00", 7},
+	}
+	if len(vars) != len(want) {
+		t.Fatalf("got %d variables, want %d: %+v", len(vars), len(want), vars)
+	}
+	for i, w := range want {
+		v := vars[i]
+		if v.block != w.block || v.name != w.name || v.value != w.value || v.line != w.line {
+			t.Errorf("variable %d = %+v, want %+v", i, v, w)
+		}
+	}
+}
+
+func TestOutlineJumpsToDefinition(t *testing.T) {
+	m := newOutlineModel(t)
+	m = press(m, runes("GgO")...)
+	if m.mode != ModeOutline || m.outlineIdx != 3 {
+		t.Fatalf("gO at the end: mode %v, selected %d, want the outline at tax", m.mode, m.outlineIdx)
+	}
+
+	view := m.View()
+	for _, want := range []string{"Variables", "Block 1", "Block 2", "price", "$600.00", "OUTLINE"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view doesn't show %q:\n%s", want, view)
+		}
+	}
+
+	m = press(m, runes("kk")...)
+	m = press(m, keyEnter)
+	if m.mode != ModeNormal || m.cursorLine != 1 || m.cursorCol != 0 {
+		t.Errorf("Enter on qty: mode %v at %d:%d, want normal at 1:0", m.mode, m.cursorLine, m.cursorCol)
+	}
+	if m.statusMsg != "qty defined in block 1" {
+		t.Errorf("status %q", m.statusMsg)
+	}
+}
+
+func TestOutlineReferences(t *testing.T) {
+	m := newOutlineModel(t)
+	m = press(m, runes("gOr")...)
+	if m.outlineVar != "price" {
+		t.Fatalf("r listed references to %q, want price", m.outlineVar)
+	}
+
+	// price * 2 in its own block, and price * qty in the one reading it;
+	// the assignments aren't references
+	refs := m.variableReferences("price")
+	if len(refs) != 2 || refs[0].line != 2 || refs[0].column != 9 || refs[1].line != 6 || refs[1].column != 9 {
+		t.Fatalf("references to price %+v, want 3:9 and 7:9", refs)
+	}
+	if view := m.View(); !strings.Contains(view, "References to price (2)") || !strings.Contains(view, "7:9") {
+		t.Errorf("view doesn't list the references:\n%s", view)
+	}
+
+	m = press(m, runes("j")...)
+	m = press(m, keyEnter)
+	if m.cursorLine != 6 || m.cursorCol != 8 || m.statusMsg != "Reference 2 of 2 to price" {
+		t.Errorf("Enter on the second reference: %d:%d, status %q", m.cursorLine, m.cursorCol, m.statusMsg)
+	}
+}
+
+func TestOutlineEscape(t *testing.T) {
+	m := newOutlineModel(t)
+	m = press(m, runes("gOjr")...)
+	if m.outlineVar != "qty" {
+		t.Fatalf("r listed references to %q, want qty", m.outlineVar)
+	}
+
+	// Esc goes back to the variables at qty, then closes
+	m = press(m, keyEsc)
+	if m.mode != ModeOutline || m.outlineVar != "" || m.outlineIdx != 1 {
+		t.Errorf("Esc from references: mode %v, var %q, selected %d", m.mode, m.outlineVar, m.outlineIdx)
+	}
+	m = press(m, keyEsc)
+	if m.mode != ModeNormal || strings.Contains(m.View(), "Block 1") {
+		t.Error("Esc didn't close the outline")
+	}
+
+	// A variable nothing reads has no references
+	m = press(m, runes("GgOr")...)
+	if m.outlineVar != "" || m.statusMsg != "No references to tax" {
+		t.Errorf("r on tax: var %q, status %q", m.outlineVar, m.statusMsg)
+	}
+}
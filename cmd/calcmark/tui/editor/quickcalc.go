@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Quick calculator: Ctrl+K opens a one-line scratch calculator over the
+// editor from any mode. It evaluates as you type against the document's
+// variables without changing them, and Enter inserts the expression as a
+// new line below the cursor.
+
+// openQuickCalc shows the quick calculator, remembering the mode to return to.
+func (m *Model) openQuickCalc() {
+	m.quickReturnMode = m.mode
+	m.mode = ModeQuickCalc
+	m.quickInput = ""
+	m.quickResult = ""
+	m.quickIsErr = false
+}
+
+// closeQuickCalc hides the quick calculator, returning to the mode it was
+// opened from.
+func (m *Model) closeQuickCalc() {
+	m.mode = m.quickReturnMode
+	m.quickInput = ""
+	m.quickResult = ""
+	m.quickIsErr = false
+}
+
+// handleQuickCalcKey processes keys in the quick calculator.
+func (m Model) handleQuickCalcKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlK:
+		m.closeQuickCalc()
+		return m, nil
+	case tea.KeyEnter:
+		m.insertQuickCalc()
+		return m, nil
+	case tea.KeyBackspace:
+		if len(m.quickInput) > 0 {
+			m.quickInput = m.quickInput[:prevGrapheme(m.quickInput, len(m.quickInput))]
+		}
+	case tea.KeySpace:
+		m.quickInput += " "
+	case tea.KeyRunes:
+		m.quickInput += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	m.quickResult, m.quickIsErr = m.evalQuickCalc(m.quickInput)
+	return m, nil
+}
+
+// evalQuickCalc evaluates input against a copy of the document's
+// environment, so assignments in the scratch expression don't leak into the
+// document. It returns the displayed result, or an error message and true.
+func (m *Model) evalQuickCalc(input string) (string, bool) {
+	if strings.TrimSpace(input) == "" {
+		return "", false
+	}
+
+	nodes, err := parser.ParseWithLocale(input+"\n", m.doc.NumberLocale())
+	if err != nil {
+		return err.Error(), true
+	}
+	env := m.eval.GetEnvironment().Clone()
+	results, err := interpreter.NewInterpreterWithEnv(env).Eval(nodes)
+	if err != nil {
+		return err.Error(), true
+	}
+	if len(results) == 0 {
+		return "", false
+	}
+	return display.FormatWith(results[len(results)-1], format.DisplayOptions(m.doc)), false
+}
+
+// insertQuickCalc inserts the quick calculator's expression as a new line
+// below the cursor and closes the calculator. A line being edited is saved
+// first; in the block editor, the expression goes in at its cursor.
+func (m *Model) insertQuickCalc() {
+	input := strings.TrimSpace(m.quickInput)
+	if input == "" {
+		m.closeQuickCalc()
+		return
+	}
+
+	m.closeQuickCalc()
+	if m.mode == ModeBlockEdit {
+		// The block editor takes it at its cursor, to apply with the block
+		m.blockEditor.InsertString(input)
+		m.statusMsg = fmt.Sprintf("Inserted: %s", input)
+		return
+	}
+	if m.mode == ModeEditing {
+		m.exitEditMode(true)
+	}
+	m.insertLineWith(m.cursorLine+1, input)
+	m.statusMsg = fmt.Sprintf("Inserted: %s", input)
+}
@@ -0,0 +1,123 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeQuickCalc sends text to the model one key at a time, as typed.
+func typeQuickCalc(m Model, text string) Model {
+	for _, r := range text {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace}
+		}
+		tm, _ := m.handleKey(msg)
+		m = tm.(Model)
+	}
+	return m
+}
+
+func TestQuickCalc(t *testing.T) {
+	doc, _ := document.NewDocument("price = $20\nqty = 3\n")
+	m := New(doc)
+
+	tm, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = tm.(Model)
+	if m.mode != ModeQuickCalc {
+		t.Fatalf("Ctrl+K should open the quick calculator, mode = %v", m.mode)
+	}
+
+	// Evaluates as you type, against the document's variables
+	m = typeQuickCalc(m, "price * qty")
+	if m.quickResult != "$60.00" || m.quickIsErr {
+		t.Errorf("quickResult = %q (error %v), want $60.00", m.quickResult, m.quickIsErr)
+	}
+
+	tm, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = tm.(Model)
+	if m.quickInput != "price * qt" || !m.quickIsErr {
+		t.Errorf("after backspace: input %q, error %v; want an undefined variable error", m.quickInput, m.quickIsErr)
+	}
+
+	// Escape closes without changing the document
+	before := m.getDocumentContent()
+	tm, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = tm.(Model)
+	if m.mode != ModeNormal || m.quickInput != "" {
+		t.Errorf("Esc should close the calculator, mode = %v, input %q", m.mode, m.quickInput)
+	}
+	if m.getDocumentContent() != before || m.modified {
+		t.Error("closing the calculator should not change the document")
+	}
+}
+
+// TestQuickCalcReadOnly checks assignments in the calculator don't reach
+// the document's variables.
+func TestQuickCalcReadOnly(t *testing.T) {
+	doc, _ := document.NewDocument("x = 10\n")
+	m := New(doc)
+
+	tm, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = typeQuickCalc(tm.(Model), "x = 99")
+	if m.quickResult != "99" {
+		t.Errorf("quickResult = %q, want 99", m.quickResult)
+	}
+	if x, _ := m.eval.GetEnvironment().Get("x"); x.String() != "10" {
+		t.Errorf("document x = %s, want 10", x)
+	}
+}
+
+func TestQuickCalcInsert(t *testing.T) {
+	doc, _ := document.NewDocument("price = $20\nqty = 3\n")
+	m := New(doc)
+	m.cursorLine = 1
+
+	tm, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = typeQuickCalc(tm.(Model), "total = price * qty")
+	tm, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = tm.(Model)
+
+	if m.mode != ModeNormal {
+		t.Errorf("Enter should close the calculator, mode = %v", m.mode)
+	}
+	lines := m.GetLines()
+	if len(lines) < 3 || lines[2] != "total = price * qty" || m.cursorLine != 2 {
+		t.Fatalf("expected the expression inserted below the cursor, got lines %q, cursor %d", lines, m.cursorLine)
+	}
+	if total, ok := m.eval.GetEnvironment().Get("total"); !ok || total.String() != "$60.00" {
+		t.Errorf("inserted line should be evaluated, total = %v", total)
+	}
+	if !m.modified {
+		t.Error("inserting should mark the document modified")
+	}
+}
+
+// TestQuickCalcFromEditMode checks the calculator returns to the line being
+// edited, and inserting saves that line first.
+func TestQuickCalcFromEditMode(t *testing.T) {
+	doc, _ := document.NewDocument("x = 10\n")
+	m := New(doc)
+	m.enterEditMode()
+	m.editBuf = "x = 15"
+
+	tm, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = tm.(Model)
+	tm, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = tm.(Model)
+	if m.mode != ModeEditing || m.editBuf != "x = 15" {
+		t.Fatalf("closing should return to editing, mode = %v, buffer %q", m.mode, m.editBuf)
+	}
+
+	tm, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = typeQuickCalc(tm.(Model), "x * 2")
+	tm, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = tm.(Model)
+
+	if got := strings.Join(m.GetLines(), "\n"); !strings.HasPrefix(got, "x = 15\nx * 2") {
+		t.Errorf("lines = %q, want the edited line then the expression", got)
+	}
+}
@@ -0,0 +1,138 @@
+package editor
+
+// renderCache tracks damage between successive AlignedModel computations
+// so a redraw only re-renders the source lines that changed.
+//
+// Rendering a line (markdown through glamour, or a styled calc result) is
+// by far the most expensive part of View(), and most keystrokes change
+// one line, or none at all for cursor moves. The cache keeps:
+//   - the wrapped source and preview content of every line, keyed by all
+//     the inputs its rendering depends on (lineKey). A line whose key is
+//     not cached is damaged and rendered; every other line is reused, even
+//     when inserted lines above it have shifted its index
+//   - the last assembled model and the rows it was built from. When no
+//     row changed, the model is patched (cursor marks moved) rather than
+//     reassembled
+//
+// Renderings not used by a pass are dropped at its end, so the cache holds
+// one document's worth of lines. View() receives a value copy of Model, so
+// Model holds the cache by pointer and copies share it.
+type renderCache struct {
+	ctx   renderContext
+	lines map[lineKey]*lineRender
+	pass  int // Incremented per computation, for dropping unused renderings
+
+	// Last assembled model, the rows it was built from, and its cursor line
+	last       *AlignedModel
+	lastRows   []rowKey
+	lastCursor int
+
+	rendered int // Lines rendered (cache misses) by the last computation
+}
+
+// renderContext holds the inputs that affect every line's rendering. A
+// change, such as a resize, damages all lines.
+type renderContext struct {
+	sourceWidth  int
+	previewWidth int
+	previewMode  PreviewMode
+	tabWidth     int
+	locale       string // Number locale tag, for calculation detection
+}
+
+// lineKey identifies the rendering of one source line: its text and the
+// LineResult fields the renderers read.
+type lineKey struct {
+	line        string
+	source      string
+	varName     string
+	value       string
+	err         string
+	highlight   string
+	isCalc      bool
+	isCalcBlock bool
+	wasChanged  bool
+	stale       bool
+	compare     string
+	compareSign int
+}
+
+// rowKey identifies one source line's place in the aligned model.
+type rowKey struct {
+	lineKey
+	lineNum int
+	blockID string
+}
+
+// lineRender is a line's wrapped content in both panes.
+type lineRender struct {
+	source  []string
+	preview []string
+	pass    int // Last computation that used it
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{lines: make(map[lineKey]*lineRender)}
+}
+
+// begin starts a computation, discarding everything when ctx differs
+// from the previous one.
+func (c *renderCache) begin(ctx renderContext) {
+	if ctx != c.ctx {
+		c.ctx = ctx
+		c.lines = make(map[lineKey]*lineRender)
+		c.last = nil
+		c.lastRows = nil
+	}
+	c.pass++
+	c.rendered = 0
+}
+
+// lookup returns the cached rendering for key, or nil if the line is
+// damaged.
+func (c *renderCache) lookup(key lineKey) *lineRender {
+	lr := c.lines[key]
+	if lr != nil {
+		lr.pass = c.pass
+	}
+	return lr
+}
+
+// store records a line rendered in the current computation.
+func (c *renderCache) store(key lineKey, lr *lineRender) {
+	lr.pass = c.pass
+	c.lines[key] = lr
+	c.rendered++
+}
+
+// finish drops renderings the computation didn't use and remembers the
+// assembled model for patching.
+func (c *renderCache) finish(model AlignedModel, rows []rowKey, cursorLine int) {
+	for key, lr := range c.lines {
+		if lr.pass != c.pass {
+			delete(c.lines, key)
+		}
+	}
+	c.last = &model
+	c.lastRows = rows
+	c.lastCursor = cursorLine
+}
+
+// patch returns the last model with the cursor moved to cursorLine, if it
+// was assembled from exactly rows and lineCount source lines.
+func (c *renderCache) patch(rows []rowKey, lineCount, cursorLine int) (AlignedModel, bool) {
+	if c.last == nil || c.last.TotalSourceLines != lineCount || len(rows) != len(c.lastRows) {
+		return AlignedModel{}, false
+	}
+	for i := range rows {
+		if rows[i] != c.lastRows[i] {
+			return AlignedModel{}, false
+		}
+	}
+
+	// WithCursor copies before patching: earlier models may still be in use
+	model := c.last.WithCursor(c.lastCursor, cursorLine)
+	c.last = &model
+	c.lastCursor = cursorLine
+	return model, true
+}
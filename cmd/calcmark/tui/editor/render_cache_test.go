@@ -0,0 +1,198 @@
+package editor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// largeDocument returns a document of n sections, each a heading, a line
+// of prose, and a few calculations.
+func largeDocument(n int) string {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "## Section %d\n\nSome *notes* about section %d.\n\n", i, i)
+		fmt.Fprintf(&b, "a%d = %d\nb%d = a%d * 2\nc%d = b%d + 1.5\n\n", i, i, i, i, i, i)
+	}
+	return b.String()
+}
+
+// cachedInput returns an aligned model input for lines, where lines
+// containing "=" are calculations in one block.
+func cachedInput(lines []string, cursor int) AlignedModelInput {
+	input := AlignedModelInput{
+		Lines:              lines,
+		SourceContentWidth: 20,
+		PreviewWidth:       20,
+		CursorLine:         cursor,
+		PreviewMode:        PreviewFull,
+	}
+	for i, line := range lines {
+		r := LineResult{LineNum: i, Source: line, BlockID: fmt.Sprintf("text%d", i)}
+		if name, value, ok := strings.Cut(line, "="); ok {
+			r.IsCalc, r.BlockID = true, "calc"
+			r.VarName, r.Value = strings.TrimSpace(name), strings.TrimSpace(value)
+		}
+		input.Results = append(input.Results, r)
+	}
+	return input
+}
+
+func TestRenderCacheMatchesFreshComputation(t *testing.T) {
+	cache := newRenderCache()
+	steps := []AlignedModelInput{
+		cachedInput([]string{"# Title", "x = 1", "y = 2"}, 0),
+		cachedInput([]string{"# Title", "x = 1", "y = 2"}, 1),                                            // Cursor move
+		cachedInput([]string{"# Title", "x = 15", "y = 2"}, 1),                                           // Edit
+		cachedInput([]string{"# Title", "Some prose", "x = 15", "y = 2"}, 1),                             // Insert
+		cachedInput([]string{"# Title", "Some prose that is long enough to wrap", "x = 15", "y = 2"}, 1), // Wrap
+		cachedInput([]string{"# Title", "x = 15", "y = 2"}, 2),                                           // Delete and move
+	}
+	for i, input := range steps {
+		cache.begin(renderContext{sourceWidth: input.SourceContentWidth, previewWidth: input.PreviewWidth})
+		got := computeAlignedModel(input, cache, mockRenderCalcLine, mockRenderMarkdown)
+		want := ComputeAlignedModel(input, mockRenderCalcLine, mockRenderMarkdown)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("step %d: cached model differs from fresh computation\ngot  %+v\nwant %+v", i, got, want)
+		}
+	}
+}
+
+func TestRenderCacheRendersOnlyDamagedLines(t *testing.T) {
+	cache := newRenderCache()
+	ctx := renderContext{sourceWidth: 20, previewWidth: 20}
+	renders := 0
+	renderCalc := func(r LineResult, width int) string {
+		renders++
+		return mockRenderCalcLine(r, width)
+	}
+	renderMarkdown := func(line string, width int) []string {
+		renders++
+		return mockRenderMarkdown(line, width)
+	}
+	compute := func(input AlignedModelInput) AlignedModel {
+		renders = 0
+		cache.begin(ctx)
+		return computeAlignedModel(input, cache, renderCalc, renderMarkdown)
+	}
+
+	lines := []string{"# Title", "x = 1", "y = 2", "z = 3"}
+	compute(cachedInput(lines, 0))
+	if renders != 4 {
+		t.Errorf("first computation rendered %d lines, want 4", renders)
+	}
+
+	before := compute(cachedInput(lines, 0))
+	if renders != 0 {
+		t.Errorf("unchanged document rendered %d lines, want 0", renders)
+	}
+
+	after := compute(cachedInput(lines, 2))
+	if renders != 0 {
+		t.Errorf("cursor move rendered %d lines, want 0", renders)
+	}
+	if before.SourceLines[2].Kind != AlignedLineNormal || after.SourceLines[2].Kind != AlignedLineCursor {
+		t.Error("cursor move was not patched into the model")
+	}
+	if before.SourceLines[0].Kind != AlignedLineCursor || after.SourceLines[0].Kind != AlignedLineNormal {
+		t.Error("patching changed an earlier model")
+	}
+
+	compute(cachedInput([]string{"# Title", "x = 1", "y = 20", "z = 3"}, 2))
+	if renders != 1 {
+		t.Errorf("one edited line rendered %d lines, want 1", renders)
+	}
+
+	compute(cachedInput([]string{"# Title", "new", "x = 1", "y = 20", "z = 3"}, 2))
+	if renders != 1 {
+		t.Errorf("one inserted line rendered %d lines, want 1", renders)
+	}
+
+	ctx.previewWidth = 30
+	compute(cachedInput([]string{"# Title", "new", "x = 1", "y = 20", "z = 3"}, 2))
+	if renders != 5 {
+		t.Errorf("resize rendered %d lines, want 5", renders)
+	}
+	if len(cache.lines) != 5 {
+		t.Errorf("cache holds %d lines, want 5", len(cache.lines))
+	}
+}
+
+func TestViewReusesRenderedLines(t *testing.T) {
+	doc, err := document.NewDocument(largeDocument(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(doc)
+	first := m.View()
+
+	m.View()
+	if m.renderCache.rendered != 0 {
+		t.Errorf("redraw rendered %d lines, want 0", m.renderCache.rendered)
+	}
+
+	m.cursorLine = 5
+	moved := m.View()
+	if m.renderCache.rendered != 0 {
+		t.Errorf("cursor move rendered %d lines, want 0", m.renderCache.rendered)
+	}
+	if moved == first {
+		t.Error("cursor move did not change the view")
+	}
+
+	fresh := m
+	fresh.renderCache = nil
+	if got, want := moved, fresh.View(); got != want {
+		t.Errorf("cached view differs from uncached view:\n%s\nwant\n%s", got, want)
+	}
+}
+
+// BenchmarkViewLargeDocument measures a redraw after a cursor move in a
+// large document on a tall terminal.
+func BenchmarkViewLargeDocument(b *testing.B) {
+	doc, err := document.NewDocument(largeDocument(200))
+	if err != nil {
+		b.Fatal(err)
+	}
+	m := New(doc)
+	m.width, m.height = 200, 120
+	lines := len(m.GetLines())
+	m.View()
+
+	b.ResetTimer()
+	for i := range b.N {
+		m.cursorLine = i % lines
+		m.View()
+	}
+}
+
+// BenchmarkViewHugeDocument measures a redraw after a cursor move in the
+// middle of a 20,000-line document of mostly prose.
+func BenchmarkViewHugeDocument(b *testing.B) {
+	var source strings.Builder
+	for i := range 1000 {
+		fmt.Fprintf(&source, "## Section %d\n\n", i)
+		for j := range 14 {
+			fmt.Fprintf(&source, "Line %d of the *notes* for section %d.\n", j, i)
+		}
+		fmt.Fprintf(&source, "\na%d = %d\nb%d = a%d * 2\n\n", i, i, i, i)
+	}
+	doc, err := document.NewDocument(source.String())
+	if err != nil {
+		b.Fatal(err)
+	}
+	m := New(doc)
+	m.width, m.height = 200, 120
+	lines := len(m.GetLines())
+	m.View()
+
+	b.ResetTimer()
+	for i := range b.N {
+		m.cursorLine = lines/2 + i%100
+		m.cursorRow = 10
+		m.View()
+	}
+}
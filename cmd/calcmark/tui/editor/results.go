@@ -1,8 +1,10 @@
 package editor
 
 import (
+	"math"
 	"strings"
 
+	"github.com/CalcMark/go-calcmark/format"
 	"github.com/CalcMark/go-calcmark/format/display"
 	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/document"
@@ -20,21 +22,52 @@ type LineResult struct {
 	Diagnostic *document.Diagnostic // Structured diagnostic with code, message, position
 	BlockID    string
 	WasChanged bool
+	Highlight  string // Color from the frontmatter highlight rules, "" if none
+	Stale      bool   // Block timed out; Value is from an earlier evaluation
+
+	// Compare is the value's change from the /compare results, e.g.
+	// "was $1,000.00  +$200.00 (+20.0%)"; CompareSign is the change's sign
+	Compare     string
+	CompareSign int
 }
 
 // GetLineResults returns evaluation results for all lines.
 // Each source line maps to its corresponding statement result when available.
 func (m *Model) GetLineResults() []LineResult {
+	return m.lineResultsInRange(0, math.MaxInt)
+}
+
+// lineResultsInRange returns the results for lines from through to-1.
+// Blocks outside the range are skipped, so the cost is proportional to
+// the range rather than the document.
+func (m *Model) lineResultsInRange(from, to int) []LineResult {
 	var results []LineResult
 	lineNum := 0
+	displayOpts := format.DisplayOptions(m.doc)
+
+	// add records a result, if its line is in range
+	add := func(lr LineResult) {
+		if lr.LineNum >= from && lr.LineNum < to {
+			results = append(results, lr)
+		}
+	}
 
 	for _, node := range m.doc.GetBlocks() {
+		if lineNum >= to {
+			break
+		}
+		if n := len(node.Block.Source()); lineNum+n <= from {
+			lineNum += n
+			continue
+		}
+
 		switch b := node.Block.(type) {
 		case *document.CalcBlock:
 			sourceLines := b.Source()
 			vars := b.Variables()
-			stmtResults := b.Results()   // Per-statement results
-			statements := b.Statements() // Parsed AST nodes
+			stmtResults := b.Results()        // Per-statement results
+			statements := b.Statements()      // Parsed AST nodes
+			highlights := m.doc.Highlights(b) // Per-statement highlight colors
 			blockError := b.Error()
 
 			// Build a map of variable index for lookup
@@ -66,6 +99,7 @@ func (m *Model) GetLineResults() []LineResult {
 					IsCalc:     true,
 					BlockID:    node.ID,
 					WasChanged: m.changedBlockIDs[node.ID],
+					Stale:      b.IsStale(),
 				}
 
 				// Skip empty/whitespace-only lines (no result to show)
@@ -77,7 +111,7 @@ func (m *Model) GetLineResults() []LineResult {
 					}
 				}
 				if len(trimmed) == 0 || trimmed == "" {
-					results = append(results, lr)
+					add(lr)
 					lineNum++
 					continue
 				}
@@ -91,7 +125,7 @@ func (m *Model) GetLineResults() []LineResult {
 				if diag, hasError := diagByLine[blockLineNum]; hasError {
 					lr.Diagnostic = diag
 					lr.Error = diag.Code + ": " + diag.Message // Legacy string for backwards compat
-					results = append(results, lr)
+					add(lr)
 					lineNum++
 					continue
 				}
@@ -107,15 +141,19 @@ func (m *Model) GetLineResults() []LineResult {
 					}
 					if showErrorHere {
 						lr.Error = blockError.Error()
-						results = append(results, lr)
+						add(lr)
 						lineNum++
 						continue
 					}
 				}
 
-				// Get result for this statement if available
-				if stmtIdx < len(stmtResults) && stmtResults[stmtIdx] != nil {
-					lr.Value = display.Format(stmtResults[stmtIdx])
+				// Get result for this statement if available (formatting is the
+				// costly part, so only for lines in range)
+				if stmtIdx < len(stmtResults) && stmtResults[stmtIdx] != nil && lineNum >= from && lineNum < to {
+					lr.Value = display.FormatWith(stmtResults[stmtIdx], displayOpts)
+				}
+				if stmtIdx < len(highlights) {
+					lr.Highlight = highlights[stmtIdx]
 				}
 
 				// Get variable name if this statement defines one
@@ -129,13 +167,18 @@ func (m *Model) GetLineResults() []LineResult {
 					}
 				}
 
-				results = append(results, lr)
+				if m.compare != nil && lr.VarName != "" && lr.Value != "" {
+					current := format.ValueRow(stmtResults[stmtIdx], displayOpts)
+					lr.Compare, lr.CompareSign = m.compare.delta(lr.VarName, current, displayOpts)
+				}
+
+				add(lr)
 				lineNum++
 			}
 
 		case *document.TextBlock:
 			for _, line := range b.Source() {
-				results = append(results, LineResult{
+				add(LineResult{
 					LineNum: lineNum,
 					Source:  line,
 					IsCalc:  false,
@@ -208,3 +251,14 @@ func getAssignmentVarName(node ast.Node) string {
 	}
 	return ""
 }
+
+// highlightColors maps frontmatter highlight colors to terminal colors.
+var highlightColors = map[string]string{
+	"red":    "9",
+	"orange": "208",
+	"yellow": "11",
+	"green":  "10",
+	"blue":   "12",
+	"purple": "13",
+	"gray":   "245",
+}
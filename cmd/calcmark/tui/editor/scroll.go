@@ -0,0 +1,115 @@
+package editor
+
+// Scrolling works in visual lines, the rows the panes actually show: a
+// source line takes one or more of them once wrapping and alignment padding
+// are applied. The scroll position is the cursor's screen row (cursorRow),
+// so the top of the panes is that many visual lines above the cursor. This
+// anchors the view to the cursor: when lines above it wrap or unwrap, the
+// cursor keeps its place on screen and the lines above it move instead.
+//
+// Only the lines between two positions are ever laid out to convert between
+// source and visual lines, so scrolling costs the same in any size document.
+
+// visibleHeight returns the number of visual lines the panes show.
+func (m Model) visibleHeight() int {
+	_, source, _ := m.paneHeights()
+	return source
+}
+
+// scrollLayout returns the aligned layout of source lines [from, to) at the
+// current pane widths. Its visual lines count from the first line of from.
+func (m Model) scrollLayout(from, to int) AlignedModel {
+	leftWidth, rightWidth := m.GetPaneWidths(m.width)
+	return m.computeAlignedModelRange(leftWidth, rightWidth, from, to)
+}
+
+// visualDistance returns the number of visual lines from the first visual
+// line of source line from to the first visual line of source line to (or
+// the end of the document, if to is past its last line), negative if to is
+// above from.
+func (m Model) visualDistance(from, to int) int {
+	if to < from {
+		return -m.visualDistance(to, from)
+	}
+	return m.scrollLayout(from, to).TotalVisualLines
+}
+
+// sourceLineAtDistance returns the source line shown rows visual lines below
+// the first visual line of source line from (above, if rows is negative),
+// stopping at the first or last line of the document.
+func (m Model) sourceLineAtDistance(from, rows int) int {
+	total := m.TotalLines()
+	if total == 0 {
+		return 0
+	}
+
+	// Every source line takes at least one visual line, so |rows| source
+	// lines always cover the distance
+	if rows >= 0 {
+		to := min(total, from+rows+1)
+		aligned := m.scrollLayout(from, to)
+		if line := aligned.SourceLineAt(rows); line >= 0 {
+			return line
+		}
+		return to - 1
+	}
+	top := max(0, from+rows)
+	aligned := m.scrollLayout(top, from+1)
+	if line := aligned.SourceLineAt(aligned.CursorVisualLine(from) + rows); line >= 0 {
+		return line
+	}
+	return top
+}
+
+// scrollStart returns the first visual line of aligned for a pane of the
+// given height to show, putting the cursor on its screen row unless that
+// would leave rows empty at either end of the document.
+func (m Model) scrollStart(aligned alignedPanes, height int) int {
+	cursorVisual, ok := aligned.sourceToVisual[m.cursorLine]
+	if !ok {
+		return 0
+	}
+	row := max(0, min(m.cursorRow, height-1))
+	return max(0, min(cursorVisual-row, len(aligned.sourceLines)-height))
+}
+
+// followCursor updates the scroll position after the cursor moved from
+// source line prev: the cursor's screen row moves with it by the visual
+// lines between the two, and the panes scroll only when it would leave the
+// screen.
+func (m *Model) followCursor(prev int) {
+	height := m.visibleHeight()
+	switch {
+	case m.cursorLine-prev >= height:
+		m.cursorRow = height - 1
+	case prev-m.cursorLine >= height:
+		m.cursorRow = 0
+	default:
+		m.cursorRow += m.visualDistance(prev, m.cursorLine)
+	}
+	m.clampScroll()
+}
+
+// clampScroll keeps the cursor's screen row on screen, and matches it to
+// scrollStart's at the ends of the document: low enough that the panes don't
+// scroll past the last line, and then high enough that they don't scroll
+// above the first.
+func (m *Model) clampScroll() {
+	height := m.visibleHeight()
+	m.cursorRow = max(0, min(m.cursorRow, height-1))
+	if total := m.TotalLines(); total-m.cursorLine < height {
+		m.cursorRow = max(m.cursorRow, height-m.visualDistance(m.cursorLine, total))
+	}
+	if m.cursorLine < height {
+		m.cursorRow = min(m.cursorRow, m.visualDistance(0, m.cursorLine))
+	}
+}
+
+// movePage moves the cursor rows visual lines down (up, if negative),
+// scrolling the panes with it so the cursor keeps its screen row.
+func (m *Model) movePage(rows int) {
+	row := m.cursorRow
+	m.moveCursor(m.sourceLineAtDistance(m.cursorLine, rows)-m.cursorLine, 0)
+	m.cursorRow = row
+	m.clampScroll()
+}
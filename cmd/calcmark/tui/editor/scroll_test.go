@@ -0,0 +1,183 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const wrappingLine = "Some prose that runs on well past the width of the source pane, so it wraps onto more lines."
+
+// newScrollModel returns a model of lines source lines, every third of them
+// prose that wraps, in a pane showing fewer lines than the document.
+func newScrollModel(t *testing.T, lines int) Model {
+	t.Helper()
+	var content strings.Builder
+	for i := range lines {
+		if i%3 == 1 {
+			content.WriteString(wrappingLine + "\n")
+		} else {
+			fmt.Fprintf(&content, "x%d = %d\n", i, i)
+		}
+	}
+	doc, err := document.NewDocument(content.String())
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	m := New(doc)
+	m.width, m.height = 80, 20
+	return m
+}
+
+// screenRow returns the row of the panes the cursor is drawn on.
+func screenRow(m Model) int {
+	leftWidth, rightWidth := m.GetPaneWidths(m.width)
+	height := m.visibleHeight()
+	aligned := m.computeVisiblePanes(leftWidth, rightWidth, height)
+	return aligned.sourceToVisual[m.cursorLine] - m.scrollStart(aligned, height)
+}
+
+// press sends keys to the model in turn.
+func press(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		tm, _ := m.handleKey(key)
+		m = tm.(Model)
+	}
+	return m
+}
+
+func TestScrollConversions(t *testing.T) {
+	m := newScrollModel(t, 30)
+	leftWidth, rightWidth := m.GetPaneWidths(m.width)
+	full := m.computeAlignedModelFresh(leftWidth, rightWidth)
+
+	total := m.TotalLines()
+	for from := range total {
+		for _, to := range []int{0, from, total - 1} {
+			want := full.CursorVisualLine(to) - full.CursorVisualLine(from)
+			if got := m.visualDistance(from, to); got != want {
+				t.Errorf("visualDistance(%d, %d) = %d, want %d", from, to, got, want)
+			}
+		}
+		for _, rows := range []int{-100, -5, -1, 0, 1, 4, 100} {
+			visual := full.CursorVisualLine(from) + rows
+			visual = max(0, min(visual, full.TotalVisualLines-1))
+			if got, want := m.sourceLineAtDistance(from, rows), full.SourceLineAt(visual); got != want {
+				t.Errorf("sourceLineAtDistance(%d, %d) = %d, want %d", from, rows, got, want)
+			}
+		}
+	}
+	if got := m.visualDistance(0, total); got != full.TotalVisualLines {
+		t.Errorf("visualDistance to the end = %d, want %d", got, full.TotalVisualLines)
+	}
+}
+
+// TestScrollFollowsCursor checks the cursor moves down the screen until it
+// reaches the bottom, then the panes scroll by the visual lines it moves.
+func TestScrollFollowsCursor(t *testing.T) {
+	m := newScrollModel(t, 60)
+	height := m.visibleHeight()
+
+	for range 40 {
+		prevRow, prevScroll, prevLine := screenRow(m), m.ScrollOffset(), m.cursorLine
+		m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+		moved := m.visualDistance(prevLine, m.cursorLine)
+
+		if row := screenRow(m); row != m.cursorRow || row < 0 || row >= height {
+			t.Fatalf("line %d: cursor drawn on row %d, tracked as %d", m.cursorLine, row, m.cursorRow)
+		}
+		if prevRow+moved < height {
+			if m.ScrollOffset() != prevScroll {
+				t.Errorf("line %d: panes scrolled while the cursor was on screen", m.cursorLine)
+			}
+		} else if m.cursorRow != height-1 {
+			t.Errorf("line %d: cursor on row %d after scrolling, want the last row %d", m.cursorLine, m.cursorRow, height-1)
+		}
+	}
+}
+
+// TestScrollAnchoredToCursor checks the cursor keeps its place on screen when
+// a line above it (line 28, which starts out wrapping) wraps more or unwraps.
+func TestScrollAnchoredToCursor(t *testing.T) {
+	m := newScrollModel(t, 60)
+	m.cursorLine, m.cursorRow = 30, 8
+	m.clampScroll()
+	before := m.ScrollOffset()
+
+	for _, line := range []string{wrappingLine + " " + wrappingLine, "short = 1", wrappingLine} {
+		lines := m.GetLines()
+		lines[28] = line
+		if err := m.reparse(strings.Join(lines, "\n")); err != nil {
+			t.Fatal(err)
+		}
+		if row := screenRow(m); row != 8 {
+			t.Errorf("after changing line 28 to %q, cursor drawn on row %d, want 8", line, row)
+		}
+	}
+	if m.ScrollOffset() != before {
+		t.Errorf("scroll offset = %d after restoring the line, want %d", m.ScrollOffset(), before)
+	}
+}
+
+// TestScrollPageMoves checks page movements are measured in visual lines,
+// keeping the cursor on its row.
+func TestScrollPageMoves(t *testing.T) {
+	m := newScrollModel(t, 90)
+	height := m.visibleHeight()
+	m.cursorLine, m.cursorRow = 30, 4
+
+	tests := []struct {
+		key  tea.KeyType
+		rows int
+	}{
+		{tea.KeyPgDown, height},
+		{tea.KeyPgUp, -height},
+		{tea.KeyCtrlU, -height / 2},
+	}
+	for _, tt := range tests {
+		from := m.cursorLine
+		want := m.sourceLineAtDistance(from, tt.rows)
+		m = press(m, tea.KeyMsg{Type: tt.key})
+		if m.cursorLine != want {
+			t.Errorf("%v from line %d: cursor on line %d, want %d", tt.key, from, m.cursorLine, want)
+		}
+		if got := m.visualDistance(from, m.cursorLine); got < tt.rows-2 || got > tt.rows+2 {
+			// A wrapped line may start up to a line either side of the target
+			t.Errorf("%v from line %d moved %d visual lines, want about %d", tt.key, from, got, tt.rows)
+		}
+		if row := screenRow(m); row != 4 {
+			t.Errorf("%v from line %d: cursor drawn on row %d, want 4", tt.key, from, row)
+		}
+	}
+}
+
+// TestScrollDocumentEnds checks the panes never scroll past the first or
+// last line of the document.
+func TestScrollDocumentEnds(t *testing.T) {
+	m := newScrollModel(t, 60)
+	height := m.visibleHeight()
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	total := m.visualDistance(0, m.TotalLines())
+	if got, want := m.ScrollOffset(), total-height; got != want {
+		t.Errorf("at the last line, scroll offset = %d, want %d", got, want)
+	}
+	if row := screenRow(m); row != m.cursorRow {
+		t.Errorf("at the last line, cursor drawn on row %d, tracked as %d", row, m.cursorRow)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	if got, want := m.ScrollOffset(), total-height; got != want {
+		t.Errorf("paging past the end, scroll offset = %d, want %d", got, want)
+	}
+
+	m.cursorLine, m.cursorRow = 3, 0
+	m = press(m, tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.cursorLine != 0 || m.ScrollOffset() != 0 || screenRow(m) != 0 {
+		t.Errorf("paging above the start: cursor line %d, scroll offset %d, row %d; want all 0",
+			m.cursorLine, m.ScrollOffset(), screenRow(m))
+	}
+}
@@ -0,0 +1,181 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Split view: /split <file> opens a second document in a pane beside the
+// first, an editor of its own with its own cursor, mode, and undo history.
+// Ctrl-W switches which pane keys go to, as does clicking in the other
+// pane. The panes share the yank buffer, so lines yanked with yy, V y, or
+// yB (the block under the cursor) in one paste into the other with p.
+// /split off closes the pane without focus.
+//
+// The focused editor is the model itself and holds the other in split;
+// switching focus swaps them, so everything else the editor does needs no
+// knowledge of splits.
+
+// splitView is the other pane of a split.
+type splitView struct {
+	other *Model // The editor without focus; never itself split
+	right bool   // The focused editor's pane is the right one
+	width int    // Width of both panes and the divider between them
+}
+
+// splitFile opens path in a pane beside the document.
+func (m *Model) splitFile(path string) {
+	if path == "" {
+		m.statusMsg = "Usage: /split <file> or /split off"
+		m.statusIsErr = true
+		return
+	}
+	if path == "off" {
+		m.closeSplit()
+		return
+	}
+	if m.split != nil && m.split.other.modified {
+		m.statusMsg = "The other pane has unsaved changes; Ctrl-W to it and /save first"
+		m.statusIsErr = true
+		return
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Invalid path: %v", err)
+		m.statusIsErr = true
+		return
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Split failed: %v", err)
+		m.statusIsErr = true
+		return
+	}
+	doc, err := document.NewDocument(string(content))
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Parse error: %v", err)
+		m.statusIsErr = true
+		return
+	}
+
+	width := m.width
+	if m.split != nil {
+		width = m.split.width
+	}
+	other := NewWithFile(absPath, doc)
+	other.inactive = true
+	m.split = &splitView{other: &other, width: width}
+	m.layoutSplit(width, m.height)
+	m.statusMsg = fmt.Sprintf("Split: %s (Ctrl-W switches panes)", filepath.Base(absPath))
+}
+
+// closeSplit closes the pane without focus, unless it has unsaved changes.
+func (m *Model) closeSplit() {
+	if m.split == nil {
+		m.statusMsg = "Not split"
+		return
+	}
+	if m.split.other.modified {
+		m.statusMsg = "The other pane has unsaved changes; Ctrl-W to it and /save first"
+		m.statusIsErr = true
+		return
+	}
+	m.width = m.split.width
+	m.split = nil
+	m.InvalidateAlignedCache()
+	m.statusMsg = "Split closed"
+}
+
+// layoutSplit sizes both panes to share width, the left one taking any odd
+// column, with one between them for the divider.
+func (m *Model) layoutSplit(width, height int) {
+	left := width / 2
+	right := max(0, width-left-1)
+	other := *m.split.other
+	if m.split.right {
+		m.width, other.width = right, left
+	} else {
+		m.width, other.width = left, right
+	}
+	m.height, other.height = height, height
+	other.InvalidateAlignedCache()
+	if other.mode == ModeBlockEdit {
+		other.sizeBlockEditor()
+	}
+	m.split = &splitView{other: &other, right: m.split.right, width: width}
+	m.InvalidateAlignedCache()
+}
+
+// switchSplit returns the editor with the other pane focused, carrying the
+// yank buffer and session trace over.
+func (m Model) switchSplit() Model {
+	if m.split == nil {
+		m.statusMsg = "Not split (use /split <file>)"
+		m.statusIsErr = true
+		return m
+	}
+	focused := *m.split.other
+	split := m.split
+	m.split = nil
+	m.inactive = true
+	m.pendingKey = 0
+
+	focused.inactive = false
+	focused.yankBuffer = m.yankBuffer
+	focused.trace, m.trace = m.trace, nil
+	focused.split = &splitView{other: &m, right: !split.right, width: split.width}
+	focused.statusMsg = ""
+	focused.statusIsErr = false
+	focused.InvalidateAlignedCache()
+	return focused
+}
+
+// paneX returns the screen column the focused pane starts at.
+func (m Model) paneX() int {
+	if m.split == nil || !m.split.right {
+		return 0
+	}
+	return m.split.other.width + 1
+}
+
+// handleSplitMouse handles a mouse event in split view: in the focused
+// pane as without a split, and a press in the other pane switches focus
+// to it first.
+func (m Model) handleSplitMouse(msg tea.MouseMsg) Model {
+	if msg.X == m.split.width/2 {
+		return m // On the divider
+	}
+	if x := m.paneX(); msg.X < x || msg.X >= x+m.width {
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || m.mode != ModeNormal {
+			return m
+		}
+		m = m.switchSplit()
+	}
+	msg.X -= m.paneX()
+	return m.handleMouse(msg)
+}
+
+// renderSplit renders both panes with a divider between them.
+func (m Model) renderSplit() string {
+	focused := m
+	focused.split = nil
+	mine := focused.View()
+	other := m.split.other.View()
+
+	height := max(lipgloss.Height(mine), lipgloss.Height(other))
+	divider := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+
+	if m.split.right {
+		return lipgloss.JoinHorizontal(lipgloss.Top, other, divider, mine)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, mine, divider, other)
+}
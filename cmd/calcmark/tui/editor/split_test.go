@@ -0,0 +1,123 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var keyCtrlW = tea.KeyMsg{Type: tea.KeyCtrlW}
+
+// newSplitModel returns a 100-column editor on one document split with
+// another, rates.cm, with the first focused.
+func newSplitModel(t *testing.T) Model {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rates.cm")
+	if err := os.WriteFile(path, []byte("rate = 5%\nfee = $20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := document.NewDocument("price = $100\n\nSome notes.\n\ntotal = price * 2\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, _ := New(doc).Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	m := model.(Model)
+	m.executeCommand("/split " + path)
+	if m.statusIsErr || m.split == nil {
+		t.Fatalf("/split failed: %q", m.statusMsg)
+	}
+	return m
+}
+
+func TestSplitShowsBothDocuments(t *testing.T) {
+	m := newSplitModel(t)
+	if m.width != 50 || m.split.other.width != 49 {
+		t.Errorf("pane widths %d and %d, want 50 and 49", m.width, m.split.other.width)
+	}
+
+	view := m.View()
+	for _, want := range []string{"price = $100", "fee = $20", "│", "SPLIT"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("split view doesn't show %q:\n%s", want, view)
+		}
+	}
+	for i, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 100 {
+			t.Errorf("line %d is %d columns wide, more than the screen", i, w)
+		}
+	}
+}
+
+func TestSplitSwitchesFocusAndSharesYanks(t *testing.T) {
+	m := newSplitModel(t)
+	m = press(m, runes("jjjjyB")...)
+	if m.statusMsg != "Block yanked (2 lines)" {
+		t.Errorf("yB status %q", m.statusMsg)
+	}
+
+	m = press(m, keyCtrlW)
+	if m.doc.GetBlocks()[0].Block.Source()[0] != "rate = 5%" {
+		t.Fatal("Ctrl-W didn't focus the other document")
+	}
+	if !m.split.right || m.split.other.cursorLine != 4 || !m.split.other.inactive {
+		t.Errorf("the first document's pane moved or lost its cursor")
+	}
+
+	m = press(m, runes("jp")...)
+	if got := strings.Join(m.GetLines(), "\n"); !strings.HasPrefix(got, "rate = 5%\nfee = $20\ntotal = price * 2\n") {
+		t.Errorf("after pasting, the other document is %q", got)
+	}
+	if !m.modified || m.split.other.modified {
+		t.Error("pasting changed the wrong document")
+	}
+
+	// Back in the first document, nothing changed and the cursor stayed
+	m = press(m, keyCtrlW)
+	if m.cursorLine != 4 || m.modified || m.split.right {
+		t.Errorf("back in the first document: line %d, modified %v", m.cursorLine, m.modified)
+	}
+}
+
+func TestSplitClickFocusesOtherPane(t *testing.T) {
+	m := newSplitModel(t)
+	x, y := -1, -1
+	for row, line := range strings.Split(m.View(), "\n") {
+		if i := strings.Index(line, "fee = $20"); i >= 0 {
+			x, y = lipgloss.Width(line[:i]), row
+		}
+	}
+	if x < 50 {
+		t.Fatalf("fee = $20 is at column %d, not in the right pane", x)
+	}
+	model, _ := m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	m = model.(Model)
+	if m.filepath == "" || filepath.Base(m.filepath) != "rates.cm" || m.cursorLine != 1 {
+		t.Errorf("clicking fee focused %q at line %d", m.filepath, m.cursorLine)
+	}
+}
+
+func TestSplitOff(t *testing.T) {
+	m := newSplitModel(t)
+	m = press(m, keyCtrlW)
+	m = press(m, runes("dd")...)
+	m = press(m, keyCtrlW)
+
+	m.executeCommand("/split off")
+	if m.split == nil || !m.statusIsErr {
+		t.Fatal("/split off closed a pane with unsaved changes")
+	}
+
+	// Saved, it closes
+	m = press(m, keyCtrlW)
+	m.executeCommand("/save")
+	m = press(m, keyCtrlW)
+	m.executeCommand("/split off")
+	if m.split != nil || m.width != 100 {
+		t.Errorf("/split off left split %v, width %d", m.split != nil, m.width)
+	}
+}
@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"io"
+	"slices"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/trace"
+)
+
+// Session trace (cm edit --trace): every change to the document is
+// written with the results after it, for `calcmark replay` to step
+// through later. A step is recorded after any message that changed the
+// lines or the results, so a result that changed on its own, a stale
+// block finishing say, shows up as a step with no edit.
+
+// traceRecorder holds what was last recorded, to diff the next step
+// against.
+type traceRecorder struct {
+	w       *trace.Writer
+	lines   []string
+	results []trace.Result
+	err     error // Set once a write fails; recording stops
+}
+
+// TraceTo starts recording the session to w, beginning with the document
+// as it is now.
+func (m *Model) TraceTo(w io.Writer) error {
+	rec := &traceRecorder{w: trace.NewWriter(w)}
+	lines, results := m.GetLines(), m.traceResults()
+	if err := rec.w.Write(&trace.Edit{Inserted: lines}, results); err != nil {
+		return err
+	}
+	rec.lines, rec.results = slices.Clone(lines), results
+	m.trace = rec
+	return nil
+}
+
+// recordTrace records a step if the lines or results changed since the
+// last one.
+func (m *Model) recordTrace() {
+	rec := m.trace
+	if rec.err != nil {
+		return
+	}
+	lines, results := m.GetLines(), m.traceResults()
+	edit, changed := diffLines(rec.lines, lines)
+	if !changed && slices.Equal(results, rec.results) {
+		return
+	}
+
+	var step *trace.Edit
+	if changed {
+		step = &trace.Edit{At: edit.at, Removed: edit.removed, Inserted: edit.inserted}
+	}
+	if err := rec.w.Write(step, results); err != nil {
+		rec.err = err
+		m.statusMsg = "Trace stopped: " + err.Error()
+		m.statusIsErr = true
+		return
+	}
+	rec.lines, rec.results = slices.Clone(lines), results
+}
+
+// traceResults returns the document's calculation results as a trace
+// records them.
+func (m *Model) traceResults() []trace.Result {
+	var results []trace.Result
+	for _, lr := range m.GetLineResults() {
+		if !lr.IsCalc || (lr.Value == "" && lr.Error == "") {
+			continue
+		}
+		r := trace.Result{Line: lr.LineNum + 1, Source: lr.Source, Var: lr.VarName, Value: lr.Value, Error: lr.Error}
+		if lr.Error != "" && lr.Diagnostic != nil {
+			r.Error = lr.Diagnostic.Message
+		}
+		results = append(results, r)
+	}
+	return results
+}
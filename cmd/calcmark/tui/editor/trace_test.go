@@ -0,0 +1,90 @@
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/trace"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// send passes keys through Update, as the program does.
+func send(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		tm, _ := m.Update(key)
+		m = tm.(Model)
+	}
+	return m
+}
+
+func TestTraceRecordsEditsAndResults(t *testing.T) {
+	doc, _ := document.NewDocument("a = 1\nb = a * 2")
+	m := New(doc)
+	var buf bytes.Buffer
+	if err := m.TraceTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	m = send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}}, keyEsc)
+	m = send(m, tea.KeyMsg{Type: tea.KeyDown}) // Moving the cursor records nothing
+	m = send(m, keyUndo)
+
+	tr, err := trace.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Steps) != 3 {
+		t.Fatalf("recorded %d steps, want opening, the edit, and its undo", len(tr.Steps))
+	}
+	if got := tr.Lines(1); !slices.Equal(got, []string{"a = 10", "b = a * 2"}) {
+		t.Errorf("document after the edit = %q", got)
+	}
+	if got := tr.Lines(2); !slices.Equal(got, tr.Lines(0)) {
+		t.Errorf("document after undo = %q, want it as opened", got)
+	}
+	var changes []string
+	for _, c := range tr.Changes(1) {
+		changes = append(changes, c.String())
+	}
+	if !slices.Equal(changes, []string{"a: 1 → 10", "b: 2 → 20"}) {
+		t.Errorf("edit changed %q", changes)
+	}
+}
+
+// failingWriter fails writes once fail is set.
+type failingWriter struct {
+	fail bool
+	bytes.Buffer
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("disk full")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestTraceStopsOnWriteError(t *testing.T) {
+	doc, _ := document.NewDocument("a = 1")
+	m := New(doc)
+	w := &failingWriter{}
+	if err := m.TraceTo(w); err != nil {
+		t.Fatal(err)
+	}
+
+	w.fail = true
+	m = send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if !m.statusIsErr || m.trace.err == nil {
+		t.Fatalf("write error not reported, status %q", m.statusMsg)
+	}
+
+	// Later changes are no longer written
+	w.fail = false
+	send(m, keyUndo)
+	if tr, err := trace.Read(&w.Buffer); err != nil || len(tr.Steps) != 1 {
+		t.Errorf("trace after the error = %v, %v; want only the opening step", tr, err)
+	}
+}
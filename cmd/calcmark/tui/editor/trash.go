@@ -0,0 +1,119 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// Trash: dB moves the block under the cursor to the document's trash, as dd
+// does a block when it deletes the block's last line. /trash lists the
+// trash and /restore puts a block back, whatever has happened to the undo
+// stack since.
+
+// trashListed is how many trashed blocks /trash lists.
+const trashListed = 5
+
+// deleteBlock moves the block under the cursor to the trash.
+func (m *Model) deleteBlock() {
+	node, start, ok := m.blockAt(m.cursorLine)
+	if !ok {
+		return
+	}
+	lines := len(node.Block.Source())
+	if _, err := m.doc.SoftDeleteBlock(node.ID); err != nil {
+		m.statusMsg = fmt.Sprintf("Delete failed: %v", err)
+		m.statusIsErr = true
+		return
+	}
+	m.afterTrashChange()
+
+	prev := m.cursorLine
+	m.cursorLine = max(0, min(start, m.TotalLines()-1))
+	m.cursorCol = 0
+	m.followCursor(prev)
+	m.statusMsg = fmt.Sprintf("Block moved to trash (%d lines); /restore to bring it back", lines)
+}
+
+// showTrash lists the most recently trashed blocks in the status bar,
+// numbered for /restore.
+func (m *Model) showTrash() {
+	trash := m.doc.Trash()
+	if len(trash) == 0 {
+		m.statusMsg = "Trash is empty"
+		return
+	}
+	var items []string
+	for i, t := range trash[:min(len(trash), trashListed)] {
+		items = append(items, fmt.Sprintf("%d) %s (%s ago)", i+1, trashSummary(t), time.Since(t.DeletedAt).Round(time.Second)))
+	}
+	m.statusMsg = "Trash: " + strings.Join(items, "  ")
+}
+
+// restoreFromTrash restores the nth most recently trashed block, from 1,
+// and moves the cursor to it.
+func (m *Model) restoreFromTrash(arg string) {
+	trash := m.doc.Trash()
+	n := 1
+	if arg != "" {
+		var err error
+		if n, err = strconv.Atoi(arg); err != nil || n < 1 {
+			m.statusMsg = "Usage: /restore [n], n from /trash"
+			m.statusIsErr = true
+			return
+		}
+	}
+	if n > len(trash) {
+		m.statusMsg = fmt.Sprintf("Trash has %d blocks", len(trash))
+		m.statusIsErr = true
+		return
+	}
+
+	entry := trash[n-1]
+	if _, err := m.doc.RestoreBlock(entry.Node.ID); err != nil {
+		m.statusMsg = fmt.Sprintf("Restore failed: %v", err)
+		m.statusIsErr = true
+		return
+	}
+	m.afterTrashChange()
+
+	prev := m.cursorLine
+	line := 0
+	for _, node := range m.doc.GetBlocks() {
+		if node.ID == entry.Node.ID {
+			break
+		}
+		line += len(node.Block.Source())
+	}
+	m.cursorLine, m.cursorCol = line, 0
+	m.followCursor(prev)
+	m.statusMsg = "Restored " + trashSummary(entry)
+}
+
+// afterTrashChange records a block moved to or from the trash. Moving
+// definitions can change any value, so the document is evaluated in full.
+func (m *Model) afterTrashChange() {
+	m.changedBlockIDs = make(map[string]bool)
+	m.modified = true
+	m.pushUndoState()
+	m.eval = newEvaluator(m.filepath)
+	_ = m.eval.Evaluate(m.doc)
+	m.InvalidateAlignedCache()
+}
+
+// trashSummary describes a trashed block by its first non-blank line.
+func trashSummary(t document.TrashedBlock) string {
+	first := ""
+	for _, line := range t.Node.Block.Source() {
+		if first = strings.TrimSpace(line); first != "" {
+			break
+		}
+	}
+	if r := []rune(first); len(r) > 24 {
+		first = string(r[:23]) + "…"
+	}
+	return fmt.Sprintf("%q", first)
+}
@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTrashDeleteAndRestore(t *testing.T) {
+	source := "# Assumptions\n\nprice = $20\nqty = 3\n\ntotal = price * qty\n"
+	doc, _ := document.NewDocument(source)
+	m := New(doc)
+	m.cursorLine = 3
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'B'}})
+	if got := strings.Join(m.GetLines(), "\n"); strings.Contains(got, "price") {
+		t.Fatalf("dB left the block in the document: %q", got)
+	}
+	if len(m.doc.Trash()) != 1 {
+		t.Fatalf("trash holds %d blocks, want 1", len(m.doc.Trash()))
+	}
+	if _, ok := m.eval.GetEnvironment().Get("price"); ok {
+		t.Error("price still defined after its block was deleted")
+	}
+
+	// Restoring doesn't need the undo history
+	m.history = newUndoHistory(m.GetLines())
+	m.executeCommand("/trash")
+	if !strings.Contains(m.statusMsg, `1) "price = $20"`) {
+		t.Errorf("/trash status = %q", m.statusMsg)
+	}
+	m.executeCommand("/restore")
+	if got := strings.Join(m.GetLines(), "\n"); !strings.HasPrefix(got+"\n", source) {
+		t.Errorf("after /restore, document = %q", got)
+	}
+	if total, _ := m.eval.GetEnvironment().Get("total"); total == nil || total.String() != "$60.00" {
+		t.Errorf("total = %v after restoring, want $60.00", total)
+	}
+	if m.cursorLine != 2 || len(m.doc.Trash()) != 0 {
+		t.Errorf("cursor on line %d with %d trashed blocks; want line 2 and an empty trash", m.cursorLine, len(m.doc.Trash()))
+	}
+
+	m.executeCommand("/restore 3")
+	if !m.statusIsErr {
+		t.Error("/restore of a missing entry should fail")
+	}
+}
+
+// TestTrashDeleteLastLine checks dd of a block's last line keeps the block
+// in the trash.
+func TestTrashDeleteLastLine(t *testing.T) {
+	doc, _ := document.NewDocument("a = 1\n\n\nb = 2\nc = 3")
+	m := New(doc)
+	dd := []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune{'d'}}, {Type: tea.KeyRunes, Runes: []rune{'d'}}}
+
+	m.cursorLine = 4
+	m = press(m, dd...)
+	if len(m.doc.Trash()) != 0 {
+		t.Fatalf("dd of one line of several trashed the block")
+	}
+	m.cursorLine = 3
+	m = press(m, dd...)
+	if trash := m.doc.Trash(); len(trash) != 1 || trash[0].Node.Block.Source()[0] != "b = 2" {
+		t.Fatalf("trash = %+v, want the emptied block as it was", trash)
+	}
+}
@@ -0,0 +1,229 @@
+package editor
+
+import (
+	"slices"
+	"strings"
+)
+
+// Undo: each change is recorded as the range of lines it replaced, not a
+// copy of the document, so the history's size follows the edits rather
+// than the document. The edits made while typing, from entering edit
+// mode until Esc, form one undo step, and undo and redo put the cursor
+// back where the change was.
+
+// maxUndoBytes caps the text the undo history holds; the oldest steps are
+// dropped to stay under it.
+const maxUndoBytes = 1 << 20
+
+// lineEdit replaces the removed lines at line at with the inserted ones.
+type lineEdit struct {
+	at       int
+	removed  []string
+	inserted []string
+}
+
+// size is roughly the bytes the edit holds.
+func (e lineEdit) size() int {
+	n := 0
+	for _, line := range e.removed {
+		n += len(line) + 1
+	}
+	for _, line := range e.inserted {
+		n += len(line) + 1
+	}
+	return n
+}
+
+// cursorPos is a cursor line and byte column.
+type cursorPos struct {
+	line, col int
+}
+
+// undoStep is what one undo or redo reverts or reapplies.
+type undoStep struct {
+	edits  []lineEdit // In the order made
+	before cursorPos  // Where the first edit began
+	after  cursorPos  // The cursor after the last edit
+	open   bool       // Typing continues it
+	size   int
+}
+
+// undoHistory records the document's changes as steps of line edits.
+type undoHistory struct {
+	lines []string // The document as last recorded
+	undo  []undoStep
+	redo  []undoStep
+	size  int // Bytes held by undo and redo
+}
+
+// newUndoHistory starts an empty history for a document's lines.
+func newUndoHistory(lines []string) undoHistory {
+	return undoHistory{lines: slices.Clone(lines)}
+}
+
+// record adds the change from the last recorded lines to lines, if any,
+// reporting whether there was one. Typing continues an open step, and
+// starts one if none is open; other changes are steps of their own.
+// Recording a change forgets what was undone.
+func (h *undoHistory) record(lines []string, cursor cursorPos, typing bool) bool {
+	edit, ok := diffLines(h.lines, lines)
+	if !ok {
+		return false
+	}
+	h.lines = slices.Clone(lines)
+	for _, step := range h.redo {
+		h.size -= step.size
+	}
+	h.redo = nil
+
+	if n := len(h.undo); typing && n > 0 && h.undo[n-1].open {
+		step := &h.undo[n-1]
+		step.edits = append(step.edits, edit)
+		step.after = cursor
+		step.size += edit.size()
+	} else {
+		h.undo = append(h.undo, undoStep{
+			edits:  []lineEdit{edit},
+			before: editStart(edit),
+			after:  cursor,
+			open:   typing,
+			size:   edit.size(),
+		})
+	}
+	h.size += edit.size()
+
+	// Keep at least the latest step, however large
+	for h.size > maxUndoBytes && len(h.undo) > 1 {
+		h.size -= h.undo[0].size
+		h.undo = h.undo[1:]
+	}
+	return true
+}
+
+// seal ends the open step, so the next change starts another.
+func (h *undoHistory) seal() {
+	if n := len(h.undo); n > 0 {
+		h.undo[n-1].open = false
+	}
+}
+
+// undoLast reverts the last step, returning the document's lines and
+// cursor before it, or false if there's nothing to undo.
+func (h *undoHistory) undoLast() ([]string, cursorPos, bool) {
+	n := len(h.undo)
+	if n == 0 {
+		return nil, cursorPos{}, false
+	}
+	step := h.undo[n-1]
+	step.open = false
+	h.undo = h.undo[:n-1]
+	h.redo = append(h.redo, step)
+
+	lines := slices.Clone(h.lines)
+	for _, e := range slices.Backward(step.edits) {
+		lines = slices.Replace(lines, e.at, e.at+len(e.inserted), e.removed...)
+	}
+	h.lines = lines
+	return slices.Clone(lines), step.before, true
+}
+
+// redoLast reapplies the last undone step, returning the document's lines
+// and cursor after it, or false if there's nothing to redo.
+func (h *undoHistory) redoLast() ([]string, cursorPos, bool) {
+	n := len(h.redo)
+	if n == 0 {
+		return nil, cursorPos{}, false
+	}
+	step := h.redo[n-1]
+	h.redo = h.redo[:n-1]
+	h.undo = append(h.undo, step)
+
+	lines := slices.Clone(h.lines)
+	for _, e := range step.edits {
+		lines = slices.Replace(lines, e.at, e.at+len(e.removed), e.inserted...)
+	}
+	h.lines = lines
+	return slices.Clone(lines), step.after, true
+}
+
+// diffLines returns the edit turning old into lines: the lines between
+// their common first and last lines. It reports false if they're equal.
+func diffLines(old, lines []string) (lineEdit, bool) {
+	start := 0
+	for start < len(old) && start < len(lines) && old[start] == lines[start] {
+		start++
+	}
+	if start == len(old) && start == len(lines) {
+		return lineEdit{}, false
+	}
+	end := 0
+	for end < len(old)-start && end < len(lines)-start && old[len(old)-1-end] == lines[len(lines)-1-end] {
+		end++
+	}
+	return lineEdit{
+		at:       start,
+		removed:  slices.Clone(old[start : len(old)-end]),
+		inserted: slices.Clone(lines[start : len(lines)-end]),
+	}, true
+}
+
+// editStart returns where an edit began: the first column its first
+// line changed at, or the start of the line for whole lines.
+func editStart(e lineEdit) cursorPos {
+	if len(e.removed) == 0 || len(e.inserted) == 0 {
+		return cursorPos{line: e.at}
+	}
+	was, now := e.removed[0], e.inserted[0]
+	col := 0
+	for col < len(was) && col < len(now) && was[col] == now[col] {
+		col++
+	}
+	return cursorPos{line: e.at, col: col}
+}
+
+// pushUndoState records the change since the last one as an undo step,
+// or as part of the open step while typing.
+func (m *Model) pushUndoState() {
+	m.history.record(m.GetLines(), cursorPos{m.cursorLine, m.cursorCol}, m.typing)
+}
+
+// undo reverts the last change, putting the cursor where it was made.
+func (m *Model) undo() {
+	// Changes not yet recorded are undone first
+	m.pushUndoState()
+	m.history.seal()
+	lines, cursor, ok := m.history.undoLast()
+	if !ok {
+		return
+	}
+	m.restoreUndo(lines, cursor)
+}
+
+// redo re-applies an undone change, putting the cursor after it.
+func (m *Model) redo() {
+	lines, cursor, ok := m.history.redoLast()
+	if !ok {
+		return
+	}
+	m.restoreUndo(lines, cursor)
+}
+
+// restoreUndo replaces the document's lines with those undo or redo
+// returned, and moves the cursor to cursor.
+func (m *Model) restoreUndo(lines []string, cursor cursorPos) {
+	if err := m.reparse(strings.Join(lines, "\n")); err != nil {
+		return
+	}
+	m.modified = true
+	m.InvalidateAlignedCache()
+
+	prev := m.cursorLine
+	current := m.GetLines()
+	m.history.lines = slices.Clone(current)
+	m.cursorLine = max(0, min(cursor.line, len(current)-1))
+	m.cursorCol = 0
+	if m.cursorLine < len(current) {
+		m.cursorCol = clampGrapheme(current[m.cursorLine], cursor.col)
+	}
+	m.followCursor(prev)
+}
@@ -0,0 +1,122 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	keyEsc  = tea.KeyMsg{Type: tea.KeyEsc}
+	keyUndo = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}}
+	keyRedo = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}}
+)
+
+// TestUndoGroupsTyping checks everything typed between entering edit mode
+// and Esc, across lines, undoes and redoes as one step, with the cursor
+// put back where the change was.
+func TestUndoGroupsTyping(t *testing.T) {
+	doc, _ := document.NewDocument("a = 1\nb = 2")
+	m := New(doc)
+	m.cursorLine = 1
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = typeText(m, " + a\nc = b * 2")
+	m = press(m, keyEsc)
+	if got := strings.Join(m.GetLines(), "\n"); got != "a = 1\nb = 2 + a\nc = b * 2" {
+		t.Fatalf("after typing, document = %q", got)
+	}
+
+	m = press(m, keyUndo)
+	if got := strings.Join(m.GetLines(), "\n"); got != "a = 1\nb = 2" {
+		t.Errorf("after undo, document = %q", got)
+	}
+	if m.cursorLine != 1 || m.cursorCol != len("b = 2") {
+		t.Errorf("after undo, cursor = %d:%d, want 1:%d", m.cursorLine, m.cursorCol, len("b = 2"))
+	}
+	if b, _ := m.eval.GetEnvironment().Get("b"); b == nil || b.String() != "2" {
+		t.Errorf("after undo, b = %v, want 2", b)
+	}
+
+	m = press(m, keyRedo)
+	if got := strings.Join(m.GetLines(), "\n"); got != "a = 1\nb = 2 + a\nc = b * 2" {
+		t.Errorf("after redo, document = %q", got)
+	}
+	if m.cursorLine != 2 {
+		t.Errorf("after redo, cursor line = %d, want 2", m.cursorLine)
+	}
+	if c, _ := m.eval.GetEnvironment().Get("c"); c == nil || c.String() != "6" {
+		t.Errorf("after redo, c = %v, want 6", c)
+	}
+}
+
+// TestUndoSteps checks separate edits undo one at a time, a new edit
+// forgets what was undone, and an inserted line undoes with its text.
+func TestUndoSteps(t *testing.T) {
+	doc, _ := document.NewDocument("x = 1")
+	m := New(doc)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = typeText(m, "y = 2")
+	m = press(m, keyEsc)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = typeText(m, "z = 3")
+	m = press(m, keyEsc)
+	if len(m.history.undo) != 2 {
+		t.Fatalf("undo steps = %d, want 2", len(m.history.undo))
+	}
+
+	m = press(m, keyUndo)
+	if got := strings.Join(m.GetLines(), "\n"); got != "x = 1\ny = 2" {
+		t.Errorf("after one undo, document = %q", got)
+	}
+	m = press(m, keyUndo)
+	if got := strings.Join(m.GetLines(), "\n"); got != "x = 1" {
+		t.Errorf("after two undos, document = %q", got)
+	}
+	m = press(m, keyUndo) // Nothing left
+	if got := strings.Join(m.GetLines(), "\n"); got != "x = 1" {
+		t.Errorf("undo with nothing to undo changed the document to %q", got)
+	}
+
+	m = press(m, keyRedo)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if len(m.history.redo) != 0 {
+		t.Errorf("redo steps after a new edit = %d, want 0", len(m.history.redo))
+	}
+}
+
+// TestUndoHistorySize checks the history holds line edits, dropping the
+// oldest steps past maxUndoBytes.
+func TestUndoHistorySize(t *testing.T) {
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 100)
+	}
+	h := newUndoHistory(lines)
+
+	big := strings.Repeat("y", maxUndoBytes/4)
+	for i := range 6 {
+		lines[i] = big
+		h.record(lines, cursorPos{line: i}, false)
+		if h.size > maxUndoBytes+len(big) {
+			t.Fatalf("history holds %d bytes", h.size)
+		}
+	}
+	if len(h.undo) >= 6 {
+		t.Errorf("kept all %d steps past the size cap", len(h.undo))
+	}
+
+	// A one-line edit holds that line, not the document
+	h = newUndoHistory(lines)
+	lines[500] = "changed"
+	h.record(lines, cursorPos{line: 500}, false)
+	if h.size > 200 {
+		t.Errorf("one-line edit holds %d bytes", h.size)
+	}
+	if step := h.undo[0]; step.before != (cursorPos{line: 500}) {
+		t.Errorf("edit began at %v, want line 500", step.before)
+	}
+}
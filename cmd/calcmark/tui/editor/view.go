@@ -2,6 +2,7 @@ package editor
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/CalcMark/go-calcmark/cmd/calcmark/tui/components"
@@ -26,43 +27,27 @@ func (m Model) View() string {
 	if m.quitting {
 		return "Goodbye!\n"
 	}
+	if m.split != nil {
+		return m.renderSplit()
+	}
+	if m.mode == ModeGraph {
+		return m.renderGraph()
+	}
 
 	var b strings.Builder
 
 	// Calculate layout
 	totalWidth := m.width
-	totalHeight := m.height
-
-	// Reserve space: status bar (2) + context footer (2) + separator (1)
-	contentHeight := totalHeight - 5
-	if contentHeight < 5 {
-		contentHeight = 5
-	}
+	paneContentHeight, sourceContentHeight, globalsHeight := m.paneHeights()
 
 	// Calculate pane widths based on preview mode using centralized configuration
 	leftWidth, rightWidth := m.GetPaneWidths(totalWidth)
 
-	// Pane content height (minus header row)
-	paneContentHeight := contentHeight - 1
-	if paneContentHeight < 3 {
-		paneContentHeight = 3
-	}
-
-	// Calculate globals panel height for alignment
-	// (collapsed = 1 line, expanded = 1 + number of globals)
-	globalsHeight := 1 // collapsed state
-	if m.globalsExpanded {
-		globalsHeight = 1 + m.getGlobalsCount()
-		if m.getGlobalsCount() == 0 {
-			globalsHeight = 2 // "(no globals defined)" message
-		}
-	}
-	globalsHeight++ // +1 for separator line
-
 	// CRITICAL: Compute aligned line structure ONCE to avoid cycles.
 	// Both widths are fixed, and we compute wrapping/padding based on them.
 	// This prevents: preview reflows → padding changes → width changes → reflow...
-	aligned := m.computeAlignedPanes(leftWidth, rightWidth)
+	// Only the lines around the viewport are computed (see visibleSourceRange).
+	aligned := m.computeVisiblePanes(leftWidth, rightWidth, paneContentHeight)
 
 	// Render source pane with header
 	sourceHeader := lipgloss.NewStyle().
@@ -81,15 +66,10 @@ func (m Model) View() string {
 		}
 	}
 
-	sourceContentHeight := paneContentHeight
-	if m.previewMode != PreviewHidden {
-		sourceContentHeight = paneContentHeight - globalsHeight
-	}
-	if sourceContentHeight < 1 {
-		sourceContentHeight = 1
-	}
-
 	sourceContent := m.renderSourcePaneAligned(leftWidth, sourceContentHeight, aligned)
+	if m.mode == ModeBlockEdit {
+		sourceContent = m.blockEditor.View()
+	}
 	sourcePane := lipgloss.JoinVertical(lipgloss.Left, sourceHeader, sourcePadding+sourceContent)
 
 	// Render preview pane (if visible)
@@ -115,6 +95,18 @@ func (m Model) View() string {
 	}
 	b.WriteString("\n")
 
+	// Render the variable outline if open
+	if m.mode == ModeOutline {
+		b.WriteString(m.renderOutlinePanel(totalWidth))
+		b.WriteString("\n")
+	}
+
+	// Render the diagnostics panel if open
+	if m.diagnosticsOpen {
+		b.WriteString(m.renderDiagnosticsPanel(totalWidth))
+		b.WriteString("\n")
+	}
+
 	// Render context footer (variables referenced in current line)
 	contextFooter := m.renderContextFooter(totalWidth)
 	b.WriteString(contextFooter)
@@ -141,9 +133,32 @@ func (m Model) View() string {
 		b.WriteString(cmdLine)
 	}
 
+	// Render the quick calculator if open (overlay)
+	if m.mode == ModeQuickCalc {
+		b.WriteString("\n")
+		b.WriteString(m.renderQuickCalc(totalWidth))
+	}
+
 	return b.String()
 }
 
+// renderQuickCalc renders the quick calculator's input line and result.
+func (m Model) renderQuickCalc(width int) string {
+	prompt := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("6")).
+		Bold(true).
+		Render("calc> " + m.quickInput + "█")
+
+	var result string
+	switch {
+	case m.quickIsErr:
+		result = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("  " + m.quickResult)
+	case m.quickResult != "":
+		result = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("  → " + m.quickResult)
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(prompt + result)
+}
+
 // computeAlignedPanes computes both pane line structures once with fixed widths.
 // This is the single source of truth for alignment, preventing reflow cycles.
 // It uses the cached AlignedModel and converts to the legacy format for rendering.
@@ -152,8 +167,68 @@ func (m Model) computeAlignedPanes(sourceWidth, previewWidth int) alignedPanes {
 	// Note: We need a mutable reference to update the cache, but View() receives
 	// a value copy. For now, we recompute each time in View() but the AlignedModel
 	// computation is still the single source of truth.
-	aligned := m.computeAlignedModelFresh(sourceWidth, previewWidth)
+	return toAlignedPanes(m.computeAlignedModelFresh(sourceWidth, previewWidth))
+}
 
+// paneHeights returns the height of the panes below their headers, the
+// number of document lines the source pane shows, and the height of the
+// globals panel (with its separator) that the source pane is padded by.
+func (m Model) paneHeights() (pane, source, globals int) {
+	// Reserve space: status bar (2) + context footer (2) + separator (1)
+	contentHeight := m.height - 5 - m.diagnosticsPanelHeight() - m.outlinePanelHeight()
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+
+	// Pane content height (minus header row)
+	pane = contentHeight - 1
+	if pane < 3 {
+		pane = 3
+	}
+
+	// Calculate globals panel height for alignment
+	// (collapsed = 1 line, expanded = 1 + number of globals)
+	globals = 1 // collapsed state
+	if m.globalsExpanded {
+		globals = 1 + m.getGlobalsCount()
+		if m.getGlobalsCount() == 0 {
+			globals = 2 // "(no globals defined)" message
+		}
+	}
+	globals++ // +1 for separator line
+
+	source = pane
+	if m.previewMode != PreviewHidden {
+		source = pane - globals
+	}
+	if source < 1 {
+		source = 1
+	}
+	return pane, source, globals
+}
+
+// computeVisiblePanes is computeAlignedPanes for just the source lines that
+// can appear in a pane of the given height (see visibleSourceRange). Visual
+// line indexes count from the first line of that range, so rendering from
+// the scroll position shows the same lines as the full structure would.
+func (m Model) computeVisiblePanes(sourceWidth, previewWidth, height int) alignedPanes {
+	from, to := m.visibleSourceRange(height)
+	return toAlignedPanes(m.computeAlignedModelRange(sourceWidth, previewWidth, from, to))
+}
+
+// visibleSourceRange returns the source lines [from, to) that the panes may
+// show at the given height, plus a margin of a screen on each side.
+//
+// The panes show the cursor at most height-1 visual lines from the top (see
+// scrollStart). Every source line takes at least one visual line, so a
+// screen shows at most height source lines on either side of the cursor,
+// and the heights of wrapped lines elsewhere never need computing.
+func (m Model) visibleSourceRange(height int) (from, to int) {
+	return max(0, m.cursorLine-2*height), m.cursorLine + 2*height
+}
+
+// toAlignedPanes converts an AlignedModel to the legacy alignedPanes format.
+func toAlignedPanes(aligned AlignedModel) alignedPanes {
 	// Convert AlignedModel to legacy alignedPanes format
 	sourceLines := make([]sourceLine, len(aligned.SourceLines))
 	for i, al := range aligned.SourceLines {
@@ -164,6 +239,7 @@ func (m Model) computeAlignedPanes(sourceWidth, previewWidth int) alignedPanes {
 			isWrapped:     al.Kind == AlignedLineWrapped || al.Kind == AlignedLineCursorWrapped,
 			isCursorLine:  al.Kind == AlignedLineCursor,
 			sourceLineIdx: al.SourceLineIdx,
+			isCalc:        al.IsCalc,
 		}
 	}
 
@@ -184,9 +260,17 @@ func (m Model) computeAlignedPanes(sourceWidth, previewWidth int) alignedPanes {
 	}
 }
 
-// computeAlignedModelFresh computes a fresh AlignedModel without caching.
-// Used by computeAlignedPanes since View() receives a value copy of Model.
+// computeAlignedModelFresh computes the AlignedModel for the current state.
+// Used by computeAlignedPanes since View() receives a value copy of Model;
+// the shared render cache limits the work to lines changed since the last
+// call (see renderCache).
 func (m Model) computeAlignedModelFresh(sourceWidth, previewWidth int) AlignedModel {
+	return m.computeAlignedModelRange(sourceWidth, previewWidth, 0, math.MaxInt)
+}
+
+// computeAlignedModelRange computes the AlignedModel for source lines from
+// through to-1 only.
+func (m Model) computeAlignedModelRange(sourceWidth, previewWidth, from, to int) AlignedModel {
 	// Calculate content width for source pane (accounting for line numbers)
 	lineNumWidth := 4
 	sourceContentWidth := sourceWidth - lineNumWidth - 2
@@ -196,21 +280,26 @@ func (m Model) computeAlignedModelFresh(sourceWidth, previewWidth int) AlignedMo
 
 	input := AlignedModelInput{
 		Lines:              m.GetLines(),
-		Results:            m.GetLineResults(),
+		Results:            m.lineResultsInRange(from, to),
 		SourceContentWidth: sourceContentWidth,
 		PreviewWidth:       previewWidth,
 		CursorLine:         m.cursorLine,
 		PreviewMode:        m.previewMode,
+		TabWidth:           m.tabWidth,
+	}
+
+	if m.renderCache != nil {
+		m.renderCache.begin(renderContext{
+			sourceWidth:  sourceContentWidth,
+			previewWidth: previewWidth,
+			previewMode:  m.previewMode,
+			tabWidth:     m.tabWidth,
+			locale:       m.doc.NumberLocale().Tag,
+		})
 	}
 
 	// Compute with render functions that match view.go behavior
-	return ComputeAlignedModel(input, m.renderCalcLine, func(line string, width int) []string {
-		mdRenderer, _ := NewMarkdownRenderer(width)
-		if mdRenderer != nil {
-			return mdRenderer.RenderLine(line)
-		}
-		return WrapText(line, width)
-	})
+	return computeAlignedModel(input, m.renderCache, m.renderCalcLine, renderMarkdownLine)
 }
 
 // sourceLine represents a line in the source pane (may be padding or wrapped).
@@ -221,6 +310,7 @@ type sourceLine struct {
 	isWrapped     bool   // True if this is a continuation of a wrapped line
 	isCursorLine  bool   // True if this is the cursor line
 	sourceLineIdx int    // Original source line index (for cursor tracking on wrapped lines)
+	isCalc        bool   // True if this is from a calculation block, drawn highlighted
 }
 
 // renderSourcePaneAligned renders the source pane using pre-computed aligned lines.
@@ -231,33 +321,13 @@ func (m Model) renderSourcePaneAligned(width, height int, aligned alignedPanes)
 
 	visibleLines := height
 
-	// Convert cursor's source line to visual line index for proper scrolling
-	cursorVisualLine := 0
-	if visualIdx, ok := aligned.sourceToVisual[m.cursorLine]; ok {
-		cursorVisualLine = visualIdx
-	}
-
-	// Convert m.scrollOffset from source-line space to visual-line space
-	// m.scrollOffset is stored as a source line index, but we need visual line index
-	visualScrollOffset := 0
-	if visualIdx, ok := aligned.sourceToVisual[m.scrollOffset]; ok {
-		visualScrollOffset = visualIdx
-	}
-
-	// Ensure cursor is visible by adjusting scroll based on visual position
-	if cursorVisualLine < visualScrollOffset {
-		visualScrollOffset = cursorVisualLine
-	}
-	if cursorVisualLine >= visualScrollOffset+visibleLines {
-		visualScrollOffset = cursorVisualLine - visibleLines + 1
-	}
-
 	// Calculate visible range
-	start := visualScrollOffset
+	start := m.scrollStart(aligned, visibleLines)
 	end := min(start+visibleLines, len(sourceLines))
 
 	lineNumWidth := 4
 	contentWidth := width - lineNumWidth - 2
+	marks := diagnosticMarks(m.diagnostics())
 
 	linesWritten := 0
 	for i := start; i < end && linesWritten < visibleLines; i++ {
@@ -278,6 +348,12 @@ func (m Model) renderSourcePaneAligned(width, height int, aligned alignedPanes)
 			lineNum = m.styles.LineNumber.
 				Width(lineNumWidth).
 				Render("")
+		} else if mark, ok := marks[sl.sourceLineIdx]; ok {
+			// Line with a problem - line number in its severity's color
+			lineNum = m.diagnosticStyle(mark.severity).
+				Width(lineNumWidth).
+				Align(lipgloss.Right).
+				Render(fmt.Sprintf("%d", sl.lineNum))
 		} else {
 			// Regular line - show line number
 			lineNum = m.styles.LineNumber.
@@ -308,8 +384,8 @@ func (m Model) renderSourcePaneAligned(width, height int, aligned alignedPanes)
 				b.WriteString("\n")
 			}
 			continue
-		} else if sl.isCursorLine {
-			// Highlight current line
+		} else if sl.isCursorLine || (m.inSelection(sl.sourceLineIdx) && !sl.isPadding) {
+			// Highlight current line, and the lines a mouse drag selects
 			content = m.styles.CurrentLine.
 				Width(contentWidth).
 				Render(padToWidth(sl.content, contentWidth))
@@ -318,10 +394,15 @@ func (m Model) renderSourcePaneAligned(width, height int, aligned alignedPanes)
 			content = ""
 		} else if sl.isWrapped {
 			// Wrapped continuation line - no extra indent (line number space provides visual separation)
-			content = padToWidth(sl.content, contentWidth)
+			content = padToWidth(m.sourceContent(sl), contentWidth)
+		} else if mark, ok := marks[sl.sourceLineIdx]; ok {
+			// Source line with a problem - underline what it's about
+			content = padToWidth(m.markDiagnostic(sl.content, mark, func(s string) string {
+				return m.sourceContent(sourceLine{content: s, isCalc: sl.isCalc})
+			}), contentWidth)
 		} else {
 			// Normal source line - already fits within width
-			content = padToWidth(sl.content, contentWidth)
+			content = padToWidth(m.sourceContent(sl), contentWidth)
 		}
 
 		b.WriteString(lineNum)
@@ -343,6 +424,15 @@ func (m Model) renderSourcePaneAligned(width, height int, aligned alignedPanes)
 	return b.String()
 }
 
+// sourceContent returns a source line's text, with calculation syntax
+// highlighted. The cursor and edit lines keep their own styles instead.
+func (m Model) sourceContent(sl sourceLine) string {
+	if !sl.isCalc {
+		return sl.content
+	}
+	return highlightCalcLine(sl.content, m.styles, m.doc.NumberLocale())
+}
+
 // padToWidth pads a string to exactly width visual columns (no truncation).
 // Uses lipgloss.Width for correct unicode handling.
 func padToWidth(s string, width int) string {
@@ -375,18 +465,20 @@ func wrapStyledLine(line string, maxWidth int) []string {
 // renderEditLine renders the line being edited with cursor (single line, no wrapping).
 func (m Model) renderEditLine(width int) string {
 	var line string
+	buf, cursor := m.displayEditBuffer()
 
 	// Text style for non-cursor parts (uses EditLine foreground color)
 	textStyle := m.styles.EditLine.UnsetBackground().UnsetWidth()
 
-	if m.cursorCol >= len(m.editBuf) {
+	if cursor >= len(buf) {
 		// Cursor at end - show text followed by cursor
-		line = textStyle.Render(m.editBuf) + m.styles.Cursor.Render(" ")
+		line = textStyle.Render(buf) + m.styles.Cursor.Render(" ")
 	} else {
 		// Cursor in middle - highlight the character under cursor
-		before := m.editBuf[:m.cursorCol]
-		charAtCursor := string(m.editBuf[m.cursorCol])
-		after := m.editBuf[m.cursorCol+1:]
+		next := nextGrapheme(buf, cursor)
+		before := buf[:cursor]
+		charAtCursor := buf[cursor:next]
+		after := buf[next:]
 
 		line = textStyle.Render(before) + m.styles.Cursor.Render(charAtCursor) + textStyle.Render(after)
 	}
@@ -400,31 +492,32 @@ func (m Model) renderEditLine(width int) string {
 // renderEditLineWrapped renders the edit buffer with wrapping support.
 // Returns multiple lines if the content exceeds width.
 func (m Model) renderEditLineWrapped(width int) []string {
-	if len(m.editBuf) <= width {
+	buf, cursor := m.displayEditBuffer()
+	if lipgloss.Width(buf) <= width {
 		// Fits on one line
 		return []string{m.renderEditLine(width)}
 	}
 
 	// Wrap the edit buffer content
-	wrappedContent := WrapText(m.editBuf, width)
+	wrappedContent := WrapText(buf, width)
 	var result []string
 
 	// Track which wrapped line contains the cursor
 	charsSoFar := 0
 	cursorLineIdx := 0
-	cursorColInLine := m.cursorCol
+	cursorColInLine := cursor
 
 	for i, seg := range wrappedContent {
-		if m.cursorCol >= charsSoFar && m.cursorCol < charsSoFar+len(seg) {
+		if cursor >= charsSoFar && cursor < charsSoFar+len(seg) {
 			cursorLineIdx = i
-			cursorColInLine = m.cursorCol - charsSoFar
+			cursorColInLine = cursor - charsSoFar
 			break
 		}
 		charsSoFar += len(seg)
 		// Handle cursor at very end
-		if i == len(wrappedContent)-1 && m.cursorCol >= charsSoFar {
+		if i == len(wrappedContent)-1 && cursor >= charsSoFar {
 			cursorLineIdx = i
-			cursorColInLine = m.cursorCol - charsSoFar + len(seg)
+			cursorColInLine = cursor - charsSoFar + len(seg)
 		}
 	}
 
@@ -437,9 +530,10 @@ func (m Model) renderEditLineWrapped(width int) []string {
 			if cursorColInLine >= len(seg) {
 				line = textStyle.Render(seg) + m.styles.Cursor.Render(" ")
 			} else {
+				next := nextGrapheme(seg, cursorColInLine)
 				before := seg[:cursorColInLine]
-				charAtCursor := string(seg[cursorColInLine])
-				after := seg[cursorColInLine+1:]
+				charAtCursor := seg[cursorColInLine:next]
+				after := seg[next:]
 				line = textStyle.Render(before) + m.styles.Cursor.Render(charAtCursor) + textStyle.Render(after)
 			}
 		} else {
@@ -454,6 +548,13 @@ func (m Model) renderEditLineWrapped(width int) []string {
 	return result
 }
 
+// displayEditBuffer returns the edit buffer with tabs expanded to spaces,
+// and the cursor's offset in it.
+func (m Model) displayEditBuffer() (string, int) {
+	cursor := min(m.cursorCol, len(m.editBuf))
+	return document.ExpandTabs(m.editBuf, m.tabWidth), len(document.ExpandTabs(m.editBuf[:cursor], m.tabWidth))
+}
+
 // previewLine represents a line in the preview pane with its source mapping.
 type previewLine struct {
 	content       string // Rendered content for this preview line
@@ -486,31 +587,10 @@ func (m Model) renderPreviewPaneAligned(width, height int, aligned alignedPanes)
 		resultsHeight = 1
 	}
 
-	// Convert cursor's source line to visual line index for proper scrolling
-	// Must use same scroll offset as source pane to keep them aligned
-	cursorVisualLine := 0
-	if visualIdx, ok := aligned.sourceToVisual[m.cursorLine]; ok {
-		cursorVisualLine = visualIdx
-	}
-
-	// Convert m.scrollOffset from source-line space to visual-line space
-	// m.scrollOffset is stored as a source line index, but we need visual line index
-	visualScrollOffset := 0
-	if visualIdx, ok := aligned.sourceToVisual[m.scrollOffset]; ok {
-		visualScrollOffset = visualIdx
-	}
-
-	// Ensure cursor is visible by adjusting scroll based on visual position
-	if cursorVisualLine < visualScrollOffset {
-		visualScrollOffset = cursorVisualLine
-	}
-	if cursorVisualLine >= visualScrollOffset+resultsHeight {
-		visualScrollOffset = cursorVisualLine - resultsHeight + 1
-	}
-
-	// Apply scroll offset and render visible lines
+	// Apply scroll position and render visible lines; must match the source
+	// pane to keep them aligned
 	// Note: wrapping is already done in computeAlignedPanes to ensure proper alignment
-	start := visualScrollOffset
+	start := m.scrollStart(aligned, resultsHeight)
 	end := min(start+resultsHeight, len(previewLines))
 
 	// In edit mode, source pane may render different number of lines for cursor line
@@ -523,7 +603,8 @@ func (m Model) renderPreviewPaneAligned(width, height int, aligned alignedPanes)
 	if m.mode == ModeEditing {
 		// Count how many lines the edit buffer would produce
 		contentWidth := width // approximate
-		editLines := WrapText(m.editBuf, contentWidth)
+		buf, _ := m.displayEditBuffer()
+		editLines := WrapText(buf, contentWidth)
 		editLineCount = len(editLines)
 		if editLineCount == 0 {
 			editLineCount = 1
@@ -591,8 +672,7 @@ func (m Model) renderPreviewPaneAligned(width, height int, aligned alignedPanes)
 // renderCalcLine renders a single calculation line result.
 func (m Model) renderCalcLine(r LineResult, width int) string {
 	// Use the detector to check if this line is actually a calculation
-	detector := document.NewDetector()
-	isActuallyCalc, _ := detector.IsCalculation(r.Source)
+	isActuallyCalc, _ := m.doc.Detector().IsCalculation(r.Source)
 
 	if r.Error != "" && isActuallyCalc {
 		// Show brief error indicator inline - detailed error shown in context footer
@@ -605,11 +685,7 @@ func (m Model) renderCalcLine(r LineResult, width int) string {
 
 	if !isActuallyCalc {
 		// Render as markdown even if in a CalcBlock
-		mdRenderer, err := NewMarkdownRenderer(width)
-		if err != nil {
-			return r.Source
-		}
-		lines := mdRenderer.RenderLine(r.Source)
+		lines := renderMarkdownLine(r.Source, width)
 		if len(lines) > 0 {
 			return lines[0] // Return first line; wrapping handled by caller
 		}
@@ -633,6 +709,20 @@ func (m Model) renderCalcLine(r LineResult, width int) string {
 			Render("* ")
 	}
 
+	// Stale indicator: the block timed out and shows its previous value
+	// until the idle retry finishes
+	if r.Stale {
+		valueStyle = valueStyle.Faint(true)
+		changedMarker = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("245")).
+			Render("⏱ ")
+	}
+
+	// Highlight rules flag values that crossed a threshold
+	if color, ok := highlightColors[r.Highlight]; ok {
+		valueStyle = valueStyle.Foreground(lipgloss.Color(color)).Bold(true)
+	}
+
 	switch m.previewMode {
 	case PreviewFull:
 		// Full mode: left-aligned "varName → value" (with * if changed)
@@ -640,12 +730,20 @@ func (m Model) renderCalcLine(r LineResult, width int) string {
 			Foreground(lipgloss.Color("240"))
 		arrowStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240"))
-		return changedMarker + varStyle.Render(r.VarName) + " " + arrowStyle.Render("→") + " " + valueStyle.Render(r.Value)
+		line := changedMarker + varStyle.Render(r.VarName) + " " + arrowStyle.Render("→") + " " + valueStyle.Render(r.Value)
+		if r.Compare != "" {
+			return renderCompareColumn(line, r, width)
+		}
+		return line
 
 	case PreviewMinimal:
 		// Minimal mode: left-aligned "→ value" (with * if changed)
 		arrow := "→ "
-		return changedMarker + valueStyle.Render(arrow + r.Value)
+		line := changedMarker + valueStyle.Render(arrow + r.Value)
+		if r.Compare != "" {
+			return renderCompareColumn(line, r, width)
+		}
+		return line
 	}
 
 	return ""
@@ -744,14 +842,14 @@ func (m Model) renderGlobalsPanel(width int) string {
 // renderContextFooter renders the context footer showing errors or referenced variables.
 // Delegates to components.RenderContextFooter with prepared state.
 func (m Model) renderContextFooter(width int) string {
-	results := m.GetLineResults()
+	results := m.lineResultsInRange(m.cursorLine, m.cursorLine+1)
 
 	// Build state for the pure render function
 	state := components.ContextFooterState{}
 
 	// Check bounds
-	if m.cursorLine < len(results) {
-		currentResult := results[m.cursorLine]
+	if len(results) == 1 {
+		currentResult := results[0]
 		state.IsCalcLine = currentResult.IsCalc
 
 		if currentResult.IsCalc && currentResult.Error != "" {
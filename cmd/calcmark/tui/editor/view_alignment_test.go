@@ -3,6 +3,7 @@ package editor
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/CalcMark/go-calcmark/spec/document"
 	tea "github.com/charmbracelet/bubbletea"
@@ -393,3 +394,111 @@ z = 30`
 		}
 	}
 }
+
+// TestVisiblePanesMatchFullDocument tests that rendering from the aligned
+// structure of just the visible source lines matches rendering from the
+// structure of the whole document, wherever the cursor and scroll are.
+func TestVisiblePanesMatchFullDocument(t *testing.T) {
+	var content strings.Builder
+	for i := range 40 {
+		content.WriteString("## Section with a heading long enough to wrap in a narrow pane\n")
+		content.WriteString("x = 1\ny = x * 2\n\n")
+		if i%3 == 0 {
+			content.WriteString("Some prose that runs on well past the width of the source pane, so it wraps.\n\n")
+		}
+	}
+	doc, err := document.NewDocument(content.String())
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+
+	m := New(doc)
+	m.width, m.height = 80, 20
+	leftWidth, rightWidth := m.GetPaneWidths(m.width)
+	height := 12
+	total := m.TotalLines()
+
+	positions := [][2]int{ // cursor, cursor row
+		{0, 0}, {5, 0}, {30, 5}, {60, 11}, {80, 70}, {total - 1, 9}, {total - 1, 0}, {3, 3},
+	}
+	for _, mode := range []EditorMode{ModeNormal, ModeEditing} {
+		for _, pos := range positions {
+			m.mode = mode
+			m.cursorLine, m.cursorRow = pos[0], pos[1]
+			m.editBuf = m.GetLines()[m.cursorLine]
+			m.cursorCol = 0
+
+			full := m.computeAlignedPanes(leftWidth, rightWidth)
+			visible := m.computeVisiblePanes(leftWidth, rightWidth, height)
+			if len(visible.sourceLines) >= len(full.sourceLines) {
+				t.Errorf("cursor %d row %d: visible structure has %d lines, full has %d",
+					pos[0], pos[1], len(visible.sourceLines), len(full.sourceLines))
+			}
+
+			if got, want := m.renderSourcePaneAligned(leftWidth, height, visible), m.renderSourcePaneAligned(leftWidth, height, full); got != want {
+				t.Errorf("mode %v cursor %d row %d: source pane differs:\n%s\nwant\n%s", mode, pos[0], pos[1], got, want)
+			}
+			if got, want := m.renderPreviewPaneAligned(rightWidth, height, visible), m.renderPreviewPaneAligned(rightWidth, height, full); got != want {
+				t.Errorf("mode %v cursor %d row %d: preview pane differs:\n%s\nwant\n%s", mode, pos[0], pos[1], got, want)
+			}
+		}
+	}
+}
+
+// TestViewAlignment_EditBufferTabs tests that the line being edited shows
+// tabs at their width, with the cursor after them.
+func TestViewAlignment_EditBufferTabs(t *testing.T) {
+	doc, err := document.NewDocument("\tx = 1\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	m := New(doc)
+	m.tabWidth = 4
+	m.editBuf = "\tx = 1"
+	m.cursorCol = 1 // On the "x"
+
+	buf, cursor := m.displayEditBuffer()
+	if buf != "    x = 1" || cursor != 4 {
+		t.Errorf("displayEditBuffer() = %q, %d; want %q, 4", buf, cursor, "    x = 1")
+	}
+	if line := m.renderEditLine(20); strings.Contains(line, "\t") || !strings.HasPrefix(line, "    x") {
+		t.Errorf("renderEditLine = %q, want the tab expanded", line)
+	}
+}
+
+// TestViewAlignment_EditBufferWideCharacters tests that the cursor highlights
+// a whole wide character or emoji, never part of one.
+func TestViewAlignment_EditBufferWideCharacters(t *testing.T) {
+	doc, err := document.NewDocument("💰_total = 給料 * 12\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	m := New(doc)
+	m.editBuf = "💰_total = 給料 * 12"
+
+	for _, tt := range []struct {
+		cursor int
+		under  string
+	}{
+		{0, "💰"},
+		{len("💰_total = "), "給"},
+		{len("💰_total = 給"), "料"},
+	} {
+		m.cursorCol = tt.cursor
+		line := m.renderEditLine(40)
+		if !utf8.ValidString(line) {
+			t.Fatalf("renderEditLine splits a character at %d: %q", tt.cursor, line)
+		}
+		if !strings.Contains(line, m.styles.Cursor.Render(tt.under)) {
+			t.Errorf("cursor at %d should highlight %q, got %q", tt.cursor, tt.under, line)
+		}
+	}
+
+	// 給料 wraps as a unit when the line is wider than the pane
+	m.cursorCol = len("💰_total = 給")
+	for _, line := range m.renderEditLineWrapped(12) {
+		if !utf8.ValidString(line) {
+			t.Errorf("renderEditLineWrapped splits a character: %q", line)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Visual line mode: V selects whole lines from the cursor, and moving the
+// cursor extends the selection. d deletes the selected lines, y yanks
+// them, p and P replace them with the yanked lines, and > and < indent
+// and outdent them, each as one undo step that returns to normal mode.
+//
+// A bulk edit reparses the document and re-evaluates only the blocks it
+// changed and those depending on them, unless it added, removed, or moved
+// a definition, which can change any value and so evaluates everything.
+
+// enterVisualMode starts selecting lines at the cursor.
+func (m *Model) enterVisualMode() {
+	if m.cursorLine >= m.TotalLines() {
+		return
+	}
+	m.mode = ModeVisual
+	m.visualAnchor = m.cursorLine
+}
+
+// exitVisualMode returns to normal mode with the cursor on line.
+func (m *Model) exitVisualMode(line int) {
+	m.mode = ModeNormal
+	m.pendingKey = 0
+	prev := m.cursorLine
+	m.cursorLine = max(0, min(line, m.TotalLines()-1))
+	m.cursorCol = 0
+	m.followCursor(prev)
+}
+
+// handleVisualKey processes keys in visual line mode.
+func (m Model) handleVisualKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	first, last, _ := m.selection()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.exitVisualMode(m.cursorLine)
+		return m, nil
+	case tea.KeyUp:
+		m.moveCursor(-1, 0)
+	case tea.KeyDown:
+		m.moveCursor(1, 0)
+	case tea.KeyPgUp:
+		m.movePage(-m.visibleHeight())
+	case tea.KeyPgDown:
+		m.movePage(m.visibleHeight())
+	case tea.KeyDelete:
+		m.deleteSelection(first, last)
+	case tea.KeyRunes:
+		return m.handleVisualRune(msg.Runes, first, last)
+	}
+	return m, nil
+}
+
+// handleVisualRune handles character input in visual line mode, on the
+// selected lines first through last.
+func (m Model) handleVisualRune(runes []rune, first, last int) (tea.Model, tea.Cmd) {
+	if len(runes) == 0 {
+		return m, nil
+	}
+	key := runes[0]
+
+	if m.pendingKey == 'g' {
+		m.pendingKey = 0
+		if key == 'g' {
+			prev := m.cursorLine
+			m.cursorLine = 0
+			m.followCursor(prev)
+		}
+		return m, nil
+	}
+
+	switch key {
+	case 'j':
+		m.moveCursor(1, 0)
+	case 'k':
+		m.moveCursor(-1, 0)
+	case 'g':
+		m.pendingKey = 'g'
+	case 'G':
+		prev := m.cursorLine
+		m.cursorLine = max(0, m.TotalLines()-1)
+		m.followCursor(prev)
+	case 'V':
+		m.exitVisualMode(m.cursorLine)
+	case 'y':
+		m.yankLines(first, last)
+		m.exitVisualMode(first)
+	case 'd', 'x':
+		m.deleteSelection(first, last)
+	case 'p', 'P':
+		m.putOverSelection(first, last)
+	case '>':
+		m.indentSelection(first, last, 1)
+	case '<':
+		m.indentSelection(first, last, -1)
+	}
+	return m, nil
+}
+
+// yankLines copies lines first through last to the yank buffer.
+func (m *Model) yankLines(first, last int) {
+	lines := m.GetLines()
+	if last >= len(lines) {
+		return
+	}
+	m.yankBuffer = strings.Join(lines[first:last+1], "\n")
+	m.statusMsg = linesStatus(m.yankBuffer, "yanked")
+}
+
+// deleteSelection deletes lines first through last, yanking them.
+func (m *Model) deleteSelection(first, last int) {
+	m.yankLines(first, last)
+	if m.replaceLines(first, last, nil) {
+		m.statusMsg = linesStatus(m.yankBuffer, "deleted")
+	}
+	m.exitVisualMode(first)
+}
+
+// putOverSelection replaces lines first through last with the yanked
+// lines.
+func (m *Model) putOverSelection(first, last int) {
+	if m.yankBuffer == "" {
+		m.statusMsg = "Nothing yanked"
+		m.statusIsErr = true
+		return
+	}
+	if m.replaceLines(first, last, strings.Split(m.yankBuffer, "\n")) {
+		m.statusMsg = linesStatus(m.yankBuffer, "put")
+	}
+	m.exitVisualMode(first)
+}
+
+// indentSelection indents lines first through last by a tab stop of
+// spaces, or outdents them for dir < 0. Blank lines are left alone.
+func (m *Model) indentSelection(first, last, dir int) {
+	width := m.tabWidth
+	if width < 1 {
+		width = document.DefaultTabWidth
+	}
+	lines := m.GetLines()
+	if last >= len(lines) {
+		return
+	}
+	changed := slices.Clone(lines[first : last+1])
+	for i, line := range changed {
+		switch {
+		case strings.TrimSpace(line) == "":
+		case dir > 0:
+			changed[i] = strings.Repeat(" ", width) + line
+		case strings.HasPrefix(line, "\t"):
+			changed[i] = line[1:]
+		default:
+			spaces := len(line) - len(strings.TrimLeft(line, " "))
+			changed[i] = line[min(spaces, width):]
+		}
+	}
+	if m.replaceLines(first, last, changed) {
+		verb := "indented"
+		if dir < 0 {
+			verb = "outdented"
+		}
+		m.statusMsg = linesStatus(strings.Join(changed, "\n"), verb)
+	}
+	m.exitVisualMode(first)
+}
+
+// replaceLines replaces lines first through last with lines, as one undo
+// step, and re-evaluates what the change affects. It reports whether the
+// document changed.
+func (m *Model) replaceLines(first, last int, lines []string) bool {
+	current := m.GetLines()
+	if last >= len(current) {
+		return false
+	}
+	updated := slices.Concat(current[:first], lines, current[last+1:])
+	if slices.Equal(updated, current) {
+		return false
+	}
+	if err := m.reparseAffected(strings.Join(updated, "\n")); err != nil {
+		m.statusMsg = fmt.Sprintf("Edit failed: %v", err)
+		m.statusIsErr = true
+		return false
+	}
+	m.modified = true
+	m.pushUndoState()
+	m.InvalidateAlignedCache()
+	m.autoPinVariables()
+	return true
+}
+
+// reparseAffected replaces the document's content as reparse does, but
+// re-evaluates only the changed blocks and their dependents when the
+// variables each block defines are the same as before.
+func (m *Model) reparseAffected(content string) error {
+	before := blockDefinitions(m.doc)
+	result, err := m.doc.Reparse(m.doc.GetFrontmatter().Serialize() + content)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(blockDefinitions(m.doc), before) {
+		// Removed or reordered definitions can change any value
+		m.changedBlockIDs = make(map[string]bool)
+		m.eval = newEvaluator(m.filepath)
+		_ = m.eval.Evaluate(m.doc)
+		return nil
+	}
+	m.changedBlockIDs = make(map[string]bool)
+	for _, id := range result.AffectedBlockIDs {
+		m.changedBlockIDs[id] = true
+	}
+	m.reEvaluate()
+	return nil
+}
+
+// blockDefinitions lists the variables each calculation block defines,
+// in document order, with "" between blocks.
+func blockDefinitions(doc *document.Document) []string {
+	var names []string
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok {
+			names = append(names, cb.Variables()...)
+			names = append(names, "")
+		}
+	}
+	return names
+}
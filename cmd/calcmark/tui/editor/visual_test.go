@@ -0,0 +1,105 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newVisualModel returns an editor on two independent calculation blocks,
+// lines 0-1 and 5-6.
+func newVisualModel(t *testing.T) Model {
+	t.Helper()
+	doc, err := document.NewDocument("a = 1\nb = a + 1\n\nNotes on c.\n\nc = 5\nd = c * 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(doc)
+}
+
+// runes returns the key presses for typing s outside edit mode.
+func runes(s string) []tea.KeyMsg {
+	var keys []tea.KeyMsg
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+func TestVisualDeleteIsOneUndoStep(t *testing.T) {
+	m := newVisualModel(t)
+	m = press(m, runes("Vjj")...)
+	if m.mode != ModeVisual || !m.inSelection(0) || !m.inSelection(2) || m.inSelection(3) {
+		t.Fatalf("V then jj didn't select lines 0 to 2")
+	}
+
+	m = press(m, runes("d")...)
+	if got := strings.Join(m.GetLines(), "\n"); got != "Notes on c.\n\nc = 5\nd = c * 2" {
+		t.Fatalf("after d, document = %q", got)
+	}
+	if m.mode != ModeNormal || m.statusMsg != "3 lines deleted" {
+		t.Errorf("after d, mode %v, status %q", m.mode, m.statusMsg)
+	}
+	if m.yankBuffer != "a = 1\nb = a + 1\n" {
+		t.Errorf("deleted lines weren't yanked: %q", m.yankBuffer)
+	}
+	if a, _ := m.eval.GetEnvironment().Get("a"); a != nil {
+		t.Errorf("a = %v after its definition was deleted", a)
+	}
+
+	m = press(m, keyUndo)
+	if got := strings.Join(m.GetLines(), "\n"); got != "a = 1\nb = a + 1\n\nNotes on c.\n\nc = 5\nd = c * 2" {
+		t.Errorf("after undo, document = %q", got)
+	}
+}
+
+func TestVisualIndentReevaluatesAffectedBlocks(t *testing.T) {
+	m := newVisualModel(t)
+	first, _, _ := m.blockAt(0)
+	m.cursorLine = 5
+
+	m = press(m, runes("Vj>")...)
+	if got := m.GetLines()[5:]; got[0] != "    c = 5" || got[1] != "    d = c * 2" {
+		t.Fatalf("after >, lines = %q", got)
+	}
+	if m.changedBlockIDs[first.ID] {
+		t.Error("indenting the second block re-evaluated the first")
+	}
+	if d, _ := m.eval.GetEnvironment().Get("d"); d == nil || d.String() != "10" {
+		t.Errorf("d = %v, want 10", d)
+	}
+
+	m = press(m, runes("Vj<")...)
+	if got := strings.Join(m.GetLines()[5:], "\n"); got != "c = 5\nd = c * 2" {
+		t.Errorf("after <, lines = %q", got)
+	}
+}
+
+func TestVisualPutReplacesSelection(t *testing.T) {
+	m := newVisualModel(t)
+	m.cursorLine = 5
+	m = press(m, runes("yy")...)
+	m.cursorLine = 0
+	m = press(m, runes("Vjp")...)
+
+	if got := strings.Join(m.GetLines(), "\n"); got != "c = 5\n\nNotes on c.\n\nc = 5\nd = c * 2" {
+		t.Errorf("after p, document = %q", got)
+	}
+	if m.mode != ModeNormal {
+		t.Errorf("mode %v after p, want normal", m.mode)
+	}
+}
+
+func TestVisualEscCancels(t *testing.T) {
+	m := newVisualModel(t)
+	m = press(m, runes("Vj")...)
+	m = press(m, keyEsc)
+	if m.mode != ModeNormal || m.inSelection(0) {
+		t.Errorf("Esc left mode %v with a selection", m.mode)
+	}
+	if strings.Join(m.GetLines(), "\n") != "a = 1\nb = a + 1\n\nNotes on c.\n\nc = 5\nd = c * 2" {
+		t.Error("Esc changed the document")
+	}
+}
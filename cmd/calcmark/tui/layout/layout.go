@@ -0,0 +1,355 @@
+// Package layout aligns the two panes of a split view line for line.
+//
+// The left pane shows source lines and the right pane shows something
+// derived from each one, such as a calculation's result. Either side of a
+// line may wrap onto several visual lines, so the shorter side is padded to
+// keep every source line's content level with its derived content.
+//
+// Align builds the visual lines of both panes from content already wrapped to
+// each pane's width. The Model it returns always satisfies these invariants,
+// which Check verifies:
+//
+//   - Both panes have the same number of visual lines
+//   - Every visual line maps to a source line, and every aligned source line
+//     maps to its first visual line
+//   - Both mappings are monotonic: later source lines start on later visual
+//     lines, and visual lines never go back to an earlier source line
+//
+// A Model aligns either a whole document or a range of its lines (as a view
+// does for the lines around its viewport); only whole-document models map
+// every source line.
+package layout
+
+import "fmt"
+
+// LineKind categorizes how a visual line should be rendered.
+type LineKind int
+
+const (
+	// Normal is the first visual line of a source line, with its line number.
+	Normal LineKind = iota
+
+	// Wrapped is a wrapped continuation of the previous visual line.
+	Wrapped
+
+	// Padding is an empty line for alignment, when the other pane has more lines.
+	Padding
+
+	// Cursor is the first visual line of the cursor's source line.
+	Cursor
+
+	// CursorWrapped is a wrapped continuation of the cursor's source line.
+	CursorWrapped
+)
+
+// String returns the kind's name, for test failures and debugging.
+func (k LineKind) String() string {
+	switch k {
+	case Normal:
+		return "Normal"
+	case Wrapped:
+		return "Wrapped"
+	case Padding:
+		return "Padding"
+	case Cursor:
+		return "Cursor"
+	case CursorWrapped:
+		return "CursorWrapped"
+	default:
+		return fmt.Sprintf("LineKind(%d)", int(k))
+	}
+}
+
+// Line is a single visual line in either pane.
+type Line struct {
+	// Content is the text content for this visual line.
+	Content string
+
+	// SourceLineIdx is the source document line this visual line belongs to.
+	SourceLineIdx int
+
+	// LineNum is the display line number (1-indexed, 0 means no line number shown).
+	LineNum int
+
+	// Kind indicates the type of visual line.
+	Kind LineKind
+
+	// BlockID is the ID of the block this line belongs to (for preview styling).
+	BlockID string
+
+	// IsCalc indicates if this line is from a calculation block (for preview styling).
+	IsCalc bool
+}
+
+// Row is one source line's content in both panes, already wrapped to each
+// pane's width. Empty content counts as a single empty visual line.
+type Row struct {
+	SourceLine int      // Source line index (0-indexed)
+	Source     []string // Visual lines in the source pane
+	Preview    []string // Visual lines in the preview pane
+	BlockID    string
+	IsCalc     bool
+}
+
+// Model is the visual line structure of both panes.
+type Model struct {
+	// SourceLines contains all visual lines for the source pane.
+	// This may include wrapped continuation lines and padding lines.
+	SourceLines []Line
+
+	// PreviewLines contains all visual lines for the preview pane.
+	// Always has the same length as SourceLines for 1:1 alignment.
+	PreviewLines []Line
+
+	// SourceToVisual maps source line index to the first visual line index.
+	// Used for cursor positioning and scroll synchronization.
+	SourceToVisual map[int]int
+
+	// VisualToSource maps visual line index to source line index.
+	// Used for reverse lookups (e.g., clicking on a visual line).
+	VisualToSource map[int]int
+
+	// TotalSourceLines is the number of source lines in the document.
+	TotalSourceLines int
+
+	// TotalVisualLines is the total number of visual lines (len(SourceLines)).
+	TotalVisualLines int
+}
+
+// Align lays out rows, which must be in source line order, as the aligned
+// visual lines of both panes. totalSourceLines is the document's line count
+// and cursorLine the source line marked as the cursor.
+// This is a pure function - same inputs always produce same outputs.
+func Align(rows []Row, totalSourceLines, cursorLine int) Model {
+	var sourceLines []Line
+	var previewLines []Line
+	sourceToVisual := make(map[int]int)
+	visualToSource := make(map[int]int)
+
+	for _, row := range rows {
+		source, preview := row.Source, row.Preview
+		if len(source) == 0 {
+			source = []string{""}
+		}
+		if len(preview) == 0 {
+			preview = []string{""}
+		}
+		isCursor := row.SourceLine == cursorLine
+
+		// Record mapping: source line -> first visual line index
+		if _, exists := sourceToVisual[row.SourceLine]; !exists {
+			sourceToVisual[row.SourceLine] = len(sourceLines)
+		}
+
+		// Emit visual lines (source and preview in parallel), padding the
+		// side with fewer
+		for j := range max(len(source), len(preview)) {
+			visualToSource[len(sourceLines)] = row.SourceLine
+
+			sl := Line{SourceLineIdx: row.SourceLine, BlockID: row.BlockID, IsCalc: row.IsCalc, Kind: Padding}
+			if j < len(source) {
+				sl.Content = source[j]
+				sl.Kind = lineKind(j, isCursor)
+				if j == 0 {
+					sl.LineNum = row.SourceLine + 1
+				}
+			}
+			sourceLines = append(sourceLines, sl)
+
+			pl := Line{SourceLineIdx: row.SourceLine, BlockID: row.BlockID, IsCalc: row.IsCalc, Kind: Padding}
+			if j < len(preview) {
+				pl.Content = preview[j]
+				pl.Kind = lineKind(j, false)
+				if j == 0 {
+					pl.LineNum = row.SourceLine + 1
+				}
+			}
+			previewLines = append(previewLines, pl)
+		}
+	}
+
+	return Model{
+		SourceLines:      sourceLines,
+		PreviewLines:     previewLines,
+		SourceToVisual:   sourceToVisual,
+		VisualToSource:   visualToSource,
+		TotalSourceLines: totalSourceLines,
+		TotalVisualLines: len(sourceLines),
+	}
+}
+
+// lineKind returns the kind of a source line's j-th visual line.
+func lineKind(j int, isCursor bool) LineKind {
+	switch {
+	case j == 0 && isCursor:
+		return Cursor
+	case j == 0:
+		return Normal
+	case isCursor:
+		return CursorWrapped
+	default:
+		return Wrapped
+	}
+}
+
+// WithCursor returns a copy of the model with the cursor marks moved from
+// source line from to source line to. The source pane's lines are copied, so
+// the original model is unchanged; the mappings are shared.
+func (a *Model) WithCursor(from, to int) Model {
+	model := *a
+	if from == to {
+		return model
+	}
+	model.SourceLines = append([]Line(nil), a.SourceLines...)
+	for i := range model.SourceLines {
+		line := &model.SourceLines[i]
+		if line.SourceLineIdx != from && line.SourceLineIdx != to {
+			continue
+		}
+		isCursor := line.SourceLineIdx == to
+		switch line.Kind {
+		case Normal, Cursor:
+			line.Kind = lineKind(0, isCursor)
+		case Wrapped, CursorWrapped:
+			line.Kind = lineKind(1, isCursor)
+		}
+	}
+	return model
+}
+
+// CursorVisualLine returns the visual line index for the given source line.
+// Returns -1 if the source line is not in the mapping.
+func (a *Model) CursorVisualLine(sourceLine int) int {
+	if v, ok := a.SourceToVisual[sourceLine]; ok {
+		return v
+	}
+	return -1
+}
+
+// SourceLineAt returns the source line index for the given visual line.
+// Returns -1 if the visual line is out of bounds.
+func (a *Model) SourceLineAt(visualLine int) int {
+	if s, ok := a.VisualToSource[visualLine]; ok {
+		return s
+	}
+	return -1
+}
+
+// VisibleRange calculates the range of visual lines to display given scroll offset and height.
+// Returns (start, end) indices where end is exclusive.
+func (a *Model) VisibleRange(scrollOffset, height int) (start, end int) {
+	start = scrollOffset
+	if start < 0 {
+		start = 0
+	}
+	if start >= a.TotalVisualLines {
+		start = max(0, a.TotalVisualLines-1)
+	}
+
+	end = start + height
+	if end > a.TotalVisualLines {
+		end = a.TotalVisualLines
+	}
+
+	return start, end
+}
+
+// ScrollOffsetForCursor calculates the scroll offset needed to keep the cursor visible.
+// Returns the adjusted scroll offset.
+func (a *Model) ScrollOffsetForCursor(cursorSourceLine, currentScrollOffset, viewportHeight int) int {
+	cursorVisual := a.CursorVisualLine(cursorSourceLine)
+	if cursorVisual < 0 {
+		return currentScrollOffset
+	}
+
+	// Ensure cursor is within visible range
+	if cursorVisual < currentScrollOffset {
+		return cursorVisual
+	}
+	if cursorVisual >= currentScrollOffset+viewportHeight {
+		return cursorVisual - viewportHeight + 1
+	}
+
+	return currentScrollOffset
+}
+
+// Invariants holds consistency check results.
+type Invariants struct {
+	SourcePreviewMatch bool // Source and preview have same line count
+	MappingComplete    bool // All source lines have visual mappings (whole-document models only)
+	ReverseComplete    bool // All visual lines have source mappings
+	Monotonic          bool // Both mappings preserve source line order
+}
+
+// Invariants returns a set of boolean checks for the model's consistency.
+// Used for debugging and testing.
+func (a *Model) Invariants() Invariants {
+	// Check source/preview line count match
+	sourcePreviewMatch := len(a.SourceLines) == len(a.PreviewLines) && len(a.SourceLines) == a.TotalVisualLines
+
+	// Check all source lines have mappings
+	mappingComplete := true
+	for i := 0; i < a.TotalSourceLines; i++ {
+		if _, ok := a.SourceToVisual[i]; !ok {
+			mappingComplete = false
+			break
+		}
+	}
+
+	// Check visual-to-source mapping is complete, and agrees with the lines
+	reverseComplete := true
+	for i := 0; i < a.TotalVisualLines; i++ {
+		s, ok := a.VisualToSource[i]
+		if !ok || i >= len(a.SourceLines) || a.SourceLines[i].SourceLineIdx != s {
+			reverseComplete = false
+			break
+		}
+	}
+
+	return Invariants{
+		SourcePreviewMatch: sourcePreviewMatch,
+		MappingComplete:    mappingComplete,
+		ReverseComplete:    reverseComplete,
+		Monotonic:          a.monotonic(),
+	}
+}
+
+// monotonic reports whether visual lines never go back to an earlier source
+// line, and each source line's visual lines start where SourceToVisual says.
+func (a *Model) monotonic() bool {
+	prev := -1
+	for i := 0; i < a.TotalVisualLines; i++ {
+		s, ok := a.VisualToSource[i]
+		if !ok || s < prev {
+			return false
+		}
+		if s != prev {
+			if first, ok := a.SourceToVisual[s]; !ok || first != i {
+				return false
+			}
+		}
+		prev = s
+	}
+	for s, first := range a.SourceToVisual {
+		if a.VisualToSource[first] != s || first >= a.TotalVisualLines {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns an error describing the first invariant the model breaks,
+// or nil. Models from Align always pass; Check guards models built or
+// patched by hand.
+func (a *Model) Check() error {
+	inv := a.Invariants()
+	switch {
+	case !inv.SourcePreviewMatch:
+		return fmt.Errorf("layout: %d source lines but %d preview lines (total %d)", len(a.SourceLines), len(a.PreviewLines), a.TotalVisualLines)
+	case !inv.ReverseComplete:
+		return fmt.Errorf("layout: visual lines without a matching source line")
+	case !inv.Monotonic:
+		return fmt.Errorf("layout: visual lines out of source line order")
+	}
+	return nil
+}
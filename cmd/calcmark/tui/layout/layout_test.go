@@ -0,0 +1,263 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// lines returns n visual lines named after prefix.
+func lines(prefix string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return out
+}
+
+// checkModel fails the test if model breaks an invariant.
+func checkModel(t *testing.T, model Model) {
+	t.Helper()
+	if err := model.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	rows := []Row{
+		{SourceLine: 0, Source: []string{"# Header"}, Preview: []string{"Header"}, BlockID: "b1"},
+		{SourceLine: 1, Source: []string{"x = 10"}, Preview: []string{"x = 10"}, BlockID: "b2", IsCalc: true},
+		{SourceLine: 2, Source: []string{"note = ", "wrapped"}, Preview: []string{"short"}, BlockID: "b2", IsCalc: true},
+		{SourceLine: 3, Source: []string{"y"}, Preview: []string{"long ", "result ", "here"}, BlockID: "b2", IsCalc: true},
+	}
+	model := Align(rows, 4, 2)
+	checkModel(t, model)
+
+	if model.TotalSourceLines != 4 || model.TotalVisualLines != 7 {
+		t.Fatalf("totals = %d source, %d visual; want 4, 7", model.TotalSourceLines, model.TotalVisualLines)
+	}
+
+	want := []struct {
+		source, preview         string
+		sourceKind, previewKind LineKind
+		sourceLineNum           int
+		sourceLine              int
+	}{
+		{"# Header", "Header", Normal, Normal, 1, 0},
+		{"x = 10", "x = 10", Normal, Normal, 2, 1},
+		{"note = ", "short", Cursor, Normal, 3, 2},
+		{"wrapped", "", CursorWrapped, Padding, 0, 2},
+		{"y", "long ", Normal, Normal, 4, 3},
+		{"", "result ", Padding, Wrapped, 0, 3},
+		{"", "here", Padding, Wrapped, 0, 3},
+	}
+	for i, w := range want {
+		sl, pl := model.SourceLines[i], model.PreviewLines[i]
+		if sl.Content != w.source || pl.Content != w.preview {
+			t.Errorf("line %d content = %q | %q, want %q | %q", i, sl.Content, pl.Content, w.source, w.preview)
+		}
+		if sl.Kind != w.sourceKind || pl.Kind != w.previewKind {
+			t.Errorf("line %d kinds = %v | %v, want %v | %v", i, sl.Kind, pl.Kind, w.sourceKind, w.previewKind)
+		}
+		if sl.LineNum != w.sourceLineNum {
+			t.Errorf("line %d LineNum = %d, want %d", i, sl.LineNum, w.sourceLineNum)
+		}
+		if sl.SourceLineIdx != w.sourceLine || pl.SourceLineIdx != w.sourceLine || model.SourceLineAt(i) != w.sourceLine {
+			t.Errorf("line %d source line = %d | %d (at %d), want %d", i, sl.SourceLineIdx, pl.SourceLineIdx, model.SourceLineAt(i), w.sourceLine)
+		}
+	}
+
+	for source, visual := range map[int]int{0: 0, 1: 1, 2: 2, 3: 4} {
+		if got := model.CursorVisualLine(source); got != visual {
+			t.Errorf("CursorVisualLine(%d) = %d, want %d", source, got, visual)
+		}
+	}
+	if model.PreviewLines[1].BlockID != "b2" || !model.PreviewLines[1].IsCalc || model.PreviewLines[0].IsCalc {
+		t.Error("lines should carry their row's block")
+	}
+}
+
+// TestAlignExhaustive aligns every combination of one to three visual lines
+// on each side of three rows, with the cursor on each row, and checks the
+// invariants and that each row keeps its content in order.
+func TestAlignExhaustive(t *testing.T) {
+	const maxLines = 3
+	for combo := range maxLines * maxLines * maxLines * maxLines * maxLines * maxLines {
+		var counts [6]int
+		for i, c := 0, combo; i < len(counts); i, c = i+1, c/maxLines {
+			counts[i] = c%maxLines + 1
+		}
+
+		var rows []Row
+		height := 0
+		for r := range 3 {
+			rows = append(rows, Row{
+				SourceLine: r * 2, // Gaps, as in a model of part of a document
+				Source:     lines(fmt.Sprintf("s%d.", r), counts[r*2]),
+				Preview:    lines(fmt.Sprintf("p%d.", r), counts[r*2+1]),
+			})
+			height += max(counts[r*2], counts[r*2+1])
+		}
+
+		for cursor := range 6 {
+			model := Align(rows, 6, cursor)
+			if err := model.Check(); err != nil {
+				t.Fatalf("counts %v, cursor %d: %v", counts, cursor, err)
+			}
+			if model.TotalVisualLines != height {
+				t.Fatalf("counts %v: %d visual lines, want %d", counts, model.TotalVisualLines, height)
+			}
+			if model.Invariants().MappingComplete {
+				t.Fatalf("counts %v: a model of alternate lines should not map every line", counts)
+			}
+
+			for r, row := range rows {
+				first := model.CursorVisualLine(row.SourceLine)
+				var source, preview []string
+				for i := first; i < model.TotalVisualLines && model.SourceLineAt(i) == row.SourceLine; i++ {
+					sl, pl := model.SourceLines[i], model.PreviewLines[i]
+					if sl.Kind != Padding {
+						source = append(source, sl.Content)
+					}
+					if pl.Kind != Padding {
+						preview = append(preview, pl.Content)
+					}
+					isCursor := sl.Kind == Cursor || sl.Kind == CursorWrapped
+					if sl.Kind != Padding && isCursor != (row.SourceLine == cursor) {
+						t.Fatalf("counts %v, cursor %d: row %d line %d kind %v", counts, cursor, r, i, sl.Kind)
+					}
+				}
+				if strings.Join(source, ",") != strings.Join(row.Source, ",") || strings.Join(preview, ",") != strings.Join(row.Preview, ",") {
+					t.Fatalf("counts %v: row %d = %q | %q, want %q | %q", counts, r, source, preview, row.Source, row.Preview)
+				}
+			}
+		}
+	}
+}
+
+func TestAlignEmpty(t *testing.T) {
+	model := Align(nil, 0, 0)
+	checkModel(t, model)
+	if model.TotalVisualLines != 0 || !model.Invariants().MappingComplete {
+		t.Errorf("empty model = %+v", model)
+	}
+
+	// Rows without content still take a line, so they stay addressable
+	model = Align([]Row{{SourceLine: 0}, {SourceLine: 1, Source: []string{"x"}}}, 2, -1)
+	checkModel(t, model)
+	if model.TotalVisualLines != 2 || !model.Invariants().MappingComplete {
+		t.Errorf("rows without content = %d visual lines, want 2", model.TotalVisualLines)
+	}
+}
+
+func TestWithCursor(t *testing.T) {
+	rows := []Row{
+		{SourceLine: 0, Source: []string{"a", "b"}, Preview: []string{"1", "2", "3"}},
+		{SourceLine: 1, Source: []string{"c"}, Preview: []string{"4"}},
+	}
+	original := Align(rows, 2, 0)
+	moved := original.WithCursor(0, 1)
+	checkModel(t, moved)
+
+	want := Align(rows, 2, 1)
+	for i := range want.SourceLines {
+		if moved.SourceLines[i] != want.SourceLines[i] {
+			t.Errorf("line %d = %+v, want %+v", i, moved.SourceLines[i], want.SourceLines[i])
+		}
+	}
+	if original.SourceLines[0].Kind != Cursor || original.SourceLines[1].Kind != CursorWrapped {
+		t.Error("WithCursor should not change the original model")
+	}
+	if original.SourceLines[2].Kind != Padding || moved.SourceLines[2].Kind != Padding {
+		t.Error("padding should stay padding")
+	}
+}
+
+func TestVisibleRange(t *testing.T) {
+	model := Align([]Row{
+		{SourceLine: 0, Source: lines("a", 3)},
+		{SourceLine: 1, Source: lines("b", 2)},
+	}, 2, 0)
+
+	tests := []struct {
+		scroll, height int
+		start, end     int
+	}{
+		{0, 2, 0, 2},
+		{0, 10, 0, 5},
+		{3, 10, 3, 5},
+		{-1, 2, 0, 2},
+		{9, 2, 4, 5},
+	}
+	for _, tt := range tests {
+		start, end := model.VisibleRange(tt.scroll, tt.height)
+		if start != tt.start || end != tt.end {
+			t.Errorf("VisibleRange(%d, %d) = %d, %d, want %d, %d", tt.scroll, tt.height, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestScrollOffsetForCursor(t *testing.T) {
+	model := Align([]Row{
+		{SourceLine: 0, Source: lines("a", 3)},
+		{SourceLine: 1, Source: lines("b", 2)},
+		{SourceLine: 2, Source: lines("c", 1)},
+	}, 3, 0)
+
+	tests := []struct {
+		cursor, scroll, height int
+		want                   int
+	}{
+		{0, 0, 2, 0},  // Already visible
+		{2, 0, 2, 4},  // Below: scroll so the cursor is the last visible line
+		{1, 4, 2, 3},  // Above: scroll up to the cursor
+		{9, 1, 2, 1},  // Unknown line: unchanged
+		{1, 2, 10, 2}, // Visible in a tall viewport
+	}
+	for _, tt := range tests {
+		if got := model.ScrollOffsetForCursor(tt.cursor, tt.scroll, tt.height); got != tt.want {
+			t.Errorf("ScrollOffsetForCursor(%d, %d, %d) = %d, want %d", tt.cursor, tt.scroll, tt.height, got, tt.want)
+		}
+	}
+}
+
+// TestCheck checks hand-built models that break each invariant are caught.
+func TestCheck(t *testing.T) {
+	valid := func() Model {
+		return Align([]Row{
+			{SourceLine: 0, Source: lines("a", 2)},
+			{SourceLine: 1, Source: lines("b", 1)},
+		}, 2, 0)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(m *Model)
+		want    string
+	}{
+		{"preview line missing", func(m *Model) { m.PreviewLines = m.PreviewLines[1:] }, "preview lines"},
+		{"visual line unmapped", func(m *Model) { delete(m.VisualToSource, 1) }, "without a matching source line"},
+		{"visual line out of order", func(m *Model) {
+			m.VisualToSource[1], m.VisualToSource[2] = 1, 0
+			m.SourceLines[1].SourceLineIdx, m.SourceLines[2].SourceLineIdx = 1, 0
+		}, "out of source line order"},
+		{"source line starts mid-line", func(m *Model) { m.SourceToVisual[0] = 1 }, "out of source line order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := valid()
+			checkModel(t, model)
+			tt.corrupt(&model)
+			err := model.Check()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Check() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineKindString(t *testing.T) {
+	if Padding.String() != "Padding" || LineKind(9).String() != "LineKind(9)" {
+		t.Errorf("String() = %q, %q", Padding.String(), LineKind(9).String())
+	}
+}
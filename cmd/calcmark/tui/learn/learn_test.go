@@ -0,0 +1,169 @@
+package learn
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func init() {
+	// Initialize config for tests
+	config.Load()
+}
+
+// TestLessonAnswers checks every exercise accepts its own answer, so the
+// hints are right.
+func TestLessonAnswers(t *testing.T) {
+	ids := map[string]bool{}
+	for _, lesson := range Lessons {
+		if ids[lesson.ID] {
+			t.Errorf("duplicate lesson ID %q", lesson.ID)
+		}
+		ids[lesson.ID] = true
+		env := interpreter.NewEnvironment()
+		for _, ex := range lesson.Examples {
+			if _, err := evaluateLine(ex, env); err != nil {
+				t.Errorf("%s: example %q: %v", lesson.ID, ex, err)
+			}
+		}
+		for i, ex := range lesson.Exercises {
+			if _, err := ex.Check(ex.Answer); err != nil {
+				t.Errorf("%s exercise %d: answer %q rejected: %v", lesson.ID, i+1, ex.Answer, err)
+			}
+		}
+	}
+}
+
+func TestExerciseCheck(t *testing.T) {
+	ex := Exercise{Prompt: "Convert 10 miles to km.", Answer: "10 miles in km", Uses: "in"}
+	if _, err := ex.Check("10 miles in km"); err != nil {
+		t.Errorf("right answer rejected: %v", err)
+	}
+	if _, err := ex.Check("10 miles"); err == nil || !strings.Contains(err.Error(), "the answer is") {
+		t.Errorf("wrong answer: err = %v", err)
+	}
+	if _, err := ex.Check("16.09344 km"); err == nil || !strings.Contains(err.Error(), `"in"`) {
+		t.Errorf("right value without a conversion: err = %v", err)
+	}
+	if _, err := ex.Check("10 +"); err == nil {
+		t.Error("an answer that doesn't parse should be rejected")
+	}
+
+	define := Exercise{Answer: "rent = 1200", Define: "rent"}
+	if _, err := define.Check("1200"); err == nil || !strings.Contains(err.Error(), "rent =") {
+		t.Errorf("answer without the assignment: err = %v", err)
+	}
+	if _, err := define.Check("rent = 1,200"); err != nil {
+		t.Errorf("same value written differently rejected: %v", err)
+	}
+}
+
+func TestProgressSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calcmark", "learn.json")
+	p, err := LoadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, e, ok := p.Next(); !ok || l != 0 || e != 0 {
+		t.Fatalf("a new learner starts at lesson 0 exercise 0, got %d %d %v", l, e, ok)
+	}
+	if err := p.Complete(0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := LoadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Done(0, 0) || again.Done(0, 1) {
+		t.Errorf("reloaded progress = %v", again.Completed)
+	}
+	if l, e, _ := again.Next(); l != 0 || e != 1 {
+		t.Errorf("next exercise = %d %d, want 0 1", l, e)
+	}
+}
+
+func TestTutorialFlow(t *testing.T) {
+	p, _ := LoadProgress(filepath.Join(t.TempDir(), "learn.json"))
+	m := New(p)
+
+	m = answer(m, "12 * 8")
+	if m.correct || m.exercise != 0 {
+		t.Fatalf("a wrong answer should stay on the exercise: feedback %q", m.feedback)
+	}
+	m = update(m, tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.View(), "Hint: 12 * 7") {
+		t.Error("Tab should show the answer as a hint")
+	}
+
+	if m.input.Value() != "12 * 8" {
+		t.Errorf("a wrong answer should stay in the input for editing, got %q", m.input.Value())
+	}
+	m.input.Reset()
+	m = answer(m, "12 * 7")
+	if !m.correct || m.exercise != 1 || m.hint || m.input.Value() != "" {
+		t.Fatalf("a right answer should move to the next exercise: exercise %d, feedback %q", m.exercise, m.feedback)
+	}
+	if !p.Done(0, 0) {
+		t.Error("the completed exercise should be saved")
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyCtrlN})
+	if m.lesson != 1 || m.exercise != 0 {
+		t.Errorf("Ctrl+N should skip to the next lesson, at %d %d", m.lesson, m.exercise)
+	}
+	if !strings.Contains(m.View(), "Lesson 2: Variables") {
+		t.Error("view should show the current lesson")
+	}
+	m = update(m, tea.KeyMsg{Type: tea.KeyCtrlP})
+	if m.lesson != 0 {
+		t.Errorf("Ctrl+P should go back a lesson, at %d", m.lesson)
+	}
+
+	// Resuming starts at the first exercise left
+	if resumed := New(p); resumed.lesson != 0 || resumed.exercise != 1 {
+		t.Errorf("resumed at %d %d, want 0 1", resumed.lesson, resumed.exercise)
+	}
+}
+
+func TestTutorialFinished(t *testing.T) {
+	p, _ := LoadProgress(filepath.Join(t.TempDir(), "learn.json"))
+	for l, lesson := range Lessons {
+		for e := range lesson.Exercises {
+			p.Completed[exerciseKey(l, e)] = true
+		}
+	}
+	delete(p.Completed, exerciseKey(len(Lessons)-1, 1))
+
+	m := New(p)
+	ex := Lessons[len(Lessons)-1].Exercises[1]
+	m = answer(m, ex.Answer)
+	if !m.finished || !strings.Contains(m.View(), "finished every lesson") {
+		t.Errorf("completing the last exercise should finish the tutorial: %q", m.feedback)
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.Quitting() {
+		t.Error("Esc should quit")
+	}
+}
+
+func answer(m Model, text string) Model {
+	for _, r := range text {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		}
+		m = update(m, msg)
+	}
+	return update(m, tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func update(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
@@ -0,0 +1,163 @@
+// Package learn is the interactive tutorial behind `cm learn`: short lessons
+// on CalcMark syntax, each with exercises the evaluator checks.
+package learn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+// Lesson teaches one part of the language.
+type Lesson struct {
+	ID        string // Stable key for saved progress
+	Title     string
+	Text      []string // Explanation, one paragraph per entry
+	Examples  []string // CalcMark lines shown with their results
+	Exercises []Exercise
+}
+
+// Exercise asks for a calculation. An answer is right when it gives the
+// same value as Answer, evaluated after Setup.
+type Exercise struct {
+	Prompt string
+	Setup  string // Definitions in scope for the answer, "" for none
+	Answer string // A correct answer, shown as the hint
+	Define string // Variable the answer must assign, "" for none
+	Uses   string // Text the answer must contain, e.g. "in" for conversions
+}
+
+// Lessons are the tutorial's lessons in order.
+var Lessons = []Lesson{
+	{
+		ID:    "numbers",
+		Title: "Numbers",
+		Text: []string{
+			"A CalcMark line is a calculation: type an expression and its result appears beside it. The usual operators work, with parentheses for grouping.",
+			"Write large numbers with thousands separators if you like, and percentages with %. \"of\" takes a percentage of a number.",
+		},
+		Examples: []string{"(3 + 4) * 2", "1,500 + 2,500", "20% of 80"},
+		Exercises: []Exercise{
+			{Prompt: "Multiply 12 by 7.", Answer: "12 * 7"},
+			{Prompt: "Add one million to 250,000, writing both with separators.", Answer: "1,000,000 + 250,000", Uses: ","},
+			{Prompt: "Work out 15% of 240.", Answer: "15% of 240", Uses: "%"},
+		},
+	},
+	{
+		ID:    "variables",
+		Title: "Variables",
+		Text: []string{
+			"name = expression stores a result under a name. Later lines use the name, and change when it does.",
+			"Names are letters, digits, and underscores, starting with a letter.",
+		},
+		Examples: []string{"hours = 40", "rate = 25", "weekly = hours * rate"},
+		Exercises: []Exercise{
+			{Prompt: "Set rent to 1200.", Answer: "rent = 1200", Define: "rent"},
+			{Prompt: "rent is 1200. Set yearly to a year of rent.", Setup: "rent = 1200", Answer: "yearly = rent * 12", Define: "yearly", Uses: "rent"},
+		},
+	},
+	{
+		ID:    "currencies",
+		Title: "Currencies",
+		Text: []string{
+			"Put a currency symbol or code on a number to make it money: $20, €15, 100 GBP. Money keeps its currency through arithmetic and shows two decimal places.",
+		},
+		Examples: []string{"$20 * 3", "€15 + €10", "price = $100", "price * 1.08"},
+		Exercises: []Exercise{
+			{Prompt: "A ticket costs $45. What do 4 tickets cost?", Answer: "$45 * 4", Uses: "$"},
+			{Prompt: "price is $80. Set total to price plus 10% tax.", Setup: "price = $80", Answer: "total = price * 1.1", Define: "total", Uses: "price"},
+		},
+	},
+	{
+		ID:    "units",
+		Title: "Units",
+		Text: []string{
+			"Numbers can carry units such as km, m, kg, or lb. Adding lengths in different units converts between them, and \"in\" converts a result to another unit.",
+		},
+		Examples: []string{"5 km + 300 m", "100 kg in lb", "3 km / 1 hour"},
+		Exercises: []Exercise{
+			{Prompt: "Add 2 km and 450 m.", Answer: "2 km + 450 m", Uses: "km"},
+			{Prompt: "Convert 10 miles to km.", Answer: "10 miles in km", Uses: "in"},
+		},
+	},
+	{
+		ID:    "dates",
+		Title: "Dates and durations",
+		Text: []string{
+			"Write dates as Jan 1 2025 and durations as 30 days or 2 hours. Adding a duration to a date gives a date; durations add up to a duration.",
+		},
+		Examples: []string{"Jan 1 2025 + 30 days", "2 hours + 30 minutes"},
+		Exercises: []Exercise{
+			{Prompt: "What date is 90 days after Mar 1 2025?", Answer: "Mar 1 2025 + 90 days", Uses: "days"},
+			{Prompt: "Add 1 hour and 45 minutes.", Answer: "1 hour + 45 minutes", Uses: "minutes"},
+		},
+	},
+}
+
+// Check evaluates an answer to the exercise and returns its result. It
+// returns an error saying what's wrong when the answer doesn't evaluate or
+// isn't right.
+func (e Exercise) Check(answer string) (string, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return "", fmt.Errorf("type a calculation, or press Tab for a hint")
+	}
+	got, err := evaluate(e.Setup, answer, e.Define)
+	if err != nil {
+		return "", err
+	}
+	want, err := evaluate(e.Setup, e.Answer, e.Define)
+	if err != nil {
+		return "", fmt.Errorf("exercise answer %q: %w", e.Answer, err)
+	}
+	if got != want {
+		return got, fmt.Errorf("that gives %s; the answer is %s", got, want)
+	}
+	if e.Uses != "" && !strings.Contains(answer, e.Uses) {
+		return got, fmt.Errorf("right value, but try writing it with %q", e.Uses)
+	}
+	return got, nil
+}
+
+// evaluate runs setup and then source, returning the value of variable, or
+// of source's last line if variable is "".
+func evaluate(setup, source, variable string) (string, error) {
+	env := interpreter.NewEnvironment()
+	if setup != "" {
+		if err := interpreter.Evaluate(setup, env); err != nil {
+			return "", err
+		}
+	}
+	last, err := evaluateLine(source, env)
+	if err != nil {
+		return "", err
+	}
+	if variable != "" {
+		v, ok := env.Get(variable)
+		if !ok {
+			return "", fmt.Errorf("assign the result to %s, as in %s = ...", variable, variable)
+		}
+		return display.Format(v), nil
+	}
+	if last == "" {
+		return "", fmt.Errorf("that has no result")
+	}
+	return last, nil
+}
+
+// evaluateLine evaluates source in env and returns the displayed result of
+// its last line, "" if it has none.
+func evaluateLine(source string, env *interpreter.Environment) (string, error) {
+	nodes, err := parser.Parse(source + "\n")
+	if err != nil {
+		return "", err
+	}
+	results, err := interpreter.NewInterpreterWithEnv(env).Eval(nodes)
+	if err != nil || len(results) == 0 || results[len(results)-1] == nil {
+		return "", err
+	}
+	return display.Format(results[len(results)-1]), nil
+}
@@ -0,0 +1,162 @@
+package learn
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Model is the tutorial: the current lesson and exercise, the learner's
+// answer, and feedback on the last one checked. Implements tea.Model.
+type Model struct {
+	progress *Progress
+	lesson   int
+	exercise int
+	finished bool // Every exercise is completed
+
+	input    textinput.Model
+	feedback string
+	correct  bool // Whether feedback is for a right answer
+	hint     bool // Whether the current exercise's answer is shown
+
+	quitting bool
+	width    int
+	height   int
+	styles   config.Styles
+}
+
+// New creates the tutorial, starting at the first exercise progress hasn't
+// completed.
+func New(progress *Progress) Model {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "Type a calculation"
+	ti.Focus()
+	ti.CharLimit = 200
+	ti.Width = 70
+
+	m := Model{
+		progress: progress,
+		input:    ti,
+		width:    80,
+		height:   24,
+		styles:   config.GetStyles(),
+	}
+	m.lesson, m.exercise, m.finished = 0, 0, true
+	if l, e, ok := progress.Next(); ok {
+		m.lesson, m.exercise, m.finished = l, e, false
+	}
+	return m
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Quitting reports whether the learner has left the tutorial.
+func (m Model) Quitting() bool {
+	return m.quitting
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.input.Width = max(20, msg.Width-4)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC:
+			m.quitting = true
+			return m, tea.Quit
+		case tea.KeyEnter:
+			m.check()
+			return m, nil
+		case tea.KeyTab:
+			m.hint = !m.finished
+			return m, nil
+		case tea.KeyCtrlN:
+			m.nextLesson()
+			return m, nil
+		case tea.KeyCtrlP:
+			m.previousLesson()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// check evaluates the answer to the current exercise, moving on to the
+// next exercise when it is right.
+func (m *Model) check() {
+	if m.finished {
+		return
+	}
+	ex := Lessons[m.lesson].Exercises[m.exercise]
+	result, err := ex.Check(m.input.Value())
+	if err != nil {
+		m.feedback, m.correct = err.Error(), false
+		return
+	}
+
+	m.feedback, m.correct = fmt.Sprintf("Right: %s = %s", m.input.Value(), result), true
+	if err := m.progress.Complete(m.lesson, m.exercise); err != nil {
+		m.feedback += fmt.Sprintf(" (progress not saved: %v)", err)
+	}
+	m.advance()
+}
+
+// advance moves to the next exercise, or the next lesson after a lesson's
+// last one. After the last lesson it goes to the first exercise still to
+// do, if any.
+func (m *Model) advance() {
+	m.input.Reset()
+	m.hint = false
+	if m.exercise+1 < len(Lessons[m.lesson].Exercises) {
+		m.exercise++
+		return
+	}
+	if m.lesson+1 < len(Lessons) {
+		m.lesson, m.exercise = m.lesson+1, 0
+		return
+	}
+	if l, e, ok := m.progress.Next(); ok {
+		m.lesson, m.exercise = l, e
+		return
+	}
+	m.finished = true
+}
+
+// nextLesson skips to the start of the next lesson.
+func (m *Model) nextLesson() {
+	if m.finished || m.lesson+1 >= len(Lessons) {
+		return
+	}
+	m.goToLesson(m.lesson + 1)
+}
+
+// previousLesson goes back to the start of the previous lesson, or of the
+// last lesson once the tutorial is finished.
+func (m *Model) previousLesson() {
+	switch {
+	case m.finished:
+		m.finished = false
+		m.goToLesson(len(Lessons) - 1)
+	case m.lesson > 0:
+		m.goToLesson(m.lesson - 1)
+	}
+}
+
+func (m *Model) goToLesson(lesson int) {
+	m.lesson, m.exercise = lesson, 0
+	m.input.Reset()
+	m.feedback, m.correct, m.hint = "", false, false
+}
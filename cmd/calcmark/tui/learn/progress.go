@@ -0,0 +1,108 @@
+package learn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Progress records the exercises a learner has completed, saved as JSON so
+// the tutorial resumes where they left off.
+type Progress struct {
+	Completed map[string]bool `json:"completed"` // By exerciseKey
+
+	path string
+}
+
+// DefaultProgressPath returns where progress is saved:
+// ~/.config/calcmark/learn.json, next to the config file.
+func DefaultProgressPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "calcmark", "learn.json"), nil
+}
+
+// LoadProgress reads progress saved at path. A missing file is a new
+// learner with no progress.
+func LoadProgress(path string) (*Progress, error) {
+	p := &Progress{Completed: map[string]bool{}, path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read progress: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("read progress %s: %w", path, err)
+	}
+	if p.Completed == nil {
+		p.Completed = map[string]bool{}
+	}
+	return p, nil
+}
+
+// Complete marks an exercise done and saves the progress.
+func (p *Progress) Complete(lesson, exercise int) error {
+	p.Completed[exerciseKey(lesson, exercise)] = true
+	return p.Save()
+}
+
+// Done reports whether an exercise is completed.
+func (p *Progress) Done(lesson, exercise int) bool {
+	return p.Completed[exerciseKey(lesson, exercise)]
+}
+
+// LessonDone returns how many of a lesson's exercises are completed.
+func (p *Progress) LessonDone(lesson int) int {
+	n := 0
+	for i := range Lessons[lesson].Exercises {
+		if p.Done(lesson, i) {
+			n++
+		}
+	}
+	return n
+}
+
+// Next returns the first exercise not yet completed, and false once every
+// exercise is.
+func (p *Progress) Next() (lesson, exercise int, ok bool) {
+	for l, lesson := range Lessons {
+		for e := range lesson.Exercises {
+			if !p.Done(l, e) {
+				return l, e, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Save writes the progress to its file, creating the directory if needed.
+// Progress without a file, as in tests, isn't saved.
+func (p *Progress) Save() error {
+	if p.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return fmt.Errorf("save progress: %w", err)
+	}
+	if err := os.WriteFile(p.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("save progress: %w", err)
+	}
+	return nil
+}
+
+// exerciseKey identifies an exercise in saved progress: "units/1". Keys use
+// lesson IDs, so adding lessons keeps earlier progress.
+func exerciseKey(lesson, exercise int) string {
+	return fmt.Sprintf("%s/%d", Lessons[lesson].ID, exercise)
+}
@@ -0,0 +1,118 @@
+package learn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	currentStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+)
+
+// View implements tea.Model.
+func (m Model) View() string {
+	if m.quitting {
+		return ""
+	}
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("252")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1).
+		Width(m.width)
+	b.WriteString(titleStyle.Render("CalcMark Tutorial"))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderLessons())
+	b.WriteString("\n")
+
+	wrap := lipgloss.NewStyle().Width(max(20, m.width-2))
+	if m.finished {
+		b.WriteString(m.styles.Header.Render("You've finished every lesson."))
+		b.WriteString("\n\n")
+		b.WriteString(wrap.Render("Open a document with cm <file.cm> to put it to use, or read docs/README.md for the rest of the language."))
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render("Ctrl+P review the last lesson · Esc quit"))
+		return b.String()
+	}
+
+	lesson := Lessons[m.lesson]
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("Lesson %d: %s", m.lesson+1, lesson.Title)))
+	b.WriteString("\n\n")
+	for _, p := range lesson.Text {
+		b.WriteString(wrap.Render(p))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(renderExamples(lesson.Examples, m))
+	b.WriteString("\n")
+
+	ex := lesson.Exercises[m.exercise]
+	b.WriteString(m.styles.Prompt.Render(fmt.Sprintf("Exercise %d of %d", m.exercise+1, len(lesson.Exercises))))
+	b.WriteString("\n")
+	b.WriteString(wrap.Render(ex.Prompt))
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n")
+
+	switch {
+	case m.feedback != "" && m.correct:
+		b.WriteString(m.styles.Changed.Render("✓ " + m.feedback))
+		b.WriteString("\n")
+	case m.feedback != "":
+		b.WriteString(m.styles.Error.Render("✗ " + m.feedback))
+		b.WriteString("\n")
+	}
+	if m.hint {
+		b.WriteString(m.styles.Hint.Render("Hint: " + ex.Answer))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Enter check · Tab hint · Ctrl+N next lesson · Ctrl+P previous lesson · Esc quit"))
+	return b.String()
+}
+
+// renderLessons lists the lessons with how many of their exercises are
+// done, marking the current one.
+func (m Model) renderLessons() string {
+	var items []string
+	for i, lesson := range Lessons {
+		done, total := m.progress.LessonDone(i), len(lesson.Exercises)
+		item := fmt.Sprintf("%s %d/%d", lesson.Title, done, total)
+		if done == total {
+			item = "✓ " + item
+		}
+		if i == m.lesson && !m.finished {
+			item = currentStyle.Render(item)
+		} else {
+			item = dimStyle.Render(item)
+		}
+		items = append(items, item)
+	}
+	return strings.Join(items, dimStyle.Render(" · ")) + "\n"
+}
+
+// renderExamples shows example lines evaluated in order, each with its
+// result.
+func renderExamples(examples []string, m Model) string {
+	var b strings.Builder
+	env := interpreter.NewEnvironment()
+	width := 0
+	for _, ex := range examples {
+		width = max(width, len(ex))
+	}
+	for _, ex := range examples {
+		result := ""
+		if v, err := evaluateLine(ex, env); err == nil && v != "" {
+			result = m.styles.Output.Render("→ " + v)
+		}
+		b.WriteString("  " + m.styles.Example.Render(fmt.Sprintf("%-*s", width, ex)) + "  " + result + "\n")
+	}
+	return b.String()
+}
@@ -68,7 +68,7 @@ func New(doc *document.Document) Model {
 	ti.Width = 70
 
 	// Initialize evaluator
-	eval := implDoc.NewEvaluator()
+	eval := newEvaluator()
 	_ = eval.Evaluate(doc)
 
 	m := Model{
@@ -333,7 +333,7 @@ func (m Model) handleCommand(cmd string) (Model, tea.Cmd) {
 		m.pinnedVars = make(map[string]bool)
 		m.changedVars = make(map[string]bool)
 		m.doc, _ = document.NewDocument("")
-		m.eval = implDoc.NewEvaluator()
+		m.eval = newEvaluator()
 		_ = m.eval.Evaluate(m.doc)
 
 	case "pin":
@@ -445,7 +445,7 @@ func (m Model) evaluateExpression(expr string) Model {
 			return m
 		}
 
-		m.eval = implDoc.NewEvaluator()
+		m.eval = newEvaluator()
 		if err := m.eval.Evaluate(doc); err != nil {
 			m.addErrorToHistory(expr, err)
 			return m
@@ -466,9 +466,29 @@ func (m Model) evaluateExpression(expr string) Model {
 
 	// Add result to history
 	m.addResultToHistory(expr)
+	m.warnSizeLimits()
 	return m
 }
 
+// newEvaluator creates an evaluator with the configured size limits.
+func newEvaluator() *implDoc.Evaluator {
+	limits := config.Get().Limits
+	eval := implDoc.NewEvaluator()
+	eval.SetLimits(implDoc.Limits{
+		MaxVariables: limits.MaxVariables,
+		MaxResults:   limits.MaxResults,
+	})
+	return eval
+}
+
+// warnSizeLimits shows a warning once the session has grown past the
+// configured size limits ([limits] in config).
+func (m *Model) warnSizeLimits() {
+	if diags := m.eval.CheckLimits(m.doc); len(diags) > 0 {
+		m.err = fmt.Errorf("session size: %s (/reset to start over)", diags[0].Message)
+	}
+}
+
 // addResultToHistory adds the evaluation result to output history.
 func (m *Model) addResultToHistory(expr string) {
 	blocks := m.doc.GetBlocks()
@@ -586,7 +606,7 @@ func (m Model) Document() *document.Document {
 // SetDocument sets a new document.
 func (m *Model) SetDocument(doc *document.Document) {
 	m.doc = doc
-	m.eval = implDoc.NewEvaluator()
+	m.eval = newEvaluator()
 	_ = m.eval.Evaluate(doc)
 	m.populateFromDocument()
 }
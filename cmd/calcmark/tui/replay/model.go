@@ -0,0 +1,152 @@
+// Package replay steps through a session trace recorded by
+// `cm edit --trace`, showing each edit and the results after it, for
+// `cm replay`.
+package replay
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/trace"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Model shows one step of a trace at a time. Implements tea.Model.
+type Model struct {
+	trace  *trace.Trace
+	stops  []int // Steps forward and back move between, in order
+	stop   int   // Index into stops of the step shown
+	scroll int   // First result line shown
+
+	quitting bool
+	width    int
+	height   int
+	styles   config.Styles
+}
+
+// New creates a replay of tr from its first step. With name set, moving
+// forward and back stops only at the steps that changed that result.
+func New(tr *trace.Trace, name string) Model {
+	stops := []int{0}
+	for i := 1; i < len(tr.Steps); i++ {
+		if name == "" || changed(tr.Changes(i), name) {
+			stops = append(stops, i)
+		}
+	}
+	return Model{trace: tr, stops: stops, width: 80, height: 24, styles: config.GetStyles()}
+}
+
+// changed reports whether changes includes the result name.
+func changed(changes []trace.Change, name string) bool {
+	for _, c := range changes {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Step returns the index of the step shown.
+func (m Model) Step() int {
+	return m.stops[m.stop]
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "right", "l", "n", " ":
+			m.moveTo(m.stop + 1)
+		case "left", "h", "p":
+			m.moveTo(m.stop - 1)
+		case "g", "home":
+			m.moveTo(0)
+		case "G", "end":
+			m.moveTo(len(m.stops) - 1)
+		case "down", "j":
+			m.scroll = min(m.scroll+1, max(0, len(m.trace.Steps[m.Step()].Results)-1))
+		case "up", "k":
+			m.scroll = max(m.scroll-1, 0)
+		}
+	}
+	return m, nil
+}
+
+// moveTo shows the step at stop, kept within the stops.
+func (m *Model) moveTo(stop int) {
+	m.stop = max(0, min(stop, len(m.stops)-1))
+	m.scroll = 0
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	if m.quitting {
+		return ""
+	}
+	i := m.Step()
+	step := m.trace.Steps[i]
+	elapsed := step.Time.Sub(m.trace.Steps[0].Time).Round(time.Second)
+
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Width(m.width).Render(fmt.Sprintf("Replay  step %d of %d  +%s  %s",
+		i, len(m.trace.Steps)-1, elapsed, m.trace.Describe(i))))
+	b.WriteString("\n\n")
+	rows := 2
+
+	// The edit, then the results it changed
+	if e := step.Edit; e != nil && i > 0 {
+		for _, line := range e.Removed {
+			b.WriteString(m.styles.Error.Render("- "+line) + "\n")
+			rows++
+		}
+		for _, line := range e.Inserted {
+			b.WriteString(m.styles.Changed.Render("+ "+line) + "\n")
+			rows++
+		}
+		b.WriteString("\n")
+		rows++
+	}
+	changes := m.trace.Changes(i)
+	changedLines := make(map[int]bool, len(changes))
+	for _, c := range changes {
+		b.WriteString(m.styles.Var.Render(c.String()) + "\n")
+		changedLines[c.Line] = true
+		rows++
+	}
+	if len(changes) == 0 && i > 0 {
+		b.WriteString(m.styles.Hint.Render("No results changed") + "\n")
+		rows++
+	}
+
+	// Every result after the step, as many as fit
+	b.WriteString("\n" + m.styles.Header.Render("Results") + "\n")
+	rows += 3
+	results := step.Results[min(m.scroll, len(step.Results)):]
+	for _, r := range results[:min(len(results), max(1, m.height-rows))] {
+		line := fmt.Sprintf("%4d  %s  →  %s", r.Line, strings.TrimSpace(r.Source), r.Display())
+		switch {
+		case r.Error != "":
+			line = m.styles.Error.Render(line)
+		case changedLines[r.Line]:
+			line = m.styles.Changed.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString(m.styles.Help.Render("←/p back · →/n forward · g/G first/last · j/k scroll · q quit"))
+	return b.String()
+}
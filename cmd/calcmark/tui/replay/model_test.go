@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/config"
+	"github.com/CalcMark/go-calcmark/cmd/calcmark/trace"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func init() {
+	// Initialize config for tests
+	config.Load()
+}
+
+const session = `{"step":0,"time":"2025-01-01T09:00:00Z","edit":{"inserted":["rent = $1200","food = $400"]},"results":[{"line":1,"source":"rent = $1200","var":"rent","value":"$1,200.00"},{"line":2,"source":"food = $400","var":"food","value":"$400.00"}]}
+{"step":1,"time":"2025-01-01T09:00:30Z","edit":{"at":1,"removed":["food = $400"],"inserted":["food = $450"]},"results":[{"line":1,"source":"rent = $1200","var":"rent","value":"$1,200.00"},{"line":2,"source":"food = $450","var":"food","value":"$450.00"}]}
+{"step":2,"time":"2025-01-01T09:01:00Z","edit":{"at":0,"removed":["rent = $1200"],"inserted":["rent = $1250"]},"results":[{"line":1,"source":"rent = $1250","var":"rent","value":"$1,250.00"},{"line":2,"source":"food = $450","var":"food","value":"$450.00"}]}
+`
+
+func key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func press(m Model, keys ...tea.KeyMsg) Model {
+	for _, k := range keys {
+		tm, _ := m.Update(k)
+		m = tm.(Model)
+	}
+	return m
+}
+
+func readSession(t *testing.T) *trace.Trace {
+	t.Helper()
+	tr, err := trace.Read(strings.NewReader(session))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestStepping(t *testing.T) {
+	m := New(readSession(t), "")
+	m = press(m, key("n"))
+	if m.Step() != 1 {
+		t.Fatalf("step %d after n, want 1", m.Step())
+	}
+	view := m.View()
+	for _, want := range []string{"step 1 of 2", "+30s", "- food = $400", "+ food = $450", "food: $400.00 → $450.00"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q:\n%s", want, view)
+		}
+	}
+
+	m = press(m, key("n"), key("n"))
+	if m.Step() != 2 {
+		t.Errorf("step %d past the end, want 2", m.Step())
+	}
+	m = press(m, key("p"), key("g"))
+	if m.Step() != 0 {
+		t.Errorf("step %d after g, want 0", m.Step())
+	}
+}
+
+func TestSteppingByName(t *testing.T) {
+	m := New(readSession(t), "rent")
+	m = press(m, key("n"))
+	if m.Step() != 2 {
+		t.Errorf("step %d, want 2, the one changing rent", m.Step())
+	}
+}
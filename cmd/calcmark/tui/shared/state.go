@@ -76,6 +76,9 @@ func EditorSlashCommands() []SlashCommand {
 		{"eval", "/eval <expr>", "Quick evaluate"},
 		{"undo", "/undo", "Undo change"},
 		{"redo", "/redo", "Redo change"},
+		{"compare", "/compare <file|off>", "Compare results with a JSON export or document"},
+		{"trash", "/trash", "List deleted blocks"},
+		{"restore", "/restore [n]", "Restore a deleted block"},
 		{"wq", "/wq", "Save and quit"},
 	}
 }
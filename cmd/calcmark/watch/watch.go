@@ -0,0 +1,222 @@
+// Package watch re-evaluates a CalcMark file whenever it changes and
+// prints only the results that changed, used by `calcmark watch`.
+//
+// Each output line is a calculation's source followed by its value, as
+// `calcmark eval -v` prints it:
+//
+//	total = rent + food → $1650.00
+//
+// The first evaluation prints every result. After an edit, the document
+// is updated in place (see document.Document.Reparse) and only the edited
+// blocks and the blocks depending on them are re-evaluated.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounce is how long to wait after a change event before reloading, so
+// an editor's write-and-rename save is read once, complete.
+const debounce = 100 * time.Millisecond
+
+// Watcher evaluates successive versions of one document and reports the
+// results that changed between them.
+type Watcher struct {
+	filename     string
+	out          io.Writer
+	newEvaluator func() (*implDoc.Evaluator, error)
+
+	doc     *document.Document
+	eval    *implDoc.Evaluator
+	printed map[string]string // Output line by result key, as last printed
+}
+
+// New creates a watcher for filename that writes changed results to out.
+// newEvaluator creates the evaluator for full evaluations.
+func New(filename string, out io.Writer, newEvaluator func() (*implDoc.Evaluator, error)) *Watcher {
+	return &Watcher{
+		filename:     filename,
+		out:          out,
+		newEvaluator: newEvaluator,
+		printed:      make(map[string]string),
+	}
+}
+
+// Update evaluates source as the document's new content and prints the
+// results that differ from the last ones printed.
+func (w *Watcher) Update(source string) error {
+	if err := w.evaluate(source); err != nil {
+		return err
+	}
+	for _, line := range w.changedLines() {
+		if _, err := fmt.Fprintln(w.out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluate brings the document and its results up to date with source.
+func (w *Watcher) evaluate(source string) error {
+	if w.doc == nil {
+		doc, err := document.NewDocument(source)
+		if err != nil {
+			return fmt.Errorf("parse error: %w", err)
+		}
+		w.doc = doc
+		return w.evaluateAll()
+	}
+
+	oldFrontmatter := w.doc.GetFrontmatter().Serialize()
+	oldDefinitions := definitions(w.doc)
+	result, err := w.doc.Reparse(source)
+	if err != nil {
+		return fmt.Errorf("parse error: %w", err)
+	}
+
+	// Frontmatter is applied, and removed or moved definitions are
+	// forgotten, only by a full evaluation
+	if w.doc.GetFrontmatter().Serialize() != oldFrontmatter || !slices.Equal(definitions(w.doc), oldDefinitions) {
+		return w.evaluateAll()
+	}
+	_ = w.eval.EvaluateAffectedBlocks(w.doc, result.AffectedBlockIDs)
+	return nil
+}
+
+func (w *Watcher) evaluateAll() error {
+	eval, err := w.newEvaluator()
+	if err != nil {
+		return err
+	}
+	w.eval = eval
+	_ = w.eval.Evaluate(w.doc) // Errors are kept on their blocks and printed
+	return nil
+}
+
+// definitions lists the variables each calculation block defines, in
+// document order.
+func definitions(doc *document.Document) []string {
+	var names []string
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok {
+			names = append(names, cb.Variables()...)
+			names = append(names, "") // Block boundary
+		}
+	}
+	return names
+}
+
+// changedLines returns the output lines for results that are new or
+// differ from the last printed, in document order, and records them.
+func (w *Watcher) changedLines() []string {
+	var changed []string
+	current := make(map[string]string)
+	seen := make(map[string]int) // Repeats of the same source line
+
+	record := func(key, line string) {
+		seen[key]++
+		key = fmt.Sprintf("%s#%d", key, seen[key])
+		current[key] = line
+		if w.printed[key] != line {
+			changed = append(changed, line)
+		}
+	}
+
+	for _, node := range w.doc.GetBlocks() {
+		cb, ok := node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		results := cb.Results()
+		stmt := 0
+		for _, line := range cb.Source() {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if stmt < len(results) && results[stmt] != nil {
+				record(line, line+" → "+display.Format(results[stmt]))
+			}
+			stmt++
+		}
+		if err := cb.Error(); err != nil {
+			first := strings.TrimSpace(strings.Join(cb.Source(), " "))
+			record("error:"+first, "Error: "+err.Error())
+		}
+	}
+
+	w.printed = current
+	return changed
+}
+
+// Run evaluates the file, prints its results, and then prints changed
+// results each time the file is saved, until ctx is done. Problems reading
+// or parsing a version of the file are written to errOut and the watcher
+// waits for the next save.
+func (w *Watcher) Run(ctx context.Context, errOut io.Writer) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+	defer fsw.Close()
+
+	// Watch the directory: editors often save by writing a new file and
+	// renaming it over the old one, which ends a watch on the file itself
+	path, err := filepath.Abs(w.filename)
+	if err != nil {
+		return err
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	if err := w.reload(); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(errOut, "watch: %v\n", err)
+		case <-timer.C:
+			if err := w.reload(); err != nil {
+				fmt.Fprintln(errOut, err)
+			}
+		}
+	}
+}
+
+// reload reads the file and updates the watcher with its content.
+func (w *Watcher) reload() error {
+	content, err := os.ReadFile(w.filename)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	return w.Update(string(content))
+}
@@ -0,0 +1,149 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+)
+
+func newTestWatcher(out *bytes.Buffer) *Watcher {
+	return New("budget.cm", out, func() (*implDoc.Evaluator, error) {
+		return implDoc.NewEvaluator(), nil
+	})
+}
+
+func lines(out *bytes.Buffer) []string {
+	text := strings.TrimSpace(out.String())
+	out.Reset()
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func TestUpdatePrintsChangedResults(t *testing.T) {
+	var out bytes.Buffer
+	w := newTestWatcher(&out)
+
+	steps := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "first evaluation prints everything",
+			source: "# Budget\n\nrent = 100\nfood = 40\n\n\nother = 7\ntotal = rent + food\n",
+			want:   []string{"rent = 100 → 100", "food = 40 → 40", "other = 7 → 7", "total = rent + food → 140"},
+		},
+		{
+			name:   "edit prints the line and its dependents",
+			source: "# Budget\n\nrent = 120\nfood = 40\n\n\nother = 7\ntotal = rent + food\n",
+			want:   []string{"rent = 120 → 120", "total = rent + food → 160"},
+		},
+		{
+			name:   "text edit prints nothing",
+			source: "# Monthly budget\n\nrent = 120\nfood = 40\n\n\nother = 7\ntotal = rent + food\n",
+			want:   nil,
+		},
+		{
+			name:   "removed definition re-evaluates everything",
+			source: "# Monthly budget\n\nrent = 120\n\n\nother = 7\ntotal = rent * 2\n",
+			want:   []string{"total = rent * 2 → 240"},
+		},
+		{
+			name:   "frontmatter change",
+			source: "---\nglobals:\n  rent: 90\n---\nother = 7\ntotal = rent * 2\n",
+			want:   []string{"total = rent * 2 → 180"},
+		},
+	}
+
+	for _, step := range steps {
+		if err := w.Update(step.source); err != nil {
+			t.Fatalf("%s: Update failed: %v", step.name, err)
+		}
+		got := lines(&out)
+		if strings.Join(got, "\n") != strings.Join(step.want, "\n") {
+			t.Errorf("%s: printed\n%s\nwant\n%s", step.name, strings.Join(got, "\n"), strings.Join(step.want, "\n"))
+		}
+	}
+}
+
+func TestUpdatePrintsErrors(t *testing.T) {
+	var out bytes.Buffer
+	w := newTestWatcher(&out)
+	if err := w.Update("x = 5\n"); err != nil {
+		t.Fatal(err)
+	}
+	lines(&out)
+
+	if err := w.Update("x = 5\n\n\ny = missing * 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	got := lines(&out)
+	if len(got) != 1 || !strings.HasPrefix(got[0], "Error:") {
+		t.Errorf("printed %q, want one error line", got)
+	}
+}
+
+func TestRunReloadsOnSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "budget.cm")
+	if err := os.WriteFile(path, []byte("x = 1\ny = x * 10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &syncBuffer{}
+	w := New(path, out, func() (*implDoc.Evaluator, error) { return implDoc.NewEvaluator(), nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx, &bytes.Buffer{}) }()
+
+	waitFor(t, out, "y = x * 10 → 10")
+	if err := os.WriteFile(path, []byte("x = 2\ny = x * 10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, out, "y = x * 10 → 20")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
+
+func waitFor(t *testing.T, out *syncBuffer, text string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(out.String(), text) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("output never contained %q; got:\n%s", text, out.String())
+}
+
+// syncBuffer is a bytes.Buffer safe for the watcher goroutine to write
+// while the test reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
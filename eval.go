@@ -23,6 +23,7 @@ import (
 
 	"github.com/CalcMark/go-calcmark/impl/interpreter"
 	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/parser"
 	"github.com/CalcMark/go-calcmark/spec/semantic"
 )
@@ -44,7 +45,7 @@ func Eval(input string) (*Result, error) {
 }
 
 // evaluate is the internal pipeline that connects parser → semantic → interpreter.
-func evaluate(input string, env *interpreter.Environment) (*Result, error) {
+func evaluate(input string, env *interpreter.Environment, loc lexer.NumberLocale) (*Result, error) {
 	// 0. Parse frontmatter (if present)
 	frontmatter, remaining, fmErr := document.ParseFrontmatter(input)
 	if fmErr != nil {
@@ -53,6 +54,11 @@ func evaluate(input string, env *interpreter.Environment) (*Result, error) {
 
 	// Apply frontmatter to environment
 	if frontmatter != nil {
+		// A declared locale overrides the session's
+		if frontmatter.Locale != "" {
+			loc = frontmatter.NumberLocale()
+		}
+
 		// Set exchange rates
 		for key, rate := range frontmatter.Exchange {
 			from, to, err := document.ParseExchangeRateKey(key)
@@ -64,7 +70,7 @@ func evaluate(input string, env *interpreter.Environment) (*Result, error) {
 
 		// Parse and set global variables
 		if len(frontmatter.Globals) > 0 {
-			parsedGlobals, err := document.ParseGlobals(frontmatter.Globals)
+			parsedGlobals, err := document.ParseGlobalsWithLocale(frontmatter.Globals, loc)
 			if err != nil {
 				return nil, fmt.Errorf("frontmatter error: %w", err)
 			}
@@ -83,7 +89,7 @@ func evaluate(input string, env *interpreter.Environment) (*Result, error) {
 	}
 
 	// 1. Parse the input
-	nodes, parseErr := parser.Parse(input)
+	nodes, parseErr := parser.ParseWithLocale(input, loc)
 	if parseErr != nil {
 		return nil, fmt.Errorf("parse error: %w", parseErr)
 	}
@@ -155,9 +161,10 @@ func convertDiagnostics(semDiags []semantic.Diagnostic) []Diagnostic {
 	diags := make([]Diagnostic, len(semDiags))
 	for i, d := range semDiags {
 		diags[i] = Diagnostic{
-			Severity: Severity(d.Severity),
-			Code:     d.Code,
-			Message:  d.Message,
+			Severity:    Severity(d.Severity),
+			Code:        d.Code,
+			Message:     d.Message,
+			Suggestions: d.Suggestions,
 		}
 	}
 	return diags
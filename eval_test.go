@@ -449,3 +449,31 @@ my_var`,
 		})
 	}
 }
+
+func TestSessionLocale(t *testing.T) {
+	session := NewSession()
+	if err := session.SetLocale("de-DE"); err != nil {
+		t.Fatalf("SetLocale(de-DE) error = %v", err)
+	}
+
+	result, err := session.Eval("1.000,50 + 0,5")
+	if err != nil {
+		t.Fatalf("Eval error = %v", err)
+	}
+	if result.Value.String() != "1001" {
+		t.Errorf("Expected 1001, got %v", result.Value)
+	}
+
+	// Frontmatter locale overrides the session's
+	result, err = session.Eval("---\nlocale: fr-FR\n---\n2 000,5 * 2")
+	if err != nil {
+		t.Fatalf("Eval error = %v", err)
+	}
+	if result.Value.String() != "4001" {
+		t.Errorf("Expected 4001, got %v", result.Value)
+	}
+
+	if err := session.SetLocale("xx-YY"); err == nil {
+		t.Error("Expected error for unknown locale")
+	}
+}
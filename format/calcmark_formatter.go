@@ -31,18 +31,33 @@ func (f *CalcMarkFormatter) Format(w io.Writer, doc *document.Document, opts Opt
 	blocks := doc.GetBlocks()
 
 	// Filter out blocks that only contain frontmatter assignments
-	// (they're now serialized in the YAML frontmatter above)
+	// (they're now serialized in the YAML frontmatter above), and result or
+	// provenance lines from previous saves that were read back as text
 	filteredBlocks := make([]*document.BlockNode, 0, len(blocks))
 	for _, node := range blocks {
-		if !isOnlyFrontmatterBlock(node) {
-			filteredBlocks = append(filteredBlocks, node)
+		if isOnlyFrontmatterBlock(node) {
+			continue
+		}
+		if tb, ok := node.Block.(*document.TextBlock); ok && isResultBlock(tb) && strings.TrimSpace(strings.Join(tb.Source(), "")) != "" {
+			continue
 		}
+		filteredBlocks = append(filteredBlocks, node)
+	}
+
+	var provenance *provenanceTracker
+	if opts.Provenance {
+		provenance = newProvenanceTracker(doc, opts.ProvenanceTime)
 	}
 
 	for i, node := range filteredBlocks {
 		source := node.Block.Source()
 		isLastBlock := i == len(filteredBlocks)-1
 
+		var comments map[int]string
+		if cb, ok := node.Block.(*document.CalcBlock); ok && provenance != nil {
+			comments = provenance.blockComments(cb)
+		}
+
 		// Write source lines, filtering out legacy "# = ..." result comments
 		for j, line := range source {
 			isLastLine := j == len(source)-1
@@ -59,6 +74,9 @@ func (f *CalcMarkFormatter) Format(w io.Writer, doc *document.Document, opts Opt
 
 			fmt.Fprint(w, line)
 			fmt.Fprint(w, "\n")
+			if comment, ok := comments[j]; ok {
+				fmt.Fprintln(w, comment)
+			}
 		}
 
 		// Add block boundary between blocks (except after the last one)
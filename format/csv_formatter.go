@@ -0,0 +1,36 @@
+package format
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// CSVFormatter exports a document's variables as CSV, one row per global
+// and assignment (see VariableRows), for loading results into spreadsheets.
+type CSVFormatter struct{}
+
+// csvHeader names the CSV columns.
+var csvHeader = []string{"name", "expression", "value", "raw_value", "unit", "type"}
+
+// Extensions returns the file extensions handled by this formatter.
+func (f *CSVFormatter) Extensions() []string {
+	return []string{".csv"}
+}
+
+// Format writes the document's variables as CSV to the writer.
+func (f *CSVFormatter) Format(w io.Writer, doc *document.Document, opts Options) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, row := range VariableRows(doc) {
+		record := []string{row.Name, row.Expression, row.Value, row.Raw, row.Unit, row.Type}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
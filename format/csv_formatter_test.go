@@ -0,0 +1,56 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+func TestCSVFormatter(t *testing.T) {
+	source := "---\nglobals:\n  tax: \"0.2\"\n---\n# Budget\n\nrent = $1,200\nsize = 10 meters\nspeed = 100 MB/s\ntotal = rent * (1 + tax)\nrent * 2\n"
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&CSVFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Output is not valid CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"name", "expression", "value", "raw_value", "unit", "type"},
+		{"tax", "0.2", "0.2", "0.2", "", "number"},
+		{"rent", "$1,200", "$1200.00", "1200", "USD", "currency"},
+		{"size", "10 meters", "10 m", "10", "meters", "quantity"},
+		{"speed", "100 MB/s", "100 MB/s", "100", "MB/second", "rate"},
+		{"total", "rent * (1 + tax)", "$1440.00", "1440", "USD", "currency"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("CSV records =\n%q\nwant\n%q", records, want)
+	}
+}
+
+func TestCSVFormatterFailedBlock(t *testing.T) {
+	doc, err := document.NewDocument("y = x + 1\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	_ = implDoc.NewEvaluator().Evaluate(doc) // x is undefined
+
+	rows := VariableRows(doc)
+	if len(rows) != 1 || rows[0].Name != "y" || rows[0].Expression != "x + 1" || rows[0].Value != "" {
+		t.Errorf("VariableRows() = %+v, want y with no value", rows)
+	}
+}
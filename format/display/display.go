@@ -10,6 +10,20 @@
 //
 //	result := interpreter.Eval(...)
 //	fmt.Println(display.Format(result))  // "100K users" instead of "100000 users"
+//
+// Format passes values through a Pipeline of stages: unit normalization
+// (1000 m → 1 km), then locale formatting (K/M/B/T suffixes, decimal mark).
+// Embedders add middleware to the Default pipeline for rounding policies,
+// redaction, or highlighting:
+//
+//	display.Default.Use("redact", func(next display.Handler) display.Handler {
+//		return func(v display.Value) string {
+//			if _, ok := v.Type.(*types.Currency); ok {
+//				return "•••"
+//			}
+//			return next(v)
+//		}
+//	})
 package display
 
 import (
@@ -22,34 +36,144 @@ import (
 )
 
 // Format returns a human-readable string representation of any CalcMark type.
-// This is the main entry point for display formatting.
+// This is the main entry point for display formatting; it passes t through
+// the Default pipeline, including any middleware embedders have added.
 func Format(t types.Type) string {
-	if t == nil {
-		return ""
-	}
+	return Default.Format(t)
+}
 
-	switch v := t.(type) {
+// FormatWith formats t through the Default pipeline with a document's
+// settings, such as how it displays durations.
+func FormatWith(t types.Type, opts Options) string {
+	return Default.FormatWith(t, opts)
+}
+
+// text formats a value that has been through the earlier pipeline stages.
+// Lists format each element with formatElem.
+func text(v Value, formatElem func(types.Type) string) string {
+	switch t := v.Type.(type) {
+	case nil:
+		return ""
 	case *types.Number:
-		return FormatNumber(v.Value)
+		if t.IsPercentage() {
+			return FormatNumber(t.Value.Shift(2)) + "%"
+		}
+		return FormatNumber(t.Value)
 	case *types.Quantity:
-		return FormatQuantity(v)
+		return quantityText(t, v.Normalized)
 	case *types.Rate:
-		return FormatRate(v)
+		return rateText(t, v.Normalized)
 	case *types.Currency:
-		return FormatCurrency(v)
+		return FormatCurrency(t)
 	case *types.Duration:
-		return FormatDuration(v)
+		return HumanizeDuration(t, v.Durations)
 	case *types.Date:
-		return v.String() // Dates are already human-readable
+		return t.String() // Dates are already human-readable
 	case *types.Boolean:
-		return v.String()
+		return t.String()
 	case *types.Time:
-		return v.String()
+		return t.String()
+	case *types.Schedule:
+		return t.String()
+	case *types.List:
+		return listText(t, formatElem)
+	case *types.Distribution:
+		return distributionText(t, formatElem)
+	case *types.Basket:
+		return basketText(t, formatElem)
+	case *types.Table:
+		return tableText(t, formatElem)
 	default:
 		return fmt.Sprintf("%v", t)
 	}
 }
 
+// normalize rescales a quantity or rate in a known unit to the most
+// readable unit scale, marking v Normalized when the unit changes. Prices
+// ($/kg, $/hour) keep their units.
+func normalize(v Value) Value {
+	switch t := v.Type.(type) {
+	case *types.Quantity:
+		if numerator, _, ok := strings.Cut(t.Unit, "/"); ok && types.IsCurrencyCode(numerator) {
+			return v
+		}
+		if value, unit := NormalizeForDisplay(t.Value, t.Unit); unit != t.Unit {
+			v.Type, v.Normalized = types.NewQuantity(value, unit), true
+		}
+	case *types.Rate:
+		if t.Amount == nil || types.IsCurrencyCode(t.Amount.Unit) {
+			return v
+		}
+		if value, unit := NormalizeForDisplay(t.Amount.Value, t.Amount.Unit); unit != t.Amount.Unit {
+			v.Type, v.Normalized = types.NewRate(types.NewQuantity(value, unit), t.PerUnit), true
+		}
+	}
+	return v
+}
+
+// FormatList formats each element of a list for display.
+//
+// Examples:
+//
+//	FormatList([1000, 2000000]) → "[1K, 2M]"
+func FormatList(l *types.List) string {
+	return listText(l, Format)
+}
+
+func listText(l *types.List, formatElem func(types.Type) string) string {
+	parts := make([]string, len(l.Elements))
+	for i, elem := range l.Elements {
+		parts[i] = formatElem(elem)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// distributionText formats a distribution's mean and the range 90% of its
+// samples fall in, each with formatElem: "~$99.61 (p5 $75.28, p95 $123.45)".
+func distributionText(d *types.Distribution, formatElem func(types.Type) string) string {
+	if d.Len() == 0 {
+		return d.String()
+	}
+	s := d.Summary()
+	return fmt.Sprintf("~%s (p5 %s, p95 %s)", formatElem(s.Mean), formatElem(s.P5), formatElem(s.P95))
+}
+
+// basketText formats a currency basket as a sum of its weights, each as a
+// currency with formatElem: "$0.60 + €0.30 + £0.10".
+func basketText(b *types.Basket, formatElem func(types.Type) string) string {
+	if len(b.Weights) == 0 {
+		return b.String()
+	}
+	var sb strings.Builder
+	for i, w := range b.Weights {
+		amount := w.Amount
+		if i > 0 {
+			if amount.IsNegative() {
+				sb.WriteString(" - ")
+				amount = amount.Neg()
+			} else {
+				sb.WriteString(" + ")
+			}
+		}
+		sb.WriteString(formatElem(types.NewCurrency(amount, types.GetCurrencySymbol(w.Code))))
+	}
+	return sb.String()
+}
+
+// tableText formats a one-row table's values with formatElem: "period 12,
+// payment $1.8K, ...". Larger tables are described by their shape; exports
+// show them in full.
+func tableText(t *types.Table, formatElem func(types.Type) string) string {
+	if t.Len() != 1 {
+		return t.String()
+	}
+	parts := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		parts[i] = column + " " + formatElem(t.Rows[0][i])
+	}
+	return strings.Join(parts, ", ")
+}
+
 // FormatNumber formats a decimal number in human-readable form.
 // Uses K/M/B/T suffixes for large numbers, preserves small numbers as-is.
 //
@@ -73,19 +197,25 @@ func FormatNumber(value decimal.Decimal) string {
 //	FormatQuantity(1000 m) → "1 km"
 //	FormatQuantity(23400000 GB) → "22.31 PB"
 //	FormatQuantity(100000 users) → "100K users"
+//	FormatQuantity(5 $/kg) → "$5.00/kg"
 func FormatQuantity(q *types.Quantity) string {
 	if q == nil {
 		return ""
 	}
+	v := normalize(Value{Type: q})
+	return quantityText(v.Type.(*types.Quantity), v.Normalized)
+}
 
-	// Try to normalize to a better unit (e.g., 1000 m → 1 km)
-	normValue, normUnit := NormalizeForDisplay(q.Value, q.Unit)
-
-	// If normalization changed the unit, use the normalized form without K/M/B/T
-	if normUnit != q.Unit {
-		return formatNormalizedQuantity(normValue, normUnit)
+// quantityText formats a quantity, with K/M/B/T suffixes unless it has been
+// normalized to a unit that encodes the magnitude.
+func quantityText(q *types.Quantity, normalized bool) string {
+	// Currency per unit (e.g., 5 $/kg) reads as a price: "$5.00/kg"
+	if numerator, denominator, ok := strings.Cut(q.Unit, "/"); ok && types.IsCurrencyCode(numerator) {
+		return fmt.Sprintf("%s/%s", FormatCurrency(types.NewCurrency(q.Value, numerator)), denominator)
+	}
+	if normalized {
+		return formatNormalizedQuantity(q.Value, q.Unit)
 	}
-
 	// Unknown unit: fall back to K/M/B/T number suffixes
 	return formatWithSuffix(q.Value, q.Unit)
 }
@@ -97,26 +227,31 @@ func FormatQuantity(q *types.Quantity) string {
 //
 //	FormatRate(1000000 bytes/s) → "976.56 KB/s"
 //	FormatRate(100000 users/day) → "100K users/day"
+//	FormatRate(50 $/hour) → "$50.00/h"
 func FormatRate(r *types.Rate) string {
+	v := normalize(Value{Type: r})
+	return rateText(v.Type.(*types.Rate), v.Normalized)
+}
+
+// rateText formats a rate, with K/M/B/T suffixes unless its amount has been
+// normalized.
+func rateText(r *types.Rate, normalized bool) string {
 	if r == nil || r.Amount == nil {
 		return "0/s"
 	}
 
-	// Try to normalize the amount to a better unit
-	normValue, normUnit := NormalizeForDisplay(r.Amount.Value, r.Amount.Unit)
 	timeAbbrev := abbreviateTimeUnit(r.PerUnit)
 
-	// If normalization changed the unit, use the normalized form
-	if normUnit != r.Amount.Unit {
-		numStr := formatNormalizedQuantity(normValue, normUnit)
-		// Remove the unit from the formatted quantity (it's already included)
-		// formatNormalizedQuantity returns "value unit", we need "value unit/time"
-		return fmt.Sprintf("%s/%s", numStr, timeAbbrev)
+	// Currency rates read as prices: "$50.00/h" rather than "50 $/h"
+	if types.IsCurrencyCode(r.Amount.Unit) {
+		return fmt.Sprintf("%s/%s", FormatCurrency(types.NewCurrency(r.Amount.Value, r.Amount.Unit)), timeAbbrev)
+	}
+	if normalized {
+		return fmt.Sprintf("%s/%s", formatNormalizedQuantity(r.Amount.Value, r.Amount.Unit), timeAbbrev)
 	}
 
 	// Unknown unit: fall back to K/M/B/T number suffixes
-	numStr := formatWithSuffix(r.Amount.Value, r.Amount.Unit)
-	return fmt.Sprintf("%s/%s", numStr, timeAbbrev)
+	return fmt.Sprintf("%s/%s", formatWithSuffix(r.Amount.Value, r.Amount.Unit), timeAbbrev)
 }
 
 // FormatCurrency formats a currency value in human-readable form.
@@ -140,7 +275,7 @@ func FormatCurrency(c *types.Currency) string {
 
 	// For large values, use suffix notation
 	numStr := formatNumberWithSuffix(c.Value)
-	return fmt.Sprintf("%s%s", c.Symbol, numStr)
+	return fmt.Sprintf("%s%s", c.Symbol, numStr) + c.RealLabel()
 }
 
 // FormatDuration formats a duration in human-readable form.
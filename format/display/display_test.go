@@ -67,12 +67,14 @@ func TestFormatQuantity(t *testing.T) {
 		expected string
 	}{
 		{"100K users", "100000", "users", "100K users"},             // arbitrary unit: uses K/M/B/T
-		{"1.5M bytes normalized", "1500000", "bytes", "1.43 MB"},    // known unit: uses unit normalization
+		{"1.5M bytes normalized", "1500000", "bytes", "1.5 MB"},     // known unit: uses unit normalization
 		{"small quantity", "42", "items", "42 items"},               // arbitrary unit: stays as-is
 		{"decimal quantity normalized", "3.14", "meters", "3.14 m"}, // known unit: uses canonical symbol
-		{"large GB normalized", "23400000", "GB", "22.3 PB"},        // the original problem case!
+		{"large GB normalized", "23400000", "GB", "23.4 PB"},        // the original problem case!
 		{"1000 meters to km", "1000", "m", "1 km"},                  // meters → kilometers
 		{"5280 feet to miles", "5280", "feet", "1 mi"},              // feet → miles
+		{"compound unit", "5", "kg/l", "5 kg/l"},                    // compound: kept as written
+		{"currency per unit", "5", "$/kg", "$5.00/kg"},              // currency numerator reads as a price
 	}
 
 	for _, tt := range tests {
@@ -95,10 +97,11 @@ func TestFormatRate(t *testing.T) {
 		perUnit  string
 		expected string
 	}{
-		{"100K users/day", "100000", "users", "day", "100K users/day"},         // arbitrary unit
-		{"1.5M bytes/s normalized", "1500000", "bytes", "second", "1.43 MB/s"}, // known unit: normalized
-		{"small rate", "100", "requests", "minute", "100 requests/min"},        // arbitrary unit
-		{"1000 meters/hour", "1000", "m", "hour", "1 km/h"},                    // meters → km
+		{"100K users/day", "100000", "users", "day", "100K users/day"},        // arbitrary unit
+		{"1.5M bytes/s normalized", "1500000", "bytes", "second", "1.5 MB/s"}, // known unit: normalized
+		{"small rate", "100", "requests", "minute", "100 requests/min"},       // arbitrary unit
+		{"1000 meters/hour", "1000", "m", "hour", "1 km/h"},                   // meters → km
+		{"currency rate", "50", "$", "hour", "$50.00/h"},                      // currency reads as a price
 	}
 
 	for _, tt := range tests {
@@ -148,6 +151,11 @@ func TestFormat(t *testing.T) {
 			value:    types.NewNumber(decimal.NewFromInt(100000)),
 			expected: "100K",
 		},
+		{
+			name:     "percentage",
+			value:    types.NewPercentage(decimal.RequireFromString("-0.2")),
+			expected: "-20%",
+		},
 		{
 			name:     "quantity",
 			value:    types.NewQuantity(decimal.NewFromInt(1500000), "users"),
@@ -158,6 +166,14 @@ func TestFormat(t *testing.T) {
 			value:    types.NewBoolean(true),
 			expected: "true",
 		},
+		{
+			name: "list",
+			value: types.NewList([]types.Type{
+				types.NewNumber(decimal.NewFromInt(1000)),
+				types.NewNumber(decimal.NewFromInt(2500000)),
+			}),
+			expected: "[1K, 2.5M]",
+		},
 		{
 			name:     "nil",
 			value:    nil,
@@ -0,0 +1,92 @@
+package display
+
+import (
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// DurationStyle is how durations are displayed. Documents choose one with
+// the durations frontmatter key.
+type DurationStyle string
+
+const (
+	DurationAsComputed DurationStyle = ""      // 90061 seconds
+	DurationLong       DurationStyle = "long"  // 1 day 1 hour 1 minute 1 second
+	DurationShort      DurationStyle = "short" // 1d 1h 1m 1s
+)
+
+// durationPart is one unit of a humanized duration, with its long and short
+// names.
+type durationPart struct {
+	value       decimal.Decimal
+	name, short string
+}
+
+// HumanizeDuration writes a duration as days, hours, minutes, and seconds,
+// leaving out units that are zero. Months and years split into years and
+// months instead, since they are calendar lengths rather than fixed ones.
+// Business days, and DurationAsComputed, keep the duration as computed.
+//
+// Examples:
+//
+//	HumanizeDuration(90061 seconds, DurationLong)  → "1 day 1 hour 1 minute 1 second"
+//	HumanizeDuration(90061 seconds, DurationShort) → "1d 1h 1m 1s"
+//	HumanizeDuration(18 months, DurationLong)      → "1 year 6 months"
+//	HumanizeDuration(1500 milliseconds, DurationShort) → "1.5s"
+func HumanizeDuration(d *types.Duration, style DurationStyle) string {
+	if d == nil {
+		return ""
+	}
+	if style == DurationAsComputed || d.IsBusinessDays() {
+		return FormatDuration(d)
+	}
+
+	var parts []durationPart
+	switch d.Unit {
+	case "month", "months", "year", "years":
+		months := d.Value.Abs()
+		if strings.HasPrefix(d.Unit, "year") {
+			months = months.Mul(decimal.NewFromInt(12))
+		}
+		parts = []durationPart{
+			{months.Div(decimal.NewFromInt(12)).Floor(), "year", "y"},
+			{months.Mod(decimal.NewFromInt(12)), "month", "mo"},
+		}
+	default:
+		seconds := d.ToSeconds().Abs().Round(3)
+		parts = []durationPart{
+			{seconds.Div(decimal.NewFromInt(86400)).Floor(), "day", "d"},
+			{seconds.Mod(decimal.NewFromInt(86400)).Div(decimal.NewFromInt(3600)).Floor(), "hour", "h"},
+			{seconds.Mod(decimal.NewFromInt(3600)).Div(decimal.NewFromInt(60)).Floor(), "minute", "m"},
+			{seconds.Mod(decimal.NewFromInt(60)), "second", "s"},
+		}
+	}
+
+	var words []string
+	for _, p := range parts {
+		if !p.value.IsZero() {
+			words = append(words, p.text(style))
+		}
+	}
+	if len(words) == 0 {
+		words = []string{parts[len(parts)-1].text(style)}
+	}
+
+	text := strings.Join(words, " ")
+	if d.Value.IsNegative() {
+		return "-" + text
+	}
+	return text
+}
+
+func (p durationPart) text(style DurationStyle) string {
+	if style == DurationShort {
+		return p.value.String() + p.short
+	}
+	if p.value.Equal(decimal.NewFromInt(1)) {
+		return "1 " + p.name
+	}
+	return p.value.String() + " " + p.name + "s"
+}
@@ -0,0 +1,55 @@
+package display
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		value, unit string
+		long, short string
+	}{
+		{"90061", "seconds", "1 day 1 hour 1 minute 1 second", "1d 1h 1m 1s"},
+		{"36", "hours", "1 day 12 hours", "1d 12h"},
+		{"2.5", "minutes", "2 minutes 30 seconds", "2m 30s"},
+		{"1500", "milliseconds", "1.5 seconds", "1.5s"},
+		{"18", "months", "1 year 6 months", "1y 6mo"},
+		{"2", "years", "2 years", "2y"},
+		{"-3700", "seconds", "-1 hour 1 minute 40 seconds", "-1h 1m 40s"},
+		{"0", "days", "0 seconds", "0s"},
+		{"10", types.BusinessDayUnit, "10 business day", "10 business day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.unit, func(t *testing.T) {
+			d, err := types.NewDuration(decimal.RequireFromString(tt.value), tt.unit)
+			if err != nil {
+				t.Fatalf("NewDuration() error = %v", err)
+			}
+			if got := HumanizeDuration(d, DurationLong); got != tt.long {
+				t.Errorf("long = %q, want %q", got, tt.long)
+			}
+			if got := HumanizeDuration(d, DurationShort); got != tt.short {
+				t.Errorf("short = %q, want %q", got, tt.short)
+			}
+			if got := HumanizeDuration(d, DurationAsComputed); got != d.String() {
+				t.Errorf("as computed = %q, want %q", got, d.String())
+			}
+		})
+	}
+}
+
+func TestFormatWithDurations(t *testing.T) {
+	d, _ := types.NewDuration(decimal.NewFromInt(90061), "seconds")
+	list := types.NewList([]types.Type{d})
+
+	if got := FormatWith(list, Options{Durations: DurationShort}); got != "[1d 1h 1m 1s]" {
+		t.Errorf("FormatWith(list, short) = %q, want [1d 1h 1m 1s]", got)
+	}
+	if got := Format(d); got != "90061 seconds" {
+		t.Errorf("Format() = %q, want durations as computed", got)
+	}
+}
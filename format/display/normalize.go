@@ -146,16 +146,16 @@ func buildUnitFamilies() {
 		"gal":  {"gal", "gallon", "gallons"},
 	})
 
-	// Data Storage (binary, base: byte)
+	// Data Storage (SI, base: byte)
 	dataStorage := &UnitFamily{
 		BaseUnit: "bytes",
 		Units: []UnitScale{
 			{"bytes", d("1")},
-			{"KB", d("1024")},
-			{"MB", d("1048576")},          // 1024^2
-			{"GB", d("1073741824")},       // 1024^3
-			{"TB", d("1099511627776")},    // 1024^4
-			{"PB", d("1125899906842624")}, // 1024^5
+			{"KB", d("1000")},
+			{"MB", d("1000000")},          // 1000^2
+			{"GB", d("1000000000")},       // 1000^3
+			{"TB", d("1000000000000")},    // 1000^4
+			{"PB", d("1000000000000000")}, // 1000^5
 		},
 	}
 	registerFamily("data_storage", dataStorage, map[string][]string{
@@ -167,6 +167,27 @@ func buildUnitFamilies() {
 		"PB":    {"pb", "PB", "petabyte", "petabytes"},
 	})
 
+	// Data Storage (explicit IEC, base: byte). Bytes stay mapped to the
+	// family above; this one only normalizes values already in KiB..PiB.
+	dataStorageIEC := &UnitFamily{
+		BaseUnit: "bytes",
+		Units: []UnitScale{
+			{"bytes", d("1")},
+			{"KiB", d("1024")},
+			{"MiB", d("1048576")},
+			{"GiB", d("1073741824")},
+			{"TiB", d("1099511627776")},
+			{"PiB", d("1125899906842624")},
+		},
+	}
+	registerFamily("data_storage_iec", dataStorageIEC, map[string][]string{
+		"KiB": {"KiB", "kibibyte", "kibibytes"},
+		"MiB": {"MiB", "mebibyte", "mebibytes"},
+		"GiB": {"GiB", "gibibyte", "gibibytes"},
+		"TiB": {"TiB", "tebibyte", "tebibytes"},
+		"PiB": {"PiB", "pebibyte", "pebibytes"},
+	})
+
 	// Power (base: watt)
 	power := &UnitFamily{
 		BaseUnit: "W",
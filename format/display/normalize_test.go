@@ -127,26 +127,26 @@ func TestNormalizeForDisplay(t *testing.T) {
 
 		// ========== DATA/STORAGE (Binary) ==========
 		// Bytes scaling up
-		{"1024B to KB", "1024", "bytes", "1", "KB"},
-		{"1048576B to MB", "1048576", "bytes", "1", "MB"},
-		{"1073741824B to GB", "1073741824", "bytes", "1", "GB"},
+		{"1000B to KB", "1000", "bytes", "1", "KB"},
+		{"1000000B to MB", "1000000", "bytes", "1", "MB"},
+		{"1000000000B to GB", "1000000000", "bytes", "1", "GB"},
 
 		// Kilobytes scaling up
-		{"1024KB to MB", "1024", "KB", "1", "MB"},
-		{"1048576KB to GB", "1048576", "KB", "1", "GB"},
+		{"1000KB to MB", "1000", "KB", "1", "MB"},
+		{"1000000KB to GB", "1000000", "KB", "1", "GB"},
 
 		// Megabytes scaling up
-		{"1024MB to GB", "1024", "MB", "1", "GB"},
-		{"1048576MB to TB", "1048576", "MB", "1", "TB"},
+		{"1000MB to GB", "1000", "MB", "1", "GB"},
+		{"1000000MB to TB", "1000000", "MB", "1", "TB"},
 
 		// Gigabytes scaling up
-		{"1024GB to TB", "1024", "GB", "1", "TB"},
-		{"1048576GB to PB", "1048576", "GB", "1", "PB"},
+		{"1000GB to TB", "1000", "GB", "1", "TB"},
+		{"1000000GB to PB", "1000000", "GB", "1", "PB"},
 
 		// Large values with decimal
-		{"1536MB to GB", "1536", "MB", "1.5", "GB"},
-		// 23400000 GB = 23400000 / 1024 / 1024 PB ≈ 22.31 PB (rounding)
-		{"23400000GB to PB", "23400000", "GB", "22.31", "PB"},
+		{"1500MB to GB", "1500", "MB", "1.5", "GB"},
+		// 23400000 GB = 23400000 / 1000 / 1000 PB = 23.4 PB
+		{"23400000GB to PB", "23400000", "GB", "23.4", "PB"},
 
 		// Small data stays as-is
 		{"500MB stays MB", "500", "MB", "500", "MB"},
@@ -195,7 +195,7 @@ func TestNormalizeForDisplay(t *testing.T) {
 
 		// Negative values
 		{"negative meters", "-1000", "m", "-1", "km"},
-		{"negative GB", "-1024", "GB", "-1", "TB"},
+		{"negative GB", "-1000", "GB", "-1", "TB"},
 
 		// Very small values (stay in smallest reasonable unit)
 		{"tiny meters", "0.001", "m", "1", "mm"},
@@ -305,13 +305,13 @@ func TestFormatQuantity_WithNormalization(t *testing.T) {
 		unit     string
 		expected string
 	}{
-		// The original problem: 23.4M GB should become ~22.89 PB
-		{"23.4M GB normalized", "23400000", "GB", "22.89 PB"},
+		// The original problem: 23.4M GB should become 23.4 PB
+		{"23.4M GB normalized", "23400000", "GB", "23.4 PB"},
 
 		// Other examples
 		{"1000 meters", "1000", "m", "1 km"},
 		{"5280 feet", "5280", "feet", "1 mi"},
-		{"1024 MB", "1024", "MB", "1 GB"},
+		{"1000 MB", "1000", "MB", "1 GB"},
 		{"16 cups", "16", "cup", "1 gal"},
 
 		// Values that should stay as-is
@@ -0,0 +1,230 @@
+package display
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// Value is a value on its way through a Pipeline: the value to show and what
+// earlier stages decided about it.
+type Value struct {
+	types.Type
+	Locale     lexer.NumberLocale // Decimal mark for the text; the zero value means LocaleUS
+	Durations  DurationStyle      // How durations are written (see HumanizeDuration)
+	Normalized bool               // Rescaled to a readable unit (1000 m → 1 km), so no K/M/B/T suffix
+}
+
+// Options are a document's display settings, from its frontmatter.
+type Options struct {
+	Durations DurationStyle // How durations are written; DurationAsComputed by default
+}
+
+// Handler turns a value into display text.
+type Handler func(Value) string
+
+// Middleware wraps the rest of a pipeline. It can change the value before
+// calling next (a rounding policy), change the text next returns
+// (highlighting), or return text without calling next (redaction).
+type Middleware func(next Handler) Handler
+
+// Built-in stages, in the order values pass through them.
+const (
+	StageNormalize = "normalize" // Rescales quantities and rates to readable units
+	StageLocalize  = "localize"  // Writes the text, with K/M/B/T suffixes and the locale's decimal mark
+)
+
+// Pipeline formats values for display in stages: unit normalization, then
+// locale formatting, then any decoration. Embedders add their own stages as
+// middleware. Localization always runs last and can't be removed, since it
+// produces the text; middleware added with Use runs just before it, so it
+// sees normalized values and the finished text.
+//
+// A Pipeline is safe for concurrent use.
+type Pipeline struct {
+	Locale lexer.NumberLocale // Decimal mark for values formatted with Format
+
+	mu      sync.RWMutex
+	stages  []stage
+	handler Handler // Built from stages on first use
+}
+
+type stage struct {
+	name       string
+	middleware Middleware
+}
+
+// Default is the pipeline Format uses. Change it at start-up, before
+// formatting, as with format.RegisterFormatter.
+var Default = NewPipeline()
+
+// NewPipeline returns a pipeline with the built-in stages.
+func NewPipeline() *Pipeline {
+	return &Pipeline{stages: []stage{{StageNormalize, normalizeStage}}}
+}
+
+// Format returns the display text for t.
+func (p *Pipeline) Format(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+	return p.Handle(Value{Type: t, Locale: p.Locale})
+}
+
+// FormatWith returns the display text for t with a document's settings.
+func (p *Pipeline) FormatWith(t types.Type, opts Options) string {
+	if t == nil {
+		return ""
+	}
+	return p.Handle(Value{Type: t, Locale: p.Locale, Durations: opts.Durations})
+}
+
+// Handle passes v through every stage and returns the text.
+func (p *Pipeline) Handle(v Value) string {
+	p.mu.RLock()
+	h := p.handler
+	p.mu.RUnlock()
+	if h == nil {
+		p.mu.Lock()
+		if p.handler == nil {
+			p.handler = p.build()
+		}
+		h = p.handler
+		p.mu.Unlock()
+	}
+	return h(v)
+}
+
+// Use adds a stage just before localization.
+func (p *Pipeline) Use(name string, m Middleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stages = append(p.stages, stage{name, m})
+	p.handler = nil
+}
+
+// InsertBefore adds a stage just before the named one, such as a rounding
+// policy before StageNormalize. Naming StageLocalize is the same as Use.
+func (p *Pipeline) InsertBefore(before, name string, m Middleware) error {
+	return p.insert(before, 0, name, m)
+}
+
+// InsertAfter adds a stage just after the named one.
+func (p *Pipeline) InsertAfter(after, name string, m Middleware) error {
+	return p.insert(after, 1, name, m)
+}
+
+func (p *Pipeline) insert(at string, offset int, name string, m Middleware) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := len(p.stages)
+	if at != StageLocalize || offset != 0 {
+		i = p.index(at)
+		if i < 0 {
+			return fmt.Errorf("no display stage named %q", at)
+		}
+		i += offset
+	}
+	p.stages = slices.Insert(p.stages, i, stage{name, m})
+	p.handler = nil
+	return nil
+}
+
+// Remove removes the named stage, reporting whether there was one. Removing
+// StageNormalize shows quantities in the units they were computed in.
+func (p *Pipeline) Remove(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := p.index(name)
+	if i < 0 {
+		return false
+	}
+	p.stages = slices.Delete(p.stages, i, i+1)
+	p.handler = nil
+	return true
+}
+
+// Stages returns the names of the pipeline's stages in order, ending with
+// StageLocalize.
+func (p *Pipeline) Stages() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	names := make([]string, 0, len(p.stages)+1)
+	for _, s := range p.stages {
+		names = append(names, s.name)
+	}
+	return append(names, StageLocalize)
+}
+
+func (p *Pipeline) index(name string) int {
+	return slices.IndexFunc(p.stages, func(s stage) bool { return s.name == name })
+}
+
+// build chains the stages into one handler. Lists pass each element, and
+// distributions each summary value, through the whole pipeline before the
+// value itself, so a rounding policy or redaction applies to them too.
+func (p *Pipeline) build() Handler {
+	var h Handler
+	h = func(v Value) string {
+		return LocalizeDecimals(text(v, func(elem types.Type) string {
+			return p.Handle(Value{Type: elem, Locale: v.Locale, Durations: v.Durations})
+		}), v.Locale)
+	}
+	for i := len(p.stages) - 1; i >= 0; i-- {
+		h = p.stages[i].middleware(h)
+	}
+	return h
+}
+
+// normalizeStage rescales quantities and rates in known units to the most
+// readable unit.
+func normalizeStage(next Handler) Handler {
+	return func(v Value) string {
+		return next(normalize(v))
+	}
+}
+
+// decimalPoint matches a decimal point between digits.
+var decimalPoint = regexp.MustCompile(`(\d)\.(\d)`)
+
+// LocalizeDecimals writes the decimal points of numbers in s with loc's
+// decimal mark, as the localize stage does. Display text has no thousands
+// separators to convert, and neither do number literals in a document.
+func LocalizeDecimals(s string, loc lexer.NumberLocale) string {
+	if loc.Decimal == '.' || loc.Decimal == 0 {
+		return s
+	}
+	return decimalPoint.ReplaceAllString(s, "${1}"+string(loc.Decimal)+"${2}")
+}
+
+// Round returns middleware rounding numbers, currencies, quantities, and
+// rates to places decimal places before they are formatted.
+func Round(places int32) Middleware {
+	return func(next Handler) Handler {
+		return func(v Value) string {
+			switch t := v.Type.(type) {
+			case *types.Number:
+				if t.IsPercentage() {
+					v.Type = types.NewPercentage(t.Value.Round(places + 2)) // Round the digits shown
+				} else {
+					v.Type = types.NewNumber(t.Value.Round(places))
+				}
+			case *types.Currency:
+				rounded := *t
+				rounded.Value = t.Value.Round(places)
+				v.Type = &rounded
+			case *types.Quantity:
+				v.Type = types.NewQuantity(t.Value.Round(places), t.Unit)
+			case *types.Rate:
+				if t.Amount != nil {
+					v.Type = types.NewRate(types.NewQuantity(t.Amount.Value.Round(places), t.Amount.Unit), t.PerUnit)
+				}
+			}
+			return next(v)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package display
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+func TestPipelineDefault(t *testing.T) {
+	values := []types.Type{
+		types.NewNumber(d("1500000")),
+		types.NewQuantity(d("1500000"), "bytes"),
+		types.NewQuantity(d("100000"), "users"),
+		types.NewRate(types.NewQuantity(d("1000000"), "bytes"), "second"),
+		types.NewCurrency(d("42.5"), "$"),
+		types.NewList([]types.Type{types.NewNumber(d("1000")), types.NewQuantity(d("1000"), "m")}),
+	}
+	want := []string{"1.5M", "1.5 MB", "100K users", "1 MB/s", "$42.50", "[1K, 1 km]"}
+
+	p := NewPipeline()
+	for i, v := range values {
+		if got := p.Format(v); got != want[i] {
+			t.Errorf("Format(%s) = %q, want %q", v, got, want[i])
+		}
+		if got := Format(v); got != want[i] {
+			t.Errorf("display.Format(%s) = %q, want %q", v, got, want[i])
+		}
+	}
+	if got := p.Stages(); strings.Join(got, ",") != "normalize,localize" {
+		t.Errorf("Stages() = %v", got)
+	}
+}
+
+func TestPipelineMiddleware(t *testing.T) {
+	redact := func(next Handler) Handler {
+		return func(v Value) string {
+			if _, ok := v.Type.(*types.Currency); ok {
+				return "•••"
+			}
+			return next(v)
+		}
+	}
+	highlight := func(next Handler) Handler {
+		return func(v Value) string {
+			text := next(v)
+			if n, ok := v.Type.(*types.Number); ok && n.Value.IsNegative() {
+				return "(" + text + ")"
+			}
+			return text
+		}
+	}
+
+	p := NewPipeline()
+	p.Use("redact", redact)
+	p.Use("highlight", highlight)
+
+	tests := []struct {
+		value types.Type
+		want  string
+	}{
+		{types.NewCurrency(d("1234.5"), "EUR"), "•••"},
+		{types.NewNumber(d("-2500")), "(-2.5K)"},
+		{types.NewNumber(d("2500")), "2.5K"},
+		{types.NewList([]types.Type{types.NewCurrency(d("1"), "$"), types.NewNumber(d("-1"))}), "[•••, (-1)]"},
+	}
+	for _, tt := range tests {
+		if got := p.Format(tt.value); got != tt.want {
+			t.Errorf("Format(%s) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPipelineRoundingPosition(t *testing.T) {
+	q := types.NewQuantity(d("1234"), "m")
+
+	// Rounding after normalization rounds the displayed value: 1.23 km → 1 km
+	after := NewPipeline()
+	after.Use("round", Round(0))
+	if got := after.Format(q); got != "1 km" {
+		t.Errorf("rounding after normalize = %q, want %q", got, "1 km")
+	}
+
+	// Before normalization it rounds the computed value: 1234 m → 1200 m
+	before := NewPipeline()
+	if err := before.InsertBefore(StageNormalize, "round", Round(-2)); err != nil {
+		t.Fatal(err)
+	}
+	if got := before.Format(q); got != "1.2 km" {
+		t.Errorf("rounding before normalize = %q, want %q", got, "1.2 km")
+	}
+	if got := before.Stages(); strings.Join(got, ",") != "round,normalize,localize" {
+		t.Errorf("Stages() = %v", got)
+	}
+
+	// A percentage rounds the digits shown: 33.33% → 33%
+	if got := after.Format(types.NewPercentage(d("0.33333"))); got != "33%" {
+		t.Errorf("rounding a percentage = %q, want %q", got, "33%")
+	}
+
+	if err := before.InsertAfter("missing", "x", Round(0)); err == nil {
+		t.Error("expected error inserting after an unknown stage")
+	}
+	if !before.Remove(StageNormalize) || before.Remove(StageNormalize) {
+		t.Error("Remove should remove the stage once")
+	}
+	if got := before.Format(q); got != "1.2K m" {
+		t.Errorf("without normalize = %q, want %q", got, "1.2K m")
+	}
+}
+
+func TestPipelineLocale(t *testing.T) {
+	p := NewPipeline()
+	p.Locale = lexer.LocaleDE
+	tests := []struct {
+		value types.Type
+		want  string
+	}{
+		{types.NewNumber(d("1500000")), "1,5M"},
+		{types.NewCurrency(d("42.5"), "EUR"), "EUR42,50"},
+		{types.NewQuantity(d("1500000"), "bytes"), "1,5 MB"},
+	}
+	for _, tt := range tests {
+		if got := p.Format(tt.value); got != tt.want {
+			t.Errorf("Format(%s) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPipelineConcurrentUse(t *testing.T) {
+	p := NewPipeline()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i == 0 {
+				p.Use("round", Round(0))
+			}
+			p.Format(types.NewNumber(d("1.5")))
+		}()
+	}
+	wg.Wait()
+}
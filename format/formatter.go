@@ -2,7 +2,9 @@ package format
 
 import (
 	"io"
+	"time"
 
+	"github.com/CalcMark/go-calcmark/format/display"
 	"github.com/CalcMark/go-calcmark/spec/document"
 )
 
@@ -21,4 +23,37 @@ type Options struct {
 	Verbose       bool   // Show calculation steps, types, units
 	IncludeErrors bool   // Include error details
 	Template      string // For template-based formatters (future use)
+
+	// Results sets where the HTML formatter places calculation results:
+	// ResultsInline (default) or ResultsTable.
+	Results string
+
+	// Header is the PDF page header: up to three "|"-separated sections
+	// with placeholders such as {title}; see pdf.DefaultHeader.
+	Header string
+
+	// Diagnostics are the evaluator's warnings and hints, which the JSON
+	// formatter reports with the blocks they belong to (by BlockID).
+	Diagnostics []document.Diagnostic
+
+	// Provenance appends a "# = ..." comment after each calculation showing
+	// the variable values it used (CalcMark and Markdown formatters).
+	Provenance     bool
+	ProvenanceTime time.Time // Date stamp for provenance comments; zero means now
 }
+
+// DisplayOptions returns the display settings doc's frontmatter declares,
+// such as how durations are written.
+func DisplayOptions(doc *document.Document) display.Options {
+	var opts display.Options
+	if fm := doc.GetFrontmatter(); fm != nil {
+		opts.Durations = display.DurationStyle(fm.Durations)
+	}
+	return opts
+}
+
+// HTML result layouts for Options.Results.
+const (
+	ResultsInline = "inline" // Each result beside its source line
+	ResultsTable  = "table"  // Each calculation block as a source/result table
+)
@@ -2,11 +2,13 @@ package format
 
 import (
 	_ "embed"
+	"fmt"
 	"html/template"
 	"io"
 	"strings"
 
 	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/document"
 )
 
@@ -15,6 +17,15 @@ var defaultHTMLTemplate string
 
 // HTMLFormatter formats CalcMark documents as HTML.
 // Uses an embedded template with modern styling.
+//
+// Results appear beside each calculation line, or with Options.Results set
+// to ResultsTable, as a two-column table per calculation block. Elements
+// carry classes for stylesheets to theme: calc-block, calc-line or
+// calc-row, calc-source, calc-inline-result or calc-value, calc-error,
+// calc-data-table for table results such as amortize() schedules,
+// text-block, a calc-type-<kind> class naming the result's type (number,
+// currency, quantity, ...), and a data-var attribute naming the variable
+// a line assigns.
 type HTMLFormatter struct{}
 
 // Extensions returns the file extensions handled by this formatter.
@@ -28,12 +39,18 @@ type TemplateBlock struct {
 	SourceLines []TemplateLine // For calc blocks with per-line results
 	Error       string
 	HTML        template.HTML // For text blocks
+	Tables      []ResultTable // Tables the block's lines evaluated to, shown in full
+	Label       string        // Annotation label, rendered as data-label
+	Tags        string        // Space-separated annotation tags, rendered as data-tags
 }
 
 // TemplateLine represents a single source line with its result
 type TemplateLine struct {
-	Source string
-	Result string // Formatted result for this line
+	Source    string
+	Result    string // Formatted result for this line
+	Highlight string // Color from the frontmatter highlight rules, "" if none
+	Variable  string // Variable the line assigns, "" if none
+	Kind      string // Result type, e.g. "currency", for calc-type-<kind> classes
 }
 
 // TemplateFrontmatter represents frontmatter for template rendering
@@ -63,6 +80,14 @@ func (f *HTMLFormatter) Format(w io.Writer, doc *document.Document, opts Options
 		templateContent = opts.Template
 	}
 
+	layout := opts.Results
+	if layout == "" {
+		layout = ResultsInline
+	}
+	if layout != ResultsInline && layout != ResultsTable {
+		return fmt.Errorf("unknown results layout: %s (valid: %s, %s)", layout, ResultsInline, ResultsTable)
+	}
+
 	tmpl, err := template.New("html").Parse(templateContent)
 	if err != nil {
 		return err
@@ -71,7 +96,8 @@ func (f *HTMLFormatter) Format(w io.Writer, doc *document.Document, opts Options
 	data := struct {
 		Frontmatter *TemplateFrontmatter
 		Blocks      []TemplateBlock
-	}{}
+		Results     string // Result layout: "inline" or "table"
+	}{Results: layout}
 
 	// Build frontmatter data if present
 	if fm := doc.GetFrontmatter(); fm != nil {
@@ -104,9 +130,14 @@ func (f *HTMLFormatter) Format(w io.Writer, doc *document.Document, opts Options
 	}
 
 	blocks := doc.GetBlocks()
+	displayOpts := DisplayOptions(doc)
 
 	for _, node := range blocks {
 		tb := TemplateBlock{}
+		if meta := node.Block.Metadata(); meta != nil {
+			tb.Label = meta.Label
+			tb.Tags = strings.Join(meta.Tags, " ")
+		}
 
 		switch block := node.Block.(type) {
 		case *document.CalcBlock:
@@ -115,6 +146,8 @@ func (f *HTMLFormatter) Format(w io.Writer, doc *document.Document, opts Options
 			// Build source lines with inline results, skipping empty lines
 			sourceLines := block.Source()
 			results := block.Results()
+			statements := block.Statements()
+			highlights := doc.Highlights(block)
 
 			for i, line := range sourceLines {
 				if line == "" {
@@ -123,11 +156,21 @@ func (f *HTMLFormatter) Format(w io.Writer, doc *document.Document, opts Options
 				tl := TemplateLine{Source: line}
 				// Add result if available for this line
 				if i < len(results) && results[i] != nil {
-					tl.Result = display.Format(results[i])
+					tl.Result = display.FormatWith(results[i], displayOpts)
+					tl.Kind = valueKind(results[i])
+				}
+				if i < len(statements) {
+					if assign, ok := statements[i].(*ast.Assignment); ok {
+						tl.Variable = assign.Name
+					}
+				}
+				if i < len(highlights) {
+					tl.Highlight = highlights[i]
 				}
 				tb.SourceLines = append(tb.SourceLines, tl)
 			}
 
+			tb.Tables = resultTables(block, displayOpts)
 			if block.Error() != nil {
 				tb.Error = block.Error().Error()
 			}
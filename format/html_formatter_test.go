@@ -301,3 +301,114 @@ price = base_price * (1 + tax_rate)
 		t.Errorf("Expected HTML to use definition descriptions")
 	}
 }
+
+// TestHTMLFormatterBlockMetadata tests that annotations become data attributes
+func TestHTMLFormatterBlockMetadata(t *testing.T) {
+	doc, err := document.NewDocument("<!-- calc: label=\"Budget Summary\" tags=finance,q3 -->\nrent = $1000\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&HTMLFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	want := `<div class="calc-block" data-label="Budget Summary" data-tags="finance q3">`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %s in output, got: %s", want, buf.String())
+	}
+}
+
+func TestHTMLFormatterHighlight(t *testing.T) {
+	doc, err := document.NewDocument("---\nhighlight:\n  burn: {\"> 50000\": red}\n---\nburn = $60000\nspend = $70000\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&HTMLFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	output := buf.String()
+	if strings.Count(output, `class="calc-inline-result highlight-red"`) != 1 {
+		t.Errorf("Expected only burn highlighted red, got: %s", output)
+	}
+}
+
+// TestHTMLFormatterClassHooks tests the type classes and variable attributes
+func TestHTMLFormatterClassHooks(t *testing.T) {
+	doc, err := document.NewDocument("rent = $1200\nsize = 10 meters\nrent * 2\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&HTMLFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`<body class="calcmark">`,
+		`<div class="calc-line calc-type-currency" data-var="rent">`,
+		`<div class="calc-line calc-type-quantity" data-var="size">`,
+		`<div class="calc-line calc-type-currency">`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "<table") {
+		t.Error("Inline layout should not render a table")
+	}
+}
+
+// TestHTMLFormatterTableResults tests the table result layout
+func TestHTMLFormatterTableResults(t *testing.T) {
+	doc, err := document.NewDocument("---\nhighlight:\n  burn: {\"> 50000\": red}\n---\nburn = $60000\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&HTMLFormatter{}).Format(&buf, doc, Options{Results: ResultsTable}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`<table class="calc-table">`,
+		`<tr class="calc-row calc-type-currency" data-var="burn">`,
+		`<td class="calc-source"><code>burn = $60000</code></td>`,
+		`<td class="calc-value highlight-red">$60K</td>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, `<div class="calc-line`) {
+		t.Error("Table layout should not render inline lines")
+	}
+}
+
+func TestHTMLFormatterUnknownResultsLayout(t *testing.T) {
+	doc, err := document.NewDocument("x = 1\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (&HTMLFormatter{}).Format(&buf, doc, Options{Results: "grid"}); err == nil {
+		t.Error("Expected error for unknown results layout")
+	}
+}
@@ -4,8 +4,15 @@ import (
 	"encoding/json"
 	"io"
 	"maps"
+	"regexp"
+	"strings"
 
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
 )
 
 // JSONFormatter formats CalcMark documents as JSON.
@@ -17,31 +24,140 @@ func (f *JSONFormatter) Extensions() []string {
 	return []string{".json"}
 }
 
+// JSONSchemaVersion is the version of the JSON output's structure, written
+// as schema_version. It goes up when a field is removed or changes meaning;
+// adding fields doesn't change it.
+const JSONSchemaVersion = 1
+
+// JSON output is canonical, so exports of the same document are
+// byte-identical and diff cleanly: blocks and results are in document
+// order, variables in order of first assignment, dependencies sorted by
+// name, and maps sorted by key. Decimals are rounded to 15 significant
+// digits and at most 12 decimal places, with no trailing zeros, which
+// drops the artifacts of unit conversions done in floating point.
+const (
+	jsonSignificantDigits = 15
+	jsonDecimalPlaces     = 12
+)
+
 // JSONDocument represents the full document in JSON output
 type JSONDocument struct {
-	Frontmatter *JSONFrontmatter `json:"frontmatter,omitempty"`
-	Blocks      []JSONBlock      `json:"blocks"`
+	SchemaVersion int              `json:"schema_version"`
+	Frontmatter   *JSONFrontmatter `json:"frontmatter,omitempty"`
+	Blocks        []JSONBlock      `json:"blocks"`
+	Diagnostics   []JSONDiagnostic `json:"diagnostics,omitempty"` // Document-wide, such as size limits
 }
 
 // JSONFrontmatter represents frontmatter in JSON output
 type JSONFrontmatter struct {
-	Globals  map[string]string `json:"globals,omitempty"`
-	Exchange map[string]string `json:"exchange,omitempty"`
+	Globals  map[string]string     `json:"globals,omitempty"`
+	Exchange map[string]string     `json:"exchange,omitempty"`
+	Exports  []string              `json:"exports,omitempty"`
+	Widgets  map[string]JSONWidget `json:"widgets,omitempty"`
+}
+
+// JSONWidget represents an interactive control bound to a global in JSON output.
+// Numbers are strings to preserve decimal precision, like exchange rates.
+type JSONWidget struct {
+	Type    string   `json:"type"`
+	Min     string   `json:"min,omitempty"`
+	Max     string   `json:"max,omitempty"`
+	Step    string   `json:"step,omitempty"`
+	Options []string `json:"options,omitempty"`
 }
 
 // JSONBlock represents a single block in JSON output
 type JSONBlock struct {
-	Type      string   `json:"type"`
-	Source    []string `json:"source"`
-	Output    string   `json:"output,omitempty"`
-	Error     string   `json:"error,omitempty"`
-	Variables []string `json:"variables,omitempty"`
+	Type        string             `json:"type"`
+	Source      []string           `json:"source"`
+	Output      string             `json:"output,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	Variables   []string           `json:"variables,omitempty"`
+	Metadata    *JSONBlockMetadata `json:"metadata,omitempty"`
+	Highlight   map[string]string  `json:"highlight,omitempty"` // Variable -> color from highlight rules
+	Results     []JSONResult       `json:"results,omitempty"`
+	DependsOn   []JSONDependency   `json:"depends_on,omitempty"`
+	Diagnostics []JSONDiagnostic   `json:"diagnostics,omitempty"` // Those not on a result's line
+}
+
+// JSONResult represents one calculation line and its value in JSON output
+type JSONResult struct {
+	Line         int               `json:"line"` // 1-indexed line within the block
+	Source       string            `json:"source"`
+	Variable     string            `json:"variable,omitempty"`  // Variable the line assigns
+	Output       string            `json:"output,omitempty"`    // Display form, e.g. "$1.5K"
+	Value        string            `json:"value,omitempty"`     // Exact value in the document's number locale
+	RawValue     string            `json:"raw_value,omitempty"` // Unformatted number, or ISO date or time
+	Type         string            `json:"type,omitempty"`      // e.g. "currency", as in VariableRow
+	Unit         string            `json:"unit,omitempty"`      // Unit, or "unit/period" for rates
+	Currency     *JSONCurrency     `json:"currency,omitempty"`
+	Distribution *JSONDistribution `json:"distribution,omitempty"`
+	Table        *JSONTable        `json:"table,omitempty"`
+	Diagnostics  []JSONDiagnostic  `json:"diagnostics,omitempty"`
+}
+
+// JSONCurrency describes a currency result in JSON output
+type JSONCurrency struct {
+	Code   string `json:"code"`   // ISO 4217 code, e.g. "USD"
+	Symbol string `json:"symbol"` // As written, e.g. "$"
+
+	RealYear int `json:"real_year,omitempty"` // Year whose prices a real amount is in; omitted for nominal
+}
+
+// JSONDistribution summarizes a distribution result, whose raw_value is its
+// mean, in JSON output. Values are unformatted numbers in the result's unit.
+type JSONDistribution struct {
+	Samples int    `json:"samples"`
+	Mean    string `json:"mean"`
+	P5      string `json:"p5"`
+	P50     string `json:"p50"`
+	P95     string `json:"p95"`
+}
+
+// JSONTable holds a table result, such as an amortize() schedule, in JSON
+// output. Cells are unformatted numbers, as raw_value is.
+type JSONTable struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// JSONDiagnostic represents an error, warning, or hint in JSON output
+type JSONDiagnostic struct {
+	Severity    string   `json:"severity"` // "error", "warning", or "hint"
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Line        int      `json:"line,omitempty"` // 1-indexed line within the block
+	Column      int      `json:"column,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// JSONDependency is an edge from a block to an earlier block defining a
+// variable it uses. Block is the defining block's index in blocks.
+type JSONDependency struct {
+	Block    int    `json:"block"`
+	Variable string `json:"variable"`
+}
+
+// JSONBlockMetadata represents a block's annotation in JSON output
+type JSONBlockMetadata struct {
+	Label string            `json:"label,omitempty"`
+	Tags  []string          `json:"tags,omitempty"`
+	Attrs map[string]string `json:"attrs,omitempty"`
 }
 
 // Format writes the document as JSON to the writer.
 func (f *JSONFormatter) Format(w io.Writer, doc *document.Document, opts Options) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f.Document(doc, opts))
+}
+
+// Document returns the JSON structure Format writes, for callers that
+// embed it in their own JSON.
+func (f *JSONFormatter) Document(doc *document.Document, opts Options) JSONDocument {
 	result := JSONDocument{
-		Blocks: make([]JSONBlock, 0),
+		SchemaVersion: JSONSchemaVersion,
+		Blocks:        make([]JSONBlock, 0),
 	}
 
 	// Add frontmatter if present
@@ -60,36 +176,246 @@ func (f *JSONFormatter) Format(w io.Writer, doc *document.Document, opts Options
 			}
 		}
 
-		if len(fm.Globals) > 0 || len(fm.Exchange) > 0 {
+		jfm.Exports = fm.Exports
+
+		for name, w := range fm.Widgets {
+			if jfm.Widgets == nil {
+				jfm.Widgets = make(map[string]JSONWidget)
+			}
+			jw := JSONWidget{Type: w.Type, Options: w.Options}
+			if w.Min != nil {
+				jw.Min = w.Min.String()
+			}
+			if w.Max != nil {
+				jw.Max = w.Max.String()
+			}
+			if w.Type == document.WidgetSlider || w.Type == document.WidgetNumber {
+				jw.Step = w.Step.String()
+			}
+			jfm.Widgets[name] = jw
+		}
+
+		if len(fm.Globals) > 0 || len(fm.Exchange) > 0 || len(fm.Exports) > 0 || len(jfm.Widgets) > 0 {
 			result.Frontmatter = jfm
 		}
 	}
 
-	// Add blocks
+	// Diagnostics by block ID; "" holds document-wide ones
+	diagnostics := make(map[string][]document.Diagnostic)
+	for _, d := range opts.Diagnostics {
+		diagnostics[d.BlockID] = append(diagnostics[d.BlockID], d)
+	}
+	for _, d := range diagnostics[""] {
+		result.Diagnostics = append(result.Diagnostics, jsonDiagnostic(d))
+	}
+
+	blocks := NewJSONBlocks(doc)
 	for _, node := range doc.GetBlocks() {
-		jb := JSONBlock{
-			Source: node.Block.Source(),
+		result.Blocks = append(result.Blocks, blocks.Next(node, diagnostics[node.ID]))
+	}
+	return result
+}
+
+// JSONBlocks formats a document's blocks one at a time, in order, as
+// Document does, for callers sending each block's results as soon as it
+// is evaluated.
+type JSONBlocks struct {
+	doc         *document.Document
+	loc         lexer.NumberLocale
+	displayOpts display.Options
+	definedIn   map[string]int // Variable -> index of the last block defining it
+	index       int            // Index of the next block
+}
+
+// NewJSONBlocks returns a formatter for doc's blocks, starting at the first.
+func NewJSONBlocks(doc *document.Document) *JSONBlocks {
+	return &JSONBlocks{
+		doc:         doc,
+		loc:         doc.NumberLocale(),
+		displayOpts: DisplayOptions(doc),
+		definedIn:   make(map[string]int),
+	}
+}
+
+// Next formats node, the block after the last one formatted, with the
+// evaluator's diagnostics for it.
+func (b *JSONBlocks) Next(node *document.BlockNode, diagnostics []document.Diagnostic) JSONBlock {
+	i := b.index
+	b.index++
+
+	jb := JSONBlock{
+		Source: node.Block.Source(),
+	}
+	if meta := node.Block.Metadata(); meta != nil {
+		jb.Metadata = &JSONBlockMetadata{Label: meta.Label, Tags: meta.Tags, Attrs: meta.Attrs}
+	}
+
+	switch block := node.Block.(type) {
+	case *document.CalcBlock:
+		jb.Type = "calculation"
+		jb.Variables = block.Variables()
+		jb.Highlight = b.doc.HighlightedVariables(block)
+
+		if block.Error() != nil {
+			jb.Error = block.Error().Error()
+		} else if block.LastValue() != nil {
+			jb.Output = canonicalDecimals(block.LastValue().String())
 		}
 
-		switch block := node.Block.(type) {
-		case *document.CalcBlock:
-			jb.Type = "calculation"
-			jb.Variables = block.Variables()
+		jb.Results = jsonResults(block, b.loc, b.displayOpts)
+		blockDiags := append(block.Diagnostics(), diagnostics...)
+		jb.Diagnostics = attachDiagnostics(jb.Results, blockDiags)
 
-			if block.Error() != nil {
-				jb.Error = block.Error().Error()
-			} else if block.LastValue() != nil {
-				jb.Output = block.LastValue().String()
+		for _, name := range block.Dependencies() {
+			if from, ok := b.definedIn[name]; ok {
+				jb.DependsOn = append(jb.DependsOn, JSONDependency{Block: from, Variable: name})
 			}
+		}
+		for _, name := range block.Variables() {
+			b.definedIn[name] = i
+		}
 
-		case *document.TextBlock:
-			jb.Type = "text"
+	case *document.TextBlock:
+		jb.Type = "text"
+		for _, d := range diagnostics {
+			jb.Diagnostics = append(jb.Diagnostics, jsonDiagnostic(d))
 		}
+	}
+	return jb
+}
+
+// jsonResults returns an entry for each of block's statements, with its
+// value if it has one.
+func jsonResults(block *document.CalcBlock, loc lexer.NumberLocale, displayOpts display.Options) []JSONResult {
+	statements := block.Statements()
+	results := block.Results()
+	source := block.Source()
+	lines, oneLineEach := statementLineNumbers(block)
 
-		result.Blocks = append(result.Blocks, jb)
+	entries := make([]JSONResult, 0, len(statements))
+	for i, stmt := range statements {
+		var entry JSONResult
+		if oneLineEach {
+			entry.Line = lines[i]
+		} else if r := stmt.GetRange(); r != nil {
+			entry.Line = r.Start.Line
+		}
+		if entry.Line > 0 && entry.Line <= len(source) {
+			entry.Source = strings.TrimSpace(source[entry.Line-1])
+		}
+		if assign, ok := stmt.(*ast.Assignment); ok {
+			entry.Variable = assign.Name
+		}
+		if i < len(results) && results[i] != nil {
+			setResultValue(&entry, results[i], loc, displayOpts)
+		}
+		entries = append(entries, entry)
 	}
+	return entries
+}
 
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(result)
+// statementLineNumbers returns the 1-indexed line of each non-empty line
+// of block, and whether they match its statements one-to-one (see
+// statementLines).
+func statementLineNumbers(block *document.CalcBlock) ([]int, bool) {
+	var numbers []int
+	for i, line := range block.Source() {
+		if strings.TrimSpace(line) != "" && !isResultLine(line) {
+			numbers = append(numbers, i+1)
+		}
+	}
+	return numbers, len(numbers) == len(block.Statements())
+}
+
+func setResultValue(entry *JSONResult, value types.Type, loc lexer.NumberLocale, displayOpts display.Options) {
+	row := variableRow(entry.Variable, "", value, displayOpts)
+	entry.Output = row.Value
+	entry.Value = display.LocalizeDecimals(canonicalDecimals(value.String()), loc)
+	entry.RawValue = canonicalDecimals(row.Raw)
+	entry.Type = row.Type
+	entry.Unit = row.Unit
+	v := value.ToValue()
+	if d := v.Distribution; d != nil {
+		entry.Distribution = &JSONDistribution{
+			Samples: d.Samples,
+			Mean:    canonicalDecimals(d.Mean),
+			P5:      canonicalDecimals(d.P5),
+			P50:     canonicalDecimals(d.P50),
+			P95:     canonicalDecimals(d.P95),
+		}
+	}
+	if t := v.Table; t != nil {
+		entry.Table = &JSONTable{Columns: t.Columns, Rows: make([][]string, len(t.Rows))}
+		for i, row := range t.Rows {
+			entry.Table.Rows[i] = make([]string, len(row))
+			for j, cell := range row {
+				raw := cell.Decimal
+				if raw == "" {
+					raw = cell.Display
+				}
+				entry.Table.Rows[i][j] = canonicalDecimals(raw)
+			}
+		}
+	}
+	if v.CurrencyCode != "" {
+		entry.Unit = ""
+		entry.Currency = &JSONCurrency{Code: v.CurrencyCode, Symbol: v.CurrencySymbol, RealYear: v.RealYear}
+	}
+}
+
+// decimalNumber matches a number with a fractional part.
+var decimalNumber = regexp.MustCompile(`\d+\.\d+`)
+
+// canonicalDecimals rounds the decimal numbers in s with canonicalDecimal.
+func canonicalDecimals(s string) string {
+	return decimalNumber.ReplaceAllStringFunc(s, canonicalDecimal)
+}
+
+// canonicalDecimal rounds a decimal number to jsonSignificantDigits and at
+// most jsonDecimalPlaces, dropping the trailing zeros rounding leaves.
+// Integer digits are never rounded away, and numbers that are short enough
+// are unchanged, so currencies keep their two decimal places.
+func canonicalDecimal(n string) string {
+	point := strings.IndexByte(n, '.')
+	intDigits := len(strings.TrimLeft(n[:point], "0"))
+	places := min(jsonDecimalPlaces, max(0, jsonSignificantDigits-max(intDigits, 1)))
+	if len(n)-point-1 <= places {
+		return n
+	}
+	d, err := decimal.NewFromString(n)
+	if err != nil {
+		return n
+	}
+	return d.Round(int32(places)).String()
+}
+
+// attachDiagnostics adds each diagnostic to the result on its line and
+// returns those on no result's line.
+func attachDiagnostics(results []JSONResult, diagnostics []document.Diagnostic) []JSONDiagnostic {
+	var rest []JSONDiagnostic
+	for _, d := range diagnostics {
+		attached := false
+		for i := range results {
+			if d.Line > 0 && results[i].Line == d.Line {
+				results[i].Diagnostics = append(results[i].Diagnostics, jsonDiagnostic(d))
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			rest = append(rest, jsonDiagnostic(d))
+		}
+	}
+	return rest
+}
+
+func jsonDiagnostic(d document.Diagnostic) JSONDiagnostic {
+	return JSONDiagnostic{
+		Severity:    d.Severity,
+		Code:        d.Code,
+		Message:     d.Message,
+		Line:        d.Line,
+		Column:      d.Column,
+		Suggestions: d.Suggestions,
+	}
 }
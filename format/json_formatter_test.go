@@ -3,6 +3,7 @@ package format
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
@@ -166,6 +167,221 @@ x = 10
 	}
 }
 
+// TestJSONFormatterWidgets tests that widget declarations are exported
+func TestJSONFormatterWidgets(t *testing.T) {
+	source := `---
+globals:
+  rate: 0.05
+widgets:
+  rate: {type: slider, min: 0, max: 0.2, step: 0.005}
+---
+cost = 1000 * rate
+`
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&JSONFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var result JSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	want := JSONWidget{Type: "slider", Min: "0", Max: "0.2", Step: "0.005"}
+	if result.Frontmatter == nil || !reflect.DeepEqual(result.Frontmatter.Widgets["rate"], want) {
+		t.Errorf("Expected rate widget %+v, got: %s", want, buf.String())
+	}
+}
+
+// TestJSONFormatterBlockMetadata tests that block annotations are exported
+func TestJSONFormatterBlockMetadata(t *testing.T) {
+	doc, err := document.NewDocument("<!-- calc: label=\"Budget Summary\" tags=finance -->\nrent = $1000\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&JSONFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var result JSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+
+	var found bool
+	for _, block := range result.Blocks {
+		if block.Type == "calculation" {
+			found = true
+			if block.Metadata == nil || block.Metadata.Label != "Budget Summary" || !slices.Equal(block.Metadata.Tags, []string{"finance"}) {
+				t.Errorf("calculation metadata = %+v", block.Metadata)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected a calculation block")
+	}
+}
+
+func TestJSONFormatterHighlight(t *testing.T) {
+	doc, err := document.NewDocument("---\nhighlight:\n  burn: {\"> 50000\": red, \">= 0\": green}\n---\nburn = $60000\nspend = $70000\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&JSONFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var result JSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+
+	want := map[string]string{"burn": "red"}
+	if len(result.Blocks) != 1 || !reflect.DeepEqual(result.Blocks[0].Highlight, want) {
+		t.Errorf("blocks = %+v, want highlight %v", result.Blocks, want)
+	}
+}
+
+// formatJSON evaluates source and returns its JSON output, decoded.
+func formatJSON(t *testing.T, source string, opts func(*document.Document, *implDoc.Evaluator) Options) JSONDocument {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	eval := implDoc.NewEvaluator()
+	_ = eval.Evaluate(doc) // Errors are part of the output
+
+	var buf bytes.Buffer
+	if err := (&JSONFormatter{}).Format(&buf, doc, opts(doc, eval)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	var result JSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	return result
+}
+
+func noOptions(*document.Document, *implDoc.Evaluator) Options { return Options{} }
+
+// TestJSONFormatterResults tests the per-line result entries
+func TestJSONFormatterResults(t *testing.T) {
+	result := formatJSON(t, "---\nlocale: de-DE\n---\nrent = 1.200,50 EUR\n\nsize = 2,5 kg\n2 + 3\n", noOptions)
+
+	want := []JSONResult{
+		{Line: 1, Source: "rent = 1.200,50 EUR", Variable: "rent", Output: "EUR1200.50", Value: "EUR1200,50",
+			RawValue: "1200.5", Type: "currency", Currency: &JSONCurrency{Code: "EUR", Symbol: "EUR"}},
+		{Line: 3, Source: "size = 2,5 kg", Variable: "size", Output: "2.5 kg", Value: "2,5 kg",
+			RawValue: "2.5", Type: "quantity", Unit: "kg"},
+		{Line: 4, Source: "2 + 3", Output: "5", Value: "5", RawValue: "5", Type: "number"},
+	}
+	if len(result.Blocks) != 1 || !reflect.DeepEqual(result.Blocks[0].Results, want) {
+		t.Errorf("results = %+v, want %+v", result.Blocks, want)
+	}
+}
+
+func TestJSONFormatterDurations(t *testing.T) {
+	result := formatJSON(t, "---\ndurations: short\n---\nuptime = 90061 seconds\n", noOptions)
+
+	got := result.Blocks[0].Results[0]
+	if got.Output != "1d 1h 1m 1s" || got.Value != "90061 second" {
+		t.Errorf("output = %q, value = %q; want humanized output and exact value", got.Output, got.Value)
+	}
+}
+
+func TestJSONFormatterDistribution(t *testing.T) {
+	result := formatJSON(t, "---\nsamples: 200\n---\nprice = uniform($10, $20)\n", noOptions)
+
+	got := result.Blocks[0].Results[0]
+	if got.Type != "distribution" || got.Currency == nil || got.Currency.Code != "USD" || got.Distribution == nil {
+		t.Fatalf("result = %+v, want a distribution in USD", got)
+	}
+	d := got.Distribution
+	if d.Samples != 200 || got.RawValue == "" || d.Mean == "" || d.P5 == "" || d.P50 == "" || d.P95 == "" {
+		t.Errorf("distribution = %+v, raw value %q; want 200 samples and every summary", d, got.RawValue)
+	}
+	if !strings.HasPrefix(got.Output, "~$") || !strings.Contains(got.Output, "p95 $") {
+		t.Errorf("output = %q, want the mean and percentiles", got.Output)
+	}
+}
+
+// TestJSONFormatterDependencies tests the block dependency edges
+func TestJSONFormatterDependencies(t *testing.T) {
+	result := formatJSON(t, "a = 1\nb = 2\n\n\nNotes.\n\nc = a + b\n", noOptions)
+
+	if len(result.Blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %+v", result.Blocks)
+	}
+	want := []JSONDependency{{Block: 0, Variable: "a"}, {Block: 0, Variable: "b"}}
+	if got := result.Blocks[2].DependsOn; !reflect.DeepEqual(got, want) {
+		t.Errorf("depends_on = %+v, want %+v", got, want)
+	}
+	if result.Blocks[0].DependsOn != nil {
+		t.Errorf("first block should have no dependencies, got %+v", result.Blocks[0].DependsOn)
+	}
+}
+
+// TestJSONFormatterDiagnostics tests diagnostics placed on their result
+// lines, their blocks, and the document
+func TestJSONFormatterDiagnostics(t *testing.T) {
+	source := "x = 1\ny = 2\n\n\ntotal = 2 +\n"
+	result := formatJSON(t, source, func(doc *document.Document, eval *implDoc.Evaluator) Options {
+		var diags []document.Diagnostic
+		for _, d := range eval.Diagnostics() {
+			diags = append(diags, document.Diagnostic{BlockID: d.BlockID, Severity: d.Severity.String(), Code: d.Code, Message: d.Message, Line: d.Line})
+		}
+		diags = append(diags,
+			document.Diagnostic{BlockID: doc.GetBlocks()[0].ID, Severity: "hint", Code: "test_hint", Message: "hint", Line: 2},
+			document.Diagnostic{Severity: "warning", Code: "test_document", Message: "document"},
+		)
+		return Options{Diagnostics: diags}
+	})
+
+	if len(result.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %+v", result.Blocks)
+	}
+	calc := result.Blocks[0]
+	if len(calc.Results) != 2 || len(calc.Results[0].Diagnostics) != 0 ||
+		len(calc.Results[1].Diagnostics) != 1 || calc.Results[1].Diagnostics[0].Code != "test_hint" {
+		t.Errorf("expected the hint on the second result, got %+v", calc.Results)
+	}
+	text := result.Blocks[1]
+	if len(text.Diagnostics) != 1 || text.Diagnostics[0].Code != implDoc.DiagLikelyCalculation || text.Diagnostics[0].Line != 1 {
+		t.Errorf("expected a likely-calculation warning on the text block, got %+v", text.Diagnostics)
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Code != "test_document" {
+		t.Errorf("expected a document diagnostic, got %+v", result.Diagnostics)
+	}
+}
+
+// TestJSONFormatterErrorDiagnostics tests that a checker error is
+// reported on the result of the line it's on
+func TestJSONFormatterErrorDiagnostics(t *testing.T) {
+	result := formatJSON(t, "x = 1\ny = 5 kg + 3 meters\n", noOptions)
+
+	block := result.Blocks[0]
+	if block.Error == "" || len(block.Diagnostics) != 0 {
+		t.Fatalf("expected an error with no block-level diagnostics, got %+v", block)
+	}
+	diags := block.Results[1].Diagnostics
+	if len(diags) != 1 || diags[0].Severity != "error" || diags[0].Code != "incompatible_units" || diags[0].Column != 5 {
+		t.Errorf("expected incompatible_units at 2:5, got %+v", block.Results)
+	}
+}
+
 // TestJSONFormatterExtensions tests file extensions
 func TestJSONFormatterExtensions(t *testing.T) {
 	formatter := &JSONFormatter{}
@@ -179,3 +395,66 @@ func TestJSONFormatterExtensions(t *testing.T) {
 		t.Error("JSONFormatter should handle .json extension")
 	}
 }
+
+// TestJSONFormatterCanonical checks exports are byte-identical across runs,
+// versioned, and free of floating-point artifacts, so they diff cleanly.
+func TestJSONFormatterCanonical(t *testing.T) {
+	source := "---\nglobals:\n  rate: 0.05\n  base: $100\nexchange:\n  USD_EUR: 0.92\n  USD_GBP: 0.79\n---\n" +
+		"water = 1.5 l in cup\nthird = 1 / 3\nprice = base * 3\n\ntotal = price * 2\n"
+	render := func() string {
+		doc, err := document.NewDocument(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = implDoc.NewEvaluator().Evaluate(doc)
+		var buf bytes.Buffer
+		if err := (&JSONFormatter{}).Format(&buf, doc, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	first := render()
+	for range 5 {
+		if again := render(); again != first {
+			t.Fatalf("output differs between runs:\n%s\n---\n%s", first, again)
+		}
+	}
+	if !strings.HasPrefix(first, "{\n  \"schema_version\": 1,") {
+		t.Errorf("schema_version should come first:\n%s", first[:min(80, len(first))])
+	}
+
+	result := formatJSON(t, source, noOptions)
+	results := result.Blocks[0].Results
+	if got := results[0].Value; got != "6.25 cup" {
+		t.Errorf("water value = %q, want the conversion without float artifacts", got)
+	}
+	if got := results[1].RawValue; got != "0.333333333333" {
+		t.Errorf("third raw_value = %q", got)
+	}
+	if got := results[2].Value; got != "$300.00" {
+		t.Errorf("short decimals should be unchanged: price value = %q", got)
+	}
+}
+
+func TestCanonicalDecimal(t *testing.T) {
+	tests := map[string]string{
+		"6.25000000000005":         "6.25",
+		"1.6611555555555556":       "1.661155555556",
+		"925.9259259259259259":     "925.925925925926",
+		"1200.50":                  "1200.50",
+		"0.5":                      "0.5",
+		"12345678901234.567":       "12345678901234.6",
+		"123456789012345678.91":    "123456789012345679",
+		"0.00000000000012":         "0",
+		"79028.918245904369782629": "79028.9182459044",
+	}
+	for in, want := range tests {
+		if got := canonicalDecimal(in); got != want {
+			t.Errorf("canonicalDecimal(%s) = %s, want %s", in, got, want)
+		}
+	}
+	if got := canonicalDecimals("2777.7777777777777777 /s"); got != "2777.77777777778 /s" {
+		t.Errorf("canonicalDecimals = %q", got)
+	}
+}
@@ -30,6 +30,11 @@ func (f *MarkdownFormatter) Format(w io.Writer, doc *document.Document, opts Opt
 
 	blocks := doc.GetBlocks()
 
+	var provenance *provenanceTracker
+	if opts.Provenance {
+		provenance = newProvenanceTracker(doc, opts.ProvenanceTime)
+	}
+
 	for _, node := range blocks {
 		switch block := node.Block.(type) {
 		case *document.CalcBlock:
@@ -38,21 +43,32 @@ func (f *MarkdownFormatter) Format(w io.Writer, doc *document.Document, opts Opt
 				continue
 			}
 
+			var comments map[int]string
+			if provenance != nil {
+				comments = provenance.blockComments(block)
+			}
+
 			// Format calculation blocks in fenced code blocks
 			fmt.Fprintf(w, "```calcmark\n")
-			for _, line := range block.Source() {
+			for i, line := range block.Source() {
 				// Skip result lines from previous saves
 				if isResultLine(line) {
 					continue
 				}
 				fmt.Fprintln(w, line)
+				if comment, ok := comments[i]; ok {
+					fmt.Fprintln(w, comment)
+				}
 			}
 			fmt.Fprintf(w, "```\n\n")
 
 			if block.Error() != nil {
 				fmt.Fprintf(w, "**Error:** %v\n\n", block.Error())
 			} else if block.LastValue() != nil {
-				fmt.Fprintf(w, "**Result:** %s\n\n", display.Format(block.LastValue()))
+				fmt.Fprintf(w, "**Result:** %s\n\n", display.FormatWith(block.LastValue(), DisplayOptions(doc)))
+			}
+			for _, table := range resultTables(block, DisplayOptions(doc)) {
+				writeMarkdownTable(w, table)
 			}
 
 		case *document.TextBlock:
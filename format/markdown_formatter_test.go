@@ -283,3 +283,30 @@ func TestMarkdownFormatterFiltersFrontmatterBlocks(t *testing.T) {
 		t.Errorf("Expected calculation, got: %s", output)
 	}
 }
+
+// TestMarkdownFormatterTables tests that table results, such as amortize()
+// schedules, are written in full as Markdown tables.
+func TestMarkdownFormatterTables(t *testing.T) {
+	doc, err := document.NewDocument("loan = amortize($1200, 0%, 1)\nloan[12].payment\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+		t.Fatalf("Failed to evaluate: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&MarkdownFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"**loan**\n\n| period | payment | interest | principal | balance |\n| ---: | ---: | ---: | ---: | ---: |\n",
+		"| 1 | $100.00 | $0.00 | $100.00 | $1100.00 |\n",
+		"| 12 | $100.00 | $0.00 | $100.00 | $0.00 |\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
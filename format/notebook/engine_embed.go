@@ -0,0 +1,18 @@
+//go:build notebook_engine
+
+package notebook
+
+import _ "embed"
+
+// The engine files are copied here by task build:notebook-engine; the
+// directory is not checked in.
+var (
+	//go:embed engine/calcmark.wasm
+	embeddedWASM []byte
+	//go:embed engine/wasm_exec.js
+	embeddedExec []byte
+)
+
+func init() {
+	Embedded = &Engine{WASM: embeddedWASM, Exec: embeddedExec}
+}
@@ -0,0 +1,155 @@
+// Package notebook exports a CalcMark document as a single self-contained
+// HTML file that recalculates in the browser: the HTML export, plus the
+// document's source and the CalcMark WASM engine, embedded inline. Readers
+// change the frontmatter globals in place and every result updates, with
+// no server and no network.
+//
+// Built with the notebook_engine tag (as release builds are), the package
+// embeds the engine as Embedded. Otherwise the formatter is registered
+// with one loaded from disk:
+//
+//	engine, err := notebook.LoadEngine("dist/component") // task build:wasm:component
+//	format.RegisterFormatter("html-interactive", &notebook.Formatter{Engine: engine})
+package notebook
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// Engine files, as written by task build:wasm:component.
+const (
+	EngineFile = "calcmark.wasm"
+	ExecFile   = "wasm_exec.js" // Go's WASM support script, from the release that built EngineFile
+)
+
+//go:embed notebook.js
+var notebookScript string
+
+// Engine is the CalcMark WASM build a notebook embeds.
+type Engine struct {
+	WASM []byte // The compiled impl/wasm module
+	Exec []byte // wasm_exec.js from the same Go release
+}
+
+// Embedded is the engine built into the binary by the notebook_engine
+// build tag, or nil without it.
+var Embedded *Engine
+
+// LoadEngine reads the engine files from dir.
+func LoadEngine(dir string) (*Engine, error) {
+	wasm, err := os.ReadFile(filepath.Join(dir, EngineFile))
+	if err != nil {
+		return nil, fmt.Errorf("load WASM engine: %w (build it with 'task build:wasm:component')", err)
+	}
+	exec, err := os.ReadFile(filepath.Join(dir, ExecFile))
+	if err != nil {
+		return nil, fmt.Errorf("load WASM engine: %w", err)
+	}
+	return &Engine{WASM: wasm, Exec: exec}, nil
+}
+
+// Formatter writes interactive HTML notebooks. It implements
+// format.Formatter.
+type Formatter struct {
+	Engine *Engine
+}
+
+// Extensions returns no extensions: notebooks are .html files, which the
+// HTML formatter handles unless this one is asked for by name.
+func (f *Formatter) Extensions() []string {
+	return nil
+}
+
+// Format writes the document as an interactive HTML notebook.
+//
+// The page is the HTML export, so it reads the same before the engine
+// starts and without JavaScript. Documents with imports can't be exported,
+// since the notebook has no other files to read.
+func (f *Formatter) Format(w io.Writer, doc *document.Document, opts format.Options) error {
+	if f.Engine == nil || len(f.Engine.WASM) == 0 {
+		return errors.New("html-interactive needs the CalcMark WASM engine")
+	}
+	fm := doc.GetFrontmatter()
+	if fm != nil && len(fm.Imports) > 0 {
+		return errors.New("html-interactive can't embed imported documents; inline them or export as html")
+	}
+
+	var page bytes.Buffer
+	if err := (&format.HTMLFormatter{}).Format(&page, doc, format.Options{Results: format.ResultsInline}); err != nil {
+		return err
+	}
+
+	var wasm bytes.Buffer
+	zw := gzip.NewWriter(&wasm)
+	if _, err := zw.Write(f.Engine.WASM); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	var scripts bytes.Buffer
+	err := scriptTemplate.Execute(&scripts, struct {
+		Source  string
+		Decimal string
+		Exec    template.JS
+		WASM    string
+		Script  template.JS
+	}{
+		Source:  Source(doc),
+		Decimal: string(doc.NumberLocale().Decimal),
+		Exec:    template.JS(f.Engine.Exec),
+		WASM:    base64.StdEncoding.EncodeToString(wasm.Bytes()),
+		Script:  template.JS(notebookScript),
+	})
+	if err != nil {
+		return err
+	}
+
+	html, ok := strings.CutSuffix(strings.TrimRight(page.String(), "\n"), "</html>")
+	body, ok2 := strings.CutSuffix(strings.TrimRight(html, "\n"), "</body>")
+	if !ok || !ok2 {
+		return errors.New("html-interactive: unexpected HTML export layout")
+	}
+	if _, err := io.WriteString(w, body); err != nil {
+		return err
+	}
+	if _, err := scripts.WriteTo(w); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "</body>\n\n</html>\n")
+	return err
+}
+
+// scriptTemplate holds the notebook's data and scripts, appended to the
+// HTML export's body. html/template quotes the source for the script.
+var scriptTemplate = template.Must(template.New("notebook").Parse(`    <script>{{.Exec}}</script>
+    <script>
+        const calcmarkNotebook = { source: {{.Source}}, decimal: {{.Decimal}}, wasm: {{.WASM}} };
+    </script>
+    <script>{{.Script}}</script>
+`))
+
+// Source returns the document's CalcMark source, as the engine reads it:
+// the frontmatter followed by every block, so the engine's blocks match
+// the exported page's one for one.
+func Source(doc *document.Document) string {
+	var lines []string
+	for _, node := range doc.GetBlocks() {
+		lines = append(lines, node.Block.Source()...)
+	}
+	return doc.GetFrontmatter().Serialize() + strings.Join(lines, "\n")
+}
@@ -0,0 +1,204 @@
+// Recalculates an exported CalcMark notebook in the browser.
+//
+// The page is the HTML export of calcmarkNotebook.source. Once the embedded
+// engine starts, each frontmatter global listed on the page becomes a
+// control matching its widget, and changing one re-renders the document
+// with the new value, updating results in place.
+(() => {
+  "use strict";
+
+  const notebook = calcmarkNotebook;
+  const overrides = {};
+  let calcmark = null;
+
+  const status = document.createElement("p");
+  status.className = "calcmark-status";
+  status.setAttribute("role", "status");
+  status.textContent = "Loading calculator…";
+  document.body.prepend(status);
+
+  // loadEngine decompresses and starts the embedded WASM engine.
+  async function loadEngine() {
+    const bytes = Uint8Array.from(atob(notebook.wasm), (c) => c.charCodeAt(0));
+    const stream = new Blob([bytes]).stream().pipeThrough(new DecompressionStream("gzip"));
+    const module = await new Response(stream).arrayBuffer();
+    const go = new Go();
+    const { instance } = await WebAssembly.instantiate(module, go.importObject);
+    go.run(instance); // Registers window.calcmark, then blocks forever
+    return window.calcmark;
+  }
+
+  // render evaluates the document with the current overrides.
+  function render() {
+    const response = calcmark.renderDocument(notebook.source, JSON.stringify(overrides));
+    if (response.error) {
+      throw new Error(response.error);
+    }
+    return JSON.parse(response.document);
+  }
+
+  // update writes a render's results into the page's calculation blocks.
+  function update(doc) {
+    const blocks = document.querySelectorAll(".calc-block");
+    doc.blocks
+      .filter((block) => block.type === "calculation")
+      .forEach((block, i) => {
+        const element = blocks[i];
+        if (!element) {
+          return;
+        }
+        const lines = element.querySelectorAll(".calc-line");
+        (block.lines || []).forEach((line, j) => {
+          if (lines[j]) {
+            setResult(lines[j], line);
+          }
+        });
+        setError(element, block.error);
+      });
+  }
+
+  function setResult(lineElement, line) {
+    let result = lineElement.querySelector(".calc-inline-result");
+    if (!line.result) {
+      if (result) {
+        result.remove();
+      }
+      return;
+    }
+    if (!result) {
+      result = document.createElement("span");
+      lineElement.append(result);
+    }
+    result.className = "calc-inline-result" + (line.highlight ? " highlight-" + line.highlight : "");
+    result.textContent = line.result;
+  }
+
+  function setError(blockElement, message) {
+    let error = blockElement.querySelector(".calc-error");
+    if (!message) {
+      if (error) {
+        error.remove();
+      }
+      return;
+    }
+    if (!error) {
+      error = document.createElement("div");
+      error.className = "calc-error";
+      blockElement.append(error);
+    }
+    error.innerHTML = "<strong>Error:</strong> ";
+    error.append(message);
+  }
+
+  // numberPattern matches the first number in a global's source text, as
+  // widget steps do, so "$5000" keeps its "$" when a slider moves it.
+  const numberPattern = /-?[0-9](?:[0-9_,.'’]*[0-9])?/;
+
+  // numberIn returns the number in a global's source text as an input
+  // value: no digit grouping, with a decimal point.
+  function numberIn(value) {
+    const match = numberPattern.exec(value);
+    if (!match) {
+      return "";
+    }
+    let text = "";
+    for (const c of match[0]) {
+      if (c === (notebook.decimal || ".")) {
+        text += ".";
+      } else if (c === "-" || (c >= "0" && c <= "9")) {
+        text += c;
+      }
+    }
+    return text;
+  }
+
+  function withNumber(value, number) {
+    const text = notebook.decimal === "," ? String(number).replace(".", ",") : String(number);
+    return numberPattern.test(value) ? value.replace(numberPattern, text) : text;
+  }
+
+  // control builds the input for a param, matching its widget.
+  function control(param) {
+    const widget = param.widget || { type: "text" };
+    let input;
+    switch (widget.type) {
+      case "toggle":
+        input = document.createElement("input");
+        input.type = "checkbox";
+        input.checked = param.value.trim().toLowerCase() === "true";
+        input.addEventListener("change", () => change(param.name, String(input.checked)));
+        return input;
+      case "select":
+        input = document.createElement("select");
+        for (const option of widget.options) {
+          input.append(new Option(option, option, false, option === param.value.trim()));
+        }
+        input.addEventListener("change", () => change(param.name, input.value));
+        return input;
+      case "slider":
+      case "number": {
+        input = document.createElement("input");
+        input.type = widget.type === "slider" ? "range" : "number";
+        for (const attr of ["min", "max", "step"]) {
+          if (widget[attr]) {
+            input[attr] = widget[attr];
+          }
+        }
+        input.value = numberIn(param.value);
+        const label = document.createElement("output");
+        label.textContent = param.value;
+        input.addEventListener("input", () => {
+          label.textContent = withNumber(param.value, input.value);
+          change(param.name, label.textContent);
+        });
+        if (widget.type !== "slider") {
+          return input;
+        }
+        const span = document.createElement("span");
+        span.append(input, " ", label);
+        return span;
+      }
+      default:
+        input = document.createElement("input");
+        input.type = "text";
+        input.value = param.value;
+        input.addEventListener("change", () => change(param.name, input.value));
+        return input;
+    }
+  }
+
+  // controls replaces each global's value on the page with its control.
+  function controls(params) {
+    const terms = document.querySelectorAll(".frontmatter dl:not(.exchange) dt");
+    for (const param of params) {
+      const term = Array.from(terms).find((dt) => dt.textContent.trim() === param.name);
+      const value = term && term.nextElementSibling;
+      if (value) {
+        value.replaceChildren(control(param));
+      }
+    }
+  }
+
+  function change(name, value) {
+    overrides[name] = value;
+    try {
+      update(render());
+      status.hidden = true;
+    } catch (err) {
+      status.textContent = err.message;
+      status.hidden = false;
+    }
+  }
+
+  loadEngine()
+    .then((engine) => {
+      calcmark = engine;
+      const doc = render();
+      controls(doc.params);
+      update(doc);
+      status.hidden = true;
+    })
+    .catch((err) => {
+      status.textContent = "Calculator unavailable: " + err.message;
+    });
+})();
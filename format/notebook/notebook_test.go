@@ -0,0 +1,145 @@
+package notebook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+const budget = `---
+locale: de-DE
+globals:
+  growth: 0,05
+widgets:
+  growth: {type: slider, min: 0, max: 0.2, step: 0.01}
+---
+# Budget
+
+next_month = 5000 * (1 + growth)
+
+
+yearly = next_month * 12
+`
+
+var engine = &Engine{WASM: []byte("\x00asm engine"), Exec: []byte("/* wasm_exec */")}
+
+func newDocument(t *testing.T, source string) *document.Document {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	_ = implDoc.NewEvaluator().Evaluate(doc)
+	return doc
+}
+
+func TestFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Formatter{Engine: engine}).Format(&buf, newDocument(t, budget), format.Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	page := buf.String()
+
+	// The page is the HTML export with the scripts appended
+	for _, want := range []string{`class="calc-block"`, `<dt>growth</dt>`, "<script>/* wasm_exec */</script>", "calcmarkNotebook"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("notebook missing %q", want)
+		}
+	}
+	if !strings.HasSuffix(page, "</body>\n\n</html>\n") || strings.Count(page, "</body>") != 1 {
+		t.Error("scripts should end the body of a single HTML page")
+	}
+	if !strings.Contains(page, `decimal: ","`) {
+		t.Error("notebook should carry the document's decimal mark")
+	}
+
+	// The engine is embedded gzipped and base64-encoded
+	m := regexp.MustCompile(`wasm: "([^"]*)"`).FindStringSubmatch(page)
+	if m == nil {
+		t.Fatal("notebook missing the engine")
+	}
+	var encoded string
+	if err := json.Unmarshal([]byte(`"`+m[1]+`"`), &encoded); err != nil {
+		t.Fatalf("engine is not a string literal: %v", err)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("engine is not base64: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("engine is not gzipped: %v", err)
+	}
+	if got, _ := io.ReadAll(zr); !bytes.Equal(got, engine.WASM) {
+		t.Errorf("embedded engine = %q, want %q", got, engine.WASM)
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	doc := newDocument(t, "x = 1\n")
+	if err := (&Formatter{}).Format(io.Discard, doc, format.Options{}); err == nil {
+		t.Error("expected error without an engine")
+	}
+
+	doc = newDocument(t, "---\nimports:\n  - other.cm\n---\nx = 1\n")
+	if err := (&Formatter{Engine: engine}).Format(io.Discard, doc, format.Options{}); err == nil {
+		t.Error("expected error for a document with imports")
+	}
+}
+
+// TestSource checks the engine sees the same calculation blocks as the
+// page, so results land on the right lines.
+func TestSource(t *testing.T) {
+	doc := newDocument(t, budget)
+	reparsed := newDocument(t, Source(doc))
+
+	calcLines := func(d *document.Document) [][]string {
+		var blocks [][]string
+		for _, node := range d.GetBlocks() {
+			if block, ok := node.Block.(*document.CalcBlock); ok {
+				blocks = append(blocks, block.Source())
+			}
+		}
+		return blocks
+	}
+	got, want := calcLines(reparsed), calcLines(doc)
+	if len(got) != len(want) || len(want) != 2 {
+		t.Fatalf("reparsed calculation blocks = %q, want %q", got, want)
+	}
+	for i := range want {
+		if strings.Join(got[i], "\n") != strings.Join(want[i], "\n") {
+			t.Errorf("block %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if reparsed.GetFrontmatter().Globals["growth"] != "0,05" {
+		t.Errorf("frontmatter did not survive: %v", reparsed.GetFrontmatter().Globals)
+	}
+}
+
+func TestLoadEngine(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadEngine(dir); err == nil {
+		t.Error("expected error for a directory without the engine")
+	}
+
+	os.WriteFile(filepath.Join(dir, EngineFile), engine.WASM, 0o644)
+	os.WriteFile(filepath.Join(dir, ExecFile), engine.Exec, 0o644)
+	got, err := LoadEngine(dir)
+	if err != nil {
+		t.Fatalf("LoadEngine failed: %v", err)
+	}
+	if !bytes.Equal(got.WASM, engine.WASM) || !bytes.Equal(got.Exec, engine.Exec) {
+		t.Errorf("LoadEngine = %+v, want %+v", got, engine)
+	}
+}
@@ -0,0 +1,119 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// font is one of the standard Type 1 fonts every PDF viewer provides, so
+// no font data is embedded. Text is encoded as WinAnsi (Windows-1252).
+type font int
+
+const (
+	regular font = iota
+	bold
+	italic
+	mono
+	monoBold
+)
+
+// fontNames are the base font names, by font.
+var fontNames = [...]string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Courier", "Courier-Bold"}
+
+// resource returns the font's name in page resources, e.g. "F1".
+func (f font) resource() string {
+	return fmt.Sprintf("F%d", int(f)+1)
+}
+
+// width returns the width of s set in f at size, in points.
+func (f font) width(s string, size float64) float64 {
+	units := 0
+	for _, b := range encode(s) {
+		units += f.glyphWidth(b)
+	}
+	return float64(units) * size / 1000
+}
+
+// glyphWidth returns the advance of a WinAnsi character, in 1/1000 em.
+// Characters outside printable ASCII use an average width.
+func (f font) glyphWidth(b byte) int {
+	switch f {
+	case mono, monoBold:
+		return 600
+	}
+	if b < 32 || b > 126 {
+		return 556
+	}
+	if f == bold {
+		return helveticaBoldWidths[b-32]
+	}
+	return helveticaWidths[b-32]
+}
+
+// Advances of ASCII 32-126 from the Adobe font metrics. Helvetica-Oblique
+// shares Helvetica's.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// substitutes spell characters WinAnsi lacks that documents commonly use.
+var substitutes = map[rune]string{
+	'→': "->",
+	'←': "<-",
+	'−': "-",
+	'≤': "<=",
+	'≥': ">=",
+	'≠': "!=",
+}
+
+// encode converts s to WinAnsi bytes. Characters it can't represent
+// become "?".
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if sub, ok := substitutes[r]; ok {
+			out = append(out, sub...)
+			continue
+		}
+		if r == '\t' {
+			out = append(out, "    "...)
+			continue
+		}
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok || b < 32 {
+			b = '?'
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// literal returns s as a PDF string literal, e.g. "(a \(b\))".
+func literal(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range encode(s) {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
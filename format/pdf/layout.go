@@ -0,0 +1,218 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page geometry, in points: US Letter with 0.75in side margins. Content
+// runs between the header and footer bands.
+const (
+	pageWidth  = 612.0
+	pageHeight = 792.0
+	margin     = 54.0
+	contentTop = pageHeight - 72
+	contentEnd = 64.0 // Lowest point of content
+	headerY    = pageHeight - 40
+	footerY    = 34.0
+)
+
+// color is an RGB fill color with components from 0 to 1.
+type color [3]float64
+
+var (
+	black     = color{0, 0, 0}
+	gray      = color{0.42, 0.45, 0.5}
+	accent    = color{0.05, 0.35, 0.65}
+	errorRed  = color{0.75, 0.1, 0.1}
+	calcShade = color{0.95, 0.96, 0.97}
+	ruleGray  = color{0.8, 0.8, 0.8}
+)
+
+// span is a run of text in one font. A span with brk set is a forced
+// line break.
+type span struct {
+	text string
+	font font
+	brk  bool
+}
+
+// canvas is a page's content stream of drawing operators.
+type canvas struct {
+	strings.Builder
+}
+
+// layout places content top to bottom on successive pages.
+type layout struct {
+	pages []*canvas
+	y     float64 // Top of the next line on the current page
+}
+
+// page returns the current page.
+func (l *layout) page() *canvas {
+	return l.pages[len(l.pages)-1]
+}
+
+// newPage starts a page.
+func (l *layout) newPage() {
+	l.pages = append(l.pages, &canvas{})
+	l.y = contentTop
+}
+
+// ensure starts a new page if a line of height doesn't fit on this one.
+func (l *layout) ensure(height float64) {
+	if len(l.pages) == 0 || l.y-height < contentEnd {
+		l.newPage()
+	}
+}
+
+// space adds vertical space, except at the top of a page.
+func (l *layout) space(height float64) {
+	if len(l.pages) > 0 && l.y < contentTop {
+		l.y -= height
+	}
+}
+
+// text draws s with its baseline at (x, y).
+func (p *canvas) text(s string, f font, size, x, y float64, c color) {
+	fmt.Fprintf(p, "BT /%s %.2f Tf %.3f %.3f %.3f rg %.2f %.2f Td %s Tj ET\n",
+		f.resource(), size, c[0], c[1], c[2], x, y, literal(s))
+}
+
+// rect fills a rectangle with its lower left corner at (x, y).
+func (p *canvas) rect(x, y, w, h float64, c color) {
+	fmt.Fprintf(p, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", c[0], c[1], c[2], x, y, w, h)
+}
+
+// rule draws a horizontal line at y.
+func (p *canvas) rule(x1, x2, y float64, c color) {
+	fmt.Fprintf(p, "%.3f %.3f %.3f RG 0.5 w %.2f %.2f m %.2f %.2f l S\n", c[0], c[1], c[2], x1, y, x2, y)
+}
+
+// paragraph wraps spans to the width between x and the right margin and
+// draws them at size, one line per leading. A marker, such as a list
+// bullet, is drawn left of x on the first line.
+func (l *layout) paragraph(spans []span, size, x float64, c color, marker string) {
+	leading := size * 1.4
+	for i, line := range wrap(spans, size, pageWidth-margin-x) {
+		l.ensure(leading)
+		baseline := l.y - size*1.1
+		if i == 0 && marker != "" {
+			l.page().text(marker, regular, size, x-regular.width(marker+" ", size), baseline, c)
+		}
+		lx := x
+		for _, seg := range line {
+			l.page().text(seg.text, seg.font, size, lx, baseline, c)
+			lx += seg.font.width(seg.text, size)
+		}
+		l.y -= leading
+	}
+}
+
+// word is a unit of wrapping: text that is never split across lines
+// unless it is wider than a whole line.
+type word struct {
+	text  string
+	font  font
+	space bool // Preceded by a space
+	brk   bool // Forced line break before this word
+}
+
+// wrap breaks spans into lines no wider than width at size. Each line is
+// a list of segments, with adjacent words in the same font merged.
+func wrap(spans []span, size, width float64) [][]span {
+	var words []word
+	space, brk := false, false
+	for _, s := range spans {
+		if s.brk {
+			brk = true
+			continue
+		}
+		text := s.text
+		for text != "" {
+			n := strings.IndexAny(text, " \t\n")
+			if n == 0 {
+				space = true
+				text = text[1:]
+				continue
+			}
+			if n < 0 {
+				n = len(text)
+			}
+			words = append(words, word{text: text[:n], font: s.font, space: space, brk: brk})
+			space, brk = false, false
+			text = text[n:]
+		}
+	}
+
+	var lines [][]span
+	var line []span
+	lineWidth := 0.0
+	flush := func() {
+		lines = append(lines, line)
+		line, lineWidth = nil, 0
+	}
+	for _, w := range words {
+		if w.brk && len(line) > 0 {
+			flush()
+		}
+		for _, piece := range splitWord(w.text, w.font, size, width) {
+			text := piece
+			if w.space && len(line) > 0 {
+				text = " " + piece
+			}
+			wWidth := w.font.width(text, size)
+			if len(line) > 0 && lineWidth+wWidth > width {
+				flush()
+				text = piece
+				wWidth = w.font.width(text, size)
+			}
+			if n := len(line); n > 0 && line[n-1].font == w.font {
+				line[n-1].text += text
+			} else {
+				line = append(line, span{text: text, font: w.font})
+			}
+			lineWidth += wWidth
+			w.space = false // Pieces of one word join without spaces
+		}
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines
+}
+
+// splitWord splits text into pieces that each fit in width; most words
+// are returned whole.
+func splitWord(text string, f font, size, width float64) []string {
+	if f.width(text, size) <= width {
+		return []string{text}
+	}
+	var pieces []string
+	start, w := 0, 0.0
+	for i, r := range text {
+		rw := f.width(string(r), size)
+		if w+rw > width && i > start {
+			pieces = append(pieces, text[start:i])
+			start, w = i, 0
+		}
+		w += rw
+	}
+	return append(pieces, text[start:])
+}
+
+// chunk splits s into pieces of at most n characters, for monospaced
+// text.
+func chunk(s string, n int) []string {
+	if n < 1 || utf8.RuneCountInString(s) <= n {
+		return []string{s}
+	}
+	var pieces []string
+	runes := []rune(s)
+	for len(runes) > n {
+		pieces = append(pieces, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return append(pieces, string(runes))
+}
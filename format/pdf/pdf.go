@@ -0,0 +1,382 @@
+// Package pdf renders a CalcMark document as a printable PDF report:
+// markdown text typeset as headings, paragraphs, lists, and code, and
+// each calculation in monospace with its result right-aligned beside it.
+//
+// It is a separate package so the format package stays free of binary
+// output; register it to make it available by name:
+//
+//	format.RegisterFormatter("pdf", &pdf.Formatter{})
+//
+// Every page carries a header built from format.Options.Header (see
+// DefaultHeader) and a page number footer. The file is written directly
+// with the standard PDF fonts, which cover Latin-1 text; characters they
+// lack print as "?".
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// DefaultHeader is the page header used when format.Options.Header is
+// empty.
+//
+// A header has up to three sections separated by "|": left, center, and
+// right, or left and right when there are two. Sections may use the
+// placeholders {title}, {author}, and {date}, read from the document's
+// frontmatter (the title falls back to the first heading), and {page}
+// and {pages}.
+const DefaultHeader = "{title}|{author}|{date}"
+
+// Text sizes, in points.
+const (
+	bodySize   = 10.5
+	calcSize   = 9.5
+	headerSize = 8.5
+)
+
+// Formatter writes PDF reports. It implements format.Formatter.
+type Formatter struct{}
+
+// Extensions returns the file extensions handled by this formatter.
+func (f *Formatter) Extensions() []string {
+	return []string{".pdf"}
+}
+
+// Format writes the document as a PDF to the writer.
+func (f *Formatter) Format(w io.Writer, doc *document.Document, opts format.Options) error {
+	var l layout
+	l.newPage()
+	for _, node := range doc.GetBlocks() {
+		switch block := node.Block.(type) {
+		case *document.CalcBlock:
+			calcBlock(&l, block, format.DisplayOptions(doc))
+		case *document.TextBlock:
+			markdownBlock(&l, strings.Join(block.Source(), "\n"))
+		}
+	}
+
+	header := opts.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+	meta := metadata(doc)
+	for i, page := range l.pages {
+		decorate(page, i, len(l.pages), header, meta)
+	}
+	return write(w, l.pages, meta)
+}
+
+// docMeta is the document information shown in headers and recorded in
+// the file.
+type docMeta struct {
+	title, author, date string
+}
+
+func metadata(doc *document.Document) docMeta {
+	meta := docMeta{title: format.DocumentTitle(doc)}
+	if fm := doc.GetFrontmatter(); fm != nil {
+		meta.author, meta.date = fm.Author, fm.Date
+	}
+	return meta
+}
+
+// decorate draws the header and footer of page i of pages.
+func decorate(p *canvas, i, pages int, header string, meta docMeta) {
+	r := strings.NewReplacer(
+		"{title}", meta.title,
+		"{author}", meta.author,
+		"{date}", meta.date,
+		"{page}", fmt.Sprint(i+1),
+		"{pages}", fmt.Sprint(pages),
+	)
+	sections := strings.SplitN(header, "|", 3)
+	if len(sections) == 2 {
+		sections = []string{sections[0], "", sections[1]}
+	}
+	drawn := false
+	for j, section := range sections {
+		text := strings.TrimSpace(r.Replace(section))
+		if text == "" {
+			continue
+		}
+		drawn = true
+		width := regular.width(text, headerSize)
+		x := margin
+		switch j {
+		case 1:
+			x = (pageWidth - width) / 2
+		case 2:
+			x = pageWidth - margin - width
+		}
+		p.text(text, regular, headerSize, x, headerY, gray)
+	}
+	if drawn {
+		p.rule(margin, pageWidth-margin, headerY-6, ruleGray)
+	}
+
+	footer := fmt.Sprintf("Page %d of %d", i+1, pages)
+	p.text(footer, regular, headerSize, (pageWidth-regular.width(footer, headerSize))/2, footerY, gray)
+}
+
+// calcBlock draws a calculation block: each line's source on a shaded
+// band with its result right-aligned, followed by the block's error.
+func calcBlock(l *layout, block *document.CalcBlock, displayOpts display.Options) {
+	const (
+		leading = calcSize * 1.45
+		pad     = 6.0
+	)
+	charWidth := mono.width(" ", calcSize)
+	width := pageWidth - 2*margin - 2*pad
+
+	l.space(4)
+	results := block.Results()
+	stmt := 0
+	for _, line := range block.Source() {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		result := ""
+		if stmt < len(results) && results[stmt] != nil {
+			result = display.FormatWith(results[stmt], displayOpts)
+		}
+		stmt++
+
+		resultWidth := 0.0
+		if result != "" {
+			resultWidth = monoBold.width(result, calcSize) + 2*charWidth
+		}
+		pieces := chunk(line, int(width/charWidth))
+		last := pieces[len(pieces)-1]
+		if result != "" && mono.width(last, calcSize)+resultWidth > width {
+			pieces = append(pieces, "") // Result on a line of its own
+		}
+
+		for i, piece := range pieces {
+			l.ensure(leading)
+			l.page().rect(margin, l.y-leading, pageWidth-2*margin, leading, calcShade)
+			baseline := l.y - calcSize*1.1
+			l.page().text(piece, mono, calcSize, margin+pad, baseline, black)
+			if i == len(pieces)-1 && result != "" {
+				x := pageWidth - margin - pad - monoBold.width(result, calcSize)
+				l.page().text(result, monoBold, calcSize, x, baseline, accent)
+			}
+			l.y -= leading
+		}
+	}
+
+	if err := block.Error(); err != nil {
+		l.space(2)
+		l.paragraph([]span{{text: "Error: " + err.Error(), font: italic}}, calcSize, margin+pad, errorRed, "")
+	}
+	l.space(6)
+}
+
+// markdownBlock draws markdown text.
+func markdownBlock(l *layout, source string) {
+	p := parser.NewWithExtensions(parser.CommonExtensions)
+	root := p.Parse([]byte(source))
+	for _, child := range root.GetChildren() {
+		markdownNode(l, child, margin, "")
+	}
+}
+
+// markdownNode draws a block-level markdown node indented to x. A
+// marker, for list items, is drawn beside the node's first line.
+func markdownNode(l *layout, node ast.Node, x float64, marker string) {
+	switch n := node.(type) {
+	case *ast.Heading:
+		size := map[int]float64{1: 18, 2: 15, 3: 13}[n.Level]
+		if size == 0 {
+			size = 11.5
+		}
+		l.space(size * 0.6)
+		l.paragraph(inlines(n, bold), size, x, black, marker)
+		l.space(3)
+
+	case *ast.Paragraph:
+		l.paragraph(inlines(n, regular), bodySize, x, black, marker)
+		l.space(5)
+
+	case *ast.List:
+		for i, item := range n.Children {
+			bullet := "•"
+			if n.ListFlags&ast.ListTypeOrdered != 0 {
+				bullet = fmt.Sprintf("%d.", max(n.Start, 1)+i)
+			}
+			for j, child := range item.GetChildren() {
+				m := ""
+				if j == 0 {
+					m = bullet
+				}
+				markdownNode(l, child, x+16, m)
+			}
+		}
+		l.space(3)
+
+	case *ast.CodeBlock:
+		const leading = calcSize * 1.45
+		charWidth := mono.width(" ", calcSize)
+		l.space(2)
+		for _, line := range strings.Split(strings.TrimRight(string(n.Literal), "\n"), "\n") {
+			for _, piece := range chunk(line, int((pageWidth-margin-x-12)/charWidth)) {
+				l.ensure(leading)
+				l.page().rect(x, l.y-leading, pageWidth-margin-x, leading, calcShade)
+				l.page().text(piece, mono, calcSize, x+6, l.y-calcSize*1.1, black)
+				l.y -= leading
+			}
+		}
+		l.space(8)
+
+	case *ast.BlockQuote:
+		for _, child := range n.Children {
+			markdownNode(l, child, x+18, "")
+		}
+
+	case *ast.HorizontalRule:
+		l.space(6)
+		l.ensure(6)
+		l.page().rule(x, pageWidth-margin, l.y, ruleGray)
+		l.y -= 6
+
+	case *ast.Table:
+		for _, row := range tableRows(n) {
+			var spans []span
+			for i, cell := range row.cells {
+				if i > 0 {
+					spans = append(spans, span{text: "   |   ", font: regular})
+				}
+				f := regular
+				if row.header {
+					f = bold
+				}
+				spans = append(spans, inlines(cell, f)...)
+			}
+			l.paragraph(spans, bodySize, x, black, "")
+		}
+		l.space(5)
+
+	default:
+		if leaf := node.AsLeaf(); leaf != nil {
+			if text := strings.TrimSpace(string(leaf.Literal)); text != "" && !isHTML(node) {
+				l.paragraph([]span{{text: text, font: regular}}, bodySize, x, black, marker)
+			}
+			return
+		}
+		for _, child := range node.GetChildren() {
+			markdownNode(l, child, x, marker)
+			marker = ""
+		}
+	}
+}
+
+func isHTML(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.HTMLBlock, *ast.HTMLSpan:
+		return true
+	}
+	return false
+}
+
+// tableRow is a row of table cells.
+type tableRow struct {
+	cells  []ast.Node
+	header bool
+}
+
+// tableRows collects the rows of a markdown table.
+func tableRows(table *ast.Table) []tableRow {
+	var rows []tableRow
+	ast.WalkFunc(table, func(node ast.Node, entering bool) ast.WalkStatus {
+		row, ok := node.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		tr := tableRow{}
+		for _, c := range row.Children {
+			cell, ok := c.(*ast.TableCell)
+			if !ok {
+				continue
+			}
+			tr.cells = append(tr.cells, cell)
+			tr.header = tr.header || cell.IsHeader
+		}
+		rows = append(rows, tr)
+		return ast.SkipChildren
+	})
+	return rows
+}
+
+// inlines flattens a node's inline content into spans, starting in font
+// base: emphasis sets italic, strong emphasis bold, and code spans
+// monospace.
+func inlines(node ast.Node, base font) []span {
+	var spans []span
+	var walk func(n ast.Node, f font)
+	walk = func(n ast.Node, f font) {
+		switch n := n.(type) {
+		case *ast.Text:
+			spans = append(spans, span{text: string(n.Literal), font: f})
+		case *ast.Code:
+			spans = append(spans, span{text: string(n.Literal), font: mono})
+		case *ast.Softbreak:
+			spans = append(spans, span{text: " ", font: f})
+		case *ast.Hardbreak:
+			spans = append(spans, span{brk: true})
+		case *ast.HTMLSpan:
+			return
+		case *ast.Strong:
+			f = bold
+		case *ast.Emph:
+			if f == regular {
+				f = italic
+			}
+		}
+		for _, child := range n.GetChildren() {
+			walk(child, f)
+		}
+	}
+	for _, child := range node.GetChildren() {
+		walk(child, base)
+	}
+	return spans
+}
+
+// write serializes the pages as a PDF file.
+func write(out io.Writer, pages []*canvas, meta docMeta) error {
+	var w writer
+	catalog := w.add("<< /Type /Catalog /Pages 2 0 R >>")
+	tree := w.reserve()
+
+	var fonts strings.Builder
+	for i, name := range fontNames {
+		n := w.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fmt.Fprintf(&fonts, " /%s %d 0 R", font(i).resource(), n)
+	}
+
+	var kids []string
+	for _, page := range pages {
+		content := w.addStream(page.String())
+		n := w.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %g %g] /Resources << /Font <<%s >> >> /Contents %d 0 R >>",
+			tree, pageWidth, pageHeight, fonts.String(), content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", n))
+	}
+	w.set(tree, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	info := "<< /Producer (CalcMark)"
+	if meta.title != "" {
+		info += " /Title " + textString(meta.title)
+	}
+	if meta.author != "" {
+		info += " /Author " + textString(meta.author)
+	}
+	info += " >>"
+	return w.writeTo(out, catalog, w.add(info))
+}
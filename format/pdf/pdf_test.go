@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// render formats source as a PDF, checks the file's structure, and
+// returns the decompressed content stream of each page.
+func render(t *testing.T, source string, opts format.Options) (string, []string) {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	_ = implDoc.NewEvaluator().Evaluate(doc)
+
+	var buf bytes.Buffer
+	if err := (&Formatter{}).Format(&buf, doc, opts); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	data := buf.String()
+	if !strings.HasPrefix(data, "%PDF-1.4\n") || !strings.HasSuffix(data, "%%EOF\n") {
+		t.Fatalf("missing PDF header or trailer")
+	}
+
+	// Every cross-reference entry points at its object
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(data)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(m[1])
+	lines := strings.Split(data[xref:], "\n")
+	if lines[0] != "xref" {
+		t.Fatalf("startxref does not point at the xref table: %q", lines[0])
+	}
+	var count int
+	fmt.Sscanf(lines[1], "0 %d", &count)
+	for n := 1; n < count; n++ {
+		offset, _ := strconv.Atoi(lines[2+n][:10])
+		if !strings.HasPrefix(data[offset:], fmt.Sprintf("%d 0 obj\n", n)) {
+			t.Errorf("xref entry %d points at %q", n, data[offset:min(offset+12, len(data))])
+		}
+	}
+
+	var pages []string
+	for _, s := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllStringSubmatch(data, -1) {
+		zr, err := zlib.NewReader(strings.NewReader(s[1]))
+		if err != nil {
+			t.Fatalf("content stream is not Flate-compressed: %v", err)
+		}
+		content, _ := io.ReadAll(zr)
+		pages = append(pages, string(content))
+	}
+	if got := regexp.MustCompile(`/Type /Pages /Kids \[[^]]*\] /Count (\d+)`).FindStringSubmatch(data); got == nil || got[1] != fmt.Sprint(len(pages)) {
+		t.Errorf("page tree count %v, want %d pages", got, len(pages))
+	}
+	return data, pages
+}
+
+func TestFormat(t *testing.T) {
+	source := "---\ntitle: Budget (draft)\nauthor: Ada\ndate: 2025-10-01\n---\n" +
+		"# Monthly\n\nSome **bold** text.\n\n- one\n- two\n\nrent = $1200\nfood = $400\ntotal = rent + food\n"
+	data, pages := render(t, source, format.Options{})
+
+	if len(pages) != 1 {
+		t.Fatalf("expected 1 page, got %d", len(pages))
+	}
+	for _, want := range []string{
+		`(Budget \(draft\)) Tj`,
+		`(Ada) Tj`,
+		`(2025-10-01) Tj`,
+		`(Page 1 of 1) Tj`,
+		`/F2 18.00 Tf`, // Heading
+		`(Monthly) Tj`,
+		`(rent = $1200) Tj`,
+		`($1600.00) Tj`,
+	} {
+		if !strings.Contains(pages[0], want) {
+			t.Errorf("page missing %s:\n%s", want, pages[0])
+		}
+	}
+	if !strings.Contains(data, "/BaseFont /Helvetica ") || !strings.Contains(data, "/Title <FEFF") {
+		t.Error("missing font or document information")
+	}
+}
+
+func TestFormatHeader(t *testing.T) {
+	source := "# Report\n\nx = 1\n"
+	_, pages := render(t, source, format.Options{Header: "{title}|{page}/{pages}"})
+	if !strings.Contains(pages[0], "54.00 752.00 Td (Report) Tj") {
+		t.Errorf("title from first heading not at the header's left:\n%s", pages[0])
+	}
+	if !strings.Contains(pages[0], "(1/1) Tj") {
+		t.Errorf("page placeholders not replaced:\n%s", pages[0])
+	}
+}
+
+func TestFormatPaginates(t *testing.T) {
+	var b strings.Builder
+	for i := range 120 {
+		fmt.Fprintf(&b, "v%d = %d\n", i, i)
+	}
+	_, pages := render(t, b.String(), format.Options{})
+	if len(pages) < 2 {
+		t.Fatalf("expected several pages, got %d", len(pages))
+	}
+	last := fmt.Sprintf("(Page %d of %d) Tj", len(pages), len(pages))
+	if !strings.Contains(pages[len(pages)-1], last) {
+		t.Errorf("last page missing %s", last)
+	}
+	if !strings.Contains(strings.Join(pages, ""), "(v119 = 119) Tj") {
+		t.Error("last calculation missing")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	spans := []span{{text: "alpha beta ", font: regular}, {text: "gamma", font: bold}, {brk: true}, {text: "delta", font: regular}}
+	lines := wrap(spans, 10, regular.width("alpha beta", 10)+1)
+	want := [][]span{
+		{{text: "alpha beta", font: regular}},
+		{{text: "gamma", font: bold}},
+		{{text: "delta", font: regular}},
+	}
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Errorf("wrap() = %v, want %v", lines, want)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	if got := literal(`a (b) \ é → ✓`); got != "(a \\(b\\) \\\\ \xe9 -> ?)" {
+		t.Errorf("literal() = %q", got)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	if exts := (&Formatter{}).Extensions(); len(exts) != 1 || exts[0] != ".pdf" {
+		t.Errorf("Extensions() = %v", exts)
+	}
+}
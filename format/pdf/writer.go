@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// writer assembles a PDF file from numbered objects. Objects are
+// numbered from 1 in the order they're added.
+type writer struct {
+	objects [][]byte
+}
+
+// add appends an object and returns its number.
+func (w *writer) add(body string) int {
+	w.objects = append(w.objects, []byte(body))
+	return len(w.objects)
+}
+
+// reserve adds a placeholder object to be filled with set, for objects
+// that refer to objects added after them.
+func (w *writer) reserve() int {
+	return w.add("")
+}
+
+func (w *writer) set(n int, body string) {
+	w.objects[n-1] = []byte(body)
+}
+
+// addStream adds a Flate-compressed stream object.
+func (w *writer) addStream(data string) int {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	_, _ = zw.Write([]byte(data))
+	_ = zw.Close()
+
+	var obj bytes.Buffer
+	fmt.Fprintf(&obj, "<< /Length %d /Filter /FlateDecode >>\nstream\n", z.Len())
+	obj.Write(z.Bytes())
+	obj.WriteString("\nendstream")
+	w.objects = append(w.objects, obj.Bytes())
+	return len(w.objects)
+}
+
+// writeTo writes the file: header, objects, cross-reference table, and a
+// trailer naming the catalog and info dictionary.
+func (w *writer) writeTo(out io.Writer, root, info int) error {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n") // Binary marker for transfer tools
+
+	offsets := make([]int, len(w.objects))
+	for i, obj := range w.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(obj)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.objects)+1, root, info, xref)
+
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+// textString returns s as a UTF-16 hex string, for document information
+// that may hold any characters.
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package format
+
+import (
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// provenanceDateFormat is the date stamp appended to provenance comments.
+const provenanceDateFormat = "2006-01-02"
+
+// provenanceTracker builds "# = ..." provenance comments that show each
+// result with the values of the variables it was computed from:
+//
+//	cost = base * rate
+//	# = base(1200) * rate(0.3) → 360 @ 2026-03-01
+//
+// Blocks must be visited in document order so each comment reflects the
+// variable values in effect at that line. When the file is read back the
+// comments stay in their calculation's block, which skips them, and saving
+// drops them, so exporting again regenerates rather than repeats them.
+type provenanceTracker struct {
+	vars  map[string]types.Type
+	stamp string
+}
+
+// newProvenanceTracker starts tracking from the document's frontmatter globals.
+func newProvenanceTracker(doc *document.Document, at time.Time) *provenanceTracker {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	env := interpreter.NewEnvironment()
+	_ = doc.ApplyFrontmatter(env) // Evaluation already reported frontmatter errors
+	return &provenanceTracker{
+		vars:  maps.Clone(env.GetAllVariables()),
+		stamp: at.Format(provenanceDateFormat),
+	}
+}
+
+// blockComments returns provenance comments for a calc block, keyed by the
+// index of the source line each follows. Returns nil for blocks that failed
+// or whose lines cannot be matched one-to-one with statements.
+func (p *provenanceTracker) blockComments(block *document.CalcBlock) map[int]string {
+	results := block.Results()
+	statements := block.Statements()
+	if block.Error() != nil || len(results) != len(statements) {
+		return nil
+	}
+
+	var lines []int
+	for i, line := range block.Source() {
+		if strings.TrimSpace(line) != "" && !isResultLine(line) {
+			lines = append(lines, i)
+		}
+	}
+	if len(lines) != len(results) {
+		return nil
+	}
+
+	source := block.Source()
+	comments := make(map[int]string, len(lines))
+	for i, lineIdx := range lines {
+		if results[i] != nil {
+			comments[lineIdx] = p.comment(source[lineIdx], results[i])
+		}
+		if assign, ok := statements[i].(*ast.Assignment); ok {
+			p.vars[assign.Name] = results[i]
+		}
+	}
+	return comments
+}
+
+// comment renders the provenance comment for one statement line.
+func (p *provenanceTracker) comment(line string, result types.Type) string {
+	value := result.String()
+	if trace := p.trace(line); trace != "" {
+		return "# = " + trace + " → " + value + " @ " + p.stamp
+	}
+	return "# = " + value + " @ " + p.stamp
+}
+
+// trace returns the right-hand side of line with each known variable
+// annotated with its value, e.g. "base(1200) * rate(0.3)". Returns "" when
+// the line references no known variables.
+func (p *provenanceTracker) trace(line string) string {
+	trimmed := strings.TrimSpace(line)
+	tokens, err := lexer.NewLexer(trimmed).Tokenize()
+	if err != nil {
+		return ""
+	}
+
+	// Skip "name =" so only the expression is traced
+	start := 0
+	if len(tokens) >= 2 && tokens[0].Type == lexer.IDENTIFIER && tokens[1].Type == lexer.ASSIGN {
+		start = 2
+	}
+	if start >= len(tokens) {
+		return ""
+	}
+
+	var b strings.Builder
+	pos := tokens[start].StartPos
+	found := false
+	for _, tok := range tokens[start:] {
+		if tok.Type != lexer.IDENTIFIER {
+			continue
+		}
+		value, ok := p.vars[tok.Value]
+		if !ok || value == nil {
+			continue
+		}
+		b.WriteString(trimmed[pos:tok.EndPos])
+		b.WriteString("(" + value.String() + ")")
+		pos = tok.EndPos
+		found = true
+	}
+	if !found {
+		return ""
+	}
+	b.WriteString(trimmed[pos:])
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,126 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+func TestProvenanceComments(t *testing.T) {
+	source := "base = 1200\nrate = 0.3\ncost = base * rate\n\nrate = 0.5\nraised = base * rate\n"
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+	}{
+		{"calcmark", &CalcMarkFormatter{}},
+		{"markdown", &MarkdownFormatter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.NewDocument(source)
+			if err != nil {
+				t.Fatalf("Failed to create document: %v", err)
+			}
+			if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+				t.Fatalf("Failed to evaluate: %v", err)
+			}
+
+			var buf bytes.Buffer
+			opts := Options{Provenance: true, ProvenanceTime: at}
+			if err := tt.formatter.Format(&buf, doc, opts); err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			output := buf.String()
+
+			for _, want := range []string{
+				"base = 1200\n# = 1200 @ 2026-03-01\n",
+				"cost = base * rate\n# = base(1200) * rate(0.3) → 360 @ 2026-03-01\n",
+				// Values in effect at each line, not the final values
+				"raised = base * rate\n# = base(1200) * rate(0.5) → 600 @ 2026-03-01\n",
+			} {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestProvenanceRoundTrip(t *testing.T) {
+	source := "base = 1200\ncost = base * 2\n"
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+		t.Fatalf("Failed to evaluate: %v", err)
+	}
+
+	var baked bytes.Buffer
+	if err := (&CalcMarkFormatter{}).Format(&baked, doc, Options{Provenance: true}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	// Reloading and saving without provenance drops the comments
+	reloaded, err := document.NewDocument(baked.String())
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	var plain bytes.Buffer
+	if err := (&CalcMarkFormatter{}).Format(&plain, reloaded, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if strings.Contains(plain.String(), "# =") {
+		t.Errorf("Expected provenance comments to be stripped, got:\n%s", plain.String())
+	}
+}
+
+// TestProvenanceReexport checks that reloading a --provenance export keeps
+// each calculation block whole, and that exporting it again regenerates
+// the comments rather than repeating them.
+func TestProvenanceReexport(t *testing.T) {
+	source := "# Costs\n\nbase = 1200\nrate = 0.3\ncost = base * rate\n\n\nraised = cost * 2\n"
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	export := func(doc *document.Document) string {
+		t.Helper()
+		if err := implDoc.NewEvaluator().Evaluate(doc); err != nil {
+			t.Fatalf("Failed to evaluate: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := (&CalcMarkFormatter{}).Format(&buf, doc, Options{Provenance: true, ProvenanceTime: at}); err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	first := export(doc)
+
+	reloaded, err := document.NewDocument(first)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if got, want := len(reloaded.GetBlocks()), len(doc.GetBlocks()); got != want {
+		t.Fatalf("Reloaded export has %d blocks, want %d:\n%s", got, want, first)
+	}
+	for i, node := range reloaded.GetBlocks() {
+		if got, want := node.Block.Type(), doc.GetBlocks()[i].Block.Type(); got != want {
+			t.Errorf("Block %d is %s, want %s", i, got, want)
+		}
+	}
+
+	if second := export(reloaded); second != first {
+		t.Errorf("Re-export differs:\n%s\nwant:\n%s", second, first)
+	}
+}
@@ -13,6 +13,11 @@ var formatters = map[string]Formatter{
 	"json": &JSONFormatter{},
 	"html": &HTMLFormatter{},
 	"md":   &MarkdownFormatter{},
+	"csv":  &CSVFormatter{},
+
+	// Email/Slack summaries of the document's exported variables
+	"report":      &ReportFormatter{},
+	"report-text": &ReportFormatter{PlainText: true},
 }
 
 // GetFormatter returns the appropriate formatter based on format name or filename extension.
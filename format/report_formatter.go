@@ -0,0 +1,178 @@
+package format
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+//go:embed templates/report.html
+var reportHTMLTemplate string
+
+// defaultReportTitle is used when the document has no Markdown heading.
+const defaultReportTitle = "CalcMark Report"
+
+// ReportFormatter renders a compact summary of a document's headline
+// variables for posting to email or Slack. The HTML output is a fragment
+// with inline styles only (mail clients strip <style> blocks); PlainText
+// renders the matching text/plain alternative.
+//
+// Headline variables are the frontmatter `exports:` list, in that order.
+// Without exports, every assigned variable is listed in document order.
+type ReportFormatter struct {
+	PlainText bool
+}
+
+// Extensions returns nil: reports are only selected explicitly by name.
+func (f *ReportFormatter) Extensions() []string {
+	return nil
+}
+
+// ReportRow is one headline variable in a report.
+type ReportRow struct {
+	Name  string
+	Value string
+}
+
+// Format writes the report to the writer.
+func (f *ReportFormatter) Format(w io.Writer, doc *document.Document, opts Options) error {
+	data := struct {
+		Title  string
+		Rows   []ReportRow
+		Errors string
+	}{
+		Title: reportTitle(doc),
+		Rows:  reportRows(doc),
+	}
+	if opts.IncludeErrors {
+		data.Errors = reportErrors(doc)
+	}
+
+	if f.PlainText {
+		fmt.Fprintln(w, data.Title)
+		fmt.Fprintln(w, strings.Repeat("=", len([]rune(data.Title))))
+
+		width := 0
+		for _, row := range data.Rows {
+			width = max(width, len([]rune(row.Name)))
+		}
+		for _, row := range data.Rows {
+			fmt.Fprintf(w, "%-*s  %s\n", width, row.Name, row.Value)
+		}
+		if data.Errors != "" {
+			fmt.Fprintf(w, "\n%s\n", data.Errors)
+		}
+		return nil
+	}
+
+	templateContent := reportHTMLTemplate
+	if opts.Template != "" {
+		templateContent = opts.Template
+	}
+	tmpl, err := template.New("report").Parse(templateContent)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// reportTitle returns the document's title, or a default for untitled
+// documents.
+func reportTitle(doc *document.Document) string {
+	if title := DocumentTitle(doc); title != "" {
+		return title
+	}
+	return defaultReportTitle
+}
+
+// DocumentTitle returns the frontmatter title, or else the text of the
+// document's first Markdown heading. It returns "" for untitled documents.
+func DocumentTitle(doc *document.Document) string {
+	if fm := doc.GetFrontmatter(); fm != nil && fm.Title != "" {
+		return fm.Title
+	}
+	for _, node := range doc.GetBlocks() {
+		block, ok := node.Block.(*document.TextBlock)
+		if !ok {
+			continue
+		}
+		for _, line := range block.Source() {
+			trimmed := strings.TrimSpace(line)
+			if !strings.HasPrefix(trimmed, "#") || isResultLine(trimmed) {
+				continue
+			}
+			if title := strings.TrimSpace(strings.TrimLeft(trimmed, "#")); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+// reportRows returns the headline variables with their final values.
+// Exports may name frontmatter globals; names that were never assigned show as "—".
+func reportRows(doc *document.Document) []ReportRow {
+	env := interpreter.NewEnvironment()
+	_ = doc.ApplyFrontmatter(env) // Evaluation already reported frontmatter errors
+	values := maps.Clone(env.GetAllVariables())
+	var order []string
+	for _, node := range doc.GetBlocks() {
+		block, ok := node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		results := block.Results()
+		for i, stmt := range block.Statements() {
+			assign, ok := stmt.(*ast.Assignment)
+			if !ok || i >= len(results) {
+				continue
+			}
+			if !slices.Contains(order, assign.Name) {
+				order = append(order, assign.Name)
+			}
+			values[assign.Name] = results[i]
+		}
+	}
+
+	if fm := doc.GetFrontmatter(); fm != nil && len(fm.Exports) > 0 {
+		order = fm.Exports
+	}
+
+	displayOpts := DisplayOptions(doc)
+	rows := make([]ReportRow, 0, len(order))
+	for _, name := range order {
+		row := ReportRow{Name: name, Value: "—"}
+		if value := values[name]; value != nil {
+			row.Value = display.FormatWith(value, displayOpts)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// reportErrors summarizes failed calculation blocks, or "" if none failed.
+func reportErrors(doc *document.Document) string {
+	failed := 0
+	for _, node := range doc.GetBlocks() {
+		if block, ok := node.Block.(*document.CalcBlock); ok && block.Error() != nil {
+			failed++
+		}
+	}
+	switch failed {
+	case 0:
+		return ""
+	case 1:
+		return "1 calculation failed; values may be incomplete."
+	default:
+		return fmt.Sprintf("%d calculations failed; values may be incomplete.", failed)
+	}
+}
@@ -0,0 +1,113 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+const reportSource = `---
+globals:
+  budget: $50000
+exports:
+  - projected_burn
+  - budget
+  - not_defined
+---
+# Q3 <Budget>
+
+monthly = $4000
+projected_burn = monthly * 3
+`
+
+func evaluateReportDoc(t *testing.T, source string) *document.Document {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	implDoc.NewEvaluator().Evaluate(doc) // Errors are part of the report
+	return doc
+}
+
+// TestReportFormatterHTML tests the inline-styled HTML fragment
+func TestReportFormatterHTML(t *testing.T) {
+	doc := evaluateReportDoc(t, reportSource)
+
+	var buf bytes.Buffer
+	if err := (&ReportFormatter{}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Q3 &lt;Budget&gt;", "projected_burn", "$12K", "budget", "$50K", "—"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected report to contain %q, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "<style") || strings.Contains(output, "<html") {
+		t.Errorf("Expected an inline-styled fragment, got: %s", output)
+	}
+	if strings.Contains(output, "monthly") {
+		t.Errorf("Expected only exported variables, got: %s", output)
+	}
+}
+
+// TestReportFormatterPlainText tests the aligned plain-text alternative
+func TestReportFormatterPlainText(t *testing.T) {
+	doc := evaluateReportDoc(t, reportSource)
+
+	var buf bytes.Buffer
+	if err := (&ReportFormatter{PlainText: true}).Format(&buf, doc, Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	want := `Q3 <Budget>
+===========
+projected_burn  $12K
+budget          $50K
+not_defined     —
+`
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, buf.String())
+	}
+}
+
+// TestReportFormatterWithoutExports tests the fallback to all variables
+func TestReportFormatterWithoutExports(t *testing.T) {
+	doc := evaluateReportDoc(t, "a = 1\nb = a + 1\na = 5\n\nSome notes.\n\nc = missing + 1\n")
+
+	var buf bytes.Buffer
+	if err := (&ReportFormatter{PlainText: true}).Format(&buf, doc, Options{IncludeErrors: true}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.HasPrefix(output, defaultReportTitle+"\n") {
+		t.Errorf("Expected default title, got: %s", output)
+	}
+	if !strings.Contains(output, "a  5\nb  2\n") {
+		t.Errorf("Expected variables in document order with final values, got: %s", output)
+	}
+	if !strings.Contains(output, "1 calculation failed") {
+		t.Errorf("Expected error summary, got: %s", output)
+	}
+}
+
+// TestDocumentTitle tests the frontmatter title taking precedence over
+// the first heading
+func TestDocumentTitle(t *testing.T) {
+	if got := DocumentTitle(evaluateReportDoc(t, reportSource)); got != "Q3 <Budget>" {
+		t.Errorf("Expected first heading, got %q", got)
+	}
+	doc := evaluateReportDoc(t, "---\ntitle: Forecast\n---\n# Q3\n\nx = 1\n")
+	if got := DocumentTitle(doc); got != "Forecast" {
+		t.Errorf("Expected frontmatter title, got %q", got)
+	}
+	if got := DocumentTitle(evaluateReportDoc(t, "x = 1\n")); got != "" {
+		t.Errorf("Expected no title, got %q", got)
+	}
+}
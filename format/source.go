@@ -0,0 +1,311 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/mattn/go-runewidth"
+)
+
+// Source returns CalcMark source in canonical form:
+//   - one space around binary operators and after commas, none inside
+//     brackets or after a unary sign; "/" and "^" written without spaces
+//     (as in "MB/s") stay that way
+//   - integer parts of five or more digits grouped in threes with the
+//     document locale's separator, shorter ones ungrouped ("1000", "12,000")
+//   - the "=" of assignments aligned within each calculation block
+//   - no trailing whitespace on calculation lines, "\n" line endings, and
+//     no byte order mark (see document.NormalizeText)
+//
+// Frontmatter and markdown text are preserved as written. A line is only
+// rewritten when it lexes to the same tokens as before, so formatting never
+// changes what a document computes, and Source(Source(s)) == Source(s).
+//
+// Source returns an error for documents that don't load, such as invalid
+// frontmatter or a calculation the lexer rejects.
+func Source(src string) (string, error) {
+	src = document.NormalizeText(src)
+	fm, body, err := document.ParseFrontmatter(src)
+	if err != nil {
+		return "", fmt.Errorf("frontmatter: %w", err)
+	}
+	header := src[:len(src)-len(body)]
+
+	loc := fm.NumberLocale()
+	detector := document.NewDetectorWithLocale(loc)
+
+	lines := strings.Split(body, "\n")
+	out := make([]string, len(lines))
+	var block []formattedLine // Calculation lines of the current block
+	blanks := 0
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			out[i] = line
+			// Two empty lines end a block, as in block detection
+			if blanks++; blanks >= 2 {
+				alignAssignments(block, out)
+				block = nil
+			}
+			continue
+		}
+		blanks = 0
+
+		isCalc, err := detector.IsCalculation(line)
+		if err != nil {
+			return "", fmt.Errorf("line %d: %w", strings.Count(header, "\n")+i+1, err)
+		}
+		if !isCalc {
+			out[i] = line
+			alignAssignments(block, out)
+			block = nil
+			continue
+		}
+
+		fl := formatLine(line, loc)
+		fl.index = i
+		out[i] = fl.text
+		block = append(block, fl)
+	}
+	alignAssignments(block, out)
+
+	return header + strings.Join(out, "\n"), nil
+}
+
+// formattedLine is a calculation line in canonical spacing.
+type formattedLine struct {
+	index int    // Line index in the body
+	text  string // Formatted line
+	name  string // Indentation and variable, for assignments
+	rest  string // Expression after "=", for assignments
+}
+
+// alignAssignments pads the variables of a block's assignments so their
+// "=" signs line up, writing the results into out.
+func alignAssignments(block []formattedLine, out []string) {
+	width := 0
+	for _, fl := range block {
+		if fl.name != "" {
+			width = max(width, runewidth.StringWidth(fl.name))
+		}
+	}
+	for _, fl := range block {
+		if fl.name != "" {
+			padding := strings.Repeat(" ", width-runewidth.StringWidth(fl.name))
+			out[fl.index] = fl.name + padding + " = " + fl.rest
+		}
+	}
+}
+
+// formatLine respaces one calculation line. The line is returned with only
+// trailing whitespace removed if the canonical form would lex differently
+// or the tokens don't account for all of its text.
+func formatLine(line string, loc lexer.NumberLocale) formattedLine {
+	unchanged := formattedLine{text: strings.TrimRightFunc(line, unicode.IsSpace)}
+
+	tokens, err := lexer.NewLexerWithLocale(line, loc).Tokenize()
+	if err != nil {
+		return unchanged
+	}
+	tokens = significantTokens(tokens)
+	if len(tokens) == 0 {
+		return unchanged
+	}
+
+	runes := []rune(line)
+	indent := string(runes[:tokens[0].StartPos])
+	if strings.TrimSpace(indent) != "" {
+		return unchanged
+	}
+
+	texts := make([]string, len(tokens))
+	var covered strings.Builder
+	for i, tok := range tokens {
+		if tok.StartPos < 0 || tok.EndPos > len(runes) || tok.StartPos > tok.EndPos {
+			return unchanged
+		}
+		// Some literals, such as durations, end after trailing spaces
+		texts[i] = strings.TrimSpace(string(runes[tok.StartPos:tok.EndPos]))
+		covered.WriteString(texts[i])
+		if hasNumber(tok.Type) {
+			texts[i] = regroup(texts[i], loc)
+		}
+	}
+	if withoutSpaces(covered.String()) != withoutSpaces(line) {
+		return unchanged // Text the lexer skipped, which respacing would lose
+	}
+
+	var b strings.Builder
+	b.WriteString(indent)
+	for i := range tokens {
+		if i > 0 {
+			b.WriteString(separator(tokens, i))
+		}
+		b.WriteString(texts[i])
+	}
+	text := b.String()
+
+	relexed, err := lexer.NewLexerWithLocale(text, loc).Tokenize()
+	if err != nil || !sameTokens(tokens, significantTokens(relexed)) {
+		return unchanged
+	}
+
+	fl := formattedLine{text: text}
+	if len(tokens) > 2 && tokens[0].Type == lexer.IDENTIFIER && tokens[1].Type == lexer.ASSIGN {
+		fl.name = indent + texts[0]
+		fl.rest = strings.TrimPrefix(text, fl.name+" = ")
+	}
+	return fl
+}
+
+// separator returns the spacing to write before tokens[i].
+func separator(tokens []lexer.Token, i int) string {
+	prev, cur := tokens[i-1], tokens[i]
+	gap := cur.StartPos > prev.EndPos
+
+	switch {
+	case cur.Type == lexer.RPAREN || cur.Type == lexer.RBRACKET || cur.Type == lexer.COMMA || cur.Type == lexer.DOT:
+		return ""
+	case prev.Type == lexer.LPAREN || prev.Type == lexer.LBRACKET || prev.Type == lexer.AT_PREFIX || prev.Type == lexer.DOT:
+		return ""
+	case prev.Type == lexer.COMMA:
+		return " "
+	case isSign(tokens, i-1):
+		return ""
+	case isTightOperator(tokens, i-1) || isTightOperator(tokens, i):
+		return ""
+	case isBinaryOperator(prev.Type) || isBinaryOperator(cur.Type):
+		return " "
+	case gap:
+		return " "
+	}
+	return ""
+}
+
+// isSign reports whether tokens[i] is a unary plus or minus.
+func isSign(tokens []lexer.Token, i int) bool {
+	t := tokens[i].Type
+	return (t == lexer.MINUS || t == lexer.PLUS) && (i == 0 || !endsOperand(tokens[i-1].Type))
+}
+
+// isTightOperator reports whether tokens[i] is a "/" or "^" written with
+// no space on either side, as in "MB/s" or "2^10".
+func isTightOperator(tokens []lexer.Token, i int) bool {
+	t := tokens[i].Type
+	if (t != lexer.DIVIDE && t != lexer.EXPONENT) || i == 0 || i == len(tokens)-1 {
+		return false
+	}
+	return tokens[i-1].EndPos == tokens[i].StartPos && tokens[i].EndPos == tokens[i+1].StartPos
+}
+
+func isBinaryOperator(t lexer.TokenType) bool {
+	switch t {
+	case lexer.PLUS, lexer.MINUS, lexer.MULTIPLY, lexer.DIVIDE, lexer.MODULUS, lexer.EXPONENT, lexer.ASSIGN,
+		lexer.GREATER_THAN, lexer.LESS_THAN, lexer.GREATER_EQUAL, lexer.LESS_EQUAL, lexer.EQUAL, lexer.NOT_EQUAL:
+		return true
+	}
+	return false
+}
+
+// endsOperand reports whether a token of type t can end an operand, so a
+// following "-" is binary rather than a sign.
+func endsOperand(t lexer.TokenType) bool {
+	switch t {
+	case lexer.NUMBER, lexer.QUANTITY, lexer.CURRENCY, lexer.BOOLEAN, lexer.IDENTIFIER, lexer.CURRENCY_CODE,
+		lexer.NUMBER_PERCENT, lexer.NUMBER_K, lexer.NUMBER_M, lexer.NUMBER_B, lexer.NUMBER_T, lexer.NUMBER_SCI,
+		lexer.RPAREN, lexer.RBRACKET, lexer.NAPKIN, lexer.DATE_LITERAL, lexer.DURATION_LITERAL,
+		lexer.DATE_TODAY, lexer.DATE_TOMORROW, lexer.DATE_YESTERDAY,
+		lexer.DATE_THIS_WEEK, lexer.DATE_THIS_MONTH, lexer.DATE_THIS_YEAR,
+		lexer.DATE_NEXT_WEEK, lexer.DATE_NEXT_MONTH, lexer.DATE_NEXT_YEAR,
+		lexer.DATE_LAST_WEEK, lexer.DATE_LAST_MONTH, lexer.DATE_LAST_YEAR:
+		return true
+	}
+	return false
+}
+
+// hasNumber reports whether tokens of type t are written with a number
+// literal that regroup may rewrite.
+func hasNumber(t lexer.TokenType) bool {
+	switch t {
+	case lexer.NUMBER, lexer.QUANTITY, lexer.NUMBER_PERCENT,
+		lexer.NUMBER_K, lexer.NUMBER_M, lexer.NUMBER_B, lexer.NUMBER_T:
+		return true
+	}
+	return false
+}
+
+// regroup rewrites the integer part of the first number in text with
+// canonical digit grouping for loc. Grouping applies from five digits up.
+func regroup(text string, loc lexer.NumberLocale) string {
+	runes := []rune(text)
+	start := 0
+	for start < len(runes) && !unicode.IsDigit(runes[start]) {
+		start++
+	}
+	if start == len(runes) {
+		return text
+	}
+
+	var digits []rune
+	end := start
+	for end < len(runes) {
+		r := runes[end]
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+			end++
+			continue
+		}
+		// A separator only counts when a digit follows
+		if isGroupSeparator(r, loc) && end+1 < len(runes) && unicode.IsDigit(runes[end+1]) {
+			end++
+			continue
+		}
+		break
+	}
+
+	var b strings.Builder
+	b.WriteString(string(runes[:start]))
+	for i, d := range digits {
+		if len(digits) >= 5 && i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteRune(loc.Groups[0])
+		}
+		b.WriteRune(d)
+	}
+	b.WriteString(string(runes[end:]))
+	return b.String()
+}
+
+func isGroupSeparator(r rune, loc lexer.NumberLocale) bool {
+	return r == '_' || strings.ContainsRune(string(loc.Groups), r)
+}
+
+func withoutSpaces(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// significantTokens drops the NEWLINE and EOF tokens.
+func significantTokens(tokens []lexer.Token) []lexer.Token {
+	var out []lexer.Token
+	for _, tok := range tokens {
+		if tok.Type != lexer.NEWLINE && tok.Type != lexer.EOF {
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
+// sameTokens reports whether a and b have the same types and values.
+func sameTokens(a, b []lexer.Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Value != b[i].Value {
+			return false
+		}
+	}
+	return true
+}
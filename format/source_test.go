@@ -0,0 +1,118 @@
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "operator spacing",
+			source: "x=1+2*3\ny = (x-1)/ 2\n",
+			want:   "x = 1 + 2 * 3\ny = (x - 1) / 2\n",
+		},
+		{
+			name:   "signs and calls",
+			source: "a = - 5\nb = avg( 1,2 , -3 )\nc = 3 - -a\n",
+			want:   "a = -5\nb = avg(1, 2, -3)\nc = 3 - -a\n",
+		},
+		{
+			name:   "tight division and exponent kept",
+			source: "bw = 100 MB/s over 1 day\nm = 2^10\n",
+			want:   "bw = 100 MB/s over 1 day\nm  = 2^10\n",
+		},
+		{
+			name:   "thousands separators",
+			source: "a = 1,000\nb = 12000 kg\nc = $1200000.50\nd = 3_000_000\n",
+			want:   "a = 1000\nb = 12,000 kg\nc = $1,200,000.50\nd = 3,000,000\n",
+		},
+		{
+			name:   "alignment per block",
+			source: "rent = $1500\nfood = 400\ntotal_cost = rent + food\n\n\nx = 1\nlong_name = 2\n",
+			want:   "rent       = $1500\nfood       = 400\ntotal_cost = rent + food\n\n\nx         = 1\nlong_name = 2\n",
+		},
+		{
+			name:   "markdown preserved",
+			source: "# Budget  \n\nSome   *text*  here.\nrent=5\n\n- item  one\n",
+			want:   "# Budget  \n\nSome   *text*  here.\nrent = 5\n\n- item  one\n",
+		},
+		{
+			name:   "frontmatter and locale",
+			source: "---\nlocale: de-DE\nglobals:\n  rate:   \"0.5\"\n---\npreis = 12345,50 * 2\nmenge=1.000\n",
+			want:   "---\nlocale: de-DE\nglobals:\n  rate:   \"0.5\"\n---\npreis = 12.345,50 * 2\nmenge = 1000\n",
+		},
+		{
+			name:   "trailing whitespace and CRLF",
+			source: "x = 1   \r\ny = x \r\n",
+			want:   "x = 1\ny = x\n",
+		},
+		{
+			name:   "byte order mark",
+			source: "\ufeff---\r\nglobals:\r\n  a: 1\r\n---\r\nx=a\r\n",
+			want:   "---\nglobals:\n  a: 1\n---\nx = a\n",
+		},
+		{
+			name:   "keywords and dates",
+			source: "d = Dec 12 2025 + 2 days\np = 20% of 50000\ns = 1200 meters in feet\n",
+			want:   "d = Dec 12 2025 + 2 days\np = 20% of 50,000\ns = 1200 meters in feet\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Source(tt.source)
+			if err != nil {
+				t.Fatalf("Source failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Source() =\n%q\nwant\n%q", got, tt.want)
+			}
+			again, err := Source(got)
+			if err != nil {
+				t.Fatalf("Source of formatted output failed: %v", err)
+			}
+			if again != got {
+				t.Errorf("not idempotent:\n%q\nthen\n%q", got, again)
+			}
+		})
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	if _, err := Source("---\nunknown: 1\n---\nx = 1\n"); err == nil {
+		t.Error("expected error for invalid frontmatter")
+	}
+}
+
+// TestSourceIdempotentOnTestdata formats every sample document twice.
+func TestSourceIdempotentOnTestdata(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "testdata", "*.cm"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no testdata documents: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		once, err := Source(string(data))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		twice, err := Source(once)
+		if err != nil {
+			t.Errorf("%s: second pass: %v", file, err)
+			continue
+		}
+		if once != twice {
+			t.Errorf("%s: formatting is not idempotent", file)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// ResultTable is a table a calculation line evaluated to, such as an
+// amortize() schedule, with its cells formatted for display. Exports
+// render these in full after their block; the line's own result only
+// summarizes them.
+type ResultTable struct {
+	Name    string // Variable the line assigns, "" if none
+	Columns []string
+	Rows    [][]string
+}
+
+// resultTables returns the tables a block's lines evaluated to, in order.
+func resultTables(block *document.CalcBlock, opts display.Options) []ResultTable {
+	var tables []ResultTable
+	statements := block.Statements()
+	for i, result := range block.Results() {
+		t, ok := result.(*types.Table)
+		if !ok {
+			continue
+		}
+		rt := ResultTable{Columns: t.Columns}
+		if i < len(statements) {
+			if assign, ok := statements[i].(*ast.Assignment); ok {
+				rt.Name = assign.Name
+			}
+		}
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			for j, v := range row {
+				cells[j] = display.FormatWith(v, opts)
+			}
+			rt.Rows = append(rt.Rows, cells)
+		}
+		tables = append(tables, rt)
+	}
+	return tables
+}
+
+// writeMarkdownTable writes t as a Markdown table under its variable's
+// name in bold, its columns right-aligned as figures are.
+func writeMarkdownTable(w io.Writer, t ResultTable) {
+	if t.Name != "" {
+		fmt.Fprintf(w, "**%s**\n\n", t.Name)
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(t.Columns, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" ---: |", len(t.Columns)))
+	for _, row := range t.Rows {
+		fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+	}
+	fmt.Fprintln(w)
+}
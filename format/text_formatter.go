@@ -22,6 +22,7 @@ func (f *TextFormatter) Extensions() []string {
 // All output uses the centralized Type.String() methods for display.
 func (f *TextFormatter) Format(w io.Writer, doc *document.Document, opts Options) error {
 	blocks := doc.GetBlocks()
+	displayOpts := DisplayOptions(doc)
 
 	for i, node := range blocks {
 		switch block := node.Block.(type) {
@@ -38,7 +39,7 @@ func (f *TextFormatter) Format(w io.Writer, doc *document.Document, opts Options
 					fmt.Fprint(w, line)
 					// Add result if available for this line
 					if j < len(results) && results[j] != nil {
-						fmt.Fprintf(w, " → %s", display.Format(results[j]))
+						fmt.Fprintf(w, " → %s", display.FormatWith(results[j], displayOpts))
 					}
 					fmt.Fprintln(w)
 				}
@@ -47,7 +48,7 @@ func (f *TextFormatter) Format(w io.Writer, doc *document.Document, opts Options
 				if block.Error() != nil {
 					fmt.Fprintf(w, "Error: %v\n", block.Error())
 				} else if block.LastValue() != nil {
-					fmt.Fprintln(w, display.Format(block.LastValue()))
+					fmt.Fprintln(w, display.FormatWith(block.LastValue(), displayOpts))
 				}
 			}
 
@@ -0,0 +1,119 @@
+package format
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// VariableRow is one variable assignment in tabular exports.
+type VariableRow struct {
+	Name       string
+	Expression string // Right-hand side as written, or the frontmatter value for globals
+	Value      string // Display form, e.g. "$1,200.00"
+	Raw        string // Unformatted number, e.g. "1200"; ISO date or time; "" for errors
+	Unit       string // Unit, currency code, or "unit/period" for rates
+	Type       string // Value type, as in calc-type-<kind> HTML classes
+}
+
+// VariableRows returns the document's frontmatter globals, sorted by name,
+// followed by every variable assignment in document order. Assignments in
+// failed blocks have no Value.
+func VariableRows(doc *document.Document) []VariableRow {
+	var rows []VariableRow
+	displayOpts := DisplayOptions(doc)
+
+	if fm := doc.GetFrontmatter(); fm != nil && len(fm.Globals) > 0 {
+		env := interpreter.NewEnvironment()
+		_ = doc.ApplyFrontmatter(env) // Evaluation already reported frontmatter errors
+		values := env.GetAllVariables()
+		for _, name := range slices.Sorted(maps.Keys(fm.Globals)) {
+			rows = append(rows, variableRow(name, fm.Globals[name], values[name], displayOpts))
+		}
+	}
+
+	for _, node := range doc.GetBlocks() {
+		block, ok := node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		results := block.Results()
+		lines := statementLines(block)
+		for i, stmt := range block.Statements() {
+			assign, ok := stmt.(*ast.Assignment)
+			if !ok {
+				continue
+			}
+			expr := assign.Value.String()
+			if len(lines) == len(block.Statements()) {
+				if _, rhs, found := strings.Cut(lines[i], "="); found {
+					expr = strings.TrimSpace(rhs)
+				}
+			}
+			var value types.Type
+			if i < len(results) {
+				value = results[i]
+			}
+			rows = append(rows, variableRow(assign.Name, expr, value, displayOpts))
+		}
+	}
+	return rows
+}
+
+// statementLines returns a block's non-empty source lines, which match its
+// statements one-to-one for blocks without multi-line statements.
+func statementLines(block *document.CalcBlock) []string {
+	var lines []string
+	for _, line := range block.Source() {
+		if strings.TrimSpace(line) != "" && !isResultLine(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// ValueRow describes a single value, with no name or expression: its
+// display form, raw value, unit, and type as VariableRows reports them.
+func ValueRow(value types.Type, displayOpts display.Options) VariableRow {
+	return variableRow("", "", value, displayOpts)
+}
+
+func variableRow(name, expr string, value types.Type, displayOpts display.Options) VariableRow {
+	row := VariableRow{Name: name, Expression: expr}
+	if value == nil {
+		return row
+	}
+	row.Value = display.FormatWith(value, displayOpts)
+
+	v := value.ToValue()
+	row.Type = string(v.Kind)
+	row.Unit = v.Unit
+	switch {
+	case v.Date != nil:
+		row.Raw = v.Date.String()
+	case v.Time != nil:
+		row.Raw = v.Time.String()
+	case v.Decimal != "":
+		row.Raw = v.Decimal
+	case v.Kind != types.KindDistribution: // An empty one has no mean
+		row.Raw = v.Display
+	}
+	if v.CurrencyCode != "" {
+		row.Unit = v.CurrencyCode
+	}
+	if v.PerUnit != "" {
+		row.Unit += "/" + v.PerUnit
+	}
+	return row
+}
+
+// valueKind names a result's type for calc-type-<kind> classes.
+func valueKind(v types.Type) string {
+	return string(v.ToValue().Kind)
+}
@@ -0,0 +1,117 @@
+// Package xlsx exports a CalcMark document's variables as an Excel
+// workbook: one sheet with the columns of the CSV export (see
+// format.VariableRows), raw values written as numbers where they are.
+//
+// It is a separate package so the format package stays free of binary
+// output; register it to make it available by name:
+//
+//	format.RegisterFormatter("xlsx", &xlsx.Formatter{})
+//
+// The workbook is written directly as Office Open XML, without a
+// spreadsheet library.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/shopspring/decimal"
+)
+
+// Formatter writes .xlsx workbooks. It implements format.Formatter.
+type Formatter struct{}
+
+// header names the sheet's columns, as in the CSV export.
+var header = []string{"name", "expression", "value", "raw_value", "unit", "type"}
+
+// Extensions returns the file extensions handled by this formatter.
+func (f *Formatter) Extensions() []string {
+	return []string{".xlsx"}
+}
+
+// Format writes the document's variables as a workbook to the writer.
+func (f *Formatter) Format(w io.Writer, doc *document.Document, opts format.Options) error {
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/worksheets/sheet1.xml", sheet(format.VariableRows(doc))},
+	} {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// sheet renders the worksheet XML for rows, under a header row.
+func sheet(rows []format.VariableRow) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	writeRow(&b, 1, header, -1)
+	for i, row := range rows {
+		raw := -1
+		if isNumber(row.Raw) {
+			raw = 3 // raw_value column
+		}
+		writeRow(&b, i+2, []string{row.Name, row.Expression, row.Value, row.Raw, row.Unit, row.Type}, raw)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// writeRow writes one row of cells; the cell at index number, if any, is
+// written as a number and the others as inline strings.
+func writeRow(b *strings.Builder, n int, cells []string, number int) {
+	fmt.Fprintf(b, `<row r="%d">`, n)
+	for i, cell := range cells {
+		if cell == "" {
+			continue
+		}
+		ref := fmt.Sprintf("%c%d", 'A'+i, n)
+		if i == number {
+			fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, cell)
+			continue
+		}
+		fmt.Fprintf(b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		_ = xml.EscapeText(b, []byte(cell))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+}
+
+func isNumber(s string) bool {
+	_, err := decimal.NewFromString(s)
+	return s != "" && err == nil
+}
+
+const contentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Variables" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const workbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
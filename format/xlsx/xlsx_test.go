@@ -0,0 +1,66 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+func TestFormat(t *testing.T) {
+	doc, err := document.NewDocument("rent = $1200\nlabel = 10 < 20\n")
+	if err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	_ = implDoc.NewEvaluator().Evaluate(doc)
+
+	var buf bytes.Buffer
+	if err := (&Formatter{}).Format(&buf, doc, format.Options{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Output is not a zip archive: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">rent</t></is></c>`,
+		`<c r="D2"><v>1200</v></c>`,
+		`<c r="E2" t="inlineStr"><is><t xml:space="preserve">USD</t></is></c>`,
+		`<t xml:space="preserve">10 &lt; 20</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet missing %s:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	if exts := (&Formatter{}).Extensions(); len(exts) != 1 || exts[0] != ".xlsx" {
+		t.Errorf("Extensions() = %v", exts)
+	}
+}
@@ -0,0 +1,141 @@
+package document
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+)
+
+// CheckSource evaluates a document's content and returns its problems,
+// with ranges in file lines (frontmatter included), and the number of
+// blocks evaluated. A file that doesn't load at all has a single error on
+// its first line.
+func CheckSource(content string, eval *Evaluator) ([]semantic.Diagnostic, int) {
+	doc, err := document.NewDocument(content)
+	if err != nil {
+		return []semantic.Diagnostic{{Severity: semantic.Error, Message: err.Error(), Range: lineRange(1, 0)}}, 0
+	}
+	return CheckDocument(doc, content, eval), len(doc.GetBlocks())
+}
+
+// CheckDocument is CheckSource for a document already parsed from
+// content, as when its frontmatter is overridden first.
+func CheckDocument(doc *document.Document, content string, eval *Evaluator) []semantic.Diagnostic {
+	_ = eval.Evaluate(doc) // Errors are kept on the blocks
+
+	// Blocks cover the lines after the frontmatter, in order
+	bodyLines := 0
+	for _, node := range doc.GetBlocks() {
+		bodyLines += len(node.Block.Source())
+	}
+	return blockDiagnostics(doc, eval, max(0, len(strings.Split(document.NormalizeText(content), "\n"))-bodyLines))
+}
+
+// DocumentDiagnostics returns the problems eval found when it last
+// evaluated doc, with ranges in the lines of doc's blocks (frontmatter
+// excluded), counting from 1.
+func DocumentDiagnostics(doc *document.Document, eval *Evaluator) []semantic.Diagnostic {
+	return blockDiagnostics(doc, eval, 0)
+}
+
+// blockDiagnostics returns the problems on doc's blocks and eval's, with
+// the blocks' lines following start, in order of line and column.
+func blockDiagnostics(doc *document.Document, eval *Evaluator, start int) []semantic.Diagnostic {
+	starts := make(map[string]int)
+
+	var diags []semantic.Diagnostic
+	for _, node := range doc.GetBlocks() {
+		starts[node.ID] = start
+		if cb, ok := node.Block.(*document.CalcBlock); ok {
+			for _, d := range cb.Diagnostics() {
+				diags = append(diags, semantic.Diagnostic{
+					Severity:    severityOf(d.Severity),
+					Code:        d.Code,
+					Message:     d.Message,
+					Suggestions: d.Suggestions,
+					Range:       lineRange(start+blockLine(node.Block, d.Line), d.Column),
+				})
+			}
+			if err := cb.Error(); err != nil && len(cb.Diagnostics()) == 0 {
+				line, column := 0, 0
+				var se *interpreter.StatementError
+				if errors.As(err, &se) && se.Range != nil {
+					line, column = se.Range.Start.Line, se.Range.Start.Column
+				}
+				diags = append(diags, semantic.Diagnostic{
+					Severity: semantic.Error,
+					Message:  err.Error(),
+					Range:    lineRange(start+blockLine(node.Block, line), column),
+				})
+			}
+		}
+		start += len(node.Block.Source())
+	}
+
+	for _, d := range eval.Diagnostics() {
+		node, _ := doc.GetBlock(d.BlockID)
+		line, column := 1, 0
+		if node != nil {
+			line = starts[d.BlockID] + blockLine(node.Block, d.Line)
+			if line-starts[d.BlockID] == d.Line {
+				column = d.Column
+			}
+		}
+		severity := semantic.Warning
+		switch d.Severity {
+		case Error:
+			severity = semantic.Error
+		case Hint:
+			severity = semantic.Hint
+		}
+		diags = append(diags, semantic.Diagnostic{Severity: severity, Code: d.Code, Message: d.Message, Range: lineRange(line, column)})
+	}
+
+	slices.SortStableFunc(diags, func(a, b semantic.Diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.Range.Start.Line, b.Range.Start.Line),
+			cmp.Compare(a.Range.Start.Column, b.Range.Start.Column),
+		)
+	})
+	return diags
+}
+
+// blockLine returns the 1-indexed line of block a diagnostic on line
+// (1-indexed, 0 if unknown) belongs on. Diagnostics without a line, such
+// as document-wide limits, go on the block's first non-empty line.
+func blockLine(block document.Block, line int) int {
+	source := block.Source()
+	if line > 0 && line <= len(source) {
+		return line
+	}
+	for i, l := range source {
+		if strings.TrimSpace(l) != "" {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// lineRange returns a range starting at line and column (0 for the
+// whole line).
+func lineRange(line, column int) *ast.Range {
+	return &ast.Range{Start: ast.Position{Line: line, Column: column}}
+}
+
+// severityOf maps a document.Diagnostic severity to a semantic one.
+func severityOf(s string) semantic.Severity {
+	switch s {
+	case "warning":
+		return semantic.Warning
+	case "hint", "info":
+		return semantic.Hint
+	default:
+		return semantic.Error
+	}
+}
@@ -0,0 +1,63 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+)
+
+// TestCheckSourcePositions tests that problems are reported at the
+// statements they come from, in order of line and column, whether the
+// checker, the interpreter, or the evaluator found them.
+func TestCheckSourcePositions(t *testing.T) {
+	content := "---\n" +
+		"globals:\n" +
+		"  rate: 2\n" +
+		"---\n" +
+		"# Trip\n" +
+		"\n" +
+		"size = 1 GiB + 5 Mbps\n" +
+		"\n" +
+		"Some text.\n" +
+		"total = rate +\n" +
+		"\n" +
+		"w = 5 kg\n" +
+		"h = 5 kg + 3 meters\n"
+
+	diags, _ := CheckSource(content, NewEvaluator())
+
+	want := []struct {
+		line, column int
+		severity     semantic.Severity
+		message      string
+	}{
+		{7, 8, semantic.Hint, "mixing binary and decimal data units"},
+		{10, 0, semantic.Warning, "line looks like an assignment: parse error at line 1, column 15: unexpected token: NEWLINE"},
+		{13, 5, semantic.Error, "incompatible units"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %+v", len(want), diags)
+	}
+	for i, w := range want {
+		d := diags[i]
+		if d.Range.Start.Line != w.line || d.Range.Start.Column != w.column ||
+			d.Severity != w.severity || d.Message != w.message {
+			t.Errorf("diagnostic %d = %d:%d %s %q, want %d:%d %s %q", i,
+				d.Range.Start.Line, d.Range.Start.Column, d.Severity, d.Message,
+				w.line, w.column, w.severity, w.message)
+		}
+	}
+}
+
+// TestCheckSourceRuntimeError tests that an error evaluating a statement
+// is reported on the statement's line.
+func TestCheckSourceRuntimeError(t *testing.T) {
+	diags, _ := CheckSource("zero = 0\nratio = 5 / zero\n", NewEvaluator())
+
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %+v", diags)
+	}
+	if d := diags[0]; d.Severity != semantic.Error || d.Range.Start.Line != 2 || d.Range.Start.Column != 1 {
+		t.Errorf("expected an error at 2:1, got %+v", d)
+	}
+}
@@ -3,8 +3,10 @@ package document
 import (
 	"strings"
 
+	"github.com/CalcMark/go-calcmark/spec/document"
 	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
 )
 
 // DiagnosticSeverity indicates the severity of a diagnostic.
@@ -15,6 +17,8 @@ const (
 	Warning DiagnosticSeverity = iota
 	// Error indicates a problem that prevents evaluation.
 	Error
+	// Hint indicates a style or readability suggestion.
+	Hint
 )
 
 func (s DiagnosticSeverity) String() string {
@@ -23,6 +27,8 @@ func (s DiagnosticSeverity) String() string {
 		return "warning"
 	case Error:
 		return "error"
+	case Hint:
+		return "hint"
 	default:
 		return "unknown"
 	}
@@ -32,10 +38,29 @@ func (s DiagnosticSeverity) String() string {
 type BlockDiagnostic struct {
 	BlockID  string             // ID of the block containing the issue
 	Line     int                // Line number within block (1-indexed)
-	Severity DiagnosticSeverity // Warning or Error
+	Column   int                // Column within the line (1-indexed), 0 if unknown
+	Severity DiagnosticSeverity // Warning, Error, or Hint
 	Code     string             // Diagnostic code (e.g., "LIKELY_CALCULATION")
 	Message  string             // Human-readable message
 	Source   string             // The problematic line content
+	Fixes    []semantic.Fix     // Edits resolving the issue, in block lines
+}
+
+// FormatDiagnostics converts the evaluator's warnings and hints to the
+// spec diagnostics formatters attach to blocks.
+func FormatDiagnostics(diags []BlockDiagnostic) []document.Diagnostic {
+	out := make([]document.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		out = append(out, document.Diagnostic{
+			BlockID:  d.BlockID,
+			Severity: d.Severity.String(),
+			Code:     d.Code,
+			Message:  d.Message,
+			Line:     d.Line,
+			Column:   d.Column,
+		})
+	}
+	return out
 }
 
 // Diagnostic codes
@@ -43,6 +68,20 @@ const (
 	// DiagLikelyCalculation indicates a line that looks like an assignment
 	// but failed to parse as a calculation.
 	DiagLikelyCalculation = "LIKELY_CALCULATION"
+
+	// DiagBlockTimeout indicates a CalcBlock that ran past the evaluator's
+	// block timeout and is showing its previous results.
+	DiagBlockTimeout = "BLOCK_TIMEOUT"
+
+	// DiagSizeLimit indicates a document that exceeds the evaluator's
+	// size limits (see Limits). It applies to the whole document, so
+	// BlockID is empty.
+	DiagSizeLimit = "SIZE_LIMIT"
+
+	// DiagAmbiguousLocale indicates a literal that a document with
+	// locale: auto may have read wrongly, because literals disagree on
+	// the decimal mark or none shows which it is.
+	DiagAmbiguousLocale = "AMBIGUOUS_LOCALE"
 )
 
 // CalculationIndicator defines a pattern that suggests a line was intended
@@ -83,13 +122,19 @@ var calculationIndicators = []CalculationIndicator{
 // Returns (true, parseError) if it looks like a failed calculation.
 // Returns (false, nil) if it's likely just text.
 func looksLikeFailedCalculation(line string) (bool, error) {
+	return looksLikeFailedCalculationWithLocale(line, lexer.LocaleUS)
+}
+
+// looksLikeFailedCalculationWithLocale is looksLikeFailedCalculation for a
+// document whose numbers are written in loc's style.
+func looksLikeFailedCalculationWithLocale(line string, loc lexer.NumberLocale) (bool, error) {
 	trimmed := strings.TrimSpace(line)
 	if trimmed == "" {
 		return false, nil
 	}
 
 	// Try to tokenize the line
-	lex := lexer.NewLexer(trimmed)
+	lex := lexer.NewLexerWithLocale(trimmed, loc)
 	tokens, lexErr := lex.Tokenize()
 	if lexErr != nil {
 		// Lexer failed - could be markdown with special chars
@@ -117,7 +162,7 @@ func looksLikeFailedCalculation(line string) (bool, error) {
 	}
 
 	// Matches an indicator - try to parse
-	_, parseErr := parser.Parse(trimmed + "\n")
+	_, parseErr := parser.ParseWithLocale(trimmed+"\n", loc)
 	if parseErr != nil {
 		// Matches indicator but fails to parse = likely failed calculation
 		return true, parseErr
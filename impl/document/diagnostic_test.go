@@ -6,6 +6,7 @@ import (
 
 	"github.com/CalcMark/go-calcmark/spec/document"
 	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
 )
 
 func TestLooksLikeFailedCalculation(t *testing.T) {
@@ -264,3 +265,97 @@ func TestIndicatorTriggersWarning(t *testing.T) {
 		})
 	}
 }
+
+func TestComplexExpressionHints(t *testing.T) {
+	source := "a = 1\nb = 2\nx = sqrt(((a + b) * a - b) / (a + 1))\n"
+
+	tests := []struct {
+		name      string
+		limits    semantic.ComplexityLimits
+		wantHints int
+	}{
+		{"default limits", semantic.DefaultComplexityLimits, 1},
+		{"disabled", semantic.ComplexityLimits{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.NewDocument(source)
+			if err != nil {
+				t.Fatalf("NewDocument error: %v", err)
+			}
+
+			evaluator := NewEvaluator()
+			evaluator.SetComplexityLimits(tt.limits)
+			if err := evaluator.Evaluate(doc); err != nil {
+				t.Fatalf("Evaluate error: %v", err)
+			}
+
+			hints := 0
+			for _, d := range evaluator.Diagnostics() {
+				if d.Severity == Hint && d.Code == semantic.DiagComplexExpression {
+					hints++
+				}
+			}
+			if hints != tt.wantHints {
+				t.Errorf("got %d complex expression hints, want %d: %v", hints, tt.wantHints, evaluator.Diagnostics())
+			}
+		})
+	}
+}
+
+func TestEvaluatorReportsPrecisionWarnings(t *testing.T) {
+	doc, err := document.NewDocument("third = 1 / 3\nhalf = 1 / 2\n")
+	if err != nil {
+		t.Fatalf("NewDocument error: %v", err)
+	}
+
+	evaluator := NewEvaluator()
+	if err := evaluator.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+
+	var warnings []BlockDiagnostic
+	for _, d := range evaluator.Diagnostics() {
+		if d.Code == semantic.DiagPrecisionLoss {
+			warnings = append(warnings, d)
+		}
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("got %d precision warnings, want 1: %v", len(warnings), evaluator.Diagnostics())
+	}
+	if warnings[0].Severity != Warning {
+		t.Errorf("got severity %v, want Warning", warnings[0].Severity)
+	}
+	if !strings.Contains(warnings[0].Message, "0.333333") {
+		t.Errorf("message %q does not include the result", warnings[0].Message)
+	}
+}
+
+func TestReevaluatingBlockReplacesItsDiagnostics(t *testing.T) {
+	doc, err := document.NewDocument("third = 1 / 3\n")
+	if err != nil {
+		t.Fatalf("NewDocument error: %v", err)
+	}
+
+	evaluator := NewEvaluator()
+	if err := evaluator.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	blockID := doc.GetBlocks()[0].ID
+	for range 2 {
+		if err := evaluator.EvaluateAffectedBlocks(doc, []string{blockID}); err != nil {
+			t.Fatalf("EvaluateAffectedBlocks error: %v", err)
+		}
+	}
+
+	var warnings []BlockDiagnostic
+	for _, d := range evaluator.Diagnostics() {
+		if d.Code == semantic.DiagPrecisionLoss {
+			warnings = append(warnings, d)
+		}
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("got %d precision warnings, want 1: %v", len(warnings), evaluator.Diagnostics())
+	}
+}
@@ -2,11 +2,14 @@ package document
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/CalcMark/go-calcmark/impl/interpreter"
 	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/parser"
 	"github.com/CalcMark/go-calcmark/spec/semantic"
 	"github.com/CalcMark/go-calcmark/spec/types"
@@ -17,51 +20,170 @@ import (
 type Evaluator struct {
 	env         *interpreter.Environment
 	diagnostics []BlockDiagnostic
+	complexity  semantic.ComplexityLimits
+	naming      semantic.NamingRules
+	locale      lexer.NumberLocale // Number style of the document being evaluated
+
+	resolver     document.Resolver   // Loads imported documents; nil disables imports
+	name         string              // Resolver name of the document being evaluated
+	importing    []string            // Documents importing this one, for cycle detection
+	importedDocs map[string][]string // Import path -> every document it loaded
+
+	blockTimeout time.Duration // Budget per CalcBlock; zero waits indefinitely
+	limits       Limits        // Size guardrails checked after Evaluate
+
+	blockDone func(node *document.BlockNode) // Called by Evaluate after each block; may be nil
+
+	functions   map[string]customFunction // Registered by RegisterFunction
+	dataPolicy  *interpreter.DataPolicy   // Files lookup() and jsonpath() may read; nil for none
+	cpiProvider interpreter.CPIProvider   // Price index adjust() reads after the frontmatter's; nil for none
+
+	divisionPrecision int // Decimal places division rounds to; zero for the interpreter's default
+
+	envLookup func(string) (string, bool) // Expands ${NAME} in frontmatter globals; nil for off
 }
 
 // NewEvaluator creates a new document evaluator.
 func NewEvaluator() *Evaluator {
 	return &Evaluator{
-		env: interpreter.NewEnvironment(),
+		env:        interpreter.NewEnvironment(),
+		complexity: semantic.DefaultComplexityLimits,
+		locale:     lexer.LocaleUS,
 	}
 }
 
+// SetEnvExpansion lets the documents this evaluator evaluates, and those
+// they import, fill in frontmatter globals from ${NAME} references looked
+// up through lookup, typically os.LookupEnv:
+//
+//	globals:
+//	  region_count: ${REGIONS:-3}
+//
+// Expansion is off by default, so a document can't read the environment
+// of whoever evaluates it; see document.ExpandReferences for the syntax.
+func (e *Evaluator) SetEnvExpansion(lookup func(name string) (string, bool)) {
+	e.envLookup = lookup
+}
+
+// SetComplexityLimits sets the thresholds for complex-expression hints.
+// A zero limit disables that check.
+func (e *Evaluator) SetComplexityLimits(limits semantic.ComplexityLimits) {
+	e.complexity = limits
+}
+
+// SetNamingRules enables variable naming convention hints.
+func (e *Evaluator) SetNamingRules(rules semantic.NamingRules) {
+	e.naming = rules
+}
+
+// SetBlockCallback sets a function Evaluate calls after each block, in
+// document order, so a caller can report a long document's results as
+// they arrive. A block that fails is reported before Evaluate returns its
+// error; blocks after it aren't. Diagnostics holds the block's warnings
+// by the time fn is called. nil removes the callback.
+func (e *Evaluator) SetBlockCallback(fn func(node *document.BlockNode)) {
+	e.blockDone = fn
+}
+
 // Evaluate evaluates all blocks in the document in dependency order.
-// CalcBlocks are evaluated top-down with accumulated environment.
+// CalcBlocks are evaluated top-down with accumulated environment, as are
+// custom blocks implementing document.EvaluableBlock.
 // TextBlocks are checked for lines that look like failed calculations.
+// Frontmatter imports are evaluated first, through the resolver set by SetResolver.
 //
 // Returns an error if any CalcBlock fails to evaluate.
 // Use Diagnostics() to get warnings about TextBlocks with likely calculation errors.
 func (e *Evaluator) Evaluate(doc *document.Document) error {
 	// Reset environment and diagnostics for clean evaluation
-	e.env = interpreter.NewEnvironment()
+	e.env = e.newEnvironment()
 	e.diagnostics = nil
+	e.locale = doc.NumberLocale()
+	doc.SetEnvExpansion(e.envLookup)
+
+	// Imported variables come first so the document's own globals can shadow them
+	if err := e.applyImports(doc); err != nil {
+		return err
+	}
 
 	// Apply frontmatter (exchange rates, globals) to environment before evaluation
 	if err := doc.ApplyFrontmatter(e.env); err != nil {
 		return fmt.Errorf("frontmatter: %w", err)
 	}
 
+	// Warn about oversized documents however evaluation ends
+	defer func() {
+		e.diagnostics = append(e.diagnostics, e.CheckLimits(doc)...)
+	}()
+
+	// Reported with the block holding the literal, as it's evaluated
+	ambiguity := localeDiagnostic(doc)
+
 	// Evaluate blocks in document order (top-down)
 	for _, node := range doc.GetBlocks() {
+		var err error
 		switch block := node.Block.(type) {
 		case *document.CalcBlock:
 			// Pass doc so @global/@exchange update frontmatter
-			err := e.evaluateCalcBlockWithDoc(node.ID, block, doc)
-			if err != nil {
-				return err
-			}
+			err = e.evaluateCalcBlockWithDoc(node.ID, block, doc)
+		case document.EvaluableBlock:
+			err = evaluateCustomBlock(block, e.env)
 		case *document.TextBlock:
 			// Check TextBlocks for lines that look like failed calculations
 			e.checkTextBlockForLikelyCalculations(node.ID, block)
 		}
+		if ambiguity != nil && ambiguity.BlockID == node.ID {
+			e.diagnostics = append(e.diagnostics, *ambiguity)
+		}
+		if e.blockDone != nil {
+			e.blockDone(node)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// Diagnostics returns warnings and errors collected during evaluation.
-// This includes warnings about TextBlock lines that look like failed calculations.
+// localeDiagnostic returns a warning on the literal that locale: auto
+// couldn't be sure how to read, or nil.
+func localeDiagnostic(doc *document.Document) *BlockDiagnostic {
+	det, ok := doc.LocaleDetection()
+	if !ok || det.Ambiguity == "" {
+		return nil
+	}
+	// Blocks cover the body's lines in order
+	line := det.Line
+	for _, node := range doc.GetBlocks() {
+		source := node.Block.Source()
+		if line <= len(source) {
+			return &BlockDiagnostic{
+				BlockID:  node.ID,
+				Line:     line,
+				Severity: Warning,
+				Code:     DiagAmbiguousLocale,
+				Message:  fmt.Sprintf("numbers read as %s: %s", det.Locale.Tag, det.Ambiguity),
+				Source:   source[line-1],
+			}
+		}
+		line -= len(source)
+	}
+	return nil
+}
+
+// dropBlockDiagnostics forgets the diagnostics evaluating a block
+// reported, before it is evaluated again. The locale ambiguity is the
+// document's, not the block's, so it stays.
+func (e *Evaluator) dropBlockDiagnostics(blockID string) {
+	e.diagnostics = slices.DeleteFunc(e.diagnostics, func(d BlockDiagnostic) bool {
+		return d.BlockID == blockID && d.Code != DiagAmbiguousLocale
+	})
+}
+
+// Diagnostics returns warnings, errors, and hints collected during evaluation.
+// This includes warnings about TextBlock lines that look like failed calculations
+// and semantic hints (e.g., overly complex expressions, naming conventions)
+// for CalcBlocks.
 func (e *Evaluator) Diagnostics() []BlockDiagnostic {
 	return e.diagnostics
 }
@@ -76,7 +198,7 @@ func (e *Evaluator) GetEnvironment() *interpreter.Environment {
 // appear to be intended calculations but failed to parse.
 func (e *Evaluator) checkTextBlockForLikelyCalculations(blockID string, block *document.TextBlock) {
 	for i, line := range block.Source() {
-		isLikely, parseErr := looksLikeFailedCalculation(line)
+		isLikely, parseErr := looksLikeFailedCalculationWithLocale(line, e.locale)
 		if isLikely {
 			msg := "line looks like an assignment but failed to parse"
 			if parseErr != nil {
@@ -108,12 +230,16 @@ func (e *Evaluator) EvaluateBlock(doc *document.Document, blockID string) error
 
 	// PASS 1: Evaluate all blocks to collect final variable values
 	// This builds the environment with all variable assignments
-	e.env = interpreter.NewEnvironment()
+	e.env = e.newEnvironment()
+	e.locale = doc.NumberLocale()
 
 	for _, node := range doc.GetBlocks() {
-		if cb, ok := node.Block.(*document.CalcBlock); ok {
+		switch block := node.Block.(type) {
+		case *document.CalcBlock:
 			// Evaluate to collect variable values (pass doc for frontmatter updates)
-			_ = e.evaluateCalcBlockWithDoc(node.ID, cb, doc)
+			_ = e.evaluateCalcBlockWithDoc(node.ID, block, doc)
+		case document.EvaluableBlock:
+			_ = evaluateCustomBlock(block, e.env)
 		}
 	}
 
@@ -125,10 +251,15 @@ func (e *Evaluator) EvaluateBlock(doc *document.Document, blockID string) error
 	// These are the "authoritative" assignments that shouldn't be overwritten
 	lastDefBlock := make(map[string]string) // varName -> blockID
 	for _, node := range doc.GetBlocks() {
-		if cb, ok := node.Block.(*document.CalcBlock); ok {
-			for _, varName := range cb.Variables() {
-				lastDefBlock[varName] = node.ID
-			}
+		var vars []string
+		switch block := node.Block.(type) {
+		case *document.CalcBlock:
+			vars = block.Variables()
+		case document.EvaluableBlock:
+			vars = block.Variables()
+		}
+		for _, varName := range vars {
+			lastDefBlock[varName] = node.ID
 		}
 	}
 
@@ -138,11 +269,15 @@ func (e *Evaluator) EvaluateBlock(doc *document.Document, blockID string) error
 	reactiveEnv := finalEnv.Clone()
 
 	for _, node := range doc.GetBlocks() {
-		if cb, ok := node.Block.(*document.CalcBlock); ok {
-			err := e.evaluateCalcBlockSelective(node.ID, cb, reactiveEnv, lastDefBlock)
-			if err != nil {
-				return err
-			}
+		var err error
+		switch block := node.Block.(type) {
+		case *document.CalcBlock:
+			err = e.evaluateCalcBlockSelective(node.ID, block, reactiveEnv, lastDefBlock)
+		case document.EvaluableBlock:
+			err = evaluateCustomBlockSelective(node.ID, block, reactiveEnv, lastDefBlock)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
@@ -164,17 +299,51 @@ func (e *Evaluator) EvaluateBlock(doc *document.Document, blockID string) error
 // The blocks should be in dependency order (use GetBlocksInDependencyOrder).
 // The environment is NOT reset - it maintains accumulated state from previous evaluations.
 func (e *Evaluator) EvaluateAffectedBlocks(doc *document.Document, blockIDs []string) error {
+	e.locale = doc.NumberLocale()
 	for _, blockID := range blockIDs {
 		node, ok := doc.GetBlock(blockID)
 		if !ok {
 			continue // Skip missing blocks
 		}
 
-		if cb, ok := node.Block.(*document.CalcBlock); ok {
-			err := e.evaluateCalcBlock(blockID, cb)
-			if err != nil {
-				return err
-			}
+		var err error
+		switch block := node.Block.(type) {
+		case *document.CalcBlock:
+			e.dropBlockDiagnostics(blockID)
+			err = e.evaluateCalcBlock(blockID, block)
+		case document.EvaluableBlock:
+			err = evaluateCustomBlock(block, e.env)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateCustomBlock evaluates an embedder's block in env.
+func evaluateCustomBlock(block document.EvaluableBlock, env *interpreter.Environment) error {
+	if err := block.Evaluate(env); err != nil {
+		return fmt.Errorf("%s block: %w", block.Kind(), err)
+	}
+	block.SetDirty(false)
+	return nil
+}
+
+// evaluateCustomBlockSelective evaluates an embedder's block in a copy of
+// env, then sets in env only the variables the block defines last, as
+// evaluateCalcBlockSelective does.
+func evaluateCustomBlockSelective(blockID string, block document.EvaluableBlock, env *interpreter.Environment, lastDefBlock map[string]string) error {
+	scratch := env.Clone()
+	if err := evaluateCustomBlock(block, scratch); err != nil {
+		return err
+	}
+	for _, varName := range block.Variables() {
+		if lastDefBlock[varName] != blockID {
+			continue
+		}
+		if value, ok := scratch.Get(varName); ok {
+			env.Set(varName, value)
 		}
 	}
 	return nil
@@ -194,7 +363,7 @@ func (e *Evaluator) evaluateCalcBlockSelective(blockID string, block *document.C
 		source += "\n"
 	}
 
-	nodes, err := parser.Parse(source)
+	nodes, err := parser.ParseWithLocale(source, e.locale)
 	if err != nil {
 		block.SetError(err)
 		// Convert ParseError to Diagnostic for position info
@@ -214,7 +383,7 @@ func (e *Evaluator) evaluateCalcBlockSelective(blockID string, block *document.C
 	block.SetStatements(nodes)
 
 	// 2. Semantic check with the provided environment
-	checker := semantic.NewChecker()
+	checker := e.newChecker()
 	for varName, value := range env.GetAllVariables() {
 		checker.GetEnvironment().Set(varName, value)
 	}
@@ -224,9 +393,11 @@ func (e *Evaluator) evaluateCalcBlockSelective(blockID string, block *document.C
 		if diag.Severity == semantic.Error {
 			// Store structured diagnostic with position info
 			blockDiag := document.Diagnostic{
-				Severity: "error",
-				Code:     diag.Code,
-				Message:  diag.Message,
+				Severity:    "error",
+				Code:        diag.Code,
+				Message:     diag.Message,
+				Suggestions: diag.Suggestions,
+				Fixes:       diag.Fixes,
 			}
 			if diag.Range != nil {
 				blockDiag.Line = diag.Range.Start.Line
@@ -244,8 +415,7 @@ func (e *Evaluator) evaluateCalcBlockSelective(blockID string, block *document.C
 	// 3. Interpret with a COPY of the environment
 	// We'll selectively copy back only authoritative assignments
 	evalEnv := env.Clone()
-	interp := interpreter.NewInterpreterWithEnv(evalEnv)
-	results, err := interp.Eval(nodes)
+	results, err := interpreter.NewInterpreterWithEnv(evalEnv).Eval(nodes)
 	if err != nil {
 		block.SetError(err)
 		return err
@@ -290,7 +460,7 @@ func (e *Evaluator) evaluateCalcBlockWithDoc(blockID string, block *document.Cal
 		source += "\n"
 	}
 
-	nodes, err := parser.Parse(source)
+	nodes, err := parser.ParseWithLocale(source, e.locale)
 	if err != nil {
 		block.SetError(err)
 		// Convert ParseError to Diagnostic for position info
@@ -306,11 +476,12 @@ func (e *Evaluator) evaluateCalcBlockWithDoc(blockID string, block *document.Cal
 		return err
 	}
 
-	// Store parsed AST
+	// Store parsed AST, keeping the previous one in case interpreting times out
+	prevStatements := block.Statements()
 	block.SetStatements(nodes)
 
 	// 2. Semantic check with current environment
-	checker := semantic.NewChecker()
+	checker := e.newChecker()
 
 	// Pre-populate checker environment with interpreter's environment
 	for varName, value := range e.env.GetAllVariables() {
@@ -319,14 +490,20 @@ func (e *Evaluator) evaluateCalcBlockWithDoc(blockID string, block *document.Cal
 
 	diagnostics := checker.Check(nodes)
 
-	// Check for errors
+	// Check for errors; collect warnings and hints for Diagnostics()
 	for _, diag := range diagnostics {
+		if diag.Severity != semantic.Error {
+			e.diagnostics = append(e.diagnostics, checkerDiagnostic(blockID, block, diag))
+			continue
+		}
 		if diag.Severity == semantic.Error {
 			// Store structured diagnostic with position info
 			blockDiag := document.Diagnostic{
-				Severity: "error",
-				Code:     diag.Code,
-				Message:  diag.Message,
+				Severity:    "error",
+				Code:        diag.Code,
+				Message:     diag.Message,
+				Suggestions: diag.Suggestions,
+				Fixes:       diag.Fixes,
 			}
 			if diag.Range != nil {
 				blockDiag.Line = diag.Range.Start.Line
@@ -342,9 +519,13 @@ func (e *Evaluator) evaluateCalcBlockWithDoc(blockID string, block *document.Cal
 	}
 
 	// 3. Interpret statements with shared environment
-	interp := interpreter.NewInterpreterWithEnv(e.env)
-
-	results, err := interp.Eval(nodes)
+	results, timedOut, err := e.interpret(source, nodes)
+	if timedOut {
+		// Not an error: later blocks carry on with the previous values
+		e.keepStale(blockID, block, prevStatements)
+		return nil
+	}
+	block.SetStale(false)
 	if err != nil {
 		block.SetError(err)
 		return err
@@ -403,3 +584,26 @@ func (e *Evaluator) updateFrontmatterFromNodes(doc *document.Document, nodes []a
 		}
 	}
 }
+
+// checkerDiagnostic converts a semantic warning or hint into a BlockDiagnostic.
+func checkerDiagnostic(blockID string, block *document.CalcBlock, diag semantic.Diagnostic) BlockDiagnostic {
+	severity := Hint
+	if diag.Severity == semantic.Warning {
+		severity = Warning
+	}
+	bd := BlockDiagnostic{
+		BlockID:  blockID,
+		Severity: severity,
+		Code:     diag.Code,
+		Message:  diag.Message,
+		Fixes:    diag.Fixes,
+	}
+	if diag.Range != nil && diag.Range.Start.Line > 0 {
+		bd.Line = diag.Range.Start.Line
+		bd.Column = diag.Range.Start.Column
+		if source := block.Source(); bd.Line <= len(source) {
+			bd.Source = source[bd.Line-1]
+		}
+	}
+	return bd
+}
@@ -1,9 +1,11 @@
 package document
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
 	"github.com/CalcMark/go-calcmark/spec/document"
 )
 
@@ -417,3 +419,207 @@ z = x + y
 		}
 	}
 }
+
+// TestEvaluateWithLocale tests that numbers follow the frontmatter locale.
+func TestEvaluateWithLocale(t *testing.T) {
+	source := `---
+locale: de-DE
+globals:
+  base: 1.000,00
+---
+
+total = base + 2,5
+mean = avg(total, 1,5)
+`
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	total, ok := eval.GetEnvironment().Get("total")
+	if !ok || total.String() != "1002.5" {
+		t.Errorf("total = %v, want 1002.5", total)
+	}
+
+	mean, ok := eval.GetEnvironment().Get("mean")
+	if !ok || mean.String() != "502" {
+		t.Errorf("mean = %v, want 502", mean)
+	}
+}
+
+// TestEvaluateWithLocaleAuto tests that a detected locale reads the
+// literals, and a literal written the other way is warned about.
+func TestEvaluateWithLocaleAuto(t *testing.T) {
+	source := `---
+locale: auto
+---
+preis = 1.000,50
+rabatt = 0,1
+
+
+# Notizen
+
+Versand kostet 4.95 extra.
+`
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if preis, ok := eval.GetEnvironment().Get("preis"); !ok || preis.String() != "1000.5" {
+		t.Errorf("preis = %v, want 1000.5", preis)
+	}
+
+	var found *BlockDiagnostic
+	for i, d := range eval.Diagnostics() {
+		if d.Code == DiagAmbiguousLocale {
+			found = &eval.Diagnostics()[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("no %s diagnostic in %v", DiagAmbiguousLocale, eval.Diagnostics())
+	}
+	if found.Source != "Versand kostet 4.95 extra." || !strings.Contains(found.Message, "de-DE") {
+		t.Errorf("diagnostic = %+v", found)
+	}
+}
+
+// TestBlockCallback tests that Evaluate reports each block as it finishes,
+// up to and including one that fails.
+func TestBlockCallback(t *testing.T) {
+	doc, err := document.NewDocument("a = 2\n\n# Notes\n\nb = a / 0\n\n\nc = 3\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	eval := NewEvaluator()
+	var reported []string
+	eval.SetBlockCallback(func(node *document.BlockNode) {
+		source := strings.TrimSpace(strings.Join(node.Block.Source(), " "))
+		if cb, ok := node.Block.(*document.CalcBlock); ok && cb.Error() == nil && cb.LastValue() == nil {
+			t.Errorf("block %q reported before it was evaluated", source)
+		}
+		reported = append(reported, source)
+	})
+	if err := eval.Evaluate(doc); err == nil {
+		t.Fatal("expected division by zero")
+	}
+
+	want := []string{"a = 2", "# Notes", "b = a / 0"}
+	if strings.Join(reported, "|") != strings.Join(want, "|") {
+		t.Errorf("reported %q, want %q", reported, want)
+	}
+}
+
+func TestEvaluateWithEnvExpansion(t *testing.T) {
+	source := "---\nglobals:\n  regions: ${REGIONS:-3}\n---\nservers = regions * 4\n"
+	lookup := func(name string) (string, bool) {
+		if name == "REGIONS" {
+			return "5", true
+		}
+		return "", false
+	}
+
+	eval := NewEvaluator()
+	doc, _ := document.NewDocument(source)
+	if err := eval.Evaluate(doc); err == nil || !strings.Contains(err.Error(), "expanding environment variables is off") {
+		t.Errorf("without expansion, err = %v", err)
+	}
+
+	eval.SetEnvExpansion(lookup)
+	doc, _ = document.NewDocument(source)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if servers, _ := eval.GetEnvironment().Get("servers"); servers == nil || servers.String() != "20" {
+		t.Errorf("servers = %v, want 20", servers)
+	}
+	// Formatters applying the frontmatter again see the same values
+	env := interpreter.NewEnvironment()
+	if err := doc.ApplyFrontmatter(env); err != nil {
+		t.Errorf("ApplyFrontmatter after evaluation: %v", err)
+	}
+}
+
+// copyFactory makes one-line blocks like "!copy subtotal as total".
+type copyFactory struct{}
+
+func (copyFactory) Kind() string { return "copy" }
+
+func (copyFactory) Detect(lines []string) int {
+	if strings.HasPrefix(lines[0], "!copy ") {
+		return 1
+	}
+	return 0
+}
+
+func (copyFactory) Parse(lines []string) (document.CustomBlock, error) {
+	var from, to string
+	if _, err := fmt.Sscanf(lines[0], "!copy %s as %s", &from, &to); err != nil {
+		return nil, err
+	}
+	return &copyBlock{CustomBlockBase: document.NewCustomBlockBase(lines), from: from, to: to}, nil
+}
+
+type copyBlock struct {
+	document.CustomBlockBase
+	from, to string
+}
+
+func (b *copyBlock) Kind() string           { return "copy" }
+func (b *copyBlock) Variables() []string    { return []string{b.to} }
+func (b *copyBlock) Dependencies() []string { return []string{b.from} }
+
+func (b *copyBlock) Evaluate(env *interpreter.Environment) error {
+	value, ok := env.Get(b.from)
+	if !ok {
+		return fmt.Errorf("undefined variable '%s'", b.from)
+	}
+	env.Set(b.to, value)
+	return nil
+}
+
+func TestEvaluateCustomBlock(t *testing.T) {
+	detector := document.NewDetector()
+	if err := detector.RegisterBlock(copyFactory{}); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := document.NewDocumentWithDetector("subtotal = 40\n!copy subtotal as total\nresult = total * 2\n", detector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result, _ := eval.GetEnvironment().Get("result"); result == nil || result.String() != "80" {
+		t.Errorf("result = %v, want 80", result)
+	}
+
+	// Reactive evaluation reaches the custom block too
+	first := doc.GetBlocks()[0]
+	if _, err := doc.ReplaceBlockSource(first.ID, []string{"subtotal = 50"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := eval.EvaluateBlock(doc, first.ID); err != nil {
+		t.Fatalf("EvaluateBlock failed: %v", err)
+	}
+	if result, _ := eval.GetEnvironment().Get("result"); result == nil || result.String() != "100" {
+		t.Errorf("after edit, result = %v, want 100", result)
+	}
+
+	doc, _ = document.NewDocumentWithDetector("!copy missing as total\n", detector)
+	if err := eval.Evaluate(doc); err == nil || !strings.Contains(err.Error(), "copy block: undefined variable 'missing'") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+)
+
+// customFunction is a function registered with RegisterFunction.
+type customFunction struct {
+	fn  interpreter.Function
+	sig semantic.FunctionSignature
+}
+
+// RegisterFunction adds a function documents can call, so an application
+// embedding CalcMark can offer its own calculations:
+//
+//	eval.RegisterFunction("shipping_cost", shippingCost,
+//		semantic.FunctionSignature{Params: []string{"weight", "zone"}})
+//
+// Calls are checked against sig before evaluation, so fn receives as many
+// arguments as sig takes; it checks their types itself. A misspelled call
+// is an undefined-function error suggesting the closest names, custom
+// ones included. Documents this evaluator imports can call it too.
+//
+// name must be an identifier and not a built-in function. Registering a
+// name again replaces its function.
+func (e *Evaluator) RegisterFunction(name string, fn interpreter.Function, sig semantic.FunctionSignature) error {
+	if fn == nil {
+		return fmt.Errorf("function '%s' is nil", name)
+	}
+	if semantic.IsBuiltinFunction(name) {
+		return fmt.Errorf("'%s' is a built-in function", name)
+	}
+	// The name must read back as a call to it, not as a keyword or unit
+	nodes, err := parser.Parse(name + "()\n")
+	if err != nil || len(nodes) != 1 {
+		return fmt.Errorf("invalid function name '%s'", name)
+	}
+	if call, ok := nodes[0].(*ast.FunctionCall); !ok || call.Name != name {
+		return fmt.Errorf("invalid function name '%s'", name)
+	}
+
+	if e.functions == nil {
+		e.functions = make(map[string]customFunction)
+	}
+	e.functions[name] = customFunction{fn: fn, sig: sig}
+	e.env.SetFunction(name, fn)
+	return nil
+}
+
+// SetDataPolicy allows lookup() and jsonpath() to read the files policy
+// allows. Reading files is disabled until it is set, so documents from
+// untrusted sources can't read the evaluator's files; nil disables it again.
+func (e *Evaluator) SetDataPolicy(policy *interpreter.DataPolicy) {
+	e.dataPolicy = policy
+	e.env.SetDataPolicy(policy)
+}
+
+// SetCPIProvider sets where adjust() finds the consumer price index for
+// years a document's cpi: frontmatter doesn't list, such as a series an
+// application loads from a statistics agency:
+//
+//	eval.SetCPIProvider(interpreter.CPITable{2015: decimal.NewFromFloat(237.0)})
+//
+// Nil leaves documents to their own frontmatter.
+func (e *Evaluator) SetCPIProvider(p interpreter.CPIProvider) {
+	e.cpiProvider = p
+	e.env.SetCPIProvider(p)
+}
+
+// newEnvironment returns an empty environment with the registered
+// functions, data policy, CPI provider, and division precision.
+func (e *Evaluator) newEnvironment() *interpreter.Environment {
+	env := interpreter.NewEnvironment()
+	for name, f := range e.functions {
+		env.SetFunction(name, f.fn)
+	}
+	env.SetDataPolicy(e.dataPolicy)
+	env.SetCPIProvider(e.cpiProvider)
+	env.SetDivisionPrecision(e.divisionPrecision)
+	return env
+}
+
+// newChecker returns a semantic checker with the evaluator's settings and
+// the registered functions' signatures.
+func (e *Evaluator) newChecker() *semantic.Checker {
+	checker := semantic.NewChecker()
+	checker.SetComplexityLimits(e.complexity)
+	checker.SetNamingRules(e.naming)
+	if len(e.functions) > 0 {
+		signatures := make(map[string]semantic.FunctionSignature, len(e.functions))
+		for name, f := range e.functions {
+			signatures[name] = f.sig
+		}
+		checker.SetFunctions(signatures)
+	}
+	return checker
+}
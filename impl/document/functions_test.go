@@ -0,0 +1,141 @@
+package document
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// shippingCost charges $5 a kilogram plus $10 a zone.
+func shippingCost(args []types.Type) (types.Type, error) {
+	weight, ok := args[0].(*types.Quantity)
+	if !ok {
+		return nil, fmt.Errorf("weight must be a quantity, got %s", args[0])
+	}
+	zone, ok := args[1].(*types.Number)
+	if !ok {
+		return nil, fmt.Errorf("zone must be a number")
+	}
+	cost := weight.Value.Mul(decimal.NewFromInt(5)).Add(zone.Value.Mul(decimal.NewFromInt(10)))
+	return types.NewCurrency(cost, "$"), nil
+}
+
+var shippingSignature = semantic.FunctionSignature{Params: []string{"weight", "zone"}}
+
+func TestRegisterFunction(t *testing.T) {
+	eval := NewEvaluator()
+	if err := eval.RegisterFunction("shipping_cost", shippingCost, shippingSignature); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := document.NewDocument("parcel = 3 kg\ncost = shipping_cost(parcel, 2)\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	cost, ok := eval.GetEnvironment().Get("cost")
+	if !ok || cost.String() != "$35.00" {
+		t.Errorf("cost = %v, want $35.00", cost)
+	}
+
+	// Errors from the function name it
+	doc, _ = document.NewDocument("cost = shipping_cost(2, 3)\n")
+	if err := eval.Evaluate(doc); err == nil || !strings.Contains(err.Error(), "shipping_cost(): weight must be a quantity") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRegisterFunctionChecksCalls(t *testing.T) {
+	eval := NewEvaluator()
+	if err := eval.RegisterFunction("shipping_cost", shippingCost, shippingSignature); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source, code, message string
+	}{
+		{"cost = shipping_cost(3 kg)\n", semantic.DiagInvalidArgumentCount, "shipping_cost() requires exactly 2 arguments, as in shipping_cost(weight, zone)"},
+		{"cost = shiping_cost(3 kg, 2)\n", semantic.DiagUndefinedFunction, `Undefined function "shiping_cost"`},
+	}
+	for _, tt := range tests {
+		doc, _ := document.NewDocument(tt.source)
+		if err := eval.Evaluate(doc); err == nil {
+			t.Errorf("%q: expected an error", tt.source)
+			continue
+		}
+		diags := doc.GetBlocks()[0].Block.(*document.CalcBlock).Diagnostics()
+		if len(diags) != 1 || diags[0].Code != tt.code || diags[0].Message != tt.message {
+			t.Errorf("%q: diagnostics = %+v", tt.source, diags)
+			continue
+		}
+		if tt.code == semantic.DiagUndefinedFunction && !slices.Contains(diags[0].Suggestions, "shipping_cost") {
+			t.Errorf("suggestions = %v, want shipping_cost", diags[0].Suggestions)
+		}
+	}
+}
+
+func TestRegisterFunctionErrors(t *testing.T) {
+	eval := NewEvaluator()
+	for _, name := range []string{"sum", "2x", "shipping cost", ""} {
+		if err := eval.RegisterFunction(name, shippingCost, shippingSignature); err == nil {
+			t.Errorf("RegisterFunction(%q) succeeded", name)
+		}
+	}
+	if err := eval.RegisterFunction("shipping_cost", nil, shippingSignature); err == nil {
+		t.Error("RegisterFunction with a nil function succeeded")
+	}
+}
+
+func TestSetDataPolicy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "prices.csv"), []byte("sku,price\nA-100,$12.50\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := "unit = lookup(\"prices.csv\", sku, \"A-100\", price)\ntotal = unit * 4\n"
+
+	eval := NewEvaluator()
+	doc, _ := document.NewDocument(source)
+	if err := eval.Evaluate(doc); err == nil || !strings.Contains(err.Error(), "reading files is disabled") {
+		t.Errorf("without a policy, err = %v", err)
+	}
+
+	eval.SetDataPolicy(&interpreter.DataPolicy{AllowedPaths: []string{dir}, BaseDir: dir})
+	doc, _ = document.NewDocument(source)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if total, _ := eval.GetEnvironment().Get("total"); total == nil || total.String() != "$50.00" {
+		t.Errorf("total = %v, want $50.00", total)
+	}
+}
+
+func TestSetCPIProvider(t *testing.T) {
+	source := "---\ncpi:\n  2020: 250\n---\n\nprice = adjust($100 from 2010 to 2020)\n"
+
+	eval := NewEvaluator()
+	doc, _ := document.NewDocument(source)
+	if err := eval.Evaluate(doc); err == nil || !strings.Contains(err.Error(), "no CPI for 2010") {
+		t.Errorf("without a provider, err = %v", err)
+	}
+
+	// The frontmatter's 2020 wins over the provider's
+	eval.SetCPIProvider(interpreter.CPITable{2010: decimal.NewFromInt(200), 2020: decimal.NewFromInt(300)})
+	doc, _ = document.NewDocument(source)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if price, _ := eval.GetEnvironment().Get("price"); price == nil || price.String() != "$125.00 (real 2020)" {
+		t.Errorf("price = %v, want $125.00 (real 2020)", price)
+	}
+}
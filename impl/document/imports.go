@@ -0,0 +1,223 @@
+package document
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// MaxImportSize is the largest document a FileResolver will load.
+const MaxImportSize = 1 * 1024 * 1024 // 1MB
+
+// FileResolver resolves imports to .cm files inside a root directory.
+// Paths are relative to the importing file, or to the root for a document
+// without a name (e.g. read from stdin). Nothing outside the root is loaded.
+type FileResolver struct {
+	root string
+}
+
+// NewFileResolver creates a resolver confined to root.
+func NewFileResolver(root string) (*FileResolver, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import root: %w", err)
+	}
+	return &FileResolver{root: abs}, nil
+}
+
+// Resolve implements document.Resolver. The returned name is the file's
+// absolute path.
+func (r *FileResolver) Resolve(from, path string) (string, string, error) {
+	dir := r.root
+	if from != "" {
+		dir = filepath.Dir(from)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(r.root, dir)
+		}
+	}
+	full := path
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(dir, path)
+	}
+	full = filepath.Clean(full)
+
+	rel, err := filepath.Rel(r.root, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("'%s' is outside %s", path, r.root)
+	}
+
+	ext := strings.ToLower(filepath.Ext(full))
+	if ext != ".cm" && ext != ".calcmark" {
+		return "", "", fmt.Errorf("invalid file extension: expected .cm or .calcmark")
+	}
+
+	info, err := os.Stat(full)
+	if err != nil {
+		return "", "", err
+	}
+	if info.IsDir() {
+		return "", "", fmt.Errorf("'%s' is a directory", path)
+	}
+	if info.Size() > MaxImportSize {
+		return "", "", fmt.Errorf("file too large: %d bytes (max %d)", info.Size(), MaxImportSize)
+	}
+
+	content, err := os.ReadFile(full)
+	if err != nil {
+		return "", "", err
+	}
+	return full, string(content), nil
+}
+
+// SetResolver enables frontmatter imports. name identifies the document
+// being evaluated to the resolver (for files, its path) so its imports
+// resolve relative to it and cycles back to it are caught; it may be empty.
+func (e *Evaluator) SetResolver(resolver document.Resolver, name string) {
+	e.resolver = resolver
+	e.name = name
+}
+
+// applyImports evaluates the documents doc imports and defines the variables
+// they export in the environment. Variables from different imports must not
+// collide; the document's own globals and assignments may shadow them.
+func (e *Evaluator) applyImports(doc *document.Document) error {
+	e.importedDocs = nil
+	fm := doc.GetFrontmatter()
+	if fm == nil || len(fm.Imports) == 0 {
+		doc.SetImportedVariables(nil)
+		return nil
+	}
+	if e.resolver == nil {
+		return fmt.Errorf("import '%s': imports are not available here", fm.Imports[0])
+	}
+
+	imported := make(map[string]string) // variable -> import path
+	e.importedDocs = make(map[string][]string)
+	for _, imp := range fm.Imports {
+		values, loaded, err := e.loadImport(imp)
+		if err != nil {
+			return err
+		}
+		for name, value := range values {
+			if prev, ok := imported[name]; ok && prev != imp.Path {
+				return fmt.Errorf("import '%s': '%s' is also imported from '%s'", imp, name, prev)
+			}
+			imported[name] = imp.Path
+			e.env.Set(name, value)
+		}
+		e.importedDocs[imp.Path] = append(e.importedDocs[imp.Path], loaded...)
+	}
+	doc.SetImportedVariables(imported)
+	return nil
+}
+
+// loadImport resolves and evaluates one imported document. It returns the
+// variables exported at the import's anchor and the names of every document
+// loaded on the way, including those it imports itself.
+func (e *Evaluator) loadImport(imp document.Import) (map[string]types.Type, []string, error) {
+	name, source, err := e.resolver.Resolve(e.name, imp.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("import '%s': %w", imp, err)
+	}
+
+	chain := e.importing
+	if e.name != "" {
+		chain = append(slices.Clone(chain), e.name)
+	}
+	if slices.Contains(chain, name) {
+		return nil, nil, fmt.Errorf("import cycle: %s", strings.Join(append(chain, name), " -> "))
+	}
+
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("import '%s': %w", imp, err)
+	}
+	child := &Evaluator{
+		env:               interpreter.NewEnvironment(),
+		complexity:        e.complexity,
+		naming:            e.naming,
+		locale:            doc.NumberLocale(),
+		resolver:          e.resolver,
+		name:              name,
+		importing:         chain,
+		functions:         e.functions,
+		dataPolicy:        e.dataPolicy,
+		cpiProvider:       e.cpiProvider,
+		divisionPrecision: e.divisionPrecision,
+		envLookup:         e.envLookup,
+	}
+	if err := child.Evaluate(doc); err != nil {
+		return nil, nil, fmt.Errorf("import '%s': %w", imp, err)
+	}
+
+	exported, err := doc.ExportedVariables(imp.Anchor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("import '%s': %w", imp, err)
+	}
+	values := make(map[string]types.Type, len(exported))
+	for _, varName := range exported {
+		if value, ok := child.env.Get(varName); ok {
+			values[varName] = value
+		}
+	}
+
+	loaded := []string{name}
+	for _, names := range child.importedDocs {
+		loaded = append(loaded, names...)
+	}
+	return values, loaded, nil
+}
+
+// ReloadImports re-evaluates the imports that depend on the document named
+// changed (as returned by the resolver), directly or through their own
+// imports, and updates their variables in the environment. It returns the
+// IDs of the blocks that now need re-evaluation, in dependency order, for
+// EvaluateAffectedBlocks:
+//
+//	affected, err := eval.ReloadImports(doc, "/path/to/rates.cm")
+//	eval.EvaluateAffectedBlocks(doc, affected)
+//
+// Returns nil if doc does not depend on changed.
+func (e *Evaluator) ReloadImports(doc *document.Document, changed string) ([]string, error) {
+	fm := doc.GetFrontmatter()
+	if fm == nil || e.resolver == nil {
+		return nil, nil
+	}
+
+	imported := doc.ImportedVariables()
+	var affected []string
+	for _, imp := range fm.Imports {
+		if !slices.Contains(e.importedDocs[imp.Path], changed) {
+			continue
+		}
+		values, loaded, err := e.loadImport(imp)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			if prev, ok := imported[name]; ok && prev != imp.Path {
+				return nil, fmt.Errorf("import '%s': '%s' is also imported from '%s'", imp, name, prev)
+			}
+			imported[name] = imp.Path
+			e.env.Set(name, value)
+		}
+		e.importedDocs[imp.Path] = loaded
+		affected = append(affected, imp.Path)
+	}
+	if len(affected) == 0 {
+		return nil, nil
+	}
+
+	doc.SetImportedVariables(imported)
+	var blockIDs []string
+	for _, path := range affected {
+		blockIDs = append(blockIDs, doc.BlocksAffectedByImport(path)...)
+	}
+	return doc.GetBlocksInDependencyOrder(blockIDs), nil
+}
@@ -0,0 +1,223 @@
+package document
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// mapResolver serves documents from memory, keyed by path.
+type mapResolver map[string]string
+
+func (r mapResolver) Resolve(from, path string) (string, string, error) {
+	source, ok := r[path]
+	if !ok {
+		return "", "", fmt.Errorf("not found")
+	}
+	return path, source, nil
+}
+
+func evaluateWithImports(t *testing.T, resolver document.Resolver, source string) (*Evaluator, *document.Document, error) {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	eval.SetResolver(resolver, "main.cm")
+	return eval, doc, eval.Evaluate(doc)
+}
+
+func TestEvaluateImports(t *testing.T) {
+	resolver := mapResolver{
+		"rates.cm": "---\nglobals:\n  tax: 0.25\n---\nfee = 10\n",
+		"budget.cm": `# Budget
+
+## Costs
+
+rent = 1000
+food = 400
+
+
+## Summary
+
+total = rent + food
+`,
+	}
+	source := `---
+imports:
+  - rates.cm
+  - budget.cm#summary
+---
+net = total * (1 - tax) - fee
+`
+	eval, doc, err := evaluateWithImports(t, resolver, source)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	net, ok := eval.GetEnvironment().Get("net")
+	if !ok || net.String() != "1040" {
+		t.Errorf("net = %v, want 1040", net)
+	}
+
+	// Only the summary section of budget.cm is imported
+	if eval.GetEnvironment().Has("rent") {
+		t.Error("rent should not be imported from budget.cm#summary")
+	}
+
+	imported := doc.ImportedVariables()
+	if imported["total"] != "budget.cm" || imported["tax"] != "rates.cm" || imported["fee"] != "rates.cm" {
+		t.Errorf("ImportedVariables() = %v", imported)
+	}
+}
+
+func TestEvaluateImportErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver mapResolver
+		source   string
+		wantErr  string
+	}{
+		{
+			name:     "missing document",
+			resolver: mapResolver{},
+			source:   "---\nimports: [gone.cm]\n---\nx = 1\n",
+			wantErr:  "import 'gone.cm': not found",
+		},
+		{
+			name: "cycle",
+			resolver: mapResolver{
+				"a.cm":    "---\nimports: [b.cm]\n---\na = 1\n",
+				"b.cm":    "---\nimports: [main.cm]\n---\nb = 1\n",
+				"main.cm": "---\nimports: [a.cm]\n---\nx = 1\n",
+			},
+			source:  "---\nimports: [a.cm]\n---\nx = 1\n",
+			wantErr: "import cycle: main.cm -> a.cm -> b.cm -> main.cm",
+		},
+		{
+			name: "collision",
+			resolver: mapResolver{
+				"a.cm": "x = 1\n",
+				"b.cm": "x = 2\n",
+			},
+			source:  "---\nimports: [a.cm, b.cm]\n---\ny = x\n",
+			wantErr: "'x' is also imported from 'a.cm'",
+		},
+		{
+			name:     "unknown anchor",
+			resolver: mapResolver{"a.cm": "x = 1\n"},
+			source:   "---\nimports: [a.cm#totals]\n---\ny = 1\n",
+			wantErr:  "no block or heading named 'totals'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := evaluateWithImports(t, tt.resolver, tt.source)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Evaluate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateImportsWithoutResolver(t *testing.T) {
+	doc, err := document.NewDocument("---\nimports: [a.cm]\n---\nx = 1\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	if err := NewEvaluator().Evaluate(doc); err == nil {
+		t.Error("Evaluate() should fail when imports cannot be loaded")
+	}
+}
+
+func TestReloadImports(t *testing.T) {
+	resolver := mapResolver{
+		"base.cm":  "price = 10\n",
+		"rates.cm": "---\nimports: [base.cm]\n---\nunit = price * 2\n",
+		"other.cm": "qty = 3\n",
+	}
+	source := `---
+imports: [rates.cm, other.cm]
+---
+cost = unit * qty
+
+
+label = 5
+`
+	eval, doc, err := evaluateWithImports(t, resolver, source)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	// base.cm changes; rates.cm imports it, so cost is affected
+	resolver["base.cm"] = "price = 20\n"
+	affected, err := eval.ReloadImports(doc, "base.cm")
+	if err != nil {
+		t.Fatalf("ReloadImports failed: %v", err)
+	}
+	if len(affected) != 1 || affected[0] != doc.GetBlocks()[0].ID {
+		t.Fatalf("ReloadImports() = %v, want only the cost block", affected)
+	}
+	if err := eval.EvaluateAffectedBlocks(doc, affected); err != nil {
+		t.Fatalf("EvaluateAffectedBlocks failed: %v", err)
+	}
+	cost, _ := eval.GetEnvironment().Get("cost")
+	if cost == nil || cost.String() != "120" {
+		t.Errorf("cost = %v, want 120", cost)
+	}
+
+	// A document nothing imports affects nothing
+	affected, err = eval.ReloadImports(doc, "unrelated.cm")
+	if err != nil || affected != nil {
+		t.Errorf("ReloadImports(unrelated) = %v, %v; want nil, nil", affected, err)
+	}
+}
+
+func TestFileResolver(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "shared")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(sub, "rates.cm"), "---\nimports: [base.cm]\n---\nrate = base * 2\n")
+	writeFile(filepath.Join(sub, "base.cm"), "base = 21\n")
+	writeFile(filepath.Join(root, "notes.txt"), "x = 1\n")
+	main := filepath.Join(root, "main.cm")
+
+	resolver, err := NewFileResolver(root)
+	if err != nil {
+		t.Fatalf("NewFileResolver failed: %v", err)
+	}
+
+	// Nested imports resolve relative to the importing file
+	doc, err := document.NewDocument("---\nimports: [shared/rates.cm]\n---\nx = rate\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	eval.SetResolver(resolver, main)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if x, _ := eval.GetEnvironment().Get("x"); x == nil || x.String() != "42" {
+		t.Errorf("x = %v, want 42", x)
+	}
+
+	for _, path := range []string{"../outside.cm", "notes.txt", "missing.cm", "shared"} {
+		if _, _, err := resolver.Resolve(main, path); err == nil {
+			t.Errorf("Resolve(%q) should fail", path)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Approximate heap sizes on 64-bit platforms, used by MemoryStats.
+const (
+	pointerBytes  = 8
+	stringBytes   = 16      // String header; contents are added separately
+	sliceBytes    = 24      // Slice header; elements are added separately
+	decimalBytes  = 16 + 32 // decimal.Decimal plus its big.Int header
+	timeBytes     = 24      // time.Time
+	mapEntryBytes = 2*stringBytes + pointerBytes
+)
+
+// MemoryStats estimates the memory an evaluated document holds on to.
+// The byte counts are estimates of the values' heap sizes, not measurements;
+// they are meant for spotting growth, not for exact accounting.
+type MemoryStats struct {
+	Variables        int   // Variables in the environment, including constants
+	Results          int   // Per-statement results held by calculation blocks
+	EnvironmentBytes int64 // Variable names and values
+	ResultBytes      int64 // Block results
+}
+
+// TotalBytes returns the estimated size of the environment and results.
+func (s MemoryStats) TotalBytes() int64 {
+	return s.EnvironmentBytes + s.ResultBytes
+}
+
+// MemoryStats estimates the memory held by doc's results and by the
+// environment of its last evaluation.
+func (e *Evaluator) MemoryStats(doc *document.Document) MemoryStats {
+	var stats MemoryStats
+	for name, value := range e.env.GetAllVariables() {
+		stats.Variables++
+		stats.EnvironmentBytes += mapEntryBytes + int64(len(name)) + valueBytes(value)
+	}
+	for _, node := range doc.GetBlocks() {
+		cb, ok := node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		results := cb.Results()
+		stats.Results += len(results)
+		stats.ResultBytes += sliceBytes + int64(len(results))*2*pointerBytes
+		for _, result := range results {
+			stats.ResultBytes += valueBytes(result)
+		}
+	}
+	return stats
+}
+
+// valueBytes estimates the heap size of a value.
+func valueBytes(value types.Type) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case *types.Number:
+		return decimalSize(v.Value)
+	case *types.Currency:
+		return decimalSize(v.Value) + 2*stringBytes + int64(len(v.Symbol)+len(v.Code))
+	case *types.Quantity:
+		return decimalSize(v.Value) + stringBytes + int64(len(v.Unit))
+	case *types.Duration:
+		return decimalSize(v.Value) + stringBytes + int64(len(v.Unit))
+	case *types.Rate:
+		return pointerBytes + valueBytes(v.Amount) + stringBytes + int64(len(v.PerUnit))
+	case *types.List:
+		size := int64(sliceBytes)
+		for _, elem := range v.Elements {
+			size += 2*pointerBytes + valueBytes(elem)
+		}
+		return size
+	case *types.Distribution:
+		size := int64(sliceBytes)
+		for _, sample := range v.Samples {
+			size += 2*pointerBytes + valueBytes(sample)
+		}
+		return size
+	case *types.Table:
+		size := int64(2 * sliceBytes)
+		for _, column := range v.Columns {
+			size += stringBytes + int64(len(column))
+		}
+		for _, row := range v.Rows {
+			size += sliceBytes
+			for _, cell := range row {
+				size += 2*pointerBytes + valueBytes(cell)
+			}
+		}
+		return size
+	case *types.Basket:
+		size := int64(sliceBytes)
+		for _, w := range v.Weights {
+			size += stringBytes + int64(len(w.Code)) + decimalSize(w.Amount)
+		}
+		return size
+	case *types.Date, *types.Time:
+		return timeBytes
+	case *types.Schedule:
+		size := int64(4*8 + stringBytes + pointerBytes) // Every, Weekday, OnDay, Day, Unit, Start
+		if v.Start != nil {
+			size += timeBytes
+		}
+		return size
+	default:
+		return pointerBytes
+	}
+}
+
+// decimalSize estimates the size of d, including its coefficient's words.
+func decimalSize(d decimal.Decimal) int64 {
+	words := (d.Coefficient().BitLen() + 63) / 64
+	return decimalBytes + int64(words)*8
+}
+
+// Limits caps how large a document may grow before the evaluator warns,
+// so long-running editors and servers notice unbounded growth.
+// A zero limit disables that check.
+type Limits struct {
+	MaxVariables int // Variables in the environment, including constants
+	MaxResults   int // Per-statement results held by calculation blocks
+}
+
+// SetLimits sets the size limits checked by CheckLimits after each Evaluate.
+func (e *Evaluator) SetLimits(limits Limits) {
+	e.limits = limits
+}
+
+// CheckLimits returns a warning for each limit doc exceeds, or nil.
+// Evaluate adds these to Diagnostics; incremental callers can check
+// directly after EvaluateAffectedBlocks.
+func (e *Evaluator) CheckLimits(doc *document.Document) []BlockDiagnostic {
+	if e.limits == (Limits{}) {
+		return nil
+	}
+
+	var diags []BlockDiagnostic
+	variables := len(e.env.GetAllVariables())
+	if limit := e.limits.MaxVariables; limit > 0 && variables > limit {
+		diags = append(diags, BlockDiagnostic{
+			Severity: Warning,
+			Code:     DiagSizeLimit,
+			Message:  fmt.Sprintf("document has %d variables (limit %d)", variables, limit),
+		})
+	}
+	if limit := e.limits.MaxResults; limit > 0 {
+		results := 0
+		for _, node := range doc.GetBlocks() {
+			if cb, ok := node.Block.(*document.CalcBlock); ok {
+				results += len(cb.Results())
+			}
+		}
+		if results > limit {
+			diags = append(diags, BlockDiagnostic{
+				Severity: Warning,
+				Code:     DiagSizeLimit,
+				Message:  fmt.Sprintf("document holds %d results (limit %d)", results, limit),
+			})
+		}
+	}
+	return diags
+}
@@ -0,0 +1,75 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestMemoryStats(t *testing.T) {
+	doc, err := document.NewDocument("a = 5\nb = $100\n\n\nc = a * 2\nc + 1\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	stats := eval.MemoryStats(doc)
+	if stats.Results != 4 {
+		t.Errorf("Results = %d, want 4", stats.Results)
+	}
+	// a, b, c plus the built-in constants
+	if stats.Variables < 3 {
+		t.Errorf("Variables = %d, want at least 3", stats.Variables)
+	}
+	if stats.EnvironmentBytes <= 0 || stats.ResultBytes <= 0 {
+		t.Errorf("byte estimates = %d, %d, want positive", stats.EnvironmentBytes, stats.ResultBytes)
+	}
+	if stats.TotalBytes() != stats.EnvironmentBytes+stats.ResultBytes {
+		t.Errorf("TotalBytes() = %d, want the sum", stats.TotalBytes())
+	}
+
+	// Numbers with more digits are estimated larger
+	big := types.NewNumber(decimal.RequireFromString("123456789012345678901234567890123456789.5"))
+	if valueBytes(big) <= valueBytes(types.NewNumber(decimal.NewFromInt(1))) {
+		t.Error("a 40-digit number should be estimated larger than 1")
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	doc, err := document.NewDocument("a = 1\nb = 2\nc = 3\nd = 4\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if diags := eval.CheckLimits(doc); diags != nil {
+		t.Errorf("CheckLimits() without limits = %v, want nil", diags)
+	}
+
+	eval.SetLimits(Limits{MaxResults: 3})
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	var found bool
+	for _, diag := range eval.Diagnostics() {
+		if diag.Code == DiagSizeLimit && strings.Contains(diag.Message, "4 results (limit 3)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Diagnostics() = %v, want a results limit warning", eval.Diagnostics())
+	}
+
+	eval.SetLimits(Limits{MaxVariables: 1000, MaxResults: 4})
+	if diags := eval.CheckLimits(doc); diags != nil {
+		t.Errorf("CheckLimits() within limits = %v, want nil", diags)
+	}
+}
@@ -0,0 +1,98 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// VerifyDivisionPrecision is the number of decimal places used for division
+// during precision verification (the fast path uses
+// interpreter.DefaultDivisionPrecision).
+const VerifyDivisionPrecision = 64
+
+// PrecisionMismatch is a result whose displayed value changes when the
+// document is re-evaluated at full precision.
+type PrecisionMismatch struct {
+	BlockID   string
+	Statement int    // 0-indexed statement within the block
+	Name      string // Assigned variable, empty for bare expressions
+	Fast      string // Displayed value from normal evaluation
+	Precise   string // Displayed value at VerifyDivisionPrecision
+}
+
+// VerifyPrecision re-evaluates the document at VerifyDivisionPrecision and
+// reports every result whose rendered value differs from the block's current
+// (fast-path) results. render is the display formatter, typically display.Format,
+// so only differences a user would actually see are reported.
+//
+// The document must already have been evaluated by e. It is evaluated
+// again as Evaluate does, with the registered functions, imports, and
+// frontmatter, in an environment of its own, so neither the document's
+// blocks nor e change. Blocks that failed, or have no results, are skipped;
+// a block that evaluated but fails at full precision is an error.
+func (e *Evaluator) VerifyPrecision(doc *document.Document, render func(types.Type) string) ([]PrecisionMismatch, error) {
+	p := *e
+	p.divisionPrecision = VerifyDivisionPrecision
+	p.env = p.newEnvironment()
+	if err := p.applyImports(doc); err != nil {
+		return nil, err
+	}
+	if err := doc.ApplyFrontmatter(p.env); err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+
+	var mismatches []PrecisionMismatch
+	for _, node := range doc.GetBlocks() {
+		if block, ok := node.Block.(document.EvaluableBlock); ok {
+			if err := evaluateCustomBlock(block, p.env); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		cb, ok := node.Block.(*document.CalcBlock)
+		if !ok || cb.Error() != nil || len(cb.Results()) == 0 {
+			continue
+		}
+
+		source := strings.Join(cb.Source(), "\n")
+		if !strings.HasSuffix(source, "\n") {
+			source += "\n"
+		}
+		nodes, err := parser.ParseWithLocale(source, doc.NumberLocale())
+		if err != nil {
+			return nil, fmt.Errorf("block %s: %w", node.ID, err)
+		}
+		precise, err := interpreter.NewInterpreterWithEnv(p.env).Eval(nodes)
+		if err != nil {
+			return nil, fmt.Errorf("block %s: %w", node.ID, err)
+		}
+
+		fast := cb.Results()
+		for i := 0; i < len(fast) && i < len(precise); i++ {
+			fastText, preciseText := render(fast[i]), render(precise[i])
+			if fastText == preciseText {
+				continue
+			}
+			mismatch := PrecisionMismatch{
+				BlockID:   node.ID,
+				Statement: i,
+				Fast:      fastText,
+				Precise:   preciseText,
+			}
+			if i < len(nodes) {
+				if assign, ok := nodes[i].(*ast.Assignment); ok {
+					mismatch.Name = assign.Name
+				}
+			}
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	return mismatches, nil
+}
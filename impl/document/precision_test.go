@@ -0,0 +1,88 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// exactString renders values with full decimal digits, so any precision
+// difference is visible.
+func exactString(t types.Type) string {
+	return t.String()
+}
+
+// TestVerifyPrecisionFlagsDifferences tests that precision-sensitive results are reported
+func TestVerifyPrecisionFlagsDifferences(t *testing.T) {
+	doc, err := document.NewDocument("a = 2 + 2\nthird = 1 / 3\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	mismatches, err := eval.VerifyPrecision(doc, exactString)
+	if err != nil {
+		t.Fatalf("VerifyPrecision failed: %v", err)
+	}
+
+	if len(mismatches) != 1 {
+		t.Fatalf("got %d mismatches, want 1: %+v", len(mismatches), mismatches)
+	}
+	m := mismatches[0]
+	if m.Name != "third" || m.Statement != 1 {
+		t.Errorf("mismatch = %+v, want statement 1 (third)", m)
+	}
+	if len(m.Precise) <= len(m.Fast) {
+		t.Errorf("precise value %q should carry more digits than %q", m.Precise, m.Fast)
+	}
+
+	// decimal's package-global precision is left alone
+	if decimal.DivisionPrecision == VerifyDivisionPrecision {
+		t.Error("DivisionPrecision was changed")
+	}
+}
+
+// TestVerifyPrecisionDisplayStable tests that display rounding hides insignificant differences
+func TestVerifyPrecisionDisplayStable(t *testing.T) {
+	doc, err := document.NewDocument("x = 1 / 3 * 3\ny = 1000000 / 3\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	mismatches, err := eval.VerifyPrecision(doc, display.Format)
+	if err != nil {
+		t.Fatalf("VerifyPrecision failed: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected no displayed differences, got %+v", mismatches)
+	}
+}
+
+// TestVerifyPrecisionImports tests that the check evaluates with the
+// document's imports, as Evaluate does, rather than skipping the blocks
+// that use them.
+func TestVerifyPrecisionImports(t *testing.T) {
+	resolver := mapResolver{"shares.cm": "share = 1 / 3\n"}
+	eval, doc, err := evaluateWithImports(t, resolver, "---\nimports:\n  - shares.cm\n---\npart = share * 3\n")
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	mismatches, err := eval.VerifyPrecision(doc, exactString)
+	if err != nil {
+		t.Fatalf("VerifyPrecision failed: %v", err)
+	}
+	if len(mismatches) != 1 || mismatches[0].Name != "part" {
+		t.Errorf("mismatches = %+v, want one for part", mismatches)
+	}
+}
@@ -0,0 +1,50 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// PreviewReplaceBlockSource reports what replacing blockID's source with
+// newSource would change, like document.Document.PreviewReplaceBlockSource,
+// but evaluates the scratch copies the way Evaluate would: with the
+// document's imports, the registered functions, the data policy, and the
+// block timeout. Neither the document nor the evaluator's environment and
+// diagnostics are modified.
+func (e *Evaluator) PreviewReplaceBlockSource(doc *document.Document, blockID string, newSource []string) (*document.PreviewResult, error) {
+	return doc.PreviewReplaceBlockSourceWith(blockID, newSource, e.evaluatePreview)
+}
+
+// evaluatePreview is the document.PreviewEvaluator for PreviewReplaceBlockSource.
+// It evaluates with a copy of the evaluator so the preview leaves e as it was.
+func (e *Evaluator) evaluatePreview(scratch *document.Document) (map[string]types.Type, error) {
+	p := *e
+	p.env = e.newEnvironment()
+	p.diagnostics = nil
+	p.blockDone = nil
+	p.locale = scratch.NumberLocale()
+	scratch.SetEnvExpansion(e.envLookup)
+
+	if err := p.applyImports(scratch); err != nil {
+		return nil, err
+	}
+	if err := scratch.ApplyFrontmatter(p.env); err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+
+	for _, node := range scratch.GetBlocks() {
+		cb, ok := node.Block.(*document.CalcBlock)
+		if !ok {
+			continue
+		}
+		// Errors stay on the block; the preview carries on past them
+		_ = p.evaluateCalcBlock(node.ID, cb)
+		if cb.IsStale() {
+			// A scratch block has no previous results to fall back on
+			cb.SetError(fmt.Errorf("evaluation took longer than %s", e.blockTimeout))
+		}
+	}
+	return p.env.GetAllVariables(), nil
+}
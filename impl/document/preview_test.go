@@ -0,0 +1,53 @@
+package document
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// TestPreviewUsesEvaluatorSettings tests that an evaluator's preview sees its
+// registered functions, which the document's own preview does not.
+func TestPreviewUsesEvaluatorSettings(t *testing.T) {
+	eval := NewEvaluator()
+	if err := eval.RegisterFunction("shipping_cost", shippingCost, shippingSignature); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := document.NewDocument("parcel = 3 kg\n\n\ncost = shipping_cost(parcel, 2)\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	blocks := doc.GetBlocks()
+	parcelID, costID := blocks[0].ID, blocks[len(blocks)-1].ID
+
+	preview, err := eval.PreviewReplaceBlockSource(doc, parcelID, []string{"parcel = 5 kg"})
+	if err != nil {
+		t.Fatalf("PreviewReplaceBlockSource failed: %v", err)
+	}
+	if err := preview.Errors[costID]; err != nil {
+		t.Fatalf("cost block failed in preview: %v", err)
+	}
+	if got := preview.Results[costID]; len(got) != 1 || got[0].String() != "$45.00" {
+		t.Errorf("previewed cost = %v, want $45.00", got)
+	}
+
+	// The preview leaves the evaluator's own values alone
+	if cost, _ := eval.GetEnvironment().Get("cost"); cost.String() != "$35.00" {
+		t.Errorf("cost after preview = %v, want $35.00", cost)
+	}
+
+	// Without the evaluator the function is unknown, so cost fails before
+	// and after the edit and the preview misses its change
+	preview, err = doc.PreviewReplaceBlockSource(parcelID, []string{"parcel = 5 kg"})
+	if err != nil {
+		t.Fatalf("PreviewReplaceBlockSource failed: %v", err)
+	}
+	if slices.Contains(preview.AffectedBlockIDs, costID) {
+		t.Errorf("expected the document's preview not to evaluate shipping_cost, got %v", preview.Results[costID])
+	}
+}
@@ -0,0 +1,145 @@
+package document
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// SensitivityRow is the target's value for one value of the input.
+type SensitivityRow struct {
+	Input  types.Type
+	Output types.Type // Nil when Err is set
+	Err    error      // Evaluation error for this input value
+}
+
+// Sensitivity answers what-if questions: it sets input to each of values in
+// turn and returns the value target takes, one row per value in order.
+//
+// The document must already have been evaluated by e. Only the blocks that
+// depend on input, directly or through other variables, are re-evaluated,
+// on a copy of the environment with their assignments to input left out, so
+// neither the document nor the evaluator changes. An error for one value is
+// reported in its row; Sensitivity itself fails only if the variables are
+// unknown or no block re-evaluated assigns target.
+func (e *Evaluator) Sensitivity(doc *document.Document, target, input string, values []types.Type) ([]SensitivityRow, error) {
+	if _, ok := e.env.Get(input); !ok {
+		return nil, fmt.Errorf("sensitivity: undefined variable %q", input)
+	}
+	if _, ok := e.env.Get(target); !ok {
+		return nil, fmt.Errorf("sensitivity: undefined variable %q", target)
+	}
+
+	// Blocks that assign input may use it in later statements, which the
+	// dependency graph doesn't record, so they rerun along with everything
+	// depending on what they assign
+	var affected []string
+	changed := []string{input}
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok && slices.Contains(cb.Variables(), input) {
+			affected = append(affected, node.ID)
+			changed = append(changed, cb.Variables()...)
+		}
+	}
+	affected = append(affected, doc.GetTransitiveDependents(changed)...)
+
+	var blocks []*document.CalcBlock
+	dependsOnInput := false
+	for _, id := range doc.GetBlocksInDependencyOrder(affected) {
+		node, ok := doc.GetBlock(id)
+		if !ok {
+			continue
+		}
+		if cb, ok := node.Block.(*document.CalcBlock); ok {
+			blocks = append(blocks, cb)
+			dependsOnInput = dependsOnInput || slices.Contains(cb.Variables(), target)
+		}
+	}
+	if target == input || !dependsOnInput {
+		return nil, fmt.Errorf("sensitivity: %s does not depend on %s", target, input)
+	}
+
+	// The same statements run for every value
+	statements := make([][]ast.Node, len(blocks))
+	for i, cb := range blocks {
+		statements[i] = withoutAssignmentTo(cb.Statements(), input)
+	}
+
+	rows := make([]SensitivityRow, len(values))
+	for i, value := range values {
+		rows[i].Input = value
+
+		env := e.env.Clone()
+		env.Set(input, value)
+		for _, nodes := range statements {
+			if _, err := interpreter.NewInterpreterWithEnv(env).Eval(nodes); err != nil {
+				rows[i].Err = err
+				break
+			}
+		}
+		if rows[i].Err == nil {
+			rows[i].Output, _ = env.Get(target)
+		}
+	}
+	return rows, nil
+}
+
+// withoutAssignmentTo returns nodes without the statements assigning name.
+func withoutAssignmentTo(nodes []ast.Node, name string) []ast.Node {
+	return slices.DeleteFunc(slices.Clone(nodes), func(node ast.Node) bool {
+		assign, ok := node.(*ast.Assignment)
+		return ok && assign.Name == name
+	})
+}
+
+// SensitivityRange returns steps values evenly spaced from from to to, both
+// included, for Sensitivity. Both ends must be numbers, amounts of the same
+// currency, or quantities in the same unit.
+func SensitivityRange(from, to types.Type, steps int) ([]types.Type, error) {
+	if steps < 2 {
+		return nil, fmt.Errorf("sensitivity range needs at least 2 steps, got %d", steps)
+	}
+
+	var start, end decimal.Decimal
+	var withValue func(decimal.Decimal) types.Type
+	switch f := from.(type) {
+	case *types.Number:
+		t, ok := to.(*types.Number)
+		if !ok {
+			return nil, fmt.Errorf("sensitivity range from %s to %s: both ends must be numbers", from, to)
+		}
+		start, end = f.Value, t.Value
+		withValue = func(v decimal.Decimal) types.Type { return types.NewNumber(v) }
+	case *types.Currency:
+		t, ok := to.(*types.Currency)
+		if !ok || !f.IsSameCurrency(t) {
+			return nil, fmt.Errorf("sensitivity range from %s to %s: both ends must be in %s", from, to, f.Code)
+		}
+		start, end = f.Value, t.Value
+		withValue = func(v decimal.Decimal) types.Type {
+			return &types.Currency{Value: v, Symbol: f.Symbol, Code: f.Code, RealYear: f.RealYear}
+		}
+	case *types.Quantity:
+		t, ok := to.(*types.Quantity)
+		if !ok || t.Unit != f.Unit {
+			return nil, fmt.Errorf("sensitivity range from %s to %s: both ends must be in %s", from, to, f.Unit)
+		}
+		start, end = f.Value, t.Value
+		withValue = func(v decimal.Decimal) types.Type { return types.NewQuantity(v, f.Unit) }
+	default:
+		return nil, fmt.Errorf("sensitivity range from %s to %s: expected numbers, currency, or quantities", from, to)
+	}
+
+	values := make([]types.Type, steps)
+	step := end.Sub(start).Div(decimal.NewFromInt(int64(steps - 1)))
+	for i := range values {
+		values[i] = withValue(start.Add(step.Mul(decimal.NewFromInt(int64(i)))))
+	}
+	values[steps-1] = withValue(end) // Exact, whatever the step's rounding
+	return values, nil
+}
@@ -0,0 +1,172 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// evaluated returns source as a document, evaluated, with its evaluator.
+func evaluated(t *testing.T, source string) (*Evaluator, *document.Document) {
+	t.Helper()
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	return eval, doc
+}
+
+func TestSensitivity(t *testing.T) {
+	source := `price = $20
+units = 100
+fixed = $500
+
+
+revenue = price * units
+profit = revenue - fixed
+
+
+unrelated = 7
+`
+	eval, doc := evaluated(t, source)
+
+	values, err := SensitivityRange(types.NewNumber(decimal.NewFromInt(50)), types.NewNumber(decimal.NewFromInt(150)), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := eval.Sensitivity(doc, "profit", "units", values)
+	if err != nil {
+		t.Fatalf("Sensitivity failed: %v", err)
+	}
+
+	want := []string{"$500.00", "$1500.00", "$2500.00"}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if row.Err != nil || row.Output.String() != want[i] {
+			t.Errorf("units = %s: profit = %v (error %v), want %s", row.Input, row.Output, row.Err, want[i])
+		}
+	}
+
+	// The document and the evaluator keep their values
+	if profit, _ := eval.GetEnvironment().Get("profit"); profit.String() != "$1500.00" {
+		t.Errorf("evaluator profit = %s after Sensitivity, want $1500.00", profit)
+	}
+	if units, _ := eval.GetEnvironment().Get("units"); units.String() != "100" {
+		t.Errorf("evaluator units = %s after Sensitivity, want 100", units)
+	}
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok && strings.Contains(cb.Source()[0], "revenue") {
+			if got := cb.Results()[1].String(); got != "$1500.00" {
+				t.Errorf("block results changed: profit = %s", got)
+			}
+		}
+	}
+}
+
+// TestSensitivitySameBlock checks the input's own assignment is left out
+// when it shares a block with its dependents.
+func TestSensitivitySameBlock(t *testing.T) {
+	eval, doc := evaluated(t, "rate = 5%\nloan = $1000\ninterest = loan * rate\n")
+
+	rows, err := eval.Sensitivity(doc, "interest", "rate", []types.Type{
+		types.NewNumber(decimal.RequireFromString("0.1")),
+		types.NewNumber(decimal.RequireFromString("0.2")),
+	})
+	if err != nil {
+		t.Fatalf("Sensitivity failed: %v", err)
+	}
+	for i, want := range []string{"$100.00", "$200.00"} {
+		if rows[i].Err != nil || rows[i].Output.String() != want {
+			t.Errorf("rate = %s: interest = %v (error %v), want %s", rows[i].Input, rows[i].Output, rows[i].Err, want)
+		}
+	}
+}
+
+func TestSensitivityRowError(t *testing.T) {
+	eval, doc := evaluated(t, "price = $20\ntotal = price * 3\n")
+
+	rows, err := eval.Sensitivity(doc, "total", "price", []types.Type{
+		types.NewNumber(decimal.NewFromInt(2)),
+		types.NewQuantity(decimal.NewFromInt(2), "kg"),
+		&types.Boolean{Value: true},
+	})
+	if err != nil {
+		t.Fatalf("Sensitivity failed: %v", err)
+	}
+	if rows[0].Err != nil || rows[0].Output.String() != "6" {
+		t.Errorf("price = 2: total = %v (error %v), want 6", rows[0].Output, rows[0].Err)
+	}
+	if rows[2].Err == nil || rows[2].Output != nil {
+		t.Errorf("price = true: want an error, got %v", rows[2].Output)
+	}
+}
+
+func TestSensitivityErrors(t *testing.T) {
+	eval, doc := evaluated(t, "a = 1\nb = a * 2\n\n\nc = 3\n")
+	one := []types.Type{types.NewNumber(decimal.NewFromInt(1))}
+
+	tests := []struct {
+		target, input string
+		want          string
+	}{
+		{"b", "missing", `undefined variable "missing"`},
+		{"missing", "a", `undefined variable "missing"`},
+		{"c", "a", "c does not depend on a"},
+		{"a", "a", "a does not depend on a"},
+	}
+	for _, tt := range tests {
+		_, err := eval.Sensitivity(doc, tt.target, tt.input, one)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Sensitivity(%s, %s) error = %v, want %q", tt.target, tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestSensitivityRange(t *testing.T) {
+	dollars := func(s string) types.Type { return types.NewCurrency(decimal.RequireFromString(s), "$") }
+
+	values, err := SensitivityRange(dollars("10"), dollars("20"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range values {
+		got = append(got, v.String())
+	}
+	if strings.Join(got, " ") != "$10.00 $15.00 $20.00" {
+		t.Errorf("currency range = %v", got)
+	}
+
+	values, err = SensitivityRange(types.NewNumber(decimal.Zero), types.NewNumber(decimal.NewFromInt(1)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[3].String() != "1" {
+		t.Errorf("range should end exactly on its upper bound, got %s", values[3])
+	}
+
+	bad := []struct {
+		from, to types.Type
+		steps    int
+	}{
+		{dollars("1"), dollars("2"), 1},
+		{dollars("1"), types.NewCurrency(decimal.NewFromInt(2), "€"), 3},
+		{types.NewQuantity(decimal.NewFromInt(1), "kg"), types.NewQuantity(decimal.NewFromInt(2), "m"), 3},
+		{types.NewNumber(decimal.Zero), dollars("2"), 3},
+		{&types.Boolean{Value: true}, &types.Boolean{Value: false}, 3},
+	}
+	for _, tt := range bad {
+		if _, err := SensitivityRange(tt.from, tt.to, tt.steps); err == nil {
+			t.Errorf("SensitivityRange(%s, %s, %d) should fail", tt.from, tt.to, tt.steps)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package document
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// BenchmarkEditingSession reassigns a variable that every later block
+// depends on, over and over, as a long editing session would. The
+// environment overwrites bindings in place and blocks keep only their
+// latest results, so the live heap reported as heap-bytes should stay
+// flat however many edits run (compare -benchtime=1000x and 20000x).
+func BenchmarkEditingSession(b *testing.B) {
+	var src strings.Builder
+	src.WriteString("base = 100\n")
+	for i := range 50 {
+		fmt.Fprintf(&src, "\n\nv%d = $1 * base * %d + $%d\n", i, i+1, i)
+	}
+	doc, err := document.NewDocument(src.String())
+	if err != nil {
+		b.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	if err := eval.Evaluate(doc); err != nil {
+		b.Fatalf("Evaluate failed: %v", err)
+	}
+	first := doc.GetBlocks()[0].ID
+
+	b.ResetTimer()
+	i := 0
+	for b.Loop() {
+		i++
+		result, err := doc.ReplaceBlockSource(first, []string{fmt.Sprintf("base = %d", i)})
+		if err != nil {
+			b.Fatalf("ReplaceBlockSource failed: %v", err)
+		}
+		ordered := doc.GetBlocksInDependencyOrder(result.AffectedBlockIDs)
+		if err := eval.EvaluateAffectedBlocks(doc, ordered); err != nil {
+			b.Fatalf("EvaluateAffectedBlocks failed: %v", err)
+		}
+	}
+	b.StopTimer()
+
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	b.ReportMetric(float64(mem.HeapAlloc), "heap-bytes")
+}
@@ -0,0 +1,210 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// SetBlockTimeout limits how long interpreting one CalcBlock may take.
+// A block that runs over keeps its previous statements and results, is
+// marked stale (see CalcBlock.IsStale), and evaluation carries on with its
+// previous values, so one pathological expression cannot stall an editor.
+//
+// The slow run finishes in the background on a copy of the environment.
+// A later evaluation of the same source in an environment of the same
+// generation (see interpreter.Environment.Generation) waits on that run
+// instead of starting another, so retrying with a longer timeout picks up
+// where the first attempt left off. Evaluate starts from a new
+// environment, so only an evaluation carrying on in the same one, such as
+// EvaluateAffectedBlocks with nothing set since, can do that.
+//
+// A run no evaluation is waiting on is cancelled after abandonedRunGrace,
+// or sooner if more than maxAbandonedRuns are left running, so timed-out
+// blocks can't pile up work that never ends.
+//
+// Zero (the default) waits for every block.
+func (e *Evaluator) SetBlockTimeout(d time.Duration) {
+	e.blockTimeout = d
+}
+
+const (
+	// maxAbandonedRuns is the most timed-out runs left going in the
+	// background, across every Evaluator; past it the oldest is cancelled.
+	maxAbandonedRuns = 4
+
+	// abandonedRunGrace is how long a timed-out run goes on with no
+	// evaluation waiting on it before it is cancelled.
+	abandonedRunGrace = 30 * time.Second
+)
+
+// blockRun is one interpretation of a block's source, shared by every
+// evaluation of that source with the same variables while it runs.
+type blockRun struct {
+	key    string
+	cancel context.CancelFunc
+	done   chan struct{} // Closed when the fields below are set
+
+	// Guarded by blockRunsMu
+	waiters int         // Evaluations waiting on the run
+	expire  *time.Timer // Cancels the run while no evaluation waits on it
+
+	env     *interpreter.Environment
+	results []types.Type
+	err     error
+}
+
+var (
+	blockRunsMu   sync.Mutex
+	blockRuns     = make(map[string]*blockRun) // Running interpretations by runKey
+	abandonedRuns []*blockRun                  // Runs no evaluation waits on, oldest first
+)
+
+// interpret evaluates nodes in the environment, giving up after the block
+// timeout. On timeout the environment is unchanged and timedOut is true.
+func (e *Evaluator) interpret(source string, nodes []ast.Node) (results []types.Type, timedOut bool, err error) {
+	if e.blockTimeout <= 0 {
+		results, err = interpreter.NewInterpreterWithEnv(e.env).Eval(nodes)
+		return results, false, err
+	}
+
+	run := startBlockRun(source, nodes, e.env)
+	timer := time.NewTimer(e.blockTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-run.done:
+		// The run may be shared, so take a copy of its environment
+		e.env = run.env.Clone()
+		return run.results, false, run.err
+	case <-timer.C:
+		abandonBlockRun(run)
+		return nil, true, nil
+	}
+}
+
+// startBlockRun interprets nodes on a copy of env in the background, or
+// joins the run already interpreting source with the same variables.
+func startBlockRun(source string, nodes []ast.Node, env *interpreter.Environment) *blockRun {
+	key := runKey(source, env)
+
+	blockRunsMu.Lock()
+	defer blockRunsMu.Unlock()
+	if run, ok := blockRuns[key]; ok {
+		run.waiters++
+		if run.expire != nil {
+			run.expire.Stop()
+			run.expire = nil
+			abandonedRuns = slices.DeleteFunc(abandonedRuns, func(r *blockRun) bool { return r == run })
+		}
+		return run
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &blockRun{key: key, cancel: cancel, done: make(chan struct{}), waiters: 1, env: env.Clone()}
+	blockRuns[key] = run
+	go func() {
+		results, err := interpreter.NewInterpreterWithEnv(run.env).EvalContext(ctx, nodes)
+
+		blockRunsMu.Lock()
+		forgetBlockRun(run)
+		blockRunsMu.Unlock()
+		cancel()
+
+		run.results, run.err = results, err
+		close(run.done)
+	}()
+	return run
+}
+
+// abandonBlockRun stops an evaluation waiting on run. Once none is, run
+// is left to finish for a retry to pick up, within the limits of
+// maxAbandonedRuns and abandonedRunGrace.
+func abandonBlockRun(run *blockRun) {
+	blockRunsMu.Lock()
+	defer blockRunsMu.Unlock()
+	run.waiters--
+	if run.waiters > 0 || blockRuns[run.key] != run {
+		return // Still wanted, or finished
+	}
+	run.expire = time.AfterFunc(abandonedRunGrace, func() {
+		blockRunsMu.Lock()
+		defer blockRunsMu.Unlock()
+		if run.expire != nil {
+			cancelBlockRun(run)
+		}
+	})
+	abandonedRuns = append(abandonedRuns, run)
+	if len(abandonedRuns) > maxAbandonedRuns {
+		cancelBlockRun(abandonedRuns[0])
+	}
+}
+
+// cancelBlockRun stops an abandoned run at its interpreter's next check,
+// removing it so no evaluation joins it. blockRunsMu must be held.
+func cancelBlockRun(run *blockRun) {
+	forgetBlockRun(run)
+	run.cancel()
+}
+
+// forgetBlockRun removes a finished or cancelled run from blockRuns and
+// abandonedRuns. blockRunsMu must be held.
+func forgetBlockRun(run *blockRun) {
+	if blockRuns[run.key] == run {
+		delete(blockRuns, run.key)
+	}
+	if run.expire != nil {
+		run.expire.Stop()
+		run.expire = nil
+	}
+	abandonedRuns = slices.DeleteFunc(abandonedRuns, func(r *blockRun) bool { return r == run })
+}
+
+// runKey identifies a block's source together with the environment it
+// runs in. The generation covers everything that can change a result,
+// such as exchange rates and custom functions, not only the variables.
+func runKey(source string, env *interpreter.Environment) string {
+	return fmt.Sprintf("%d\x00%s", env.Generation(), source)
+}
+
+// keepStale restores a timed-out block's previous statements so its previous
+// results still line up with them, and puts its previous assignments back in
+// the environment for the blocks after it.
+func (e *Evaluator) keepStale(blockID string, block *document.CalcBlock, prevStatements []ast.Node) {
+	block.SetStatements(prevStatements)
+	block.SetStale(true)
+
+	results := block.Results()
+	for i, stmt := range prevStatements {
+		if i >= len(results) {
+			break
+		}
+		if assign, ok := stmt.(*ast.Assignment); ok && results[i] != nil {
+			e.env.Set(assign.Name, results[i])
+		}
+	}
+
+	e.diagnostics = append(e.diagnostics, BlockDiagnostic{
+		BlockID:  blockID,
+		Line:     1,
+		Severity: Warning,
+		Code:     DiagBlockTimeout,
+		Message:  fmt.Sprintf("evaluation took longer than %s; showing previous results", e.blockTimeout),
+		Source:   firstLine(block.Source()),
+	})
+}
+
+// firstLine returns the first line of source, or "" if there is none.
+func firstLine(source []string) string {
+	if len(source) == 0 {
+		return ""
+	}
+	return source[0]
+}
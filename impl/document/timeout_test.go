@@ -0,0 +1,209 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// slowEvaluator returns an evaluator whose documents can call tick(),
+// which takes a few milliseconds and returns how often it has been called.
+func slowEvaluator(t *testing.T, calls *atomic.Int64) *Evaluator {
+	t.Helper()
+	eval := NewEvaluator()
+	tick := func([]types.Type) (types.Type, error) {
+		time.Sleep(5 * time.Millisecond)
+		return types.NewNumber(decimal.NewFromInt(calls.Add(1))), nil
+	}
+	if err := eval.RegisterFunction("tick", tick, semantic.FunctionSignature{}); err != nil {
+		t.Fatal(err)
+	}
+	return eval
+}
+
+// slowSource returns a block calling tick() n times, assigning name.
+func slowSource(name string, n int) string {
+	return strings.Repeat(name+" = tick()\n", n)
+}
+
+func TestBlockTimeoutKeepsPreviousResults(t *testing.T) {
+	doc, err := document.NewDocument("x = 2\n\n\ny = x + 1\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	var calls atomic.Int64
+	eval := slowEvaluator(t, &calls)
+	eval.SetBlockTimeout(time.Second)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	// Takes seconds; the run is abandoned and finishes in the background
+	first := doc.GetBlocks()[0]
+	if _, err := doc.ReplaceBlockSource(first.ID, strings.Split(slowSource("x", 400), "\n")); err != nil {
+		t.Fatalf("ReplaceBlockSource failed: %v", err)
+	}
+	eval.SetBlockTimeout(20 * time.Millisecond)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate with slow block failed: %v", err)
+	}
+
+	slow := first.Block.(*document.CalcBlock)
+	if !slow.IsStale() {
+		t.Fatal("slow block should be stale")
+	}
+	if results := slow.Results(); len(results) != 1 || results[0].String() != "2" {
+		t.Errorf("stale results = %v, want previous [2]", results)
+	}
+
+	// Later blocks still evaluate, using the previous value
+	next := doc.GetBlocks()[1].Block.(*document.CalcBlock)
+	if next.IsStale() || next.LastValue() == nil || next.LastValue().String() != "3" {
+		t.Errorf("y = %v (stale %v), want 3", next.LastValue(), next.IsStale())
+	}
+
+	var found bool
+	for _, diag := range eval.Diagnostics() {
+		if diag.Code == DiagBlockTimeout && diag.BlockID == first.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Diagnostics() = %v, want a %s warning", eval.Diagnostics(), DiagBlockTimeout)
+	}
+}
+
+func TestBlockTimeoutFastBlocks(t *testing.T) {
+	doc, err := document.NewDocument("a = 5\nb = a * 2\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	eval := NewEvaluator()
+	eval.SetBlockTimeout(time.Second)
+	if err := eval.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	block := doc.GetBlocks()[0].Block.(*document.CalcBlock)
+	if block.IsStale() || block.LastValue().String() != "10" {
+		t.Errorf("b = %v (stale %v), want 10", block.LastValue(), block.IsStale())
+	}
+	if val, ok := eval.GetEnvironment().Get("b"); !ok || val.String() != "10" {
+		t.Errorf("environment b = %v, want 10", val)
+	}
+}
+
+// TestVerifyPrecisionDuringTimedOutRun checks VerifyPrecision runs while a
+// timed-out block is still running in the background, without changing
+// the precision that block divides at. Run with -race.
+func TestVerifyPrecisionDuringTimedOutRun(t *testing.T) {
+	// The calls take a moment, then the division reads the precision
+	slow, err := document.NewDocument(slowSource("y", 40) + "y = 1 / 3\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	var calls atomic.Int64
+	eval := slowEvaluator(t, &calls)
+	eval.SetBlockTimeout(time.Millisecond)
+	if err := eval.Evaluate(slow); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if block := slow.GetBlocks()[0].Block.(*document.CalcBlock); !block.IsStale() {
+		t.Fatal("slow block should be stale")
+	}
+
+	doc, err := document.NewDocument("third = 1 / 3\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	verifier := NewEvaluator()
+	if err := verifier.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if mismatches, err := verifier.VerifyPrecision(doc, exactString); err != nil || len(mismatches) != 1 {
+		t.Errorf("VerifyPrecision() = %+v, %v, want one mismatch", mismatches, err)
+	}
+
+	// Wait for the background run to finish
+	eval.SetBlockTimeout(time.Minute)
+	if err := eval.Evaluate(slow); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	block := slow.GetBlocks()[0].Block.(*document.CalcBlock)
+	if block.IsStale() {
+		t.Fatal("block should have finished")
+	}
+	if got := block.LastValue().String(); got != "0.3333333333333333" {
+		t.Errorf("y = %s, want 16 decimal places", got)
+	}
+}
+
+// TestAbandonedRunsCancelled checks that once more than maxAbandonedRuns
+// timed-out blocks are left running, the oldest stops.
+func TestAbandonedRunsCancelled(t *testing.T) {
+	counts := make([]*atomic.Int64, maxAbandonedRuns+1)
+	for i := range counts {
+		counts[i] = new(atomic.Int64)
+		doc, err := document.NewDocument(slowSource(fmt.Sprintf("abandoned%d", i), 1000))
+		if err != nil {
+			t.Fatalf("NewDocument failed: %v", err)
+		}
+		eval := slowEvaluator(t, counts[i])
+		eval.SetBlockTimeout(time.Millisecond)
+		if err := eval.Evaluate(doc); err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+	}
+
+	// The first run stops at its next statement; the last keeps going
+	time.Sleep(50 * time.Millisecond)
+	first, last := counts[0].Load(), counts[maxAbandonedRuns].Load()
+	time.Sleep(100 * time.Millisecond)
+	if n := counts[0].Load(); n != first {
+		t.Errorf("oldest abandoned run went from %d to %d calls, want it cancelled", first, n)
+	}
+	if n := counts[maxAbandonedRuns].Load(); n == last {
+		t.Errorf("newest abandoned run stopped at %d calls, want it still running", n)
+	}
+}
+
+// TestTimedOutRunNotSharedAcrossEnvironments checks that a retry only
+// joins a timed-out run made in the same state, not merely with the same
+// source and variables: here another evaluator's tick() differs.
+func TestTimedOutRunNotSharedAcrossEnvironments(t *testing.T) {
+	source := slowSource("shared", 400)
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	var calls atomic.Int64
+	slow := slowEvaluator(t, &calls)
+	slow.SetBlockTimeout(time.Millisecond)
+	if err := slow.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	doc, err = document.NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	fast := NewEvaluator()
+	tick := func([]types.Type) (types.Type, error) { return types.NewNumber(decimal.NewFromInt(100)), nil }
+	if err := fast.RegisterFunction("tick", tick, semantic.FunctionSignature{}); err != nil {
+		t.Fatal(err)
+	}
+	fast.SetBlockTimeout(time.Second)
+	if err := fast.Evaluate(doc); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if val, ok := fast.GetEnvironment().Get("shared"); !ok || val.String() != "100" {
+		t.Errorf("shared = %v, want 100 from this evaluator's tick()", val)
+	}
+}
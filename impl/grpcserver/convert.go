@@ -0,0 +1,92 @@
+package grpcserver
+
+import (
+	"github.com/CalcMark/go-calcmark/format"
+	calcmarkv1 "github.com/CalcMark/go-calcmark/proto/calcmark/v1"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// blockProto converts the JSON export of the block at index.
+func blockProto(index int, jb format.JSONBlock) *calcmarkv1.Block {
+	b := &calcmarkv1.Block{
+		Index:       int32(index),
+		Type:        jb.Type,
+		Source:      jb.Source,
+		Output:      jb.Output,
+		Error:       jb.Error,
+		Variables:   jb.Variables,
+		Highlight:   jb.Highlight,
+		Diagnostics: diagnosticsProto(jb.Diagnostics),
+	}
+	if m := jb.Metadata; m != nil {
+		b.Metadata = &calcmarkv1.BlockMetadata{Label: m.Label, Tags: m.Tags, Attrs: m.Attrs}
+	}
+	for _, r := range jb.Results {
+		b.Results = append(b.Results, resultProto(r))
+	}
+	for _, d := range jb.DependsOn {
+		b.DependsOn = append(b.DependsOn, &calcmarkv1.Dependency{Block: int32(d.Block), Variable: d.Variable})
+	}
+	return b
+}
+
+// resultProto converts the JSON export of a result.
+func resultProto(r format.JSONResult) *calcmarkv1.Result {
+	out := &calcmarkv1.Result{
+		Line:        int32(r.Line),
+		Source:      r.Source,
+		Variable:    r.Variable,
+		Output:      r.Output,
+		Value:       r.Value,
+		RawValue:    r.RawValue,
+		Type:        r.Type,
+		Unit:        r.Unit,
+		Diagnostics: diagnosticsProto(r.Diagnostics),
+	}
+	if c := r.Currency; c != nil {
+		out.Currency = &calcmarkv1.Currency{Code: c.Code, Symbol: c.Symbol}
+	}
+	if d := r.Distribution; d != nil {
+		out.Distribution = &calcmarkv1.Distribution{
+			Samples: int32(d.Samples),
+			Mean:    d.Mean,
+			P5:      d.P5,
+			P50:     d.P50,
+			P95:     d.P95,
+		}
+	}
+	return out
+}
+
+// diagnosticsProto converts diagnostics from the JSON export.
+func diagnosticsProto(diags []format.JSONDiagnostic) []*calcmarkv1.Diagnostic {
+	var out []*calcmarkv1.Diagnostic
+	for _, d := range diags {
+		out = append(out, &calcmarkv1.Diagnostic{
+			Severity:    d.Severity,
+			Code:        d.Code,
+			Message:     d.Message,
+			Line:        int32(d.Line),
+			Column:      int32(d.Column),
+			Suggestions: d.Suggestions,
+		})
+	}
+	return out
+}
+
+// documentDiagnosticsProto converts document-wide diagnostics from the
+// evaluator, for a stream's summary.
+func documentDiagnosticsProto(diags []document.Diagnostic) []*calcmarkv1.Diagnostic {
+	var out []*calcmarkv1.Diagnostic
+	for _, d := range diags {
+		out = append(out, &calcmarkv1.Diagnostic{
+			Severity:    d.Severity,
+			Code:        d.Code,
+			Message:     d.Message,
+			Line:        int32(d.Line),
+			Column:      int32(d.Column),
+			Suggestions: d.Suggestions,
+		})
+	}
+	return out
+}
@@ -0,0 +1,223 @@
+// Package grpcserver implements the CalcMark gRPC service defined in
+// proto/calcmark/v1, for services embedding CalcMark evaluation:
+//
+//	s := grpc.NewServer()
+//	calcmarkv1.RegisterCalcMarkServer(s, grpcserver.New(grpcserver.Options{}))
+//
+// Responses carry the same results as the JSON export. Documents can't
+// import files, so a server never reads from its disk.
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/format"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	calcmarkv1 "github.com/CalcMark/go-calcmark/proto/calcmark/v1"
+	"github.com/CalcMark/go-calcmark/spec/document"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults for the zero values of Options.
+const (
+	DefaultMaxSourceBytes = 1 << 20 // As the CLI limits files it reads
+	DefaultBlockTimeout   = 5 * time.Second
+)
+
+// Options configures a Server.
+type Options struct {
+	MaxSourceBytes int            // Largest source accepted
+	BlockTimeout   time.Duration  // Time each block's evaluation may take
+	Limits         implDoc.Limits // Size limits that add warnings, as in the editor
+}
+
+// Server implements calcmarkv1.CalcMarkServer. Calls are independent, so
+// one Server can handle any number at once. Clients bound a whole call
+// with a deadline.
+type Server struct {
+	calcmarkv1.UnimplementedCalcMarkServer
+	opts Options
+}
+
+// New creates a server with opts, using the defaults for zero values.
+func New(opts Options) *Server {
+	if opts.MaxSourceBytes <= 0 {
+		opts.MaxSourceBytes = DefaultMaxSourceBytes
+	}
+	if opts.BlockTimeout <= 0 {
+		opts.BlockTimeout = DefaultBlockTimeout
+	}
+	return &Server{opts: opts}
+}
+
+// Evaluate returns every block of a document with its results.
+func (s *Server) Evaluate(ctx context.Context, req *calcmarkv1.EvaluateRequest) (*calcmarkv1.EvaluateResponse, error) {
+	doc, err := s.parse(req.GetSource(), req.GetOverrides())
+	if err != nil {
+		return nil, err
+	}
+	eval := s.newEvaluator()
+	if err := eval.Evaluate(doc); err != nil && !blockFailed(doc) {
+		return nil, status.Errorf(codes.InvalidArgument, "evaluation error: %v", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	jd := (&format.JSONFormatter{}).Document(doc, format.Options{Diagnostics: implDoc.FormatDiagnostics(eval.Diagnostics())})
+	resp := &calcmarkv1.EvaluateResponse{
+		SchemaVersion: int32(jd.SchemaVersion),
+		Diagnostics:   diagnosticsProto(jd.Diagnostics),
+	}
+	for i, jb := range jd.Blocks {
+		resp.Blocks = append(resp.Blocks, blockProto(i, jb))
+	}
+	return resp, nil
+}
+
+// Validate returns a document's problems, as cm check reports them. A
+// document that can't be parsed has one error, on its first line.
+func (s *Server) Validate(ctx context.Context, req *calcmarkv1.ValidateRequest) (*calcmarkv1.ValidateResponse, error) {
+	if err := s.checkSize(req.GetSource()); err != nil {
+		return nil, err
+	}
+	var diags []semantic.Diagnostic
+	if doc, err := document.NewDocument(req.GetSource()); err != nil {
+		diags, _ = implDoc.CheckSource(req.GetSource(), s.newEvaluator())
+	} else {
+		if err := override(doc, req.GetOverrides()); err != nil {
+			return nil, err
+		}
+		diags = implDoc.CheckDocument(doc, req.GetSource(), s.newEvaluator())
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	resp := &calcmarkv1.ValidateResponse{Valid: true}
+	for _, d := range diags {
+		severity := strings.ToLower(d.Severity.String())
+		if severity == "error" {
+			resp.Valid = false
+		}
+		resp.Diagnostics = append(resp.Diagnostics, &calcmarkv1.Diagnostic{
+			Severity:    severity,
+			Code:        d.Code,
+			Message:     d.Message,
+			Line:        int32(d.Range.Start.Line),
+			Column:      int32(d.Range.Start.Column),
+			Suggestions: d.Suggestions,
+		})
+	}
+	return resp, nil
+}
+
+// StreamResults sends each block as soon as it is evaluated, then a
+// summary with the document-wide diagnostics.
+func (s *Server) StreamResults(req *calcmarkv1.EvaluateRequest, stream calcmarkv1.CalcMark_StreamResultsServer) error {
+	doc, err := s.parse(req.GetSource(), req.GetOverrides())
+	if err != nil {
+		return err
+	}
+
+	eval := s.newEvaluator()
+	blocks := format.NewJSONBlocks(doc)
+	sent := 0
+	var sendErr error
+	reported := 0 // Evaluator diagnostics already attached to a block
+	eval.SetBlockCallback(func(node *document.BlockNode) {
+		// Evaluation can't be stopped midway; once the client is gone,
+		// the rest finishes without sending
+		if sendErr != nil {
+			return
+		}
+		var diags []document.Diagnostic
+		for _, d := range implDoc.FormatDiagnostics(eval.Diagnostics()[reported:]) {
+			if d.BlockID == node.ID {
+				diags = append(diags, d)
+			}
+		}
+		reported = len(eval.Diagnostics())
+		block := &calcmarkv1.StreamResultsResponse_Block{Block: blockProto(sent, blocks.Next(node, diags))}
+		sendErr = stream.Send(&calcmarkv1.StreamResultsResponse{Result: block})
+		sent++
+	})
+	if err := eval.Evaluate(doc); err != nil && !blockFailed(doc) {
+		return status.Errorf(codes.InvalidArgument, "evaluation error: %v", err)
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	// What remains is document-wide, such as size limits
+	var summary []document.Diagnostic
+	for _, d := range implDoc.FormatDiagnostics(eval.Diagnostics()[reported:]) {
+		if d.BlockID == "" {
+			summary = append(summary, d)
+		}
+	}
+	return stream.Send(&calcmarkv1.StreamResultsResponse{
+		Result: &calcmarkv1.StreamResultsResponse_Summary{Summary: &calcmarkv1.Summary{
+			SchemaVersion: format.JSONSchemaVersion,
+			Blocks:        int32(sent),
+			Diagnostics:   documentDiagnosticsProto(summary),
+		}},
+	})
+}
+
+// parse parses source and applies overrides to its frontmatter.
+func (s *Server) parse(source string, overrides map[string]string) (*document.Document, error) {
+	if err := s.checkSize(source); err != nil {
+		return nil, err
+	}
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parse error: %v", err)
+	}
+	if err := override(doc, overrides); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// checkSize rejects a source larger than the server accepts.
+func (s *Server) checkSize(source string) error {
+	if len(source) > s.opts.MaxSourceBytes {
+		return status.Errorf(codes.ResourceExhausted, "source exceeds %d bytes", s.opts.MaxSourceBytes)
+	}
+	return nil
+}
+
+// override applies a request's overrides to doc's frontmatter.
+func override(doc *document.Document, overrides map[string]string) error {
+	for name, value := range overrides {
+		if err := doc.Override(name, value); err != nil {
+			return status.Errorf(codes.InvalidArgument, "override %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// newEvaluator returns an evaluator with the server's limits. It has no
+// resolver, so documents can't import files.
+func (s *Server) newEvaluator() *implDoc.Evaluator {
+	eval := implDoc.NewEvaluator()
+	eval.SetBlockTimeout(s.opts.BlockTimeout)
+	eval.SetLimits(s.opts.Limits)
+	return eval
+}
+
+// blockFailed reports whether a calculation block of doc has an error,
+// which Evaluate returns after keeping it on the block.
+func blockFailed(doc *document.Document) bool {
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*document.CalcBlock); ok && cb.Error() != nil {
+			return true
+		}
+	}
+	return false
+}
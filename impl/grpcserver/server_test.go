@@ -0,0 +1,150 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	calcmarkv1 "github.com/CalcMark/go-calcmark/proto/calcmark/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves s in memory and returns a client for it.
+func dial(t *testing.T, s *Server) calcmarkv1.CalcMarkClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	calcmarkv1.RegisterCalcMarkServer(srv, s)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return calcmarkv1.NewCalcMarkClient(conn)
+}
+
+const budget = `---
+globals:
+  rate: 0.25
+---
+price = $20
+tax = price * rate
+
+# Notes
+
+
+total = price + tax
+`
+
+func TestEvaluate(t *testing.T) {
+	client := dial(t, New(Options{}))
+	resp, err := client.Evaluate(context.Background(), &calcmarkv1.EvaluateRequest{
+		Source:    budget,
+		Overrides: map[string]string{"rate": "0.5"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Blocks) != 3 {
+		t.Fatalf("%d blocks", len(resp.Blocks))
+	}
+	last := resp.Blocks[2]
+	if last.Index != 2 || last.Results[0].RawValue != "30" || last.Results[0].Currency.GetCode() != "USD" {
+		t.Errorf("last block = %v", last)
+	}
+	if deps := last.DependsOn; len(deps) != 2 || deps[0].Block != 0 {
+		t.Errorf("depends_on = %v", deps)
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	client := dial(t, New(Options{MaxSourceBytes: 100}))
+	tests := []struct {
+		name string
+		req  *calcmarkv1.EvaluateRequest
+		code codes.Code
+	}{
+		{"too large", &calcmarkv1.EvaluateRequest{Source: strings.Repeat("x = 1\n", 20)}, codes.ResourceExhausted},
+		{"unknown override", &calcmarkv1.EvaluateRequest{Source: "x = 1\n", Overrides: map[string]string{"y": "2"}}, codes.InvalidArgument},
+		{"imports", &calcmarkv1.EvaluateRequest{Source: "---\nimports: [rates.cm]\n---\nx = 1\n"}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Evaluate(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("err = %v, want %s", err, tt.code)
+			}
+		})
+	}
+
+	// Errors within blocks are results, not failures
+	resp, err := client.Evaluate(context.Background(), &calcmarkv1.EvaluateRequest{Source: "a = 1/0\n"})
+	if err != nil || !strings.Contains(resp.Blocks[0].Error, "division by zero") {
+		t.Errorf("resp = %v, err = %v", resp, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	client := dial(t, New(Options{}))
+	resp, err := client.Validate(context.Background(), &calcmarkv1.ValidateRequest{Source: "x = 1\ny = x + missing\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Valid || len(resp.Diagnostics) == 0 || resp.Diagnostics[0].Line != 2 {
+		t.Errorf("resp = %v", resp)
+	}
+
+	resp, err = client.Validate(context.Background(), &calcmarkv1.ValidateRequest{Source: budget})
+	if err != nil || !resp.Valid {
+		t.Errorf("resp = %v, err = %v", resp, err)
+	}
+}
+
+func TestStreamResults(t *testing.T) {
+	client := dial(t, New(Options{Limits: implDoc.Limits{MaxResults: 2}}))
+	stream, err := client.StreamResults(context.Background(), &calcmarkv1.EvaluateRequest{Source: budget})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var blocks []*calcmarkv1.Block
+	var summary *calcmarkv1.Summary
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary != nil {
+			t.Fatal("message after the summary")
+		}
+		if b := msg.GetBlock(); b != nil {
+			blocks = append(blocks, b)
+		}
+		summary = msg.GetSummary()
+	}
+
+	if len(blocks) != 3 || blocks[2].Results[0].RawValue != "25" || blocks[2].DependsOn[0].Block != 0 {
+		t.Fatalf("blocks = %v", blocks)
+	}
+	if summary == nil || summary.Blocks != 3 {
+		t.Fatalf("summary = %v", summary)
+	}
+	if len(summary.Diagnostics) != 1 || summary.Diagnostics[0].Code != implDoc.DiagSizeLimit {
+		t.Errorf("summary diagnostics = %v", summary.Diagnostics)
+	}
+}
@@ -0,0 +1,139 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Variadic aggregate functions: sum, min, max, median, stdev.
+// These follow the same unit handling as avg(): arguments may be numbers or
+// currencies, and the result is a plain Number.
+
+// evalSum calculates the sum of numbers.
+func evalSum(args []types.Type) (types.Type, error) {
+	numbers, err := extractAggregateArgs("sum", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	total := decimal.Zero
+	for _, n := range numbers {
+		total = total.Add(n)
+	}
+
+	return types.NewNumber(total), nil
+}
+
+// evalMin returns the smallest argument.
+func evalMin(args []types.Type) (types.Type, error) {
+	numbers, err := extractAggregateArgs("min", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewNumber(decimal.Min(numbers[0], numbers[1:]...)), nil
+}
+
+// evalMax returns the largest argument.
+func evalMax(args []types.Type) (types.Type, error) {
+	numbers, err := extractAggregateArgs("max", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewNumber(decimal.Max(numbers[0], numbers[1:]...)), nil
+}
+
+// evalMedian returns the middle value, or the mean of the two middle values
+// when the argument count is even.
+func evalMedian(args []types.Type) (types.Type, error) {
+	numbers, err := extractAggregateArgs("median", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(numbers, func(a, b decimal.Decimal) int {
+		return a.Cmp(b)
+	})
+
+	mid := len(numbers) / 2
+	if len(numbers)%2 == 1 {
+		return types.NewNumber(numbers[mid]), nil
+	}
+
+	median := numbers[mid-1].Add(numbers[mid]).Div(decimal.NewFromInt(2))
+	return types.NewNumber(median), nil
+}
+
+// evalStdev calculates the sample standard deviation (n-1 denominator),
+// matching the STDEV function found in spreadsheets.
+func evalStdev(args []types.Type) (types.Type, error) {
+	numbers, err := extractAggregateArgs("stdev", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	count := decimal.NewFromInt(int64(len(numbers)))
+	sum := decimal.Zero
+	for _, n := range numbers {
+		sum = sum.Add(n)
+	}
+	mean := sum.Div(count)
+
+	squares := decimal.Zero
+	for _, n := range numbers {
+		diff := n.Sub(mean)
+		squares = squares.Add(diff.Mul(diff))
+	}
+	variance := squares.Div(count.Sub(decimal.NewFromInt(1)))
+
+	// decimal has no sqrt; float64 precision matches sqrt()
+	f, _ := variance.Float64()
+	return types.NewNumber(decimal.NewFromFloat(math.Sqrt(f))), nil
+}
+
+// isAggregateFunction reports whether name is a variadic aggregate function
+// whose list arguments are flattened into individual values.
+func isAggregateFunction(name string) bool {
+	switch name {
+	case "avg", "average", "sum", "min", "max", "median", "stdev":
+		return true
+	default:
+		return false
+	}
+}
+
+// flattenLists replaces each List argument with its elements, so that
+// avg(prices) and avg(10, 20, 30) are equivalent.
+func flattenLists(args []types.Type) []types.Type {
+	flat := make([]types.Type, 0, len(args))
+	for _, arg := range args {
+		if list, ok := arg.(*types.List); ok {
+			flat = append(flat, flattenLists(list.Elements)...)
+			continue
+		}
+		flat = append(flat, arg)
+	}
+	return flat
+}
+
+// extractAggregateArgs validates the argument count for an aggregate function
+// and extracts the numeric values.
+func extractAggregateArgs(name string, args []types.Type, minArgs int) ([]decimal.Decimal, error) {
+	if len(args) < minArgs {
+		if minArgs == 1 {
+			return nil, fmt.Errorf("%s() requires at least one argument", name)
+		}
+		return nil, fmt.Errorf("%s() requires at least %d arguments", name, minArgs)
+	}
+
+	numbers, err := extractNumbers(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s() %w", name, err)
+	}
+	return numbers, nil
+}
@@ -0,0 +1,110 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestAggregateFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"sum of numbers", "sum(1, 2, 3)\n", "6"},
+		{"sum of one", "sum(42)\n", "42"},
+		{"sum of currencies", "sum($10, $20)\n", "30"},
+		{"min", "min(5, 2, 8)\n", "2"},
+		{"min negative", "min(-5, 2)\n", "-5"},
+		{"max", "max(5, 2, 8)\n", "8"},
+		{"max mixed currencies", "max($100, €200)\n", "200"},
+		{"median odd", "median(3, 1, 2)\n", "2"},
+		{"median even", "median(4, 1, 3, 2)\n", "2.5"},
+		{"stdev", "stdev(2, 4, 4, 4, 5, 5, 7, 9)\n", "2.138089935299395"},
+		{"stdev identical", "stdev(5, 5, 5)\n", "0"},
+		{"nested", "sum(max(1, 2), min(3, 4))\n", "5"},
+		{"sum of list", "sum([10, 20, 30])\n", "60"},
+		{"avg of list", "avg([10, 20, 30])\n", "20"},
+		{"list mixed with values", "max([1, 5], 3)\n", "5"},
+		{"nested lists", "sum([1, [2, 3]])\n", "6"},
+		{"stdev of list", "stdev([5, 5, 5])\n", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			interp := interpreter.NewInterpreter()
+			results, err := interp.Eval(nodes)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+
+			if len(results) == 0 {
+				t.Fatal("No results returned")
+			}
+
+			actual := results[0].String()
+			if actual != tt.expected {
+				t.Errorf("Result = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregateFunctionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"sum no args", "sum()\n"},
+		{"min no args", "min()\n"},
+		{"median no args", "median()\n"},
+		{"stdev one arg", "stdev(1)\n"},
+		{"sum of dates", "sum(Jan 1 2025)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				return
+			}
+
+			interp := interpreter.NewInterpreter()
+			_, err = interp.Eval(nodes)
+			if err == nil {
+				t.Errorf("Expected error for %q but got none", tt.input)
+			}
+		})
+	}
+}
+
+func TestListVariableAggregation(t *testing.T) {
+	input := "prices = [10, 20, 30]\ntotal = sum(prices)\nmean = avg(prices)\n"
+
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	results, err := interpreter.NewInterpreter().Eval(nodes)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	expected := []string{"[10, 20, 30]", "60", "20"}
+	if len(results) != len(expected) {
+		t.Fatalf("got %d results, want %d", len(results), len(expected))
+	}
+	for i, want := range expected {
+		if got := results[i].String(); got != want {
+			t.Errorf("result %d = %s, want %s", i, got, want)
+		}
+	}
+}
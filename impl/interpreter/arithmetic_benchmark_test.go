@@ -0,0 +1,24 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+// BenchmarkNumberArithmetic evaluates the kind of small-number arithmetic
+// typical documents are made of, which the Number fast path handles
+// without big-integer math.
+func BenchmarkNumberArithmetic(b *testing.B) {
+	nodes, err := parser.Parse("a = 12\nb = a * 3 + 7\nc = b / 4 - a\nd = (a + b + c) * 1.5\ne = d - 2.25 * a\n")
+	if err != nil {
+		b.Fatalf("Parse failed: %v", err)
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := NewInterpreter().Eval(nodes); err != nil {
+			b.Fatalf("Eval failed: %v", err)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package interpreter
 import (
 	"fmt"
 
-	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 )
@@ -46,14 +45,6 @@ func calculateDowntime(availability, timePeriod types.Type) (*types.Duration, er
 		}
 		periodDuration = d
 
-	case *ast.Identifier:
-		// Bare identifier like "month", "year" - treat as 1 unit
-		d, err := types.NewDuration(decimal.NewFromInt(1), period.Name)
-		if err != nil {
-			return nil, fmt.Errorf("downtime() time period must be a time unit, got %s", period.Name)
-		}
-		periodDuration = d
-
 	default:
 		return nil, fmt.Errorf("downtime() time period must be a duration or time unit, got %T", timePeriod)
 	}
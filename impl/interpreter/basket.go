@@ -0,0 +1,90 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Currency baskets: a bare currency code is one unit of that currency, so
+// "0.6 * USD + 0.4 * EUR" is a weighted basket. Baskets add and subtract
+// with each other and with currencies, scale by numbers, and take a value
+// only when converted into one currency: "basket in EUR".
+
+// evalCurrencyUnit evaluates a bare currency code to a basket of one unit.
+func (interp *Interpreter) evalCurrencyUnit(c *ast.CurrencyUnit) (types.Type, error) {
+	return types.NewBasket(types.NormalizeCurrencyCode(c.Code), decimal.NewFromInt(1)), nil
+}
+
+// isBasket reports whether either value is a basket.
+func isBasket(left, right types.Type) bool {
+	_, l := left.(*types.Basket)
+	_, r := right.(*types.Basket)
+	return l || r
+}
+
+// asBasket returns v as a basket if it is a basket or a currency.
+func asBasket(v types.Type) (*types.Basket, bool) {
+	switch b := v.(type) {
+	case *types.Basket:
+		return b, true
+	case *types.Currency:
+		return types.BasketOf(b), true
+	}
+	return nil, false
+}
+
+// evalBasketOperation applies operator to a basket and another value,
+// dividing to precision decimal places.
+func evalBasketOperation(left, right types.Type, operator string, precision int32) (types.Type, error) {
+	leftBasket, leftOK := asBasket(left)
+	rightBasket, rightOK := asBasket(right)
+
+	switch operator {
+	case "+":
+		if leftOK && rightOK {
+			return leftBasket.Add(rightBasket), nil
+		}
+	case "-":
+		if leftOK && rightOK {
+			return leftBasket.Add(rightBasket.Scale(decimal.NewFromInt(-1))), nil
+		}
+	case "*":
+		if num, ok := right.(*types.Number); ok && leftOK {
+			return leftBasket.Scale(num.Value), nil
+		}
+		if num, ok := left.(*types.Number); ok && rightOK {
+			return rightBasket.Scale(num.Value), nil
+		}
+	case "/":
+		if num, ok := right.(*types.Number); ok && leftOK {
+			if num.Value.IsZero() {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return leftBasket.DivRound(num.Value, precision), nil
+		}
+	}
+	return nil, unsupportedOperationError(left, right, operator)
+}
+
+// evalBasketConversion values a basket in the target currency, converting
+// each weight at the document's exchange rates.
+func (interp *Interpreter) evalBasketConversion(basket *types.Basket, targetCode string) (types.Type, error) {
+	target := types.NormalizeCurrencyCode(targetCode)
+	if !types.IsCurrencyCode(target) && (len(target) != 3 || strings.ToUpper(target) != target) {
+		return nil, fmt.Errorf("a currency basket converts only to a currency, got '%s'", targetCode)
+	}
+
+	total := types.NewCurrency(decimal.Zero, types.GetCurrencySymbol(target))
+	for _, w := range basket.Weights {
+		converted, err := interp.evalCurrencyConversion(types.NewCurrency(w.Amount, w.Code), target)
+		if err != nil {
+			return nil, err
+		}
+		total.Value = total.Value.Add(converted.(*types.Currency).Value)
+	}
+	return total, nil
+}
@@ -0,0 +1,82 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// evalBaskets evaluates input with USD, EUR, and GBP rates defined.
+func evalBaskets(t *testing.T, input string) (*interpreter.Environment, error) {
+	t.Helper()
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	env := interpreter.NewEnvironment()
+	env.SetExchangeRate("EUR", "USD", decimal.RequireFromString("1.1"))
+	env.SetExchangeRate("GBP", "USD", decimal.RequireFromString("1.25"))
+	env.SetExchangeRate("USD", "EUR", decimal.RequireFromString("0.9"))
+	env.SetExchangeRate("GBP", "EUR", decimal.RequireFromString("1.15"))
+	_, err = interpreter.NewInterpreterWithEnv(env).Eval(nodes)
+	return env, err
+}
+
+func TestBasketConversion(t *testing.T) {
+	env, err := evalBaskets(t, `index = 0.6 * USD + 0.3 * EUR + 0.1 * GBP
+exposure = index * 1000
+in_usd = exposure in USD
+in_eur = index in EUR
+cash = $500 + 200 * EUR - $100
+cash_usd = cash in USD
+`)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"index", "0.6 USD + 0.3 EUR + 0.1 GBP"},
+		{"exposure", "600 USD + 300 EUR + 100 GBP"},
+		{"in_usd", "$1055.00"}, // 600 + 300 * 1.1 + 100 * 1.25
+		{"in_eur", "€0.96"},    // 0.6 * 0.9 + 0.3 + 0.1 * 1.15
+		{"cash", "400 USD + 200 EUR"},
+		{"cash_usd", "$620.00"},
+	}
+	for _, tt := range tests {
+		if v, _ := env.Get(tt.name); v == nil || v.String() != tt.want {
+			t.Errorf("%s = %v, want %s", tt.name, v, tt.want)
+		}
+	}
+
+	v, _ := env.Get("index")
+	if value := v.ToValue(); value.Kind != types.KindBasket || len(value.Items) != 3 || value.Items[1].CurrencyCode != "EUR" {
+		t.Errorf("index.ToValue() = %+v", value)
+	}
+}
+
+func TestBasketErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"x = USD * EUR", "cannot multiply"},
+		{"x = USD / 0", "division by zero"},
+		{"x = USD + 1", "cannot add"},
+		{"x = (USD + EUR) > $1", "cannot compare a currency basket"},
+		{"x = (USD + EUR) in meters", "converts only to a currency"},
+		{"x = (USD + 2 * JPY) in EUR", "no exchange rate defined for JPY → EUR"},
+	}
+	for _, tt := range tests {
+		_, err := evalBaskets(t, tt.input)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want it to contain %q", tt.input, err, tt.want)
+		}
+	}
+}
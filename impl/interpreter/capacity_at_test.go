@@ -428,13 +428,13 @@ func TestCapacityAtFunction_EndToEnd(t *testing.T) {
 		{
 			name:          "throughput: GB/s to Mbps",
 			input:         "10 GB/s at 100 Mbps per connection\n",
-			expectedValue: "859", // 10 GiB/s = 85,899 Mbps → ceil(85899/100) = 859
+			expectedValue: "800", // 10 GB/s = 80,000 Mbps → 80000/100 = 800
 			expectedUnit:  "connection",
 		},
 		{
 			name:          "throughput: 1 GB/s to Gbps",
 			input:         "1 GB/s at 1 Gbps per link\n",
-			expectedValue: "9", // 1 GiB/s ≈ 8.59 Gbps → ceil(8.59/1) = 9
+			expectedValue: "8", // 1 GB/s = 8 Gbps → 8/1 = 8
 			expectedUnit:  "link",
 		},
 	}
@@ -210,10 +210,10 @@ func isRateUnit(unit string) bool {
 // then normalizes for time units.
 //
 // Example: 10 GB/s vs 100 Mbps
-//   - 10 GB (binary) = 10 × 1024³ × 8 bits = 85,899,345,920 bits
-//   - Per second: 85,899,345,920 bps
+//   - 10 GB = 10 × 10^9 × 8 bits = 80,000,000,000 bits
+//   - Per second: 80,000,000,000 bps
 //   - 100 Mbps = 100 × 10^6 bits/s = 100,000,000 bps
-//   - Result: 85,899,345,920 / 100,000,000 ≈ 859
+//   - Result: 80,000,000,000 / 100,000,000 = 800
 func normalizeRateToThroughput(rate *types.Rate, throughput *types.Quantity) (demandValue, capacityValue decimal.Decimal) {
 	// Convert rate's amount to bits (base unit) using the registry
 	var amountInBits float64
@@ -0,0 +1,169 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/CalcMark/go-calcmark/spec/units"
+	"github.com/shopspring/decimal"
+)
+
+// Compound unit arithmetic: multiplying and dividing quantities whose units
+// combine or cancel.
+//
+//	120 km / 2 hours     → 60 km/h        (quantity / duration = rate)
+//	60 km/h * 2 hours    → 120 km         (rate * duration = quantity)
+//	10 kg / 2 liters     → 5 kg/L         (compound quantity)
+//	5 kg/L * 3 liters    → 15 kg          (denominator cancels)
+//	100 km / 50 km       → 2              (same dimension = ratio)
+//	10 GB / 100 Mbps     → 14.3 minute    (data / bandwidth = time)
+//	100 Mbps * 1 minute  → 6000 Mbit      (bandwidth * time = data)
+
+// evalQuantityProduct handles quantity * quantity and quantity / quantity,
+// dividing to precision decimal places.
+func evalQuantityProduct(left, right *types.Quantity, operator string, precision int32) (types.Type, error) {
+	switch operator {
+	case "/":
+		if right.Value.IsZero() {
+			return nil, fmt.Errorf("division by zero")
+		}
+		// Data / bandwidth: how long the transfer takes
+		if units.IsBandwidthUnit(right.Unit) && !units.IsBandwidthUnit(left.Unit) {
+			if bits, err := units.ConvertDataSize(left.Value, left.Unit, "bit"); err == nil {
+				bps, _ := units.ConvertDataSize(right.Value, right.Unit, "bps")
+				return humanDuration(bits.DivRound(bps, precision)), nil
+			}
+		}
+		// Same dimension: the units cancel, leaving a plain ratio
+		if converted, err := convertQuantity(right, left.Unit); err == nil {
+			if converted.Value.IsZero() {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return types.NewNumber(left.Value.DivRound(converted.Value, precision)), nil
+		}
+		return &types.Quantity{
+			Value: left.Value.DivRound(right.Value, precision),
+			Unit:  units.Compound(left.Unit, right.Unit),
+		}, nil
+	case "*":
+		if result, ok := cancelDenominator(left, right); ok {
+			return result, nil
+		}
+		if result, ok := cancelDenominator(right, left); ok {
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported quantity operation: %s", operator)
+}
+
+// cancelDenominator multiplies a compound quantity by a quantity in (or
+// convertible to) its denominator unit, returning the numerator quantity.
+func cancelDenominator(compound, other *types.Quantity) (types.Type, bool) {
+	numerator, denominator, ok := units.SplitCompound(compound.Unit)
+	if !ok {
+		return nil, false
+	}
+
+	value := other.Value
+	if !units.SameUnit(denominator, other.Unit) {
+		converted, err := convertQuantity(other, denominator)
+		if err != nil {
+			return nil, false
+		}
+		value = converted.Value
+	}
+
+	return amountOf(compound.Value.Mul(value), numerator), true
+}
+
+// evalRateDuration handles rate * duration (and duration * rate),
+// accumulating the rate over the duration.
+func evalRateDuration(rate *types.Rate, dur *types.Duration) (types.Type, error) {
+	total, err := accumulateRate(rate, dur.Value, dur.Unit)
+	if err != nil {
+		return nil, err
+	}
+	return amountOf(total.Value, total.Unit), nil
+}
+
+// evalPerDuration handles amount / duration, producing a rate per the
+// duration's unit. The amount may be a quantity, currency, or plain number.
+// Division rounds to precision decimal places.
+func evalPerDuration(amount types.Type, dur *types.Duration, precision int32) (types.Type, error) {
+	if dur.Value.IsZero() {
+		return nil, fmt.Errorf("division by zero")
+	}
+
+	var value decimal.Decimal
+	var unit string
+	switch a := amount.(type) {
+	case *types.Quantity:
+		value, unit = a.Value, a.Unit
+	case *types.Currency:
+		value, unit = a.Value, a.Symbol
+	case *types.Number:
+		value = a.Value
+	default:
+		return nil, fmt.Errorf("cannot divide %s by duration", formatTypeForError(amount))
+	}
+
+	return types.NewRate(&types.Quantity{Value: value.DivRound(dur.Value, precision), Unit: unit}, dur.Unit), nil
+}
+
+// evalBandwidthDuration handles bandwidth * duration, returning the data
+// transferred in the bandwidth's bit unit (100 Mbps * 10 s = 1000 Mbit).
+func evalBandwidthDuration(bandwidth *types.Quantity, dur *types.Duration) (types.Type, error) {
+	unit := units.BandwidthDataUnit(bandwidth.Unit)
+	return &types.Quantity{Value: bandwidth.Value.Mul(dur.ToSeconds()), Unit: unit}, nil
+}
+
+// evalQuantityPerRate handles quantity / rate, returning how long the rate
+// takes to reach the quantity (10 GB / 125 MB/s = 1.37 minute), dividing
+// to precision decimal places.
+func evalQuantityPerRate(qty *types.Quantity, rate *types.Rate, precision int32) (types.Type, error) {
+	converted, err := convertQuantity(qty, rate.Amount.Unit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot divide %s by %s: %w", qty.String(), rate.String(), err)
+	}
+	if rate.Amount.Value.IsZero() {
+		return nil, fmt.Errorf("division by zero")
+	}
+	perSeconds, err := types.TimeUnitToSeconds(rate.PerUnit)
+	if err != nil {
+		return nil, err
+	}
+	return humanDuration(converted.Value.DivRound(rate.Amount.Value, precision).Mul(perSeconds)), nil
+}
+
+// humanDuration returns seconds as a duration in seconds, minutes, or hours,
+// whichever keeps the value readable (same thresholds as transfer_time).
+func humanDuration(seconds decimal.Decimal) *types.Duration {
+	switch {
+	case seconds.LessThan(decimal.NewFromInt(60)):
+		return &types.Duration{Value: seconds, Unit: "second"}
+	case seconds.LessThan(decimal.NewFromInt(3600)):
+		return &types.Duration{Value: seconds.Div(decimal.NewFromInt(60)), Unit: "minute"}
+	default:
+		return &types.Duration{Value: seconds.Div(decimal.NewFromInt(3600)), Unit: "hour"}
+	}
+}
+
+// evalCurrencyPerQuantity handles currency / quantity (e.g., $10 / 2 kg → 5 $/kg),
+// dividing to precision decimal places.
+func evalCurrencyPerQuantity(cur *types.Currency, qty *types.Quantity, precision int32) (types.Type, error) {
+	if qty.Value.IsZero() {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return &types.Quantity{
+		Value: cur.Value.DivRound(qty.Value, precision),
+		Unit:  units.Compound(cur.Symbol, qty.Unit),
+	}, nil
+}
+
+// amountOf returns a currency for currency units and a quantity otherwise.
+func amountOf(value decimal.Decimal, unit string) types.Type {
+	if types.IsCurrencyCode(unit) {
+		return types.NewCurrency(value, unit)
+	}
+	return &types.Quantity{Value: value, Unit: unit}
+}
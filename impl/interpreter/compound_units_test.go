@@ -0,0 +1,119 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestCompoundUnitArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantType string
+		want     string
+	}{
+		{"rate times duration", "60 km/h * 2 hours\n", "*types.Quantity", "120 km"},
+		{"rate times other time unit", "speed = 60 km/h\nspeed * 30 minutes\n", "*types.Quantity", "30 km"},
+		{"data rate times duration", "10 GB/s * 1 minute\n", "*types.Quantity", "600 GB"},
+		{"currency rate times duration", "$50/hour * 3 hours\n", "*types.Currency", "$150.00"},
+		{"quantity per duration", "120 km / 2 hours\n", "*types.Rate", "60 km/h"},
+		{"currency per duration", "$300 / 2 days\n", "*types.Rate", "150 $/day"},
+		{"same unit ratio", "100 km / 50 km\n", "*types.Number", "2"},
+		{"convertible unit ratio", "1 km / 500 m\n", "*types.Number", "2"},
+		{"compound quantity", "10 kg / 2 liters\n", "*types.Quantity", "5 kg/l"},
+		{"denominator cancels", "d = 10 kg / 2 liters\nd * 3 liters\n", "*types.Quantity", "15 kg"},
+		{"denominator cancels after conversion", "d = 10 kg / 2 liters\n500 ml * d\n", "*types.Quantity", "2.5 kg"},
+		{"price per unit", "p = $10 / 2 kg\np * 3 kg\n", "*types.Currency", "$15.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			results, err := NewInterpreter().Eval(nodes)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+
+			result := results[len(results)-1]
+			if got := fmt.Sprintf("%T", result); got != tt.wantType {
+				t.Errorf("Expected %s, got %s (%v)", tt.wantType, got, result)
+			}
+			if result.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, result.String())
+			}
+		})
+	}
+}
+
+func TestCompoundUnitErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"units do not cancel", "5 kg * 3 meters\n", "unsupported"},
+		{"division by zero quantity", "10 kg / 0 liters\n", "division by zero"},
+		{"division by zero duration", "10 km / 0 hours\n", "division by zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			_, err = NewInterpreter().Eval(nodes)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDataSizeArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantType string
+		want     string
+	}{
+		{"IEC to SI", "1 GiB in MB\n", "*types.Quantity", "1073.741824 MB"},
+		{"IEC to IEC", "1 GiB in MiB\n", "*types.Quantity", "1024 MiB"},
+		{"bits to bytes", "1 Gbit in MB\n", "*types.Quantity", "125 MB"},
+		{"bytes to bits", "1 KiB in bits\n", "*types.Quantity", "8192 bits"},
+		{"transfer time", "10 GB / 100 Mbps\n", "*types.Duration", "13.3333333333333333 minute"},
+		{"transfer time over byte rate", "r = 125 MB/s\n10 GB / r\n", "*types.Duration", "1.3333333333333333 minute"},
+		{"bandwidth times duration", "100 Mbps * 1 minute\n", "*types.Quantity", "6000 Mbit"},
+		{"duration times bandwidth", "2 seconds * 1 Gbps\n", "*types.Quantity", "2 Gbit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			results, err := NewInterpreter().Eval(nodes)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+
+			result := results[len(results)-1]
+			if got := fmt.Sprintf("%T", result); got != tt.wantType {
+				t.Errorf("Expected %s, got %s (%v)", tt.wantType, got, result)
+			}
+			if result.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, result.String())
+			}
+		})
+	}
+}
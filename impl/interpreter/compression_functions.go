@@ -23,7 +23,7 @@ var compressionRatios = map[string]float64{
 // Time Complexity: O(1) - map lookup + arithmetic
 //
 // Examples:
-//   - compress(1 GB, gzip) → ~341 MB (1024/3 ≈ 341)
+//   - compress(1 GB, gzip) → ~333 MB (1000/3 ≈ 333)
 //   - compress(100 MB, lz4) → 50 MB (100/2)
 //   - compress(500 MB, none) → 500 MB (no compression)
 func calculateCompression(size *types.Quantity, compressionType string) (*types.Quantity, error) {
@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/CalcMark/go-calcmark/spec/units"
+	"github.com/shopspring/decimal"
+)
+
+// unitConversion is a resolved conversion between two unit names.
+type unitConversion struct {
+	dataSize bool            // Exact data-size conversion (units.ConvertDataSize)
+	linear   bool            // Multiply by factor
+	factor   decimal.Decimal // Source units per target unit, for linear conversions
+	from, to UnitInfo        // Base-unit functions, for offset conversions (temperature)
+	err      error           // Why the units don't convert
+}
+
+// unitFactors maps each linear registry unit (lowercase) to the size of
+// one unit in its category's base unit, e.g. "feet" → 0.3048. Built at
+// init alongside unitRegistry; offset units such as temperatures are absent.
+var unitFactors map[string]decimal.Decimal
+
+// conversionMatrix caches resolved conversions between registry units by
+// their lowercase [from, to] names. It holds only pairs of units in the
+// same category, so it is bounded by the registry, under ten thousand
+// pairs, however many unit names documents write; other pairs resolve to
+// an error each time.
+var conversionMatrix sync.Map // [2]string → unitConversion
+
+// buildUnitFactors derives the factor table from the registry's
+// conversion functions. A unit is linear when zero maps to zero.
+func buildUnitFactors(registry map[string]UnitInfo) map[string]decimal.Decimal {
+	factors := make(map[string]decimal.Decimal, len(registry))
+	for name, info := range registry {
+		if info.ToBaseUnit(0) != 0 {
+			continue
+		}
+		factors[name] = decimal.NewFromFloat(info.ToBaseUnit(1))
+	}
+	return factors
+}
+
+// lookupConversion returns the conversion from one unit to another,
+// resolving and caching it on first use.
+func lookupConversion(from, to string) unitConversion {
+	// Data sizes convert exactly (no float rounding), e.g. 1 GiB in MB
+	if src, ok := units.LookupDataSize(from); ok {
+		if dst, ok := units.LookupDataSize(to); ok && src.PerSecond == dst.PerSecond {
+			return unitConversion{dataSize: true}
+		}
+	}
+
+	sourceNorm := strings.ToLower(from)
+	targetNorm := strings.ToLower(to)
+	key := [2]string{sourceNorm, targetNorm}
+	if c, ok := conversionMatrix.Load(key); ok {
+		return c.(unitConversion)
+	}
+
+	sourceInfo, sourceOk := GetUnitInfo(sourceNorm)
+	targetInfo, targetOk := GetUnitInfo(targetNorm)
+
+	if !sourceOk || !targetOk {
+		// One or both are arbitrary units - cannot convert
+		return unitConversion{err: fmt.Errorf("cannot convert %s to %s (incompatible units)", from, to)}
+	}
+	if sourceInfo.Category != targetInfo.Category {
+		return unitConversion{err: fmt.Errorf("cannot convert %s to %s (different unit types: %s vs %s)",
+			from, to, sourceInfo.Category, targetInfo.Category)}
+	}
+
+	c := unitConversion{from: sourceInfo, to: targetInfo}
+	sourceFactor, sourceLinear := unitFactors[sourceNorm]
+	targetFactor, targetLinear := unitFactors[targetNorm]
+	if sourceLinear && targetLinear {
+		c = unitConversion{linear: true, factor: sourceFactor.Div(targetFactor)}
+	}
+	conversionMatrix.Store(key, c)
+	return c
+}
+
+// apply converts value with c.
+func (c unitConversion) apply(value decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	switch {
+	case c.err != nil:
+		return decimal.Zero, c.err
+	case c.dataSize:
+		return units.ConvertDataSize(value, from, to)
+	case c.linear:
+		return value.Mul(c.factor), nil
+	}
+	// Offset conversion: source -> base unit -> target
+	v, _ := value.Float64()
+	return decimal.NewFromFloat(c.to.FromBaseUnit(c.from.ToBaseUnit(v))), nil
+}
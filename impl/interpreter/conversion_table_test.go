@@ -0,0 +1,88 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestConversionTable(t *testing.T) {
+	tests := []struct {
+		from, to string
+		value    string
+		want     string
+	}{
+		{"feet", "meters", "10", "3.048"},       // Linear factor, exact
+		{"Miles", "km", "1", "1.609344"},        // Case-insensitive names
+		{"celsius", "fahrenheit", "100", "212"}, // Offset conversion, in floating point
+		{"GiB", "MiB", "1.5", "1536"},           // Exact data size
+	}
+	for _, tt := range tests {
+		qty := &types.Quantity{Value: decimal.RequireFromString(tt.value), Unit: tt.from}
+		// Twice, so the second conversion comes from the table
+		for range 2 {
+			got, err := convertQuantity(qty, tt.to)
+			if err != nil {
+				t.Fatalf("%s %s in %s: %v", tt.value, tt.from, tt.to, err)
+			}
+			if !got.Value.Round(9).Equal(decimal.RequireFromString(tt.want)) || got.Unit != tt.to {
+				t.Errorf("%s %s in %s = %s %s, want %s %s", tt.value, tt.from, tt.to, got.Value, got.Unit, tt.want, tt.to)
+			}
+		}
+	}
+
+	qty := &types.Quantity{Value: decimal.NewFromInt(1), Unit: "kg"}
+	for range 2 {
+		if _, err := convertQuantity(qty, "meters"); err == nil {
+			t.Error("expected error converting kg to meters")
+		}
+	}
+}
+
+// TestConversionTableBounded tests that only pairs of registry units are
+// cached, by lowercase name, so unit names as written can't grow it.
+func TestConversionTableBounded(t *testing.T) {
+	count := func() int {
+		n := 0
+		conversionMatrix.Range(func(any, any) bool { n++; return true })
+		return n
+	}
+
+	qty := &types.Quantity{Value: decimal.NewFromInt(1), Unit: "Furlongs"}
+	if _, err := convertQuantity(qty, "widgets"); err == nil {
+		t.Fatal("expected error converting furlongs to widgets")
+	}
+	before := count()
+	for _, unit := range []string{"FEET", "Feet", "fEEt", "feet"} {
+		qty := &types.Quantity{Value: decimal.NewFromInt(1), Unit: unit}
+		if _, err := convertQuantity(qty, "Meters"); err != nil {
+			t.Fatalf("%s in Meters: %v", unit, err)
+		}
+		if _, err := convertQuantity(qty, "gadgets"); err == nil {
+			t.Fatalf("expected error converting %s to gadgets", unit)
+		}
+	}
+	if added := count() - before; added > 1 {
+		t.Errorf("cache grew by %d entries, want at most 1 for feet → meters", added)
+	}
+}
+
+func TestExchangeRateUpdates(t *testing.T) {
+	env := NewEnvironment()
+	env.SetExchangeRate("usd", "eur", decimal.RequireFromString("0.92"))
+
+	rate, ok := env.GetExchangeRate("USD", "EUR")
+	if !ok || !rate.Equal(decimal.RequireFromString("0.92")) {
+		t.Fatalf("GetExchangeRate = %s, %v; want 0.92", rate, ok)
+	}
+
+	// A refreshed rate replaces the earlier one
+	env.SetExchangeRate("USD", "EUR", decimal.RequireFromString("0.95"))
+	if rate, _ := env.GetExchangeRate("usd", "eur"); !rate.Equal(decimal.RequireFromString("0.95")) {
+		t.Errorf("after update, rate = %s, want 0.95", rate)
+	}
+	if _, ok := env.GetExchangeRate("EUR", "USD"); ok {
+		t.Error("inverse rate should not be defined")
+	}
+}
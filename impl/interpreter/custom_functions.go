@@ -0,0 +1,46 @@
+package interpreter
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// Function is a custom function an embedding application adds to the
+// language, such as shipping_cost(weight, zone). It receives the
+// evaluated arguments; lists are passed as lists.
+type Function func(args []types.Type) (types.Type, error)
+
+// SetFunction makes fn callable as name in this environment and those
+// cloned from it. Built-in functions take precedence over it.
+func (e *Environment) SetFunction(name string, fn Function) {
+	// Clones share the map, so replace it rather than add to it
+	functions := make(map[string]Function, len(e.functions)+1)
+	maps.Copy(functions, e.functions)
+	functions[name] = fn
+	e.functions = functions
+	e.touch()
+}
+
+// Function returns the custom function set as name, if any.
+func (e *Environment) Function(name string) (Function, bool) {
+	fn, ok := e.functions[name]
+	return fn, ok
+}
+
+// callFunction calls the custom function name, or fails as unknown.
+func (interp *Interpreter) callFunction(name string, args []types.Type) (types.Type, error) {
+	fn, ok := interp.env.Function(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+	result, err := fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", name, err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("%s() returned no value", name)
+	}
+	return result, nil
+}
@@ -0,0 +1,366 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// MaxDataFileSize is the largest file lookup() and jsonpath() will read.
+const MaxDataFileSize = 10 * 1024 * 1024 // 10MB
+
+// DataPolicy controls which files lookup() and jsonpath() may read, so a
+// document can only pull reference data from where its evaluator allows.
+// Without a policy, or with no allowed paths, reading files is disabled.
+type DataPolicy struct {
+	AllowedPaths []string // Files, or directories whose files, may be read
+	BaseDir      string   // Relative file names resolve against it; "" for the working directory
+}
+
+// SetDataPolicy sets which files lookup() and jsonpath() may read in this
+// environment and those cloned from it; nil disables reading files.
+func (e *Environment) SetDataPolicy(policy *DataPolicy) {
+	e.dataPolicy = policy
+	e.touch()
+}
+
+// resolve returns the real path of the file name, or an error if the
+// policy doesn't allow reading it.
+func (p *DataPolicy) resolve(name string) (string, error) {
+	if p == nil || len(p.AllowedPaths) == 0 {
+		return "", errors.New("reading files is disabled; allow their paths in the evaluator's data policy")
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.BaseDir, path)
+	}
+	real, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot open '%s'", name)
+	}
+	// Follow links, so a link can't lead outside the allowed paths
+	if linked, err := filepath.EvalSymlinks(real); err == nil {
+		real = linked
+	}
+	for _, allowed := range p.AllowedPaths {
+		root, err := filepath.Abs(allowed)
+		if err == nil {
+			root, err = filepath.EvalSymlinks(root)
+		}
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, real)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return real, nil
+		}
+	}
+	return "", fmt.Errorf("'%s' is outside the allowed paths", name)
+}
+
+// readDataFile reads the file a data function's first argument names.
+func (interp *Interpreter) readDataFile(f *ast.FunctionCall) ([]byte, string, error) {
+	name, ok := f.Arguments[0].(*ast.StringLiteral)
+	if !ok {
+		return nil, "", fmt.Errorf("%s() file must be quoted text, as in \"prices.csv\"", f.Name)
+	}
+	path, err := interp.env.dataPolicy.resolve(name.Value)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s(): %w", f.Name, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s(): cannot open '%s'", f.Name, name.Value)
+	}
+	if info.IsDir() {
+		return nil, "", fmt.Errorf("%s(): '%s' is a directory", f.Name, name.Value)
+	}
+	if info.Size() > MaxDataFileSize {
+		return nil, "", fmt.Errorf("%s(): '%s' is too large: %d bytes (max %d)", f.Name, name.Value, info.Size(), MaxDataFileSize)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s(): cannot read '%s'", f.Name, name.Value)
+	}
+	return content, name.Value, nil
+}
+
+// evalLookup handles lookup(file, key_column, key, value_column): the
+// value_column cell of the first CSV row whose key_column cell is key.
+// The file's first row names its columns.
+func (interp *Interpreter) evalLookup(f *ast.FunctionCall) (types.Type, error) {
+	if len(f.Arguments) != 4 {
+		return nil, fmt.Errorf("lookup() requires exactly 4 arguments (file, key_column, key, value_column)")
+	}
+	keyColumn, ok := columnName(f.Arguments[1])
+	if !ok {
+		return nil, fmt.Errorf("lookup() key_column must be a column name")
+	}
+	valueColumn, ok := columnName(f.Arguments[3])
+	if !ok {
+		return nil, fmt.Errorf("lookup() value_column must be a column name")
+	}
+	matches, key, err := interp.lookupKey(f.Arguments[2])
+	if err != nil {
+		return nil, err
+	}
+
+	content, file, err := interp.readDataFile(f)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("lookup(): '%s' is not valid CSV: %w", file, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("lookup(): '%s' is empty", file)
+	}
+
+	header := rows[0]
+	keyIndex, valueIndex := columnIndex(header, keyColumn), columnIndex(header, valueColumn)
+	for _, missing := range []struct {
+		name  string
+		index int
+	}{{keyColumn, keyIndex}, {valueColumn, valueIndex}} {
+		if missing.index < 0 {
+			return nil, fmt.Errorf("lookup(): '%s' has no column '%s' (columns: %s)", file, missing.name, strings.Join(header, ", "))
+		}
+	}
+	for _, row := range rows[1:] {
+		if keyIndex >= len(row) || !matches(strings.TrimSpace(row[keyIndex])) {
+			continue
+		}
+		if valueIndex >= len(row) {
+			break
+		}
+		value, err := parseDataValue(row[valueIndex])
+		if err != nil {
+			return nil, fmt.Errorf("lookup(): %s for %s in '%s': %w", valueColumn, key, file, err)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("lookup(): no row in '%s' has %s %s", file, keyColumn, key)
+}
+
+// lookupKey returns how lookup() matches key cells against its key
+// argument, and the key for messages. Quoted text matches cells exactly;
+// a number matches cells with the same numeric value.
+func (interp *Interpreter) lookupKey(arg ast.Node) (func(cell string) bool, string, error) {
+	if text, ok := arg.(*ast.StringLiteral); ok {
+		return func(cell string) bool { return cell == text.Value }, strconv.Quote(text.Value), nil
+	}
+	val, err := interp.evalNode(arg)
+	if err != nil {
+		return nil, "", err
+	}
+	if num, ok := val.(*types.Number); ok {
+		return func(cell string) bool {
+			d, err := decimal.NewFromString(cell)
+			return err == nil && d.Equal(num.Value)
+		}, num.String(), nil
+	}
+	key := val.String()
+	return func(cell string) bool { return cell == key }, key, nil
+}
+
+// columnName returns the column a lookup() argument names, as quoted text
+// or a bare name.
+func columnName(arg ast.Node) (string, bool) {
+	switch a := arg.(type) {
+	case *ast.StringLiteral:
+		return a.Value, true
+	case *ast.Identifier:
+		return a.Name, true
+	}
+	return "", false
+}
+
+// columnIndex returns the index of the header naming column, ignoring
+// case and surrounding space, or -1.
+func columnIndex(header []string, column string) int {
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i
+		}
+	}
+	return -1
+}
+
+// evalJSONPath handles jsonpath(file, path): the value at path in a JSON
+// file, e.g. "$.rates.usd" or "$.items[0].price". Arrays become lists.
+func (interp *Interpreter) evalJSONPath(f *ast.FunctionCall) (types.Type, error) {
+	if len(f.Arguments) != 2 {
+		return nil, fmt.Errorf("jsonpath() requires exactly 2 arguments (file, path)")
+	}
+	path, ok := f.Arguments[1].(*ast.StringLiteral)
+	if !ok {
+		return nil, fmt.Errorf("jsonpath() path must be quoted text, as in \"$.rates.usd\"")
+	}
+	steps, err := parseJSONPath(path.Value)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath(): %w", err)
+	}
+
+	content, file, err := interp.readDataFile(f)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("jsonpath(): '%s' is not valid JSON: %w", file, err)
+	}
+
+	for _, step := range steps {
+		switch v := value.(type) {
+		case map[string]any:
+			field, ok := v[step.field]
+			if step.isIndex || !ok {
+				return nil, fmt.Errorf("jsonpath(): '%s' has nothing at %s", file, path.Value)
+			}
+			value = field
+		case []any:
+			if !step.isIndex || step.index < 0 || step.index >= len(v) {
+				return nil, fmt.Errorf("jsonpath(): '%s' has nothing at %s", file, path.Value)
+			}
+			value = v[step.index]
+		default:
+			return nil, fmt.Errorf("jsonpath(): '%s' has nothing at %s", file, path.Value)
+		}
+	}
+
+	result, err := jsonValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath(): %s in '%s': %w", path.Value, file, err)
+	}
+	return result, nil
+}
+
+// jsonPathStep is one step of a JSON path: an object field or array index.
+type jsonPathStep struct {
+	field   string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the JSONPath subset jsonpath() supports: "$"
+// followed by .field, ['field'], or [index] steps.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("path %q must start with '$'", path)
+	}
+	var steps []jsonPathStep
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			field := rest[1 : end+1]
+			if field == "" || field == "*" || field == "." {
+				return nil, fmt.Errorf("path %q: expected a field name after '.'", path)
+			}
+			steps = append(steps, jsonPathStep{field: field})
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q: missing ']'", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			if quoted := len(inner) >= 2 && (inner[0] == '\'' && inner[len(inner)-1] == '\''); quoted {
+				steps = append(steps, jsonPathStep{field: inner[1 : len(inner)-1]})
+			} else if index, err := strconv.Atoi(inner); err == nil {
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("path %q: unsupported selector [%s]; use a field or index", path, inner)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q: expected '.' or '[' at %q", path, rest)
+		}
+	}
+	return steps, nil
+}
+
+// jsonValue converts a decoded JSON value to a CalcMark value. Strings
+// are read as CalcMark values, so "$12.50" is money and "5 kg" a quantity.
+func jsonValue(value any) (types.Type, error) {
+	switch v := value.(type) {
+	case json.Number:
+		d, err := decimal.NewFromString(v.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", v)
+		}
+		return types.NewNumber(d), nil
+	case string:
+		return parseDataValue(v)
+	case bool:
+		return types.NewBoolean(v), nil
+	case []any:
+		if len(v) == 0 {
+			return nil, errors.New("the list is empty")
+		}
+		elements := make([]types.Type, len(v))
+		for i, elem := range v {
+			converted, err := jsonValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = converted
+		}
+		return types.NewList(elements), nil
+	case nil:
+		return nil, errors.New("the value is null")
+	default:
+		return nil, errors.New("an object is not a value; select one of its fields")
+	}
+}
+
+// parseDataValue reads a value from a data file: a number such as 12.5,
+// or any CalcMark literal such as $12.50, 5 kg, or 3 days.
+func parseDataValue(text string) (types.Type, error) {
+	text = strings.TrimSpace(text)
+	if d, err := decimal.NewFromString(text); err == nil {
+		return types.NewNumber(d), nil
+	}
+	nodes, err := parser.Parse(text + "\n")
+	if err != nil || len(nodes) != 1 || !isDataLiteral(nodes[0]) {
+		return nil, fmt.Errorf("'%s' is not a value", text)
+	}
+	return NewInterpreter().evalNode(nodes[0])
+}
+
+// isDataLiteral reports whether node is a literal value, optionally
+// negated, rather than an expression that could refer to variables.
+func isDataLiteral(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Expression:
+		return isDataLiteral(n.Expr)
+	case *ast.UnaryOp:
+		return isDataLiteral(n.Operand)
+	case *ast.NumberLiteral, *ast.CurrencyLiteral, *ast.QuantityLiteral,
+		*ast.RateLiteral, *ast.BooleanLiteral, *ast.DateLiteral,
+		*ast.DurationLiteral, *ast.TimeLiteral:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package interpreter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+// writeDataFiles writes reference data into a new directory and returns it.
+func writeDataFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"prices.csv": "sku, name, price\nA-100,Widget,$12.50\nB-200,\"Gadget, large\",30\n7,Seven,5 kg\n",
+		"rates.json": `{"usd": {"eur": 0.92, "fee": "$2.50"}, "tiers": [10, 20, 30], "items": [{"weight": "3 kg"}], "empty": null}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func evalData(policy *interpreter.DataPolicy, input string) (string, error) {
+	nodes, err := parser.Parse(input + "\n")
+	if err != nil {
+		return "", err
+	}
+	env := interpreter.NewEnvironment()
+	env.SetDataPolicy(policy)
+	results, err := interpreter.NewInterpreterWithEnv(env).Eval(nodes)
+	if err != nil {
+		return "", err
+	}
+	return results[len(results)-1].String(), nil
+}
+
+func TestDataFunctions(t *testing.T) {
+	dir := writeDataFiles(t)
+	policy := &interpreter.DataPolicy{AllowedPaths: []string{dir}, BaseDir: dir}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"lookup by text", `lookup("prices.csv", sku, "A-100", price)`, "$12.50"},
+		{"lookup by number", `lookup("prices.csv", "SKU", 7, "price")`, "5 kg"},
+		{"lookup in arithmetic", `lookup("prices.csv", sku, "B-200", price) * 2`, "60"},
+		{"lookup by variable", "key = 7\nlookup(\"prices.csv\", sku, key, price)", "5 kg"},
+		{"jsonpath number", `jsonpath("rates.json", "$.usd.eur")`, "0.92"},
+		{"jsonpath string value", `jsonpath("rates.json", "$['usd']['fee']")`, "$2.50"},
+		{"jsonpath index", `jsonpath("rates.json", "$.items[0].weight")`, "3 kg"},
+		{"jsonpath list", `sum(jsonpath("rates.json", "$.tiers"))`, "60"},
+		{"absolute path", `jsonpath("` + filepath.Join(dir, "rates.json") + `", "$.tiers[2]")`, "30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := evalData(policy, tt.input)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Result = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDataFunctionErrors(t *testing.T) {
+	dir := writeDataFiles(t)
+	policy := &interpreter.DataPolicy{AllowedPaths: []string{dir}, BaseDir: dir}
+
+	outside := filepath.Join(t.TempDir(), "secret.csv")
+	if err := os.WriteFile(outside, []byte("k,v\na,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link.csv")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		policy  *interpreter.DataPolicy
+		input   string
+		wantErr string
+	}{
+		{"disabled by default", nil, `lookup("prices.csv", sku, "A-100", price)`, "reading files is disabled"},
+		{"no allowed paths", &interpreter.DataPolicy{BaseDir: dir}, `jsonpath("rates.json", "$.tiers")`, "reading files is disabled"},
+		{"outside allowed paths", policy, `lookup("../secret.csv", k, "a", v)`, "outside the allowed paths"},
+		{"absolute path outside", policy, `lookup("` + outside + `", k, "a", v)`, "outside the allowed paths"},
+		{"link outside", policy, `lookup("link.csv", k, "a", v)`, "outside the allowed paths"},
+		{"missing file", policy, `lookup("missing.csv", sku, "A-100", price)`, "cannot open"},
+		{"missing key", policy, `lookup("prices.csv", sku, "Z-999", price)`, `no row in 'prices.csv' has sku "Z-999"`},
+		{"missing column", policy, `lookup("prices.csv", sku, "A-100", cost)`, "has no column 'cost' (columns: sku, name, price)"},
+		{"text cell", policy, `lookup("prices.csv", sku, "A-100", name)`, "'Widget' is not a value"},
+		{"missing field", policy, `jsonpath("rates.json", "$.usd.gbp")`, "nothing at $.usd.gbp"},
+		{"index out of range", policy, `jsonpath("rates.json", "$.tiers[3]")`, "nothing at $.tiers[3]"},
+		{"object", policy, `jsonpath("rates.json", "$.usd")`, "an object is not a value"},
+		{"null", policy, `jsonpath("rates.json", "$.empty")`, "the value is null"},
+		{"unsupported path", policy, `jsonpath("rates.json", "$.tiers[*]")`, "unsupported selector [*]"},
+		{"path without root", policy, `jsonpath("rates.json", "usd.eur")`, "must start with '$'"},
+		{"unquoted file", policy, `lookup(prices, sku, "A-100", price)`, "file must be quoted text"},
+		{"text elsewhere", policy, `sum("prices.csv")`, "is not a value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evalData(tt.policy, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -22,9 +22,9 @@ func TestDataRateUnitConversions(t *testing.T) {
 	}{
 		// ========== BASIC DATA SIZE ADDITION ==========
 		{
-			name:          "GB + MB (binary)",
+			name:          "GB + MB (decimal)",
 			input:         "1 GB + 1 MB\n",
-			expectedValue: "1.0009765625",
+			expectedValue: "1.001",
 			expectedUnit:  "GB",
 		},
 		{
@@ -37,15 +37,15 @@ func TestDataRateUnitConversions(t *testing.T) {
 		// ========== CAPACITY WITH RATE UNITS ==========
 		// Rate-to-Rate conversions (same time unit, different data units)
 		{
-			name:          "GB/s at MB/s (binary byte rates)",
+			name:          "GB/s at MB/s (decimal byte rates)",
 			input:         "1 GB/s at 100 MB/s per connection\n",
-			expectedValue: "11", // 1024 MB / 100 MB = 10.24 → ceil = 11
+			expectedValue: "10", // 1000 MB / 100 MB = 10
 			expectedUnit:  "connection",
 		},
 		{
 			name:          "GiB/s at MiB/s (explicit binary)",
 			input:         "1 GiB/s at 100 MiB/s per pipe\n",
-			expectedValue: "11", // Same as above
+			expectedValue: "11", // 1024 MiB / 100 MiB = 10.24 → ceil = 11
 			expectedUnit:  "pipe",
 		},
 
@@ -53,19 +53,19 @@ func TestDataRateUnitConversions(t *testing.T) {
 		{
 			name:          "1 GB/s at 100 Mbps (byte rate vs bit rate)",
 			input:         "1 GB/s at 100 Mbps per connection\n",
-			expectedValue: "86", // 1 GiB/s = 8,589,934,592 bps / 100M = 85.9 → 86
+			expectedValue: "80", // 1 GB/s = 8,000,000,000 bps / 100M = 80
 			expectedUnit:  "connection",
 		},
 		{
 			name:          "10 GB/s at 1 Gbps",
 			input:         "10 GB/s at 1 Gbps per link\n",
-			expectedValue: "86", // 10 GiB/s ≈ 85.9 Gbps → 86
+			expectedValue: "80", // 10 GB/s = 80 Gbps → 80
 			expectedUnit:  "link",
 		},
 		{
 			name:          "1 GB/s at 1 Gbps",
 			input:         "1 GB/s at 1 Gbps per link\n",
-			expectedValue: "9", // 1 GiB/s ≈ 8.59 Gbps → 9
+			expectedValue: "8", // 1 GB/s = 8 Gbps → 8
 			expectedUnit:  "link",
 		},
 
@@ -93,13 +93,13 @@ func TestDataRateUnitConversions(t *testing.T) {
 		{
 			name:          "TB at GB per disk (conversion)",
 			input:         "1 TB at 100 GB per disk\n",
-			expectedValue: "11", // 1024 GB / 100 GB = 10.24 → 11
+			expectedValue: "10", // 1000 GB / 100 GB = 10
 			expectedUnit:  "disk",
 		},
 		{
 			name:          "PB at TB per server",
 			input:         "1 PB at 1 TB per server\n",
-			expectedValue: "1024",
+			expectedValue: "1000",
 			expectedUnit:  "server",
 		},
 
@@ -107,13 +107,13 @@ func TestDataRateUnitConversions(t *testing.T) {
 		{
 			name:          "GB/s at Mbps with buffer",
 			input:         "1 GB/s at 100 Mbps per connection with 10% buffer\n",
-			expectedValue: "95", // 86 * 1.1 = 94.6 → 95
+			expectedValue: "88", // 80 * 1.1 = 88
 			expectedUnit:  "connection",
 		},
 		{
 			name:          "TB/day at GB/day with buffer",
 			input:         "1 TB/day at 100 GB/day per worker with 20% buffer\n",
-			expectedValue: "13", // (1024/100) * 1.2 = 12.29 → 13
+			expectedValue: "12", // (1000/100) * 1.2 = 12
 			expectedUnit:  "worker",
 		},
 	}
@@ -176,11 +176,11 @@ func TestDataSizeUnitRegistry(t *testing.T) {
 		{"GiB", "gib", 1, 8 * 1024 * 1024 * 1024},
 		{"TiB", "tib", 1, 8 * 1024 * 1024 * 1024 * 1024},
 
-		// KB/MB/GB aliased to binary
-		{"KB", "kb", 1, 8 * 1024},
-		{"MB", "mb", 1, 8 * 1024 * 1024},
-		{"GB", "gb", 1, 8 * 1024 * 1024 * 1024},
-		{"TB", "tb", 1, 8 * 1024 * 1024 * 1024 * 1024},
+		// Decimal byte prefixes (1000-based)
+		{"KB", "kb", 1, 8 * 1000},
+		{"MB", "mb", 1, 8 * 1000 * 1000},
+		{"GB", "gb", 1, 8 * 1000 * 1000 * 1000},
+		{"TB", "tb", 1, 8 * 1000 * 1000 * 1000 * 1000},
 
 		// Bit prefixes (1000-based)
 		{"bit", "bit", 1, 1},
@@ -241,7 +241,7 @@ func TestByteBitConversions(t *testing.T) {
 		{
 			name:          "MB to Mbit",
 			input:         "1 MB + 1 Mbit\n",
-			expectedValue: "1.11920928955078125", // 1 MiB (8388608 bits) + 1 Mbit (1000000 bits) = 9388608 bits = ~1.119 MiB
+			expectedValue: "1.125", // 1 MB (8000000 bits) + 1 Mbit (1000000 bits) = 9000000 bits = 1.125 MB
 			expectedUnit:  "MB",
 		},
 	}
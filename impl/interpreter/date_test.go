@@ -385,12 +385,7 @@ func TestDateDifference(t *testing.T) {
 }
 
 // TestTimeLiterals tests time literal parsing and evaluation.
-// NOTE: Standalone time expressions like "10:30" don't produce results
-// because they're parsed as ratio expressions (10 divided by 30).
-// Time literals work in context (e.g., "meeting at 10:30AM").
 func TestTimeLiterals(t *testing.T) {
-	t.Skip("Standalone time literals parse as division; need context-aware testing")
-
 	tests := []struct {
 		name       string
 		input      string
@@ -402,6 +397,10 @@ func TestTimeLiterals(t *testing.T) {
 		{"10:30AM", "t = 10:30AM\n", 10, 30},
 		{"10:30PM", "t = 10:30PM\n", 22, 30},
 		{"12:00PM", "t = 12:00PM\n", 12, 0}, // Noon
+		{"12:00AM", "t = 12:00AM\n", 0, 0}, // Midnight
+		{"3pm", "t = 3pm\n", 15, 0},
+		{"with zone", "t = 9:15 Tokyo\n", 9, 15},
+		{"with offset", "t = 10:30:45 PM UTC+5:30\n", 22, 30},
 	}
 
 	for _, tt := range tests {
@@ -592,3 +591,160 @@ func TestDateStringFormat(t *testing.T) {
 		})
 	}
 }
+
+// TestBusinessDays tests date arithmetic in business days, which skips
+// weekends and the environment's holidays.
+func TestBusinessDays(t *testing.T) {
+	us, err := types.NewRegionCalendar("US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		calendar *types.Calendar
+		want     string // Date as 2006-01-02, or the duration's String
+		wantErr  string
+	}{
+		{"over a weekend", "d = Jan 16 2026 + 1 business day\n", nil, "2026-01-19", ""},
+		{"over a holiday", "d = Jan 16 2026 + 1 business day\n", us, "2026-01-20", ""},
+		{"subtract", "d = Jan 20 2026 - 2 business days\n", us, "2026-01-15", ""},
+		{"from syntax", "d = 3 workdays from Jan 16 2026\n", us, "2026-01-22", ""},
+		{"count", "n = workdays between Jan 5 2026 and Feb 20 2026\n", us, "33 business day", ""},
+		{"count without holidays", "n = workdays between Jan 5 2026 and Feb 20 2026\n", nil, "35 business day", ""},
+		{"calendar argument", "n = workdays(Jan 1 2026, Jan 31 2026, UK)\n", us, "21 business day", ""},
+		{"day rate", "cost = $500/day * 10 business days\n", nil, "$5000.00", ""},
+		{"fractional", "d = Jan 5 2026 + 5 business days / 2\n", nil, "", "whole"},
+		{"unknown calendar", "n = workdays(Jan 1 2026, Jan 31 2026, Narnia)\n", nil, "", "unknown holiday calendar"},
+		{"not dates", "n = workdays(1, 2)\n", nil, "", "must be dates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := NewInterpreter()
+			interp.GetEnvironment().SetCalendar(tt.calendar)
+
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			results, err := interp.Eval(nodes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval error = %v", err)
+			}
+
+			got := results[0].String()
+			if date, ok := results[0].(*types.Date); ok {
+				got = date.Format(time.DateOnly)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSchedules tests recurring schedules and the next, count, and quarter
+// queries over them.
+func TestSchedules(t *testing.T) {
+	paydays := "paydays = every 2nd Friday from Jan 9 2026\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string // Date as 2006-01-02, or the result's String
+		wantErr string
+	}{
+		{"schedule", paydays, "every 2nd Friday from Jan 9 2026", ""},
+		{"next", paydays + "d = next payday after Jun 1 2026\n", "2026-06-12", ""},
+		{"next by full name", paydays + "d = next paydays after Jun 12 2026\n", "2026-06-26", ""},
+		{"count", paydays + "n = count paydays between Mar 1 2026 and Jun 30 2026\n", "9", ""},
+		{"count inline", "n = count every Friday between Jan 1 2026 and Dec 31 2026\n", "52", ""},
+		{"quarter", paydays + "l = paydays in Q3 2026\n", "[Friday, July 10, 2026, Friday, July 24, 2026, Friday, August 7, 2026, Friday, August 21, 2026, Friday, September 4, 2026, Friday, September 18, 2026]", ""},
+		{"monthly", "rent = every month on the 31st\nd = next rent after Feb 1 2026\n", "2026-02-28", ""},
+		{"every other", "s = every other month from Jan 15 2026\nd = next s after Jan 15 2026\n", "2026-03-15", ""},
+		{"duration", "s = every 10 days starting Jan 1 2026\nd = next s after Jan 1 2026\n", "2026-01-11", ""},
+		{"every as a variable", "every = 5\nnext = every + 1\n", "6", ""},
+		{"too many dates", "s = every day\nn = count s between Jan 1 1900 and Jan 1 2100\n", "", "more than 10000 dates"},
+		{"not a schedule", "x = 5\nd = next x\n", "", "needs a schedule"},
+		{"day of a weekly schedule", "s = every week on the 3rd\n", "", "only monthly schedules"},
+		{"start isn't a date", "s = every week from 5\n", "", "must start from a date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			results, err := NewInterpreter().Eval(nodes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval error = %v", err)
+			}
+
+			last := results[len(results)-1]
+			got := last.String()
+			if date, ok := last.(*types.Date); ok {
+				got = date.Format(time.DateOnly)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTimeZoneConversion tests converting times between zones with "in".
+// The zones here don't observe daylight saving time, so results don't
+// depend on the day the test runs.
+func TestTimeZoneConversion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"city to UTC", "t = 9:00 Tokyo in UTC\n", "00:00:00 UTC", ""},
+		{"abbreviation to IANA name", "t = 6pm JST in Asia/Kolkata\n", "14:30:00 IST", ""},
+		{"offset to city", "t = 10:30AM UTC-7 in Singapore\n", "01:30:00 +08", ""},
+		{"no zone is UTC", "t = 14:30 in Tokyo\n", "23:30:00 JST", ""},
+		{"variable", "meeting = 4pm Asia/Kolkata\nt = meeting in tokyo\n", "19:30:00 JST", ""},
+		{"lowercase IANA name", "t = 12:00 UTC in asia/seoul\n", "21:00:00 KST", ""},
+		{"not a time", "t = 5 in Tokyo\n", "", "only times convert"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			results, err := NewInterpreter().Eval(nodes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval error = %v", err)
+			}
+			if got := results[len(results)-1].String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
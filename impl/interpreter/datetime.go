@@ -52,20 +52,46 @@ func (interp *Interpreter) evalTimeLiteral(t *ast.TimeLiteral) (types.Type, erro
 		}
 	}
 
-	isPM := false
-	if t.Period != nil && strings.ToUpper(*t.Period) == "PM" {
-		isPM = true
+	if t.Period != nil {
+		if hour, err = types.Hour24(hour, *t.Period); err != nil {
+			return nil, err
+		}
 	}
 
-	utcOffsetMinutes := 0
-	if t.UTCOffset != nil {
-		utcOffsetMinutes, err = parseUTCOffset(t.UTCOffset)
+	location := time.UTC
+	switch {
+	case t.Zone != "":
+		if location, err = types.LoadTimeZone(t.Zone); err != nil {
+			return nil, err
+		}
+	case t.UTCOffset != nil:
+		utcOffsetMinutes, err := parseUTCOffset(t.UTCOffset)
 		if err != nil {
 			return nil, err
 		}
+		location = types.OffsetZone(utcOffsetMinutes)
 	}
 
-	return types.NewTime(hour, minute, second, isPM, utcOffsetMinutes)
+	// Times are today's, so conversions follow today's daylight saving rules
+	return types.NewTimeIn(hour, minute, second, location, time.Now().In(location))
+}
+
+// evalTimeZoneConversion converts a time to another zone: "3pm EST in PST".
+// A time written without a zone is taken as UTC.
+func (interp *Interpreter) evalTimeZoneConversion(c *ast.TimeZoneConversion) (types.Type, error) {
+	val, err := interp.evalNode(c.Time)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := val.(*types.Time)
+	if !ok {
+		return nil, fmt.Errorf("only times convert to a time zone, got %T", val)
+	}
+	location, err := types.LoadTimeZone(c.Zone)
+	if err != nil {
+		return nil, err
+	}
+	return t.In(location), nil
 }
 
 func (interp *Interpreter) evalDurationLiteral(d *ast.DurationLiteral) (types.Type, error) {
@@ -0,0 +1,145 @@
+package interpreter
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Distributions: normal(mean, sd) and uniform(low, high) draw samples
+// (Environment.Samples of them) into a types.Distribution, and arithmetic
+// on a distribution applies to each of its samples.
+//
+// Arguments are numbers, currencies, quantities, or durations. Samples are
+// seeded in the order distributions are drawn, so evaluating a document
+// again gives the same results.
+
+// pcgStream is the PCG stream the samples' random sources use; any
+// constant works.
+const pcgStream = 0x43616c634d61726b
+
+// evalNormal draws normal(mean, sd): samples normally distributed around
+// mean with standard deviation sd. sd is in mean's unit, or a plain number.
+func (interp *Interpreter) evalNormal(args []types.Type) (types.Type, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("normal() requires 2 arguments (mean, standard deviation)")
+	}
+	mean, sd := args[0], args[1]
+	if err := checkSampleArgs("normal", mean, sd); err != nil {
+		return nil, err
+	}
+	if types.SampleValue(sd).IsNegative() {
+		return nil, fmt.Errorf("normal() standard deviation must not be negative, got %s", sd)
+	}
+
+	m, s := types.SampleValue(mean), types.SampleValue(sd)
+	return interp.drawDistribution(mean, func(r *rand.Rand) decimal.Decimal {
+		return m.Add(s.Mul(decimal.NewFromFloat(r.NormFloat64())))
+	}), nil
+}
+
+// evalUniform draws uniform(low, high): samples spread evenly from low to
+// high. high is in low's unit, or a plain number.
+func (interp *Interpreter) evalUniform(args []types.Type) (types.Type, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("uniform() requires 2 arguments (low, high)")
+	}
+	low, high := args[0], args[1]
+	if err := checkSampleArgs("uniform", low, high); err != nil {
+		return nil, err
+	}
+	l, h := types.SampleValue(low), types.SampleValue(high)
+	if h.LessThan(l) {
+		return nil, fmt.Errorf("uniform() low %s is above high %s", low, high)
+	}
+
+	width := h.Sub(l)
+	return interp.drawDistribution(low, func(r *rand.Rand) decimal.Decimal {
+		return l.Add(width.Mul(decimal.NewFromFloat(r.Float64())))
+	}), nil
+}
+
+// checkSampleArgs checks a distribution function's arguments: the first a
+// number, currency, quantity, or duration, the second the same kind in the
+// same unit, or a plain number.
+func checkSampleArgs(name string, first, second types.Type) error {
+	if _, ok := first.(*types.Distribution); ok || !types.IsSample(first) {
+		return fmt.Errorf("%s() requires a number, currency, quantity, or duration, got %s", name, formatTypeForError(first))
+	}
+	if _, ok := second.(*types.Number); ok {
+		return nil
+	}
+	same := false
+	switch f := first.(type) {
+	case *types.Currency:
+		s, ok := second.(*types.Currency)
+		same = ok && f.IsSameCurrency(s)
+	case *types.Quantity:
+		s, ok := second.(*types.Quantity)
+		same = ok && s.Unit == f.Unit
+	case *types.Duration:
+		s, ok := second.(*types.Duration)
+		same = ok && s.Unit == f.Unit
+	}
+	if !same {
+		return fmt.Errorf("%s() arguments must be in the same unit, got %s and %s", name, first, second)
+	}
+	return nil
+}
+
+// drawDistribution draws the environment's sample count of values from
+// draw into a distribution of like's kind and unit.
+func (interp *Interpreter) drawDistribution(like types.Type, draw func(*rand.Rand) decimal.Decimal) *types.Distribution {
+	r := rand.New(rand.NewPCG(interp.env.nextDrawSeed(), pcgStream))
+	samples := make([]types.Type, interp.env.Samples())
+	for i := range samples {
+		samples[i] = types.WithSampleValue(like, draw(r))
+	}
+	return types.NewDistribution(samples)
+}
+
+// evalDistributionOperation applies op to each sample of the distributions
+// among left and right, pairing two distributions' samples in draw order,
+// with a value that isn't a distribution used for every sample.
+func evalDistributionOperation(left, right types.Type, op func(left, right types.Type) (types.Type, error)) (types.Type, error) {
+	leftDist, leftOK := left.(*types.Distribution)
+	rightDist, rightOK := right.(*types.Distribution)
+	n := 0
+	switch {
+	case leftOK && rightOK && leftDist.Len() != rightDist.Len():
+		return nil, fmt.Errorf("cannot combine distributions of %d and %d samples", leftDist.Len(), rightDist.Len())
+	case leftOK:
+		n = leftDist.Len()
+	case rightOK:
+		n = rightDist.Len()
+	}
+
+	samples := make([]types.Type, n)
+	for i := range samples {
+		l, r := left, right
+		if leftOK {
+			l = leftDist.Samples[i]
+		}
+		if rightOK {
+			r = rightDist.Samples[i]
+		}
+		v, err := op(l, r)
+		if err != nil {
+			return nil, err
+		}
+		if !types.IsSample(v) {
+			return nil, fmt.Errorf("a distribution cannot hold %s", formatTypeForError(v))
+		}
+		samples[i] = v
+	}
+	return types.NewDistribution(samples), nil
+}
+
+// isDistribution reports whether either value is a distribution.
+func isDistribution(left, right types.Type) bool {
+	_, l := left.(*types.Distribution)
+	_, r := right.(*types.Distribution)
+	return l || r
+}
@@ -0,0 +1,168 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// evalDistributions evaluates input and returns its environment.
+func evalDistributions(t *testing.T, input string, samples int) *interpreter.Environment {
+	t.Helper()
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	env := interpreter.NewEnvironment()
+	env.SetSamples(samples)
+	if _, err := interpreter.NewInterpreterWithEnv(env).Eval(nodes); err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+	return env
+}
+
+// distribution returns the distribution variable name holds.
+func distribution(t *testing.T, env *interpreter.Environment, name string) *types.Distribution {
+	t.Helper()
+	v, _ := env.Get(name)
+	d, ok := v.(*types.Distribution)
+	if !ok {
+		t.Fatalf("%s = %v, want a distribution", name, v)
+	}
+	return d
+}
+
+// near reports whether v is within tolerance of want.
+func near(v types.Type, want, tolerance float64) bool {
+	f, _ := types.SampleValue(v).Float64()
+	return f >= want-tolerance && f <= want+tolerance
+}
+
+func TestDistributions(t *testing.T) {
+	env := evalDistributions(t, `price = normal($100, $15)
+demand = uniform(50, 80)
+revenue = price * demand
+discounted = -price + $10
+weight = normal(10 kg, 1)
+`, 20000)
+
+	price := distribution(t, env, "price")
+	if price.Len() != 20000 {
+		t.Errorf("price has %d samples, want 20000", price.Len())
+	}
+	if s := price.Summary(); !near(s.Mean, 100, 1) || !near(s.P5, 75.3, 1) || !near(s.P95, 124.7, 1) {
+		t.Errorf("normal($100, $15) summary = %v %v %v", s.Mean, s.P5, s.P95)
+	}
+	if _, ok := price.Mean().(*types.Currency); !ok {
+		t.Errorf("price mean = %v, want a currency", price.Mean())
+	}
+
+	demand := distribution(t, env, "demand")
+	if s := demand.Summary(); !near(s.Mean, 65, 0.5) || !near(s.P5, 51.5, 0.5) || !near(s.P95, 78.5, 0.5) {
+		t.Errorf("uniform(50, 80) summary = %v %v %v", s.Mean, s.P5, s.P95)
+	}
+
+	// Samples pair up in order, so each revenue sample is a price times a demand
+	revenue := distribution(t, env, "revenue")
+	for i := range 10 {
+		want := types.SampleValue(price.Samples[i]).Mul(types.SampleValue(demand.Samples[i]))
+		if got := types.SampleValue(revenue.Samples[i]); !got.Equal(want) {
+			t.Fatalf("revenue sample %d = %s, want %s", i, got, want)
+		}
+	}
+	if !near(revenue.Mean(), 6500, 100) {
+		t.Errorf("revenue mean = %v, want about $6500", revenue.Mean())
+	}
+
+	if d := distribution(t, env, "discounted"); !near(d.Mean(), -90, 1) {
+		t.Errorf("-price + $10 mean = %v, want about $-90", d.Mean())
+	}
+	if w := distribution(t, env, "weight"); !strings.HasSuffix(w.Mean().String(), " kg") {
+		t.Errorf("weight mean = %v, want kg", w.Mean())
+	}
+}
+
+// TestDistributionsRepeatable checks evaluating again draws the same
+// samples, and that separate draws are independent.
+func TestDistributionsRepeatable(t *testing.T) {
+	input := "a = normal(0, 1)\nb = normal(0, 1)\n"
+	first := evalDistributions(t, input, 100)
+	second := evalDistributions(t, input, 100)
+
+	a1, a2, b := distribution(t, first, "a"), distribution(t, second, "a"), distribution(t, first, "b")
+	for i := range a1.Samples {
+		if !types.SampleValue(a1.Samples[i]).Equal(types.SampleValue(a2.Samples[i])) {
+			t.Fatalf("sample %d differs between evaluations", i)
+		}
+	}
+	if types.SampleValue(a1.Samples[0]).Equal(types.SampleValue(b.Samples[0])) {
+		t.Error("two normal() calls drew the same samples")
+	}
+
+	// x - x is exactly zero: both sides are the same samples
+	diff := distribution(t, evalDistributions(t, "x = normal(5, 2)\ny = x - x\n", 100), "y")
+	if s := diff.Summary(); !types.SampleValue(s.P5).IsZero() || !types.SampleValue(s.P95).IsZero() {
+		t.Errorf("x - x = %v, want 0", diff)
+	}
+}
+
+func TestDistributionSampleCap(t *testing.T) {
+	env := interpreter.NewEnvironment()
+	if env.Samples() != types.DefaultSamples {
+		t.Errorf("default samples = %d, want %d", env.Samples(), types.DefaultSamples)
+	}
+	env.SetSamples(types.MaxSamples * 10)
+	if env.Samples() != types.MaxSamples {
+		t.Errorf("samples = %d, want the cap %d", env.Samples(), types.MaxSamples)
+	}
+}
+
+func TestDistributionErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"normal(100)\n", "requires 2 arguments"},
+		{"normal($100, -$15)\n", "must not be negative"},
+		{"normal($100, €15)\n", "same unit"},
+		{"uniform(10 kg, 5 m)\n", "same unit"},
+		{"uniform(80, 50)\n", "is above high"},
+		{"normal(true, 1)\n", "requires a number"},
+		{"normal(normal(1, 1), 1)\n", "requires a number"},
+		{"x = normal(10, 1)\nx > 5\n", "distribution"},
+	}
+	for _, tt := range tests {
+		nodes, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.input, err)
+		}
+		_, err = interpreter.NewInterpreter().Eval(nodes)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestDistributionPercentile(t *testing.T) {
+	samples := make([]types.Type, 100)
+	for i := range samples {
+		samples[i] = types.NewNumber(decimal.NewFromInt(int64(100 - i))) // 100 down to 1
+	}
+	d := types.NewDistribution(samples)
+
+	for _, tt := range []struct {
+		p    float64
+		want string
+	}{{0, "1"}, {5, "5"}, {50, "50"}, {95, "95"}, {100, "100"}} {
+		if got := d.Percentile(tt.p).String(); got != tt.want {
+			t.Errorf("Percentile(%v) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+	if got := d.String(); got != "~50.5 (p5 5, p95 95)" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -3,7 +3,9 @@ package interpreter
 import (
 	"maps"
 	"strings"
+	"sync/atomic"
 
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 )
@@ -11,15 +13,57 @@ import (
 // Environment tracks variable bindings during interpretation.
 // This is separate from Go's context.Context - it's simply variable storage for CalcMark variables.
 type Environment struct {
-	vars          map[string]types.Type
-	exchangeRates map[string]decimal.Decimal // "USD_EUR" -> rate
+	vars          map[lexer.Symbol]types.Type
+	exchangeRates map[currencyPair]decimal.Decimal // {"USD", "EUR"} -> rate
+
+	// names holds vars by name, for GetAllVariables
+	names map[string]types.Type
+
+	// calendar holds the holidays business-day arithmetic skips; nil for
+	// weekends only
+	calendar *types.Calendar
+
+	// samples is how many samples distributions draw; zero for
+	// types.DefaultSamples
+	samples int
+
+	// divisionPrecision is how many decimal places division rounds to;
+	// zero for DefaultDivisionPrecision
+	divisionPrecision int32
+
+	// draws counts the distributions drawn, seeding each one's samples
+	draws uint64
+
+	// functions holds custom functions by name, shared with clones
+	functions map[string]Function
+
+	// dataPolicy is which files lookup() and jsonpath() may read; nil
+	// for none
+	dataPolicy *DataPolicy
+
+	// cpi holds the price index adjust() reads by year, before asking
+	// cpiProvider; both may be nil
+	cpi         CPITable
+	cpiProvider CPIProvider
+
+	// generation identifies the environment's state; see Generation
+	generation uint64
+}
+
+// generations mints environment generations, unique across environments.
+var generations atomic.Uint64
+
+// currencyPair is a from/to pair of uppercase currency codes.
+type currencyPair struct {
+	from, to string
 }
 
 // NewEnvironment creates a new empty environment with built-in constants.
 func NewEnvironment() *Environment {
 	env := &Environment{
-		vars:          make(map[string]types.Type),
-		exchangeRates: make(map[string]decimal.Decimal),
+		vars:          make(map[lexer.Symbol]types.Type),
+		names:         make(map[string]types.Type),
+		exchangeRates: make(map[currencyPair]decimal.Decimal),
 	}
 
 	// Add built-in constants
@@ -37,56 +81,100 @@ var (
 
 // addConstants adds built-in mathematical constants (PI, E).
 func (e *Environment) addConstants() {
-	e.vars["PI"] = types.NewNumber(piValue)
-	e.vars["E"] = types.NewNumber(eValue)
+	e.set("PI", types.NewNumber(piValue))
+	e.set("E", types.NewNumber(eValue))
 }
 
-// Set stores a variable binding.
+// Set stores a variable binding. Variables are kept by symbol (see
+// lexer.SymbolFor), so the interpreter looks up the identifiers the
+// parser produces without hashing their names.
 func (e *Environment) Set(name string, value types.Type) {
-	e.vars[name] = value
+	e.set(name, value)
+	e.touch()
+}
+
+// set stores a variable binding without changing the generation.
+func (e *Environment) set(name string, value types.Type) {
+	sym := lexer.SymbolFor(name)
+	e.vars[sym] = value
+	e.names[sym.Name()] = value
 }
 
 // Get retrieves a variable binding.
 // Returns the value and true if found, nil and false if not found.
 func (e *Environment) Get(name string) (types.Type, bool) {
-	val, ok := e.vars[name]
+	return e.GetSymbol(lexer.SymbolFor(name))
+}
+
+// GetSymbol retrieves a variable binding by its name's symbol, as an
+// identifier from the parser carries it.
+func (e *Environment) GetSymbol(sym lexer.Symbol) (types.Type, bool) {
+	val, ok := e.vars[sym]
 	return val, ok
 }
 
 // Has checks if a variable is defined.
 func (e *Environment) Has(name string) bool {
-	_, ok := e.vars[name]
+	_, ok := e.vars[lexer.SymbolFor(name)]
 	return ok
 }
 
 // Clone creates a shallow copy of the environment.
 func (e *Environment) Clone() *Environment {
 	newEnv := &Environment{
-		vars:          make(map[string]types.Type),
-		exchangeRates: make(map[string]decimal.Decimal),
+		vars:          make(map[lexer.Symbol]types.Type, len(e.vars)),
+		names:         make(map[string]types.Type, len(e.names)),
+		exchangeRates: make(map[currencyPair]decimal.Decimal, len(e.exchangeRates)),
 	}
 	maps.Copy(newEnv.vars, e.vars)
+	maps.Copy(newEnv.names, e.names)
 	maps.Copy(newEnv.exchangeRates, e.exchangeRates)
+	newEnv.calendar = e.calendar
+	newEnv.samples, newEnv.draws = e.samples, e.draws
+	newEnv.divisionPrecision = e.divisionPrecision
+	newEnv.functions = e.functions
+	newEnv.dataPolicy = e.dataPolicy
+	newEnv.cpi, newEnv.cpiProvider = e.cpi.clone(), e.cpiProvider
+	newEnv.generation = e.generation
 	return newEnv
 }
 
-// GetAllVariables returns the map of all variables (for sync with semantic checker).
+// Generation identifies the environment's state: it changes whenever a
+// variable, exchange rate, or other setting is set, and environments
+// share one only if they hold the same state, as a clone does until
+// either changes. New environments start at zero. Caches of results that
+// depend on an environment, such as the classifier's, key on it.
+func (e *Environment) Generation() uint64 {
+	return e.generation
+}
+
+// touch gives the environment a new generation after a change.
+func (e *Environment) touch() {
+	e.generation = generations.Add(1)
+}
+
+// GetAllVariables returns the map of all variables by name (for sync with
+// semantic checker). It must not be changed; use Set.
 func (e *Environment) GetAllVariables() map[string]types.Type {
-	return e.vars
+	return e.names
 }
 
-// SetExchangeRate sets an exchange rate for currency conversion.
-// Key format: "FROM_TO" (e.g., "USD_EUR").
+// SetExchangeRate sets the rate converting one currency to another,
+// replacing any earlier one. A rate provider refreshing its rates sets
+// each new one here, which also gives the environment a new generation.
 func (e *Environment) SetExchangeRate(from, to string, rate decimal.Decimal) {
-	key := strings.ToUpper(from) + "_" + strings.ToUpper(to)
-	e.exchangeRates[key] = rate
+	e.exchangeRates[currencyPair{strings.ToUpper(from), strings.ToUpper(to)}] = rate
+	e.touch()
 }
 
 // GetExchangeRate retrieves an exchange rate for currency conversion.
 // Returns the rate and true if found, zero and false if not defined.
 func (e *Environment) GetExchangeRate(from, to string) (decimal.Decimal, bool) {
-	key := strings.ToUpper(from) + "_" + strings.ToUpper(to)
-	rate, ok := e.exchangeRates[key]
+	// Currency values carry uppercase codes, so try them as given first
+	if rate, ok := e.exchangeRates[currencyPair{from, to}]; ok {
+		return rate, true
+	}
+	rate, ok := e.exchangeRates[currencyPair{strings.ToUpper(from), strings.ToUpper(to)}]
 	return rate, ok
 }
 
@@ -94,3 +182,60 @@ func (e *Environment) GetExchangeRate(from, to string) (decimal.Decimal, bool) {
 func (e *Environment) HasExchangeRates() bool {
 	return len(e.exchangeRates) > 0
 }
+
+// SetCalendar sets the holiday calendar for business-day arithmetic, such
+// as today + 10 business days. Nil means weekends only.
+func (e *Environment) SetCalendar(c *types.Calendar) {
+	e.calendar = c
+	e.touch()
+}
+
+// Calendar returns the holiday calendar, nil if none is set.
+func (e *Environment) Calendar() *types.Calendar {
+	return e.calendar
+}
+
+// SetSamples sets how many samples distributions such as normal(100, 15)
+// draw, at most types.MaxSamples. Zero means types.DefaultSamples.
+func (e *Environment) SetSamples(n int) {
+	e.samples = min(max(n, 0), types.MaxSamples)
+	e.touch()
+}
+
+// Samples returns how many samples distributions draw.
+func (e *Environment) Samples() int {
+	if e.samples == 0 {
+		return types.DefaultSamples
+	}
+	return e.samples
+}
+
+// DefaultDivisionPrecision is how many decimal places division rounds to
+// unless SetDivisionPrecision says otherwise, decimal's own default.
+const DefaultDivisionPrecision = 16
+
+// SetDivisionPrecision sets how many decimal places division rounds to,
+// such as more than usual to check results against. Zero means
+// DefaultDivisionPrecision.
+func (e *Environment) SetDivisionPrecision(places int) {
+	e.divisionPrecision = int32(max(places, 0))
+	e.touch()
+}
+
+// DivisionPrecision returns how many decimal places division rounds to.
+func (e *Environment) DivisionPrecision() int32 {
+	if e.divisionPrecision == 0 {
+		return DefaultDivisionPrecision
+	}
+	return e.divisionPrecision
+}
+
+// nextDrawSeed returns the seed for the next distribution's samples.
+// Seeds follow the same sequence in every new environment, so evaluating
+// a document again draws the same samples. Drawing changes the state, so
+// the environment gets a new generation.
+func (e *Environment) nextDrawSeed() uint64 {
+	e.draws++
+	e.touch()
+	return e.draws
+}
@@ -0,0 +1,314 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Finance functions: fv, pmt, npv, irr, amortize.
+//
+// Rates are per period and dimensionless, written as percentages (5%/12 for
+// a monthly rate on 5% a year). Money arguments may be numbers or
+// currencies; when any is a currency the result is in that currency, and
+// mixing currencies is an error, as it is for arithmetic.
+//
+// Signs follow the cash, not the spreadsheet convention: a positive present
+// value grows into a positive future value, and a positive loan needs a
+// positive payment.
+
+// irrMaxIterations bounds the Newton's method search in irr().
+const irrMaxIterations = 100
+
+// maxAmortizeMonths bounds the rows amortize() builds.
+const maxAmortizeMonths = 100 * 12
+
+// evalFV calculates fv(rate, periods, payment, pv): the value after periods
+// of pv growing at rate, plus a payment added at the end of every period.
+// Division rounds to precision decimal places.
+func evalFV(args []types.Type, precision int32) (types.Type, error) {
+	if len(args) != 4 {
+		return nil, fmt.Errorf("fv() requires 4 arguments (rate, periods, payment, pv)")
+	}
+	rate, err := financeRate("fv", args[0])
+	if err != nil {
+		return nil, err
+	}
+	periods, err := financePeriods("fv", args[1])
+	if err != nil {
+		return nil, err
+	}
+	money, currency, err := financeMoney("fv", args[2:])
+	if err != nil {
+		return nil, err
+	}
+	payment, pv := money[0], money[1]
+
+	growth, err := power(decimal.NewFromInt(1).Add(rate), periods)
+	if err != nil {
+		return nil, err
+	}
+	annuity := periods
+	if !rate.IsZero() {
+		annuity = growth.Sub(decimal.NewFromInt(1)).DivRound(rate, precision)
+	}
+	return financeResult(pv.Mul(growth).Add(payment.Mul(annuity)), currency), nil
+}
+
+// evalPMT calculates pmt(rate, nper, pv): the payment at the end of every
+// period that pays off pv over nper periods at rate. Division rounds to
+// precision decimal places.
+func evalPMT(args []types.Type, precision int32) (types.Type, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("pmt() requires 3 arguments (rate, nper, pv)")
+	}
+	rate, err := financeRate("pmt", args[0])
+	if err != nil {
+		return nil, err
+	}
+	nper, err := financePeriods("pmt", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if nper.IsZero() {
+		return nil, fmt.Errorf("pmt() nper must not be zero")
+	}
+	money, currency, err := financeMoney("pmt", args[2:])
+	if err != nil {
+		return nil, err
+	}
+	pv := money[0]
+
+	if rate.IsZero() {
+		return financeResult(pv.DivRound(nper, precision), currency), nil
+	}
+	growth, err := power(decimal.NewFromInt(1).Add(rate), nper.Neg())
+	if err != nil {
+		return nil, err
+	}
+	discount := decimal.NewFromInt(1).Sub(growth)
+	return financeResult(pv.Mul(rate).DivRound(discount, precision), currency), nil
+}
+
+// amortizeColumns are the columns of an amortize() schedule.
+var amortizeColumns = []string{"period", "payment", "interest", "principal", "balance"}
+
+// evalAmortize builds amortize(principal, rate, years): the monthly
+// schedule paying off principal over years at rate a year, one row per
+// month with the payment and the interest and principal it pays, and the
+// balance left after it. Unlike the other finance functions the rate is
+// yearly, as loans are quoted. The last payment pays off what rounding to
+// cents left, so the balance ends at exactly zero.
+func evalAmortize(args []types.Type) (types.Type, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("amortize() requires 3 arguments (principal, rate, years)")
+	}
+	money, currency, err := financeMoney("amortize", args[:1])
+	if err != nil {
+		return nil, err
+	}
+	rate, err := financeRate("amortize", args[1])
+	if err != nil {
+		return nil, err
+	}
+	months, err := amortizeMonths(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	// Payments and interest are in whole cents, as a lender charges them
+	places := int32(2)
+	if currency != nil {
+		places = types.CurrencyDecimals(currency.Code)
+	}
+	balance := money[0]
+	monthly := rate.Div(decimal.NewFromInt(12))
+	payment := balance.Div(decimal.NewFromInt(months))
+	if !monthly.IsZero() {
+		discount := decimal.NewFromInt(1).Sub(decimal.NewFromInt(1).Add(monthly).Pow(decimal.NewFromInt(-months)))
+		payment = balance.Mul(monthly).Div(discount)
+	}
+	payment = payment.Round(places)
+
+	rows := make([][]types.Type, months)
+	for i := range rows {
+		interest := balance.Mul(monthly).Round(places)
+		principal := payment.Sub(interest)
+		if i == len(rows)-1 {
+			principal = balance
+		}
+		balance = balance.Sub(principal)
+		rows[i] = []types.Type{
+			types.NewNumber(decimal.NewFromInt(int64(i + 1))),
+			financeResult(interest.Add(principal), currency),
+			financeResult(interest, currency),
+			financeResult(principal, currency),
+			financeResult(balance, currency),
+		}
+	}
+	return types.NewTable(amortizeColumns, rows), nil
+}
+
+// amortizeMonths extracts the term of amortize(), a number of years or a
+// duration in months or years, as a whole number of months.
+func amortizeMonths(arg types.Type) (int64, error) {
+	var months decimal.Decimal
+	switch v := arg.(type) {
+	case *types.Number:
+		months = v.Value.Mul(decimal.NewFromInt(12))
+	case *types.Duration:
+		switch types.NormalizeTimeUnit(v.Unit) {
+		case "year":
+			months = v.Value.Mul(decimal.NewFromInt(12))
+		case "month":
+			months = v.Value
+		default:
+			return 0, fmt.Errorf("amortize() term must be in years or months, got %s", v)
+		}
+	default:
+		return 0, fmt.Errorf("amortize() years must be a number, got %T", arg)
+	}
+	if !months.IsInteger() || !months.IsPositive() {
+		return 0, fmt.Errorf("amortize() term must be a positive whole number of months")
+	}
+	if months.GreaterThan(decimal.NewFromInt(maxAmortizeMonths)) {
+		return 0, fmt.Errorf("amortize() term must be at most %d years", maxAmortizeMonths/12)
+	}
+	return months.IntPart(), nil
+}
+
+// evalNPV calculates npv(rate, cashflows): the present value of cash flows
+// one period apart, the first of them today and so not discounted. This
+// differs from the spreadsheet NPV, which discounts the first cash flow too,
+// and makes npv(irr(cashflows), cashflows) zero. Division rounds to
+// precision decimal places.
+func evalNPV(args []types.Type, precision int32) (types.Type, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("npv() requires a rate and at least one cash flow")
+	}
+	rate, err := financeRate("npv", args[0])
+	if err != nil {
+		return nil, err
+	}
+	flows, currency, err := financeMoney("npv", flattenLists(args[1:]))
+	if err != nil {
+		return nil, err
+	}
+	if len(flows) == 0 {
+		return nil, fmt.Errorf("npv() requires at least one cash flow")
+	}
+	return financeResult(presentValue(rate, flows, precision), currency), nil
+}
+
+// evalIRR calculates irr(cashflows): the rate at which the cash flows' npv()
+// is zero. The result is a per-period rate, a percentage that npv() and the
+// other finance functions take back.
+func evalIRR(args []types.Type) (types.Type, error) {
+	flows, _, err := financeMoney("irr", flattenLists(args))
+	if err != nil {
+		return nil, err
+	}
+	var positive, negative bool
+	values := make([]float64, len(flows))
+	for i, f := range flows {
+		positive = positive || f.IsPositive()
+		negative = negative || f.IsNegative()
+		values[i], _ = f.Float64()
+	}
+	if !positive || !negative {
+		return nil, fmt.Errorf("irr() requires at least one positive and one negative cash flow")
+	}
+
+	// Newton's method, in float64 like sqrt(): the rate is irrational in general
+	rate := 0.1
+	for range irrMaxIterations {
+		value, slope := 0.0, 0.0
+		for i, f := range values {
+			value += f / math.Pow(1+rate, float64(i))
+			slope -= float64(i) * f / math.Pow(1+rate, float64(i+1))
+		}
+		if slope == 0 {
+			break
+		}
+		next := rate - value/slope
+		if next <= -1 {
+			next = (rate - 1) / 2 // Stay above -100%, where discounting is undefined
+		}
+		if math.Abs(next-rate) < 1e-12 {
+			return types.NewPercentage(decimal.NewFromFloat(next)), nil
+		}
+		rate = next
+	}
+	return nil, fmt.Errorf("irr() did not converge for these cash flows")
+}
+
+// presentValue discounts flows, one period apart starting today, at rate,
+// dividing to precision decimal places.
+func presentValue(rate decimal.Decimal, flows []decimal.Decimal, precision int32) decimal.Decimal {
+	total := decimal.Zero
+	discount := decimal.NewFromInt(1)
+	for _, f := range flows {
+		total = total.Add(f.DivRound(discount, precision))
+		discount = discount.Mul(decimal.NewFromInt(1).Add(rate))
+	}
+	return total
+}
+
+// financeRate extracts a per-period rate, which must be a plain number such
+// as 5%.
+func financeRate(name string, arg types.Type) (decimal.Decimal, error) {
+	num, ok := arg.(*types.Number)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%s() rate must be a percentage like 5%%, got %T", name, arg)
+	}
+	if num.Value.LessThanOrEqual(decimal.NewFromInt(-1)) {
+		return decimal.Zero, fmt.Errorf("%s() rate must be greater than -100%%", name)
+	}
+	return num.Value, nil
+}
+
+// financePeriods extracts a period count, which must be a plain number.
+func financePeriods(name string, arg types.Type) (decimal.Decimal, error) {
+	num, ok := arg.(*types.Number)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%s() periods must be a number, got %T", name, arg)
+	}
+	return num.Value, nil
+}
+
+// financeMoney extracts money arguments, returning the currency they share,
+// or nil when all are plain numbers.
+func financeMoney(name string, args []types.Type) ([]decimal.Decimal, *types.Currency, error) {
+	values := make([]decimal.Decimal, 0, len(args))
+	var currency *types.Currency
+	for _, arg := range args {
+		var value decimal.Decimal
+		switch v := arg.(type) {
+		case *types.Number:
+			value = v.Value
+		case *types.Currency:
+			if currency != nil && !currency.IsSameCurrency(v) {
+				return nil, nil, fmt.Errorf("%s() cannot mix currencies: %s and %s", name, currency.Symbol, v.Symbol)
+			}
+			if currency == nil {
+				currency = v
+			}
+			value = v.Value
+		default:
+			return nil, nil, fmt.Errorf("%s() amounts must be numbers or currencies, got %T", name, arg)
+		}
+		values = append(values, value)
+	}
+	return values, currency, nil
+}
+
+// financeResult returns value in currency, or as a plain number when
+// currency is nil.
+func financeResult(value decimal.Decimal, currency *types.Currency) types.Type {
+	if currency == nil {
+		return types.NewNumber(value)
+	}
+	return &types.Currency{Value: value, Symbol: currency.Symbol, Code: currency.Code}
+}
@@ -0,0 +1,136 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+func TestFinanceFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"fv of savings", "fv(5%/12, 120, $100, $1000)\n", "$17175.24"},
+		{"fv of a lump sum", "fv(7%, 10, 0, €1000)\n", "€1967.15"},
+		{"fv at zero rate", "fv(0, 10, 100, 50)\n", "1050"},
+		{"pmt of a mortgage", "pmt(6%/12, 360, $300000)\n", "$1798.65"},
+		{"pmt at zero rate", "pmt(0, 12, $1200)\n", "$100.00"},
+		{"npv", "npv(10%, [-$1000, $300, $400, $500])\n", "$-21.04"},
+		{"npv of values", "npv(10%, -1000, 1100)\n", "0"},
+		{"npv of one cash flow", "npv(10%, [$500])\n", "$500.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			results, err := interpreter.NewInterpreter().Eval(nodes)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+
+			if actual := results[0].String(); actual != tt.expected {
+				t.Errorf("Result = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIRR checks irr() finds the rate at which npv() is zero.
+func TestIRR(t *testing.T) {
+	input := "flows = [-$1000, $300, $400, $500]\nrate = irr(flows)\nnpv(rate, flows)\n"
+
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	results, err := interpreter.NewInterpreter().Eval(nodes)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	rate, ok := results[1].(*types.Number)
+	if !ok || !rate.IsPercentage() {
+		t.Fatalf("irr() = %T, want a percentage", results[1])
+	}
+	if got := rate.Value.StringFixed(6); got != "0.088963" {
+		t.Errorf("irr() = %s, want 0.088963", got)
+	}
+	if got := results[2].String(); got != "$0.00" {
+		t.Errorf("npv() at irr() = %s, want $0.00", got)
+	}
+}
+
+func TestFinanceFunctionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"fv too few args", "fv(5%, 10, 100)\n"},
+		{"fv currency rate", "fv($5, 10, 100, 0)\n"},
+		{"pmt quantity rate", "pmt(5 GB, 10, 100)\n"},
+		{"pmt zero periods", "pmt(5%, 0, 100)\n"},
+		{"pmt mixed currencies", "fv(5%, 10, $100, €100)\n"},
+		{"npv no cash flows", "npv(5%)\n"},
+		{"npv of dates", "npv(5%, [Jan 1 2025])\n"},
+		{"irr all positive", "irr([100, 200])\n"},
+		{"rate of -100%", "npv(-100%, [100, 200])\n"},
+		{"amortize too few args", "amortize($1000, 5%)\n"},
+		{"amortize part of a month", "amortize($1000, 5%, 1.01)\n"},
+		{"amortize term in days", "amortize($1000, 5%, 30 days)\n"},
+		{"amortize past the last row", "amortize($1000, 5%, 1)[13]\n"},
+		{"amortize unknown column", "amortize($1000, 5%, 1)[1].fees\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				return
+			}
+
+			_, err = interpreter.NewInterpreter().Eval(nodes)
+			if err == nil {
+				t.Errorf("Expected error for %q but got none", tt.input)
+			}
+		})
+	}
+}
+
+// TestAmortize checks amortize() schedules and indexing their rows.
+func TestAmortize(t *testing.T) {
+	input := `loan = amortize($300000, 6%, 30)
+loan[1].interest
+loan[1].principal
+loan[60].balance
+loan[-1].balance
+loan[-1].period
+amortize(1200, 0, 12 months)[12].payment
+`
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	results, err := interpreter.NewInterpreter().Eval(nodes)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	loan, ok := results[0].(*types.Table)
+	if !ok || loan.Len() != 360 || len(loan.Columns) != 5 {
+		t.Fatalf("amortize() = %v, want 360 rows of 5 columns", results[0])
+	}
+	want := []string{"$1500.00", "$298.65", "$279163.14", "$0.00", "360", "100"}
+	for i, w := range want {
+		if got := results[i+1].String(); got != w {
+			t.Errorf("line %d = %s, want %s", i+2, got, w)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package interpreter
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/types"
@@ -55,8 +56,12 @@ func (interp *Interpreter) evalFunctionCall(f *ast.FunctionCall) (types.Type, er
 		// Second argument can be an Identifier (time unit) or evaluated duration
 		// Try to extract as identifier first
 		if identArg, ok := f.Arguments[1].(*ast.Identifier); ok {
-			// Pass the identifier directly without evaluation
-			return calculateDowntime(availability, identArg)
+			// Bare identifier like "month", "year" - treat as 1 unit
+			period, err := types.NewDuration(decimal.NewFromInt(1), identArg.Name)
+			if err != nil {
+				return nil, fmt.Errorf("downtime() time period must be a time unit, got %s", identArg.Name)
+			}
+			return calculateDowntime(availability, period)
 		}
 
 		// Otherwise evaluate it (could be a Duration literal or expression)
@@ -226,6 +231,25 @@ func (interp *Interpreter) evalFunctionCall(f *ast.FunctionCall) (types.Type, er
 		return capacityAt(demand, capacityVal, unitName)
 	}
 
+	// Special case: workdays' optional third argument is a holiday calendar
+	// name, not a variable
+	if f.Name == "workdays" {
+		return interp.evalWorkdays(f)
+	}
+
+	// Special case: data functions take quoted file names and paths
+	if f.Name == "lookup" {
+		return interp.evalLookup(f)
+	}
+	if f.Name == "jsonpath" {
+		return interp.evalJSONPath(f)
+	}
+
+	// Special case: schedule queries look their schedule up by plural name
+	if f.Name == "next" || f.Name == "count" || f.Name == "occurrences" {
+		return interp.evalScheduleQuery(f)
+	}
+
 	// Evaluate all arguments for other functions
 	args := make([]types.Type, len(f.Arguments))
 	for i, arg := range f.Arguments {
@@ -236,14 +260,57 @@ func (interp *Interpreter) evalFunctionCall(f *ast.FunctionCall) (types.Type, er
 		args[i] = val
 	}
 
+	// Aggregates accept lists: sum(prices) aggregates over the list's elements
+	if isAggregateFunction(f.Name) {
+		args = flattenLists(args)
+	}
+
 	// Call the appropriate function
 	switch f.Name {
 	case "avg", "average":
-		return evalAverage(args)
+		return evalAverage(args, interp.env.DivisionPrecision())
 	case "sqrt":
 		return evalSqrt(args)
+	case "sum":
+		return evalSum(args)
+	case "min":
+		return evalMin(args)
+	case "max":
+		return evalMax(args)
+	case "median":
+		return evalMedian(args)
+	case "stdev":
+		return evalStdev(args)
 	case "accumulate":
 		return evalAccumulate(args)
+	case "fv":
+		return evalFV(args, interp.env.DivisionPrecision())
+	case "pmt":
+		return evalPMT(args, interp.env.DivisionPrecision())
+	case "amortize":
+		return evalAmortize(args)
+	case "npv":
+		return evalNPV(args, interp.env.DivisionPrecision())
+	case "irr":
+		return evalIRR(args)
+	case "cagr":
+		return evalCAGR(args, interp.env.DivisionPrecision())
+	case "grow":
+		return evalGrow(args)
+	case "compound":
+		return evalCompound(args)
+	case "adjust":
+		return interp.evalAdjust(args)
+	case "normal":
+		return interp.evalNormal(args)
+	case "uniform":
+		return interp.evalUniform(args)
+	case "increase":
+		return evalIncrease(args)
+	case "decrease":
+		return evalDecrease(args)
+	case "percent_change":
+		return evalPercentChange(args, interp.env.DivisionPrecision())
 	case "convert_rate":
 		// Already handled above
 		return nil, fmt.Errorf("convert_rate should have been handled")
@@ -269,8 +336,45 @@ func (interp *Interpreter) evalFunctionCall(f *ast.FunctionCall) (types.Type, er
 		// Already handled above
 		return nil, fmt.Errorf("compress should have been handled")
 	default:
-		return nil, fmt.Errorf("unknown function: %s", f.Name)
+		return interp.callFunction(f.Name, args)
+	}
+}
+
+// evalWorkdays handles workdays(start, end) and workdays(start, end, calendar),
+// counting business days from start to end, both included. Without a
+// calendar argument, the document's holidays apply.
+func (interp *Interpreter) evalWorkdays(f *ast.FunctionCall) (types.Type, error) {
+	if len(f.Arguments) < 2 || len(f.Arguments) > 3 {
+		return nil, fmt.Errorf("workdays() requires 2 or 3 arguments (start, end, calendar?)")
 	}
+
+	var dates [2]*types.Date
+	for i := range dates {
+		val, err := interp.evalNode(f.Arguments[i])
+		if err != nil {
+			return nil, err
+		}
+		date, ok := val.(*types.Date)
+		if !ok {
+			return nil, fmt.Errorf("workdays() arguments must be dates, got %T", val)
+		}
+		dates[i] = date
+	}
+
+	calendar := interp.env.Calendar()
+	if len(f.Arguments) == 3 {
+		ident, ok := f.Arguments[2].(*ast.Identifier)
+		if !ok {
+			return nil, fmt.Errorf("workdays() calendar must be a name (%s)", strings.Join(types.HolidayRegions(), ", "))
+		}
+		var err error
+		if calendar, err = types.NewRegionCalendar(ident.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	days := calendar.BusinessDaysBetween(dates[0], dates[1])
+	return &types.Duration{Value: decimal.NewFromInt(int64(days)), Unit: types.BusinessDayUnit}, nil
 }
 
 // evalAccumulate handles accumulate(rate, time_period) function calls.
@@ -307,8 +411,8 @@ func evalAccumulate(args []types.Type) (types.Type, error) {
 	return accumulateRate(rate, periodValue, periodUnit)
 }
 
-// evalAverage calculates the average of numbers.
-func evalAverage(args []types.Type) (types.Type, error) {
+// evalAverage calculates the average of numbers, to precision decimal places.
+func evalAverage(args []types.Type, precision int32) (types.Type, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("avg() requires at least one argument")
 	}
@@ -327,7 +431,7 @@ func evalAverage(args []types.Type) (types.Type, error) {
 
 	// Calculate average
 	count := len(numbers)
-	avg := sum.Div(decimal.NewFromInt(int64(count)))
+	avg := sum.DivRound(decimal.NewFromInt(int64(count)), precision)
 
 	return types.NewNumber(avg), nil
 }
@@ -0,0 +1,193 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Growth functions: cagr, grow, compound.
+//
+// They replace hand-written exponents such as "$100 * (1 + 5%) ^ 3". The
+// amount grown may be a number, a currency, or a quantity and keeps its
+// currency or unit. Rates are percentages, including the one cagr() finds,
+// so it can be passed back to compound().
+
+// evalCAGR calculates cagr(start, end, years): the yearly rate that grows
+// start into end over years, a number of years or a duration, to precision
+// decimal places.
+func evalCAGR(args []types.Type, precision int32) (types.Type, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("cagr() requires 3 arguments (start, end, years)")
+	}
+	start, startUnit, err := growthAmount("cagr", args[0])
+	if err != nil {
+		return nil, err
+	}
+	end, endUnit, err := growthAmount("cagr", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if startUnit != endUnit {
+		return nil, fmt.Errorf("cagr() start and end must be in the same unit, got %s and %s", args[0], args[1])
+	}
+	years, err := growthYears("cagr", args[2])
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsPositive() || end.IsNegative() {
+		return nil, fmt.Errorf("cagr() start must be positive and end not negative")
+	}
+
+	ratio := end.DivRound(start, precision)
+	growth, err := ratio.PowWithPrecision(decimal.NewFromInt(1).DivRound(years, precision), precision)
+	if err != nil {
+		return nil, fmt.Errorf("cagr(): %w", err)
+	}
+	return types.NewPercentage(growth.Sub(decimal.NewFromInt(1)).Round(precision)), nil
+}
+
+// evalGrow calculates grow(value, rate, time): value after growing at rate
+// for time. The rate says its period, as in 5% per year, and time is a
+// duration converted to that period or a number of them: grow($100, 5% per
+// year, 18 months) grows for 1.5 years. A plain percentage is per period,
+// and then time must be a number.
+func evalGrow(args []types.Type) (types.Type, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("grow() requires 3 arguments (value, rate, time)")
+	}
+
+	var rate, periods decimal.Decimal
+	switch r := args[1].(type) {
+	case *types.Rate:
+		if r.Amount == nil || r.Amount.Unit != "" {
+			return nil, fmt.Errorf("grow() rate must be a percentage per period like 5%% per year, got %s", r)
+		}
+		var err error
+		if rate, err = financeRate("grow", types.NewNumber(r.Amount.Value)); err != nil {
+			return nil, err
+		}
+		switch t := args[2].(type) {
+		case *types.Number:
+			periods = t.Value
+		case *types.Duration:
+			periods = periodsIn(t, r.PerUnit)
+		default:
+			return nil, fmt.Errorf("grow() time must be a duration or a number of periods, got %s", t)
+		}
+	case *types.Number:
+		var err error
+		if rate, err = financeRate("grow", r); err != nil {
+			return nil, err
+		}
+		t, ok := args[2].(*types.Number)
+		if !ok {
+			return nil, fmt.Errorf("grow() rate must give its period, like 5%% per year, to grow for %s", args[2])
+		}
+		periods = t.Value
+	default:
+		return nil, fmt.Errorf("grow() rate must be a percentage like 5%% per year, got %s", args[1])
+	}
+	factor, err := growthFactor(rate, periods)
+	if err != nil {
+		return nil, err
+	}
+	return scaleAmount("grow", args[0], factor)
+}
+
+// evalCompound calculates compound(value, rate, n): value after growing at
+// rate for n periods, value * (1 + rate) ^ n.
+func evalCompound(args []types.Type) (types.Type, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("compound() requires 3 arguments (value, rate, periods)")
+	}
+	rate, err := financeRate("compound", args[1])
+	if err != nil {
+		return nil, err
+	}
+	periods, err := financePeriods("compound", args[2])
+	if err != nil {
+		return nil, err
+	}
+	factor, err := growthFactor(rate, periods)
+	if err != nil {
+		return nil, err
+	}
+	return scaleAmount("compound", args[0], factor)
+}
+
+// growthFactor returns (1 + rate) ^ periods, exactly for whole periods.
+func growthFactor(rate, periods decimal.Decimal) (decimal.Decimal, error) {
+	base := decimal.NewFromInt(1).Add(rate)
+	if periods.IsInteger() {
+		return power(base, periods)
+	}
+	b, _ := base.Float64()
+	p, _ := periods.Float64()
+	return decimal.NewFromFloat(math.Pow(b, p)), nil
+}
+
+// growthAmount extracts an amount to grow, with its currency code or unit,
+// "" for a plain number.
+func growthAmount(name string, arg types.Type) (decimal.Decimal, string, error) {
+	switch v := arg.(type) {
+	case *types.Number:
+		return v.Value, "", nil
+	case *types.Currency:
+		return v.Value, v.Code, nil
+	case *types.Quantity:
+		return v.Value, v.Unit, nil
+	}
+	return decimal.Zero, "", fmt.Errorf("%s() amounts must be numbers, currencies, or quantities, got %s", name, formatTypeForError(arg))
+}
+
+// scaleAmount multiplies a number, currency, or quantity by factor,
+// keeping its currency or unit.
+func scaleAmount(name string, arg types.Type, factor decimal.Decimal) (types.Type, error) {
+	switch v := arg.(type) {
+	case *types.Number:
+		return types.NewNumber(v.Value.Mul(factor)), nil
+	case *types.Currency:
+		return &types.Currency{Value: v.Value.Mul(factor), Symbol: v.Symbol, Code: v.Code, RealYear: v.RealYear}, nil
+	case *types.Quantity:
+		return types.NewQuantity(v.Value.Mul(factor), v.Unit), nil
+	}
+	return nil, fmt.Errorf("%s() value must be a number, currency, or quantity, got %s", name, formatTypeForError(arg))
+}
+
+// growthYears extracts a positive number of years, or a duration in years.
+func growthYears(name string, arg types.Type) (decimal.Decimal, error) {
+	var years decimal.Decimal
+	switch v := arg.(type) {
+	case *types.Number:
+		years = v.Value
+	case *types.Duration:
+		years = periodsIn(v, "year")
+	default:
+		return decimal.Zero, fmt.Errorf("%s() years must be a number or a duration, got %s", name, formatTypeForError(arg))
+	}
+	if !years.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%s() years must be positive", name)
+	}
+	return years, nil
+}
+
+// periodsIn returns how many of unit, a time unit, d lasts. Months and
+// years count as twelfths of each other, as calendars do, rather than by
+// their average days.
+func periodsIn(d *types.Duration, unit string) decimal.Decimal {
+	from, to := types.NormalizeTimeUnit(d.Unit), types.NormalizeTimeUnit(unit)
+	months := map[string]int64{"month": 1, "year": 12}
+	if a, ok := months[from]; ok {
+		if b, ok := months[to]; ok {
+			return d.Value.Mul(decimal.NewFromInt(a)).Div(decimal.NewFromInt(b))
+		}
+	}
+	seconds, err := types.TimeUnitToSeconds(to)
+	if err != nil || seconds.IsZero() {
+		return d.Value
+	}
+	return d.ToSeconds().Div(seconds)
+}
@@ -0,0 +1,103 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+func TestGrowthFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"compound currency", "compound($1000, 5%, 3)\n", "$1157.63"},
+		{"compound number", "compound(100, 10%, 2)\n", "121"},
+		{"compound decline", "compound(€200, -50%, 2)\n", "€50.00"},
+		{"grow for years", "grow($100, 5% per year, 3 years)\n", "$115.76"},
+		{"grow for months", "grow($100, 5% per year, 18 months)\n", "$107.59"},
+		{"grow monthly rate for a year", "grow(100, 1% per month, 1 year)\n", "112.6825030131969720661201"},
+		{"grow a quantity", "grow(10 GB, 10% per year, 2 years)\n", "12.1 GB"},
+		{"grow for periods", "grow(1000, 2%, 2)\n", "1040.4"},
+		{"grow for a number of the rate's periods", "grow($100, 5% per year, 2)\n", "$110.25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			results, err := interpreter.NewInterpreter().Eval(nodes)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+
+			if actual := results[0].String(); actual != tt.expected {
+				t.Errorf("Result = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCAGR checks cagr() finds the yearly rate that grow() reverses.
+func TestCAGR(t *testing.T) {
+	input := "rate = cagr($100, $200, 5 years)\ncagr(100, 200, 60 months)\ncompound($100, rate, 5)\n"
+
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	results, err := interpreter.NewInterpreter().Eval(nodes)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	for i := range 2 {
+		rate, ok := results[i].(*types.Number)
+		if !ok || !rate.IsPercentage() {
+			t.Fatalf("cagr() = %T, want a percentage", results[i])
+		}
+		if got := rate.Value.String(); got != "0.148698354997035" {
+			t.Errorf("cagr() = %s, want 0.148698354997035", got)
+		}
+	}
+	if got := results[2].String(); got != "$200.00" {
+		t.Errorf("compound() at cagr() = %s, want $200.00", got)
+	}
+}
+
+func TestGrowthFunctionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"compound too few args", "compound($100, 5%)\n"},
+		{"compound currency rate", "compound($100, $5, 3)\n"},
+		{"compound of a date", "compound(Jan 1 2025, 5%, 3)\n"},
+		{"grow plain rate for a duration", "grow($100, 5%, 3 years)\n"},
+		{"grow rate of money", "grow($100, $5 per year, 3 years)\n"},
+		{"grow rate of -100%", "grow($100, -100% per year, 3 years)\n"},
+		{"cagr mixed units", "cagr($100, 200 kg, 3)\n"},
+		{"cagr zero start", "cagr(0, 100, 3)\n"},
+		{"cagr zero years", "cagr(100, 200, 0)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				return
+			}
+
+			_, err = interpreter.NewInterpreter().Eval(nodes)
+			if err == nil {
+				t.Errorf("Expected error for %q but got none", tt.input)
+			}
+		})
+	}
+}
@@ -17,7 +17,7 @@ func TestInUnitSyntax(t *testing.T) {
 	}{
 		// Length conversions
 		{"meters to feet", "10 meters in feet\n", "32.808 feet"},
-		{"feet to meters", "10 feet in meters\n", "3.047 meters"}, // Rounded to match FP precision
+		{"feet to meters", "10 feet in meters\n", "3.048 meters"}, // Exact: 1 ft = 0.3048 m
 		{"km to meters", "5 km in meters\n", "5000 meters"},
 
 		// Mass conversions
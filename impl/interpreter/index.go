@@ -0,0 +1,70 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// evalIndex evaluates "prices[2]" and "schedule[12].balance". Indexes
+// count from 1, as periods and rows do, and from the end when negative:
+// schedule[-1] is the last row.
+func (interp *Interpreter) evalIndex(i *ast.IndexExpr) (types.Type, error) {
+	target, err := interp.evalNode(i.Target)
+	if err != nil {
+		return nil, err
+	}
+	indexVal, err := interp.evalNode(i.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := target.(type) {
+	case *types.List:
+		if i.Field != "" {
+			return nil, fmt.Errorf("a list has no columns like .%s", i.Field)
+		}
+		pos, err := indexPosition(indexVal, t.Len())
+		if err != nil {
+			return nil, err
+		}
+		return t.Elements[pos], nil
+
+	case *types.Table:
+		pos, err := indexPosition(indexVal, t.Len())
+		if err != nil {
+			return nil, err
+		}
+		if i.Field == "" {
+			return types.NewTable(t.Columns, t.Rows[pos:pos+1]), nil
+		}
+		column, ok := t.Column(i.Field)
+		if !ok {
+			return nil, fmt.Errorf("no column '%s'; the columns are %s", i.Field, strings.Join(t.Columns, ", "))
+		}
+		return t.Rows[pos][column], nil
+	}
+	return nil, fmt.Errorf("cannot index %s: only lists and tables have elements", formatTypeForError(target))
+}
+
+// indexPosition returns the 0-based position index names among n
+// elements.
+func indexPosition(index types.Type, n int) (int, error) {
+	num, ok := index.(*types.Number)
+	if !ok || !num.Value.IsInteger() {
+		return 0, fmt.Errorf("index must be a whole number, got %s", formatTypeForError(index))
+	}
+	i := int(num.Value.IntPart())
+	switch {
+	case i == 0:
+		return 0, fmt.Errorf("indexes start at 1")
+	case i < 0:
+		i += n + 1
+	}
+	if i < 1 || i > n {
+		return 0, fmt.Errorf("index %s is out of range: there are %d", num.Value, n)
+	}
+	return i - 1, nil
+}
@@ -0,0 +1,51 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"prices = [10, 20, 30]\nprices[1]\n", "10"},
+		{"prices = [10, 20, 30]\nprices[-1]\n", "30"},
+		{"prices = [10, 20, 30]\nprices[1 + 1] * 2\n", "40"},
+		{"amortize(1200, 0, 1)[2]\n", "period 2, payment 100, interest 0, principal 100, balance 1000"},
+	}
+	for _, tt := range tests {
+		nodes, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.input, err)
+		}
+		results, err := interpreter.NewInterpreter().Eval(nodes)
+		if err != nil {
+			t.Fatalf("Eval(%q) error: %v", tt.input, err)
+		}
+		if actual := results[len(results)-1].String(); actual != tt.expected {
+			t.Errorf("%q = %s, expected %s", tt.input, actual, tt.expected)
+		}
+	}
+}
+
+func TestIndexErrors(t *testing.T) {
+	for _, input := range []string{
+		"[10, 20][0]\n",
+		"[10, 20][3]\n",
+		"[10, 20][1.5]\n",
+		"[10, 20][1].price\n",
+		"x = 5\nx[1]\n",
+	} {
+		nodes, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		if _, err := interpreter.NewInterpreter().Eval(nodes); err == nil {
+			t.Errorf("Expected error for %q but got none", input)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package interpreter
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Inflation: adjust(amount, from, to), also written adjust(amount from 2015
+// to 2025), restates an amount in one year's prices in another's using a
+// consumer price index: amount * CPI[to] / CPI[from]. The index comes from
+// the document's cpi: frontmatter, then from the evaluator's CPIProvider.
+//
+// A currency adjust() returns is real, in the prices of its to year, and
+// shows it: $67.82K (real 2025). Amounts are nominal otherwise. Real
+// amounts in different years' prices can't be added or compared, since the
+// sum would mean nothing; a nominal amount combined with a real one is
+// taken to be in its year's prices, as today's salary is in today's.
+
+// CPIProvider supplies the price index for years the document's
+// frontmatter doesn't list, so an application embedding CalcMark can offer
+// an official series.
+type CPIProvider interface {
+	// CPI returns the index for year, and false if it has none.
+	CPI(year int) (decimal.Decimal, bool)
+}
+
+// CPITable is a price index by year, a CPIProvider for a fixed series.
+type CPITable map[int]decimal.Decimal
+
+// CPI returns the index for year.
+func (t CPITable) CPI(year int) (decimal.Decimal, bool) {
+	index, ok := t[year]
+	return index, ok
+}
+
+// SetCPI sets the price index for year, as the cpi: frontmatter does.
+// It takes precedence over the CPI provider.
+func (e *Environment) SetCPI(year int, index decimal.Decimal) {
+	if e.cpi == nil {
+		e.cpi = make(CPITable)
+	}
+	e.cpi[year] = index
+	e.touch()
+}
+
+// SetCPIProvider sets where adjust() finds the price index for years SetCPI
+// didn't set; nil for nowhere.
+func (e *Environment) SetCPIProvider(p CPIProvider) {
+	e.cpiProvider = p
+	e.touch()
+}
+
+// CPI returns the price index for year, and false if neither the table nor
+// the provider has it.
+func (e *Environment) CPI(year int) (decimal.Decimal, bool) {
+	if index, ok := e.cpi[year]; ok {
+		return index, true
+	}
+	if e.cpiProvider != nil {
+		return e.cpiProvider.CPI(year)
+	}
+	return decimal.Zero, false
+}
+
+// clone returns a copy of the price index table, nil if there is none.
+func (t CPITable) clone() CPITable {
+	if t == nil {
+		return nil
+	}
+	return maps.Clone(t)
+}
+
+// evalAdjust calculates adjust(amount, from, to): amount, in from's
+// prices, in to's. A currency comes back real in to's prices; a number is
+// scaled alone.
+func (interp *Interpreter) evalAdjust(args []types.Type) (types.Type, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("adjust() requires 3 arguments (amount, from year, to year)")
+	}
+	from, err := adjustYear("from", args[1])
+	if err != nil {
+		return nil, err
+	}
+	to, err := adjustYear("to", args[2])
+	if err != nil {
+		return nil, err
+	}
+	if cur, ok := args[0].(*types.Currency); ok && cur.RealYear != 0 && cur.RealYear != from {
+		return nil, fmt.Errorf("adjust() amount is in %d prices, not %d", cur.RealYear, from)
+	}
+
+	factor := decimal.NewFromInt(1)
+	if from != to {
+		fromIndex, ok := interp.env.CPI(from)
+		if !ok {
+			return nil, fmt.Errorf("adjust() has no CPI for %d; add it under cpi: in the frontmatter", from)
+		}
+		toIndex, ok := interp.env.CPI(to)
+		if !ok {
+			return nil, fmt.Errorf("adjust() has no CPI for %d; add it under cpi: in the frontmatter", to)
+		}
+		factor = toIndex.Div(fromIndex)
+	}
+
+	switch v := args[0].(type) {
+	case *types.Number:
+		return types.NewNumber(v.Value.Mul(factor)), nil
+	case *types.Currency:
+		return &types.Currency{Value: v.Value.Mul(factor), Symbol: v.Symbol, Code: v.Code, RealYear: to}, nil
+	}
+	return nil, fmt.Errorf("adjust() amount must be a currency or a number, got %s", formatTypeForError(args[0]))
+}
+
+// adjustYear extracts a year argument of adjust(), a whole number.
+func adjustYear(which string, arg types.Type) (int, error) {
+	num, ok := arg.(*types.Number)
+	if !ok || !num.Value.IsInteger() || !num.Value.IsPositive() {
+		return 0, fmt.Errorf("adjust() %s year must be a year like 2015, got %s", which, formatTypeForError(arg))
+	}
+	return int(num.Value.IntPart()), nil
+}
+
+// realYear returns the year whose prices the result of combining a and b
+// with operator is in: the year of the real one, zero if both are nominal,
+// or an error if they are real in different years.
+func realYear(a, b *types.Currency, operator string) (int, error) {
+	switch {
+	case a.RealYear == 0:
+		return b.RealYear, nil
+	case b.RealYear == 0 || a.RealYear == b.RealYear:
+		return a.RealYear, nil
+	}
+	return 0, fmt.Errorf("cannot %s amounts in %d and %d prices; adjust() one to the other's year", operator, a.RealYear, b.RealYear)
+}
@@ -0,0 +1,111 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// cpiEnvironment returns an environment with an index that doubles
+// prices from 2000 to 2020, by 1.25 to 2010.
+func cpiEnvironment() *interpreter.Environment {
+	env := interpreter.NewEnvironment()
+	env.SetCPI(2000, decimal.NewFromInt(100))
+	env.SetCPI(2010, decimal.NewFromInt(125))
+	env.SetCPI(2020, decimal.NewFromInt(200))
+	return env
+}
+
+// evalInflation evaluates input in env and returns its results.
+func evalInflation(t *testing.T, env *interpreter.Environment, input string) ([]types.Type, error) {
+	t.Helper()
+	nodes, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	return interpreter.NewInterpreterWithEnv(env).Eval(nodes)
+}
+
+func TestAdjust(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"natural form", "adjust($1000 from 2000 to 2020)\n", "$2000.00 (real 2020)"},
+		{"arguments", "adjust($1000, 2000, 2010)\n", "$1250.00 (real 2010)"},
+		{"back in time", "adjust(€500 from 2020 to 2000)\n", "€250.00 (real 2000)"},
+		{"same year", "adjust($80, 1990, 1990)\n", "$80.00 (real 1990)"},
+		{"number", "adjust(40 from 2000 to 2010)\n", "50"},
+		{"variable years", "y = 2010\nadjust($100 from y to y + 10)\n", "$160.00 (real 2020)"},
+		{"scaled real amount", "adjust($100 from 2000 to 2020) * 3\n", "$600.00 (real 2020)"},
+		{"real plus nominal", "adjust($100 from 2000 to 2020) + $50\n", "$250.00 (real 2020)"},
+		{"adjusted again", "adjust(adjust($100 from 2000 to 2010) from 2010 to 2020)\n", "$200.00 (real 2020)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := evalInflation(t, cpiEnvironment(), tt.input)
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+			if actual := results[len(results)-1].String(); actual != tt.expected {
+				t.Errorf("Result = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAdjustErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"missing year", "adjust($100 from 2000 to 2030)\n", "no CPI for 2030"},
+		{"fractional year", "adjust($100, 2000.5, 2020)\n", "from year must be a year"},
+		{"date amount", "adjust(Jan 1 2020, 2000, 2020)\n", "amount must be a currency or a number"},
+		{"wrong from year", "adjust(adjust($100 from 2000 to 2010) from 2000 to 2020)\n", "amount is in 2010 prices, not 2000"},
+		{"mixed years", "adjust($100 from 2000 to 2010) + adjust($100 from 2000 to 2020)\n", "amounts in 2010 and 2020 prices"},
+		{"mixed years compared", "adjust($100 from 2000 to 2010) > adjust($100 from 2000 to 2020)\n", "cannot compare amounts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evalInflation(t, cpiEnvironment(), tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCPIProvider checks the provider answers for years the table lacks,
+// and the table wins where both have a year.
+func TestCPIProvider(t *testing.T) {
+	env := interpreter.NewEnvironment()
+	env.SetCPIProvider(interpreter.CPITable{
+		2000: decimal.NewFromInt(100),
+		2020: decimal.NewFromInt(300),
+	})
+	results, err := evalInflation(t, env, "adjust($100 from 2000 to 2020)\n")
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+	if got := results[0].String(); got != "$300.00 (real 2020)" {
+		t.Errorf("with the provider, Result = %s", got)
+	}
+
+	env.SetCPI(2020, decimal.NewFromInt(200))
+	results, err = evalInflation(t, env.Clone(), "adjust($100 from 2000 to 2020)\n")
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+	if got := results[0].String(); got != "$200.00 (real 2020)" {
+		t.Errorf("with the table, Result = %s", got)
+	}
+}
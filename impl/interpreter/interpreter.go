@@ -1,6 +1,7 @@
 package interpreter
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
@@ -11,12 +12,14 @@ import (
 // This is a Go-specific implementation of CalcMark execution.
 type Interpreter struct {
 	env *Environment
+	ctx context.Context // Stops evaluation when done; see EvalContext
 }
 
 // NewInterpreter creates a new interpreter with an empty environment.
 func NewInterpreter() *Interpreter {
 	return &Interpreter{
 		env: NewEnvironment(),
+		ctx: context.Background(),
 	}
 }
 
@@ -24,18 +27,43 @@ func NewInterpreter() *Interpreter {
 func NewInterpreterWithEnv(env *Environment) *Interpreter {
 	return &Interpreter{
 		env: env,
+		ctx: context.Background(),
 	}
 }
 
+// StatementError is an error evaluating a statement, with the statement's
+// range in the source.
+type StatementError struct {
+	Range *ast.Range
+	Err   error
+}
+
+func (e *StatementError) Error() string { return e.Err.Error() }
+
+func (e *StatementError) Unwrap() error { return e.Err }
+
 // Eval executes a list of AST nodes and returns the results.
-// Each node produces a typed value.
+// Each node produces a typed value. An error evaluating a node is a
+// *StatementError.
 func (interp *Interpreter) Eval(nodes []ast.Node) ([]types.Type, error) {
+	return interp.EvalContext(context.Background(), nodes)
+}
+
+// EvalContext executes nodes like Eval, but stops once ctx is done,
+// returning its error (wrapped in a *StatementError), so an evaluation
+// that is taking too long can be abandoned without leaving it running.
+// ctx is checked before each node is evaluated; the assignments of the
+// statements before that stay in the environment.
+func (interp *Interpreter) EvalContext(ctx context.Context, nodes []ast.Node) ([]types.Type, error) {
+	interp.ctx = ctx
+	defer func() { interp.ctx = context.Background() }()
+
 	results := make([]types.Type, 0, len(nodes))
 
 	for _, node := range nodes {
 		result, err := interp.evalNode(node)
 		if err != nil {
-			return nil, err
+			return nil, &StatementError{Range: node.GetRange(), Err: err}
 		}
 		if result != nil {
 			results = append(results, result)
@@ -50,6 +78,9 @@ func (interp *Interpreter) evalNode(node ast.Node) (types.Type, error) {
 	if node == nil {
 		return nil, nil
 	}
+	if err := interp.ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	switch n := node.(type) {
 	case *ast.Assignment:
@@ -71,6 +102,8 @@ func (interp *Interpreter) evalNode(node ast.Node) (types.Type, error) {
 		return interp.evalNumberLiteral(n)
 	case *ast.CurrencyLiteral:
 		return interp.evalCurrencyLiteral(n)
+	case *ast.CurrencyUnit:
+		return interp.evalCurrencyUnit(n)
 	case *ast.BooleanLiteral:
 		return interp.evalBooleanLiteral(n)
 	case *ast.DateLiteral:
@@ -81,18 +114,28 @@ func (interp *Interpreter) evalNode(node ast.Node) (types.Type, error) {
 		return interp.evalDurationLiteral(n)
 	case *ast.RelativeDateLiteral:
 		return interp.evalRelativeDateLiteral(n)
+	case *ast.ScheduleLiteral:
+		return interp.evalScheduleLiteral(n)
 	case *ast.QuantityLiteral:
 		return interp.evalQuantityLiteral(n)
 	case *ast.RateLiteral:
 		return interp.evalRateLiteral(n)
+	case *ast.IndexExpr:
+		return interp.evalIndex(n)
 	case *ast.UnitConversion:
 		return interp.evalUnitConversion(n)
+	case *ast.TimeZoneConversion:
+		return interp.evalTimeZoneConversion(n)
 	case *ast.NapkinConversion:
 		return interp.evalNapkinConversion(n)
 	case *ast.PercentageOf:
 		return interp.evalPercentageOf(n)
 	case *ast.FunctionCall:
 		return interp.evalFunctionCall(n)
+	case *ast.ListLiteral:
+		return interp.evalListLiteral(n)
+	case *ast.StringLiteral:
+		return nil, fmt.Errorf("quoted text %q is not a value; it can only name a file, column, key, or path in lookup() and jsonpath()", n.Value)
 	default:
 		return nil, fmt.Errorf("unknown node type: %T", node)
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 )
@@ -161,7 +162,7 @@ func TestEvalNumberOperation(t *testing.T) {
 			leftNum := &types.Number{Value: left}
 			rightNum := &types.Number{Value: right}
 
-			result, err := evalNumberOperation(leftNum, rightNum, tt.operator)
+			result, err := evalNumberOperation(leftNum, rightNum, tt.operator, DefaultDivisionPrecision)
 			if err != nil {
 				t.Fatalf("evalNumberOperation error = %v", err)
 			}
@@ -188,6 +189,18 @@ func TestEnvironment(t *testing.T) {
 	if env.Has("y") {
 		t.Error("Expected variable 'y' to be undefined")
 	}
+
+	// Variables are found by their symbol and listed by name
+	env.Set("rent", types.NewNumber(decimal.NewFromInt(1200)))
+	if v, ok := env.GetSymbol(lexer.SymbolFor("rent")); !ok || v.String() != "1200" {
+		t.Errorf("GetSymbol(rent) = %v, %v", v, ok)
+	}
+	if v := env.GetAllVariables()["rent"]; v == nil || v.String() != "1200" {
+		t.Errorf("GetAllVariables()[rent] = %v", v)
+	}
+	if clone := env.Clone(); !clone.Has("rent") || len(clone.GetAllVariables()) != len(env.GetAllVariables()) {
+		t.Error("Clone lost variables")
+	}
 }
 
 // Test variable assignment and lookup
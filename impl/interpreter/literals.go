@@ -37,6 +37,19 @@ func (interp *Interpreter) evalBooleanLiteral(b *ast.BooleanLiteral) (types.Type
 	return types.NewBoolean(value), nil
 }
 
+func (interp *Interpreter) evalListLiteral(l *ast.ListLiteral) (types.Type, error) {
+	elements := make([]types.Type, len(l.Elements))
+	for i, elem := range l.Elements {
+		val, err := interp.evalNode(elem)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = val
+	}
+
+	return types.NewList(elements), nil
+}
+
 func (interp *Interpreter) evalQuantityLiteral(q *ast.QuantityLiteral) (types.Type, error) {
 	// Expand multipliers like "1k" → 1000, "1M" → 1000000
 	value, err := expandNumberLiteral(q.Value)
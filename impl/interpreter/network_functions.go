@@ -143,23 +143,31 @@ func calculateTransferTime(size *types.Quantity, scope string, networkType strin
 func convertToMegabytes(q *types.Quantity) (float64, error) {
 	unitLower := strings.ToLower(q.Unit)
 
-	// Conversion: unit → bytes → megabytes
+	// Conversion: unit → bytes → megabytes (10^6 bytes)
 	var bytesPerUnit float64
 	switch unitLower {
 	case "byte", "bytes", "b":
 		bytesPerUnit = 1
 	case "kilobyte", "kilobytes", "kb":
-		bytesPerUnit = 1024
+		bytesPerUnit = 1e3
 	case "megabyte", "megabytes", "mb":
-		bytesPerUnit = 1024 * 1024
+		bytesPerUnit = 1e6
 	case "gigabyte", "gigabytes", "gb":
-		bytesPerUnit = 1024 * 1024 * 1024
+		bytesPerUnit = 1e9
 	case "terabyte", "terabytes", "tb":
-		bytesPerUnit = 1024 * 1024 * 1024 * 1024
+		bytesPerUnit = 1e12
+	case "kibibyte", "kibibytes", "kib":
+		bytesPerUnit = 1 << 10
+	case "mebibyte", "mebibytes", "mib":
+		bytesPerUnit = 1 << 20
+	case "gibibyte", "gibibytes", "gib":
+		bytesPerUnit = 1 << 30
+	case "tebibyte", "tebibytes", "tib":
+		bytesPerUnit = 1 << 40
 	default:
 		return 0, fmt.Errorf("not a byte-based unit: %s", q.Unit)
 	}
 
 	bytes := q.Value.InexactFloat64() * bytesPerUnit
-	return bytes / (1024 * 1024), nil
+	return bytes / 1e6, nil
 }
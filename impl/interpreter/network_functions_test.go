@@ -124,7 +124,7 @@ func TestCalculateTransferTime(t *testing.T) {
 			name:      "1 GB global gigabit",
 			sizeValue: 1, sizeUnit: "gigabyte",
 			scope: "global", networkType: "gigabit",
-			expectedMs: 8342.0, // 150ms RTT + ~8192ms transmission
+			expectedMs: 8150.0, // 150ms RTT + 8000ms transmission
 			tolerance:  50.0,
 		},
 		{
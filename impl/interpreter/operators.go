@@ -5,6 +5,7 @@ import (
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/CalcMark/go-calcmark/spec/units"
 	"github.com/shopspring/decimal"
 )
 
@@ -21,7 +22,21 @@ func (interp *Interpreter) evalBinaryOp(b *ast.BinaryOp) (types.Type, error) {
 		return nil, err
 	}
 
-	return evalBinaryOperation(left, right, b.Operator)
+	// Distributions apply the operator to each sample
+	if isDistribution(left, right) {
+		return evalDistributionOperation(left, right, func(l, r types.Type) (types.Type, error) {
+			return evalBinaryOperation(l, r, b.Operator, interp.env.DivisionPrecision())
+		})
+	}
+
+	// Business days depend on the document's holiday calendar
+	if date, ok := left.(*types.Date); ok {
+		if dur, ok := right.(*types.Duration); ok && dur.IsBusinessDays() {
+			return addBusinessDays(date, dur, b.Operator, interp.env.Calendar())
+		}
+	}
+
+	return evalBinaryOperation(left, right, b.Operator, interp.env.DivisionPrecision())
 }
 
 func (interp *Interpreter) evalComparisonOp(c *ast.ComparisonOp) (types.Type, error) {
@@ -35,6 +50,13 @@ func (interp *Interpreter) evalComparisonOp(c *ast.ComparisonOp) (types.Type, er
 		return nil, err
 	}
 
+	// A distribution's samples would compare differently
+	if isDistribution(left, right) {
+		return nil, fmt.Errorf("cannot compare a distribution: it holds many possible values")
+	}
+	if isBasket(left, right) {
+		return nil, fmt.Errorf("cannot compare a currency basket: convert it to one currency first, e.g. 'basket in USD'")
+	}
 	return evalComparison(left, right, c.Operator)
 }
 
@@ -44,12 +66,22 @@ func (interp *Interpreter) evalUnaryOp(u *ast.UnaryOp) (types.Type, error) {
 		return nil, err
 	}
 
+	if isDistribution(operand, nil) {
+		return evalDistributionOperation(operand, nil, func(o, _ types.Type) (types.Type, error) {
+			return evalUnaryOperation(o, u.Operator)
+		})
+	}
 	return evalUnaryOperation(operand, u.Operator)
 }
 
-// evalBinaryOperation performs binary arithmetic operations.
+// evalBinaryOperation performs binary arithmetic operations, dividing to
+// precision decimal places (see Environment.DivisionPrecision).
 // This is a pure function for easier testing.
-func evalBinaryOperation(left, right types.Type, operator string) (types.Type, error) {
+func evalBinaryOperation(left, right types.Type, operator string, precision int32) (types.Type, error) {
+	if isBasket(left, right) {
+		return evalBasketOperation(left, right, operator, precision)
+	}
+
 	// Boolean operations (AND, OR)
 	if leftBool, ok := left.(*types.Boolean); ok {
 		if rightBool, ok := right.(*types.Boolean); ok {
@@ -76,12 +108,13 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 
 			// Note: We can't distinguish if rightNum came from a % literal
 			// So we'll handle this in a special case if needed
-			return evalNumberOperation(leftNum, rightNum, operator)
+			return evalNumberOperation(leftNum, rightNum, operator, precision)
 		}
 		// Number * Currency → Currency
 		if rightCur, ok := right.(*types.Currency); ok && operator == "*" {
-			result := leftNum.Value.Mul(rightCur.Value)
-			return types.NewCurrency(result, rightCur.Symbol), nil
+			result := types.NewCurrency(leftNum.Value.Mul(rightCur.Value), rightCur.Symbol)
+			result.RealYear = rightCur.RealYear
+			return result, nil
 		}
 		// Number * Duration → Duration
 		if rightDur, ok := right.(*types.Duration); ok && operator == "*" {
@@ -90,12 +123,25 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 		}
 	}
 
+	// Amount / Duration → Rate (e.g., 120 km / 2 hours = 60 km/h)
+	if rightDur, ok := right.(*types.Duration); ok && operator == "/" {
+		switch left.(type) {
+		case *types.Quantity, *types.Currency, *types.Number:
+			return evalPerDuration(left, rightDur, precision)
+		}
+	}
+
 	// Currency operations
 	if leftCur, ok := left.(*types.Currency); ok {
+		// Currency / Quantity → compound quantity (e.g., $10 / 2 kg = 5 $/kg)
+		if rightQty, ok := right.(*types.Quantity); ok && operator == "/" {
+			return evalCurrencyPerQuantity(leftCur, rightQty, precision)
+		}
 		// Currency * Number → Currency
 		if rightNum, ok := right.(*types.Number); ok && operator == "*" {
-			result := leftCur.Value.Mul(rightNum.Value)
-			return types.NewCurrency(result, leftCur.Symbol), nil
+			result := types.NewCurrency(leftCur.Value.Mul(rightNum.Value), leftCur.Symbol)
+			result.RealYear = leftCur.RealYear
+			return result, nil
 		}
 		// Currency op Currency (same type)
 		if rightCur, ok := right.(*types.Currency); ok {
@@ -103,15 +149,22 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 				return nil, fmt.Errorf("cannot %s different currencies: %s and %s",
 					operator, leftCur.Symbol, rightCur.Symbol)
 			}
+			year, err := realYear(leftCur, rightCur, operator)
+			if err != nil {
+				return nil, err
+			}
 			result, err := evalNumberOperation(
-				&types.Number{Value: leftCur.Value},
-				&types.Number{Value: rightCur.Value},
+				types.NewNumber(leftCur.Value),
+				types.NewNumber(rightCur.Value),
 				operator,
+				precision,
 			)
 			if err != nil {
 				return nil, err
 			}
-			return types.NewCurrency(result.(*types.Number).Value, leftCur.Symbol), nil
+			cur := types.NewCurrency(result.(*types.Number).Value, leftCur.Symbol)
+			cur.RealYear = year
+			return cur, nil
 		}
 	}
 
@@ -131,7 +184,15 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 			return evalDurationOperation(leftDur, rightDur, operator)
 		}
 		if rightNum, ok := right.(*types.Number); ok {
-			return evalDurationNumberOperation(leftDur, rightNum, operator)
+			return evalDurationNumberOperation(leftDur, rightNum, operator, precision)
+		}
+		// Duration * Rate → accumulated quantity
+		if rightRate, ok := right.(*types.Rate); ok && operator == "*" {
+			return evalRateDuration(rightRate, leftDur)
+		}
+		// Duration * Bandwidth → data
+		if rightQty, ok := right.(*types.Quantity); ok && operator == "*" && units.IsBandwidthUnit(rightQty.Unit) {
+			return evalBandwidthDuration(rightQty, leftDur)
 		}
 	}
 
@@ -151,16 +212,20 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 					return nil, fmt.Errorf("division by zero")
 				}
 				return &types.Rate{
-					Amount:  &types.Quantity{Value: leftRate.Amount.Value.Div(rightNum.Value), Unit: leftRate.Amount.Unit},
+					Amount:  &types.Quantity{Value: leftRate.Amount.Value.DivRound(rightNum.Value, precision), Unit: leftRate.Amount.Unit},
 					PerUnit: leftRate.PerUnit,
 				}, nil
 			}
 		}
+		// Rate * Duration → Quantity (e.g., 60 km/h * 2 hours = 120 km)
+		if rightDur, ok := right.(*types.Duration); ok && operator == "*" {
+			return evalRateDuration(leftRate, rightDur)
+		}
 		// Rate / Rate → Number (if same per-units, dimensionless ratio)
 		if rightRate, ok := right.(*types.Rate); ok {
 			if operator == "/" && leftRate.PerUnit == rightRate.PerUnit {
 				// Same time units, divide amounts and return dimensionless number
-				result := leftRate.Amount.Value.Div(rightRate.Amount.Value)
+				result := leftRate.Amount.Value.DivRound(rightRate.Amount.Value, precision)
 				return types.NewNumber(result), nil
 			}
 		}
@@ -168,8 +233,16 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 
 	// Quantity operations (with unit conversion - USER REQUIREMENT: first-unit-wins)
 	if leftQty, ok := left.(*types.Quantity); ok {
+		// Quantity / Rate → Duration (e.g., 10 GB / 125 MB/s)
+		if rightRate, ok := right.(*types.Rate); ok && operator == "/" {
+			return evalQuantityPerRate(leftQty, rightRate, precision)
+		}
+		// Bandwidth * Duration → data (e.g., 100 Mbps * 1 minute)
+		if rightDur, ok := right.(*types.Duration); ok && operator == "*" && units.IsBandwidthUnit(leftQty.Unit) {
+			return evalBandwidthDuration(leftQty, rightDur)
+		}
 		if rightQty, ok := right.(*types.Quantity); ok {
-			return evalQuantityOperation(leftQty, rightQty, operator)
+			return evalQuantityOperation(leftQty, rightQty, operator, precision)
 		}
 		// Quantity op Number (e.g., "10 dogs * 2" = "20 dogs", "5 dogs + 3" = "8 dogs")
 		if rightNum, ok := right.(*types.Number); ok {
@@ -177,7 +250,7 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 			case "*":
 				return &types.Quantity{Value: leftQty.Value.Mul(rightNum.Value), Unit: leftQty.Unit}, nil
 			case "/":
-				return &types.Quantity{Value: leftQty.Value.Div(rightNum.Value), Unit: leftQty.Unit}, nil
+				return &types.Quantity{Value: leftQty.Value.DivRound(rightNum.Value, precision), Unit: leftQty.Unit}, nil
 			case "+":
 				return &types.Quantity{Value: leftQty.Value.Add(rightNum.Value), Unit: leftQty.Unit}, nil
 			case "-":
@@ -205,30 +278,33 @@ func evalBinaryOperation(left, right types.Type, operator string) (types.Type, e
 	return nil, unsupportedOperationError(left, right, operator)
 }
 
-// evalNumberOperation performs operations on two numbers.
-func evalNumberOperation(left, right *types.Number, operator string) (types.Type, error) {
+// evalNumberOperation performs operations on two numbers, dividing to
+// precision decimal places.
+func evalNumberOperation(left, right *types.Number, operator string, precision int32) (types.Type, error) {
 	var result decimal.Decimal
 
 	switch operator {
 	case "+":
-		result = left.Value.Add(right.Value)
+		return left.Add(right), nil
 	case "-":
-		result = left.Value.Sub(right.Value)
+		return left.Sub(right), nil
 	case "*":
-		result = left.Value.Mul(right.Value)
+		return left.Mul(right), nil
 	case "/":
 		if right.Value.IsZero() {
 			return nil, fmt.Errorf("division by zero")
 		}
-		result = left.Value.Div(right.Value)
+		return left.DivRound(right, precision), nil
 	case "%":
 		if right.Value.IsZero() {
 			return nil, fmt.Errorf("division by zero")
 		}
 		result = left.Value.Mod(right.Value)
 	case "^":
-		// Exponentiation
-		result = left.Value.Pow(right.Value)
+		var err error
+		if result, err = power(left.Value, right.Value); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unknown operator: %s", operator)
 	}
@@ -238,6 +314,10 @@ func evalNumberOperation(left, right *types.Number, operator string) (types.Type
 
 // evalDateDurationOperation handles date ± duration.
 func evalDateDurationOperation(date *types.Date, dur *types.Duration, operator string) (types.Type, error) {
+	if dur.IsBusinessDays() {
+		return addBusinessDays(date, dur, operator, nil)
+	}
+
 	// Convert duration to days (approximate for non-day units)
 	days := durationToDays(dur)
 
@@ -251,6 +331,26 @@ func evalDateDurationOperation(date *types.Date, dur *types.Duration, operator s
 	}
 }
 
+// addBusinessDays handles date ± business days, skipping weekends and the
+// calendar's holidays.
+func addBusinessDays(date *types.Date, dur *types.Duration, operator string, calendar *types.Calendar) (types.Type, error) {
+	if !dur.Value.IsInteger() {
+		return nil, fmt.Errorf("business days must be whole, got %s", dur.Value)
+	}
+	if dur.Value.Abs().GreaterThan(decimal.NewFromInt(types.MaxBusinessDays)) {
+		return nil, fmt.Errorf("cannot add %s business days: at most %d", dur.Value, types.MaxBusinessDays)
+	}
+	days := int(dur.Value.IntPart())
+	switch operator {
+	case "+":
+		return calendar.AddBusinessDays(date, days)
+	case "-":
+		return calendar.AddBusinessDays(date, -days)
+	default:
+		return nil, fmt.Errorf("unsupported date-duration operation: %s", operator)
+	}
+}
+
 // evalDateDateOperation handles date - date → duration.
 func evalDateDateOperation(left, right *types.Date, operator string) (types.Type, error) {
 	if operator != "-" {
@@ -288,8 +388,9 @@ func evalDurationOperation(left, right *types.Duration, operator string) (types.
 	return &types.Duration{Value: resultValue, Unit: left.Unit}, nil
 }
 
-// evalDurationNumberOperation handles duration * number or duration / number.
-func evalDurationNumberOperation(dur *types.Duration, num *types.Number, operator string) (types.Type, error) {
+// evalDurationNumberOperation handles duration * number or duration / number,
+// dividing to precision decimal places.
+func evalDurationNumberOperation(dur *types.Duration, num *types.Number, operator string, precision int32) (types.Type, error) {
 	var result decimal.Decimal
 
 	switch operator {
@@ -299,7 +400,7 @@ func evalDurationNumberOperation(dur *types.Duration, num *types.Number, operato
 		if num.Value.IsZero() {
 			return nil, fmt.Errorf("division by zero")
 		}
-		result = dur.Value.Div(num.Value)
+		result = dur.Value.DivRound(num.Value, precision)
 	default:
 		return nil, fmt.Errorf("unsupported duration-number operation: %s", operator)
 	}
@@ -331,7 +432,9 @@ func evalUnaryOperation(operand types.Type, operator string) (types.Type, error)
 	if cur, ok := operand.(*types.Currency); ok {
 		switch operator {
 		case "-":
-			return types.NewCurrency(cur.Value.Neg(), cur.Symbol), nil
+			neg := types.NewCurrency(cur.Value.Neg(), cur.Symbol)
+			neg.RealYear = cur.RealYear
+			return neg, nil
 		case "+":
 			return cur, nil
 		default:
@@ -361,6 +464,17 @@ func evalUnaryOperation(operand types.Type, operator string) (types.Type, error)
 		}
 	}
 
+	if basket, ok := operand.(*types.Basket); ok {
+		switch operator {
+		case "-":
+			return basket.Scale(decimal.NewFromInt(-1)), nil
+		case "+":
+			return basket, nil
+		default:
+			return nil, fmt.Errorf("unknown unary operator: %s", operator)
+		}
+	}
+
 	return nil, fmt.Errorf("unsupported unary operation on %T", operand)
 }
 
@@ -379,6 +493,9 @@ func evalComparison(left, right types.Type, operator string) (types.Type, error)
 			if leftCur.Code != rightCur.Code {
 				return nil, fmt.Errorf("cannot compare different currencies: %s and %s", leftCur.Code, rightCur.Code)
 			}
+			if _, err := realYear(leftCur, rightCur, "compare"); err != nil {
+				return nil, err
+			}
 			return compareNumbers(leftCur.Value, rightCur.Value, operator), nil
 		}
 	}
@@ -443,6 +560,7 @@ func getDurationFactorDecimal(unit string) decimal.Decimal {
 		"week": decimal.NewFromInt(604800), "weeks": decimal.NewFromInt(604800),
 		"month": decimal.NewFromInt(2592000), "months": decimal.NewFromInt(2592000), // 30 days
 		"year": decimal.NewFromInt(31536000), "years": decimal.NewFromInt(31536000), // 365 days
+		types.BusinessDayUnit: decimal.NewFromInt(86400), "business days": decimal.NewFromInt(86400),
 	}
 	if f, ok := factors[unit]; ok {
 		return f
@@ -500,6 +618,14 @@ func formatTypeForError(t types.Type) string {
 		return fmt.Sprintf("date (%s)", v.String())
 	case *types.Boolean:
 		return fmt.Sprintf("boolean (%s)", v.String())
+	case *types.List:
+		return fmt.Sprintf("list (%s)", v.String())
+	case *types.Distribution:
+		return fmt.Sprintf("distribution (%s)", v.String())
+	case *types.Basket:
+		return fmt.Sprintf("currency basket (%s)", v.String())
+	case *types.Table:
+		return fmt.Sprintf("table (%s)", v.String())
 	default:
 		return fmt.Sprintf("%T", t)
 	}
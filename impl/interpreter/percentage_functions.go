@@ -0,0 +1,71 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Percentage functions: increase, decrease, percent_change.
+//
+// They back the natural phrases "increase 100 by 10%", "15% off $200"
+// (decrease), and "change from 80 to 100 in %". The value changed may be a
+// number, a currency, or a quantity and keeps its currency or unit; the
+// change percent_change() finds is a number shown as a percentage, 25%.
+
+// evalIncrease calculates increase(value, percentage): value * (1 + percentage).
+func evalIncrease(args []types.Type) (types.Type, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("increase() requires 2 arguments (value, percentage)")
+	}
+	percent, err := percentArg("increase", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return scaleAmount("increase", args[0], decimal.NewFromInt(1).Add(percent))
+}
+
+// evalDecrease calculates decrease(value, percentage): value * (1 - percentage).
+func evalDecrease(args []types.Type) (types.Type, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("decrease() requires 2 arguments (value, percentage)")
+	}
+	percent, err := percentArg("decrease", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return scaleAmount("decrease", args[0], decimal.NewFromInt(1).Sub(percent))
+}
+
+// evalPercentChange calculates percent_change(from, to): (to - from) / from,
+// the change relative to where it started, to precision decimal places.
+func evalPercentChange(args []types.Type, precision int32) (types.Type, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("percent_change() requires 2 arguments (from, to)")
+	}
+	from, fromUnit, err := growthAmount("percent_change", args[0])
+	if err != nil {
+		return nil, err
+	}
+	to, toUnit, err := growthAmount("percent_change", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if fromUnit != toUnit {
+		return nil, fmt.Errorf("change from %s to %s must be in the same unit", args[0], args[1])
+	}
+	if from.IsZero() {
+		return nil, fmt.Errorf("change from zero has no percentage")
+	}
+	return types.NewPercentage(to.Sub(from).DivRound(from.Abs(), precision)), nil
+}
+
+// percentArg extracts a percentage, which must be a plain number like 10%.
+func percentArg(name string, arg types.Type) (decimal.Decimal, error) {
+	num, ok := arg.(*types.Number)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%s() percentage must be a number like 10%%, got %s", name, formatTypeForError(arg))
+	}
+	return num.Value, nil
+}
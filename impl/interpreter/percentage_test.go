@@ -41,6 +41,14 @@ func TestPercentageCalculations(t *testing.T) {
 		{"25% of 80", "25% of 80\n", "20"},
 		{"100% of 50", "100% of 50\n", "50"},
 		{"1% of 1000", "1% of 1000\n", "10"},
+
+		// Percentage phrases
+		{"15% off $200", "15% off $200\n", "$170.00"},
+		{"increase by", "increase 100 by 10%\n", "110"},
+		{"decrease by", "decrease 80 kg by 25%\n", "60 kg"},
+		{"increase function", "increase($50, 10%)\n", "$55.00"},
+		{"change up", "change from 80 to 100 in %\n", "0.25"}, // Shown as 25%
+		{"change down", "change from $100 to $80 in %\n", "-0.2"},
 	}
 
 	for _, tt := range tests {
@@ -67,3 +75,21 @@ func TestPercentageCalculations(t *testing.T) {
 		})
 	}
 }
+
+// TestPercentageChangeErrors checks change from one value to another needs
+// a nonzero start in the same unit as the end.
+func TestPercentageChangeErrors(t *testing.T) {
+	for _, input := range []string{
+		"change from 0 to 5 in %\n",
+		"change from 5 kg to 5 m in %\n",
+		"change from $5 to €6 in %\n",
+	} {
+		nodes, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("%q: parse error: %v", input, err)
+		}
+		if _, err := interpreter.NewInterpreter().Eval(nodes); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxExactPowerDigits is the most significant digits a power is worked out
+// with exactly. Past it, each step of raising to a power is rounded to this
+// many significant digits, far more than any result shows, so that
+// 1.0000001 ^ 9999999 takes a few dozen small multiplications instead of
+// building a number tens of millions of digits long.
+const maxExactPowerDigits = 1000
+
+// maxPowerMagnitude is the most digits a power's result may have before or
+// after the decimal point.
+const maxPowerMagnitude = 1_000_000
+
+// power returns base ^ exp. Powers whose exact result fits in
+// maxExactPowerDigits are exact, as decimal.Pow computes them; larger ones
+// are rounded, and those too large or small to write out are an error.
+// Like decimal.Pow, negative exponents give PowPrecisionNegativeExponent
+// places and negative bases with fractional exponents give zero.
+func power(base, exp decimal.Decimal) (decimal.Decimal, error) {
+	whole := exp.Truncate(0)
+	digits := int64(max(base.NumDigits(), 1))
+	if whole.Abs().LessThanOrEqual(decimal.NewFromInt(maxExactPowerDigits / digits)) {
+		return base.Pow(exp), nil
+	}
+	frac := exp.Sub(whole)
+	if base.IsZero() || base.Sign() < 0 && !frac.IsZero() {
+		return base.Pow(exp), nil
+	}
+	if !whole.BigInt().IsInt64() {
+		return decimal.Decimal{}, fmt.Errorf("exponent %s is too large", exp)
+	}
+
+	n := whole.IntPart()
+	negative := n < 0
+	if negative {
+		n = -n
+	}
+	one := decimal.NewFromInt(1)
+	result, square := one, base
+	for n > 0 {
+		var err error
+		if n&1 == 1 {
+			if result, err = powerStep(result.Mul(square), base, exp); err != nil {
+				return decimal.Decimal{}, err
+			}
+		}
+		n >>= 1
+		if n > 0 {
+			if square, err = powerStep(square.Mul(square), base, exp); err != nil {
+				return decimal.Decimal{}, err
+			}
+		}
+	}
+
+	if negative {
+		result = one.DivRound(result, int32(decimal.PowPrecisionNegativeExponent))
+	}
+	if !frac.IsZero() {
+		result = result.Mul(base.Pow(frac))
+	}
+	return result, nil
+}
+
+// powerStep rounds a step of raising base to exp to maxExactPowerDigits
+// significant digits, or returns an error if it has grown past
+// maxPowerMagnitude.
+func powerStep(d, base, exp decimal.Decimal) (decimal.Decimal, error) {
+	magnitude := d.NumDigits() + int(d.Exponent()) // Digits before the point; negative for leading zeros after it
+	if magnitude > maxPowerMagnitude || magnitude < -maxPowerMagnitude {
+		return decimal.Decimal{}, fmt.Errorf("%s ^ %s has over %d digits", base, exp, maxPowerMagnitude)
+	}
+	if d.NumDigits() > maxExactPowerDigits {
+		d = d.Round(int32(maxExactPowerDigits - magnitude))
+	}
+	return d, nil
+}
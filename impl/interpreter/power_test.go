@@ -0,0 +1,78 @@
+package interpreter_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+// TestPower tests that small powers stay exact and large ones are rounded
+// quickly instead of growing without bound.
+func TestPower(t *testing.T) {
+	tests := []struct {
+		input  string
+		prefix string // Of the result
+		err    string
+	}{
+		{"2 ^ 10\n", "1024", ""},
+		{"1.5 ^ 3\n", "3.375", ""},
+		{"2 ^ -2\n", "0.25", ""},
+		{"1.0000001 ^ 9999999\n", "2.7182814207", ""},
+		{"0.999 ^ 1000000\n", "0.", ""},
+		{"10 ^ 9999999\n", "", "has over 1000000 digits"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			start := time.Now()
+			results, err := interpreter.NewInterpreter().Eval(nodes)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("took %s", elapsed)
+			}
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval error: %v", err)
+			}
+			if got := results[0].String(); !strings.HasPrefix(got, tt.prefix) {
+				t.Errorf("Result = %.40s, want prefix %s", got, tt.prefix)
+			}
+		})
+	}
+}
+
+// TestEvalContextCancelled tests that a cancelled evaluation stops, keeping
+// the assignments before it.
+func TestEvalContextCancelled(t *testing.T) {
+	nodes, err := parser.Parse("a = 1\nb = a + 1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	interp := interpreter.NewInterpreter()
+	if _, err := interp.EvalContext(ctx, nodes); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if interp.GetEnvironment().Has("a") {
+		t.Error("no statement should run once the context is done")
+	}
+
+	// The interpreter can be used again afterwards
+	if _, err := interp.Eval(nodes); err != nil {
+		t.Fatalf("Eval after cancel: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package interpreter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// Recurring schedules and the queries over them.
+
+var weekdays = map[string]time.Weekday{
+	"Sunday": time.Sunday, "Monday": time.Monday, "Tuesday": time.Tuesday,
+	"Wednesday": time.Wednesday, "Thursday": time.Thursday, "Friday": time.Friday,
+	"Saturday": time.Saturday,
+}
+
+func (interp *Interpreter) evalScheduleLiteral(s *ast.ScheduleLiteral) (types.Type, error) {
+	every, err := parseInt(s.Every)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule interval: %w", err)
+	}
+
+	var start *types.Date
+	if s.Start != nil {
+		val, err := interp.evalNode(s.Start)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if start, ok = val.(*types.Date); !ok {
+			return nil, fmt.Errorf("a schedule must start from a date, got %T", val)
+		}
+	}
+
+	unit := s.Unit
+	if s.Weekday != "" {
+		unit = "week"
+	}
+	schedule, err := types.NewSchedule(every, unit, start)
+	if err != nil {
+		return nil, err
+	}
+	if s.Weekday != "" {
+		schedule.Weekday, schedule.OnDay = weekdays[s.Weekday], true
+	}
+	if s.Day != "" {
+		if schedule.Unit != "month" {
+			return nil, fmt.Errorf("only monthly schedules fall on a day of the month, not every %s", schedule.Unit)
+		}
+		if schedule.Day, err = parseInt(s.Day); err != nil || schedule.Day < 1 || schedule.Day > 31 {
+			return nil, fmt.Errorf("invalid day of the month: %s", s.Day)
+		}
+	}
+	return schedule, nil
+}
+
+// evalScheduleQuery handles next(schedule, after?), count(schedule, from, to),
+// and occurrences(schedule, from, to), the forms of "next payday",
+// "count paydays between Mar 1 and Jun 30", and "paydays in Q3".
+func (interp *Interpreter) evalScheduleQuery(f *ast.FunctionCall) (types.Type, error) {
+	usage := map[string]string{
+		"next":        "next() requires 1 or 2 arguments (schedule, after?)",
+		"count":       "count() requires 3 arguments (schedule, from, to)",
+		"occurrences": "occurrences() requires 3 arguments (schedule, from, to)",
+	}[f.Name]
+	if len(f.Arguments) == 0 || len(f.Arguments) > 3 || (f.Name == "next") != (len(f.Arguments) < 3) {
+		return nil, fmt.Errorf("%s", usage)
+	}
+
+	schedule, err := interp.evalSchedule(f.Name, f.Arguments[0])
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]*types.Date, len(f.Arguments)-1)
+	for i, arg := range f.Arguments[1:] {
+		val, err := interp.evalNode(arg)
+		if err != nil {
+			return nil, err
+		}
+		date, ok := val.(*types.Date)
+		if !ok {
+			return nil, fmt.Errorf("%s() dates must be dates, got %T", f.Name, val)
+		}
+		dates[i] = date
+	}
+
+	if f.Name == "next" {
+		after := types.NewDateFromTime(time.Now())
+		if len(dates) == 1 {
+			after = dates[0]
+		}
+		return schedule.Next(after), nil
+	}
+
+	occurrences, err := schedule.Between(dates[0], dates[1])
+	if err != nil {
+		return nil, err
+	}
+	if f.Name == "count" {
+		return types.NewNumber(decimal.NewFromInt(int64(len(occurrences)))), nil
+	}
+	elements := make([]types.Type, len(occurrences))
+	for i, d := range occurrences {
+		elements[i] = d
+	}
+	return types.NewList(elements), nil
+}
+
+// evalSchedule evaluates the schedule a query asks about. A query may name
+// the schedule in the singular, so "next payday" finds paydays.
+func (interp *Interpreter) evalSchedule(query string, arg ast.Node) (*types.Schedule, error) {
+	var val types.Type
+	if id, ok := arg.(*ast.Identifier); ok {
+		for _, name := range types.ScheduleNames(id.Name) {
+			if val, ok = interp.env.Get(name); ok {
+				break
+			}
+		}
+	}
+	if val == nil {
+		var err error
+		if val, err = interp.evalNode(arg); err != nil {
+			return nil, err
+		}
+	}
+	schedule, ok := val.(*types.Schedule)
+	if !ok {
+		return nil, fmt.Errorf("%s() needs a schedule such as 'every 2nd Friday', got %T", query, val)
+	}
+	return schedule, nil
+}
@@ -33,7 +33,7 @@ var storageSeekTime = map[string]float64{
 //
 // Examples:
 //   - read(100 GB, ssd) → ~182 seconds
-//   - read(1 TB, nvme) → ~293 seconds
+//   - read(1 TB, nvme) → ~286 seconds
 //   - read(10 MB, hdd) → ~67 milliseconds
 func calculateRead(size *types.Quantity, storageType string) (*types.Duration, error) {
 	typeLower := strings.ToLower(strings.TrimSpace(storageType))
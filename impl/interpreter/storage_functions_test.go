@@ -28,7 +28,7 @@ func TestCalculateRead(t *testing.T) {
 			name:      "1 GB on NVMe",
 			sizeValue: 1, sizeUnit: "gigabyte",
 			storageType: "nvme",
-			expectedSec: 0.293, // 1024 MB / 3500 MB/s ≈ 0.293s
+			expectedSec: 0.286, // 1000 MB / 3500 MB/s ≈ 0.286s
 			tolerance:   0.001,
 		},
 		{
@@ -42,7 +42,7 @@ func TestCalculateRead(t *testing.T) {
 			name:      "500 GB on PCIe SSD",
 			sizeValue: 500, sizeUnit: "gigabyte",
 			storageType: "pcie_ssd",
-			expectedSec: 73.143, // 512000 MB / 7000 MB/s ≈ 73.14s
+			expectedSec: 71.429, // 500000 MB / 7000 MB/s ≈ 71.43s
 			tolerance:   1.0,
 		},
 		{
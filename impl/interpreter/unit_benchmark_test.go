@@ -3,6 +3,7 @@ package interpreter
 import (
 	"testing"
 
+	"github.com/CalcMark/go-calcmark/spec/parser"
 	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 )
@@ -57,3 +58,29 @@ func BenchmarkMultipleConversions(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkMixedUnitDocument evaluates a document dominated by mixed-unit
+// and mixed-currency arithmetic, where every operation converts.
+func BenchmarkMixedUnitDocument(b *testing.B) {
+	source := "route = 12 km + 3 miles + 800 meters + 2000 feet\n" +
+		"load = 40 kg + 25 pounds + 300 grams\n" +
+		"tank = 50 liters + 3 gallons\n" +
+		"backup = 2 TB + 500 GB + 1 TiB\n" +
+		"budget = 100 USD in EUR + 250 USD in EUR\n" +
+		"total = route + 1 mile - 500 meters\n" +
+		"cost = 80 USD in GBP + 40 USD in GBP\n"
+	nodes, err := parser.Parse(source)
+	if err != nil {
+		b.Fatalf("Parse failed: %v", err)
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		interp := NewInterpreter()
+		interp.env.SetExchangeRate("USD", "EUR", decimal.RequireFromString("0.92"))
+		interp.env.SetExchangeRate("USD", "GBP", decimal.RequireFromString("0.79"))
+		if _, err := interp.Eval(nodes); err != nil {
+			b.Fatalf("Eval failed: %v", err)
+		}
+	}
+}
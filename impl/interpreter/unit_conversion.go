@@ -2,7 +2,6 @@ package interpreter
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
@@ -10,7 +9,11 @@ import (
 
 // evalQuantityOperation handles quantity + quantity with unit conversion
 // USER REQUIREMENT: First-unit-wins rule
-func evalQuantityOperation(left, right *types.Quantity, operator string) (types.Type, error) {
+// Division rounds to precision decimal places.
+func evalQuantityOperation(left, right *types.Quantity, operator string, precision int32) (types.Type, error) {
+	if operator == "*" || operator == "/" {
+		return evalQuantityProduct(left, right, operator, precision)
+	}
 	if operator != "+" && operator != "-" {
 		return nil, fmt.Errorf("unsupported quantity operation: %s", operator)
 	}
@@ -34,38 +37,19 @@ func evalQuantityOperation(left, right *types.Quantity, operator string) (types.
 	return &types.Quantity{Value: result, Unit: left.Unit}, nil
 }
 
-// convertQuantity converts a quantity to the target unit using unit_library registry
+// convertQuantity converts a quantity to the target unit using the
+// conversion tables built at init (see lookupConversion).
 func convertQuantity(qty *types.Quantity, targetUnit string) (*types.Quantity, error) {
 	if qty.Unit == targetUnit {
 		return qty, nil // No conversion needed
 	}
 
-	// Normalize unit names for lookup
-	sourceNorm := strings.ToLower(qty.Unit)
-	targetNorm := strings.ToLower(targetUnit)
-
-	// Look up both units in the registry
-	sourceInfo, sourceOk := GetUnitInfo(sourceNorm)
-	targetInfo, targetOk := GetUnitInfo(targetNorm)
-
-	if !sourceOk || !targetOk {
-		// One or both are arbitrary units - cannot convert
-		return nil, fmt.Errorf("cannot convert %s to %s (incompatible units)", qty.Unit, targetUnit)
-	}
-
-	// Check if units are in the same category
-	if sourceInfo.Category != targetInfo.Category {
-		return nil, fmt.Errorf("cannot convert %s to %s (different unit types: %s vs %s)",
-			qty.Unit, targetUnit, sourceInfo.Category, targetInfo.Category)
+	value, err := lookupConversion(qty.Unit, targetUnit).apply(qty.Value, qty.Unit, targetUnit)
+	if err != nil {
+		return nil, err
 	}
-
-	// Perform conversion: source -> base unit -> target
-	value, _ := qty.Value.Float64()
-	baseValue := sourceInfo.ToBaseUnit(value)         // Convert to base unit (e.g., meters)
-	targetValue := targetInfo.FromBaseUnit(baseValue) // Convert from base to target
-
 	return &types.Quantity{
-		Value: decimal.NewFromFloat(targetValue),
+		Value: value,
 		Unit:  targetUnit, // Preserve user's target unit name
 	}, nil
 }
@@ -10,6 +10,7 @@ import (
 // evalUnitConversion evaluates explicit unit conversion: "10 meters in feet"
 // Also handles rate-to-rate conversion: "10 m/s in inch/s"
 // Also handles currency conversion: "100 USD in EUR" (requires exchange rate in frontmatter)
+// Also values currency baskets: "0.6 * USD + 0.4 * EUR in GBP"
 func (interp *Interpreter) evalUnitConversion(u *ast.UnitConversion) (types.Type, error) {
 	// Evaluate the quantity expression
 	result, err := interp.evalNode(u.Quantity)
@@ -21,6 +22,9 @@ func (interp *Interpreter) evalUnitConversion(u *ast.UnitConversion) (types.Type
 	if currency, ok := result.(*types.Currency); ok {
 		return interp.evalCurrencyConversion(currency, u.TargetUnit)
 	}
+	if basket, ok := result.(*types.Basket); ok {
+		return interp.evalBasketConversion(basket, u.TargetUnit)
+	}
 
 	// Check if this is a rate-to-rate conversion
 	if u.TargetTimeUnit != "" {
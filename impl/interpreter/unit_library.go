@@ -35,6 +35,7 @@ var unitRegistry map[string]UnitInfo
 
 func init() {
 	unitRegistry = buildUnitRegistry()
+	unitFactors = buildUnitFactors(unitRegistry)
 }
 
 // buildUnitRegistry creates the static registry
@@ -585,11 +586,8 @@ func addAreaUnits(registry map[string]UnitInfo) {
 //
 // Supports three conventions:
 // 1. Binary (IEC): KiB, MiB, GiB - 1024-based, traditional computing
-// 2. Decimal (SI): KB, MB, GB - 1000-based, storage marketing & some contexts
+// 2. Decimal (SI): KB, MB, GB - 1000-based, storage and transfers
 // 3. Bits: Kbit, Mbit, Gbit, Mbps, Gbps - 1000-based, networking
-//
-// Note: KB/MB/GB use binary (1024) by default as this matches user expectations
-// in most computing contexts. Use KiB/MiB/GiB or explicit decimal units if needed.
 func addDataSizeUnits(registry map[string]UnitInfo) {
 	makeDataSizeUnit := func(toBits, fromBits func(float64) float64) UnitInfo {
 		return UnitInfo{
@@ -659,45 +657,64 @@ func addDataSizeUnits(registry map[string]UnitInfo) {
 	registry["pebibyte"] = registry["pib"]
 	registry["pebibytes"] = registry["pib"]
 
-	// ========== DECIMAL PREFIXES (SI) - 1000-based ==========
-	// Used in storage marketing and networking contexts
-	// Note: KB/MB/GB are aliased to binary (KiB/MiB/GiB) for user convenience
-
-	// Kilobyte - use binary (1024) as default for KB (matches most user expectations)
-	registry["kb"] = registry["kib"]
-	registry["kilobyte"] = registry["kib"]
-	registry["kilobytes"] = registry["kib"]
-
-	// Megabyte - use binary (1024²) as default for MB
-	registry["mb"] = registry["mib"]
-	registry["megabyte"] = registry["mib"]
-	registry["megabytes"] = registry["mib"]
-
-	// Gigabyte - use binary (1024³) as default for GB
-	registry["gb"] = registry["gib"]
-	registry["gigabyte"] = registry["gib"]
-	registry["gigabytes"] = registry["gib"]
-
-	// Terabyte - use binary (1024⁴) as default for TB
-	registry["tb"] = registry["tib"]
-	registry["terabyte"] = registry["tib"]
-	registry["terabytes"] = registry["tib"]
-
-	// Petabyte - use binary (1024⁵) as default for PB
-	registry["pb"] = registry["pib"]
-	registry["petabyte"] = registry["pib"]
-	registry["petabytes"] = registry["pib"]
-
-	// Exabyte - use binary (1024⁶) as default for EB
-	registry["eb"] = makeDataSizeUnit(
+	// Exbibyte (EiB = 1024 PiB)
+	registry["eib"] = makeDataSizeUnit(
 		func(v float64) float64 { return float64(units.Datasize(v) * units.Exbibyte) },
 		func(v float64) float64 { return units.Datasize(v).Exbibytes() },
 	)
+	registry["exbibyte"] = registry["eib"]
+	registry["exbibytes"] = registry["eib"]
+
+	// ========== DECIMAL PREFIXES (SI) - 1000-based ==========
+	// Used for storage and network transfers
+
+	// Kilobyte (KB = 1000 bytes)
+	registry["kb"] = makeDataSizeUnit(
+		func(v float64) float64 { return float64(units.Datasize(v) * units.Kilobyte) },
+		func(v float64) float64 { return units.Datasize(v).Kilobytes() },
+	)
+	registry["kilobyte"] = registry["kb"]
+	registry["kilobytes"] = registry["kb"]
+
+	// Megabyte (MB = 1000 KB)
+	registry["mb"] = makeDataSizeUnit(
+		func(v float64) float64 { return float64(units.Datasize(v) * units.Megabyte) },
+		func(v float64) float64 { return units.Datasize(v).Megabytes() },
+	)
+	registry["megabyte"] = registry["mb"]
+	registry["megabytes"] = registry["mb"]
+
+	// Gigabyte (GB = 1000 MB)
+	registry["gb"] = makeDataSizeUnit(
+		func(v float64) float64 { return float64(units.Datasize(v) * units.Gigabyte) },
+		func(v float64) float64 { return units.Datasize(v).Gigabytes() },
+	)
+	registry["gigabyte"] = registry["gb"]
+	registry["gigabytes"] = registry["gb"]
+
+	// Terabyte (TB = 1000 GB)
+	registry["tb"] = makeDataSizeUnit(
+		func(v float64) float64 { return float64(units.Datasize(v) * units.Terabyte) },
+		func(v float64) float64 { return units.Datasize(v).Terabytes() },
+	)
+	registry["terabyte"] = registry["tb"]
+	registry["terabytes"] = registry["tb"]
+
+	// Petabyte (PB = 1000 TB)
+	registry["pb"] = makeDataSizeUnit(
+		func(v float64) float64 { return float64(units.Datasize(v) * units.Petabyte) },
+		func(v float64) float64 { return units.Datasize(v).Petabytes() },
+	)
+	registry["petabyte"] = registry["pb"]
+	registry["petabytes"] = registry["pb"]
+
+	// Exabyte (EB = 1000 PB)
+	registry["eb"] = makeDataSizeUnit(
+		func(v float64) float64 { return float64(units.Datasize(v) * units.Exabyte) },
+		func(v float64) float64 { return units.Datasize(v).Exabytes() },
+	)
 	registry["exabyte"] = registry["eb"]
 	registry["exabytes"] = registry["eb"]
-	registry["eib"] = registry["eb"]
-	registry["exbibyte"] = registry["eb"]
-	registry["exbibytes"] = registry["eb"]
 
 	// ========== BIT UNITS (NETWORK) - 1000-based ==========
 	// Standard for network throughput (Mbps, Gbps, etc.)
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/types"
 )
 
@@ -71,7 +72,11 @@ func parseExchangeKey(key string) (from, to string, err error) {
 
 func (interp *Interpreter) evalIdentifier(id *ast.Identifier) (types.Type, error) {
 	// Check for defined variables FIRST (variables take precedence over keywords)
-	if value, ok := interp.env.Get(id.Name); ok {
+	sym := id.Symbol
+	if sym.IsZero() {
+		sym = lexer.SymbolFor(id.Name)
+	}
+	if value, ok := interp.env.GetSymbol(sym); ok {
 		return value, nil
 	}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	spectypes "github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 )
 
@@ -254,3 +255,23 @@ func (b *Boolean) Equal(other Type) bool {
 func (b *Boolean) ToBool() bool {
 	return b.Value
 }
+
+// ToValue describes n as spec/types describes numbers, so legacy values
+// still serialize like the rest.
+func (n *Number) ToValue() spectypes.Value {
+	v := spectypes.NewNumber(n.Value).ToValue()
+	v.Display = n.String()
+	return v
+}
+
+// ToValue describes c as spec/types describes currencies.
+func (c *Currency) ToValue() spectypes.Value {
+	v := spectypes.NewCurrency(c.Value, c.Symbol).ToValue()
+	v.Display = c.String()
+	return v
+}
+
+// ToValue describes b as spec/types describes booleans.
+func (b *Boolean) ToValue() spectypes.Value {
+	return spectypes.NewBoolean(b.Value).ToValue()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+// Asynchronous evaluations run a document a slice at a time, yielding to
+// the browser between slices so long documents don't freeze the page. Each
+// has a token that cancel() uses to stop it, so an editor can abandon an
+// evaluation the next keystroke makes stale.
+
+// evaluationSlice is how long an asynchronous evaluation runs before it
+// yields. Lines are never split, so one slow line can run longer. A var so
+// tests can yield after every line.
+var evaluationSlice = 10 * time.Millisecond
+
+// errEvaluationCancelled is the error of an evaluation stopped by cancel().
+var errEvaluationCancelled = errors.New("evaluation cancelled")
+
+// evaluation is a running asynchronous evaluation.
+type evaluation struct {
+	token     int
+	cancelled bool
+}
+
+var (
+	evaluations         = map[int]*evaluation{}
+	nextEvaluationToken = 1
+)
+
+// startEvaluation registers a new evaluation. Tokens aren't reused, so
+// cancelling a finished evaluation can't stop a newer one.
+func startEvaluation() *evaluation {
+	e := &evaluation{token: nextEvaluationToken}
+	nextEvaluationToken++
+	evaluations[e.token] = e
+	return e
+}
+
+// cancelEvaluation stops the evaluation with the given token at its next
+// line, reporting whether it was still running.
+func cancelEvaluation(token int) bool {
+	e, ok := evaluations[token]
+	if ok {
+		e.cancelled = true
+		delete(evaluations, token)
+	}
+	return ok
+}
+
+// run evaluates source's calculation lines in ctx as evaluateDocument
+// does, calling yield after each slice of evaluationSlice. It returns
+// errEvaluationCancelled if cancelled, leaving ctx with the assignments of
+// the lines evaluated so far.
+func (e *evaluation) run(source string, ctx *interpreter.Environment, yield func()) ([]EvaluationResultWithLine, error) {
+	defer delete(evaluations, e.token)
+
+	results := make([]EvaluationResultWithLine, 0)
+	sliceStart := time.Now()
+	for i, line := range splitLines(source) {
+		if e.cancelled {
+			return nil, errEvaluationCancelled
+		}
+		results = append(results, evaluateDocumentLine(line, i+1, ctx)...)
+		if time.Since(sliceStart) >= evaluationSlice {
+			yield()
+			sliceStart = time.Now()
+		}
+	}
+	if e.cancelled {
+		return nil, errEvaluationCancelled
+	}
+	return results, nil
+}
@@ -0,0 +1,65 @@
+//go:build !wasm
+// +build !wasm
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+func yieldEveryLine(t *testing.T) {
+	t.Helper()
+	saved := evaluationSlice
+	evaluationSlice = 0
+	t.Cleanup(func() { evaluationSlice = saved })
+}
+
+func TestEvaluationYields(t *testing.T) {
+	yieldEveryLine(t)
+	source := "# Costs\nx = 5\n\nSome text\ny = x * 2\n"
+
+	e := startEvaluation()
+	yields := 0
+	results, err := e.run(source, interpreter.NewEnvironment(), func() { yields++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if yields != len(splitLines(source)) {
+		t.Errorf("yields = %d, want one per line", yields)
+	}
+	if len(results) != 2 || results[0].OriginalLine != 2 || results[1].OriginalLine != 5 || results[1].Metadata.Raw != "10" {
+		t.Errorf("results = %+v", results)
+	}
+	if cancelEvaluation(e.token) {
+		t.Error("a finished evaluation can't be cancelled")
+	}
+}
+
+func TestCancelEvaluation(t *testing.T) {
+	yieldEveryLine(t)
+	env := interpreter.NewEnvironment()
+
+	e := startEvaluation()
+	if other := startEvaluation(); other.token == e.token {
+		t.Fatal("tokens should be unique")
+	} else {
+		defer cancelEvaluation(other.token)
+	}
+	results, err := e.run("a = 1\nb = 2\nc = 3\n", env, func() {
+		if !cancelEvaluation(e.token) {
+			t.Error("a running evaluation should be cancellable")
+		}
+	})
+	if !errors.Is(err, errEvaluationCancelled) || results != nil {
+		t.Fatalf("results %v, err %v; want cancelled", results, err)
+	}
+	if _, ok := env.Get("a"); !ok {
+		t.Error("lines before the cancel should have run")
+	}
+	if _, ok := env.Get("b"); ok {
+		t.Error("lines after the cancel should not run")
+	}
+}
@@ -0,0 +1,179 @@
+// <calc-mark> renders a CalcMark document with results, client-side.
+//
+//   <script src="wasm_exec.js"></script>
+//   <script src="calc-mark.js"></script>
+//   <calc-mark src="budget.cm" data-overrides='{"growth": 0.05}'></calc-mark>
+//
+// calcmark.wasm is loaded once per page from next to this script; set
+// data-wasm on the script tag to load it from elsewhere. Without src, the
+// element's own text content is the document.
+//
+// data-overrides is a JSON object replacing frontmatter globals, so sliders
+// and inputs on the page can drive what-if scenarios:
+//
+//   slider.oninput = () => { embed.overrides = { growth: slider.value } };
+//
+// After each render the element fires "calcmark-render" with
+// event.detail.params: the document's globals and their current values.
+(() => {
+  "use strict";
+
+  const script = document.currentScript;
+  const wasmURL = new URL(
+    (script && script.dataset.wasm) || "calcmark.wasm",
+    (script && script.src) || document.baseURI,
+  );
+
+  let ready = null;
+
+  // loadCalcMark instantiates the WASM module once and resolves to window.calcmark.
+  function loadCalcMark() {
+    if (ready) {
+      return ready;
+    }
+    if (typeof Go === "undefined") {
+      return Promise.reject(new Error("wasm_exec.js must be loaded before calc-mark.js"));
+    }
+    const go = new Go();
+    ready = WebAssembly.instantiateStreaming(fetch(wasmURL), go.importObject).then((result) => {
+      go.run(result.instance); // Registers window.calcmark, then blocks forever
+      return window.calcmark;
+    });
+    return ready;
+  }
+
+  const styles = `
+    :host { display: block; font: inherit; }
+    .calc { margin: 0.75em 0; padding: 0.5em 0.75em; background: #f6f8fa; border-radius: 6px; }
+    .line { display: flex; justify-content: space-between; gap: 2em; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+    .result { color: #0969da; font-weight: 600; white-space: nowrap; }
+    .error { color: #cf222e; font-size: 0.9em; }
+    .result.red { color: #cf222e; } .result.orange { color: #bc4c00; } .result.yellow { color: #9a6700; }
+    .result.green { color: #1a7f37; } .result.purple { color: #8250df; } .result.gray { color: #6e7781; }
+  `;
+
+  function escapeHTML(text) {
+    return text.replace(/[&<>"']/g, (c) => ({ "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;" })[c]);
+  }
+
+  // renderMarkdown uses marked.js when the page provides it; otherwise it
+  // handles headings and paragraphs, which covers most CalcMark prose.
+  function renderMarkdown(markdown) {
+    if (window.marked && typeof window.marked.parse === "function") {
+      return window.marked.parse(markdown);
+    }
+    return markdown
+      .split(/\n{2,}/)
+      .filter((para) => para.trim() !== "")
+      .map((para) => {
+        const heading = /^(#{1,6})\s+(.*)$/.exec(para.trim());
+        if (heading) {
+          const level = heading[1].length;
+          return `<h${level}>${escapeHTML(heading[2])}</h${level}>`;
+        }
+        return `<p>${escapeHTML(para).replace(/\n/g, "<br>")}</p>`;
+      })
+      .join("");
+  }
+
+  function renderBlock(block) {
+    if (block.type === "text") {
+      return renderMarkdown(block.markdown || "");
+    }
+    const lines = (block.lines || []).map(
+      (line) =>
+        `<div class="line"><span class="source">${escapeHTML(line.source)}</span>` +
+        `<span class="result ${escapeHTML(line.highlight || "")}">${escapeHTML(line.result || "")}</span></div>`,
+    );
+    if (block.error) {
+      lines.push(`<div class="error">${escapeHTML(block.error)}</div>`);
+    }
+    return `<div class="calc">${lines.join("")}</div>`;
+  }
+
+  class CalcMarkElement extends HTMLElement {
+    static get observedAttributes() {
+      return ["src", "data-overrides"];
+    }
+
+    constructor() {
+      super();
+      this.attachShadow({ mode: "open" });
+      this._source = null;
+      this._params = [];
+    }
+
+    // params lists the document's frontmatter globals from the last render.
+    get params() {
+      return this._params;
+    }
+
+    // overrides sets data-overrides from an object, e.g. { growth: 0.05 }.
+    get overrides() {
+      return JSON.parse(this.dataset.overrides || "{}");
+    }
+
+    set overrides(values) {
+      this.dataset.overrides = JSON.stringify(values || {});
+    }
+
+    connectedCallback() {
+      this._render();
+    }
+
+    attributeChangedCallback(name, oldValue, newValue) {
+      if (oldValue === newValue || !this.isConnected) {
+        return;
+      }
+      if (name === "src") {
+        this._source = null;
+      }
+      this._render();
+    }
+
+    async _load() {
+      if (this._source !== null) {
+        return this._source;
+      }
+      const src = this.getAttribute("src");
+      if (!src) {
+        this._source = this.textContent;
+        return this._source;
+      }
+      const response = await fetch(src);
+      if (!response.ok) {
+        throw new Error(`failed to load ${src}: ${response.status} ${response.statusText}`);
+      }
+      this._source = await response.text();
+      return this._source;
+    }
+
+    async _render() {
+      const generation = (this._generation = (this._generation || 0) + 1);
+      let html;
+      try {
+        const [calcmark, source] = await Promise.all([loadCalcMark(), this._load()]);
+        const response = calcmark.renderDocument(source, this.dataset.overrides || "");
+        if (response.error) {
+          throw new Error(response.error);
+        }
+        const doc = JSON.parse(response.document);
+        this._params = doc.params;
+        html = doc.blocks.map(renderBlock).join("");
+      } catch (err) {
+        html = `<div class="error">${escapeHTML(err.message)}</div>`;
+      }
+
+      // A newer render (e.g. a slider still moving) supersedes this one
+      if (generation !== this._generation) {
+        return;
+      }
+      this.shadowRoot.innerHTML = `<style>${styles}</style>${html}`;
+      this.dispatchEvent(new CustomEvent("calcmark-render", { detail: { params: this._params } }));
+    }
+  }
+
+  if (!customElements.get("calc-mark")) {
+    customElements.define("calc-mark", CalcMarkElement);
+  }
+})();
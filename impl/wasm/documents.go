@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// openDocuments holds the documents created by createDocument, by handle.
+// Web editors keep a document open and change it a block at a time, so
+// only the blocks an edit affects are evaluated again, as in the TUI.
+var (
+	openDocuments      = map[int]*openDocument{}
+	nextDocumentHandle = 1
+)
+
+// openDocument is a document with the evaluator holding its variables.
+type openDocument struct {
+	handle int
+	doc    *document.Document
+	eval   *implDoc.Evaluator
+}
+
+// DocumentBlock is a block of an open document with its results. Source
+// holds every line, blank ones included, for editing.
+type DocumentBlock struct {
+	ID     string   `json:"id"`
+	Source []string `json:"source"`
+	RenderedBlock
+}
+
+// DocumentUpdate describes an open document after it is created or edited.
+// Blocks lists the blocks whose results may have changed, in document
+// order: every block for a new document.
+type DocumentUpdate struct {
+	Handle          int             `json:"handle"`
+	ModifiedBlockID string          `json:"modifiedBlockId,omitempty"`
+	Blocks          []DocumentBlock `json:"blocks"`
+}
+
+// BlockDependencies describes how a block's variables connect it to the
+// rest of its document.
+type BlockDependencies struct {
+	BlockID    string   `json:"blockId"`
+	Defines    []string `json:"defines"`    // Variables the block assigns
+	Uses       []string `json:"uses"`       // Variables it reads from other blocks
+	DependsOn  []string `json:"dependsOn"`  // Blocks defining the variables it uses
+	Dependents []string `json:"dependents"` // Blocks using its variables, transitively
+}
+
+// createOpenDocument parses and evaluates source and keeps the document
+// open under a new handle.
+func createOpenDocument(source string) (*DocumentUpdate, error) {
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		return nil, err
+	}
+	od := &openDocument{handle: nextDocumentHandle, doc: doc, eval: implDoc.NewEvaluator()}
+	nextDocumentHandle++
+	openDocuments[od.handle] = od
+	// As in renderSource, a failing block keeps its error for display
+	_ = od.eval.Evaluate(doc)
+
+	update := &DocumentUpdate{Handle: od.handle, Blocks: []DocumentBlock{}}
+	for _, node := range doc.GetBlocks() {
+		update.Blocks = append(update.Blocks, od.block(node))
+	}
+	return update, nil
+}
+
+// lookupDocument returns the open document with handle.
+func lookupDocument(handle int) (*openDocument, error) {
+	od, ok := openDocuments[handle]
+	if !ok {
+		return nil, fmt.Errorf("no open document with handle %d", handle)
+	}
+	return od, nil
+}
+
+// closeOpenDocument forgets the document with handle, freeing its memory.
+func closeOpenDocument(handle int) {
+	delete(openDocuments, handle)
+}
+
+// replaceSource replaces a block's source, lines separated by "\n", and
+// evaluates the blocks the change affects.
+func (od *openDocument) replaceSource(blockID, source string) (*DocumentUpdate, error) {
+	result, err := od.doc.ApplyEdits([]document.Edit{
+		{Kind: document.EditReplace, BlockID: blockID, Source: strings.Split(source, "\n")},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Errors are kept on the blocks
+	_ = od.eval.EvaluateAffectedBlocks(od.doc, result.AffectedBlockIDs)
+
+	update := &DocumentUpdate{Handle: od.handle, ModifiedBlockID: result.ModifiedBlockID, Blocks: []DocumentBlock{}}
+	for _, id := range result.AffectedBlockIDs {
+		if node, ok := od.doc.GetBlock(id); ok {
+			update.Blocks = append(update.Blocks, od.block(node))
+		}
+	}
+	return update, nil
+}
+
+// evaluateBlock evaluates one block again with the document's current
+// variables.
+func (od *openDocument) evaluateBlock(blockID string) (*DocumentBlock, error) {
+	node, ok := od.doc.GetBlock(blockID)
+	if !ok {
+		return nil, fmt.Errorf("block not found: %s", blockID)
+	}
+	_ = od.eval.EvaluateAffectedBlocks(od.doc, []string{blockID})
+	block := od.block(node)
+	return &block, nil
+}
+
+// blocks returns every block of the document in order.
+func (od *openDocument) blocks() []DocumentBlock {
+	blocks := []DocumentBlock{}
+	for _, node := range od.doc.GetBlocks() {
+		blocks = append(blocks, od.block(node))
+	}
+	return blocks
+}
+
+// dependencies returns the blocks a block depends on and those that depend
+// on it. Under top-down evaluation, a variable comes from the last block
+// before it that defines it.
+func (od *openDocument) dependencies(blockID string) (*BlockDependencies, error) {
+	node, ok := od.doc.GetBlock(blockID)
+	if !ok {
+		return nil, fmt.Errorf("block not found: %s", blockID)
+	}
+	deps := &BlockDependencies{BlockID: blockID, Defines: []string{}, Uses: []string{}, DependsOn: []string{}, Dependents: []string{}}
+	cb, ok := node.Block.(*document.CalcBlock)
+	if !ok {
+		return deps, nil
+	}
+	deps.Defines = append(deps.Defines, cb.Variables()...)
+	deps.Uses = append(deps.Uses, cb.Dependencies()...)
+
+	definedBy := map[string]string{}
+	for _, other := range od.doc.GetBlocks() {
+		if other.ID == blockID {
+			break
+		}
+		if ob, ok := other.Block.(*document.CalcBlock); ok {
+			for _, name := range ob.Variables() {
+				definedBy[name] = other.ID
+			}
+		}
+	}
+	for _, name := range cb.Dependencies() {
+		if id, ok := definedBy[name]; ok && !slices.Contains(deps.DependsOn, id) {
+			deps.DependsOn = append(deps.DependsOn, id)
+		}
+	}
+	dependents := od.doc.GetTransitiveDependents(cb.Variables())
+	deps.Dependents = append(deps.Dependents, od.doc.GetBlocksInDependencyOrder(slices.DeleteFunc(dependents, func(id string) bool { return id == blockID }))...)
+	return deps, nil
+}
+
+// block formats a block of the document for JavaScript.
+func (od *openDocument) block(node *document.BlockNode) DocumentBlock {
+	return DocumentBlock{
+		ID:            node.ID,
+		Source:        node.Block.Source(),
+		RenderedBlock: renderBlock(od.doc, node),
+	}
+}
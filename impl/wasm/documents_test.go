@@ -0,0 +1,83 @@
+//go:build !wasm
+// +build !wasm
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenDocumentIncrementalUpdate(t *testing.T) {
+	created, err := createOpenDocument("price = $20\n\n\n# Notes\n\nqty = 3\n\n\ntotal = price * qty\n\n\nother = 1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeOpenDocument(created.Handle)
+	if len(created.Blocks) != 5 {
+		t.Fatalf("got %d blocks, want 5", len(created.Blocks))
+	}
+	priceID, totalID := created.Blocks[0].ID, created.Blocks[3].ID
+	if got := created.Blocks[3].Lines[0].Result; got != "$60.00" {
+		t.Errorf("total = %q, want $60.00", got)
+	}
+
+	od, err := lookupDocument(created.Handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update, err := od.replaceSource(priceID, "price = $25\n\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, b := range update.Blocks {
+		ids = append(ids, b.ID)
+	}
+	if update.ModifiedBlockID != priceID || len(ids) != 2 || ids[0] != priceID || ids[1] != totalID {
+		t.Fatalf("update touched %v (modified %s), want only price and total", ids, update.ModifiedBlockID)
+	}
+	if got := update.Blocks[1].Lines[0].Result; got != "$75.00" {
+		t.Errorf("total after edit = %q, want $75.00", got)
+	}
+
+	deps, err := od.dependencies(totalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(deps.Defines, ",") != "total" || len(deps.DependsOn) != 2 || deps.DependsOn[0] != priceID {
+		t.Errorf("dependencies of total = %+v", deps)
+	}
+	if deps, _ := od.dependencies(priceID); len(deps.Dependents) != 1 || deps.Dependents[0] != totalID {
+		t.Errorf("dependents of price = %v, want total's block", deps.Dependents)
+	}
+
+	block, err := od.evaluateBlock(totalID)
+	if err != nil || block.Lines[0].Result != "$75.00" {
+		t.Errorf("evaluateBlock = %+v, %v", block, err)
+	}
+	if got := len(od.blocks()); got != 5 {
+		t.Errorf("blocks() returned %d blocks, want 5", got)
+	}
+}
+
+func TestOpenDocumentHandles(t *testing.T) {
+	created, err := createOpenDocument("x = 1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeOpenDocument(created.Handle)
+	if _, err := lookupDocument(created.Handle); err == nil {
+		t.Error("a closed document should not be found")
+	}
+
+	again, _ := createOpenDocument("x = 1\n")
+	defer closeOpenDocument(again.Handle)
+	if again.Handle == created.Handle {
+		t.Error("handles should not be reused")
+	}
+	od, _ := lookupDocument(again.Handle)
+	if _, err := od.replaceSource("missing", "y = 2"); err == nil {
+		t.Error("replacing an unknown block should fail")
+	}
+}
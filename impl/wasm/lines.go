@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/classifier"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// EvaluationResultWithLine extends evaluation result with line number tracking.
+// Used by evaluateDocument to map results back to their original line numbers.
+type EvaluationResultWithLine struct {
+	Value        types.Value    `json:"Value"`        // The computed value, structured
+	Symbol       string         `json:"Symbol"`       // Currency symbol if applicable
+	OriginalLine int            `json:"OriginalLine"` // 1-indexed line number in source document
+	Metadata     ResultMetadata `json:"Metadata"`     // Type, raw decimal, unit, and currency
+}
+
+// evaluateDocumentLine evaluates one line of a mixed document in ctx, as
+// evaluateDocument and evaluateAsync do for every line. Markdown lines and
+// lines that fail to parse or evaluate have no results.
+func evaluateDocumentLine(line string, lineNum int, ctx *interpreter.Environment) []EvaluationResultWithLine {
+	// Only evaluate calculation lines
+	lineType, _ := classifier.ClassifyLine(line, ctx)
+	if lineType != classifier.Calculation {
+		return nil
+	}
+
+	// Parse and evaluate the line
+	nodes, err := parser.Parse(line)
+	if err != nil {
+		return nil // Skip lines that fail to parse
+	}
+
+	interp := interpreter.NewInterpreterWithEnv(ctx)
+	evalResults, err := interp.Eval(nodes)
+	if err != nil {
+		return nil // Skip lines that fail to evaluate
+	}
+
+	// Add results with line numbers (1-indexed)
+	results := make([]EvaluationResultWithLine, 0, len(evalResults))
+	for _, evalResult := range evalResults {
+		value := evalResult.ToValue()
+		results = append(results, EvaluationResultWithLine{
+			Value:        value,
+			Symbol:       value.CurrencySymbol,
+			OriginalLine: lineNum,
+			Metadata:     resultMetadata(evalResult),
+		})
+	}
+	return results
+}
+
+// splitLines splits a string into lines, handling different line ending styles
+func splitLines(s string) []string {
+	var lines []string
+	var current []rune
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\r' {
+			lines = append(lines, string(current))
+			current = nil
+			// Handle \r\n
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				i++
+			}
+		} else if runes[i] == '\n' {
+			lines = append(lines, string(current))
+			current = nil
+		} else {
+			current = append(current, runes[i])
+		}
+	}
+	// Don't forget the last line if there's no trailing newline
+	if len(current) > 0 || len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
@@ -1,14 +1,16 @@
 // Package main provides WASM bindings for CalcMark library
 //
 // Architecture Notes:
-// - All functions follow the Go WASM signature: func(js.Value, []js.Value) interface{}
-// - Return values use map[string]interface{} to create JS objects with consistent error handling
-// - JSON serialization is used to pass complex data structures to JavaScript
-// - A global context persists across evaluation calls to maintain variable state
+//   - All functions follow the Go WASM signature: func(js.Value, []js.Value) interface{}
+//   - Return values use map[string]interface{} to create JS objects with consistent error handling
+//   - JSON serialization is used to pass complex data structures to JavaScript;
+//     calcmark.v2 returns the same data as plain JS objects (values.go)
+//   - A global context persists across evaluation calls to maintain variable state;
+//     sessions (sessions.go) give each tab or notebook its own
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"syscall/js"
 
 	calcmark "github.com/CalcMark/go-calcmark"
@@ -25,7 +27,8 @@ import (
 
 // globalContext maintains variable bindings across evaluation calls.
 // This allows applications to evaluate calculations line-by-line while preserving
-// previous variable assignments. Use resetContext() to clear this state.
+// previous variable assignments. Use resetContext() to clear this state, and
+// createSession() for contexts that aren't shared.
 var globalContext = interpreter.NewEnvironment()
 
 // ==============================================================================
@@ -66,18 +69,39 @@ func errorResponse(errorMsg string, fields ...string) map[string]interface{} {
 }
 
 // successResponse creates a standardized success response with JSON-serialized data.
-// The 'field' parameter becomes the key in the returned JS object.
+// The 'field' parameter becomes the key in the returned JS object. The data
+// reaches JavaScript as a JSON string, or as an object through calcmark.v2;
+// see decodeResponse.
 func successResponse(field string, data interface{}) map[string]interface{} {
-	jsonBytes, err := json.Marshal(data)
+	encoded, err := encodeJSON(data)
 	if err != nil {
 		return errorResponse(err.Error(), field)
 	}
 	return map[string]interface{}{
-		field:   string(jsonBytes),
+		field:   encoded,
 		"error": nil,
 	}
 }
 
+// contextArg returns the environment selected by args[i], an optional
+// session ID or useGlobalContext boolean: a session's environment for an
+// ID, globalContext for true, and a fresh environment for false. Without
+// the argument, useGlobal decides.
+func contextArg(args []js.Value, i int, useGlobal bool) (*interpreter.Environment, error) {
+	if len(args) > i {
+		switch args[i].Type() {
+		case js.TypeNumber:
+			return sessionEnv(args[i].Int())
+		case js.TypeBoolean:
+			useGlobal = args[i].Bool()
+		}
+	}
+	if useGlobal {
+		return globalContext, nil
+	}
+	return interpreter.NewEnvironment(), nil
+}
+
 // ==============================================================================
 // WASM Function: tokenize
 // ==============================================================================
@@ -154,41 +178,49 @@ func parse(this js.Value, args []js.Value) interface{} {
 // variable definitions across calls (e.g., line 1: "x = 5", line 2: "y = x + 1"),
 // we maintain a global context. Fresh contexts are used for isolated evaluation.
 //
-// Usage: calcmark.evaluate(sourceCode: string, useGlobalContext?: boolean)
-// Returns: {results: string (JSON array), error: string|null}
+// Usage: calcmark.evaluate(sourceCode: string, useGlobalContext?: boolean | sessionId: number)
+// Returns: {results: string (JSON array), metadata: string (JSON array of ResultMetadata), values: string (JSON array of types.Value), error: string|null}
 func evaluate(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
-		return errorResponse("Expected at least 1 argument: sourceCode (string)", "results")
+		return errorResponse("Expected at least 1 argument: sourceCode (string)", "results", "metadata", "values")
 	}
 
 	source := args[0].String()
 
-	// Default to using global context for stateful evaluation
-	useGlobalCtx := true
-	if len(args) > 1 {
-		useGlobalCtx = args[1].Bool()
-	}
-
-	// Choose context: global for persistent state, fresh for isolation
-	ctx := interpreter.NewEnvironment()
-	if useGlobalCtx {
-		ctx = globalContext
+	// Choose context: a session's or the global for persistent state, fresh
+	// for isolation. Default to the global context for stateful evaluation.
+	ctx, err := contextArg(args, 1, true)
+	if err != nil {
+		return errorResponse(err.Error(), "results", "metadata", "values")
 	}
 
 	// Parse the source
 	nodes, err := parser.Parse(source)
 	if err != nil {
-		return errorResponse(err.Error(), "results")
+		return errorResponse(err.Error(), "results", "metadata", "values")
 	}
 
 	// Evaluate with the interpreter
 	interp := interpreter.NewInterpreterWithEnv(ctx)
 	results, err := interp.Eval(nodes)
 	if err != nil {
-		return errorResponse(err.Error(), "results")
+		return errorResponse(err.Error(), "results", "metadata", "values")
 	}
 
-	return successResponse("results", results)
+	response := successResponse("results", results)
+	if response["error"] == nil {
+		metadata, err := encodeJSON(resultsMetadata(results))
+		if err != nil {
+			return errorResponse(err.Error(), "results", "metadata", "values")
+		}
+		response["metadata"] = metadata
+		values, err := encodeJSON(resultValues(results))
+		if err != nil {
+			return errorResponse(err.Error(), "results", "metadata", "values")
+		}
+		response["values"] = values
+	}
+	return response
 }
 
 // ==============================================================================
@@ -257,6 +289,12 @@ func validate(this js.Value, args []js.Value) interface{} {
 		if diag.Range != nil {
 			diagMap["range"] = diag.Range
 		}
+		if len(diag.Suggestions) > 0 {
+			diagMap["suggestions"] = diag.Suggestions
+		}
+		if len(diag.Fixes) > 0 {
+			diagMap["fixes"] = diag.Fixes
+		}
 		diagnosticsArray = append(diagnosticsArray, diagMap)
 	}
 
@@ -271,17 +309,22 @@ func validate(this js.Value, args []js.Value) interface{} {
 //
 // Why context matters: Classification depends on variable definitions.
 // Example: "total" is CALCULATION if 'total' is defined, MARKDOWN otherwise.
-// Uses globalContext to check current variable state.
+// Uses globalContext, or the given session's context, to check current
+// variable state.
 //
-// Usage: calcmark.classifyLine(line: string)
+// Usage: calcmark.classifyLine(line: string, sessionId?: number)
 // Returns: {lineType: string, error: string|null}
 func classifyLine(this js.Value, args []js.Value) interface{} {
-	if len(args) != 1 {
-		return errorResponse("Expected 1 argument: line (string)", "lineType")
+	if len(args) < 1 {
+		return errorResponse("Expected at least 1 argument: line (string)", "lineType")
 	}
 
+	ctx, err := contextArg(args, 1, true)
+	if err != nil {
+		return errorResponse(err.Error(), "lineType")
+	}
 	line := args[0].String()
-	lineType, _ := classifier.ClassifyLine(line, globalContext)
+	lineType, _ := classifier.ClassifyLine(line, ctx)
 
 	return map[string]interface{}{
 		"lineType": lineType.String(),
@@ -305,36 +348,46 @@ func classifyLine(this js.Value, args []js.Value) interface{} {
 //	Line 3: "y"            -> MARKDOWN (y is undefined)
 //
 // Critical: Uses a FRESH context, not globalContext, so each document is
-// classified independently without pollution from previous calls.
+// classified independently without pollution from previous calls. Given a
+// session, it starts from the session's variables, leaving the session
+// unchanged.
+//
+// Classification is incremental (classifier.Incremental): lines unchanged
+// since the last call, in an unchanged context, reuse their results, so
+// an editor calling this on every keystroke pays for the edited lines
+// rather than the whole document. Each session, and calls without one,
+// keep their own results.
 //
-// Usage: calcmark.classifyLines(lines: string[])
+// Usage: calcmark.classifyLines(lines: string[], sessionId?: number)
 // Returns: {classifications: string (JSON array), error: string|null}
 func classifyLines(this js.Value, args []js.Value) interface{} {
-	if len(args) != 1 {
-		return errorResponse("Expected 1 argument: lines (array of strings)", "classifications")
+	if len(args) < 1 {
+		return errorResponse("Expected at least 1 argument: lines (array of strings)", "classifications")
 	}
 
-	jsArray := args[0]
-	length := jsArray.Length()
-	results := make([]ClassificationResult, 0, length)
-
 	// Use fresh context: each document classification is independent
-	ctx := interpreter.NewEnvironment()
+	ctx, err := contextArg(args, 1, false)
+	if err != nil {
+		return errorResponse(err.Error(), "classifications")
+	}
+	sessionID := 0
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		sessionID = args[1].Int()
+	}
 
-	for i := 0; i < length; i++ {
-		line := jsArray.Index(i).String()
-		lineType, _ := classifier.ClassifyLine(line, ctx)
+	jsArray := args[0]
+	lines := make([]string, jsArray.Length())
+	for i := range lines {
+		lines[i] = jsArray.Index(i).String()
+	}
 
-		results = append(results, ClassificationResult{
-			LineType: lineType.String(),
+	classified := lineClassifier(sessionID).ClassifyLines(lines, ctx)
+	results := make([]ClassificationResult, len(lines))
+	for i, line := range lines {
+		results[i] = ClassificationResult{
+			LineType: classified[i].Type.String(),
 			Line:     line,
 			Index:    i,
-		})
-
-		// Update context if calculation: makes subsequent line classification context-aware
-		if lineType == classifier.Calculation {
-			// Evaluate to update context (ignore errors - classification shouldn't fail)
-			_ = interpreter.Evaluate(line, ctx)
 		}
 	}
 
@@ -345,15 +398,6 @@ func classifyLines(this js.Value, args []js.Value) interface{} {
 // WASM Function: evaluateDocument
 // ==============================================================================
 
-// EvaluationResultWithLine extends evaluation result with line number tracking.
-// Used by evaluateDocument to map results back to their original line numbers.
-type EvaluationResultWithLine struct {
-	Value        interface{} `json:"Value"`        // The computed value
-	Symbol       string      `json:"Symbol"`       // Currency symbol if applicable
-	SourceFormat string      `json:"SourceFormat"` // Original formatting
-	OriginalLine int         `json:"OriginalLine"` // 1-indexed line number in source document
-}
-
 // evaluateDocument evaluates a mixed document containing markdown and calculations.
 //
 // Why this exists: The evaluate() function expects pure calculation input and fails
@@ -363,8 +407,9 @@ type EvaluationResultWithLine struct {
 // Context behavior:
 //   - If useGlobalContext=true, uses globalContext (persistent across calls)
 //   - If useGlobalContext=false, uses fresh context (isolated evaluation)
+//   - If given a session ID, uses that session's context
 //
-// Usage: calcmark.evaluateDocument(sourceCode: string, useGlobalContext?: boolean)
+// Usage: calcmark.evaluateDocument(sourceCode: string, useGlobalContext?: boolean | sessionId: number)
 // Returns: {results: string (JSON array of EvaluationResultWithLine), error: string|null}
 //
 // Example:
@@ -387,118 +432,327 @@ func evaluateDocument(this js.Value, args []js.Value) interface{} {
 
 	source := args[0].String()
 
-	// Default to using global context for stateful evaluation
-	useGlobalCtx := true
-	if len(args) > 1 {
-		useGlobalCtx = args[1].Bool()
+	// Choose context: a session's or the global for persistent state, fresh
+	// for isolation. Default to the global context for stateful evaluation.
+	ctx, err := contextArg(args, 1, true)
+	if err != nil {
+		return errorResponse(err.Error(), "results")
 	}
 
-	// Choose context: global for persistent state, fresh for isolation
-	ctx := interpreter.NewEnvironment()
-	if useGlobalCtx {
-		ctx = globalContext
+	results := make([]EvaluationResultWithLine, 0)
+	for i, line := range splitLines(source) {
+		results = append(results, evaluateDocumentLine(line, i+1, ctx)...)
 	}
 
-	// Split source into lines and process each
-	lines := splitLines(source)
-	results := make([]EvaluationResultWithLine, 0)
+	return successResponse("results", results)
+}
 
-	for lineNum, line := range lines {
-		// Classify the line
-		lineType, _ := classifier.ClassifyLine(line, ctx)
+// ==============================================================================
+// WASM Functions: evaluateAsync and cancel
+// ==============================================================================
 
-		// Only evaluate calculation lines
-		if lineType != classifier.Calculation {
-			continue
-		}
+// asyncResponse is a response that settles later. withResponses returns
+// {token, promise, error}, where the promise resolves with run's response.
+type asyncResponse struct {
+	token int
+	run   func() map[string]interface{}
+}
 
-		// Parse and evaluate the line
-		nodes, err := parser.Parse(line)
-		if err != nil {
-			continue // Skip lines that fail to parse
-		}
+// evaluateAsync evaluates a mixed document like evaluateDocument, without
+// freezing the page.
+//
+// Why this exists: WASM calls run on the browser's main thread, so a long
+// document blocks input and rendering until evaluateDocument returns. This
+// evaluates a slice of lines at a time and yields to the browser between
+// slices (async.go). Editors call cancel(token) when a keystroke makes an
+// evaluation stale.
+//
+// Context behavior: defaults to a fresh context, unlike evaluateDocument. A
+// cancelled evaluation stops partway, and other calls can run while it
+// yields, so sharing the global context is opt-in.
+//
+// Usage: calcmark.evaluateAsync(sourceCode: string, useGlobalContext?: boolean | sessionId: number)
+// Returns: {token: number|null, promise: Promise, error: string|null}
+// The promise resolves with {results: string (JSON array of EvaluationResultWithLine), cancelled: boolean, error: string|null}
+func evaluateAsync(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResponse("Expected at least 1 argument: sourceCode (string)", "token", "promise")
+	}
+
+	source := args[0].String()
+	ctx, err := contextArg(args, 1, false)
+	if err != nil {
+		return errorResponse(err.Error(), "token", "promise")
+	}
 
-		interp := interpreter.NewInterpreterWithEnv(ctx)
-		evalResults, err := interp.Eval(nodes)
+	e := startEvaluation()
+	return asyncResponse{token: e.token, run: func() map[string]interface{} {
+		results, err := e.run(source, ctx, yieldToBrowser)
 		if err != nil {
-			continue // Skip lines that fail to evaluate
+			response := errorResponse(err.Error(), "results")
+			response["cancelled"] = errors.Is(err, errEvaluationCancelled)
+			return response
 		}
+		response := successResponse("results", results)
+		response["cancelled"] = false
+		return response
+	}}
+}
 
-		// Add results with line numbers (1-indexed)
-		for _, evalResult := range evalResults {
-			resultWithLine := EvaluationResultWithLine{
-				OriginalLine: lineNum + 1,
-			}
-
-			// Extract fields from types.Type interface
-			switch v := evalResult.(type) {
-			case interface{ GetValue() interface{} }:
-				resultWithLine.Value = v.GetValue()
-			default:
-				resultWithLine.Value = v
-			}
-
-			// Check for Symbol (currency types)
-			if symbolType, ok := evalResult.(interface{ GetSymbol() string }); ok {
-				resultWithLine.Symbol = symbolType.GetSymbol()
-			}
-
-			// Check for SourceFormat
-			if sourceType, ok := evalResult.(interface{ GetSourceFormat() string }); ok {
-				resultWithLine.SourceFormat = sourceType.GetSourceFormat()
-			}
-
-			results = append(results, resultWithLine)
-		}
+// cancel stops an evaluateAsync evaluation. Its promise resolves with
+// cancelled: true and no results.
+//
+// Usage: calcmark.cancel(token: number)
+// Returns: {cancelled: boolean, error: string|null}, where cancelled is
+// false if the evaluation had already finished
+func cancel(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeNumber {
+		return errorResponse("Expected 1 argument: token (number)", "cancelled")
 	}
+	return map[string]interface{}{"cancelled": cancelEvaluation(args[0].Int()), "error": nil}
+}
 
-	return successResponse("results", results)
+// yieldToBrowser blocks until a zero-delay timeout fires, returning control
+// to the browser's event loop so it can handle input and render meanwhile.
+func yieldToBrowser() {
+	done := make(chan struct{})
+	var resume js.Func
+	resume = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resume.Release()
+		close(done)
+		return nil
+	})
+	js.Global().Call("setTimeout", resume, 0)
+	<-done
 }
 
-// splitLines splits a string into lines, handling different line ending styles
-func splitLines(s string) []string {
-	var lines []string
-	var current []rune
-	runes := []rune(s)
-
-	for i := 0; i < len(runes); i++ {
-		if runes[i] == '\r' {
-			lines = append(lines, string(current))
-			current = nil
-			// Handle \r\n
-			if i+1 < len(runes) && runes[i+1] == '\n' {
-				i++
-			}
-		} else if runes[i] == '\n' {
-			lines = append(lines, string(current))
-			current = nil
-		} else {
-			current = append(current, runes[i])
-		}
+// promise runs an asyncResponse in a goroutine and returns a Promise that
+// resolves with its response, decoded as withResponses does.
+func promise(async asyncResponse, structured bool) js.Value {
+	executor := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resolve := args[0]
+		go func() {
+			resolve.Invoke(decodeResponse(async.run(), structured))
+		}()
+		return nil
+	})
+	defer executor.Release() // The Promise constructor calls it synchronously
+	return js.Global().Get("Promise").New(executor)
+}
+
+// ==============================================================================
+// WASM Function: renderDocument
+// ==============================================================================
+
+// renderDocument evaluates a complete document (frontmatter, markdown, and
+// calculation blocks) for the <calc-mark> web component.
+//
+// Why this exists: evaluateDocument works line-by-line and ignores frontmatter,
+// so it cannot support what-if overrides of declared globals. This uses the
+// same document model as the CLI, so embedded results match `cm eval`.
+// Always uses a fresh context: embeds on the same page must not share state.
+//
+// Usage: calcmark.renderDocument(sourceCode: string, overrides?: string (JSON object))
+// Returns: {document: string (JSON RenderedDocument), error: string|null}
+func renderDocument(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResponse("Expected at least 1 argument: sourceCode (string)", "document")
+	}
+
+	overrides := ""
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		overrides = args[1].String()
+	}
+
+	rendered, err := renderSource(args[0].String(), overrides)
+	if err != nil {
+		return errorResponse(err.Error(), "document")
 	}
-	// Don't forget the last line if there's no trailing newline
-	if len(current) > 0 || len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
-		lines = append(lines, string(current))
+	return successResponse("document", rendered)
+}
+
+// ==============================================================================
+// WASM Functions: document model
+// ==============================================================================
+
+// createDocument parses and evaluates a document and keeps it open for
+// block-by-block editing.
+//
+// Why this exists: evaluateDocument and renderDocument evaluate the whole
+// source on every call. Web editors that keep a document open can instead
+// replace one block's source and get back only the blocks the change
+// affects, the same O(affected blocks) updates the TUI makes.
+//
+// Usage: calcmark.createDocument(sourceCode: string)
+// Returns: {document: string (JSON DocumentUpdate with every block), error: string|null}
+func createDocument(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return errorResponse("Expected 1 argument: sourceCode (string)", "document")
+	}
+
+	update, err := createOpenDocument(args[0].String())
+	if err != nil {
+		return errorResponse(err.Error(), "document")
 	}
-	return lines
+	return successResponse("document", update)
+}
+
+// replaceBlockSource replaces an open document's block and evaluates the
+// blocks that depend on it.
+//
+// Usage: calcmark.replaceBlockSource(handle: number, blockId: string, source: string)
+// Returns: {update: string (JSON DocumentUpdate with the affected blocks), error: string|null}
+func replaceBlockSource(this js.Value, args []js.Value) interface{} {
+	if len(args) != 3 {
+		return errorResponse("Expected 3 arguments: handle (number), blockId (string), source (string)", "update")
+	}
+
+	od, err := lookupDocument(args[0].Int())
+	if err != nil {
+		return errorResponse(err.Error(), "update")
+	}
+	update, err := od.replaceSource(args[1].String(), args[2].String())
+	if err != nil {
+		return errorResponse(err.Error(), "update")
+	}
+	return successResponse("update", update)
+}
+
+// evaluateBlock evaluates one block of an open document again.
+//
+// Usage: calcmark.evaluateBlock(handle: number, blockId: string)
+// Returns: {block: string (JSON DocumentBlock), error: string|null}
+func evaluateBlock(this js.Value, args []js.Value) interface{} {
+	if len(args) != 2 {
+		return errorResponse("Expected 2 arguments: handle (number), blockId (string)", "block")
+	}
+
+	od, err := lookupDocument(args[0].Int())
+	if err != nil {
+		return errorResponse(err.Error(), "block")
+	}
+	block, err := od.evaluateBlock(args[1].String())
+	if err != nil {
+		return errorResponse(err.Error(), "block")
+	}
+	return successResponse("block", block)
+}
+
+// getBlocks returns every block of an open document with its results.
+//
+// Usage: calcmark.getBlocks(handle: number)
+// Returns: {blocks: string (JSON array of DocumentBlock), error: string|null}
+func getBlocks(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return errorResponse("Expected 1 argument: handle (number)", "blocks")
+	}
+
+	od, err := lookupDocument(args[0].Int())
+	if err != nil {
+		return errorResponse(err.Error(), "blocks")
+	}
+	return successResponse("blocks", od.blocks())
+}
+
+// getDependencies returns the variables a block of an open document
+// defines and uses, the blocks it depends on, and the blocks depending on it.
+//
+// Usage: calcmark.getDependencies(handle: number, blockId: string)
+// Returns: {dependencies: string (JSON BlockDependencies), error: string|null}
+func getDependencies(this js.Value, args []js.Value) interface{} {
+	if len(args) != 2 {
+		return errorResponse("Expected 2 arguments: handle (number), blockId (string)", "dependencies")
+	}
+
+	od, err := lookupDocument(args[0].Int())
+	if err != nil {
+		return errorResponse(err.Error(), "dependencies")
+	}
+	deps, err := od.dependencies(args[1].String())
+	if err != nil {
+		return errorResponse(err.Error(), "dependencies")
+	}
+	return successResponse("dependencies", deps)
+}
+
+// closeDocument frees an open document. Handles aren't reused.
+//
+// Usage: calcmark.closeDocument(handle: number)
+// Returns: void
+func closeDocument(this js.Value, args []js.Value) interface{} {
+	if len(args) == 1 {
+		closeOpenDocument(args[0].Int())
+	}
+	return nil
 }
 
 // ==============================================================================
 // WASM Function: resetContext
 // ==============================================================================
 
-// resetContext clears the global evaluation context.
+// resetContext clears the global evaluation context, or a session's.
 //
 // Why this exists: Allows users to start fresh without reloading the page.
 // Example use case: User wants to clear all variable definitions and start over.
 //
-// Usage: calcmark.resetContext()
+// Usage: calcmark.resetContext(sessionId?: number)
 // Returns: void
 func resetContext(this js.Value, args []js.Value) interface{} {
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		_ = resetSession(args[0].Int())
+		return nil
+	}
 	globalContext = interpreter.NewEnvironment()
 	return nil
 }
 
+// ==============================================================================
+// WASM Functions: sessions
+// ==============================================================================
+
+// createSession opens an isolated evaluation context.
+//
+// Why this exists: globalContext is shared by every caller on the page, so
+// two tabs or notebooks of a web app would see each other's variables.
+// Each can instead create a session and pass its ID to evaluate,
+// evaluateDocument, classifyLine, and classifyLines.
+//
+// Usage: calcmark.createSession()
+// Returns: {session: number|null, error: string|null}
+func createSession(this js.Value, args []js.Value) interface{} {
+	id, err := openSession()
+	if err != nil {
+		return errorResponse(err.Error(), "session")
+	}
+	return map[string]interface{}{"session": id, "error": nil}
+}
+
+// destroySession closes a session, freeing its variables.
+//
+// Usage: calcmark.destroySession(sessionId: number)
+// Returns: {destroyed: boolean, error: string|null}
+func destroySession(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return errorResponse("Expected 1 argument: sessionId (number)", "destroyed")
+	}
+	return map[string]interface{}{"destroyed": closeSession(args[0].Int()), "error": nil}
+}
+
+// setMaxSessions sets how many sessions can be open at once (default 32),
+// so a page that leaks sessions fails instead of growing without bound.
+//
+// Usage: calcmark.setMaxSessions(max: number)
+// Returns: {max: number|null, error: string|null}
+func setMaxSessions(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return errorResponse("Expected 1 argument: max (number)", "max")
+	}
+	if err := setSessionLimit(args[0].Int()); err != nil {
+		return errorResponse(err.Error(), "max")
+	}
+	return map[string]interface{}{"max": maxSessions, "error": nil}
+}
+
 // ==============================================================================
 // WASM Function: getVersion
 // ==============================================================================
@@ -518,23 +772,58 @@ func getVersion(this js.Value, args []js.Value) interface{} {
 // Main Entry Point
 // ==============================================================================
 
+// functions are the functions exported on window.calcmark.
+var functions = map[string]func(js.Value, []js.Value) interface{}{
+	"tokenize":           tokenize,
+	"parse":              parse,
+	"evaluate":           evaluate,
+	"evaluateDocument":   evaluateDocument,
+	"evaluateAsync":      evaluateAsync,
+	"cancel":             cancel,
+	"renderDocument":     renderDocument,
+	"createDocument":     createDocument,
+	"replaceBlockSource": replaceBlockSource,
+	"evaluateBlock":      evaluateBlock,
+	"getBlocks":          getBlocks,
+	"getDependencies":    getDependencies,
+	"closeDocument":      closeDocument,
+	"validate":           validate,
+	"classifyLine":       classifyLine,
+	"classifyLines":      classifyLines,
+	"resetContext":       resetContext,
+	"createSession":      createSession,
+	"destroySession":     destroySession,
+	"setMaxSessions":     setMaxSessions,
+	"getVersion":         getVersion,
+}
+
+// withResponses wraps an exported function to decode its response's JSON
+// data as strings, or as objects when structured is true.
+func withResponses(fn func(js.Value, []js.Value) interface{}, structured bool) func(js.Value, []js.Value) interface{} {
+	return func(this js.Value, args []js.Value) interface{} {
+		response := fn(this, args)
+		if async, ok := response.(asyncResponse); ok {
+			return map[string]interface{}{"token": async.token, "promise": promise(async, structured), "error": nil}
+		}
+		return decodeResponse(response, structured)
+	}
+}
+
 func main() {
 	// WASM programs must block forever - if main() exits, the module unloads.
 	// We use an unbuffered channel that never receives to keep the program alive.
 	done := make(chan struct{})
 
-	// Register all functions on window.calcmark object
-	js.Global().Set("calcmark", map[string]interface{}{
-		"tokenize":         js.FuncOf(tokenize),
-		"parse":            js.FuncOf(parse),
-		"evaluate":         js.FuncOf(evaluate),
-		"evaluateDocument": js.FuncOf(evaluateDocument),
-		"validate":         js.FuncOf(validate),
-		"classifyLine":     js.FuncOf(classifyLine),
-		"classifyLines":    js.FuncOf(classifyLines),
-		"resetContext":     js.FuncOf(resetContext),
-		"getVersion":       js.FuncOf(getVersion),
-	})
+	// Register all functions on window.calcmark object, and again on
+	// calcmark.v2 returning plain objects instead of JSON strings
+	api := map[string]interface{}{}
+	v2 := map[string]interface{}{}
+	for name, fn := range functions {
+		api[name] = js.FuncOf(withResponses(fn, false))
+		v2[name] = js.FuncOf(withResponses(fn, true))
+	}
+	api["v2"] = v2
+	js.Global().Set("calcmark", api)
 
 	// Block forever to keep WASM module loaded
 	<-done
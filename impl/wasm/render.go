@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/format/display"
+	implDoc "github.com/CalcMark/go-calcmark/impl/document"
+	"github.com/CalcMark/go-calcmark/spec/document"
+)
+
+// RenderedDocument is a whole document evaluated for display by the
+// <calc-mark> web component. Text blocks carry raw markdown because
+// markdown rendering is not available in WASM builds.
+type RenderedDocument struct {
+	Params []RenderedParam `json:"params"` // Frontmatter globals, overridable via data-overrides
+	Blocks []RenderedBlock `json:"blocks"`
+}
+
+// RenderedParam is a declared frontmatter global and its effective value.
+type RenderedParam struct {
+	Name       string          `json:"name"`
+	Value      string          `json:"value"`      // Source text after overrides, e.g. "$5000"
+	Overridden bool            `json:"overridden"` // True if data-overrides replaced the declared value
+	Widget     *RenderedWidget `json:"widget,omitempty"`
+}
+
+// RenderedWidget is the frontmatter widget declared for a param, so the page
+// can build a matching control. Numbers are strings to keep decimal precision.
+type RenderedWidget struct {
+	Type    string   `json:"type"`
+	Min     string   `json:"min,omitempty"`
+	Max     string   `json:"max,omitempty"`
+	Step    string   `json:"step,omitempty"`
+	Options []string `json:"options,omitempty"`
+}
+
+// RenderedBlock is one document block: markdown text or calculation lines.
+type RenderedBlock struct {
+	Type     string         `json:"type"`               // "text" or "calculation"
+	Markdown string         `json:"markdown,omitempty"` // Text blocks only
+	Lines    []RenderedLine `json:"lines,omitempty"`    // Calculation blocks only
+	Error    string         `json:"error,omitempty"`
+}
+
+// RenderedLine is a calculation source line with its formatted result.
+type RenderedLine struct {
+	Source    string `json:"source"`
+	Result    string `json:"result,omitempty"`
+	Highlight string `json:"highlight,omitempty"` // Color from frontmatter highlight rules
+}
+
+// renderSource evaluates a complete CalcMark document, with frontmatter
+// globals replaced by overrides, and returns it ready for display.
+//
+// overridesJSON is a JSON object mapping global names to new values, e.g.
+// {"budget": "$6000", "growth": 0.05}. Only declared globals can be
+// overridden, so a page cannot inject variables the document doesn't expose.
+// An empty string means no overrides.
+func renderSource(source, overridesJSON string) (*RenderedDocument, error) {
+	doc, err := document.NewDocument(source)
+	if err != nil {
+		return nil, err
+	}
+
+	fm := doc.GetFrontmatter()
+	declared := map[string]string{}
+	if fm != nil {
+		declared = maps.Clone(fm.Globals)
+	}
+
+	overrides, err := parseOverrides(overridesJSON)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) > 0 {
+		for name := range overrides {
+			if _, ok := declared[name]; !ok {
+				return nil, fmt.Errorf("cannot override '%s': not a frontmatter global", name)
+			}
+		}
+		if _, err := document.ParseGlobalsWithLocale(overrides, doc.NumberLocale()); err != nil {
+			return nil, fmt.Errorf("invalid overrides: %w", err)
+		}
+		maps.Copy(fm.Globals, overrides)
+	}
+
+	rendered := &RenderedDocument{Params: []RenderedParam{}, Blocks: []RenderedBlock{}}
+	for _, name := range slices.Sorted(maps.Keys(declared)) {
+		_, overridden := overrides[name]
+		param := RenderedParam{
+			Name:       name,
+			Value:      fm.Globals[name],
+			Overridden: overridden,
+		}
+		if w, ok := fm.Widget(name); ok {
+			param.Widget = &RenderedWidget{Type: w.Type, Step: w.Step.String(), Options: w.Options}
+			if w.Min != nil {
+				param.Widget.Min = w.Min.String()
+			}
+			if w.Max != nil {
+				param.Widget.Max = w.Max.String()
+			}
+		}
+		rendered.Params = append(rendered.Params, param)
+	}
+
+	// Evaluation stops at the first failing block; its error is shown inline
+	// and later blocks render without results.
+	_ = implDoc.NewEvaluator().Evaluate(doc)
+
+	for _, node := range doc.GetBlocks() {
+		rendered.Blocks = append(rendered.Blocks, renderBlock(doc, node))
+	}
+
+	return rendered, nil
+}
+
+// renderBlock formats an evaluated block for display.
+func renderBlock(doc *document.Document, node *document.BlockNode) RenderedBlock {
+	switch block := node.Block.(type) {
+	case *document.CalcBlock:
+		rb := RenderedBlock{Type: "calculation"}
+		results := block.Results()
+		highlights := doc.Highlights(block)
+		for i, line := range block.Source() {
+			if line == "" {
+				continue
+			}
+			rl := RenderedLine{Source: line}
+			if i < len(results) && results[i] != nil {
+				rl.Result = display.Format(results[i])
+			}
+			if i < len(highlights) {
+				rl.Highlight = highlights[i]
+			}
+			rb.Lines = append(rb.Lines, rl)
+		}
+		if block.Error() != nil {
+			rb.Error = block.Error().Error()
+		}
+		return rb
+	}
+	return RenderedBlock{Type: "text", Markdown: strings.Join(node.Block.Source(), "\n")}
+}
+
+// parseOverrides decodes a data-overrides JSON object into global source text.
+// Numbers keep their literal form ("0.05", not "5e-02").
+func parseOverrides(overridesJSON string) (map[string]string, error) {
+	if strings.TrimSpace(overridesJSON) == "" {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(overridesJSON))
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid overrides: %w", err)
+	}
+
+	overrides := make(map[string]string, len(raw))
+	for name, value := range raw {
+		switch v := value.(type) {
+		case string:
+			overrides[name] = v
+		case json.Number:
+			overrides[name] = v.String()
+		case bool:
+			overrides[name] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("invalid override for '%s': expected a string, number, or boolean", name)
+		}
+	}
+	return overrides, nil
+}
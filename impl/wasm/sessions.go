@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/classifier"
+)
+
+// Sessions are isolated evaluation contexts, one per tab or notebook of a
+// web app, so they don't share globalContext's variables. evaluate,
+// evaluateDocument, classifyLine, and classifyLines take a session ID in
+// place of their useGlobalContext argument.
+
+// defaultMaxSessions is how many sessions can be open at once unless
+// setMaxSessions changes it.
+const defaultMaxSessions = 32
+
+var (
+	sessions      = map[int]*interpreter.Environment{}
+	nextSessionID = 1
+	maxSessions   = defaultMaxSessions
+
+	// classifiers memoize classifyLines for each session, and for calls
+	// without one under ID 0
+	classifiers = map[int]*classifier.Incremental{}
+)
+
+// openSession creates an empty session and returns its ID. IDs aren't
+// reused, so a destroyed session's ID can't reach a newer one.
+func openSession() (int, error) {
+	if len(sessions) >= maxSessions {
+		return 0, fmt.Errorf("too many sessions: %d are open (max %d); destroy one first", len(sessions), maxSessions)
+	}
+	id := nextSessionID
+	nextSessionID++
+	sessions[id] = interpreter.NewEnvironment()
+	return id, nil
+}
+
+// closeSession forgets a session and its variables, reporting whether it
+// was open.
+func closeSession(id int) bool {
+	_, ok := sessions[id]
+	delete(sessions, id)
+	delete(classifiers, id)
+	return ok
+}
+
+// lineClassifier returns the incremental classifier for a session's
+// documents, or for documents classified without one for ID 0.
+func lineClassifier(id int) *classifier.Incremental {
+	c, ok := classifiers[id]
+	if !ok {
+		c = classifier.NewIncremental()
+		classifiers[id] = c
+	}
+	return c
+}
+
+// sessionEnv returns a session's environment.
+func sessionEnv(id int) (*interpreter.Environment, error) {
+	env, ok := sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("no session with ID %d", id)
+	}
+	return env, nil
+}
+
+// resetSession clears a session's variables.
+func resetSession(id int) error {
+	if _, ok := sessions[id]; !ok {
+		return fmt.Errorf("no session with ID %d", id)
+	}
+	sessions[id] = interpreter.NewEnvironment()
+	return nil
+}
+
+// setSessionLimit sets how many sessions can be open at once. Sessions
+// already open stay open, even above the new limit.
+func setSessionLimit(n int) error {
+	if n < 1 {
+		return fmt.Errorf("max sessions must be at least 1, got %d", n)
+	}
+	maxSessions = n
+	return nil
+}
@@ -0,0 +1,73 @@
+//go:build !wasm
+// +build !wasm
+
+package main
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+func TestSessionsAreIsolated(t *testing.T) {
+	a, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeSession(a)
+	b, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeSession(b)
+
+	envA, _ := sessionEnv(a)
+	if err := interpreter.Evaluate("x = 5", envA); err != nil {
+		t.Fatal(err)
+	}
+	envB, _ := sessionEnv(b)
+	if envB.Has("x") || globalContext.Has("x") {
+		t.Error("a variable set in one session should not be visible elsewhere")
+	}
+
+	if err := resetSession(a); err != nil {
+		t.Fatal(err)
+	}
+	if envA, _ = sessionEnv(a); envA.Has("x") {
+		t.Error("resetting a session should clear its variables")
+	}
+
+	if !closeSession(b) || closeSession(b) {
+		t.Error("closeSession should report whether the session was open")
+	}
+	if _, err := sessionEnv(b); err == nil {
+		t.Error("a destroyed session should not be found")
+	}
+}
+
+func TestSessionLimit(t *testing.T) {
+	defer func() { maxSessions = defaultMaxSessions }()
+	if err := setSessionLimit(0); err == nil {
+		t.Error("a limit below 1 should be rejected")
+	}
+	if err := setSessionLimit(len(sessions) + 1); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openSession(); err == nil {
+		t.Error("opening a session beyond the limit should fail")
+	}
+	closeSession(id)
+	again, err := openSession()
+	if err != nil {
+		t.Fatalf("destroying a session should make room: %v", err)
+	}
+	defer closeSession(again)
+	if again == id {
+		t.Error("session IDs should not be reused")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/CalcMark/go-calcmark/format"
+	"github.com/CalcMark/go-calcmark/format/display"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// ==============================================================================
+// Response Encoding
+// ==============================================================================
+
+// jsonValue is response data serialized by successResponse. Before a
+// response reaches JavaScript, decodeResponse turns it into a JSON string
+// for the original API or a plain JS object for calcmark.v2.
+type jsonValue []byte
+
+// encodeJSON serializes response data. The JSON tags are the one definition
+// of each response's shape, so both APIs return the same fields.
+func encodeJSON(data interface{}) (jsonValue, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return jsonValue(b), nil
+}
+
+// decodeResponse converts the jsonValue fields of a function's response.
+// With structured false they become JSON strings, as the original API has
+// always returned. With structured true they become maps, slices, and
+// scalars that js.ValueOf turns into real JS objects, so callers don't
+// JSON.parse every result. Other responses pass through unchanged.
+//
+// The response map is modified in place.
+func decodeResponse(response interface{}, structured bool) interface{} {
+	fields, ok := response.(map[string]interface{})
+	if !ok {
+		return response
+	}
+	for key, value := range fields {
+		data, ok := value.(jsonValue)
+		if !ok {
+			continue
+		}
+		if !structured {
+			fields[key] = string(data)
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			fields[key] = string(data) // Unreachable: data came from json.Marshal
+			continue
+		}
+		fields[key] = decoded
+	}
+	return fields
+}
+
+// ==============================================================================
+// Result Metadata
+// ==============================================================================
+
+// ResultMetadata describes an evaluation result for callers that need more
+// than its display string. Raw keeps full decimal precision as a string,
+// since JS numbers cannot.
+type ResultMetadata struct {
+	Type     string        `json:"type"`               // e.g. "number", "currency", "quantity"
+	Display  string        `json:"display"`            // Formatted as the CLI shows it, e.g. "$1.5K"
+	Raw      string        `json:"raw,omitempty"`      // Unformatted decimal, or ISO date or time
+	Unit     string        `json:"unit,omitempty"`     // Unit, or "unit/period" for rates
+	Currency *CurrencyInfo `json:"currency,omitempty"` // Currency results only
+}
+
+// CurrencyInfo identifies a currency result's currency.
+type CurrencyInfo struct {
+	Code   string `json:"code"`   // ISO 4217 code, e.g. "USD"
+	Symbol string `json:"symbol"` // As written, e.g. "$"
+}
+
+// resultMetadata describes a result the way the CLI's JSON output does.
+func resultMetadata(value types.Type) ResultMetadata {
+	row := format.ValueRow(value, display.Options{})
+	meta := ResultMetadata{Type: row.Type, Display: row.Value, Raw: row.Raw, Unit: row.Unit}
+	if v := value.ToValue(); v.CurrencyCode != "" {
+		meta.Unit = ""
+		meta.Currency = &CurrencyInfo{Code: v.CurrencyCode, Symbol: v.CurrencySymbol}
+	}
+	return meta
+}
+
+// resultValues returns each result's structured form.
+func resultValues(values []types.Type) []types.Value {
+	structured := make([]types.Value, len(values))
+	for i, v := range values {
+		structured[i] = v.ToValue()
+	}
+	return structured
+}
+
+// resultsMetadata describes each of a line's results.
+func resultsMetadata(values []types.Type) []ResultMetadata {
+	meta := make([]ResultMetadata, 0, len(values))
+	for _, v := range values {
+		meta = append(meta, resultMetadata(v))
+	}
+	return meta
+}
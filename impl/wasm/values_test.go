@@ -0,0 +1,70 @@
+//go:build !wasm
+// +build !wasm
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	tokens := []TokenInfo{{Type: "NUMBER", Value: "5", OriginalText: "5", Start: 0, End: 1, Line: 1}}
+	encoded, err := encodeJSON(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asString := decodeResponse(map[string]interface{}{"tokens": encoded, "error": nil}, false)
+	if got := asString.(map[string]interface{})["tokens"]; got != string(encoded) {
+		t.Errorf("original API should get a JSON string, got %#v", got)
+	}
+
+	encoded, _ = encodeJSON(tokens)
+	structured := decodeResponse(map[string]interface{}{"tokens": encoded, "error": nil}, true)
+	want := []interface{}{map[string]interface{}{
+		"type": "NUMBER", "value": "5", "originalText": "5",
+		"start": float64(0), "end": float64(1), "line": float64(1),
+	}}
+	if got := structured.(map[string]interface{})["tokens"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("v2 should get objects:\n got %#v\nwant %#v", got, want)
+	}
+
+	// Plain fields and non-map responses are left alone
+	plain := decodeResponse(map[string]interface{}{"lineType": "CALCULATION", "error": nil}, true)
+	if got := plain.(map[string]interface{})["lineType"]; got != "CALCULATION" {
+		t.Errorf("lineType = %#v", got)
+	}
+	if got := decodeResponse("1.0.0", true); got != "1.0.0" {
+		t.Errorf("version = %#v", got)
+	}
+}
+
+func TestResultMetadata(t *testing.T) {
+	tests := []struct {
+		source string
+		want   ResultMetadata
+	}{
+		{"1500.25", ResultMetadata{Type: "number", Display: "1.5K", Raw: "1500.25"}},
+		{"$12.50 * 3", ResultMetadata{Type: "currency", Display: "$37.50", Raw: "37.5",
+			Currency: &CurrencyInfo{Code: "USD", Symbol: "$"}}},
+		{"5 km", ResultMetadata{Type: "quantity", Display: "5 km", Raw: "5", Unit: "km"}},
+		{"Jan 2 2025", ResultMetadata{Type: "date", Display: "Thursday, January 2, 2025", Raw: "2025-01-02"}},
+	}
+	for _, tt := range tests {
+		nodes, err := parser.Parse(tt.source + "\n")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.source, err)
+		}
+		results, err := interpreter.NewInterpreterWithEnv(interpreter.NewEnvironment()).Eval(nodes)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("%s: %v %v", tt.source, results, err)
+		}
+		if got := resultMetadata(results[0]); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.source, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,1175 @@
+// CalcMark evaluation as a gRPC service, for services embedding CalcMark.
+// Messages mirror the JSON export (cm convert --to=json) and the HTTP API
+// of cm serve, with the same field names.
+//
+// Regenerate the Go code with `task generate:proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/calcmark/v1/calcmark.proto
+
+package calcmarkv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EvaluateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// CalcMark source, frontmatter included.
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// Replacements for frontmatter globals and exchange rates, as
+	// cm eval --set: the name must be declared by the frontmatter.
+	Overrides     map[string]string `protobuf:"bytes,2,rep,name=overrides,proto3" json:"overrides,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EvaluateRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetOverrides() map[string]string {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+type EvaluateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Version of the structure, as in the JSON export.
+	SchemaVersion int32    `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Blocks        []*Block `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	// Document-wide diagnostics, such as size limits.
+	Diagnostics   []*Diagnostic `protobuf:"bytes,3,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateResponse) Reset() {
+	*x = EvaluateResponse{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateResponse) ProtoMessage() {}
+
+func (x *EvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EvaluateResponse) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *EvaluateResponse) GetBlocks() []*Block {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *EvaluateResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type ValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Overrides     map[string]string      `protobuf:"bytes,2,rep,name=overrides,proto3" json:"overrides,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetOverrides() map[string]string {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+type ValidateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// No errors; warnings and hints are allowed.
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// Lines and columns are 1-indexed in the whole source.
+	Diagnostics   []*Diagnostic `protobuf:"bytes,2,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type StreamResultsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*StreamResultsResponse_Block
+	//	*StreamResultsResponse_Summary
+	Result        isStreamResultsResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamResultsResponse) Reset() {
+	*x = StreamResultsResponse{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResultsResponse) ProtoMessage() {}
+
+func (x *StreamResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResultsResponse.ProtoReflect.Descriptor instead.
+func (*StreamResultsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamResultsResponse) GetResult() isStreamResultsResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *StreamResultsResponse) GetBlock() *Block {
+	if x != nil {
+		if x, ok := x.Result.(*StreamResultsResponse_Block); ok {
+			return x.Block
+		}
+	}
+	return nil
+}
+
+func (x *StreamResultsResponse) GetSummary() *Summary {
+	if x != nil {
+		if x, ok := x.Result.(*StreamResultsResponse_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isStreamResultsResponse_Result interface {
+	isStreamResultsResponse_Result()
+}
+
+type StreamResultsResponse_Block struct {
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3,oneof"`
+}
+
+type StreamResultsResponse_Summary struct {
+	// Sent last, once every block has been.
+	Summary *Summary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*StreamResultsResponse_Block) isStreamResultsResponse_Result() {}
+
+func (*StreamResultsResponse_Summary) isStreamResultsResponse_Result() {}
+
+// Summary ends a StreamResults stream.
+type Summary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion int32                  `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// Blocks sent. Fewer than the document has if one failed, since
+	// evaluation stops there.
+	Blocks        int32         `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Diagnostics   []*Diagnostic `protobuf:"bytes,3,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Summary) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Summary) GetBlocks() int32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+func (x *Summary) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type Block struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position in the document, which depends_on refers to.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// "calculation" or "text".
+	Type   string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Source []string `protobuf:"bytes,3,rep,name=source,proto3" json:"source,omitempty"`
+	// Display form of the block's last value.
+	Output    string         `protobuf:"bytes,4,opt,name=output,proto3" json:"output,omitempty"`
+	Error     string         `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Variables []string       `protobuf:"bytes,6,rep,name=variables,proto3" json:"variables,omitempty"`
+	Metadata  *BlockMetadata `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Variable to color, from highlight rules.
+	Highlight map[string]string `protobuf:"bytes,8,rep,name=highlight,proto3" json:"highlight,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Results   []*Result         `protobuf:"bytes,9,rep,name=results,proto3" json:"results,omitempty"`
+	DependsOn []*Dependency     `protobuf:"bytes,10,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// Those not on a result's line.
+	Diagnostics   []*Diagnostic `protobuf:"bytes,11,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Block) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Block) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Block) GetSource() []string {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *Block) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *Block) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Block) GetVariables() []string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *Block) GetMetadata() *BlockMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Block) GetHighlight() map[string]string {
+	if x != nil {
+		return x.Highlight
+	}
+	return nil
+}
+
+func (x *Block) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *Block) GetDependsOn() []*Dependency {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Block) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+// Result is one calculation line and its value.
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1-indexed line within the block.
+	Line     int32  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Source   string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Variable string `protobuf:"bytes,3,opt,name=variable,proto3" json:"variable,omitempty"`
+	// Display form, e.g. "$1.5K".
+	Output string `protobuf:"bytes,4,opt,name=output,proto3" json:"output,omitempty"`
+	// Exact value in the document's number locale.
+	Value string `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	// Unformatted number, or ISO date or time.
+	RawValue string `protobuf:"bytes,6,opt,name=raw_value,json=rawValue,proto3" json:"raw_value,omitempty"`
+	// e.g. "currency".
+	Type string `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	// Unit, or "unit/period" for rates.
+	Unit          string        `protobuf:"bytes,8,opt,name=unit,proto3" json:"unit,omitempty"`
+	Currency      *Currency     `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	Distribution  *Distribution `protobuf:"bytes,10,opt,name=distribution,proto3" json:"distribution,omitempty"`
+	Diagnostics   []*Diagnostic `protobuf:"bytes,11,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Result) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Result) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Result) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *Result) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *Result) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Result) GetRawValue() string {
+	if x != nil {
+		return x.RawValue
+	}
+	return ""
+}
+
+func (x *Result) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Result) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Result) GetCurrency() *Currency {
+	if x != nil {
+		return x.Currency
+	}
+	return nil
+}
+
+func (x *Result) GetDistribution() *Distribution {
+	if x != nil {
+		return x.Distribution
+	}
+	return nil
+}
+
+func (x *Result) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type Currency struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO 4217 code, e.g. "USD".
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// As written, e.g. "$".
+	Symbol        string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Currency) Reset() {
+	*x = Currency{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Currency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Currency) ProtoMessage() {}
+
+func (x *Currency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Currency.ProtoReflect.Descriptor instead.
+func (*Currency) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Currency) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Currency) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+// Distribution summarizes an uncertain result, whose raw_value is its mean.
+// Values are unformatted numbers in the result's unit.
+type Distribution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Samples       int32                  `protobuf:"varint,1,opt,name=samples,proto3" json:"samples,omitempty"`
+	Mean          string                 `protobuf:"bytes,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	P5            string                 `protobuf:"bytes,3,opt,name=p5,proto3" json:"p5,omitempty"`
+	P50           string                 `protobuf:"bytes,4,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95           string                 `protobuf:"bytes,5,opt,name=p95,proto3" json:"p95,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Distribution) Reset() {
+	*x = Distribution{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Distribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Distribution) ProtoMessage() {}
+
+func (x *Distribution) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Distribution.ProtoReflect.Descriptor instead.
+func (*Distribution) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Distribution) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *Distribution) GetMean() string {
+	if x != nil {
+		return x.Mean
+	}
+	return ""
+}
+
+func (x *Distribution) GetP5() string {
+	if x != nil {
+		return x.P5
+	}
+	return ""
+}
+
+func (x *Distribution) GetP50() string {
+	if x != nil {
+		return x.P50
+	}
+	return ""
+}
+
+func (x *Distribution) GetP95() string {
+	if x != nil {
+		return x.P95
+	}
+	return ""
+}
+
+type Diagnostic struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "error", "warning", or "hint".
+	Severity      string   `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"`
+	Code          string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Line          int32    `protobuf:"varint,4,opt,name=line,proto3" json:"line,omitempty"`
+	Column        int32    `protobuf:"varint,5,opt,name=column,proto3" json:"column,omitempty"`
+	Suggestions   []string `protobuf:"bytes,6,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Diagnostic) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetSuggestions() []string {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// Dependency is an edge from a block to an earlier block defining a
+// variable it uses.
+type Dependency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Block         int32                  `protobuf:"varint,1,opt,name=block,proto3" json:"block,omitempty"`
+	Variable      string                 `protobuf:"bytes,2,opt,name=variable,proto3" json:"variable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dependency) Reset() {
+	*x = Dependency{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dependency) ProtoMessage() {}
+
+func (x *Dependency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dependency.ProtoReflect.Descriptor instead.
+func (*Dependency) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Dependency) GetBlock() int32 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+func (x *Dependency) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+type BlockMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Attrs         map[string]string      `protobuf:"bytes,3,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockMetadata) Reset() {
+	*x = BlockMetadata{}
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockMetadata) ProtoMessage() {}
+
+func (x *BlockMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_calcmark_v1_calcmark_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockMetadata.ProtoReflect.Descriptor instead.
+func (*BlockMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_calcmark_v1_calcmark_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BlockMetadata) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *BlockMetadata) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *BlockMetadata) GetAttrs() map[string]string {
+	if x != nil {
+		return x.Attrs
+	}
+	return nil
+}
+
+var File_proto_calcmark_v1_calcmark_proto protoreflect.FileDescriptor
+
+const file_proto_calcmark_v1_calcmark_proto_rawDesc = "" +
+	"\n" +
+	" proto/calcmark/v1/calcmark.proto\x12\vcalcmark.v1\"\xb2\x01\n" +
+	"\x0fEvaluateRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12I\n" +
+	"\toverrides\x18\x02 \x03(\v2+.calcmark.v1.EvaluateRequest.OverridesEntryR\toverrides\x1a<\n" +
+	"\x0eOverridesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa0\x01\n" +
+	"\x10EvaluateResponse\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12*\n" +
+	"\x06blocks\x18\x02 \x03(\v2\x12.calcmark.v1.BlockR\x06blocks\x129\n" +
+	"\vdiagnostics\x18\x03 \x03(\v2\x17.calcmark.v1.DiagnosticR\vdiagnostics\"\xb2\x01\n" +
+	"\x0fValidateRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12I\n" +
+	"\toverrides\x18\x02 \x03(\v2+.calcmark.v1.ValidateRequest.OverridesEntryR\toverrides\x1a<\n" +
+	"\x0eOverridesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"c\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x129\n" +
+	"\vdiagnostics\x18\x02 \x03(\v2\x17.calcmark.v1.DiagnosticR\vdiagnostics\"\x7f\n" +
+	"\x15StreamResultsResponse\x12*\n" +
+	"\x05block\x18\x01 \x01(\v2\x12.calcmark.v1.BlockH\x00R\x05block\x120\n" +
+	"\asummary\x18\x02 \x01(\v2\x14.calcmark.v1.SummaryH\x00R\asummaryB\b\n" +
+	"\x06result\"\x83\x01\n" +
+	"\aSummary\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12\x16\n" +
+	"\x06blocks\x18\x02 \x01(\x05R\x06blocks\x129\n" +
+	"\vdiagnostics\x18\x03 \x03(\v2\x17.calcmark.v1.DiagnosticR\vdiagnostics\"\xee\x03\n" +
+	"\x05Block\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06source\x18\x03 \x03(\tR\x06source\x12\x16\n" +
+	"\x06output\x18\x04 \x01(\tR\x06output\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1c\n" +
+	"\tvariables\x18\x06 \x03(\tR\tvariables\x126\n" +
+	"\bmetadata\x18\a \x01(\v2\x1a.calcmark.v1.BlockMetadataR\bmetadata\x12?\n" +
+	"\thighlight\x18\b \x03(\v2!.calcmark.v1.Block.HighlightEntryR\thighlight\x12-\n" +
+	"\aresults\x18\t \x03(\v2\x13.calcmark.v1.ResultR\aresults\x126\n" +
+	"\n" +
+	"depends_on\x18\n" +
+	" \x03(\v2\x17.calcmark.v1.DependencyR\tdependsOn\x129\n" +
+	"\vdiagnostics\x18\v \x03(\v2\x17.calcmark.v1.DiagnosticR\vdiagnostics\x1a<\n" +
+	"\x0eHighlightEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf0\x02\n" +
+	"\x06Result\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x1a\n" +
+	"\bvariable\x18\x03 \x01(\tR\bvariable\x12\x16\n" +
+	"\x06output\x18\x04 \x01(\tR\x06output\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\tR\x05value\x12\x1b\n" +
+	"\traw_value\x18\x06 \x01(\tR\brawValue\x12\x12\n" +
+	"\x04type\x18\a \x01(\tR\x04type\x12\x12\n" +
+	"\x04unit\x18\b \x01(\tR\x04unit\x121\n" +
+	"\bcurrency\x18\t \x01(\v2\x15.calcmark.v1.CurrencyR\bcurrency\x12=\n" +
+	"\fdistribution\x18\n" +
+	" \x01(\v2\x19.calcmark.v1.DistributionR\fdistribution\x129\n" +
+	"\vdiagnostics\x18\v \x03(\v2\x17.calcmark.v1.DiagnosticR\vdiagnostics\"6\n" +
+	"\bCurrency\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\"p\n" +
+	"\fDistribution\x12\x18\n" +
+	"\asamples\x18\x01 \x01(\x05R\asamples\x12\x12\n" +
+	"\x04mean\x18\x02 \x01(\tR\x04mean\x12\x0e\n" +
+	"\x02p5\x18\x03 \x01(\tR\x02p5\x12\x10\n" +
+	"\x03p50\x18\x04 \x01(\tR\x03p50\x12\x10\n" +
+	"\x03p95\x18\x05 \x01(\tR\x03p95\"\xa4\x01\n" +
+	"\n" +
+	"Diagnostic\x12\x1a\n" +
+	"\bseverity\x18\x01 \x01(\tR\bseverity\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x12\n" +
+	"\x04line\x18\x04 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06column\x18\x05 \x01(\x05R\x06column\x12 \n" +
+	"\vsuggestions\x18\x06 \x03(\tR\vsuggestions\">\n" +
+	"\n" +
+	"Dependency\x12\x14\n" +
+	"\x05block\x18\x01 \x01(\x05R\x05block\x12\x1a\n" +
+	"\bvariable\x18\x02 \x01(\tR\bvariable\"\xb0\x01\n" +
+	"\rBlockMetadata\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12;\n" +
+	"\x05attrs\x18\x03 \x03(\v2%.calcmark.v1.BlockMetadata.AttrsEntryR\x05attrs\x1a8\n" +
+	"\n" +
+	"AttrsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xf1\x01\n" +
+	"\bCalcMark\x12G\n" +
+	"\bEvaluate\x12\x1c.calcmark.v1.EvaluateRequest\x1a\x1d.calcmark.v1.EvaluateResponse\x12G\n" +
+	"\bValidate\x12\x1c.calcmark.v1.ValidateRequest\x1a\x1d.calcmark.v1.ValidateResponse\x12S\n" +
+	"\rStreamResults\x12\x1c.calcmark.v1.EvaluateRequest\x1a\".calcmark.v1.StreamResultsResponse0\x01B>Z<github.com/CalcMark/go-calcmark/proto/calcmark/v1;calcmarkv1b\x06proto3"
+
+var (
+	file_proto_calcmark_v1_calcmark_proto_rawDescOnce sync.Once
+	file_proto_calcmark_v1_calcmark_proto_rawDescData []byte
+)
+
+func file_proto_calcmark_v1_calcmark_proto_rawDescGZIP() []byte {
+	file_proto_calcmark_v1_calcmark_proto_rawDescOnce.Do(func() {
+		file_proto_calcmark_v1_calcmark_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_calcmark_v1_calcmark_proto_rawDesc), len(file_proto_calcmark_v1_calcmark_proto_rawDesc)))
+	})
+	return file_proto_calcmark_v1_calcmark_proto_rawDescData
+}
+
+var file_proto_calcmark_v1_calcmark_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_calcmark_v1_calcmark_proto_goTypes = []any{
+	(*EvaluateRequest)(nil),       // 0: calcmark.v1.EvaluateRequest
+	(*EvaluateResponse)(nil),      // 1: calcmark.v1.EvaluateResponse
+	(*ValidateRequest)(nil),       // 2: calcmark.v1.ValidateRequest
+	(*ValidateResponse)(nil),      // 3: calcmark.v1.ValidateResponse
+	(*StreamResultsResponse)(nil), // 4: calcmark.v1.StreamResultsResponse
+	(*Summary)(nil),               // 5: calcmark.v1.Summary
+	(*Block)(nil),                 // 6: calcmark.v1.Block
+	(*Result)(nil),                // 7: calcmark.v1.Result
+	(*Currency)(nil),              // 8: calcmark.v1.Currency
+	(*Distribution)(nil),          // 9: calcmark.v1.Distribution
+	(*Diagnostic)(nil),            // 10: calcmark.v1.Diagnostic
+	(*Dependency)(nil),            // 11: calcmark.v1.Dependency
+	(*BlockMetadata)(nil),         // 12: calcmark.v1.BlockMetadata
+	nil,                           // 13: calcmark.v1.EvaluateRequest.OverridesEntry
+	nil,                           // 14: calcmark.v1.ValidateRequest.OverridesEntry
+	nil,                           // 15: calcmark.v1.Block.HighlightEntry
+	nil,                           // 16: calcmark.v1.BlockMetadata.AttrsEntry
+}
+var file_proto_calcmark_v1_calcmark_proto_depIdxs = []int32{
+	13, // 0: calcmark.v1.EvaluateRequest.overrides:type_name -> calcmark.v1.EvaluateRequest.OverridesEntry
+	6,  // 1: calcmark.v1.EvaluateResponse.blocks:type_name -> calcmark.v1.Block
+	10, // 2: calcmark.v1.EvaluateResponse.diagnostics:type_name -> calcmark.v1.Diagnostic
+	14, // 3: calcmark.v1.ValidateRequest.overrides:type_name -> calcmark.v1.ValidateRequest.OverridesEntry
+	10, // 4: calcmark.v1.ValidateResponse.diagnostics:type_name -> calcmark.v1.Diagnostic
+	6,  // 5: calcmark.v1.StreamResultsResponse.block:type_name -> calcmark.v1.Block
+	5,  // 6: calcmark.v1.StreamResultsResponse.summary:type_name -> calcmark.v1.Summary
+	10, // 7: calcmark.v1.Summary.diagnostics:type_name -> calcmark.v1.Diagnostic
+	12, // 8: calcmark.v1.Block.metadata:type_name -> calcmark.v1.BlockMetadata
+	15, // 9: calcmark.v1.Block.highlight:type_name -> calcmark.v1.Block.HighlightEntry
+	7,  // 10: calcmark.v1.Block.results:type_name -> calcmark.v1.Result
+	11, // 11: calcmark.v1.Block.depends_on:type_name -> calcmark.v1.Dependency
+	10, // 12: calcmark.v1.Block.diagnostics:type_name -> calcmark.v1.Diagnostic
+	8,  // 13: calcmark.v1.Result.currency:type_name -> calcmark.v1.Currency
+	9,  // 14: calcmark.v1.Result.distribution:type_name -> calcmark.v1.Distribution
+	10, // 15: calcmark.v1.Result.diagnostics:type_name -> calcmark.v1.Diagnostic
+	16, // 16: calcmark.v1.BlockMetadata.attrs:type_name -> calcmark.v1.BlockMetadata.AttrsEntry
+	0,  // 17: calcmark.v1.CalcMark.Evaluate:input_type -> calcmark.v1.EvaluateRequest
+	2,  // 18: calcmark.v1.CalcMark.Validate:input_type -> calcmark.v1.ValidateRequest
+	0,  // 19: calcmark.v1.CalcMark.StreamResults:input_type -> calcmark.v1.EvaluateRequest
+	1,  // 20: calcmark.v1.CalcMark.Evaluate:output_type -> calcmark.v1.EvaluateResponse
+	3,  // 21: calcmark.v1.CalcMark.Validate:output_type -> calcmark.v1.ValidateResponse
+	4,  // 22: calcmark.v1.CalcMark.StreamResults:output_type -> calcmark.v1.StreamResultsResponse
+	20, // [20:23] is the sub-list for method output_type
+	17, // [17:20] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_proto_calcmark_v1_calcmark_proto_init() }
+func file_proto_calcmark_v1_calcmark_proto_init() {
+	if File_proto_calcmark_v1_calcmark_proto != nil {
+		return
+	}
+	file_proto_calcmark_v1_calcmark_proto_msgTypes[4].OneofWrappers = []any{
+		(*StreamResultsResponse_Block)(nil),
+		(*StreamResultsResponse_Summary)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_calcmark_v1_calcmark_proto_rawDesc), len(file_proto_calcmark_v1_calcmark_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_calcmark_v1_calcmark_proto_goTypes,
+		DependencyIndexes: file_proto_calcmark_v1_calcmark_proto_depIdxs,
+		MessageInfos:      file_proto_calcmark_v1_calcmark_proto_msgTypes,
+	}.Build()
+	File_proto_calcmark_v1_calcmark_proto = out.File
+	file_proto_calcmark_v1_calcmark_proto_goTypes = nil
+	file_proto_calcmark_v1_calcmark_proto_depIdxs = nil
+}
@@ -0,0 +1,223 @@
+// CalcMark evaluation as a gRPC service, for services embedding CalcMark.
+// Messages mirror the JSON export (cm convert --to=json) and the HTTP API
+// of cm serve, with the same field names.
+//
+// Regenerate the Go code with `task generate:proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/calcmark/v1/calcmark.proto
+
+package calcmarkv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CalcMark_Evaluate_FullMethodName      = "/calcmark.v1.CalcMark/Evaluate"
+	CalcMark_Validate_FullMethodName      = "/calcmark.v1.CalcMark/Validate"
+	CalcMark_StreamResults_FullMethodName = "/calcmark.v1.CalcMark/StreamResults"
+)
+
+// CalcMarkClient is the client API for CalcMark service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CalcMark evaluates and validates documents. Errors within blocks are
+// reported on the blocks; a document that can't be parsed or evaluated at
+// all fails the call with INVALID_ARGUMENT.
+type CalcMarkClient interface {
+	// Evaluate returns every block of a document with its results.
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+	// Validate returns a document's problems, as cm check reports them.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// StreamResults sends each block as soon as it is evaluated, then a
+	// summary, so clients can show a large document's results as they come.
+	StreamResults(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamResultsResponse], error)
+}
+
+type calcMarkClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalcMarkClient(cc grpc.ClientConnInterface) CalcMarkClient {
+	return &calcMarkClient{cc}
+}
+
+func (c *calcMarkClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, CalcMark_Evaluate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calcMarkClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, CalcMark_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calcMarkClient) StreamResults(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamResultsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalcMark_ServiceDesc.Streams[0], CalcMark_StreamResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EvaluateRequest, StreamResultsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalcMark_StreamResultsClient = grpc.ServerStreamingClient[StreamResultsResponse]
+
+// CalcMarkServer is the server API for CalcMark service.
+// All implementations must embed UnimplementedCalcMarkServer
+// for forward compatibility.
+//
+// CalcMark evaluates and validates documents. Errors within blocks are
+// reported on the blocks; a document that can't be parsed or evaluated at
+// all fails the call with INVALID_ARGUMENT.
+type CalcMarkServer interface {
+	// Evaluate returns every block of a document with its results.
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	// Validate returns a document's problems, as cm check reports them.
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// StreamResults sends each block as soon as it is evaluated, then a
+	// summary, so clients can show a large document's results as they come.
+	StreamResults(*EvaluateRequest, grpc.ServerStreamingServer[StreamResultsResponse]) error
+	mustEmbedUnimplementedCalcMarkServer()
+}
+
+// UnimplementedCalcMarkServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCalcMarkServer struct{}
+
+func (UnimplementedCalcMarkServer) Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (UnimplementedCalcMarkServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedCalcMarkServer) StreamResults(*EvaluateRequest, grpc.ServerStreamingServer[StreamResultsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamResults not implemented")
+}
+func (UnimplementedCalcMarkServer) mustEmbedUnimplementedCalcMarkServer() {}
+func (UnimplementedCalcMarkServer) testEmbeddedByValue()                  {}
+
+// UnsafeCalcMarkServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalcMarkServer will
+// result in compilation errors.
+type UnsafeCalcMarkServer interface {
+	mustEmbedUnimplementedCalcMarkServer()
+}
+
+func RegisterCalcMarkServer(s grpc.ServiceRegistrar, srv CalcMarkServer) {
+	// If the following call pancis, it indicates UnimplementedCalcMarkServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CalcMark_ServiceDesc, srv)
+}
+
+func _CalcMark_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalcMarkServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalcMark_Evaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalcMarkServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalcMark_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalcMarkServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalcMark_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalcMarkServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalcMark_StreamResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EvaluateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalcMarkServer).StreamResults(m, &grpc.GenericServerStream[EvaluateRequest, StreamResultsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalcMark_StreamResultsServer = grpc.ServerStreamingServer[StreamResultsResponse]
+
+// CalcMark_ServiceDesc is the grpc.ServiceDesc for CalcMark service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalcMark_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "calcmark.v1.CalcMark",
+	HandlerType: (*CalcMarkServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Evaluate",
+			Handler:    _CalcMark_Evaluate_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _CalcMark_Validate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamResults",
+			Handler:       _CalcMark_StreamResults_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/calcmark/v1/calcmark.proto",
+}
@@ -78,7 +78,11 @@ echo
 
 # 4. Build CLI tools
 echo -e "${BLUE}[4/6]${NC} Building CLI tools..."
-go build -o calcmark ./cmd/calcmark
+# Build the notebook engine into cm, so --to=html-interactive works as shipped
+mkdir -p format/notebook/engine
+GOOS=js GOARCH=wasm go build -ldflags "-s -w" -o format/notebook/engine/calcmark.wasm ./impl/wasm
+cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" format/notebook/engine/
+go build -tags notebook_engine -o calcmark ./cmd/calcmark
 
 # Verify version commands work
 CALCMARK_VERSION=$(./calcmark version)
@@ -22,6 +22,9 @@ type Diagnostic struct {
 	Severity Severity
 	Code     string
 	Message  string
+
+	// Suggestions lists likely intended fixes (e.g., "USD" for "USX"), closest first.
+	Suggestions []string
 }
 
 // Severity indicates the severity level of a diagnostic.
@@ -2,12 +2,14 @@ package calcmark
 
 import (
 	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 )
 
 // Session maintains state for live editor use.
 // Variables persist across Eval calls within the same session.
 type Session struct {
-	env *interpreter.Environment
+	env    *interpreter.Environment
+	locale lexer.NumberLocale
 }
 
 // NewSession creates a new stateful evaluation session.
@@ -21,14 +23,27 @@ type Session struct {
 //	fmt.Println(result.Value) // 15
 func NewSession() *Session {
 	return &Session{
-		env: interpreter.NewEnvironment(),
+		env:    interpreter.NewEnvironment(),
+		locale: lexer.LocaleUS,
 	}
 }
 
 // Eval evaluates an expression in this session's context.
 // Variables are preserved across calls.
 func (s *Session) Eval(input string) (*Result, error) {
-	return evaluate(input, s.env)
+	return evaluate(input, s.env, s.locale)
+}
+
+// SetLocale sets how number literals are written, e.g. "de-DE" for 1.000,50
+// or "fr-FR" for 1 000,50. The default is "en-US" (1,000.50).
+// A locale declared in the input's frontmatter takes precedence.
+func (s *Session) SetLocale(tag string) error {
+	loc, err := lexer.LookupLocale(tag)
+	if err != nil {
+		return err
+	}
+	s.locale = loc
+	return nil
 }
 
 // Reset clears all variables in this session.
@@ -3,6 +3,8 @@ package ast
 import (
 	"fmt"
 	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 )
 
 // Node is the interface that all AST nodes implement
@@ -42,6 +44,21 @@ func (c *CurrencyLiteral) GetRange() *Range {
 	return c.Range
 }
 
+// CurrencyUnit represents a bare currency code standing for one unit of
+// that currency, as in the basket "0.6 * USD + 0.4 * EUR".
+type CurrencyUnit struct {
+	Code  string // ISO code as written
+	Range *Range
+}
+
+func (c *CurrencyUnit) String() string {
+	return fmt.Sprintf("CurrencyUnit(%s)", c.Code)
+}
+
+func (c *CurrencyUnit) GetRange() *Range {
+	return c.Range
+}
+
 // QuantityLiteral represents a number with a unit (e.g., "5 kg", "10 meters").
 type QuantityLiteral struct {
 	Value      string // The numeric value
@@ -58,12 +75,48 @@ func (q *QuantityLiteral) GetRange() *Range {
 	return q.Range
 }
 
+// ListLiteral represents a bracketed list of values (e.g., "[10, 20, 30]").
+type ListLiteral struct {
+	Elements []Node
+	Range    *Range
+}
+
+func (l *ListLiteral) String() string {
+	return fmt.Sprintf("ListLiteral(%v)", l.Elements)
+}
+
+func (l *ListLiteral) GetRange() *Range {
+	return l.Range
+}
+
+// IndexExpr represents indexing a list or table (e.g., "prices[2]",
+// "schedule[12].balance"). Index counts from 1, or from the end when
+// negative; Field names a table column, or is "" for the whole row.
+type IndexExpr struct {
+	Target Node
+	Index  Node
+	Field  string
+	Range  *Range
+}
+
+func (i *IndexExpr) String() string {
+	if i.Field != "" {
+		return fmt.Sprintf("IndexExpr(%v[%v].%s)", i.Target, i.Index, i.Field)
+	}
+	return fmt.Sprintf("IndexExpr(%v[%v])", i.Target, i.Index)
+}
+
+func (i *IndexExpr) GetRange() *Range {
+	return i.Range
+}
+
 // UnitConversion represents explicit unit conversion (e.g., "10 meters in feet").
 // For rate conversions (e.g., "10 m/s in inch/s"), TargetTimeUnit is set.
 type UnitConversion struct {
 	Quantity       Node   // The quantity expression to convert
 	TargetUnit     string // The target unit to convert to
 	TargetTimeUnit string // For rate conversions: the target time unit (e.g., "s" in "inch/s")
+	TargetRange    *Range // Where the target unit is written, nil if unknown
 	Range          *Range
 }
 
@@ -151,6 +204,7 @@ type TimeLiteral struct {
 	Second     *string    // nil or "45"
 	Period     *string    // nil, "AM", or "PM"
 	UTCOffset  *UTCOffset // nil or offset spec
+	Zone       string     // IANA time zone name ("Asia/Tokyo"), "" if none
 	SourceText string
 	Range      *Range
 }
@@ -172,6 +226,10 @@ func (t *TimeLiteral) String() string {
 		parts = append(parts, t.UTCOffset.String())
 	}
 
+	if t.Zone != "" {
+		parts = append(parts, t.Zone)
+	}
+
 	return fmt.Sprintf("TimeLiteral(%s)", strings.Join(parts, " "))
 }
 
@@ -179,6 +237,23 @@ func (t *TimeLiteral) GetRange() *Range {
 	return t.Range
 }
 
+// TimeZoneConversion represents converting a time to another time zone:
+// "3pm EST in PST", "9:00 Tokyo in UTC"
+type TimeZoneConversion struct {
+	Time      Node
+	Zone      string // IANA time zone name ("America/Los_Angeles")
+	ZoneRange *Range // Position of the zone as written, for diagnostics
+	Range     *Range
+}
+
+func (t *TimeZoneConversion) String() string {
+	return fmt.Sprintf("TimeZoneConversion(%s in %s)", t.Time, t.Zone)
+}
+
+func (t *TimeZoneConversion) GetRange() *Range {
+	return t.Range
+}
+
 // UTCOffset represents a UTC timezone offset: UTC-7, UTC+5:30
 type UTCOffset struct {
 	Sign    string  // "+" or "-"
@@ -224,6 +299,39 @@ func (d *DurationLiteral) GetRange() *Range {
 	return d.Range
 }
 
+// ScheduleLiteral represents a recurring schedule: "every 2nd Friday",
+// "every 15th", "every month on the 1st from Jan 1 2026"
+type ScheduleLiteral struct {
+	Every      string // Interval ("2" for "every 2nd Friday" and "every other Friday")
+	Unit       string // Time unit ("week", "months", etc.), "" for weekday schedules
+	Weekday    string // Canonical weekday ("Friday"), "" if none
+	Day        string // Day of the month ("15"), "" if none
+	Start      Node   // Start date expression, nil if none
+	SourceText string
+	Range      *Range
+}
+
+func (s *ScheduleLiteral) String() string {
+	parts := []string{"every " + s.Every}
+	if s.Weekday != "" {
+		parts = append(parts, s.Weekday)
+	}
+	if s.Unit != "" {
+		parts = append(parts, s.Unit)
+	}
+	if s.Day != "" {
+		parts = append(parts, "on "+s.Day)
+	}
+	if s.Start != nil {
+		parts = append(parts, "from "+s.Start.String())
+	}
+	return fmt.Sprintf("ScheduleLiteral(%s)", strings.Join(parts, " "))
+}
+
+func (s *ScheduleLiteral) GetRange() *Range {
+	return s.Range
+}
+
 // BooleanLiteral represents a boolean literal
 type BooleanLiteral struct {
 	Value string // "true", "false", "yes", "no", etc.
@@ -238,12 +346,30 @@ func (b *BooleanLiteral) GetRange() *Range {
 	return b.Range
 }
 
-// Identifier represents a variable identifier
-type Identifier struct {
-	Name  string
+// StringLiteral represents quoted text, which only a function argument can
+// be, such as the file name in lookup("prices.csv", sku, "A-100", price)
+type StringLiteral struct {
+	Value string // Without the quotes
 	Range *Range
 }
 
+func (s *StringLiteral) String() string {
+	return fmt.Sprintf("StringLiteral(%q)", s.Value)
+}
+
+func (s *StringLiteral) GetRange() *Range {
+	return s.Range
+}
+
+// Identifier represents a variable identifier. Symbol is Name's symbol,
+// which the interpreter looks variables up by; the parser sets it, and it
+// is zero in identifiers built without one.
+type Identifier struct {
+	Name   string
+	Symbol lexer.Symbol
+	Range  *Range
+}
+
 func (i *Identifier) String() string {
 	return fmt.Sprintf("Identifier(%q)", i.Name)
 }
@@ -66,6 +66,7 @@ func containsFunctions(tokens []lexer.Token) bool {
 		lexer.FUNC_SQRT:           true,
 		lexer.FUNC_AVERAGE_OF:     true,
 		lexer.FUNC_SQUARE_ROOT_OF: true,
+		lexer.FUNC_STDEV_OF:       true,
 	}
 
 	for _, token := range tokens {
@@ -95,6 +96,30 @@ func containsSpecialKeywords(tokens []lexer.Token) bool {
 	return false
 }
 
+// containsPercentPhrase checks if token list contains the words that join
+// a percentage phrase: "15% off $200", "increase 100 by 10%"
+func containsPercentPhrase(tokens []lexer.Token) bool {
+	for _, token := range tokens {
+		if token.Type == lexer.IDENTIFIER && (strings.EqualFold(token.Value, "off") || strings.EqualFold(token.Value, "by")) {
+			return true
+		}
+	}
+	return false
+}
+
+// assignsPercentPhrase checks if token list assigns a percentage phrase
+// that starts with a word: "x = increase ...", "x = change from ..."
+func assignsPercentPhrase(tokens []lexer.Token) bool {
+	if len(tokens) < 4 || tokens[0].Type != lexer.IDENTIFIER || tokens[1].Type != lexer.ASSIGN || tokens[2].Type != lexer.IDENTIFIER {
+		return false
+	}
+	switch strings.ToLower(tokens[2].Value) {
+	case "increase", "decrease", "change":
+		return true
+	}
+	return false
+}
+
 // containsAssignment checks if token list contains an assignment operator
 func containsAssignment(tokens []lexer.Token) bool {
 	for _, token := range tokens {
@@ -207,6 +232,11 @@ func ClassifyLine(line string, env *interpreter.Environment) (LineType, error) {
 	if containsAssignment(contentTokens) {
 		nodes, err := parser.Parse(line)
 		if err != nil {
+			// "x = change from 80 to 100" is a mistaken calculation, not prose,
+			// so evaluating it reports the error
+			if assignsPercentPhrase(contentTokens) {
+				return Calculation, nil
+			}
 			return Markdown, nil
 		}
 
@@ -246,6 +276,15 @@ func ClassifyLine(line string, env *interpreter.Environment) (LineType, error) {
 		}
 	}
 
+	// 5c. Check for percentage phrases ("15% off $200", "increase 100 by 10%"),
+	// which read like prose unless they parse
+	if containsPercentPhrase(contentTokens) {
+		nodes, err := parser.Parse(line)
+		if err == nil && len(nodes) == 1 && allIdentifiersDefined(nodes[0], env) {
+			return Calculation, nil
+		}
+	}
+
 	// 6. Check for operators
 	if containsOperators(contentTokens) {
 		nodes, err := parser.Parse(line)
@@ -189,6 +189,31 @@ func TestComparisons(t *testing.T) {
 	}
 }
 
+// TestPercentPhrases tests percentage phrases are calculations, and prose
+// using their words is not
+func TestPercentPhrases(t *testing.T) {
+	for _, test := range []string{
+		"15% off $200",
+		"increase 100 by 10%",
+		"decrease $80 by 25%",
+		"change from 80 to 100 in %",
+		"growth = change from 80 to 100", // Mistaken, reported when evaluated
+		"price = increase $100 by 10",
+	} {
+		if classifyLineTest(t, test, nil) != Calculation {
+			t.Errorf("expected CALCULATION for %q", test)
+		}
+	}
+	for _, test := range []string{
+		"We will increase prices by 10% next year",
+		"Prices are 15% off today",
+	} {
+		if classifyLineTest(t, test, nil) != Markdown {
+			t.Errorf("expected MARKDOWN for %q", test)
+		}
+	}
+}
+
 // TestContextAwareness tests context-aware classification
 func TestKnownVariableReference(t *testing.T) {
 	ctx := interpreter.NewEnvironment()
@@ -0,0 +1,88 @@
+package classifier
+
+import (
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+// Classification is a line's type, with the error ClassifyLine reported
+// for it, if any.
+type Classification struct {
+	Type LineType
+	Err  error
+}
+
+// Incremental classifies a document's lines the way an editor does on
+// every keystroke: in order, each CALCULATION line evaluated so the lines
+// after it see the variables it defines. It memoizes each line's result
+// by its text and the environment's generation before it, so classifying
+// an edited document again only redoes the edited line and the lines
+// whose environment it changed. Editing text, or a calculation that
+// assigns no variable, costs one line rather than the whole document.
+//
+// An Incremental is not safe for concurrent use.
+type Incremental struct {
+	memo map[memoKey]memoEntry
+}
+
+// memoKey is a line and the generation of the environment it was
+// classified in.
+type memoKey struct {
+	line       string
+	generation uint64
+}
+
+// memoEntry is a line's classification and the environment after it,
+// which is never changed once stored.
+type memoEntry struct {
+	result Classification
+	after  *interpreter.Environment
+}
+
+// NewIncremental returns an Incremental with nothing memoized.
+func NewIncremental() *Incremental {
+	return &Incremental{memo: make(map[memoKey]memoEntry)}
+}
+
+// ClassifyLines classifies lines in order, starting from env's variables,
+// which it leaves unchanged. A nil env starts from a new environment.
+//
+// Results are kept for the lines of this call only, so alternating
+// between documents memoizes neither; use an Incremental per document.
+func (c *Incremental) ClassifyLines(lines []string, env *interpreter.Environment) []Classification {
+	if env == nil {
+		env = interpreter.NewEnvironment()
+	}
+	results := make([]Classification, len(lines))
+	memo := make(map[memoKey]memoEntry, len(lines))
+
+	for i, line := range lines {
+		key := memoKey{line, env.Generation()}
+		entry, ok := c.memo[key]
+		if !ok {
+			entry = classifyInto(line, env)
+		}
+		memo[key] = entry
+		results[i] = entry.result
+		env = entry.after
+	}
+
+	c.memo = memo
+	return results
+}
+
+// classifyInto classifies line in env, evaluating a calculation in a
+// clone of env to define its variables for the lines after it.
+func classifyInto(line string, env *interpreter.Environment) memoEntry {
+	lineType, err := ClassifyLine(line, env)
+	entry := memoEntry{result: Classification{Type: lineType, Err: err}, after: env}
+	if lineType == Calculation {
+		after := env.Clone()
+		// Evaluation errors don't fail classification; the line just
+		// defines nothing
+		_ = interpreter.Evaluate(line, after)
+		if after.Generation() != env.Generation() {
+			entry.after = after
+		}
+	}
+	return entry
+}
@@ -0,0 +1,90 @@
+package classifier
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func lineTypes(results []Classification) []LineType {
+	got := make([]LineType, len(results))
+	for i, r := range results {
+		got[i] = r.Type
+	}
+	return got
+}
+
+func TestIncrementalClassifyLines(t *testing.T) {
+	lines := []string{"# Budget", "x = 5", "x", "y", "", "total = x * 2", "total + y"}
+	want := []LineType{Markdown, Calculation, Calculation, Markdown, Blank, Calculation, Markdown}
+
+	c := NewIncremental()
+	if got := lineTypes(c.ClassifyLines(lines, nil)); !slices.Equal(got, want) {
+		t.Fatalf("first pass = %v, want %v", got, want)
+	}
+	// Memoized results are the same
+	if got := lineTypes(c.ClassifyLines(lines, nil)); !slices.Equal(got, want) {
+		t.Errorf("second pass = %v, want %v", got, want)
+	}
+
+	// Defining y above its use changes the lines after it
+	edited := slices.Clone(lines)
+	edited[0] = "y = 1"
+	want = []LineType{Calculation, Calculation, Calculation, Calculation, Blank, Calculation, Calculation}
+	if got := lineTypes(c.ClassifyLines(edited, nil)); !slices.Equal(got, want) {
+		t.Errorf("after defining y = %v, want %v", got, want)
+	}
+}
+
+func TestIncrementalReusesLinesAfterTextEdits(t *testing.T) {
+	lines := []string{"x = 5", "Some notes", "y = x + 1", "y"}
+	c := NewIncremental()
+	c.ClassifyLines(lines, nil)
+	before := c.memo[memoKey{"y", envBefore(c, "y")}].after
+
+	// Editing the text line leaves the environment after it unchanged, so
+	// the lines below it aren't evaluated again
+	edited := slices.Clone(lines)
+	edited[1] = "Some more notes"
+	c.ClassifyLines(edited, nil)
+	if after := c.memo[memoKey{"y", envBefore(c, "y")}].after; after != before {
+		t.Error("line after the text edit was classified again")
+	}
+	if len(c.memo) != len(lines) {
+		t.Errorf("memo holds %d lines, want %d", len(c.memo), len(lines))
+	}
+}
+
+// envBefore returns the generation the memoized line was classified in.
+func envBefore(c *Incremental, line string) uint64 {
+	for key := range c.memo {
+		if key.line == line {
+			return key.generation
+		}
+	}
+	return 0
+}
+
+func TestIncrementalFollowsEnvironmentChanges(t *testing.T) {
+	env := interpreter.NewEnvironment()
+	c := NewIncremental()
+	lines := []string{"price * 2"}
+
+	if got := c.ClassifyLines(lines, env)[0].Type; got != Markdown {
+		t.Errorf("undefined price = %v, want MARKDOWN", got)
+	}
+	env.Set("price", types.NewNumber(decimal.NewFromInt(10)))
+	if got := c.ClassifyLines(lines, env)[0].Type; got != Calculation {
+		t.Errorf("defined price = %v, want CALCULATION", got)
+	}
+
+	// Classifying leaves the given environment unchanged
+	generation := env.Generation()
+	c.ClassifyLines([]string{"z = 3"}, env)
+	if env.Has("z") || env.Generation() != generation {
+		t.Error("ClassifyLines changed the environment")
+	}
+}
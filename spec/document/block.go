@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/types"
 )
 
@@ -24,6 +25,8 @@ const (
 	BlockCalculation BlockType = iota
 	// BlockText represents markdown text.
 	BlockText
+	// BlockCustom represents a block made by a registered BlockFactory.
+	BlockCustom
 )
 
 func (bt BlockType) String() string {
@@ -32,6 +35,8 @@ func (bt BlockType) String() string {
 		return "Calculation"
 	case BlockText:
 		return "Text"
+	case BlockCustom:
+		return "Custom"
 	default:
 		return "Unknown"
 	}
@@ -50,20 +55,26 @@ type Block interface {
 
 	// SetDirty marks the block as needing update.
 	SetDirty(bool)
+
+	// Metadata returns the block's annotation metadata, or nil if unannotated.
+	Metadata() *BlockMetadata
 }
 
 // CalcBlock represents one or more consecutive calculation lines.
 // Like a Jupyter code cell.
 type CalcBlock struct {
-	source       []string     // Raw source lines
-	statements   []ast.Node   // Parsed AST nodes (one per line)
-	lastValue    types.Type   // Value of last statement
-	results      []types.Type // All statement results (for inline display)
-	variables    []string     // Variables defined in this block
-	dependencies []string     // Variables referenced from other blocks
-	err          error        // Evaluation error (legacy, prefer diagnostics)
-	diagnostics  []Diagnostic // Structured errors with position info
+	source       []string      // Raw source lines
+	statements   []ast.Node    // Parsed AST nodes (one per line)
+	tokens       []lexer.Token // Tokens of source, kept for incremental reparsing
+	lastValue    types.Type    // Value of last statement
+	results      []types.Type  // All statement results (for inline display)
+	variables    []string      // Variables defined in this block
+	dependencies []string      // Variables referenced from other blocks
+	err          error         // Evaluation error (legacy, prefer diagnostics)
+	diagnostics  []Diagnostic  // Structured errors with position info
+	metadata     *BlockMetadata
 	dirty        bool
+	stale        bool // Evaluation ran out of time; results are from an earlier run
 }
 
 // NewCalcBlock creates a new calculation block.
@@ -93,6 +104,22 @@ func (cb *CalcBlock) SetDirty(dirty bool) {
 	cb.dirty = dirty
 }
 
+// Metadata returns the block's annotation metadata, or nil if unannotated.
+func (cb *CalcBlock) Metadata() *BlockMetadata {
+	return cb.metadata
+}
+
+// IsStale returns true if the last evaluation ran out of time, so the
+// statements and results are left over from an earlier evaluation.
+func (cb *CalcBlock) IsStale() bool {
+	return cb.stale
+}
+
+// SetStale marks whether the block's results are out of date.
+func (cb *CalcBlock) SetStale(stale bool) {
+	cb.stale = stale
+}
+
 // LastValue returns the value of the last statement in the block.
 func (cb *CalcBlock) LastValue() types.Type {
 	return cb.lastValue
@@ -176,9 +203,10 @@ func (cb *CalcBlock) ClearDiagnostics() {
 // TextBlock represents markdown text.
 // Like a Jupyter markdown cell.
 type TextBlock struct {
-	source []string // Raw source lines
-	html   string   // Rendered HTML
-	dirty  bool
+	source   []string // Raw source lines
+	html     string   // Rendered HTML
+	metadata *BlockMetadata
+	dirty    bool
 }
 
 // NewTextBlock creates a new text block.
@@ -205,6 +233,11 @@ func (tb *TextBlock) SetDirty(dirty bool) {
 	tb.dirty = dirty
 }
 
+// Metadata returns the block's annotation metadata, or nil if unannotated.
+func (tb *TextBlock) Metadata() *BlockMetadata {
+	return tb.metadata
+}
+
 // HTML returns the rendered HTML.
 func (tb *TextBlock) HTML() string {
 	return tb.html
@@ -0,0 +1,153 @@
+package document
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+// BlockFactory adds a kind of block to documents detected with the
+// Detector it is registered with, such as a chart drawn from the
+// document's variables or a query against a data source:
+//
+//	detector := document.NewDetector()
+//	detector.RegisterBlock(chartFactory{})
+//	doc, err := document.NewDocumentWithDetector(source, detector)
+//
+// Factories are asked before the built-in detection, in the order
+// registered, at each line that could start a block.
+type BlockFactory interface {
+	// Kind names the blocks the factory makes, e.g. "chart".
+	Kind() string
+
+	// Detect returns how many lines the block starting at lines[0] takes,
+	// or 0 if lines[0] doesn't start one. lines runs to the end of the
+	// document, so a block can end at a closing fence or anywhere else.
+	Detect(lines []string) int
+
+	// Parse makes a block from the lines Detect claimed. An error fails
+	// loading the document, naming the block's line.
+	Parse(lines []string) (CustomBlock, error)
+}
+
+// CustomBlock is a block made by a BlockFactory. Its Type is BlockCustom,
+// and its Source is what the document serializes, so it should return
+// the lines it was parsed from unless it was changed. Embedding
+// CustomBlockBase implements the Block methods.
+type CustomBlock interface {
+	Block
+
+	// Kind is the Kind of the factory that made the block.
+	Kind() string
+}
+
+// EvaluableBlock is a custom block that takes part in evaluation: it is
+// evaluated in document order with the calculation blocks, reading the
+// variables defined above it and defining its own for those below, and
+// it is re-evaluated when the variables it depends on change.
+type EvaluableBlock interface {
+	CustomBlock
+
+	// Dependencies returns the variables the block reads.
+	Dependencies() []string
+
+	// Variables returns the variables the block defines.
+	Variables() []string
+
+	// Evaluate computes the block from env, setting the variables it
+	// defines in it. An error stops evaluation as a failed calculation does.
+	Evaluate(env *interpreter.Environment) error
+}
+
+// CustomBlockBase implements the Block methods for a custom block that
+// embeds it, keeping its source and the metadata annotating it.
+type CustomBlockBase struct {
+	source   []string
+	metadata *BlockMetadata
+	dirty    bool
+}
+
+// NewCustomBlockBase returns the base of a custom block parsed from source.
+func NewCustomBlockBase(source []string) CustomBlockBase {
+	return CustomBlockBase{source: source, dirty: true}
+}
+
+// Type returns BlockCustom.
+func (b *CustomBlockBase) Type() BlockType {
+	return BlockCustom
+}
+
+// Source returns the lines the block was parsed from.
+func (b *CustomBlockBase) Source() []string {
+	return b.source
+}
+
+func (b *CustomBlockBase) IsDirty() bool {
+	return b.dirty
+}
+
+func (b *CustomBlockBase) SetDirty(dirty bool) {
+	b.dirty = dirty
+}
+
+// Metadata returns the block's annotation metadata, or nil if unannotated.
+func (b *CustomBlockBase) Metadata() *BlockMetadata {
+	return b.metadata
+}
+
+func (b *CustomBlockBase) setMetadata(meta *BlockMetadata) {
+	b.metadata = meta
+}
+
+// RegisterBlock adds the kind of block factory makes to the documents d
+// detects. Kinds must be unique and non-empty.
+func (d *Detector) RegisterBlock(factory BlockFactory) error {
+	kind := factory.Kind()
+	if strings.TrimSpace(kind) == "" {
+		return fmt.Errorf("block factory has no kind")
+	}
+	if d.factory(kind) != nil {
+		return fmt.Errorf("block kind '%s' is already registered", kind)
+	}
+	d.factories = append(d.factories, factory)
+	return nil
+}
+
+// factory returns the factory registered for kind, or nil.
+func (d *Detector) factory(kind string) BlockFactory {
+	i := slices.IndexFunc(d.factories, func(f BlockFactory) bool { return f.Kind() == kind })
+	if i < 0 {
+		return nil
+	}
+	return d.factories[i]
+}
+
+// detectCustom returns the block a registered factory makes from the
+// lines starting at lines[0] and how many lines it takes, or 0 if none
+// claims them. line is lines[0]'s 1-indexed number, for errors.
+func (d *Detector) detectCustom(lines []string, line int) (CustomBlock, int, error) {
+	for _, f := range d.factories {
+		n := min(f.Detect(lines), len(lines))
+		if n <= 0 {
+			continue
+		}
+		block, err := f.Parse(slices.Clone(lines[:n]))
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s block at line %d: %w", f.Kind(), line, err)
+		}
+		return block, n, nil
+	}
+	return nil, 0, nil
+}
+
+// reparseCustom makes a block of kind from new source, for edits to a
+// custom block.
+func (d *Detector) reparseCustom(kind string, source []string) (CustomBlock, error) {
+	f := d.factory(kind)
+	if f == nil {
+		return nil, fmt.Errorf("block kind '%s' is not registered", kind)
+	}
+	return f.Parse(source)
+}
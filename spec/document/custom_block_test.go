@@ -0,0 +1,156 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+// aliasFactory makes fenced blocks defining variables as copies of others:
+//
+//	```alias
+//	total: subtotal
+//	```
+type aliasFactory struct{}
+
+func (aliasFactory) Kind() string { return "alias" }
+
+func (aliasFactory) Detect(lines []string) int {
+	if strings.TrimSpace(lines[0]) != "```alias" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "```" {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (aliasFactory) Parse(lines []string) (CustomBlock, error) {
+	block := &aliasBlock{CustomBlockBase: NewCustomBlockBase(lines)}
+	for _, line := range lines[1 : len(lines)-1] {
+		name, target, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected 'name: variable', got '%s'", line)
+		}
+		block.names = append(block.names, strings.TrimSpace(name))
+		block.targets = append(block.targets, strings.TrimSpace(target))
+	}
+	return block, nil
+}
+
+type aliasBlock struct {
+	CustomBlockBase
+	names, targets []string
+}
+
+func (b *aliasBlock) Kind() string           { return "alias" }
+func (b *aliasBlock) Variables() []string    { return b.names }
+func (b *aliasBlock) Dependencies() []string { return b.targets }
+
+func (b *aliasBlock) Evaluate(env *interpreter.Environment) error {
+	for i, target := range b.targets {
+		value, ok := env.Get(target)
+		if !ok {
+			return fmt.Errorf("undefined variable '%s'", target)
+		}
+		env.Set(b.names[i], value)
+	}
+	return nil
+}
+
+func newAliasDocument(t *testing.T, source string) *Document {
+	t.Helper()
+	detector := NewDetector()
+	if err := detector.RegisterBlock(aliasFactory{}); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := NewDocumentWithDetector(source, detector)
+	if err != nil {
+		t.Fatalf("NewDocumentWithDetector: %v", err)
+	}
+	return doc
+}
+
+func TestCustomBlockDetection(t *testing.T) {
+	source := "# Costs\n\nsubtotal = 40\n```alias\ntotal: subtotal\n```\n\nresult = total * 2\n\n"
+	doc := newAliasDocument(t, source)
+
+	var types []string
+	for _, node := range doc.GetBlocks() {
+		types = append(types, node.Block.Type().String())
+	}
+	if got, want := strings.Join(types, ","), "Text,Calculation,Custom,Calculation"; got != want {
+		t.Fatalf("block types = %s, want %s", got, want)
+	}
+	alias := doc.GetBlocks()[2].Block.(*aliasBlock)
+	if got := strings.Join(alias.Source(), "\n"); got != "```alias\ntotal: subtotal\n```" {
+		t.Errorf("alias source = %q", got)
+	}
+	if got := doc.Serialize(); got != source {
+		t.Errorf("Serialize() = %q, want %q", got, source)
+	}
+
+	// Without the factory, the fence is text
+	plain, err := NewDocument(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range plain.GetBlocks() {
+		if node.Block.Type() == BlockCustom {
+			t.Errorf("default detector made a custom block")
+		}
+	}
+}
+
+func TestCustomBlockEvaluation(t *testing.T) {
+	doc := newAliasDocument(t, "subtotal = 40\n\n\n```alias\ntotal: subtotal\n```\n\n\nresult = total * 2")
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	blocks := doc.GetBlocks()
+	result := blocks[len(blocks)-1].Block.(*CalcBlock)
+	if got := result.LastValue().String(); got != "80" {
+		t.Errorf("result = %s, want 80", got)
+	}
+
+	// The alias depends on subtotal, and result on the alias
+	dependents := doc.GetTransitiveDependents([]string{"subtotal"})
+	if len(dependents) != 2 {
+		t.Errorf("dependents of subtotal = %d blocks, want the alias and result", len(dependents))
+	}
+
+	// Editing the alias reparses it through its factory
+	aliasID := blocks[1].ID
+	update, err := doc.ReplaceBlockSource(aliasID, []string{"```alias", "total: result_base", "```"})
+	if err != nil {
+		t.Fatalf("ReplaceBlockSource: %v", err)
+	}
+	if len(update.AffectedBlockIDs) != 2 {
+		t.Errorf("affected = %v, want the alias and result", update.AffectedBlockIDs)
+	}
+	if deps := doc.GetTransitiveDependents([]string{"subtotal"}); len(deps) != 0 {
+		t.Errorf("dependents of subtotal after edit = %v, want none", deps)
+	}
+	if err := doc.Evaluate(); err == nil || !strings.Contains(err.Error(), "alias block: undefined variable 'result_base'") {
+		t.Errorf("Evaluate error = %v", err)
+	}
+}
+
+func TestCustomBlockErrors(t *testing.T) {
+	detector := NewDetector()
+	if err := detector.RegisterBlock(aliasFactory{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := detector.RegisterBlock(aliasFactory{}); err == nil {
+		t.Error("registering a kind twice succeeded")
+	}
+
+	_, err := NewDocumentWithDetector("x = 1\n```alias\nno colon\n```", detector)
+	if err == nil || !strings.Contains(err.Error(), "alias block at line 2: expected 'name: variable'") {
+		t.Errorf("parse error = %v", err)
+	}
+}
@@ -2,17 +2,29 @@ package document
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
 )
 
 // DependencyAnalyzer extracts variable dependencies from CalcBlocks.
-type DependencyAnalyzer struct{}
+type DependencyAnalyzer struct {
+	locale lexer.NumberLocale
+}
 
 // NewDependencyAnalyzer creates a new dependency analyzer.
 func NewDependencyAnalyzer() *DependencyAnalyzer {
-	return &DependencyAnalyzer{}
+	return NewDependencyAnalyzerWithLocale(lexer.LocaleUS)
+}
+
+// NewDependencyAnalyzerWithLocale creates a dependency analyzer for blocks
+// whose number literals are written in loc's format.
+func NewDependencyAnalyzerWithLocale(loc lexer.NumberLocale) *DependencyAnalyzer {
+	return &DependencyAnalyzer{locale: loc}
 }
 
 // AnalyzeBlock parses a CalcBlock and extracts:
@@ -23,14 +35,8 @@ func (da *DependencyAnalyzer) AnalyzeBlock(block *CalcBlock) error {
 		return fmt.Errorf("nil block")
 	}
 
-	// Join source lines for parsing
-	source := ""
-	for _, line := range block.source {
-		source += line + "\n"
-	}
-
 	// Parse the source
-	nodes, err := parser.Parse(source)
+	nodes, err := parser.ParseWithLocale(blockSource(block), da.locale)
 	if err != nil {
 		block.SetError(err)
 		return err
@@ -38,7 +44,54 @@ func (da *DependencyAnalyzer) AnalyzeBlock(block *CalcBlock) error {
 
 	// Store parsed statements
 	block.SetStatements(nodes)
+	analyzeStatements(block, nodes)
+	return nil
+}
+
+// AnalyzeLine updates a block's statements, variables, and dependencies
+// after its line (0-indexed within the block) was edited in place. Only
+// that line is re-lexed and re-parsed; the block's other statements and
+// tokens are reused. The first call for a block, or any call the
+// incremental path cannot handle (such as a syntax error), analyzes the
+// whole block as AnalyzeBlock does, so the result is always the same.
+func (da *DependencyAnalyzer) AnalyzeLine(block *CalcBlock, line int) error {
+	if block == nil {
+		return fmt.Errorf("nil block")
+	}
+	if line < 0 || line >= len(block.source) {
+		return fmt.Errorf("line %d out of range", line)
+	}
 
+	if block.tokens != nil {
+		stmts, tokens, err := parser.ReparseStatement(block.statements, block.tokens, line+1, block.source[line], da.locale)
+		if err == nil {
+			block.tokens = tokens
+			block.SetStatements(stmts)
+			analyzeStatements(block, stmts)
+			return nil
+		}
+	}
+
+	block.tokens = nil
+	if err := da.AnalyzeBlock(block); err != nil {
+		return err
+	}
+	// Keep the tokens so the next edit to this block is incremental
+	tokens, err := lexer.NewLexerWithLocale(blockSource(block), da.locale).Tokenize()
+	if err == nil {
+		block.tokens = tokens
+	}
+	return nil
+}
+
+// blockSource joins a block's lines for parsing.
+func blockSource(block *CalcBlock) string {
+	return strings.Join(block.source, "\n") + "\n"
+}
+
+// analyzeStatements sets the variables a block's statements define and
+// the variables they reference from other blocks.
+func analyzeStatements(block *CalcBlock, nodes []ast.Node) {
 	// Extract defined and referenced variables.
 	// Use a map to track defined variables (deduplicates reassignments).
 	definedSet := make(map[string]bool)
@@ -78,11 +131,10 @@ func (da *DependencyAnalyzer) AnalyzeBlock(block *CalcBlock) error {
 			dependencies = append(dependencies, varName)
 		}
 	}
+	slices.Sort(dependencies) // Stable order for output
 
 	block.SetVariables(definedOrder)
 	block.SetDependencies(dependencies)
-
-	return nil
 }
 
 // extractIdentifiers recursively finds all identifier references in an AST.
@@ -122,6 +174,22 @@ func extractIdentifiers(node ast.Node, identifiers map[string]bool) {
 		for _, arg := range n.Arguments {
 			extractIdentifiers(arg, identifiers)
 		}
+		// "next payday" may refer to paydays
+		if id, ok := scheduleQueryName(n); ok {
+			for _, name := range types.ScheduleNames(id) {
+				identifiers[name] = true
+			}
+		}
+
+	case *ast.IndexExpr:
+		extractIdentifiers(n.Target, identifiers)
+		extractIdentifiers(n.Index, identifiers)
+
+	case *ast.ScheduleLiteral:
+		extractIdentifiers(n.Start, identifiers)
+
+	case *ast.TimeZoneConversion:
+		extractIdentifiers(n.Time, identifiers)
 
 	// Literals don't have identifiers
 	case *ast.NumberLiteral,
@@ -130,10 +198,25 @@ func extractIdentifiers(node ast.Node, identifiers map[string]bool) {
 		*ast.DateLiteral,
 		*ast.TimeLiteral,
 		*ast.DurationLiteral,
-		*ast.QuantityLiteral:
+		*ast.QuantityLiteral,
+		*ast.CurrencyUnit:
 		// No identifiers in literals
 
 	default:
 		// Unknown node type - skip
 	}
 }
+
+// scheduleQueryName returns the name of the schedule a next, count, or
+// occurrences query asks about, if it names one.
+func scheduleQueryName(f *ast.FunctionCall) (string, bool) {
+	switch f.Name {
+	case "next", "count", "occurrences":
+		if len(f.Arguments) > 0 {
+			if id, ok := f.Arguments[0].(*ast.Identifier); ok {
+				return id.Name, true
+			}
+		}
+	}
+	return "", false
+}
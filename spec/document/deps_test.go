@@ -121,3 +121,17 @@ func TestVariableReassignmentNoDuplicates(t *testing.T) {
 		})
 	}
 }
+
+// TestScheduleQueryDependencies verifies that "next payday" depends on a
+// paydays schedule, and a schedule on the variables of its start date.
+func TestScheduleQueryDependencies(t *testing.T) {
+	block := NewCalcBlock([]string{"d = next payday after start", "s = every week from launch"})
+	if err := NewDependencyAnalyzer().AnalyzeBlock(block); err != nil {
+		t.Fatalf("AnalyzeBlock failed: %v", err)
+	}
+	for _, name := range []string{"payday", "paydays", "start", "launch"} {
+		if !slices.Contains(block.Dependencies(), name) {
+			t.Errorf("Dependencies() = %v, want %s", block.Dependencies(), name)
+		}
+	}
+}
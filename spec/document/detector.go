@@ -1,25 +1,110 @@
 package document
 
 import (
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/parser"
 )
 
+// LineClassifier decides whether a line of a document is a calculation.
+// A Detector uses its classifier to type each non-empty line, then
+// assembles consecutive lines of the same type into blocks.
+//
+// *Detector is itself a LineClassifier using the built-in heuristics, so a
+// custom classifier can handle its own cases and defer to one for the rest.
+type LineClassifier interface {
+	IsCalculation(line string) (bool, error)
+}
+
+// DetectorRule forces lines matching Pattern to be of Type, overriding the
+// classifier. Patterns are matched against the line without surrounding
+// whitespace.
+type DetectorRule struct {
+	Pattern *regexp.Regexp
+	Type    BlockType
+}
+
 // Detector analyzes source text and splits it into blocks.
+//
+// The heuristics can be customized for domain-specific prose: rules
+// (AddRule) are checked first, in the order added, then text keywords
+// (AddTextKeywords), then the classifier (SetClassifier), which defaults
+// to the built-in heuristics.
 type Detector struct {
+	locale     lexer.NumberLocale
+	rules      []DetectorRule
+	keywords   map[string][]string // Dialect -> lowercased keywords starting prose
+	classifier LineClassifier      // nil for the built-in heuristics
+	factories  []BlockFactory      // Custom block kinds, asked first
 }
 
 // NewDetector creates a new block detector.
 func NewDetector() *Detector {
-	return &Detector{}
+	return NewDetectorWithLocale(lexer.LocaleUS)
+}
+
+// NewDetectorWithLocale creates a block detector that recognizes number
+// literals written in loc's format, so "1.000,50" is a calculation under de-DE.
+func NewDetectorWithLocale(loc lexer.NumberLocale) *Detector {
+	return &Detector{locale: loc}
+}
+
+// WithLocale returns a copy of d with the same customizations that
+// recognizes number literals in loc's format and applies loc's dialect's
+// keywords. Documents use it to detect blocks in their frontmatter's locale.
+func (d *Detector) WithLocale(loc lexer.NumberLocale) *Detector {
+	c := *d
+	c.locale = loc
+	c.rules = slices.Clip(d.rules) // Rules added to either don't reach the other
+	c.factories = slices.Clip(d.factories)
+	return &c
+}
+
+// AddRule makes lines matching pattern be detected as blockType, whatever
+// the classifier says, e.g. to keep "Q3 - Q2 results" as text. Rules are
+// checked in the order added; the first match wins.
+func (d *Detector) AddRule(pattern *regexp.Regexp, blockType BlockType) {
+	d.rules = append(d.rules, DetectorRule{Pattern: pattern, Type: blockType})
+}
+
+// AddTextKeywords makes lines starting with any of words (as whole words,
+// ignoring case) text, e.g. "Note" or "Step". dialect restricts the words
+// to documents in a locale: a language such as "de" matches every de-*
+// locale, and a full tag such as "de-CH" matches only that one. The empty
+// dialect applies to all documents.
+func (d *Detector) AddTextKeywords(dialect string, words ...string) {
+	dialect = normalizeDialect(dialect)
+	keywords := maps.Clone(d.keywords) // Copies made by WithLocale keep theirs
+	if keywords == nil {
+		keywords = make(map[string][]string)
+	}
+	list := slices.Clone(keywords[dialect])
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			list = append(list, w)
+		}
+	}
+	keywords[dialect] = list
+	d.keywords = keywords
+}
+
+// SetClassifier replaces the built-in heuristics with c; nil restores
+// them. Rules and text keywords still apply first. c must not be d itself,
+// but may be another Detector, e.g. one made by NewDetectorWithLocale.
+func (d *Detector) SetClassifier(c LineClassifier) {
+	d.classifier = c
 }
 
 // DetectBlocks splits source into blocks using these rules:
 // - 2 consecutive empty lines = block boundary
 // - 1 empty line = part of current block
 // - Calculations vs text determined by parsing each line
+// - Lines a registered BlockFactory claims form a block of its kind
 //
 // Unicode-aware: handles all line terminators (LF, CRLF, CR, U+2028, U+2029).
 func (d *Detector) DetectBlocks(source string) ([]Block, error) {
@@ -31,7 +116,8 @@ func (d *Detector) DetectBlocks(source string) ([]Block, error) {
 	emptyLineCount := 0
 	var pendingEmpties []string // Track trailing empties for TUI line preservation
 
-	for _, line := range lines {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
 		isEmpty := isEmptyLine(line) // Unicode-aware empty check
 
 		if isEmpty {
@@ -39,11 +125,14 @@ func (d *Detector) DetectBlocks(source string) ([]Block, error) {
 
 			// 2 consecutive empty lines = block boundary
 			if emptyLineCount >= 2 {
-				// Flush current block (if not empty)
+				// Flush current block (if not empty); empty lines carry over
+				// in order so no line is lost or moved
 				if len(currentBlockLines) > 0 && !allEmpty(currentBlockLines) {
 					blocks = append(blocks, d.createBlock(currentBlockType, currentBlockLines))
-					currentBlockLines = []string{}
+				} else {
+					pendingEmpties = append(pendingEmpties, currentBlockLines...)
 				}
+				currentBlockLines = []string{}
 
 				// Reset for next block
 				emptyLineCount = 0
@@ -61,18 +150,39 @@ func (d *Detector) DetectBlocks(source string) ([]Block, error) {
 			emptyLineCount = 0
 
 			// Append pending empties to the last block (if any) to preserve line count
-			// These are the "extra" empty lines beyond the first block-separator empty
-			if len(pendingEmpties) > 0 && len(blocks) > 0 {
-				lastBlock := blocks[len(blocks)-1]
-				switch b := lastBlock.(type) {
-				case *CalcBlock:
-					b.source = append(b.source, pendingEmpties...)
-				case *TextBlock:
-					b.source = append(b.source, pendingEmpties...)
-				}
+			// These are the "extra" empty lines beyond the first block-separator empty.
+			// Before the first block they lead the current one.
+			// Custom blocks keep their own source, so the empties lead the
+			// current block instead.
+			if len(pendingEmpties) > 0 && (len(blocks) == 0 || !appendLines(blocks[len(blocks)-1], pendingEmpties)) {
+				currentBlockLines = append(pendingEmpties, currentBlockLines...)
 			}
 			pendingEmpties = nil
 
+			// A "# = ..." result comment right after a calculation is part
+			// of its block; the lexer skips it
+			if currentBlockType == BlockCalculation && len(currentBlockLines) > 0 &&
+				!isEmptyLine(currentBlockLines[len(currentBlockLines)-1]) && isResultComment(line) {
+				currentBlockLines = append(currentBlockLines, line)
+				continue
+			}
+
+			custom, n, err := d.detectCustom(lines[i:], i+1)
+			if err != nil {
+				return nil, err
+			}
+			if n > 0 {
+				if len(currentBlockLines) > 0 && !allEmpty(currentBlockLines) {
+					blocks = append(blocks, d.createBlock(currentBlockType, currentBlockLines))
+				} else if len(currentBlockLines) > 0 {
+					blocks = append(blocks, NewTextBlock(currentBlockLines))
+				}
+				blocks = append(blocks, custom)
+				currentBlockLines = []string{}
+				i += n - 1
+				continue
+			}
+
 			// Determine if this line is a calculation
 			isCalc, err := d.IsCalculation(line)
 			if err != nil {
@@ -80,8 +190,9 @@ func (d *Detector) DetectBlocks(source string) ([]Block, error) {
 				return nil, err
 			}
 
-			// If first line of new block, set type
-			if len(currentBlockLines) == 0 {
+			// If first line of new block, set type; empty lines after a
+			// custom block lead the next one
+			if len(currentBlockLines) == 0 || (allEmpty(currentBlockLines) && endsWithCustom(blocks)) {
 				currentBlockType = BlockText
 				if isCalc {
 					currentBlockType = BlockCalculation
@@ -117,17 +228,9 @@ func (d *Detector) DetectBlocks(source string) ([]Block, error) {
 	// Handle trailing empty lines (pendingEmpties) - these are empties at end of document
 	// that need to be preserved for TUI line tracking
 	if len(pendingEmpties) > 0 {
-		if len(blocks) > 0 {
-			// Append to last block to preserve line count
-			lastBlock := blocks[len(blocks)-1]
-			switch b := lastBlock.(type) {
-			case *CalcBlock:
-				b.source = append(b.source, pendingEmpties...)
-			case *TextBlock:
-				b.source = append(b.source, pendingEmpties...)
-			}
-		} else {
-			// No previous block - create a text block for the empty lines
+		// Append to last block to preserve line count; with no previous
+		// block, or a custom one, create a text block for the empty lines
+		if len(blocks) == 0 || !appendLines(blocks[len(blocks)-1], pendingEmpties) {
 			blocks = append(blocks, NewTextBlock(pendingEmpties))
 		}
 	}
@@ -135,6 +238,29 @@ func (d *Detector) DetectBlocks(source string) ([]Block, error) {
 	return blocks, nil
 }
 
+// appendLines appends lines to a calculation or text block's source,
+// reporting whether it could.
+func appendLines(block Block, lines []string) bool {
+	switch b := block.(type) {
+	case *CalcBlock:
+		b.source = append(b.source, lines...)
+	case *TextBlock:
+		b.source = append(b.source, lines...)
+	default:
+		return false
+	}
+	return true
+}
+
+// endsWithCustom reports whether the last block is a custom block.
+func endsWithCustom(blocks []Block) bool {
+	if len(blocks) == 0 {
+		return false
+	}
+	_, ok := blocks[len(blocks)-1].(CustomBlock)
+	return ok
+}
+
 // allEmpty checks if all lines in a slice are empty.
 func allEmpty(lines []string) bool {
 	for _, line := range lines {
@@ -147,7 +273,8 @@ func allEmpty(lines []string) bool {
 
 // IsCalculation checks if a line is a valid calculation.
 // The approach: if a line parses successfully as a calculation, it's a calculation.
-// If it fails to parse, it's text (markdown).
+// If it fails to parse, it's text (markdown). Rules, text keywords, and a
+// custom classifier, if any, take precedence (see Detector).
 //
 // Returns (true, nil) for valid calculation lines.
 // Returns (false, nil) for text lines (including invalid syntax - treated as markdown).
@@ -161,6 +288,18 @@ func (d *Detector) IsCalculation(line string) (bool, error) {
 		return false, nil
 	}
 
+	for _, rule := range d.rules {
+		if rule.Pattern.MatchString(trimmed) {
+			return rule.Type == BlockCalculation, nil
+		}
+	}
+	if d.startsWithTextKeyword(trimmed) {
+		return false, nil
+	}
+	if d.classifier != nil {
+		return d.classifier.IsCalculation(line)
+	}
+
 	// Explicit markdown patterns are never calculations
 	if isMarkdownPattern(trimmed) {
 		return false, nil
@@ -172,17 +311,19 @@ func (d *Detector) IsCalculation(line string) (bool, error) {
 	if !strings.HasSuffix(source, "\n") {
 		source += "\n"
 	}
-	_, err := parser.Parse(source)
+	_, err := parser.ParseWithLocale(source, d.locale)
 	if err != nil {
-		// Parse error = not valid CalcMark syntax = treat as markdown
-		return false, nil
+		// Parse error = not valid CalcMark syntax = treat as markdown,
+		// except a mistaken percentage phrase like "x = change from 80 to 100",
+		// so evaluating it reports the error
+		return d.assignsPercentPhrase(trimmed), nil
 	}
 
 	// Successfully parsed - it's a valid calculation
 	// But we still need to check if it LOOKS like a calculation vs prose
 	// (e.g., "Hello" parses as an identifier but is likely prose)
 	// Use lexer-based heuristics for this final check
-	lex := lexer.NewLexer(trimmed)
+	lex := lexer.NewLexerWithLocale(trimmed, d.locale)
 	tokens, err := lex.Tokenize()
 	if err != nil {
 		return false, nil
@@ -198,6 +339,44 @@ func (d *Detector) IsCalculation(line string) (bool, error) {
 	return looksLikeCalculation(meaningfulTokens), nil
 }
 
+// startsWithTextKeyword checks if line starts with a text keyword of the
+// detector's dialect, as a whole word.
+func (d *Detector) startsWithTextKeyword(line string) bool {
+	if len(d.keywords) == 0 {
+		return false
+	}
+	tag := normalizeDialect(d.locale.Tag)
+	language, _, _ := strings.Cut(tag, "-")
+	dialects := []string{"", language}
+	if tag != language {
+		dialects = append(dialects, tag)
+	}
+	lower := strings.ToLower(line)
+	for _, dialect := range dialects {
+		for _, kw := range d.keywords[dialect] {
+			rest, ok := strings.CutPrefix(lower, kw)
+			if !ok {
+				continue
+			}
+			if next := []rune(rest); len(next) == 0 || !isWordRune(next[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeDialect returns a locale tag or language in lowercase with "-"
+// separators, as used for keyword lookup.
+func normalizeDialect(dialect string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(dialect), "_", "-"))
+}
+
+// isWordRune checks if r can continue a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
 // filterNonNewlineTokens returns tokens excluding NEWLINE.
 // Pure function: no side effects.
 func filterNonNewlineTokens(tokens []lexer.Token) []lexer.Token {
@@ -293,6 +472,24 @@ func looksLikeCalculation(tokens []lexer.Token) bool {
 	return false
 }
 
+// assignsPercentPhrase checks if line assigns a percentage phrase that
+// starts with a word: "x = increase ...", "x = change from ...".
+func (d *Detector) assignsPercentPhrase(line string) bool {
+	tokens, err := lexer.NewLexerWithLocale(line, d.locale).Tokenize()
+	if err != nil {
+		return false
+	}
+	tokens = filterNonNewlineTokens(tokens)
+	if len(tokens) < 4 || tokens[0].Type != lexer.IDENTIFIER || tokens[1].Type != lexer.ASSIGN || tokens[2].Type != lexer.IDENTIFIER {
+		return false
+	}
+	switch strings.ToLower(tokens[2].Value) {
+	case "increase", "decrease", "change":
+		return true
+	}
+	return false
+}
+
 // isNumberToken checks if a token type is a number variant.
 // Pure function.
 func isNumberToken(t lexer.TokenType) bool {
@@ -312,7 +509,8 @@ func isDateToken(t lexer.TokenType) bool {
 		lexer.DATE_THIS_WEEK, lexer.DATE_THIS_MONTH, lexer.DATE_THIS_YEAR,
 		lexer.DATE_NEXT_WEEK, lexer.DATE_NEXT_MONTH, lexer.DATE_NEXT_YEAR,
 		lexer.DATE_LAST_WEEK, lexer.DATE_LAST_MONTH, lexer.DATE_LAST_YEAR,
-		lexer.DATE_LITERAL, lexer.DURATION_LITERAL:
+		lexer.DATE_LITERAL, lexer.DURATION_LITERAL, lexer.TIME_LITERAL,
+		lexer.SCHEDULE_EVERY, lexer.SCHEDULE_NEXT, lexer.SCHEDULE_COUNT:
 		return true
 	}
 	return false
@@ -342,6 +540,12 @@ func isKeywordToken(t lexer.TokenType) bool {
 	return false
 }
 
+// isResultComment reports whether line is a "# = ..." result comment, as
+// cm convert --provenance writes after each calculation.
+func isResultComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "# =")
+}
+
 // isMarkdownPattern checks if a line matches common markdown patterns.
 // These patterns explicitly indicate the line is NOT a calculation.
 func isMarkdownPattern(line string) bool {
@@ -370,6 +574,11 @@ func isMarkdownPattern(line string) bool {
 		return true
 	}
 
+	// HTML comments, including block annotations
+	if strings.HasPrefix(line, "<!--") {
+		return true
+	}
+
 	// Links
 	if strings.HasPrefix(line, "[") && strings.Contains(line, "](") {
 		return true
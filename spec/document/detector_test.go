@@ -1,6 +1,9 @@
 package document
 
 import (
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/CalcMark/go-calcmark/spec/lexer"
@@ -328,3 +331,173 @@ y = 20`
 		}
 	}
 }
+
+func TestDetectorRules(t *testing.T) {
+	detector := NewDetector()
+	detector.AddRule(regexp.MustCompile(`^Q\d\b`), BlockText)
+	detector.AddRule(regexp.MustCompile(`^TODO\b`), BlockCalculation)
+
+	tests := []struct {
+		line   string
+		isCalc bool
+	}{
+		{"Q3 - Q2", false}, // Rule overrides the heuristics
+		{"  Q4 + Q1  ", false},
+		{"Q = 5", true},
+		{"TODO: write this", true},
+		{"x = 5", true},
+		{"Some prose", false},
+	}
+	for _, tt := range tests {
+		if got, _ := detector.IsCalculation(tt.line); got != tt.isCalc {
+			t.Errorf("IsCalculation(%q) = %v, want %v", tt.line, got, tt.isCalc)
+		}
+	}
+}
+
+// TestDetectorPercentPhrases checks a mistaken percentage phrase in an
+// assignment is a calculation, so evaluating it reports the error, while
+// prose using the same words stays text.
+func TestDetectorPercentPhrases(t *testing.T) {
+	tests := []struct {
+		line   string
+		isCalc bool
+	}{
+		{"growth = change from 80 to 100 in %", true},
+		{"growth = change from 80 to 100", true},
+		{"price = increase $100 by 10", true},
+		{"Change from the old plan to the new one.", false},
+		{"We increase prices by 10 each year.", false},
+	}
+	for _, tt := range tests {
+		if got, _ := NewDetector().IsCalculation(tt.line); got != tt.isCalc {
+			t.Errorf("IsCalculation(%q) = %v, want %v", tt.line, got, tt.isCalc)
+		}
+	}
+}
+
+func TestDetectorTextKeywords(t *testing.T) {
+	detector := NewDetector()
+	detector.AddTextKeywords("", "Revenue")
+	detector.AddTextKeywords("de", "Summe")
+	detector.AddTextKeywords("de-CH", "Total")
+
+	tests := []struct {
+		locale lexer.NumberLocale
+		line   string
+		isCalc bool
+	}{
+		{lexer.LocaleUS, "revenue - costs", false},
+		{lexer.LocaleUS, "revenues - costs", true}, // Whole words only
+		{lexer.LocaleUS, "Summe - Kosten", true},
+		{lexer.LocaleDE, "Summe - Kosten", false},
+		{lexer.LocaleDE, "Revenue - costs", false},
+		{lexer.LocaleDE, "Total - Kosten", true},
+		{lexer.LocaleCH, "Total - Kosten", false},
+		{lexer.LocaleCH, "Summe - Kosten", false},
+	}
+	for _, tt := range tests {
+		if got, _ := detector.WithLocale(tt.locale).IsCalculation(tt.line); got != tt.isCalc {
+			t.Errorf("IsCalculation(%q) under %s = %v, want %v", tt.line, tt.locale.Tag, got, tt.isCalc)
+		}
+	}
+}
+
+// prefixClassifier treats lines starting with "calc:" as calculations and
+// defers to the built-in heuristics for the rest.
+type prefixClassifier struct{ fallback LineClassifier }
+
+func (c prefixClassifier) IsCalculation(line string) (bool, error) {
+	if strings.HasPrefix(strings.TrimSpace(line), "calc:") {
+		return true, nil
+	}
+	return c.fallback.IsCalculation(line)
+}
+
+func TestDetectorClassifier(t *testing.T) {
+	detector := NewDetector()
+	detector.SetClassifier(prefixClassifier{fallback: NewDetector()})
+	detector.AddRule(regexp.MustCompile(`^calc: skip`), BlockText)
+
+	blocks, err := detector.DetectBlocks("# Title\ncalc: anything\nx = 5\ncalc: skip this\n")
+	if err != nil {
+		t.Fatalf("DetectBlocks() error = %v", err)
+	}
+	var types []BlockType
+	for _, b := range blocks {
+		types = append(types, b.Type())
+	}
+	want := []BlockType{BlockText, BlockCalculation, BlockText}
+	if !slices.Equal(types, want) {
+		t.Errorf("block types = %v, want %v", types, want)
+	}
+}
+
+func TestNewDocumentWithDetector(t *testing.T) {
+	detector := NewDetector()
+	detector.AddRule(regexp.MustCompile(`^Q\d\b`), BlockText)
+
+	doc, err := NewDocumentWithDetector("Q3 - Q2\n\n\nx = 5\n", detector)
+	if err != nil {
+		t.Fatalf("NewDocumentWithDetector() error = %v", err)
+	}
+	if blocks := doc.GetBlocks(); len(blocks) != 2 || blocks[0].Block.Type() != BlockText {
+		t.Fatalf("expected a text block first, got %d blocks", len(blocks))
+	}
+
+	// Reparsing keeps the customization
+	if _, err := doc.Reparse("Q1 + Q4\n\n\nx = 6\n"); err != nil {
+		t.Fatalf("Reparse() error = %v", err)
+	}
+	if blocks := doc.GetBlocks(); blocks[0].Block.Type() != BlockText {
+		t.Error("expected the rule to apply after Reparse")
+	}
+	if isCalc, _ := doc.Detector().IsCalculation("Q2 * 2"); isCalc {
+		t.Error("Detector() should carry the document's rules")
+	}
+}
+
+func TestDetectorSchedulesAndTimes(t *testing.T) {
+	detector := NewDetector()
+	tests := []struct {
+		line   string
+		isCalc bool
+	}{
+		{"next payday after Jun 1 2026", true},
+		{"count paydays between Mar 1 and Jun 30", true},
+		{"every 2nd Friday", true},
+		{"14:30 in Tokyo", true},
+		{"Next we talk about budgets.", false},
+		{"Every day is new.", false},
+	}
+	for _, tt := range tests {
+		if got, _ := detector.IsCalculation(tt.line); got != tt.isCalc {
+			t.Errorf("IsCalculation(%q) = %v, want %v", tt.line, got, tt.isCalc)
+		}
+	}
+}
+
+// TestDetectorResultComments checks that "# = ..." comments right after
+// calculations stay in their block, while one after text is a heading.
+func TestDetectorResultComments(t *testing.T) {
+	source := "x = 10\n# = 10 @ 2026-03-01\ny = x * 2\n# = x(10) * 2 → 20 @ 2026-03-01\n\n\nSome notes.\n# = not a result\n"
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument() error = %v", err)
+	}
+
+	blocks := doc.GetBlocks()
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 blocks, got %d", len(blocks))
+	}
+	calc, ok := blocks[0].Block.(*CalcBlock)
+	if !ok {
+		t.Fatalf("Block 0 should be calculation, got %v", blocks[0].Block.Type())
+	}
+	if src := calc.Source(); len(src) < 4 || src[3] != "# = x(10) * 2 → 20 @ 2026-03-01" || len(calc.Statements()) != 2 {
+		t.Errorf("Calc block has lines %q and %d statements, want the comments and 2", src, len(calc.Statements()))
+	}
+	if blocks[1].Block.Type() != BlockText {
+		t.Errorf("Block 1 should be text, got %v", blocks[1].Block.Type())
+	}
+}
@@ -0,0 +1,195 @@
+package document
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// ChangeKind classifies how a block differs between two document versions.
+type ChangeKind string
+
+const (
+	BlockAdded      ChangeKind = "added"      // Only in the new document
+	BlockRemoved    ChangeKind = "removed"    // Only in the old document
+	BlockModified   ChangeKind = "modified"   // Source edited in place
+	BlockRecomputed ChangeKind = "recomputed" // Same source, different results or error
+)
+
+// BlockChange is one block that differs between two document versions.
+// Old fields are empty for added blocks; New fields are empty for removed ones.
+type BlockChange struct {
+	Kind  ChangeKind
+	Type  BlockType
+	OldID string
+	NewID string
+
+	OldSource []string
+	NewSource []string
+
+	// Per-statement results and evaluation errors, for calculation blocks
+	OldResults []types.Type
+	NewResults []types.Type
+	OldError   error
+	NewError   error
+}
+
+// DocumentDiff describes what changed between two versions of a document.
+type DocumentDiff struct {
+	// Blocks lists changed blocks in document order; removed blocks appear
+	// where they were in the old document.
+	Blocks []BlockChange
+
+	// Variables lists every variable whose final value changed, sorted by name
+	Variables []VariableChange
+}
+
+// IsEmpty reports whether the two versions are equivalent.
+func (d *DocumentDiff) IsEmpty() bool {
+	return len(d.Blocks) == 0 && len(d.Variables) == 0
+}
+
+// Diff compares two versions of a document, such as the last saved copy and
+// the current buffer, block by block. Blocks are matched by source, so block
+// IDs need not be shared; unmatched blocks between two matches are paired up
+// as modified when they have the same type.
+//
+// Both documents are evaluated on scratch copies (see PreviewReplaceBlockSource)
+// and neither is modified.
+func Diff(oldDoc, newDoc *Document) (*DocumentDiff, error) {
+	before, err := oldDoc.evaluateSnapshot("", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	after, err := newDoc.evaluateSnapshot("", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &DocumentDiff{}
+	change := func(kind ChangeKind, oldNode, newNode *BlockNode) BlockChange {
+		c := BlockChange{Kind: kind}
+		if oldNode != nil {
+			c.Type = oldNode.Block.Type()
+			c.OldID = oldNode.ID
+			c.OldSource = oldNode.Block.Source()
+			c.OldResults = before.results[oldNode.ID]
+			c.OldError = before.errors[oldNode.ID]
+		}
+		if newNode != nil {
+			c.Type = newNode.Block.Type()
+			c.NewID = newNode.ID
+			c.NewSource = newNode.Block.Source()
+			c.NewResults = after.results[newNode.ID]
+			c.NewError = after.errors[newNode.ID]
+		}
+		return c
+	}
+
+	// flush reports the unmatched blocks between two matches
+	flush := func(removed, added []*BlockNode) {
+		for len(removed) > 0 && len(added) > 0 && removed[0].Block.Type() == added[0].Block.Type() {
+			diff.Blocks = append(diff.Blocks, change(BlockModified, removed[0], added[0]))
+			removed, added = removed[1:], added[1:]
+		}
+		for _, node := range removed {
+			diff.Blocks = append(diff.Blocks, change(BlockRemoved, node, nil))
+		}
+		for _, node := range added {
+			diff.Blocks = append(diff.Blocks, change(BlockAdded, nil, node))
+		}
+	}
+
+	oldBlocks, newBlocks := oldDoc.blocks, newDoc.blocks
+	i, j := 0, 0
+	for _, pair := range matchBlocks(oldBlocks, newBlocks) {
+		flush(oldBlocks[i:pair[0]], newBlocks[j:pair[1]])
+		oldNode, newNode := oldBlocks[pair[0]], newBlocks[pair[1]]
+		if !snapshotsEqual(before, oldNode.ID, after, newNode.ID) {
+			diff.Blocks = append(diff.Blocks, change(BlockRecomputed, oldNode, newNode))
+		}
+		i, j = pair[0]+1, pair[1]+1
+	}
+	flush(oldBlocks[i:], newBlocks[j:])
+
+	names := slices.Collect(maps.Keys(before.vars))
+	for name := range after.vars {
+		if _, ok := before.vars[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		oldVal, newVal := before.vars[name], after.vars[name]
+		if valueString(oldVal) == valueString(newVal) {
+			continue
+		}
+		diff.Variables = append(diff.Variables, VariableChange{
+			Name:   name,
+			Before: oldVal,
+			After:  newVal,
+		})
+	}
+
+	return diff, nil
+}
+
+// matchBlocks returns the index pairs of the longest common subsequence of
+// blocks with identical type and source, in order.
+func matchBlocks(oldBlocks, newBlocks []*BlockNode) [][2]int {
+	key := func(node *BlockNode) string {
+		return node.Block.Type().String() + "\x00" + strings.Join(node.Block.Source(), "\n")
+	}
+	oldKeys := make([]string, len(oldBlocks))
+	for i, node := range oldBlocks {
+		oldKeys[i] = key(node)
+	}
+	newKeys := make([]string, len(newBlocks))
+	for j, node := range newBlocks {
+		newKeys[j] = key(node)
+	}
+
+	// lcs[i][j] is the LCS length of oldKeys[i:] and newKeys[j:]
+	lcs := make([][]int, len(oldKeys)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newKeys)+1)
+	}
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		for j := len(newKeys) - 1; j >= 0; j-- {
+			if oldKeys[i] == newKeys[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(oldKeys) && j < len(newKeys); {
+		switch {
+		case oldKeys[i] == newKeys[j]:
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// snapshotsEqual reports whether a block has the same results and error in
+// two snapshots, where it may have different IDs.
+func snapshotsEqual(a *evalSnapshot, aID string, b *evalSnapshot, bID string) bool {
+	if errorString(a.errors[aID]) != errorString(b.errors[bID]) {
+		return false
+	}
+	return slices.EqualFunc(a.results[aID], b.results[bID], func(x, y types.Type) bool {
+		return valueString(x) == valueString(y)
+	})
+}
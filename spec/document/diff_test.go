@@ -0,0 +1,128 @@
+package document
+
+import (
+	"testing"
+)
+
+func mustDocument(t *testing.T, source string) *Document {
+	t.Helper()
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	return doc
+}
+
+func TestDiff(t *testing.T) {
+	oldDoc := mustDocument(t, `# Budget
+
+rent = 1000
+
+
+food = 400
+
+
+total = rent + food
+
+
+Old notes
+`)
+	newDoc := mustDocument(t, `# Budget
+
+rent = 1200
+
+
+food = 400
+
+
+total = rent + food
+
+
+fun = 100
+`)
+
+	diff, err := Diff(oldDoc, newDoc)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	want := []struct {
+		kind   ChangeKind
+		source string // First line of the new (or removed) source
+	}{
+		{BlockModified, "rent = 1200"},
+		{BlockRecomputed, "total = rent + food"},
+		{BlockRemoved, "Old notes"},
+		{BlockAdded, "fun = 100"},
+	}
+	if len(diff.Blocks) != len(want) {
+		t.Fatalf("Diff() blocks = %+v, want %d changes", diff.Blocks, len(want))
+	}
+	for i, w := range want {
+		got := diff.Blocks[i]
+		source := got.NewSource
+		if got.Kind == BlockRemoved {
+			source = got.OldSource
+		}
+		if got.Kind != w.kind || len(source) == 0 || source[0] != w.source {
+			t.Errorf("block %d = %s %q, want %s %q", i, got.Kind, source, w.kind, w.source)
+		}
+	}
+
+	recomputed := diff.Blocks[1]
+	if recomputed.OldID == "" || recomputed.NewID == "" {
+		t.Error("recomputed block should reference both versions")
+	}
+	if valueString(recomputed.OldResults[0]) != "1400" || valueString(recomputed.NewResults[0]) != "1600" {
+		t.Errorf("recomputed results = %v -> %v, want 1400 -> 1600", recomputed.OldResults, recomputed.NewResults)
+	}
+	if diff.Blocks[2].NewID != "" || diff.Blocks[3].OldID != "" {
+		t.Error("removed and added blocks should only reference their own version")
+	}
+
+	wantVars := map[string][2]string{
+		"rent":  {"1000", "1200"},
+		"total": {"1400", "1600"},
+		"fun":   {"", "100"},
+	}
+	if len(diff.Variables) != len(wantVars) {
+		t.Fatalf("Diff() variables = %+v, want %d", diff.Variables, len(wantVars))
+	}
+	for _, change := range diff.Variables {
+		w, ok := wantVars[change.Name]
+		if !ok || valueString(change.Before) != w[0] || valueString(change.After) != w[1] {
+			t.Errorf("variable %s = %v -> %v, want %v", change.Name, change.Before, change.After, w)
+		}
+	}
+	// Sorted by name
+	if diff.Variables[0].Name != "fun" || diff.Variables[2].Name != "total" {
+		t.Errorf("variables not sorted: %+v", diff.Variables)
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	source := "---\nglobals:\n  rate: 0.1\n---\nx = 100 * rate\n"
+	diff, err := Diff(mustDocument(t, source), mustDocument(t, source))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !diff.IsEmpty() {
+		t.Errorf("Diff() of identical documents = %+v, want empty", diff)
+	}
+}
+
+func TestDiffFrontmatterAndErrors(t *testing.T) {
+	oldDoc := mustDocument(t, "---\nglobals:\n  rate: 0.1\n---\nx = 100 * rate\n")
+	newDoc := mustDocument(t, "---\nglobals:\n  other: 0.1\n---\nx = 100 * rate\n")
+
+	diff, err := Diff(oldDoc, newDoc)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(diff.Blocks) != 1 || diff.Blocks[0].Kind != BlockRecomputed {
+		t.Fatalf("Diff() blocks = %+v, want one recomputed block", diff.Blocks)
+	}
+	if diff.Blocks[0].OldError != nil || diff.Blocks[0].NewError == nil {
+		t.Errorf("errors = %v -> %v, want the new version to fail", diff.Blocks[0].OldError, diff.Blocks[0].NewError)
+	}
+}
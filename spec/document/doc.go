@@ -38,6 +38,15 @@
 //	textBlock.AppendLine("# Results")
 //	doc.AppendBlock(textBlock)
 //
+// # Serialization
+//
+// Serialize writes a document back out. Until it is edited, the output is
+// byte-identical to the source it was created from, blank-line runs,
+// trailing whitespace, line endings, and frontmatter formatting included:
+//
+//	doc, _ := document.NewDocument(source)
+//	doc.Serialize() == source // true
+//
 // # Incremental Evaluation
 //
 // The document model supports incremental updates for interactive editors:
@@ -51,6 +60,75 @@
 //	// Re-evaluate only affected blocks
 //	doc.EvaluateBlock(blockID)
 //
+//	// Preview an edit without applying it
+//	preview, _ := doc.PreviewReplaceBlockSource(blockID, []string{"total = 300"})
+//	for _, change := range preview.Variables {
+//		fmt.Println(change.Name, change.Before, "→", change.After)
+//	}
+//
+// When an editor only has the new text, Reparse updates the document in
+// place, keeping the IDs and results of unchanged blocks:
+//
+//	result, _ := doc.Reparse(newSource)
+//	eval.EvaluateAffectedBlocks(doc, result.AffectedBlockIDs)
+//
+// For a keystroke within one line, ReplaceLine re-lexes and re-parses only
+// that line:
+//
+//	result, _ := doc.ReplaceLine(lineNo, newText)
+//	eval.EvaluateAffectedBlocks(doc, result.AffectedBlockIDs)
+//
+// # Transactions
+//
+// Group several mutations (paste, search-replace, templates) so they apply
+// atomically and produce one combined UpdateResult for a single re-evaluation:
+//
+//	tx := doc.Begin()
+//	tx.ReplaceBlockSource(id1, []string{"price = 20"})
+//	tx.DeleteBlock(id2)
+//	result, err := tx.Commit() // rolls back if any operation failed
+//
+// ApplyEdits does the same for a prepared list of edits:
+//
+//	result, err := doc.ApplyEdits([]document.Edit{
+//		{Kind: document.EditReplace, BlockID: id1, Source: []string{"price = 20"}},
+//		{Kind: document.EditDelete, BlockID: id2},
+//	})
+//
+// # Diffing Versions
+//
+// Diff compares two versions of a document, e.g. the saved file and the
+// editor buffer, and reports changed blocks and variable values:
+//
+//	diff, _ := document.Diff(savedDoc, currentDoc)
+//	for _, change := range diff.Variables {
+//		fmt.Println(change.Name, change.Before, "→", change.After)
+//	}
+//
+// # Block Metadata
+//
+// An HTML comment annotation labels and tags the block that follows it.
+// Annotations stay in the source, so they survive saving unchanged:
+//
+//	<!-- calc: label="Budget Summary" tags=finance -->
+//	total = rent + food
+//
+//	node, _ := doc.FindBlockByLabel("Budget Summary")
+//	node.Block.Metadata().HasTag("finance") // true
+//
+// # Imports
+//
+// Frontmatter imports name other documents whose variables this one uses,
+// optionally narrowed to a "#anchor" (block label or id, or heading slug):
+//
+//	---
+//	imports: [rates.cm, budget.cm#summary]
+//	---
+//
+// Loading is left to a Resolver supplied to the evaluator. Evaluation records
+// where each imported variable came from, so BlocksAffectedByImport finds
+// the blocks to re-evaluate when an imported document changes.
+//
 // # Dependency Tracking
 //
 // The document tracks dependencies between blocks to enable smart
@@ -62,8 +140,17 @@
 // Automatic detection of calculation vs markdown:
 //
 //	detector := document.NewDetector()
-//	blockType := detector.DetectLine("x = 5")  // CalcBlock
-//	blockType = detector.DetectLine("# Title") // TextBlock
+//	detector.IsCalculation("x = 5")   // true
+//	detector.IsCalculation("# Title") // false
+//
+// Embedders whose prose trips the heuristics can customize detection with
+// regex rules, text keywords per locale, or their own LineClassifier, and
+// create documents with it:
+//
+//	detector := document.NewDetector()
+//	detector.AddRule(regexp.MustCompile(`^Q\d\b`), document.BlockText)
+//	detector.AddTextKeywords("de", "Hinweis", "Summe")
+//	doc, err := document.NewDocumentWithDetector(source, detector)
 //
 // # Use Cases
 //
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/semantic"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -16,21 +18,64 @@ type Document struct {
 	varToBlocks map[string][]string      // Dependency graph: Variable → Block UUIDs
 	env         *interpreter.Environment // Accumulated environment (top-down)
 	frontmatter *Frontmatter             // Parsed frontmatter (exchange rates, globals)
+	imported    map[string]string        // Variable → import path, set by the evaluator
+	batching    bool                     // A transaction is open; analysis waits for Commit
+	detector    *Detector                // Customized block detection, nil for the default
+	original    originalSource           // As loaded, for Serialize
+	trash       []TrashedBlock           // Soft-deleted blocks, oldest first
+
+	envLookup func(string) (string, bool) // Expands ${NAME} in globals; nil for off
 }
 
 // BlockNode wraps a Block with metadata for incremental updates.
 type BlockNode struct {
 	ID    string // UUID (session-ephemeral)
-	Block Block  // Underlying block (CalcBlock or TextBlock)
+	Block Block  // Underlying block (CalcBlock, TextBlock, or CustomBlock)
 }
 
 // NewDocument creates a new document from CalcMark source and eagerly parses it.
 func NewDocument(source string) (*Document, error) {
+	return NewDocumentWithLocale(source, "")
+}
+
+// NewDocumentWithLocale is NewDocument for source whose number literals are
+// written in the given locale (e.g., "de-DE" for 1.000,50) unless the
+// frontmatter declares its own. The locale is recorded in the frontmatter,
+// so the document reads back the same way once serialized. LocaleAuto
+// infers it from the literals; see LocaleDetection.
+func NewDocumentWithLocale(source string, locale string) (*Document, error) {
+	return newDocument(source, locale, nil)
+}
+
+// NewDocumentWithDetector is NewDocument with block detection customized
+// by detector's rules, keywords, and classifier. Its locale is replaced by
+// the frontmatter's, and the document keeps using it when reparsed.
+func NewDocumentWithDetector(source string, detector *Detector) (*Document, error) {
+	return newDocument(source, "", detector)
+}
+
+func newDocument(source string, locale string, detector *Detector) (*Document, error) {
 	// Parse frontmatter first (if present)
 	fm, remaining, err := ParseFrontmatter(source)
 	if err != nil {
 		return nil, fmt.Errorf("frontmatter: %w", err)
 	}
+	written := fm.Serialize() // Before a locale is added below
+	if locale != "" && (fm == nil || fm.Locale == "") {
+		if locale != LocaleAuto {
+			if _, err := lexer.LookupLocale(locale); err != nil {
+				return nil, err
+			}
+		}
+		if fm == nil {
+			fm = &Frontmatter{
+				Exchange: make(map[string]decimal.Decimal),
+				Globals:  make(map[string]string),
+			}
+		}
+		fm.Locale = locale
+		fm.detectLocale(remaining)
+	}
 
 	doc := &Document{
 		blocks:      []*BlockNode{},
@@ -38,11 +83,18 @@ func NewDocument(source string) (*Document, error) {
 		varToBlocks: make(map[string][]string),
 		env:         interpreter.NewEnvironment(),
 		frontmatter: fm,
+		detector:    detector,
+	}
+	doc.original = originalSource{
+		text:        source,
+		style:       DetectTextStyle(source),
+		header:      source[:len(source)-len(remaining)],
+		frontmatter: written,
+		body:        remaining,
 	}
 
 	// Detect blocks from remaining source (after frontmatter)
-	detector := NewDetector()
-	blocks, err := detector.DetectBlocks(remaining)
+	blocks, err := doc.detectorFor(fm).DetectBlocks(remaining)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +111,7 @@ func NewDocument(source string) (*Document, error) {
 
 	// Build dependency graph for calculation blocks
 	doc.rebuildDependencies()
+	doc.attachMetadata()
 
 	return doc, nil
 }
@@ -68,6 +121,34 @@ func (d *Document) GetBlocks() []*BlockNode {
 	return d.blocks
 }
 
+// SetBlockIDs gives the blocks the IDs in ids, in order, in place of the
+// ones they were created with. Block IDs last only as long as a document
+// is loaded; a service keeping documents elsewhere stores them alongside
+// the source and restores them when it loads the document again, so
+// clients' references to blocks still work. ids must be unique, one for
+// each block.
+func (d *Document) SetBlockIDs(ids []string) error {
+	if len(ids) != len(d.blocks) {
+		return fmt.Errorf("%d block IDs for %d blocks", len(ids), len(d.blocks))
+	}
+	index := make(map[string]*BlockNode, len(ids))
+	for i, id := range ids {
+		if id == "" {
+			return fmt.Errorf("block %d has an empty ID", i)
+		}
+		if _, dup := index[id]; dup {
+			return fmt.Errorf("duplicate block ID: %s", id)
+		}
+		index[id] = d.blocks[i]
+	}
+	for i, node := range d.blocks {
+		node.ID = ids[i]
+	}
+	d.blockIndex = index
+	d.linkDependencies()
+	return nil
+}
+
 // GetBlock returns a block by ID.
 func (d *Document) GetBlock(id string) (*BlockNode, bool) {
 	node, ok := d.blockIndex[id]
@@ -95,6 +176,9 @@ type Diagnostic struct {
 	Message  string
 	Line     int // 1-indexed line number within the block
 	Column   int // 1-indexed column number
+
+	Suggestions []string       // Likely intended fixes, closest first
+	Fixes       []semantic.Fix // Edits resolving the diagnostic, in block lines
 }
 
 // ReplaceBlockSource replaces the source of a block and propagates changes.
@@ -106,19 +190,30 @@ func (d *Document) ReplaceBlockSource(blockID string, newSource []string) (*Upda
 	}
 
 	// Update source
+	old := node.Block
 	switch b := node.Block.(type) {
 	case *CalcBlock:
 		b.source = newSource
+		b.tokens = nil
 		b.SetDirty(true)
 	case *TextBlock:
 		b.source = newSource
 		b.SetDirty(true)
+	case CustomBlock:
+		custom, err := d.Detector().reparseCustom(b.Kind(), newSource)
+		if err != nil {
+			return nil, fmt.Errorf("%s block: %w", b.Kind(), err)
+		}
+		node.Block = custom
+	}
+	if !d.batching {
+		d.attachMetadata()
 	}
 
 	// Rebuild dependencies for this block
 	affectedIDs := []string{blockID}
 
-	if calcBlock, ok := node.Block.(*CalcBlock); ok {
+	if calcBlock, ok := old.(variableBlock); ok {
 		// Get old variables this block defined
 		oldVars := calcBlock.Variables()
 
@@ -140,6 +235,11 @@ func (d *Document) ReplaceBlockSource(blockID string, newSource []string) (*Upda
 		}
 	}
 
+	// A custom block was reparsed whole, so its variables are known now
+	if node.Block != old {
+		d.linkDependencies()
+	}
+
 	// Remove duplicates
 	affectedIDs = uniqueStrings(affectedIDs)
 
@@ -181,11 +281,20 @@ func (d *Document) InsertBlock(afterBlockID string, blockType BlockType, source
 	d.blocks = append(d.blocks[:pos+1], append([]*BlockNode{newNode}, d.blocks[pos+1:]...)...)
 	d.blockIndex[newNode.ID] = newNode
 
+	// In a transaction, Commit analyzes the result once
+	if d.batching {
+		return &UpdateResult{
+			ModifiedBlockID:  newNode.ID,
+			AffectedBlockIDs: []string{newNode.ID},
+		}, nil
+	}
+
 	// Rebuild dependencies (this analyzes the new block and updates varToBlocks)
 	err := d.rebuildDependencies()
 	if err != nil {
 		return nil, err
 	}
+	d.attachMetadata()
 
 	// Collect affected blocks: the new block + all blocks that transitively depend
 	// on variables defined by the new block
@@ -226,11 +335,17 @@ func (d *Document) DeleteBlock(blockID string) (*UpdateResult, error) {
 	d.blocks = append(d.blocks[:pos], d.blocks[pos+1:]...)
 	delete(d.blockIndex, blockID)
 
+	// In a transaction, Commit analyzes the result once
+	if d.batching {
+		return &UpdateResult{ModifiedBlockID: blockID}, nil
+	}
+
 	// Rebuild dependencies
 	err := d.rebuildDependencies()
 	if err != nil {
 		return nil, err
 	}
+	d.attachMetadata()
 
 	// All blocks after this position might be affected
 	affectedIDs := []string{}
@@ -249,9 +364,7 @@ func (d *Document) DeleteBlock(blockID string) (*UpdateResult, error) {
 // rebuildDependencies rebuilds the variable → blocks dependency graph.
 // This is called after structural changes to the document.
 func (d *Document) rebuildDependencies() error {
-	// Clear existing mappings
-	d.varToBlocks = make(map[string][]string)
-	analyzer := NewDependencyAnalyzer()
+	analyzer := NewDependencyAnalyzerWithLocale(d.NumberLocale())
 
 	// For each calc block, analyze dependencies
 	for _, node := range d.blocks {
@@ -268,12 +381,33 @@ func (d *Document) rebuildDependencies() error {
 		}
 	}
 
-	// Second pass: build dependency graph
+	d.linkDependencies()
+	return nil
+}
+
+// variableBlock is a block that defines and reads variables: a CalcBlock
+// or an EvaluableBlock.
+type variableBlock interface {
+	Variables() []string
+	Dependencies() []string
+}
+
+// linkDependencies rebuilds the variable → blocks graph from each calc
+// block's analyzed variables and dependencies, without reparsing.
+func (d *Document) linkDependencies() {
+	// Clear existing mappings
+	d.varToBlocks = make(map[string][]string)
+
 	// For each block, find which earlier blocks define its dependencies
 	envVars := make(map[string]string) // var name → block ID that defines it
 
+	// Imported variables are defined before any block
+	for varName := range d.imported {
+		envVars[varName] = ""
+	}
+
 	for _, node := range d.blocks {
-		if calcBlock, ok := node.Block.(*CalcBlock); ok {
+		if calcBlock, ok := node.Block.(variableBlock); ok {
 			// For each dependency of this block
 			for _, depVar := range calcBlock.Dependencies() {
 				// Find the block that defines this variable
@@ -295,8 +429,6 @@ func (d *Document) rebuildDependencies() error {
 			}
 		}
 	}
-
-	return nil
 }
 
 // uniqueStrings removes duplicates from a string slice.
@@ -345,7 +477,7 @@ func (d *Document) GetTransitiveDependents(changedVars []string) []string {
 
 			// Find variables defined by this block - they're now "changed" too
 			if node, ok := d.blockIndex[blockID]; ok {
-				if cb, ok := node.Block.(*CalcBlock); ok {
+				if cb, ok := node.Block.(variableBlock); ok {
 					for _, definedVar := range cb.Variables() {
 						if !visited[definedVar] {
 							varsToProcess = append(varsToProcess, definedVar)
@@ -395,6 +527,32 @@ func (d *Document) SetFrontmatter(fm *Frontmatter) {
 	d.frontmatter = fm
 }
 
+// NumberLocale returns the number format of the document's literals,
+// as declared by the frontmatter locale key (default 1,000.50).
+func (d *Document) NumberLocale() lexer.NumberLocale {
+	return d.frontmatter.NumberLocale()
+}
+
+// LocaleDetection returns how the number locale was inferred from the
+// document's literals when it is LocaleAuto, and false otherwise.
+func (d *Document) LocaleDetection() (LocaleDetection, bool) {
+	return d.frontmatter.LocaleDetection()
+}
+
+// Detector returns the block detector for the document's number locale,
+// with the customizations it was created with, if any.
+func (d *Document) Detector() *Detector {
+	return d.detectorFor(d.frontmatter)
+}
+
+// detectorFor returns the document's block detector for fm's locale.
+func (d *Document) detectorFor(fm *Frontmatter) *Detector {
+	if d.detector == nil {
+		return NewDetectorWithLocale(fm.NumberLocale())
+	}
+	return d.detector.WithLocale(fm.NumberLocale())
+}
+
 // EnsureFrontmatter returns the frontmatter, creating an empty one if nil.
 func (d *Document) EnsureFrontmatter() *Frontmatter {
 	if d.frontmatter == nil {
@@ -406,8 +564,10 @@ func (d *Document) EnsureFrontmatter() *Frontmatter {
 	return d.frontmatter
 }
 
-// ApplyFrontmatter injects frontmatter values (exchange rates, globals) into
+// ApplyFrontmatter injects frontmatter values (exchange rates, CPI, globals) into
 // the given interpreter environment. This should be called before evaluation.
+// References to environment variables in globals are expanded if
+// SetEnvExpansion allows it.
 func (d *Document) ApplyFrontmatter(env *interpreter.Environment) error {
 	if d.frontmatter == nil {
 		return nil
@@ -422,9 +582,28 @@ func (d *Document) ApplyFrontmatter(env *interpreter.Environment) error {
 		env.SetExchangeRate(from, to, rate)
 	}
 
+	// Apply the price index adjust() reads
+	for year, index := range d.frontmatter.CPI {
+		env.SetCPI(year, index)
+	}
+
+	// Apply the holiday calendar for business-day arithmetic
+	calendar, err := d.frontmatter.Calendar()
+	if err != nil {
+		return fmt.Errorf("apply frontmatter: %w", err)
+	}
+	env.SetCalendar(calendar)
+
+	// Apply the sample count for distributions
+	env.SetSamples(d.frontmatter.Samples)
+
 	// Apply globals (parse literal values and inject as variables)
 	if len(d.frontmatter.Globals) > 0 {
-		parsed, err := ParseGlobals(d.frontmatter.Globals)
+		globals, err := d.expandedGlobals()
+		if err != nil {
+			return fmt.Errorf("apply frontmatter globals: %w", err)
+		}
+		parsed, err := ParseGlobalsWithLocale(globals, d.NumberLocale())
 		if err != nil {
 			return fmt.Errorf("apply frontmatter globals: %w", err)
 		}
@@ -63,6 +63,35 @@ func TestBlockReplacement(t *testing.T) {
 	}
 }
 
+func TestSetBlockIDs(t *testing.T) {
+	doc, err := NewDocument("x = 10\n\nSome text.\n\ny = x * 2\n")
+	if err != nil {
+		t.Fatalf("NewDocument() error = %v", err)
+	}
+
+	if err := doc.SetBlockIDs([]string{"a", "b"}); err == nil {
+		t.Error("Expected an error for too few IDs")
+	}
+	if err := doc.SetBlockIDs([]string{"a", "b", "a"}); err == nil {
+		t.Error("Expected an error for duplicate IDs")
+	}
+	if err := doc.SetBlockIDs([]string{"a", "b", "c"}); err != nil {
+		t.Fatalf("SetBlockIDs() error = %v", err)
+	}
+
+	if _, ok := doc.GetBlock("c"); !ok {
+		t.Fatal("Expected block c")
+	}
+	// Dependencies follow the new IDs
+	result, err := doc.ReplaceBlockSource("a", []string{"x = 20"})
+	if err != nil {
+		t.Fatalf("ReplaceBlockSource() error = %v", err)
+	}
+	if !slices.Equal(result.AffectedBlockIDs, []string{"a", "c"}) {
+		t.Errorf("Expected blocks a and c affected, got %v", result.AffectedBlockIDs)
+	}
+}
+
 func TestBlockInsertion(t *testing.T) {
 	source := "x = 10"
 
@@ -231,3 +260,39 @@ z = y + 1`
 		t.Errorf("Expected z's block (%s) in affected blocks (transitive)", zBlockID)
 	}
 }
+
+func TestNewDocumentWithLocale(t *testing.T) {
+	doc, err := NewDocumentWithLocale("x = 1.234,5\ny = x * 2\n", "de-DE")
+	if err != nil {
+		t.Fatalf("NewDocumentWithLocale() error = %v", err)
+	}
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	cb, ok := doc.GetBlocks()[0].Block.(*CalcBlock)
+	if !ok {
+		t.Fatalf("expected a calculation block, got %T", doc.GetBlocks()[0].Block)
+	}
+	if got := cb.LastValue(); got == nil || got.String() != "2469" {
+		t.Errorf("y = %v, want 2469", got)
+	}
+
+	// The locale is recorded in frontmatter so the exported source re-parses the same way
+	if fm := doc.GetFrontmatter(); fm == nil || fm.Locale != "de-DE" {
+		t.Fatalf("expected frontmatter locale de-DE, got %+v", fm)
+	}
+
+	// A locale declared in frontmatter wins over the option
+	doc, err = NewDocumentWithLocale("---\nlocale: fr-FR\n---\nx = 1 000,5\n", "en-US")
+	if err != nil {
+		t.Fatalf("NewDocumentWithLocale() error = %v", err)
+	}
+	if doc.NumberLocale().Tag != "fr-FR" {
+		t.Errorf("expected fr-FR, got %q", doc.NumberLocale().Tag)
+	}
+
+	if _, err := NewDocumentWithLocale("x = 1\n", "xx-YY"); err == nil {
+		t.Error("expected error for unknown locale")
+	}
+}
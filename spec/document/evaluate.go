@@ -10,8 +10,8 @@ import (
 )
 
 // Evaluate evaluates all blocks in the document in dependency order.
-// CalcBlocks are evaluated top-down with accumulated environment.
-// TextBlocks are skipped (no evaluation needed).
+// CalcBlocks and EvaluableBlocks are evaluated top-down with accumulated
+// environment. TextBlocks and other custom blocks are skipped.
 //
 // Returns error if any block has parse/semantic/evaluation errors.
 func (d *Document) Evaluate() error {
@@ -21,13 +21,9 @@ func (d *Document) Evaluate() error {
 	// Evaluate blocks in document order (top-down)
 	// Dependency graph ensures proper ordering was maintained during insertion
 	for _, node := range d.blocks {
-		if calcBlock, ok := node.Block.(*CalcBlock); ok {
-			err := d.evaluateCalcBlock(node.ID, calcBlock)
-			if err != nil {
-				return fmt.Errorf("block %s: %w", node.ID[:8], err)
-			}
+		if err := d.evaluateNode(node); err != nil {
+			return err
 		}
-		// TextBlocks don't need evaluation
 	}
 
 	return nil
@@ -44,8 +40,10 @@ func (d *Document) EvaluateBlock(blockID string) error {
 		return fmt.Errorf("block not found: %s", blockID)
 	}
 
-	// Only evaluate CalcBlocks
-	if _, ok := node.Block.(*CalcBlock); !ok {
+	// Only evaluate CalcBlocks and EvaluableBlocks
+	switch node.Block.(type) {
+	case *CalcBlock, EvaluableBlock:
+	default:
 		return nil // TextBlocks don't need evaluation
 	}
 
@@ -65,18 +63,31 @@ func (d *Document) EvaluateBlock(blockID string) error {
 	// Re-evaluate from this block forward (top-down semantics)
 	// All blocks after this one might be affected by the change
 	for i := startIdx; i < len(d.blocks); i++ {
-		node := d.blocks[i]
-		if cb, ok := node.Block.(*CalcBlock); ok {
-			err := d.evaluateCalcBlock(node.ID, cb)
-			if err != nil {
-				return fmt.Errorf("block %s: %w", node.ID[:8], err)
-			}
+		if err := d.evaluateNode(d.blocks[i]); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// evaluateNode evaluates a calc or evaluable custom block in the
+// document's environment, skipping others.
+func (d *Document) evaluateNode(node *BlockNode) error {
+	switch b := node.Block.(type) {
+	case *CalcBlock:
+		if err := d.evaluateCalcBlock(node.ID, b); err != nil {
+			return fmt.Errorf("block %s: %w", node.ID[:8], err)
+		}
+	case EvaluableBlock:
+		if err := b.Evaluate(d.env); err != nil {
+			return fmt.Errorf("block %s: %s block: %w", node.ID[:8], b.Kind(), err)
+		}
+		b.SetDirty(false)
+	}
+	return nil
+}
+
 // evaluateCalcBlock evaluates a single CalcBlock.
 // Steps: parse → semantic check → interpret → store results
 func (d *Document) evaluateCalcBlock(blockID string, block *CalcBlock) error {
@@ -89,7 +100,7 @@ func (d *Document) evaluateCalcBlock(blockID string, block *CalcBlock) error {
 		source += "\n"
 	}
 
-	nodes, err := parser.Parse(source)
+	nodes, err := parser.ParseWithLocale(source, d.NumberLocale())
 	if err != nil {
 		block.SetError(err)
 		return fmt.Errorf("parse error: %w", err)
@@ -0,0 +1,101 @@
+package document
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SetEnvExpansion expands ${NAME} references in frontmatter global values
+// through lookup, such as os.LookupEnv, whenever the frontmatter is
+// applied, so one document can serve as a template that CI or a shell
+// script fills in:
+//
+//	globals:
+//	  tax_rate: ${TAX_RATE:-0.25}
+//
+// The frontmatter keeps the references, so the document serializes
+// unchanged. Expansion is off until set; a nil lookup turns it off again,
+// leaving references as errors. Evaluating the document sets it from the
+// evaluator's option.
+func (d *Document) SetEnvExpansion(lookup func(name string) (string, bool)) {
+	d.envLookup = lookup
+}
+
+// expandedGlobals returns the frontmatter globals with their ${NAME}
+// references expanded, or the globals themselves when none have any.
+func (d *Document) expandedGlobals() (map[string]string, error) {
+	globals := d.frontmatter.Globals
+	var expanded map[string]string
+	for _, name := range slices.Sorted(maps.Keys(globals)) {
+		value := globals[name]
+		if !strings.Contains(value, "${") {
+			continue
+		}
+		if d.envLookup == nil {
+			return nil, fmt.Errorf("global '%s' refers to an environment variable, but expanding environment variables is off", name)
+		}
+		if expanded == nil {
+			expanded = maps.Clone(globals)
+		}
+		result, err := ExpandReferences(value, d.envLookup)
+		if err != nil {
+			return nil, fmt.Errorf("global '%s': %w", name, err)
+		}
+		expanded[name] = result
+	}
+	if expanded == nil {
+		return globals, nil
+	}
+	return expanded, nil
+}
+
+// ExpandReferences replaces each ${NAME} in s with lookup(NAME). As in
+// shells, ${NAME:-default} uses default when NAME is unset or empty.
+// A reference to an unset name without a default is an error, so a
+// missing variable can't silently become a different number.
+func ExpandReferences(s string, lookup func(name string) (string, bool)) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			out.WriteString(s)
+			return out.String(), nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unclosed reference '%s'", s[start:])
+		}
+		ref := s[start+2 : start+end]
+		name, fallback, hasDefault := strings.Cut(ref, ":-")
+		if !isValidEnvName(name) {
+			return "", fmt.Errorf("invalid reference '${%s}'", ref)
+		}
+
+		value, ok := lookup(name)
+		switch {
+		case hasDefault && value == "":
+			value = fallback
+		case !ok:
+			return "", fmt.Errorf("${%s} is not set", name)
+		}
+		out.WriteString(s[:start])
+		out.WriteString(value)
+		s = s[start+end+1:]
+	}
+}
+
+// isValidEnvName reports whether name can be an environment variable's
+// name: letters, digits, and underscores, not starting with a digit.
+func isValidEnvName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
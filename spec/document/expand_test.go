@@ -0,0 +1,74 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+)
+
+func TestExpandReferences(t *testing.T) {
+	vars := map[string]string{"RATE": "0.28", "EMPTY": "", "CUR": "EUR"}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+	tests := []struct {
+		input, want, wantErr string
+	}{
+		{"${RATE}", "0.28", ""},
+		{"100 ${CUR}", "100 EUR", ""},
+		{"${MISSING:-3}", "3", ""},
+		{"${EMPTY:-5}", "5", ""},
+		{"${RATE:-0.1}", "0.28", ""},
+		{"$100", "$100", ""},
+		{"${MISSING}", "", "${MISSING} is not set"},
+		{"${RATE", "", "unclosed reference"},
+		{"${1X}", "", "invalid reference"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandReferences(tt.input, lookup)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExpandReferences(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandReferences(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestSetEnvExpansion(t *testing.T) {
+	source := "---\nglobals:\n  tax_rate: ${TAX_RATE:-0.25}\n  base: $100\n---\nx = 1\n"
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Off by default
+	if err := doc.ApplyFrontmatter(interpreter.NewEnvironment()); err == nil || !strings.Contains(err.Error(), "expanding environment variables is off") {
+		t.Errorf("without expansion, err = %v", err)
+	}
+
+	doc.SetEnvExpansion(func(name string) (string, bool) {
+		if name == "TAX_RATE" {
+			return "0.3", true
+		}
+		return "", false
+	})
+	env := interpreter.NewEnvironment()
+	if err := doc.ApplyFrontmatter(env); err != nil {
+		t.Fatal(err)
+	}
+	if rate, _ := env.Get("tax_rate"); rate == nil || rate.String() != "0.3" {
+		t.Errorf("tax_rate = %v, want 0.3", rate)
+	}
+	if got := doc.GetFrontmatter().Globals["tax_rate"]; got != "${TAX_RATE:-0.25}" {
+		t.Errorf("frontmatter changed to %q", got)
+	}
+	if !strings.Contains(doc.Serialize(), "${TAX_RATE:-0.25}") {
+		t.Errorf("serialized without the reference:\n%s", doc.Serialize())
+	}
+}
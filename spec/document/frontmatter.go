@@ -3,8 +3,15 @@ package document
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 	"gopkg.in/yaml.v3"
 )
@@ -13,8 +20,18 @@ import (
 // It is delimited by --- markers and contains YAML content.
 //
 // Reserved keys (CalcMark grammar):
+//   - title, author, date: Document metadata, shown in page headers of exports
+//   - cpi: Price index by year, which adjust() uses for inflation
+//   - durations: How durations are displayed (long: 1 day 1 hour, short: 1d 1h)
 //   - exchange: Currency conversion rates
-//   - (future: precision, locale, etc.)
+//   - exports: Headline variables for reports (e.g., email/Slack summaries)
+//   - highlight: Conditional colors for results (e.g., red above a threshold)
+//   - holidays: Holiday calendars and dates skipped by business-day arithmetic
+//   - imports: Other documents whose variables this one references
+//   - locale: Number format of the document's literals (e.g., de-DE for 1.000,50), or auto to infer it
+//   - samples: How many samples distributions such as normal(100, 15) draw
+//   - widgets: Interactive controls bound to globals (e.g., sliders)
+//   - (future: precision, etc.)
 //
 // User-defined variables go under 'globals':
 //
@@ -34,13 +51,70 @@ type Frontmatter struct {
 	// Values are CalcMark expressions that will be parsed and evaluated.
 	// Example: "base_date" -> "Jan 15 2025", "tax_rate" -> "0.32"
 	Globals map[string]string
+
+	// Exports lists the document's headline variables, in display order.
+	// Report renderers show these instead of every variable.
+	Exports []string
+
+	// Locale is the tag for how number literals are written, e.g. "de-DE"
+	// for 1.000,50. Empty means the default (1,000.50); LocaleAuto infers
+	// it from the literals in the body.
+	Locale   string
+	detected *LocaleDetection // What LocaleAuto inferred
+
+	// Durations is how results display durations: "long" (1 day 1 hour),
+	// "short" (1d 1h), or empty for the unit they were computed in.
+	Durations string
+
+	// Widgets declares interactive controls for globals, keyed by global name.
+	// Frontends render them; the document only validates and applies them.
+	Widgets map[string]Widget
+
+	// Highlights colors results by threshold, keyed by variable name.
+	// Each variable's rules are kept in declaration order.
+	Highlights map[string][]HighlightRule
+
+	// Imports lists documents whose variables this document can reference,
+	// in declaration order. An evaluator with a Resolver loads them.
+	Imports []Import
+
+	// CPI is a consumer price index by year, e.g. 2015 -> 237.0, which
+	// adjust() uses to restate amounts in another year's prices.
+	CPI map[int]decimal.Decimal
+
+	// Samples is how many samples distributions draw, from 1 to
+	// types.MaxSamples; zero means types.DefaultSamples.
+	Samples int
+
+	// Holidays lists the holidays business-day arithmetic skips, as written:
+	// built-in calendar names (US, UK), dates (2026-12-24, Dec 24 2026), and
+	// dates without a year, which recur every year (Dec 24).
+	Holidays []string
+
+	// Title, Author, and Date describe the document for exports such as
+	// PDF page headers. Date is free text, e.g. "2025-10-01" or "Q3 2025".
+	Title  string
+	Author string
+	Date   string
 }
 
 // reservedKeys lists all top-level frontmatter keys reserved for CalcMark grammar.
 // Unknown keys at the top level are rejected to ensure forward compatibility.
 var reservedKeys = map[string]bool{
-	"exchange": true,
-	"globals":  true,
+	"cpi":       true,
+	"durations": true,
+	"exchange":  true,
+	"globals":   true,
+	"exports":   true,
+	"highlight": true,
+	"holidays":  true,
+	"imports":   true,
+	"locale":    true,
+	"samples":   true,
+	"widgets":   true,
+	"title":     true,
+	"author":    true,
+	"date":      true,
 }
 
 // ExchangeRateKey creates a normalized key for looking up exchange rates.
@@ -84,6 +158,34 @@ func (f *Frontmatter) SetExchangeRate(key string, rate decimal.Decimal) {
 	f.Exchange[strings.ToUpper(key)] = rate
 }
 
+// NumberLocale returns the number format declared by the locale key,
+// or lexer.LocaleUS if none is set. Safe to call on nil.
+func (f *Frontmatter) NumberLocale() lexer.NumberLocale {
+	if f == nil || f.Locale == "" {
+		return lexer.LocaleUS
+	}
+	if f.Locale == LocaleAuto {
+		if f.detected == nil {
+			return lexer.LocaleUS
+		}
+		return f.detected.Locale
+	}
+	loc, err := lexer.LookupLocale(f.Locale)
+	if err != nil {
+		return lexer.LocaleUS // Validated by ParseFrontmatter; only reachable if set directly
+	}
+	return loc
+}
+
+// Widget returns the widget declared for a global. Safe to call on nil.
+func (f *Frontmatter) Widget(name string) (Widget, bool) {
+	if f == nil {
+		return Widget{}, false
+	}
+	w, ok := f.Widgets[name]
+	return w, ok
+}
+
 // SetGlobal sets a global variable value. The valueExpr is stored as the
 // raw expression string for serialization.
 func (f *Frontmatter) SetGlobal(name, valueExpr string) {
@@ -111,11 +213,76 @@ func (f *Frontmatter) HasExchangeRate(key string) bool {
 	return ok
 }
 
+// Calendar returns the holiday calendar declared by the holidays key, or
+// nil if there is none. Safe to call on nil.
+func (f *Frontmatter) Calendar() (*types.Calendar, error) {
+	if f == nil || len(f.Holidays) == 0 {
+		return nil, nil
+	}
+	calendar := types.NewCalendar()
+	for _, entry := range f.Holidays {
+		if err := addHoliday(calendar, entry); err != nil {
+			return nil, err
+		}
+	}
+	return calendar, nil
+}
+
+// addHoliday adds a holidays entry to calendar: a built-in calendar name,
+// an ISO date, or a CalcMark date literal, which recurs every year if it
+// has no year.
+func addHoliday(calendar *types.Calendar, entry string) error {
+	entry = strings.TrimSpace(entry)
+	if _, ok := types.LookupHolidayRegion(entry); ok {
+		return calendar.AddRegion(entry)
+	}
+	if t, err := time.Parse(time.DateOnly, entry); err == nil {
+		calendar.AddDate(types.NewDateFromTime(t))
+		return nil
+	}
+
+	nodes, err := parser.Parse(entry + "\n")
+	if err == nil && len(nodes) == 1 {
+		node := nodes[0]
+		if expr, ok := node.(*ast.Expression); ok {
+			node = expr.Expr
+		}
+		if lit, ok := node.(*ast.DateLiteral); ok {
+			date, err := evalDateLiteral(lit)
+			if err != nil {
+				return fmt.Errorf("invalid holiday '%s': %w", entry, err)
+			}
+			d := date.(*types.Date)
+			if lit.Year == nil {
+				calendar.AddAnnual(d.Time.Month(), d.Time.Day())
+			} else {
+				calendar.AddDate(d)
+			}
+			return nil
+		}
+	}
+
+	// Anything else is most likely a misspelled calendar name
+	return calendar.AddRegion(entry)
+}
+
 // frontmatterYAML is the intermediate struct for YAML unmarshaling.
 // This keeps the YAML structure separate from the normalized Frontmatter type.
 type frontmatterYAML struct {
-	Exchange map[string]float64 `yaml:"exchange"`
-	Globals  map[string]string  `yaml:"globals"`
+	Exchange  map[string]float64    `yaml:"exchange"`
+	CPI       map[int]float64       `yaml:"cpi"`
+	Globals   map[string]string     `yaml:"globals"`
+	Exports   []string              `yaml:"exports"`
+	Locale    string                `yaml:"locale"`
+	Durations string                `yaml:"durations"`
+	Widgets   map[string]widgetYAML `yaml:"widgets"`
+	Imports   []string              `yaml:"imports"`
+	Highlight map[string]yaml.Node  `yaml:"highlight"`
+	Holidays  yaml.Node             `yaml:"holidays"`
+	Samples   int                   `yaml:"samples"`
+	Title     string                `yaml:"title"`
+	Author    string                `yaml:"author"`
+	Date      string                `yaml:"date"`
 }
 
 // ParseFrontmatter extracts YAML frontmatter from the beginning of a document.
@@ -125,10 +292,13 @@ type frontmatterYAML struct {
 //   - Start at line 1 with exactly "---"
 //   - End with a line containing exactly "---"
 //   - Contain valid YAML between the delimiters
-//   - Only use reserved keys at top level (exchange, globals)
+//   - Only use reserved keys at top level (title, author, date, cpi, durations, exchange,
+//     globals, exports, highlight, holidays, imports, locale, samples, widgets)
 //
-// If no frontmatter is present, returns (nil, source, nil).
+// If no frontmatter is present, returns (nil, source, nil). A leading byte
+// order mark is dropped either way.
 func ParseFrontmatter(source string) (*Frontmatter, string, error) {
+	source = strings.TrimPrefix(source, ByteOrderMark) // The remaining source stays a suffix of the input
 	lines := strings.Split(source, "\n")
 	if len(lines) == 0 {
 		return nil, source, nil
@@ -178,6 +348,9 @@ func ParseFrontmatter(source string) (*Frontmatter, string, error) {
 	fm := &Frontmatter{
 		Exchange: make(map[string]decimal.Decimal),
 		Globals:  make(map[string]string),
+		Title:    raw.Title,
+		Author:   raw.Author,
+		Date:     raw.Date,
 	}
 
 	// Process exchange rates
@@ -192,6 +365,17 @@ func ParseFrontmatter(source string) (*Frontmatter, string, error) {
 		fm.Exchange[normalizedKey] = decimal.NewFromFloat(rate)
 	}
 
+	// Process the price index; adjust() divides by it
+	for year, index := range raw.CPI {
+		if index <= 0 {
+			return nil, "", fmt.Errorf("invalid cpi for %d: must be positive", year)
+		}
+		if fm.CPI == nil {
+			fm.CPI = make(map[int]decimal.Decimal)
+		}
+		fm.CPI[year] = decimal.NewFromFloat(index)
+	}
+
 	// Copy globals (values are raw strings to be parsed as CalcMark expressions)
 	for name, expr := range raw.Globals {
 		// Validate variable name (must be valid identifier)
@@ -201,6 +385,90 @@ func ParseFrontmatter(source string) (*Frontmatter, string, error) {
 		fm.Globals[name] = expr
 	}
 
+	if raw.Locale != "" {
+		if raw.Locale != LocaleAuto {
+			if _, err := lexer.LookupLocale(raw.Locale); err != nil {
+				return nil, "", err
+			}
+		}
+		fm.Locale = raw.Locale
+		fm.detectLocale(strings.Join(lines[closeIdx+1:], "\n"))
+	}
+
+	switch durations := strings.ToLower(strings.TrimSpace(raw.Durations)); durations {
+	case "", "long", "short":
+		fm.Durations = durations
+	default:
+		return nil, "", fmt.Errorf("invalid durations '%s': must be long or short", raw.Durations)
+	}
+
+	// The cap keeps a document from making every operation on a
+	// distribution allocate without bound
+	if _, ok := rawMap["samples"]; ok && (raw.Samples < 1 || raw.Samples > types.MaxSamples) {
+		return nil, "", fmt.Errorf("invalid samples %d: must be from 1 to %d", raw.Samples, types.MaxSamples)
+	}
+	fm.Samples = raw.Samples
+
+	// Exports name variables defined anywhere in the document
+	for _, name := range raw.Exports {
+		if !isValidIdentifier(name) {
+			return nil, "", fmt.Errorf("invalid export name '%s': must be a valid identifier", name)
+		}
+		fm.Exports = append(fm.Exports, name)
+	}
+
+	// Widgets bind to globals, so each must name one
+	for name, rawWidget := range raw.Widgets {
+		if _, ok := fm.Globals[name]; !ok {
+			return nil, "", fmt.Errorf("widget '%s' must name a global", name)
+		}
+		w, err := parseWidget(name, rawWidget)
+		if err != nil {
+			return nil, "", err
+		}
+		if fm.Widgets == nil {
+			fm.Widgets = make(map[string]Widget)
+		}
+		fm.Widgets[name] = w
+	}
+
+	// Highlight rules name variables defined anywhere in the document
+	for name, node := range raw.Highlight {
+		if !isValidIdentifier(name) {
+			return nil, "", fmt.Errorf("invalid highlight name '%s': must be a valid identifier", name)
+		}
+		rules, err := parseHighlightRules(name, node, fm.NumberLocale())
+		if err != nil {
+			return nil, "", err
+		}
+		if fm.Highlights == nil {
+			fm.Highlights = make(map[string][]HighlightRule)
+		}
+		fm.Highlights[name] = rules
+	}
+
+	// Holidays are a single calendar name or a list of names and dates
+	switch raw.Holidays.Kind {
+	case 0:
+	case yaml.ScalarNode:
+		fm.Holidays = []string{raw.Holidays.Value}
+	default:
+		if err := raw.Holidays.Decode(&fm.Holidays); err != nil {
+			return nil, "", fmt.Errorf("invalid holidays: expected a calendar name or a list of names and dates")
+		}
+	}
+	if _, err := fm.Calendar(); err != nil {
+		return nil, "", err
+	}
+
+	for _, entry := range raw.Imports {
+		imp, err := ParseImport(entry)
+		if err != nil {
+			return nil, "", err
+		}
+		fm.Imports = append(fm.Imports, imp)
+	}
+
 	// Calculate remaining source (after closing delimiter)
 	remaining := ""
 	if closeIdx+1 < len(lines) {
@@ -210,6 +478,26 @@ func ParseFrontmatter(source string) (*Frontmatter, string, error) {
 	return fm, remaining, nil
 }
 
+// LocaleDetection returns what was inferred from the body for locale:
+// auto, or false if the locale is declared. Safe to call on nil.
+func (f *Frontmatter) LocaleDetection() (LocaleDetection, bool) {
+	if f == nil || f.detected == nil {
+		return LocaleDetection{}, false
+	}
+	return *f.detected, true
+}
+
+// detectLocale infers the locale from body if it is LocaleAuto. The
+// result stands until the document is loaded again, so editing a literal
+// can't change how all the others read.
+func (f *Frontmatter) detectLocale(body string) {
+	f.detected = nil
+	if f.Locale == LocaleAuto {
+		det := DetectLocale(body)
+		f.detected = &det
+	}
+}
+
 // isValidIdentifier checks if a string is a valid CalcMark identifier.
 // Identifiers must start with a letter or underscore and contain only
 // letters, digits, and underscores.
@@ -240,35 +528,111 @@ func isDigit(r rune) bool {
 }
 
 // Serialize returns the frontmatter as a YAML string with --- delimiters.
-// If the frontmatter has no content (no exchange rates, no globals), returns "".
+// If the frontmatter has no content (no exchange rates, price index, globals, exports, highlights, imports, locale, durations, samples, or widgets), returns "".
 func (f *Frontmatter) Serialize() string {
 	if f == nil {
 		return ""
 	}
-	if len(f.Exchange) == 0 && len(f.Globals) == 0 {
+	if len(f.Exchange) == 0 && len(f.CPI) == 0 && len(f.Globals) == 0 && len(f.Exports) == 0 && f.Locale == "" && f.Durations == "" && f.Samples == 0 && len(f.Widgets) == 0 && len(f.Imports) == 0 && len(f.Highlights) == 0 && len(f.Holidays) == 0 &&
+		f.Title == "" && f.Author == "" && f.Date == "" {
 		return ""
 	}
 
 	var sb strings.Builder
 	sb.WriteString("---\n")
 
+	// Document metadata leads, as in other markdown frontmatter
+	for _, field := range []struct{ key, value string }{{"title", f.Title}, {"author", f.Author}, {"date", f.Date}} {
+		if field.value != "" {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", field.key, yamlString(field.value)))
+		}
+	}
+
+	// Locale comes first: it governs how every number below is read
+	if f.Locale != "" {
+		sb.WriteString(fmt.Sprintf("locale: %s\n", f.Locale))
+	}
+	if f.Durations != "" {
+		sb.WriteString(fmt.Sprintf("durations: %s\n", f.Durations))
+	}
+	if f.Samples != 0 {
+		sb.WriteString(fmt.Sprintf("samples: %d\n", f.Samples))
+	}
+
+	// Imports come next: they define names used by the rest of the document
+	if len(f.Imports) > 0 {
+		sb.WriteString("imports:\n")
+		for _, imp := range f.Imports {
+			sb.WriteString(fmt.Sprintf("  - %s\n", imp))
+		}
+	}
+
 	// Serialize exchange rates
 	if len(f.Exchange) > 0 {
 		sb.WriteString("exchange:\n")
-		for key, rate := range f.Exchange {
+		for _, key := range slices.Sorted(maps.Keys(f.Exchange)) {
 			// Use String() for decimal to preserve precision
-			sb.WriteString(fmt.Sprintf("  %s: %s\n", key, rate.String()))
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", key, f.Exchange[key].String()))
+		}
+	}
+
+	// Serialize the price index
+	if len(f.CPI) > 0 {
+		sb.WriteString("cpi:\n")
+		for _, year := range slices.Sorted(maps.Keys(f.CPI)) {
+			sb.WriteString(fmt.Sprintf("  %d: %s\n", year, f.CPI[year].String()))
 		}
 	}
 
 	// Serialize globals
 	if len(f.Globals) > 0 {
 		sb.WriteString("globals:\n")
-		for name, expr := range f.Globals {
-			sb.WriteString(fmt.Sprintf("  %s: %s\n", name, expr))
+		for _, name := range slices.Sorted(maps.Keys(f.Globals)) {
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", name, f.Globals[name]))
+		}
+	}
+
+	// Serialize widgets (flow style keeps each on one line)
+	if len(f.Widgets) > 0 {
+		sb.WriteString("widgets:\n")
+		for _, name := range slices.Sorted(maps.Keys(f.Widgets)) {
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", name, f.Widgets[name].yaml()))
+		}
+	}
+
+	// Serialize highlight rules (flow style keeps each variable on one line)
+	if len(f.Highlights) > 0 {
+		sb.WriteString("highlight:\n")
+		for _, name := range slices.Sorted(maps.Keys(f.Highlights)) {
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", name, highlightYAML(f.Highlights[name])))
+		}
+	}
+
+	// Serialize holidays
+	if len(f.Holidays) > 0 {
+		sb.WriteString("holidays:\n")
+		for _, entry := range f.Holidays {
+			sb.WriteString(fmt.Sprintf("  - %s\n", yamlString(entry)))
+		}
+	}
+
+	// Serialize exports
+	if len(f.Exports) > 0 {
+		sb.WriteString("exports:\n")
+		for _, name := range f.Exports {
+			sb.WriteString(fmt.Sprintf("  - %s\n", name))
 		}
 	}
 
 	sb.WriteString("---\n\n") // Blank line after frontmatter for CommonMark compatibility
 	return sb.String()
 }
+
+// yamlString renders s as a YAML scalar, quoting it only when needed.
+func yamlString(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
@@ -3,7 +3,9 @@ package document
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/CalcMark/go-calcmark/spec/types"
 	"github.com/shopspring/decimal"
 )
 
@@ -618,3 +620,210 @@ func TestFrontmatter_Serialize_RoundTrip(t *testing.T) {
 		t.Errorf("tax_rate: expected 0.32, got %q", parsed.Globals["tax_rate"])
 	}
 }
+
+func TestParseFrontmatter_Exports(t *testing.T) {
+	source := `---
+globals:
+  budget: $50000
+exports:
+  - projected_burn
+  - runway
+---
+burn = $4000`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fm.Exports) != 2 || fm.Exports[0] != "projected_burn" || fm.Exports[1] != "runway" {
+		t.Errorf("expected exports [projected_burn runway], got %v", fm.Exports)
+	}
+
+	if _, _, err := ParseFrontmatter("---\nexports:\n  - 2fast\n---\n"); err == nil {
+		t.Error("expected error for invalid export name")
+	}
+
+	parsed, _, err := ParseFrontmatter(fm.Serialize())
+	if err != nil {
+		t.Fatalf("failed to parse serialized frontmatter: %v", err)
+	}
+	if len(parsed.Exports) != 2 || parsed.Exports[1] != "runway" {
+		t.Errorf("exports did not round-trip: %v", parsed.Exports)
+	}
+}
+
+func TestParseFrontmatter_Locale(t *testing.T) {
+	source := `---
+locale: de-DE
+globals:
+  rate: 1.000,50
+---
+x = rate + 0,5`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fm.Locale != "de-DE" || fm.NumberLocale().Decimal != ',' {
+		t.Errorf("expected locale de-DE with ',' decimal, got %q", fm.Locale)
+	}
+
+	if _, _, err := ParseFrontmatter("---\nlocale: xx-YY\n---\n"); err == nil {
+		t.Error("expected error for unknown locale")
+	}
+
+	var nilFM *Frontmatter
+	if nilFM.NumberLocale().Decimal != '.' {
+		t.Error("nil frontmatter should default to '.' decimal")
+	}
+
+	parsed, _, err := ParseFrontmatter(fm.Serialize())
+	if err != nil {
+		t.Fatalf("failed to parse serialized frontmatter: %v", err)
+	}
+	if parsed.Locale != "de-DE" || parsed.Globals["rate"] != "1.000,50" {
+		t.Errorf("locale did not round-trip: %q %v", parsed.Locale, parsed.Globals)
+	}
+}
+
+func TestParseFrontmatter_Durations(t *testing.T) {
+	fm, _, err := ParseFrontmatter("---\ndurations: Short\n---\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fm.Durations != "short" {
+		t.Errorf("Durations = %q, want short", fm.Durations)
+	}
+	if !strings.Contains(fm.Serialize(), "durations: short\n") {
+		t.Errorf("durations did not serialize: %q", fm.Serialize())
+	}
+
+	if _, _, err := ParseFrontmatter("---\ndurations: compact\n---\n"); err == nil {
+		t.Error("expected error for unknown duration style")
+	}
+}
+
+func TestParseFrontmatter_Samples(t *testing.T) {
+	fm, _, err := ParseFrontmatter("---\nsamples: 5000\n---\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fm.Samples != 5000 {
+		t.Errorf("Samples = %d, want 5000", fm.Samples)
+	}
+	if !strings.Contains(fm.Serialize(), "samples: 5000\n") {
+		t.Errorf("samples did not serialize: %q", fm.Serialize())
+	}
+
+	for _, bad := range []string{"0", "-5", "100001", "lots"} {
+		if _, _, err := ParseFrontmatter("---\nsamples: " + bad + "\n---\n"); err == nil {
+			t.Errorf("expected error for samples: %s", bad)
+		}
+	}
+}
+
+func TestParseFrontmatter_Metadata(t *testing.T) {
+	source := `---
+title: "Q3: Budget"
+author: Ada Lovelace
+date: 2025-10-01
+---
+x = 1`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fm.Title != "Q3: Budget" || fm.Author != "Ada Lovelace" || fm.Date != "2025-10-01" {
+		t.Errorf("unexpected metadata: title=%q author=%q date=%q", fm.Title, fm.Author, fm.Date)
+	}
+
+	serialized := fm.Serialize()
+	if !strings.HasPrefix(serialized, "---\ntitle: ") {
+		t.Errorf("expected title first, got %q", serialized)
+	}
+	parsed, _, err := ParseFrontmatter(serialized)
+	if err != nil {
+		t.Fatalf("failed to parse serialized frontmatter: %v", err)
+	}
+	if parsed.Title != fm.Title || parsed.Author != fm.Author || parsed.Date != fm.Date {
+		t.Errorf("metadata did not round-trip: %q", serialized)
+	}
+}
+
+func TestParseFrontmatter_Holidays(t *testing.T) {
+	source := `---
+holidays:
+  - us
+  - Dec 24
+  - 2026-01-02
+---
+x = 1`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calendar, err := fm.Calendar()
+	if err != nil {
+		t.Fatalf("unexpected calendar error: %v", err)
+	}
+	if got := calendar.Regions(); len(got) != 1 || got[0] != "US" {
+		t.Errorf("expected region US, got %v", got)
+	}
+	for _, tt := range []struct {
+		date    time.Time
+		holiday bool
+	}{
+		{time.Date(2026, time.December, 24, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, time.December, 24, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, time.January, 4, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, time.July, 3, 0, 0, 0, 0, time.UTC), true}, // Independence Day observed
+	} {
+		if got := calendar.IsHoliday(&types.Date{Time: tt.date}); got != tt.holiday {
+			t.Errorf("IsHoliday(%s) = %v, want %v", tt.date.Format(time.DateOnly), got, tt.holiday)
+		}
+	}
+
+	parsed, _, err := ParseFrontmatter(fm.Serialize())
+	if err != nil {
+		t.Fatalf("failed to parse serialized frontmatter: %v", err)
+	}
+	if strings.Join(parsed.Holidays, ",") != "us,Dec 24,2026-01-02" {
+		t.Errorf("holidays did not round-trip: %v", parsed.Holidays)
+	}
+
+	fm, _, err = ParseFrontmatter("---\nholidays: UK\n---\n")
+	if err != nil || len(fm.Holidays) != 1 || fm.Holidays[0] != "UK" {
+		t.Errorf("expected a single UK calendar, got %v (err %v)", fm.Holidays, err)
+	}
+
+	if _, _, err := ParseFrontmatter("---\nholidays: Narnia\n---\n"); err == nil {
+		t.Error("expected error for unknown holiday calendar")
+	}
+
+	var nilFM *Frontmatter
+	if calendar, err := nilFM.Calendar(); calendar != nil || err != nil {
+		t.Errorf("nil frontmatter should have no calendar, got %v, %v", calendar, err)
+	}
+}
+
+func TestParseFrontmatter_CPI(t *testing.T) {
+	fm, _, err := ParseFrontmatter("---\ncpi:\n  2025: 321.5\n  2015: 237.017\n---\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fm.CPI) != 2 || fm.CPI[2015].String() != "237.017" || fm.CPI[2025].String() != "321.5" {
+		t.Errorf("CPI = %v", fm.CPI)
+	}
+	if !strings.Contains(fm.Serialize(), "cpi:\n  2015: 237.017\n  2025: 321.5\n") {
+		t.Errorf("cpi did not serialize in year order: %q", fm.Serialize())
+	}
+
+	for _, bad := range []string{"  2015: 0", "  2015: -3", "  recent: 300"} {
+		if _, _, err := ParseFrontmatter("---\ncpi:\n" + bad + "\n---\n"); err == nil {
+			t.Errorf("expected error for cpi %q", bad)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 	"github.com/CalcMark/go-calcmark/spec/parser"
 	"github.com/CalcMark/go-calcmark/spec/types"
 )
@@ -27,12 +28,18 @@ type ParsedGlobals struct {
 //
 // Returns an error if any value cannot be parsed or is not a literal.
 func ParseGlobals(rawGlobals map[string]string) (*ParsedGlobals, error) {
+	return ParseGlobalsWithLocale(rawGlobals, lexer.LocaleUS)
+}
+
+// ParseGlobalsWithLocale is ParseGlobals for values whose numbers are
+// written in loc's format (e.g., "tax_rate: 0,32" with lexer.LocaleDE).
+func ParseGlobalsWithLocale(rawGlobals map[string]string, loc lexer.NumberLocale) (*ParsedGlobals, error) {
 	result := &ParsedGlobals{
 		Values: make(map[string]types.Type),
 	}
 
 	for name, exprStr := range rawGlobals {
-		value, err := parseGlobalValue(name, exprStr)
+		value, err := parseGlobalValue(name, exprStr, loc)
 		if err != nil {
 			return nil, err
 		}
@@ -43,7 +50,7 @@ func ParseGlobals(rawGlobals map[string]string) (*ParsedGlobals, error) {
 }
 
 // parseGlobalValue parses a single global variable value as a CalcMark literal.
-func parseGlobalValue(name, exprStr string) (types.Type, error) {
+func parseGlobalValue(name, exprStr string, loc lexer.NumberLocale) (types.Type, error) {
 	// Ensure expression ends with newline for parser
 	input := exprStr
 	if len(input) > 0 && input[len(input)-1] != '\n' {
@@ -51,7 +58,7 @@ func parseGlobalValue(name, exprStr string) (types.Type, error) {
 	}
 
 	// Parse the expression
-	nodes, err := parser.Parse(input)
+	nodes, err := parser.ParseWithLocale(input, loc)
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for '%s': %w", name, err)
 	}
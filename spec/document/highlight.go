@@ -0,0 +1,171 @@
+package document
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// HighlightColors are the colors a highlight rule can use. Frontends map
+// them to their own palette (terminal colors, CSS classes).
+var HighlightColors = []string{"red", "orange", "yellow", "green", "blue", "purple", "gray"}
+
+// highlightOps are the comparison operators, longest first so ">=" is not
+// read as ">".
+var highlightOps = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// HighlightRule colors a variable's result when it compares true against a
+// threshold. Rules are declared per variable in frontmatter and checked in
+// order; the first match wins:
+//
+//	---
+//	highlight:
+//	  burn_rate: {"> 50000": red, "> 30000": yellow, "<= 30000": green}
+//	---
+//
+// The threshold is compared with the result's number, ignoring any currency
+// or unit, so "> $50,000" and "> 50000" are the same rule.
+type HighlightRule struct {
+	Condition string          // As written, e.g. "> $50,000"
+	Op        string          // One of >, >=, <, <=, ==, !=
+	Threshold decimal.Decimal // Number in the condition
+	Color     string          // One of HighlightColors
+}
+
+// Matches reports whether value satisfies the rule. Values without a
+// number (dates, booleans, lists) never match.
+func (r HighlightRule) Matches(value types.Type) bool {
+	n, err := types.ToDecimal(value)
+	if err != nil {
+		return false
+	}
+	cmp := n.Cmp(r.Threshold)
+	switch r.Op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	}
+	return false
+}
+
+// parseHighlightRules reads one variable's rules from a YAML mapping of
+// condition -> color, keeping declaration order. loc is the number format
+// thresholds are written in.
+func parseHighlightRules(name string, node yaml.Node, loc lexer.NumberLocale) ([]HighlightRule, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("highlight '%s': expected a map of condition to color, e.g. {\"> 100\": red}", name)
+	}
+
+	var rules []HighlightRule
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		condition := strings.TrimSpace(node.Content[i].Value)
+		color := strings.ToLower(strings.TrimSpace(node.Content[i+1].Value))
+
+		rule := HighlightRule{Condition: condition, Color: color}
+		for _, op := range highlightOps {
+			if strings.HasPrefix(condition, op) {
+				rule.Op = op
+				break
+			}
+		}
+		if rule.Op == "" {
+			return nil, fmt.Errorf("highlight '%s': condition '%s' must start with >, >=, <, <=, ==, or !=", name, condition)
+		}
+
+		operand := condition[len(rule.Op):]
+		span := numberLiteral.FindStringIndex(operand)
+		if span == nil {
+			return nil, fmt.Errorf("highlight '%s': condition '%s' has no number", name, condition)
+		}
+		threshold, err := parseLocaleNumber(operand[span[0]:span[1]], loc)
+		if err != nil {
+			return nil, fmt.Errorf("highlight '%s': condition '%s': %w", name, condition, err)
+		}
+		rule.Threshold = threshold
+
+		if !slices.Contains(HighlightColors, color) {
+			return nil, fmt.Errorf("highlight '%s': unknown color '%s' (want %s)", name, color, strings.Join(HighlightColors, ", "))
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// highlightYAML returns rules in YAML flow style, as written in frontmatter.
+func highlightYAML(rules []HighlightRule) string {
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%q: %s", rule.Condition, rule.Color)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// HighlightColor returns the color of the first rule for variable name that
+// value matches, or "" if none does. Safe to call on nil.
+func (f *Frontmatter) HighlightColor(name string, value types.Type) string {
+	if f == nil || value == nil {
+		return ""
+	}
+	for _, rule := range f.Highlights[name] {
+		if rule.Matches(value) {
+			return rule.Color
+		}
+	}
+	return ""
+}
+
+// Highlights returns the highlight color of each of block's results, in the
+// same order as Results(); results without a matching rule are "". Returns
+// nil when the document declares no highlight rules.
+func (d *Document) Highlights(block *CalcBlock) []string {
+	fm := d.frontmatter
+	if fm == nil || len(fm.Highlights) == 0 {
+		return nil
+	}
+
+	results := block.Results()
+	statements := block.Statements()
+	colors := make([]string, len(results))
+	for i, result := range results {
+		if i >= len(statements) {
+			break
+		}
+		if assign, ok := statements[i].(*ast.Assignment); ok {
+			colors[i] = fm.HighlightColor(assign.Name, result)
+		}
+	}
+	return colors
+}
+
+// HighlightedVariables returns block's variables that match a highlight
+// rule, as name -> color. Returns nil if none match.
+func (d *Document) HighlightedVariables(block *CalcBlock) map[string]string {
+	var matched map[string]string
+	statements := block.Statements()
+	for i, color := range d.Highlights(block) {
+		if color == "" {
+			continue
+		}
+		if matched == nil {
+			matched = make(map[string]string)
+		}
+		// Highlights only colors assignments, so the statement is one
+		matched[statements[i].(*ast.Assignment).Name] = color
+	}
+	return matched
+}
@@ -0,0 +1,121 @@
+package document
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontmatter_Highlight(t *testing.T) {
+	source := `---
+highlight:
+  burn_rate: {"> $50,000": red, "> 30000": yellow, "<= 30000": green}
+  margin: {"< 0": red}
+---
+x = 1`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("ParseFrontmatter failed: %v", err)
+	}
+
+	rules := fm.Highlights["burn_rate"]
+	if len(rules) != 3 {
+		t.Fatalf("burn_rate rules = %d, want 3", len(rules))
+	}
+	// Declaration order is kept so the first match wins
+	wantColors := []string{"red", "yellow", "green"}
+	for i, rule := range rules {
+		if rule.Color != wantColors[i] {
+			t.Errorf("rule %d color = %q, want %q", i, rule.Color, wantColors[i])
+		}
+	}
+	if rules[0].Op != ">" || rules[0].Threshold.String() != "50000" {
+		t.Errorf("rule 0 = %s %s, want > 50000", rules[0].Op, rules[0].Threshold)
+	}
+	if rules[2].Op != "<=" {
+		t.Errorf("rule 2 op = %q, want <=", rules[2].Op)
+	}
+
+	// Round trip keeps conditions as written
+	serialized := fm.Serialize()
+	if !strings.Contains(serialized, `burn_rate: {"> $50,000": red, "> 30000": yellow, "<= 30000": green}`) {
+		t.Errorf("Serialize() = %q, missing burn_rate rules", serialized)
+	}
+	reparsed, _, err := ParseFrontmatter(serialized)
+	if err != nil {
+		t.Fatalf("re-parse failed: %v", err)
+	}
+	if margin := reparsed.Highlights["margin"]; len(margin) != 1 || margin[0].Condition != "< 0" || margin[0].Color != "red" {
+		t.Errorf("re-parsed margin = %v, want [< 0: red]", margin)
+	}
+}
+
+func TestParseFrontmatter_InvalidHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no operator", `x: {"50": red}`, "must start with"},
+		{"no number", `x: {"> lots": red}`, "has no number"},
+		{"unknown color", `x: {"> 5": magenta}`, "unknown color 'magenta'"},
+		{"not a map", `x: red`, "expected a map"},
+		{"bad name", `2x: {"> 5": red}`, "invalid highlight name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseFrontmatter("---\nhighlight:\n  " + tt.yaml + "\n---\n")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseFrontmatter() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDocumentHighlights(t *testing.T) {
+	source := `---
+locale: de-DE
+highlight:
+  burn: {"> 50.000": red, ">= 0": green}
+---
+burn = 60000 EUR
+spend = 5
+low = 100
+burn2 = burn
+`
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	block := doc.GetBlocks()[0].Block.(*CalcBlock)
+
+	// Thresholds are read in the document's locale and ignore units
+	fm := doc.GetFrontmatter()
+	value, err := ParseGlobalsWithLocale(map[string]string{"a": "60.000 EUR", "b": "20"}, doc.NumberLocale())
+	if err != nil {
+		t.Fatalf("ParseGlobals failed: %v", err)
+	}
+	if got := fm.HighlightColor("burn", value.Values["a"]); got != "red" {
+		t.Errorf("HighlightColor(60000 EUR) = %q, want red", got)
+	}
+	if got := fm.HighlightColor("burn", value.Values["b"]); got != "green" {
+		t.Errorf("HighlightColor(20) = %q, want green", got)
+	}
+	if got := fm.HighlightColor("spend", value.Values["a"]); got != "" {
+		t.Errorf("HighlightColor(spend) = %q, want none", got)
+	}
+
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	got := doc.Highlights(block)
+	want := []string{"red", "", "", ""}
+	if !slices.Equal(got, want) {
+		t.Errorf("Highlights() = %v, want %v", got, want)
+	}
+	if vars := doc.HighlightedVariables(block); len(vars) != 1 || vars["burn"] != "red" {
+		t.Errorf("HighlightedVariables() = %v, want burn=red", vars)
+	}
+}
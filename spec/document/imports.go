@@ -0,0 +1,173 @@
+package document
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Import names another document whose variables this one can reference,
+// declared in frontmatter:
+//
+//	---
+//	imports:
+//	  - rates.cm              # every variable rates.cm defines
+//	  - budget.cm#summary     # only the variables under the "summary" anchor
+//	---
+//	monthly = total / 12
+type Import struct {
+	Path   string // Document to load, relative to the importing document
+	Anchor string // Block label, annotation id, or heading slug; empty for all
+}
+
+// ParseImport parses a "path#anchor" import entry.
+func ParseImport(entry string) (Import, error) {
+	path, anchor, _ := strings.Cut(strings.TrimSpace(entry), "#")
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return Import{}, fmt.Errorf("invalid import '%s': missing document path", entry)
+	}
+	return Import{Path: path, Anchor: strings.TrimSpace(anchor)}, nil
+}
+
+// String returns the import as written in frontmatter.
+func (i Import) String() string {
+	if i.Anchor == "" {
+		return i.Path
+	}
+	return i.Path + "#" + i.Anchor
+}
+
+// Resolver loads imported documents. Implementations decide where documents
+// live (files, a database, an in-memory map) and which ones may be loaded.
+type Resolver interface {
+	// Resolve returns the source of the document at path, as written in an
+	// import by the document named from ("" for a document with no name).
+	// name identifies the loaded document uniquely, e.g. its absolute path;
+	// it is passed back as from for that document's own imports and is how
+	// import cycles are detected.
+	Resolve(from, path string) (name, source string, err error)
+}
+
+// ExportedVariables returns the variables another document can import from
+// d under anchor, sorted: with no anchor, every frontmatter global and
+// variable defined by a calculation; otherwise only the variables defined in
+// the blocks at the anchor (see AnchorBlocks). Variables d itself imports are
+// not passed on.
+func (d *Document) ExportedVariables(anchor string) ([]string, error) {
+	names := make(map[string]bool)
+	blocks := d.blocks
+	if anchor == "" {
+		if d.frontmatter != nil {
+			for name := range d.frontmatter.Globals {
+				names[name] = true
+			}
+		}
+	} else {
+		blocks = d.AnchorBlocks(anchor)
+		if len(blocks) == 0 {
+			return nil, fmt.Errorf("no block or heading named '%s'", anchor)
+		}
+	}
+
+	for _, node := range blocks {
+		if cb, ok := node.Block.(*CalcBlock); ok {
+			for _, name := range cb.Variables() {
+				names[name] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(names)), nil
+}
+
+// AnchorBlocks returns the blocks a "#anchor" reference points to: the block
+// annotated with label=anchor or id=anchor, or else every block in the
+// section under the markdown heading whose slug is anchor ("## Q3 Costs" has
+// the slug "q3-costs"), up to the next heading of the same or higher level.
+func (d *Document) AnchorBlocks(anchor string) []*BlockNode {
+	for _, node := range d.blocks {
+		meta := node.Block.Metadata()
+		if meta != nil && (meta.Label == anchor || meta.Attrs["id"] == anchor) {
+			return []*BlockNode{node}
+		}
+	}
+
+	var section []*BlockNode
+	level := 0 // Heading level of the open section, 0 if none
+	for _, node := range d.blocks {
+		if _, ok := node.Block.(*TextBlock); ok {
+			for _, line := range node.Block.Source() {
+				lvl, title := parseHeading(line)
+				switch {
+				case lvl == 0:
+				case level > 0 && lvl <= level:
+					return section
+				case level == 0 && headingSlug(title) == anchor:
+					level = lvl
+				}
+			}
+		}
+		if level > 0 {
+			section = append(section, node)
+		}
+	}
+	return section
+}
+
+// parseHeading returns the level and title of an ATX markdown heading,
+// or level 0 if line is not a heading.
+func parseHeading(line string) (int, string) {
+	trimmed := strings.TrimSpace(line)
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(trimmed[level:])
+}
+
+// headingSlug returns the anchor for a heading title the way markdown
+// renderers generate one: lowercase, spaces to hyphens, punctuation dropped.
+func headingSlug(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// SetImportedVariables records which variables evaluation took from imported
+// documents, as variable name -> import path, and rebuilds the dependency
+// graph so blocks that reference them are found by BlocksAffectedByImport.
+func (d *Document) SetImportedVariables(imported map[string]string) {
+	d.imported = imported
+	d.rebuildDependencies()
+}
+
+// ImportedVariables returns the variables taken from imported documents,
+// as variable name -> import path.
+func (d *Document) ImportedVariables() map[string]string {
+	return d.imported
+}
+
+// BlocksAffectedByImport returns the IDs of blocks, in document order, that
+// depend directly or transitively on variables from the import with path.
+// After that document changes, re-evaluating these blocks is enough.
+func (d *Document) BlocksAffectedByImport(path string) []string {
+	var vars []string
+	for name, from := range d.imported {
+		if from == path {
+			vars = append(vars, name)
+		}
+	}
+	return d.GetBlocksInDependencyOrder(d.GetTransitiveDependents(vars))
+}
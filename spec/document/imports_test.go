@@ -0,0 +1,119 @@
+package document
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontmatter_Imports(t *testing.T) {
+	source := `---
+imports:
+  - rates.cm
+  - shared/budget.cm#q3-costs
+---
+x = 1`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("ParseFrontmatter failed: %v", err)
+	}
+
+	want := []Import{{Path: "rates.cm"}, {Path: "shared/budget.cm", Anchor: "q3-costs"}}
+	if !slices.Equal(fm.Imports, want) {
+		t.Errorf("Imports = %v, want %v", fm.Imports, want)
+	}
+
+	// Round trip
+	serialized := fm.Serialize()
+	if !strings.Contains(serialized, "imports:\n  - rates.cm\n  - shared/budget.cm#q3-costs\n") {
+		t.Errorf("Serialize() = %q, missing imports", serialized)
+	}
+	reparsed, _, err := ParseFrontmatter(serialized)
+	if err != nil {
+		t.Fatalf("re-parse failed: %v", err)
+	}
+	if !slices.Equal(reparsed.Imports, want) {
+		t.Errorf("re-parsed Imports = %v, want %v", reparsed.Imports, want)
+	}
+
+	if _, _, err := ParseFrontmatter("---\nimports: ['#anchor']\n---\n"); err == nil {
+		t.Error("import without a path should fail")
+	}
+}
+
+func TestExportedVariables(t *testing.T) {
+	source := `---
+globals:
+  rate: 0.05
+---
+# Budget
+
+## Q3 Costs
+
+<!-- calc: id=rent-block -->
+rent = 1000
+
+
+food = 400
+
+
+### Notes
+
+snacks = 20
+
+
+## Summary
+
+total = rent + food
+`
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	tests := []struct {
+		anchor string
+		want   []string
+	}{
+		{"", []string{"food", "rate", "rent", "snacks", "total"}},
+		{"q3-costs", []string{"food", "rent", "snacks"}}, // Subsections included
+		{"notes", []string{"snacks"}},
+		{"summary", []string{"total"}},
+		{"rent-block", []string{"rent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.anchor, func(t *testing.T) {
+			got, err := doc.ExportedVariables(tt.anchor)
+			if err != nil {
+				t.Fatalf("ExportedVariables failed: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExportedVariables(%q) = %v, want %v", tt.anchor, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := doc.ExportedVariables("missing"); err == nil {
+		t.Error("unknown anchor should fail")
+	}
+}
+
+func TestBlocksAffectedByImport(t *testing.T) {
+	doc, err := NewDocument("a = price * 2\n\n\nb = a + 1\n\n\nc = 5\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	doc.SetImportedVariables(map[string]string{"price": "rates.cm"})
+
+	blocks := doc.GetBlocks()
+	got := doc.BlocksAffectedByImport("rates.cm")
+	want := []string{blocks[0].ID, blocks[1].ID}
+	if !slices.Equal(got, want) {
+		t.Errorf("BlocksAffectedByImport() = %v, want %v", got, want)
+	}
+
+	if got := doc.BlocksAffectedByImport("other.cm"); len(got) != 0 {
+		t.Errorf("BlocksAffectedByImport(other) = %v, want none", got)
+	}
+}
@@ -149,10 +149,11 @@ func evalTimeLiteral(n *ast.TimeLiteral) (types.Type, error) {
 		}
 	}
 
-	// Determine if PM
-	isPM := false
-	if n.Period != nil && strings.ToUpper(*n.Period) == "PM" {
-		isPM = true
+	// Convert 12-hour times (optional period)
+	if n.Period != nil {
+		if hour, err = types.Hour24(hour, *n.Period); err != nil {
+			return nil, err
+		}
 	}
 
 	// Parse UTC offset (optional)
@@ -175,7 +176,15 @@ func evalTimeLiteral(n *ast.TimeLiteral) (types.Type, error) {
 		}
 	}
 
-	return types.NewTime(hour, minute, second, isPM, utcOffsetMinutes)
+	location := types.OffsetZone(utcOffsetMinutes)
+	if n.Zone != "" {
+		if location, err = types.LoadTimeZone(n.Zone); err != nil {
+			return nil, err
+		}
+	}
+
+	// Times are today's, so conversions follow today's daylight saving rules
+	return types.NewTimeIn(hour, minute, second, location, time.Now().In(location))
 }
 
 func evalBooleanLiteral(n *ast.BooleanLiteral) (types.Type, error) {
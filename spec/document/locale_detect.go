@@ -0,0 +1,222 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+)
+
+// LocaleAuto is the locale that asks for the document's number format to be
+// inferred from its literals (see DetectLocale), for documents written
+// elsewhere whose convention isn't declared:
+//
+//	---
+//	locale: auto
+//	---
+const LocaleAuto = "auto"
+
+// LocaleDetection is what DetectLocale infers from a document's literals.
+type LocaleDetection struct {
+	Locale lexer.NumberLocale // Format to read the document with; LocaleUS if nothing decides
+	Found  bool               // Some literal decided the format
+
+	// Ambiguity says why the format is uncertain, empty if it isn't:
+	// literals written both ways, or only literals like 1.234 that read
+	// differently in each. Line is the 1-indexed source line it's about.
+	Ambiguity string
+	Line      int
+}
+
+// numberRun matches digits with the marks that may group or end them.
+// Apostrophes only count between digits, so "the 90's" is a plain 90.
+var numberRun = regexp.MustCompile(`\d+(?:[.,'\x{2019}]\d+)*`)
+
+// spaceGrouped matches numbers grouped by spaces with a decimal comma, as
+// in French (1 000,50); spaces alone are too common in prose to count.
+var spaceGrouped = regexp.MustCompile(`(?:^|[^\d.,])\d{1,3}(?:[ \x{00a0}\x{202f}]\d{3})+,\d+`)
+
+// decimal conventions a literal can show
+const (
+	undecided = iota
+	decimalPoint
+	decimalComma
+)
+
+// DetectLocale infers the number format of source's literals: a decimal
+// comma (1.234,56 or 1,5) or a decimal point (1,234.56 or 1.5). Literals
+// that read the same either way, such as 42 or dates like 16.10.2026, are
+// ignored, as are fenced code blocks. Space grouping makes a decimal-comma
+// document French (fr-FR) rather than German (de-DE), and apostrophe
+// grouping a decimal-point one Swiss (de-CH).
+//
+// When literals disagree, the more common format wins and Ambiguity names
+// the first literal that disagrees. When only literals like 1.234 or 1,000
+// are found, which are a thousand times apart depending on the format, the
+// result is LocaleUS with Ambiguity naming the first of them.
+func DetectLocale(source string) LocaleDetection {
+	type evidence struct {
+		literal string
+		line    int
+	}
+	var first [3]*evidence // First literal showing each convention
+	var counts [3]int
+	spaces, apostrophes := false, false
+
+	fenced := false
+	for i, line := range strings.Split(NormalizeText(source), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		if spaceGrouped.MatchString(line) {
+			spaces = true
+		}
+		for _, loc := range numberRun.FindAllStringIndex(line, -1) {
+			// Digits within a word, such as an identifier like q1, aren't literals
+			if before, _ := utf8.DecodeLastRuneInString(line[:loc[0]]); isWordRune(before) {
+				continue
+			}
+			literal := line[loc[0]:loc[1]]
+			convention, ambiguous, grouped := classifyLiteral(literal)
+			if grouped == '\'' {
+				apostrophes = true
+			}
+			if ambiguous {
+				convention = undecided
+			} else if convention == undecided {
+				continue
+			}
+			counts[convention]++
+			if first[convention] == nil {
+				first[convention] = &evidence{literal, i + 1}
+			}
+		}
+	}
+
+	det := LocaleDetection{Locale: lexer.LocaleUS}
+	point, comma := first[decimalPoint], first[decimalComma]
+	switch {
+	case point == nil && comma == nil:
+		if u := first[undecided]; u != nil {
+			det.Ambiguity = fmt.Sprintf("'%s' could be a decimal or a whole number; declare the document's locale in the frontmatter", u.literal)
+			det.Line = u.line
+		}
+		return det
+	case comma != nil && counts[decimalComma] > counts[decimalPoint]:
+		det.Locale = lexer.LocaleDE
+		if spaces {
+			det.Locale = lexer.LocaleFR
+		}
+		if point != nil {
+			det.Ambiguity = fmt.Sprintf("'%s' is written with a decimal point, but most numbers use a decimal comma (as in '%s')", point.literal, comma.literal)
+			det.Line = point.line
+		}
+	default:
+		if apostrophes {
+			det.Locale = lexer.LocaleCH
+		}
+		if comma != nil {
+			det.Ambiguity = fmt.Sprintf("'%s' is written with a decimal comma, but most numbers use a decimal point (as in '%s')", comma.literal, point.literal)
+			det.Line = comma.line
+		}
+	}
+	det.Found = true
+	return det
+}
+
+// classifyLiteral returns the decimal convention literal shows, if any;
+// whether it is one like 1.234 that reads differently in each; and the
+// mark it groups digits with, if it does.
+func classifyLiteral(literal string) (convention int, ambiguous bool, grouped byte) {
+	var marks []byte
+	var groups []string
+	start := 0
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		groups = append(groups, literal[start:i])
+		if c != '.' && c != ',' {
+			c = '\'' // Either apostrophe, the right one being several bytes
+			for i+1 < len(literal) && (literal[i+1] < '0' || literal[i+1] > '9') {
+				i++
+			}
+		}
+		marks = append(marks, c)
+		start = i + 1
+	}
+	groups = append(groups, literal[start:])
+	if len(marks) == 0 {
+		return undecided, false, 0
+	}
+
+	// validGroups reports whether the first n+1 groups are thousands:
+	// 1 to 3 digits, then 3 each
+	validGroups := func(n int) bool {
+		if len(groups[0]) > 3 || groups[0][0] == '0' {
+			return false
+		}
+		for _, g := range groups[1 : n+1] {
+			if len(g) != 3 {
+				return false
+			}
+		}
+		return true
+	}
+	styleOf := func(decimalMark byte) int {
+		if decimalMark == ',' {
+			return decimalComma
+		}
+		return decimalPoint
+	}
+
+	last := marks[len(marks)-1]
+	group := marks[0]
+	for _, m := range marks[:len(marks)-1] {
+		if m != group {
+			return undecided, false, 0 // e.g. 1.2,3 or 1,2.3.4
+		}
+	}
+
+	switch {
+	case len(marks) == 1 && last == '\'':
+		if validGroups(1) {
+			return undecided, false, '\''
+		}
+		return undecided, false, 0
+	case len(marks) == 1:
+		// One mark is a decimal unless it could also group thousands
+		if len(groups[1]) == 3 && validGroups(1) {
+			return undecided, true, 0
+		}
+		return styleOf(last), false, 0
+	case group == last:
+		// Repeated marks group thousands, so the decimal is the other mark;
+		// otherwise they're a date or version number
+		if !validGroups(len(marks)) {
+			return undecided, false, 0
+		}
+		if last == '\'' {
+			return undecided, false, '\''
+		}
+		if last == '.' {
+			return decimalComma, false, 0
+		}
+		return decimalPoint, false, 0
+	default:
+		// Grouping marks then a different decimal mark
+		if last == '\'' || !validGroups(len(marks)-1) {
+			return undecided, false, 0
+		}
+		return styleOf(last), false, group
+	}
+}
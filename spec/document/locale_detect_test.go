@@ -0,0 +1,77 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		tag       string
+		found     bool
+		ambiguity string
+		line      int
+	}{
+		{"none", "x = 42\ny = x * 2\n", "en-US", false, "", 0},
+		{"decimal point", "price = $1,234.56\nrate = 0.5\n", "en-US", true, "", 0},
+		{"decimal comma", "preis = 1.234,56 €\nsatz = 0,19\n", "de-DE", true, "", 0},
+		{"grouped thousands", "a = 1.234.567\n", "de-DE", true, "", 0},
+		{"space grouping", "prix = 1 234,56\n", "fr-FR", true, "", 0},
+		{"apostrophe grouping", "preis = 1'234.50\n", "de-CH", true, "", 0},
+		{"resolves undecided literals", "a = 1.500\nb = 2,5\n", "de-DE", true, "", 0},
+		{"undecided only", "# Notes\n\na = 1.500\n", "en-US", false, "'1.500' could be", 3},
+		{"conflict", "a = 1,5\nb = 2,25\nc = 3.75\n", "de-DE", true, "'3.75' is written with a decimal point", 3},
+		{"ignores dates and versions", "Updated 16.10.2026 for v1.2.3\n\nq1 = 1,5\n", "de-DE", true, "", 0},
+		{"ignores code", "```\nx = 1.5\n```\ny = 2,5\n", "de-DE", true, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			det := DetectLocale(tt.source)
+			if det.Locale.Tag != tt.tag || det.Found != tt.found || det.Line != tt.line {
+				t.Errorf("DetectLocale = %+v, want %s found=%v line %d", det, tt.tag, tt.found, tt.line)
+			}
+			if (tt.ambiguity == "") != (det.Ambiguity == "") || !strings.Contains(det.Ambiguity, tt.ambiguity) {
+				t.Errorf("Ambiguity = %q, want %q", det.Ambiguity, tt.ambiguity)
+			}
+		})
+	}
+}
+
+func TestLocaleAuto(t *testing.T) {
+	doc, err := NewDocument("---\nlocale: auto\n---\npreis = 1.234,5\nmwst = preis * 0,19\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.NumberLocale().Tag; got != "de-DE" {
+		t.Errorf("NumberLocale = %s, want de-DE", got)
+	}
+	if det, ok := doc.LocaleDetection(); !ok || !det.Found {
+		t.Errorf("LocaleDetection = %+v, %v", det, ok)
+	}
+	if _, ok := doc.GetBlocks()[0].Block.(*CalcBlock); !ok {
+		t.Error("expected a calculation block")
+	}
+	if !strings.Contains(doc.Serialize(), "locale: auto") {
+		t.Errorf("serialized without locale: auto:\n%s", doc.Serialize())
+	}
+
+	// The same as an option, for documents without frontmatter
+	doc, err = NewDocumentWithLocale("prix = 1 234,5\n", LocaleAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.NumberLocale().Tag; got != "fr-FR" {
+		t.Errorf("NumberLocale = %s, want fr-FR", got)
+	}
+
+	// A declared locale isn't detected
+	doc, err = NewDocumentWithLocale("---\nlocale: en-US\n---\nx = 1,5\n", LocaleAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.LocaleDetection(); ok {
+		t.Error("detected a declared locale")
+	}
+}
@@ -0,0 +1,214 @@
+package document
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Block annotations are HTML comments on their own line that attach
+// metadata to the block starting at the next non-empty line:
+//
+//	<!-- calc: label="Budget Summary" tags=finance,q3 -->
+//	total = rent + food
+//
+// Being markdown comments, they are invisible when rendered and stay in the
+// source, so they survive saving and re-parsing unchanged.
+const (
+	annotationPrefix = "<!-- calc:"
+	annotationSuffix = "-->"
+)
+
+// BlockMetadata holds the attributes declared by a block's annotations.
+type BlockMetadata struct {
+	Label string            // label="..." - a human-readable name for the block
+	Tags  []string          // tags=a,b - comma-separated, in declaration order
+	Attrs map[string]string // Any other key=value pairs, e.g. id=summary
+}
+
+// HasTag reports whether the metadata includes tag (case-insensitive).
+func (m *BlockMetadata) HasTag(tag string) bool {
+	if m == nil {
+		return false
+	}
+	return slices.ContainsFunc(m.Tags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
+
+// String returns the annotation line that declares m.
+func (m *BlockMetadata) String() string {
+	var parts []string
+	if m.Label != "" {
+		parts = append(parts, "label="+quoteAnnotationValue(m.Label))
+	}
+	if len(m.Tags) > 0 {
+		parts = append(parts, "tags="+quoteAnnotationValue(strings.Join(m.Tags, ",")))
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.Attrs)) {
+		parts = append(parts, key+"="+quoteAnnotationValue(m.Attrs[key]))
+	}
+	return annotationPrefix + " " + strings.Join(parts, " ") + " " + annotationSuffix
+}
+
+// IsAnnotation reports whether line is a block annotation comment.
+func IsAnnotation(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, annotationPrefix) && strings.HasSuffix(trimmed, annotationSuffix)
+}
+
+// ParseAnnotation parses a block annotation line into metadata.
+// Returns false if line is not an annotation. Values may be bare words or
+// double-quoted; a key without a value is recorded in Attrs as "true".
+func ParseAnnotation(line string) (*BlockMetadata, bool) {
+	if !IsAnnotation(line) {
+		return nil, false
+	}
+	body := strings.TrimSpace(line)
+	body = strings.TrimSuffix(strings.TrimPrefix(body, annotationPrefix), annotationSuffix)
+
+	meta := &BlockMetadata{}
+	for _, field := range splitAnnotationFields(body) {
+		key, value, hasValue := strings.Cut(field, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.Trim(value, `"`)
+		if !hasValue {
+			value = "true"
+		}
+
+		switch key {
+		case "":
+			continue
+		case "label":
+			meta.Label = value
+		case "tags", "tag":
+			for tag := range strings.SplitSeq(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" && !meta.HasTag(tag) {
+					meta.Tags = append(meta.Tags, tag)
+				}
+			}
+		default:
+			if meta.Attrs == nil {
+				meta.Attrs = make(map[string]string)
+			}
+			meta.Attrs[key] = value
+		}
+	}
+	return meta, true
+}
+
+// splitAnnotationFields splits on whitespace outside double quotes.
+func splitAnnotationFields(body string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range body {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// quoteAnnotationValue quotes values that would not survive as bare words.
+func quoteAnnotationValue(value string) string {
+	if value == "" || strings.ContainsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '='
+	}) {
+		return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+	}
+	return value
+}
+
+// merge folds other's attributes into m; other's label and attrs win.
+func (m *BlockMetadata) merge(other *BlockMetadata) {
+	if other.Label != "" {
+		m.Label = other.Label
+	}
+	for _, tag := range other.Tags {
+		if !m.HasTag(tag) {
+			m.Tags = append(m.Tags, tag)
+		}
+	}
+	for key, value := range other.Attrs {
+		if m.Attrs == nil {
+			m.Attrs = make(map[string]string)
+		}
+		m.Attrs[key] = value
+	}
+}
+
+// attachMetadata recomputes every block's metadata from the annotation lines
+// in the document. Each annotation applies to the block containing the next
+// non-empty, non-annotation line, which may be the annotation's own block
+// (text following the comment) or the next one (a calculation, since the
+// comment itself is never a calculation). Called after any change to blocks.
+func (d *Document) attachMetadata() {
+	var pending *BlockMetadata
+	for _, node := range d.blocks {
+		var meta *BlockMetadata
+		for _, line := range node.Block.Source() {
+			if annotation, ok := ParseAnnotation(line); ok {
+				if pending == nil {
+					pending = &BlockMetadata{}
+				}
+				pending.merge(annotation)
+				continue
+			}
+			if pending != nil && !isEmptyLine(line) {
+				if meta == nil {
+					meta = &BlockMetadata{}
+				}
+				meta.merge(pending)
+				pending = nil
+			}
+		}
+		setBlockMetadata(node.Block, meta)
+	}
+}
+
+// setBlockMetadata stores meta on a block of either type.
+func setBlockMetadata(block Block, meta *BlockMetadata) {
+	switch b := block.(type) {
+	case *CalcBlock:
+		b.metadata = meta
+	case *TextBlock:
+		b.metadata = meta
+	case interface{ setMetadata(*BlockMetadata) }:
+		b.setMetadata(meta)
+	}
+}
+
+// FindBlocksByTag returns the blocks annotated with tag, in document order.
+func (d *Document) FindBlocksByTag(tag string) []*BlockNode {
+	var found []*BlockNode
+	for _, node := range d.blocks {
+		if node.Block.Metadata().HasTag(tag) {
+			found = append(found, node)
+		}
+	}
+	return found
+}
+
+// FindBlockByLabel returns the first block annotated with label.
+func (d *Document) FindBlockByLabel(label string) (*BlockNode, bool) {
+	for _, node := range d.blocks {
+		if meta := node.Block.Metadata(); meta != nil && meta.Label == label {
+			return node, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,122 @@
+package document
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseAnnotation(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantOK    bool
+		wantLabel string
+		wantTags  []string
+		wantAttrs map[string]string
+	}{
+		{`<!-- calc: label="Budget Summary" tags=finance -->`, true, "Budget Summary", []string{"finance"}, nil},
+		{`  <!-- calc: tags="finance, q3" id=summary -->`, true, "", []string{"finance", "q3"}, map[string]string{"id": "summary"}},
+		{`<!-- calc: hidden -->`, true, "", nil, map[string]string{"hidden": "true"}},
+		{`<!-- calc: -->`, true, "", nil, nil},
+		{`<!-- just a comment -->`, false, "", nil, nil},
+		{`<!-- calc: label=x`, false, "", nil, nil},
+		{`x = 5`, false, "", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			meta, ok := ParseAnnotation(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseAnnotation() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if meta.Label != tt.wantLabel {
+				t.Errorf("Label = %q, want %q", meta.Label, tt.wantLabel)
+			}
+			if !slices.Equal(meta.Tags, tt.wantTags) {
+				t.Errorf("Tags = %v, want %v", meta.Tags, tt.wantTags)
+			}
+			for key, want := range tt.wantAttrs {
+				if meta.Attrs[key] != want {
+					t.Errorf("Attrs[%q] = %q, want %q", key, meta.Attrs[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBlockMetadata_StringRoundTrip(t *testing.T) {
+	meta := &BlockMetadata{
+		Label: "Budget Summary",
+		Tags:  []string{"finance", "q3"},
+		Attrs: map[string]string{"id": "summary"},
+	}
+
+	line := meta.String()
+	if line != `<!-- calc: label="Budget Summary" tags=finance,q3 id=summary -->` {
+		t.Errorf("String() = %q", line)
+	}
+
+	parsed, ok := ParseAnnotation(line)
+	if !ok || parsed.Label != meta.Label || !slices.Equal(parsed.Tags, meta.Tags) || parsed.Attrs["id"] != "summary" {
+		t.Errorf("round trip = %+v, want %+v", parsed, meta)
+	}
+}
+
+func TestDocumentBlockMetadata(t *testing.T) {
+	source := `# Budget
+
+<!-- calc: label="Budget Summary" tags=finance -->
+rent = $1000
+food = $400
+
+
+<!-- calc: label=Notes tags=prose -->
+These numbers are monthly.
+
+
+total = rent + food`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument() error = %v", err)
+	}
+
+	node, ok := doc.FindBlockByLabel("Budget Summary")
+	if !ok {
+		t.Fatal("expected a block labeled Budget Summary")
+	}
+	cb, ok := node.Block.(*CalcBlock)
+	if !ok {
+		t.Fatalf("annotation should attach to the following calc block, got %T", node.Block)
+	}
+	if cb.Source()[0] != "rent = $1000" {
+		t.Errorf("annotated block source = %v", cb.Source())
+	}
+
+	// An annotation directly above text belongs to that text block
+	notes, ok := doc.FindBlockByLabel("Notes")
+	if !ok || notes.Block.Type() != BlockText {
+		t.Fatalf("expected a text block labeled Notes")
+	}
+
+	if found := doc.FindBlocksByTag("FINANCE"); len(found) != 1 || found[0].ID != node.ID {
+		t.Errorf("FindBlocksByTag(FINANCE) = %v", found)
+	}
+
+	// Unannotated blocks have no metadata
+	blocks := doc.GetBlocks()
+	if last := blocks[len(blocks)-1]; last.Block.Metadata() != nil {
+		t.Errorf("expected no metadata on the last block, got %+v", last.Block.Metadata())
+	}
+
+	// Editing the annotation updates the metadata
+	textID := blocks[0].ID
+	if _, err := doc.ReplaceBlockSource(textID, []string{"# Budget", "", `<!-- calc: label="Monthly" -->`}); err != nil {
+		t.Fatalf("ReplaceBlockSource() error = %v", err)
+	}
+	if cb.Metadata() == nil || cb.Metadata().Label != "Monthly" || cb.Metadata().HasTag("finance") {
+		t.Errorf("metadata after edit = %+v, want label Monthly without tags", cb.Metadata())
+	}
+}
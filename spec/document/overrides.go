@@ -0,0 +1,36 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Override replaces a frontmatter global or exchange rate for the next
+// evaluation without editing the source, as `cm eval --set` does. name is
+// a global the frontmatter declares, or an exchange rate key it declares
+// such as USD_EUR. value is a literal, as the global would be written in
+// the document's number locale, or the rate as a plain decimal.
+//
+// Names the frontmatter doesn't declare are errors, so a misspelled
+// override can't be silently ignored.
+func (d *Document) Override(name, value string) error {
+	fm := d.frontmatter
+	switch {
+	case fm.HasGlobal(name):
+		if _, err := parseGlobalValue(name, value, d.NumberLocale()); err != nil {
+			return err
+		}
+		fm.SetGlobal(name, value)
+		return nil
+
+	case fm.HasExchangeRate(name):
+		rate, err := decimal.NewFromString(value)
+		if err != nil || !rate.IsPositive() {
+			return fmt.Errorf("exchange rate '%s': '%s' is not a positive number", name, value)
+		}
+		fm.SetExchangeRate(name, rate)
+		return nil
+	}
+	return fmt.Errorf("'%s' is not a global or exchange rate in the document's frontmatter", name)
+}
@@ -0,0 +1,53 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOverride(t *testing.T) {
+	source := "---\nglobals:\n  tax_rate: 0.25\nexchange:\n  USD_EUR: 0.92\n---\nx = 1\n"
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Override("tax_rate", "0.28"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Override("usd_eur", "0.91"); err != nil {
+		t.Fatal(err)
+	}
+	fm := doc.GetFrontmatter()
+	if fm.Globals["tax_rate"] != "0.28" {
+		t.Errorf("tax_rate = %q", fm.Globals["tax_rate"])
+	}
+	if rate, _ := fm.GetExchangeRate("USD", "EUR"); rate.String() != "0.91" {
+		t.Errorf("USD_EUR = %s", rate)
+	}
+
+	tests := []struct {
+		name, value, want string
+	}{
+		{"tax_rat", "0.3", "not a global or exchange rate"},
+		{"GBP_EUR", "1.1", "not a global or exchange rate"},
+		{"tax_rate", "0.2 + 0.1", "only literal values"},
+		{"USD_EUR", "abc", "not a positive number"},
+		{"USD_EUR", "-1", "not a positive number"},
+	}
+	for _, tt := range tests {
+		if err := doc.Override(tt.name, tt.value); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Override(%q, %q) = %v, want %q", tt.name, tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestOverrideWithoutFrontmatter(t *testing.T) {
+	doc, err := NewDocument("x = 1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Override("x", "2"); err == nil {
+		t.Error("expected an error for a document without frontmatter")
+	}
+}
@@ -0,0 +1,213 @@
+package document
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/CalcMark/go-calcmark/impl/interpreter"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// PreviewResult describes what a ReplaceBlockSource call would change,
+// computed without modifying the document.
+type PreviewResult struct {
+	// ModifiedBlockID is the block that would be directly modified
+	ModifiedBlockID string
+
+	// AffectedBlockIDs are blocks whose results or errors would change,
+	// in document order. Always includes the modified block.
+	AffectedBlockIDs []string
+
+	// Variables lists every variable whose value would change, sorted by name
+	Variables []VariableChange
+
+	// Results holds the would-be per-statement results of each affected block
+	Results map[string][]types.Type
+
+	// Errors holds the would-be evaluation error of each affected block that fails
+	Errors map[string]error
+}
+
+// VariableChange is a single variable's value before and after a previewed edit.
+// Before is nil for a newly defined variable; After is nil for a removed one.
+type VariableChange struct {
+	Name   string
+	Before types.Type
+	After  types.Type
+}
+
+// evalSnapshot captures the outcome of evaluating a scratch copy of a document.
+type evalSnapshot struct {
+	results map[string][]types.Type
+	errors  map[string]error
+	vars    map[string]types.Type
+}
+
+// PreviewEvaluator evaluates the calculation blocks of a scratch document
+// for a preview, in document order, leaving each block's results or error
+// on the block. Unlike Evaluate, it carries on past failing blocks. It
+// returns the variables defined once every block has been evaluated.
+type PreviewEvaluator func(scratch *Document) (map[string]types.Type, error)
+
+// PreviewReplaceBlockSource reports which blocks and variables would change if
+// blockID's source were replaced with newSource, and their new values.
+// The document itself is not modified: both the current and edited versions
+// are evaluated on scratch copies, so this is safe for "preview before apply"
+// UIs and optimistic updates.
+//
+// Unlike Evaluate, evaluation continues past failing blocks so that the
+// preview shows every downstream error the edit would introduce.
+//
+// The scratch copies are evaluated by the document alone, without frontmatter
+// imports, registered functions, a data policy, or block timeouts. Use
+// PreviewReplaceBlockSourceWith (or the evaluator's PreviewReplaceBlockSource
+// in impl/document) for a preview that sees those.
+func (d *Document) PreviewReplaceBlockSource(blockID string, newSource []string) (*PreviewResult, error) {
+	return d.PreviewReplaceBlockSourceWith(blockID, newSource, nil)
+}
+
+// PreviewReplaceBlockSourceWith is PreviewReplaceBlockSource with the scratch
+// copies evaluated by eval; nil evaluates them as PreviewReplaceBlockSource does.
+func (d *Document) PreviewReplaceBlockSourceWith(blockID string, newSource []string, eval PreviewEvaluator) (*PreviewResult, error) {
+	if _, ok := d.blockIndex[blockID]; !ok {
+		return nil, fmt.Errorf("block not found: %s", blockID)
+	}
+
+	before, err := d.evaluateSnapshot("", nil, eval)
+	if err != nil {
+		return nil, err
+	}
+	after, err := d.evaluateSnapshot(blockID, newSource, eval)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PreviewResult{
+		ModifiedBlockID: blockID,
+		Results:         make(map[string][]types.Type),
+		Errors:          make(map[string]error),
+	}
+
+	for _, node := range d.blocks {
+		if node.ID != blockID && snapshotBlockEqual(before, after, node.ID) {
+			continue
+		}
+		result.AffectedBlockIDs = append(result.AffectedBlockIDs, node.ID)
+		if results, ok := after.results[node.ID]; ok {
+			result.Results[node.ID] = results
+		}
+		if err := after.errors[node.ID]; err != nil {
+			result.Errors[node.ID] = err
+		}
+	}
+
+	names := slices.Sorted(maps.Keys(before.vars))
+	for name := range after.vars {
+		if _, ok := before.vars[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		oldVal, newVal := before.vars[name], after.vars[name]
+		if valueString(oldVal) == valueString(newVal) {
+			continue
+		}
+		result.Variables = append(result.Variables, VariableChange{
+			Name:   name,
+			Before: oldVal,
+			After:  newVal,
+		})
+	}
+
+	return result, nil
+}
+
+// evaluateSnapshot evaluates a scratch copy of the document's calc blocks,
+// optionally with one block's source replaced, with eval or, when it is nil,
+// evaluatePreview. The receiver is not modified.
+func (d *Document) evaluateSnapshot(replaceID string, replaceSource []string, eval PreviewEvaluator) (*evalSnapshot, error) {
+	scratch := &Document{
+		blockIndex:  make(map[string]*BlockNode),
+		varToBlocks: make(map[string][]string),
+		env:         interpreter.NewEnvironment(),
+		frontmatter: d.frontmatter,
+		envLookup:   d.envLookup,
+	}
+	for _, node := range d.blocks {
+		// A text block being previewed as calculation (or vice versa) keeps its
+		// type here; block type changes are decided by the detector on re-parse.
+		if node.Block.Type() != BlockCalculation {
+			continue
+		}
+		source := node.Block.Source()
+		if node.ID == replaceID {
+			source = replaceSource
+		}
+		copied := &BlockNode{ID: node.ID, Block: NewCalcBlock(slices.Clone(source))}
+		scratch.blocks = append(scratch.blocks, copied)
+		scratch.blockIndex[node.ID] = copied
+	}
+
+	if eval == nil {
+		eval = evaluatePreview
+	}
+	vars, err := eval(scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &evalSnapshot{
+		results: make(map[string][]types.Type),
+		errors:  make(map[string]error),
+		vars:    maps.Clone(vars),
+	}
+	for _, node := range scratch.blocks {
+		cb := node.Block.(*CalcBlock)
+		if err := cb.Error(); err != nil {
+			snap.errors[node.ID] = err
+			continue
+		}
+		snap.results[node.ID] = cb.Results()
+	}
+	return snap, nil
+}
+
+// evaluatePreview is the PreviewEvaluator the document uses on its own.
+func evaluatePreview(scratch *Document) (map[string]types.Type, error) {
+	if err := scratch.ApplyFrontmatter(scratch.env); err != nil {
+		return nil, err
+	}
+	for _, node := range scratch.blocks {
+		if cb, ok := node.Block.(*CalcBlock); ok {
+			if err := scratch.evaluateCalcBlock(node.ID, cb); err != nil {
+				cb.SetError(err) // With its stage, as Evaluate reports it
+			}
+		}
+	}
+	return scratch.env.GetAllVariables(), nil
+}
+
+// snapshotBlockEqual reports whether a block has the same results and error
+// in both snapshots.
+func snapshotBlockEqual(a, b *evalSnapshot, blockID string) bool {
+	return snapshotsEqual(a, blockID, b, blockID)
+}
+
+// valueString returns a comparable representation of a possibly-nil value.
+func valueString(v types.Type) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// errorString returns a comparable representation of a possibly-nil error.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
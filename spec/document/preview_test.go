@@ -0,0 +1,126 @@
+package document
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// findCalcBlock returns the ID of the first calc block whose source contains text.
+func findCalcBlock(t *testing.T, doc *Document, text string) string {
+	t.Helper()
+	for _, node := range doc.GetBlocks() {
+		if cb, ok := node.Block.(*CalcBlock); ok && strings.Contains(strings.Join(cb.Source(), "\n"), text) {
+			return node.ID
+		}
+	}
+	t.Fatalf("no calc block contains %q", text)
+	return ""
+}
+
+// TestPreviewReplaceBlockSource tests that a preview reports changes without applying them
+func TestPreviewReplaceBlockSource(t *testing.T) {
+	source := `x = 10
+
+
+y = x + 5
+
+
+z = 3`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	xID := findCalcBlock(t, doc, "x = 10")
+	yID := findCalcBlock(t, doc, "y = x")
+	zID := findCalcBlock(t, doc, "z = 3")
+
+	node, _ := doc.GetBlock(xID)
+	originalSource := slices.Clone(node.Block.Source())
+
+	preview, err := doc.PreviewReplaceBlockSource(xID, []string{"x = 100"})
+	if err != nil {
+		t.Fatalf("PreviewReplaceBlockSource failed: %v", err)
+	}
+
+	if preview.ModifiedBlockID != xID {
+		t.Errorf("ModifiedBlockID = %s, want %s", preview.ModifiedBlockID, xID)
+	}
+	if !slices.Equal(preview.AffectedBlockIDs, []string{xID, yID}) {
+		t.Errorf("AffectedBlockIDs = %v, want [x y] (z unchanged: %s)", preview.AffectedBlockIDs, zID)
+	}
+
+	want := map[string][2]string{"x": {"10", "100"}, "y": {"15", "105"}}
+	if len(preview.Variables) != len(want) {
+		t.Fatalf("Variables = %v, want changes to x and y", preview.Variables)
+	}
+	for _, change := range preview.Variables {
+		w, ok := want[change.Name]
+		if !ok {
+			t.Errorf("unexpected change to %s", change.Name)
+			continue
+		}
+		if change.Before.String() != w[0] || change.After.String() != w[1] {
+			t.Errorf("%s: %s → %s, want %s → %s", change.Name, change.Before, change.After, w[0], w[1])
+		}
+	}
+
+	if got := preview.Results[yID]; len(got) != 1 || got[0].String() != "105" {
+		t.Errorf("Results[y] = %v, want [105]", got)
+	}
+
+	// Document must be untouched
+	if got := node.Block.Source(); !slices.Equal(got, originalSource) {
+		t.Errorf("source changed by preview: %v", got)
+	}
+	yNode, _ := doc.GetBlock(yID)
+	if got := yNode.Block.(*CalcBlock).LastValue().String(); got != "15" {
+		t.Errorf("y value changed by preview: %s", got)
+	}
+}
+
+// TestPreviewReplaceBlockSourceErrors tests that previews surface downstream errors
+func TestPreviewReplaceBlockSourceErrors(t *testing.T) {
+	source := `x = 10
+
+
+y = x + 5`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	xID := findCalcBlock(t, doc, "x = 10")
+	yID := findCalcBlock(t, doc, "y = x")
+
+	// Renaming x removes it; y can no longer evaluate
+	preview, err := doc.PreviewReplaceBlockSource(xID, []string{"w = 10"})
+	if err != nil {
+		t.Fatalf("PreviewReplaceBlockSource failed: %v", err)
+	}
+
+	if preview.Errors[yID] == nil {
+		t.Errorf("expected an error for y, got Errors = %v", preview.Errors)
+	}
+
+	changed := make(map[string]VariableChange)
+	for _, c := range preview.Variables {
+		changed[c.Name] = c
+	}
+	if c, ok := changed["x"]; !ok || c.After != nil {
+		t.Errorf("expected x to be removed, got %+v", c)
+	}
+	if c, ok := changed["w"]; !ok || c.Before != nil {
+		t.Errorf("expected w to be added, got %+v", c)
+	}
+
+	if _, err := doc.PreviewReplaceBlockSource("missing", nil); err == nil {
+		t.Error("expected error for unknown block ID")
+	}
+}
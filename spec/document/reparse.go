@@ -0,0 +1,226 @@
+package document
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Reparse replaces the document's content with source, which may include
+// frontmatter, without rebuilding the document from scratch. Blocks whose
+// type and source are unchanged keep their ID and results, so caches keyed
+// by block ID and incremental evaluation survive the edit. Unmatched blocks
+// between two unchanged ones are treated as edited in place, as with
+// ReplaceBlockSource, when they have the same type; the rest are removed or
+// added with new IDs.
+//
+// The UpdateResult lists the edited and added blocks and every block that
+// depends (transitively) on a variable they or removed blocks defined, in
+// dependency order, and marks them dirty. If the frontmatter changed, every
+// calculation block is affected. ModifiedBlockID is the first edited or
+// added block, if any.
+func (d *Document) Reparse(source string) (*UpdateResult, error) {
+	fm, remaining, err := ParseFrontmatter(source)
+	if err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+	blocks, err := d.detectorFor(fm).DetectBlocks(remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	newNodes := make([]*BlockNode, len(blocks))
+	for i, block := range blocks {
+		newNodes[i] = &BlockNode{Block: block}
+	}
+
+	var (
+		result      []*BlockNode
+		touched     []string // Blocks edited or added
+		changedVars []string // Variables defined by edited or removed blocks before the edit
+	)
+
+	// flush keeps the unmatched blocks between two matches, reusing old
+	// nodes for in-place edits
+	flush := func(removed, added []*BlockNode) {
+		for len(removed) > 0 && len(added) > 0 && removed[0].Block.Type() == added[0].Block.Type() {
+			node := removed[0]
+			if cb, ok := node.Block.(*CalcBlock); ok {
+				changedVars = append(changedVars, cb.Variables()...)
+			}
+			setBlockSource(node.Block, added[0].Block.Source())
+			result = append(result, node)
+			touched = append(touched, node.ID)
+			removed, added = removed[1:], added[1:]
+		}
+		for _, node := range removed {
+			if cb, ok := node.Block.(*CalcBlock); ok {
+				changedVars = append(changedVars, cb.Variables()...)
+			}
+		}
+		for _, node := range added {
+			node.ID = uuid.New().String()
+			result = append(result, node)
+			touched = append(touched, node.ID)
+		}
+	}
+
+	i, j := 0, 0
+	for _, pair := range matchBlocks(d.blocks, newNodes) {
+		flush(d.blocks[i:pair[0]], newNodes[j:pair[1]])
+		result = append(result, d.blocks[pair[0]])
+		i, j = pair[0]+1, pair[1]+1
+	}
+	flush(d.blocks[i:], newNodes[j:])
+
+	frontmatterChanged := d.frontmatter.Serialize() != fm.Serialize()
+	d.frontmatter = fm
+	d.blocks = result
+	d.blockIndex = make(map[string]*BlockNode, len(result))
+	for _, node := range result {
+		d.blockIndex[node.ID] = node
+	}
+	if err := d.rebuildDependencies(); err != nil {
+		return nil, err
+	}
+	d.attachMetadata()
+
+	if frontmatterChanged {
+		for _, node := range d.blocks {
+			if node.Block.Type() == BlockCalculation {
+				touched = append(touched, node.ID)
+			}
+		}
+	}
+
+	update := &UpdateResult{
+		AffectedBlockIDs: d.affectedBlocks(touched, changedVars),
+	}
+	if len(touched) > 0 {
+		update.ModifiedBlockID = touched[0]
+	}
+	return update, nil
+}
+
+// ReplaceLine replaces one line of the document body with text. lineNo is
+// 0-indexed over the lines of every block's source in order, which is the
+// document without its frontmatter. The line stays in its block and block
+// types are not redetected; use Reparse when an edit may split, merge, or
+// retype blocks.
+//
+// Only the edited line of a calculation block is re-lexed and re-parsed
+// (see DependencyAnalyzer.AnalyzeLine), which keeps keystroke-level edits
+// cheap in large documents. The UpdateResult is as for Reparse; syntax
+// errors are left on the block for evaluation to report.
+func (d *Document) ReplaceLine(lineNo int, text string) (*UpdateResult, error) {
+	if strings.ContainsRune(text, '\n') {
+		return nil, fmt.Errorf("line %d: text contains a newline", lineNo)
+	}
+	node, line, ok := d.lineAt(lineNo)
+	if !ok {
+		return nil, fmt.Errorf("line %d out of range", lineNo)
+	}
+
+	source := slices.Clone(node.Block.Source())
+	source[line] = text
+
+	cb, ok := node.Block.(*CalcBlock)
+	if !ok {
+		setBlockSource(node.Block, source)
+		d.attachMetadata()
+		return &UpdateResult{
+			ModifiedBlockID:  node.ID,
+			AffectedBlockIDs: []string{node.ID},
+		}, nil
+	}
+
+	oldVars := cb.Variables()
+	cb.source = source // Keep the cached tokens for AnalyzeLine
+	cb.SetDirty(true)
+	if err := NewDependencyAnalyzerWithLocale(d.NumberLocale()).AnalyzeLine(cb, line); err != nil {
+		cb.SetError(err)
+	}
+	d.linkDependencies()
+	d.attachMetadata()
+
+	return &UpdateResult{
+		ModifiedBlockID:  node.ID,
+		AffectedBlockIDs: d.affectedBlocks([]string{node.ID}, oldVars),
+	}, nil
+}
+
+// lineAt returns the block holding body line lineNo and the line's index
+// within that block.
+func (d *Document) lineAt(lineNo int) (*BlockNode, int, bool) {
+	if lineNo < 0 {
+		return nil, 0, false
+	}
+	for _, node := range d.blocks {
+		n := len(node.Block.Source())
+		if lineNo < n {
+			return node, lineNo, true
+		}
+		lineNo -= n
+	}
+	return nil, 0, false
+}
+
+// setBlockSource replaces a block's source and marks it dirty.
+func setBlockSource(block Block, source []string) {
+	switch b := block.(type) {
+	case *CalcBlock:
+		b.source = source
+		b.tokens = nil
+	case *TextBlock:
+		b.source = source
+	}
+	block.SetDirty(true)
+}
+
+// affectedBlocks returns the touched blocks that still exist plus every
+// block depending (transitively) on changedVars or on a variable a touched
+// block now defines, in dependency order, and marks them dirty.
+// Dependencies must be up to date.
+func (d *Document) affectedBlocks(touched, changedVars []string) []string {
+	changedVars = append([]string(nil), changedVars...)
+	var affected []string
+	for _, id := range touched {
+		node, ok := d.blockIndex[id]
+		if !ok {
+			continue // Deleted since
+		}
+		affected = append(affected, id)
+		if cb, ok := node.Block.(*CalcBlock); ok {
+			changedVars = append(changedVars, cb.Variables()...)
+		}
+	}
+
+	// The graph only links dependencies that are still defined, so find
+	// readers of removed variables directly
+	changed := make(map[string]bool, len(changedVars))
+	for _, name := range changedVars {
+		changed[name] = true
+	}
+	for _, node := range d.blocks {
+		cb, ok := node.Block.(*CalcBlock)
+		if !ok {
+			continue
+		}
+		for _, dep := range cb.Dependencies() {
+			if changed[dep] {
+				affected = append(affected, node.ID)
+				changedVars = append(changedVars, cb.Variables()...)
+				break
+			}
+		}
+	}
+	affected = append(affected, d.GetTransitiveDependents(uniqueStrings(changedVars))...)
+
+	ordered := d.GetBlocksInDependencyOrder(uniqueStrings(affected))
+	for _, id := range ordered {
+		d.blockIndex[id].Block.SetDirty(true)
+	}
+	return ordered
+}
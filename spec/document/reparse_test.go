@@ -0,0 +1,204 @@
+package document
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReparse(t *testing.T) {
+	doc := mustDocument(t, `# Budget
+
+rent = 1000
+
+
+food = 400
+
+
+total = rent + food
+
+
+Old notes
+`)
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	before := slices.Clone(doc.GetBlocks())
+	rentID := findCalcBlock(t, doc, "rent = 1000")
+	foodID := findCalcBlock(t, doc, "food = 400")
+	totalID := findCalcBlock(t, doc, "total = rent")
+
+	result, err := doc.Reparse(`# Budget
+
+rent = 1200
+
+
+food = 400
+
+
+total = rent + food
+
+
+fun = 100
+`)
+	if err != nil {
+		t.Fatalf("Reparse failed: %v", err)
+	}
+
+	blocks := doc.GetBlocks()
+	if len(blocks) != 5 {
+		t.Fatalf("got %d blocks, want 5", len(blocks))
+	}
+	// Unchanged and edited blocks keep their IDs; the replaced text block does not
+	for i := range 4 {
+		if blocks[i].ID != before[i].ID {
+			t.Errorf("block %d ID changed", i)
+		}
+	}
+	if blocks[4].ID == before[4].ID {
+		t.Error("added block reused a removed block's ID")
+	}
+	if _, ok := doc.GetBlock(before[4].ID); ok {
+		t.Error("removed block is still indexed")
+	}
+
+	if result.ModifiedBlockID != rentID {
+		t.Errorf("ModifiedBlockID = %s, want the rent block", result.ModifiedBlockID)
+	}
+	want := []string{rentID, totalID, blocks[4].ID}
+	if !slices.Equal(result.AffectedBlockIDs, want) {
+		t.Errorf("AffectedBlockIDs = %v, want %v", result.AffectedBlockIDs, want)
+	}
+
+	// Unaffected blocks keep their results
+	food := blocks[2].Block.(*CalcBlock)
+	if blocks[2].ID != foodID || food.IsDirty() || food.LastValue() == nil {
+		t.Error("unchanged food block lost its results")
+	}
+
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if got := blocks[3].Block.(*CalcBlock).LastValue().String(); got != "1600" {
+		t.Errorf("total = %s, want 1600", got)
+	}
+}
+
+func TestReparseRemovedVariable(t *testing.T) {
+	doc := mustDocument(t, "a = 1\n\n\nb = 2\n\n\nc = a + b\n")
+	cID := findCalcBlock(t, doc, "c = a")
+
+	result, err := doc.Reparse("b = 2\n\n\nc = a + b\n")
+	if err != nil {
+		t.Fatalf("Reparse failed: %v", err)
+	}
+	// c used a, which no longer exists
+	if !slices.Equal(result.AffectedBlockIDs, []string{cID}) {
+		t.Errorf("AffectedBlockIDs = %v, want only c", result.AffectedBlockIDs)
+	}
+}
+
+func TestReparseFrontmatter(t *testing.T) {
+	doc := mustDocument(t, "---\nglobals:\n  rate: 0.1\n---\nx = 100 * rate\n\n\ny = 5\n")
+	ids := []string{doc.GetBlocks()[0].ID, doc.GetBlocks()[1].ID}
+
+	result, err := doc.Reparse("---\nglobals:\n  rate: 0.2\n---\nx = 100 * rate\n\n\ny = 5\n")
+	if err != nil {
+		t.Fatalf("Reparse failed: %v", err)
+	}
+	if !slices.Equal(result.AffectedBlockIDs, ids) {
+		t.Errorf("AffectedBlockIDs = %v, want every block after a frontmatter change", result.AffectedBlockIDs)
+	}
+	if got := doc.GetFrontmatter().Globals["rate"]; got != "0.2" {
+		t.Errorf("rate = %q, want 0.2", got)
+	}
+
+	// Same text again changes nothing
+	result, err = doc.Reparse("---\nglobals:\n  rate: 0.2\n---\nx = 100 * rate\n\n\ny = 5\n")
+	if err != nil {
+		t.Fatalf("Reparse failed: %v", err)
+	}
+	if len(result.AffectedBlockIDs) != 0 || result.ModifiedBlockID != "" {
+		t.Errorf("Reparse of identical source = %+v, want no changes", result)
+	}
+}
+
+func TestReplaceLine(t *testing.T) {
+	doc := mustDocument(t, "# Budget\n\n\nrent = 1000\nfood = 400\n\n\ntotal = rent + food\n\n\nspare = 50\n")
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	rentID := findCalcBlock(t, doc, "rent = 1000")
+	totalID := findCalcBlock(t, doc, "total = rent")
+	spare := doc.GetBlocks()[3].Block.(*CalcBlock)
+
+	// Line 4 is "food = 400", the second line of the rent block
+	result, err := doc.ReplaceLine(4, "food = 600")
+	if err != nil {
+		t.Fatalf("ReplaceLine failed: %v", err)
+	}
+	if result.ModifiedBlockID != rentID {
+		t.Errorf("ModifiedBlockID = %s, want the rent block", result.ModifiedBlockID)
+	}
+	if want := []string{rentID, totalID}; !slices.Equal(result.AffectedBlockIDs, want) {
+		t.Errorf("AffectedBlockIDs = %v, want %v", result.AffectedBlockIDs, want)
+	}
+	if spare.IsDirty() {
+		t.Error("unrelated block marked dirty")
+	}
+
+	// Further edits to the same block reuse its tokens
+	rent, _ := doc.GetBlock(rentID)
+	cb := rent.Block.(*CalcBlock)
+	if cb.tokens == nil {
+		t.Fatal("tokens not kept after ReplaceLine")
+	}
+	for _, text := range []string{"food = 600 +", "food = 600 + bonus", ""} {
+		if _, err := doc.ReplaceLine(4, text); err != nil {
+			t.Fatalf("ReplaceLine(%q) failed: %v", text, err)
+		}
+	}
+	if _, err := doc.ReplaceLine(4, "food = 700"); err != nil {
+		t.Fatalf("ReplaceLine failed: %v", err)
+	}
+
+	// Incremental analysis matches analyzing the block from scratch
+	fresh := NewCalcBlock(slices.Clone(cb.Source()))
+	if err := NewDependencyAnalyzer().AnalyzeBlock(fresh); err != nil {
+		t.Fatalf("AnalyzeBlock failed: %v", err)
+	}
+	if !slices.Equal(cb.Variables(), fresh.Variables()) || len(cb.Statements()) != len(fresh.Statements()) {
+		t.Errorf("variables %v, %d statements; want %v, %d",
+			cb.Variables(), len(cb.Statements()), fresh.Variables(), len(fresh.Statements()))
+	}
+
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	total, _ := doc.GetBlock(totalID)
+	if got := total.Block.(*CalcBlock).LastValue().String(); got != "1700" {
+		t.Errorf("total = %s, want 1700", got)
+	}
+}
+
+func TestReplaceLineText(t *testing.T) {
+	doc := mustDocument(t, "# Budget\n\n\nrent = 1000\n")
+	heading := doc.GetBlocks()[0]
+
+	result, err := doc.ReplaceLine(0, "# Monthly budget")
+	if err != nil {
+		t.Fatalf("ReplaceLine failed: %v", err)
+	}
+	if !slices.Equal(result.AffectedBlockIDs, []string{heading.ID}) {
+		t.Errorf("AffectedBlockIDs = %v, want only the heading", result.AffectedBlockIDs)
+	}
+	if got := heading.Block.Source()[0]; got != "# Monthly budget" {
+		t.Errorf("heading = %q", got)
+	}
+
+	if _, err := doc.ReplaceLine(99, "x"); err == nil {
+		t.Error("expected error for a line out of range")
+	}
+	if _, err := doc.ReplaceLine(0, "a\nb"); err == nil {
+		t.Error("expected error for text containing a newline")
+	}
+}
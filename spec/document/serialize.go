@@ -0,0 +1,45 @@
+package document
+
+import (
+	"slices"
+	"strings"
+)
+
+// originalSource records the text a document was created from.
+type originalSource struct {
+	text        string    // The whole source
+	style       TextStyle // Byte order mark and line ending of text
+	header      string    // The frontmatter as written, delimiters included
+	frontmatter string    // Frontmatter.Serialize() of the parsed frontmatter
+	body        string    // The source after the frontmatter
+}
+
+// Serialize returns the document's source, frontmatter included.
+//
+// A document that hasn't been edited serializes to exactly the source it
+// was created from, byte for byte: trailing whitespace, runs of blank
+// lines, line endings, a byte order mark, and the frontmatter's formatting,
+// comments, and key order all survive, so saving an unchanged file never
+// produces a diff. After edits, the frontmatter is kept as written unless
+// it changed (see Frontmatter.Serialize), and the text keeps the original's
+// byte order mark and line ending (see TextStyle). Use NormalizeText on
+// the result for "\n" endings and no byte order mark.
+func (d *Document) Serialize() string {
+	var lines []string
+	for _, node := range d.blocks {
+		lines = append(lines, node.Block.Source()...)
+	}
+
+	frontmatter := d.frontmatter.Serialize()
+	header := frontmatter
+	if frontmatter == d.original.frontmatter {
+		header = d.original.header
+	}
+
+	// Block lines split at any line terminator; compare them to the body
+	// split the same way
+	if header == d.original.header && slices.Equal(lines, splitLines(d.original.body)) {
+		return d.original.text
+	}
+	return d.original.style.Apply(header + strings.Join(lines, "\n"))
+}
@@ -0,0 +1,147 @@
+package document
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// roundTripCorpus lists sources whose block assembly has been known to
+// lose or move lines, beside the repository's example documents.
+var roundTripCorpus = map[string]string{
+	"empty":                  "",
+	"only newlines":          "\n\n\n",
+	"no trailing newline":    "x = 1\ny = 2",
+	"trailing whitespace":    "x = 1   \n# Title\t\n",
+	"blank line runs":        "x = 1\n\n\n\n\ny = 2\n\n\n\n",
+	"whitespace-only lines":  "  \n \n\nx = 1\n\n\n   \n",
+	"leading blank lines":    "\n\n\n# Title\n\nx = 1\n",
+	"crlf":                   "# Budget\r\n\r\nx = 1\r\ny = x * 2\r\n",
+	"cr":                     "x = 1\ry = 2\r",
+	"unicode separators":     "x = 1\u2028y = 2\u2029z = 3\n",
+	"mixed endings":          "x = 1\r\ny = 2\n\r\n\nz = 3",
+	"frontmatter formatting": "---\n# Rates for Q3\nglobals:\n    tax:   0.2   # flat\n    base: 100\nexchange:\n  USD_EUR: 0.92\n---\n\n\nx = base * tax\n",
+	"byte order mark":        "\ufeff# Title\r\n\r\nx = 1\r\n",
+	"bom frontmatter":        "\ufeff---\r\nlocale: de-DE\r\n---\r\nx = 1,5\r\n",
+	"frontmatter only":       "---\ntitle: 'Notes'\n---",
+}
+
+func TestSerializeRoundTrip(t *testing.T) {
+	sources := make(map[string]string, len(roundTripCorpus))
+	for name, source := range roundTripCorpus {
+		sources[name] = source
+	}
+	for _, pattern := range []string{"../../testdata/*.cm", "../../testdata/spec/valid/*/*.cm", "../../docs/examples/*.cm"} {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sources[file] = string(data)
+		}
+	}
+
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			doc, err := NewDocument(source)
+			if err != nil {
+				t.Skipf("does not load: %v", err)
+			}
+			if got := doc.Serialize(); got != source {
+				t.Errorf("Serialize() = %q, want %q", got, source)
+			}
+
+			// Block lines cover the body without losing or moving any
+			_, body, _ := ParseFrontmatter(source)
+			var lines []string
+			for _, node := range doc.GetBlocks() {
+				lines = append(lines, node.Block.Source()...)
+			}
+			if want := splitLines(body); strings.Join(lines, "\n") != strings.Join(want, "\n") {
+				t.Errorf("block lines = %q, want %q", lines, want)
+			}
+		})
+	}
+}
+
+func TestSerializeAfterEdits(t *testing.T) {
+	source := "\ufeff---\r\nglobals:\r\n    base: 100   # kept\r\n---\r\n# Budget\r\n\r\nx = base\r\n"
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Editing a block keeps the frontmatter as written, the byte order
+	// mark, and the line endings
+	calc := doc.GetBlocks()[len(doc.GetBlocks())-1]
+	if _, err := doc.ReplaceBlockSource(calc.ID, []string{"x = base * 2", ""}); err != nil {
+		t.Fatal(err)
+	}
+	want := "\ufeff---\r\nglobals:\r\n    base: 100   # kept\r\n---\r\n# Budget\r\n\r\nx = base * 2\r\n"
+	if got := doc.Serialize(); got != want {
+		t.Errorf("after block edit Serialize() = %q, want %q", got, want)
+	}
+
+	// Editing back restores the original exactly
+	if _, err := doc.ReplaceBlockSource(calc.ID, []string{"x = base", ""}); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Serialize(); got != source {
+		t.Errorf("after undoing the edit Serialize() = %q, want %q", got, source)
+	}
+
+	// A changed frontmatter is written from its parsed form
+	doc.GetFrontmatter().Globals["base"] = "200"
+	if got := doc.Serialize(); !strings.HasPrefix(got, "\ufeff---\r\nglobals:\r\n  base: 200\r\n---\r\n") {
+		t.Errorf("after frontmatter edit Serialize() = %q", got)
+	}
+}
+
+func TestTextStyle(t *testing.T) {
+	source := "\ufeffx = 1\r\ny = 2\r\n"
+	style := DetectTextStyle(source)
+	if !style.BOM || style.Newline != "\r\n" {
+		t.Fatalf("DetectTextStyle() = %+v", style)
+	}
+	normalized := NormalizeText(source)
+	if normalized != "x = 1\ny = 2\n" {
+		t.Errorf("NormalizeText() = %q", normalized)
+	}
+	if got := style.Apply(normalized); got != source {
+		t.Errorf("Apply() = %q, want %q", got, source)
+	}
+	if got := style.Apply(source); got != source {
+		t.Errorf("Apply() on styled text = %q, want it unchanged", got)
+	}
+	if got := NormalizeText("a\rb"); got != "a\nb" {
+		t.Errorf("NormalizeText() = %q, want CR as a line ending", got)
+	}
+}
+
+func TestNewDocumentWithBOM(t *testing.T) {
+	doc, err := NewDocument("\ufeff---\r\nglobals:\r\n  base: 100\r\n---\r\nx = base\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.GetFrontmatter() == nil || doc.GetFrontmatter().Globals["base"] != "100" {
+		t.Fatalf("frontmatter after a byte order mark not parsed: %+v", doc.GetFrontmatter())
+	}
+	if first := doc.GetBlocks()[0].Block.Source()[0]; first != "x = base" {
+		t.Errorf("first line = %q", first)
+	}
+}
+
+func TestSerializeAddedLocale(t *testing.T) {
+	doc, err := NewDocumentWithLocale("x = 1,5\n", "de-DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Serialize(); !strings.HasPrefix(got, "---\nlocale: de-DE\n---\n") || !strings.HasSuffix(got, "x = 1,5\n") {
+		t.Errorf("Serialize() = %q, want the locale recorded in frontmatter", got)
+	}
+}
@@ -0,0 +1,236 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrTxDone is returned when using a transaction after Commit or Rollback.
+var ErrTxDone = errors.New("transaction already committed or rolled back")
+
+// Tx groups several block mutations so they apply as one unit.
+// Mutations take effect on the document immediately (so later operations
+// can refer to blocks inserted earlier in the same transaction), but
+// dependency analysis and metadata attachment are deferred until Commit,
+// which runs them once; the combined UpdateResult lets callers re-evaluate
+// once and record a single undo entry.
+//
+// If any operation fails, the transaction is poisoned: later operations
+// are skipped and Commit rolls the document back and returns the error.
+//
+// Usage:
+//
+//	tx := doc.Begin()
+//	tx.ReplaceBlockSource(id1, []string{"a = 5"})
+//	newID, _ := tx.InsertBlock(id1, BlockCalculation, []string{"b = a * 2"})
+//	result, err := tx.Commit()
+//	if err == nil {
+//		eval.EvaluateAffectedBlocks(doc, result.AffectedBlockIDs)
+//	}
+//
+// The document must not be mutated outside the transaction while it is open.
+type Tx struct {
+	doc *Document
+
+	// Snapshot for rollback. Editing a custom block replaces the node's
+	// block, so each node's block is kept as well as its source.
+	blocks  []*BlockNode
+	values  map[string]Block
+	sources map[string][]string
+
+	touched     []string // Blocks inserted or modified, in operation order
+	deleted     []string // Blocks removed
+	changedVars []string // Variables defined by touched blocks before modification
+	err         error    // First operation error; poisons the transaction
+	done        bool
+}
+
+// Begin starts a transaction for grouped mutations.
+func (d *Document) Begin() *Tx {
+	tx := &Tx{
+		doc:     d,
+		blocks:  slices.Clone(d.blocks),
+		values:  make(map[string]Block, len(d.blocks)),
+		sources: make(map[string][]string, len(d.blocks)),
+	}
+	for _, node := range d.blocks {
+		tx.values[node.ID] = node.Block
+		tx.sources[node.ID] = slices.Clone(node.Block.Source())
+	}
+	d.batching = true
+	return tx
+}
+
+// ReplaceBlockSource replaces a block's source within the transaction.
+func (tx *Tx) ReplaceBlockSource(blockID string, newSource []string) error {
+	if err := tx.check(); err != nil {
+		return err
+	}
+
+	tx.recordVariables(blockID)
+	if _, err := tx.doc.ReplaceBlockSource(blockID, newSource); err != nil {
+		tx.err = err
+		return err
+	}
+	tx.touched = append(tx.touched, blockID)
+	return nil
+}
+
+// InsertBlock inserts a new block after afterBlockID within the transaction.
+// Returns the new block's ID so later operations can target it.
+func (tx *Tx) InsertBlock(afterBlockID string, blockType BlockType, source []string) (string, error) {
+	if err := tx.check(); err != nil {
+		return "", err
+	}
+
+	result, err := tx.doc.InsertBlock(afterBlockID, blockType, source)
+	if err != nil {
+		tx.err = err
+		return "", err
+	}
+	tx.touched = append(tx.touched, result.ModifiedBlockID)
+	return result.ModifiedBlockID, nil
+}
+
+// DeleteBlock removes a block within the transaction.
+func (tx *Tx) DeleteBlock(blockID string) error {
+	if err := tx.check(); err != nil {
+		return err
+	}
+
+	tx.recordVariables(blockID)
+	if _, err := tx.doc.DeleteBlock(blockID); err != nil {
+		tx.err = err
+		return err
+	}
+	tx.deleted = append(tx.deleted, blockID)
+	return nil
+}
+
+// Commit finishes the transaction and returns the combined change set.
+// AffectedBlockIDs covers every surviving block that was inserted, modified,
+// or depends (transitively) on a changed variable, in dependency order.
+// ModifiedBlockID is the first block touched by the transaction.
+//
+// If any operation failed, the document is rolled back and that error is returned.
+func (tx *Tx) Commit() (*UpdateResult, error) {
+	if tx.done {
+		return nil, ErrTxDone
+	}
+	if tx.err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("transaction rolled back: %w", tx.err)
+	}
+	tx.done = true
+
+	d := tx.doc
+	d.batching = false
+	if err := d.rebuildDependencies(); err != nil {
+		return nil, err
+	}
+	d.attachMetadata()
+
+	// Variables defined after the edits count as changed too; blocks
+	// deleted later in the same transaction are skipped
+	result := &UpdateResult{
+		AffectedBlockIDs: d.affectedBlocks(tx.touched, tx.changedVars),
+	}
+	if len(tx.touched) > 0 {
+		result.ModifiedBlockID = tx.touched[0]
+	} else if len(tx.deleted) > 0 {
+		result.ModifiedBlockID = tx.deleted[0]
+	}
+
+	return result, nil
+}
+
+// Rollback discards all mutations made in the transaction, restoring the
+// document's blocks, including reparsed custom blocks, and sources to their
+// state at Begin.
+// Rollback after Commit is a no-op.
+func (tx *Tx) Rollback() {
+	if tx.done {
+		return
+	}
+	tx.done = true
+
+	d := tx.doc
+	d.batching = false
+	d.blocks = tx.blocks
+	d.blockIndex = make(map[string]*BlockNode, len(tx.blocks))
+	for _, node := range tx.blocks {
+		d.blockIndex[node.ID] = node
+		node.Block = tx.values[node.ID]
+		switch b := node.Block.(type) {
+		case *CalcBlock:
+			b.source = tx.sources[node.ID]
+			b.tokens = nil
+		case *TextBlock:
+			b.source = tx.sources[node.ID]
+		}
+		node.Block.SetDirty(true)
+	}
+	d.rebuildDependencies()
+	d.attachMetadata()
+}
+
+// EditKind selects what an Edit does.
+type EditKind int
+
+const (
+	EditReplace EditKind = iota // Replace BlockID's source
+	EditInsert                  // Insert a block after BlockID
+	EditDelete                  // Delete BlockID
+)
+
+// Edit is one block mutation for ApplyEdits.
+type Edit struct {
+	Kind    EditKind
+	BlockID string    // Block to replace or delete, or to insert after
+	Type    BlockType // Type of an inserted block
+	Source  []string  // Source for EditReplace and EditInsert
+}
+
+// ApplyEdits applies edits in order as a single transaction and returns
+// the combined UpdateResult. If any edit fails, none are applied.
+// Use Begin directly when later edits need the IDs of inserted blocks.
+func (d *Document) ApplyEdits(edits []Edit) (*UpdateResult, error) {
+	tx := d.Begin()
+	for _, edit := range edits {
+		var err error
+		switch edit.Kind {
+		case EditReplace:
+			err = tx.ReplaceBlockSource(edit.BlockID, edit.Source)
+		case EditInsert:
+			_, err = tx.InsertBlock(edit.BlockID, edit.Type, edit.Source)
+		case EditDelete:
+			err = tx.DeleteBlock(edit.BlockID)
+		default:
+			err = fmt.Errorf("unknown edit kind %d", edit.Kind)
+			tx.err = err
+		}
+		if err != nil {
+			break
+		}
+	}
+	return tx.Commit()
+}
+
+// check returns an error if the transaction can no longer accept operations.
+func (tx *Tx) check() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	return tx.err
+}
+
+// recordVariables remembers the variables a block defines before it is
+// modified or deleted, so their dependents are included at Commit.
+func (tx *Tx) recordVariables(blockID string) {
+	if node, ok := tx.doc.blockIndex[blockID]; ok {
+		if cb, ok := node.Block.(*CalcBlock); ok {
+			tx.changedVars = append(tx.changedVars, cb.Variables()...)
+		}
+	}
+}
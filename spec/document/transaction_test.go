@@ -0,0 +1,204 @@
+package document
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestTransactionCommit tests grouped mutations producing one combined result
+func TestTransactionCommit(t *testing.T) {
+	source := `a = 1
+
+
+b = a + 1
+
+
+c = 3`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	aID := findCalcBlock(t, doc, "a = 1")
+	bID := findCalcBlock(t, doc, "b = a")
+	cID := findCalcBlock(t, doc, "c = 3")
+
+	tx := doc.Begin()
+	if err := tx.ReplaceBlockSource(aID, []string{"a = 10"}); err != nil {
+		t.Fatalf("ReplaceBlockSource failed: %v", err)
+	}
+	newID, err := tx.InsertBlock(cID, BlockCalculation, []string{"d = c * 2"})
+	if err != nil {
+		t.Fatalf("InsertBlock failed: %v", err)
+	}
+
+	result, err := tx.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if result.ModifiedBlockID != aID {
+		t.Errorf("ModifiedBlockID = %s, want first touched block %s", result.ModifiedBlockID, aID)
+	}
+	// c is untouched and does not depend on a; results come in document order
+	want := []string{aID, bID, newID}
+	if !slices.Equal(result.AffectedBlockIDs, want) {
+		t.Errorf("AffectedBlockIDs = %v, want %v", result.AffectedBlockIDs, want)
+	}
+
+	// One evaluation pass over the combined result
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	newNode, _ := doc.GetBlock(newID)
+	if got := newNode.Block.(*CalcBlock).LastValue().String(); got != "6" {
+		t.Errorf("d = %s, want 6", got)
+	}
+
+	// Finished transactions reject further use
+	if err := tx.DeleteBlock(cID); !errors.Is(err, ErrTxDone) {
+		t.Errorf("DeleteBlock after Commit: err = %v, want ErrTxDone", err)
+	}
+}
+
+// TestTransactionRollback tests that a failed operation undoes the whole transaction
+func TestTransactionRollback(t *testing.T) {
+	source := `a = 1
+
+
+b = a + 1`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	aID := findCalcBlock(t, doc, "a = 1")
+	bID := findCalcBlock(t, doc, "b = a")
+	aNode, _ := doc.GetBlock(aID)
+	originalSource := slices.Clone(aNode.Block.Source())
+	originalBlocks := len(doc.GetBlocks())
+
+	tx := doc.Begin()
+	_ = tx.ReplaceBlockSource(aID, []string{"a = 10"})
+	_ = tx.DeleteBlock(bID)
+	if err := tx.ReplaceBlockSource("missing", []string{"x = 1"}); err == nil {
+		t.Fatal("expected error for unknown block")
+	}
+	if _, err := tx.InsertBlock(aID, BlockText, []string{"skipped"}); err == nil {
+		t.Error("expected poisoned transaction to reject further operations")
+	}
+
+	if _, err := tx.Commit(); err == nil {
+		t.Fatal("expected Commit to fail after an operation error")
+	}
+
+	if got := len(doc.GetBlocks()); got != originalBlocks {
+		t.Errorf("got %d blocks after rollback, want %d", got, originalBlocks)
+	}
+	if _, ok := doc.GetBlock(bID); !ok {
+		t.Error("deleted block was not restored")
+	}
+	if got := aNode.Block.Source(); !slices.Equal(got, originalSource) {
+		t.Errorf("source after rollback = %v, want %v", got, originalSource)
+	}
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate after rollback failed: %v", err)
+	}
+}
+
+// TestTransactionRollbackCustomBlock tests rollback restores a custom
+// block its factory reparsed, not only the source of built-in blocks
+func TestTransactionRollbackCustomBlock(t *testing.T) {
+	doc := newAliasDocument(t, "subtotal = 40\n\n\n```alias\ntotal: subtotal\n```\n\n\nresult = total * 2")
+	aliasID := doc.GetBlocks()[1].ID
+	original := doc.GetBlocks()[1].Block
+
+	tx := doc.Begin()
+	if err := tx.ReplaceBlockSource(aliasID, []string{"```alias", "other: subtotal", "```"}); err != nil {
+		t.Fatalf("ReplaceBlockSource failed: %v", err)
+	}
+	_ = tx.ReplaceBlockSource("missing", []string{"x = 1"})
+	if _, err := tx.Commit(); err == nil {
+		t.Fatal("expected Commit to fail after an operation error")
+	}
+
+	node, _ := doc.GetBlock(aliasID)
+	if node.Block != original {
+		t.Errorf("block after rollback = %v, want the original alias block", node.Block)
+	}
+	if got := strings.Join(node.Block.Source(), "\n"); got != "```alias\ntotal: subtotal\n```" {
+		t.Errorf("source after rollback = %q", got)
+	}
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate after rollback failed: %v", err)
+	}
+	blocks := doc.GetBlocks()
+	if got := blocks[len(blocks)-1].Block.(*CalcBlock).LastValue().String(); got != "80" {
+		t.Errorf("result = %s, want 80", got)
+	}
+}
+
+// TestApplyEdits tests a batch of edits applied as one transaction
+func TestApplyEdits(t *testing.T) {
+	source := `a = 1
+
+
+b = a + 1
+
+
+c = 3`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	aID := findCalcBlock(t, doc, "a = 1")
+	bID := findCalcBlock(t, doc, "b = a")
+	cID := findCalcBlock(t, doc, "c = 3")
+
+	result, err := doc.ApplyEdits([]Edit{
+		{Kind: EditReplace, BlockID: aID, Source: []string{"a = 10"}},
+		{Kind: EditInsert, BlockID: cID, Type: BlockCalculation, Source: []string{"d = a * c"}},
+		{Kind: EditDelete, BlockID: bID},
+	})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+
+	blocks := doc.GetBlocks()
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3", len(blocks))
+	}
+	dID := blocks[2].ID
+	// Dependencies of the inserted block are analyzed at Commit
+	if vars := blocks[2].Block.(*CalcBlock).Variables(); !slices.Equal(vars, []string{"d"}) {
+		t.Errorf("inserted block variables = %v, want [d]", vars)
+	}
+	if want := []string{aID, dID}; !slices.Equal(result.AffectedBlockIDs, want) {
+		t.Errorf("AffectedBlockIDs = %v, want %v", result.AffectedBlockIDs, want)
+	}
+
+	if err := doc.Evaluate(); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if got := blocks[2].Block.(*CalcBlock).LastValue().String(); got != "30" {
+		t.Errorf("d = %s, want 30", got)
+	}
+
+	// A failing edit leaves the document as it was
+	if _, err := doc.ApplyEdits([]Edit{
+		{Kind: EditReplace, BlockID: aID, Source: []string{"a = 20"}},
+		{Kind: EditDelete, BlockID: "missing"},
+	}); err == nil {
+		t.Fatal("expected ApplyEdits to fail for an unknown block")
+	}
+	aNode, _ := doc.GetBlock(aID)
+	if got := aNode.Block.Source(); !slices.Equal(got, []string{"a = 10"}) {
+		t.Errorf("source after failed batch = %v, want [a = 10]", got)
+	}
+}
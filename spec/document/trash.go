@@ -0,0 +1,93 @@
+package document
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MaxTrashedBlocks is how many soft-deleted blocks a document keeps; when
+// the trash is full the oldest is dropped.
+const MaxTrashedBlocks = 100
+
+// TrashedBlock is a block removed by SoftDeleteBlock, kept so RestoreBlock
+// can put it back. The trash lasts for the session; it isn't serialized.
+type TrashedBlock struct {
+	Node      *BlockNode
+	AfterID   string // Block it followed, "" if it was first
+	Position  int    // Its index among the blocks, used if AfterID is gone
+	DeletedAt time.Time
+}
+
+// SoftDeleteBlock removes a block like DeleteBlock, keeping it in the trash
+// so it can be restored independently of any undo history.
+func (d *Document) SoftDeleteBlock(blockID string) (*UpdateResult, error) {
+	pos := slices.IndexFunc(d.blocks, func(node *BlockNode) bool { return node.ID == blockID })
+	if pos == -1 {
+		return nil, fmt.Errorf("block not found: %s", blockID)
+	}
+	entry := TrashedBlock{Node: d.blocks[pos], Position: pos, DeletedAt: time.Now()}
+	if pos > 0 {
+		entry.AfterID = d.blocks[pos-1].ID
+	}
+
+	result, err := d.DeleteBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	d.trash = append(d.trash, entry)
+	if len(d.trash) > MaxTrashedBlocks {
+		d.trash = slices.Delete(d.trash, 0, len(d.trash)-MaxTrashedBlocks)
+	}
+	return result, nil
+}
+
+// Trash returns the soft-deleted blocks, most recently deleted first.
+func (d *Document) Trash() []TrashedBlock {
+	trash := slices.Clone(d.trash)
+	slices.Reverse(trash)
+	return trash
+}
+
+// RestoreBlock puts a soft-deleted block back after the block it followed,
+// or at its old position if that block is gone, and removes it from the
+// trash. Blocks depending on its variables are affected, as for InsertBlock.
+func (d *Document) RestoreBlock(blockID string) (*UpdateResult, error) {
+	i := slices.IndexFunc(d.trash, func(t TrashedBlock) bool { return t.Node.ID == blockID })
+	if i == -1 {
+		return nil, fmt.Errorf("block not in trash: %s", blockID)
+	}
+	entry := d.trash[i]
+	d.trash = slices.Delete(d.trash, i, i+1)
+
+	pos := min(entry.Position, len(d.blocks))
+	if entry.AfterID == "" {
+		pos = 0
+	} else if after := slices.IndexFunc(d.blocks, func(node *BlockNode) bool { return node.ID == entry.AfterID }); after != -1 {
+		pos = after + 1
+	}
+	d.blocks = slices.Insert(d.blocks, pos, entry.Node)
+	d.blockIndex[entry.Node.ID] = entry.Node
+
+	// In a transaction, Commit analyzes the result once
+	if d.batching {
+		return &UpdateResult{
+			ModifiedBlockID:  entry.Node.ID,
+			AffectedBlockIDs: []string{entry.Node.ID},
+		}, nil
+	}
+
+	if err := d.rebuildDependencies(); err != nil {
+		return nil, err
+	}
+	d.attachMetadata()
+
+	affectedIDs := []string{entry.Node.ID}
+	if calcBlock, ok := entry.Node.Block.(*CalcBlock); ok {
+		affectedIDs = append(affectedIDs, d.GetTransitiveDependents(calcBlock.Variables())...)
+	}
+	return &UpdateResult{
+		ModifiedBlockID:  entry.Node.ID,
+		AffectedBlockIDs: uniqueStrings(affectedIDs),
+	}, nil
+}
@@ -0,0 +1,111 @@
+package document
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	source := `rate = 5%
+
+
+Notes about the rate.
+
+
+cost = 100 * (1 + rate)`
+
+	doc, err := NewDocument(source)
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	rateID := findCalcBlock(t, doc, "rate = 5%")
+	costID := findCalcBlock(t, doc, "cost = ")
+
+	if _, err := doc.SoftDeleteBlock(rateID); err != nil {
+		t.Fatalf("SoftDeleteBlock failed: %v", err)
+	}
+	if _, ok := doc.GetBlock(rateID); ok || len(doc.GetBlocks()) != 2 {
+		t.Fatalf("deleted block still in the document")
+	}
+	trash := doc.Trash()
+	if len(trash) != 1 || trash[0].Node.ID != rateID || trash[0].AfterID != "" {
+		t.Fatalf("trash = %+v, want the rate block, first in the document", trash)
+	}
+
+	result, err := doc.RestoreBlock(rateID)
+	if err != nil {
+		t.Fatalf("RestoreBlock failed: %v", err)
+	}
+	if blocks := doc.GetBlocks(); blocks[0].ID != rateID || len(blocks) != 3 {
+		t.Errorf("restored block not back at the start")
+	}
+	if !slices.Contains(result.AffectedBlockIDs, costID) {
+		t.Errorf("AffectedBlockIDs = %v, want the dependent cost block", result.AffectedBlockIDs)
+	}
+	if len(doc.Trash()) != 0 {
+		t.Error("restored block still in the trash")
+	}
+	if got := doc.Serialize(); got != source {
+		t.Errorf("after restoring, document = %q, want the original", got)
+	}
+	if _, err := doc.RestoreBlock(rateID); err == nil {
+		t.Error("restoring a block not in the trash should fail")
+	}
+}
+
+// TestRestoreBlockPosition checks a block goes back after the block it
+// followed, or at its old index once that block is gone too.
+func TestRestoreBlockPosition(t *testing.T) {
+	doc, err := NewDocument("a = 1\n\n\nb = 2\n\n\nc = 3\n\n\nd = 4\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	bID := findCalcBlock(t, doc, "b = 2")
+	cID := findCalcBlock(t, doc, "c = 3")
+
+	// Most recently deleted first
+	if _, err := doc.SoftDeleteBlock(cID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.SoftDeleteBlock(bID); err != nil {
+		t.Fatal(err)
+	}
+	if trash := doc.Trash(); trash[0].Node.ID != bID || trash[1].Node.ID != cID {
+		t.Fatalf("trash not most recent first")
+	}
+
+	// c followed b, which is still deleted, so c goes back at its index, 2
+	if _, err := doc.RestoreBlock(cID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.RestoreBlock(bID); err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, node := range doc.GetBlocks() {
+		order = append(order, strings.TrimSpace(node.Block.Source()[0]))
+	}
+	if want := []string{"a = 1", "b = 2", "d = 4", "c = 3"}; !slices.Equal(order, want) {
+		t.Errorf("blocks = %v, want %v", order, want)
+	}
+}
+
+func TestTrashLimit(t *testing.T) {
+	doc, err := NewDocument("x = 1\n")
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	for range MaxTrashedBlocks + 5 {
+		result, err := doc.InsertBlock(doc.GetBlocks()[0].ID, BlockCalculation, []string{"y = 2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := doc.SoftDeleteBlock(result.ModifiedBlockID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(doc.Trash()); got != MaxTrashedBlocks {
+		t.Errorf("trash holds %d blocks, want %d", got, MaxTrashedBlocks)
+	}
+}
@@ -3,6 +3,8 @@ package document
 import (
 	"strings"
 	"unicode"
+
+	"github.com/rivo/uniseg"
 )
 
 // splitLines splits text into lines, handling all Unicode line terminators:
@@ -72,3 +74,94 @@ func isEmptyLine(line string) bool {
 	}
 	return true
 }
+
+// ByteOrderMark is U+FEFF encoded in UTF-8, which some Windows editors
+// write at the start of a file. Documents and the lexer skip it.
+const ByteOrderMark = "\ufeff"
+
+// TextStyle records how a file's text is encoded on disk, so text derived
+// from it can be written back the same way.
+type TextStyle struct {
+	BOM     bool   // Starts with a byte order mark
+	Newline string // Line ending: "\r\n" if any line ends with one, else "\n"
+}
+
+// DetectTextStyle returns the style of source.
+func DetectTextStyle(source string) TextStyle {
+	style := TextStyle{BOM: strings.HasPrefix(source, ByteOrderMark), Newline: "\n"}
+	if strings.Contains(source, "\r\n") {
+		style.Newline = "\r\n"
+	}
+	return style
+}
+
+// Apply returns text written in style s: any byte order mark and line
+// endings text has are replaced by s's.
+func (s TextStyle) Apply(text string) string {
+	text = NormalizeText(text)
+	if s.Newline != "" && s.Newline != "\n" {
+		text = strings.ReplaceAll(text, "\n", s.Newline)
+	}
+	if s.BOM {
+		text = ByteOrderMark + text
+	}
+	return text
+}
+
+// NormalizeText returns source without a byte order mark and with "\n"
+// line endings in place of "\r\n" and "\r".
+func NormalizeText(source string) string {
+	source = strings.TrimPrefix(source, ByteOrderMark)
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	return strings.ReplaceAll(source, "\r", "\n")
+}
+
+// DefaultTabWidth is the number of columns between tab stops when none is
+// configured.
+const DefaultTabWidth = 4
+
+// ExpandTabs returns line with each tab replaced by spaces up to the next
+// tab stop, one every tabWidth display columns. Widths are those of grapheme
+// clusters, as terminals draw them: wide characters (給, 💰) take two
+// columns, and so do emoji sequences joined with zero-width joiners. A
+// tabWidth below 1 means DefaultTabWidth.
+func ExpandTabs(line string, tabWidth int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	if tabWidth < 1 {
+		tabWidth = DefaultTabWidth
+	}
+	var b strings.Builder
+	width := 0
+	state := -1
+	for rest := line; rest != ""; {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if cluster == "\t" {
+			n := tabWidth - width%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			width += n
+			continue
+		}
+		b.WriteString(cluster)
+		width += w
+	}
+	return b.String()
+}
+
+// DisplayColumn converts column, a 1-indexed position in line counted in
+// characters as the lexer and diagnostics count it (a tab is one), to the
+// 1-indexed column it is shown at with tabs expanded to tabWidth.
+func DisplayColumn(line string, column, tabWidth int) int {
+	prefix := line
+	for i := range line {
+		if column <= 1 {
+			prefix = line[:i]
+			break
+		}
+		column--
+	}
+	return uniseg.StringWidth(ExpandTabs(prefix, tabWidth)) + column
+}
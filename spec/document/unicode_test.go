@@ -94,3 +94,55 @@ func TestIsEmptyLine(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{"no tabs", "x = 1", 4, "x = 1"},
+		{"leading tab", "\tx = 1", 4, "    x = 1"},
+		{"tab to next stop", "ab\tc", 4, "ab  c"},
+		{"tab at a stop", "abcd\te", 4, "abcd    e"},
+		{"consecutive tabs", "a\t\tb", 4, "a       b"},
+		{"custom width", "a\tb", 8, "a       b"},
+		{"default width", "\tx", 0, "    x"},
+		{"wide characters", "日本\tx", 4, "日本    x"},
+		{"multibyte narrow", "€5\tx", 4, "€5  x"},
+		{"emoji sequence", "👨‍👩‍👧\tx", 4, "👨‍👩‍👧  x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ExpandTabs(tt.input, tt.width); result != tt.expected {
+				t.Errorf("ExpandTabs(%q, %d) = %q, want %q", tt.input, tt.width, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisplayColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		column   int
+		expected int
+	}{
+		{"no tabs", "x = y", 5, 5},
+		{"after leading tab", "\tx = y", 6, 9},
+		{"on the tab", "\tx = y", 1, 1},
+		{"after two tabs", "a\t\tb", 4, 9},
+		{"wide characters", "日本 = y", 6, 8},
+		{"past line end", "\tx", 4, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := DisplayColumn(tt.line, tt.column, 4); result != tt.expected {
+				t.Errorf("DisplayColumn(%q, %d) = %d, want %d", tt.line, tt.column, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/shopspring/decimal"
+)
+
+// Widget types a frontend can render for a global.
+const (
+	WidgetSlider = "slider" // Range between Min and Max, moved by Step
+	WidgetNumber = "number" // Free numeric input, nudged by Step
+	WidgetToggle = "toggle" // Boolean on/off
+	WidgetSelect = "select" // One of Options
+)
+
+// Widget declares an interactive control bound to a frontmatter global:
+//
+//	---
+//	globals:
+//	  rate: 0.05
+//	widgets:
+//	  rate: {type: slider, min: 0, max: 0.2, step: 0.005}
+//	---
+//
+// Min, Max, and Step apply to the number as written in the global, so for
+// "budget: $5000" a step of 100 moves the budget by $100.
+type Widget struct {
+	Type    string
+	Min     *decimal.Decimal // Lower bound, nil if unbounded
+	Max     *decimal.Decimal // Upper bound, nil if unbounded
+	Step    decimal.Decimal  // Increment for slider and number
+	Options []string         // Choices for select, as global source text
+}
+
+// widgetYAML is the intermediate struct for YAML unmarshaling of a widget.
+type widgetYAML struct {
+	Type    string   `yaml:"type"`
+	Min     *float64 `yaml:"min"`
+	Max     *float64 `yaml:"max"`
+	Step    *float64 `yaml:"step"`
+	Options []string `yaml:"options"`
+}
+
+// parseWidget validates a widget declaration for the global name.
+func parseWidget(name string, raw widgetYAML) (Widget, error) {
+	w := Widget{Type: strings.ToLower(raw.Type), Options: raw.Options}
+	if raw.Min != nil {
+		lo := decimal.NewFromFloat(*raw.Min)
+		w.Min = &lo
+	}
+	if raw.Max != nil {
+		hi := decimal.NewFromFloat(*raw.Max)
+		w.Max = &hi
+	}
+
+	switch w.Type {
+	case WidgetSlider:
+		if w.Min == nil || w.Max == nil {
+			return Widget{}, fmt.Errorf("widget '%s': slider needs min and max", name)
+		}
+	case WidgetNumber, WidgetToggle:
+	case WidgetSelect:
+		if len(w.Options) == 0 {
+			return Widget{}, fmt.Errorf("widget '%s': select needs options", name)
+		}
+	default:
+		return Widget{}, fmt.Errorf("widget '%s': unknown type '%s' (want slider, number, toggle, or select)", name, raw.Type)
+	}
+
+	if w.Min != nil && w.Max != nil && !w.Min.LessThan(*w.Max) {
+		return Widget{}, fmt.Errorf("widget '%s': min must be less than max", name)
+	}
+
+	w.Step = decimal.NewFromInt(1)
+	if raw.Step != nil {
+		w.Step = decimal.NewFromFloat(*raw.Step)
+		if !w.Step.IsPositive() {
+			return Widget{}, fmt.Errorf("widget '%s': step must be positive", name)
+		}
+	} else if w.Type == WidgetSlider {
+		w.Step = w.Max.Sub(*w.Min).Div(decimal.NewFromInt(100))
+	}
+
+	return w, nil
+}
+
+// yaml returns the widget in YAML flow style, as written in frontmatter.
+func (w Widget) yaml() string {
+	parts := []string{"type: " + w.Type}
+	if w.Min != nil {
+		parts = append(parts, "min: "+w.Min.String())
+	}
+	if w.Max != nil {
+		parts = append(parts, "max: "+w.Max.String())
+	}
+	if w.Type == WidgetSlider || w.Type == WidgetNumber {
+		parts = append(parts, "step: "+w.Step.String())
+	}
+	if len(w.Options) > 0 {
+		quoted := make([]string, len(w.Options))
+		for i, opt := range w.Options {
+			quoted[i] = fmt.Sprintf("%q", opt)
+		}
+		parts = append(parts, "options: ["+strings.Join(quoted, ", ")+"]")
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// numberLiteral matches the first number in a global's source text,
+// including digit-group separators of any locale.
+var numberLiteral = regexp.MustCompile(`-?[0-9](?:[0-9_,.'\x{2019}]*[0-9])?`)
+
+// Adjust returns value (a global's source text) moved by steps widget steps:
+// numbers are nudged by Step and clamped to Min/Max, toggles flip, and
+// selects move through Options. The rest of the text, such as a currency
+// symbol or unit, is kept. loc is the document's number locale.
+func (w Widget) Adjust(value string, steps int, loc lexer.NumberLocale) (string, error) {
+	switch w.Type {
+	case WidgetToggle:
+		if steps == 0 {
+			return value, nil
+		}
+		if strings.EqualFold(strings.TrimSpace(value), "true") {
+			return "false", nil
+		}
+		return "true", nil
+
+	case WidgetSelect:
+		idx := slices.Index(w.Options, strings.TrimSpace(value))
+		idx = min(max(idx+steps, 0), len(w.Options)-1)
+		return w.Options[idx], nil
+	}
+
+	span := numberLiteral.FindStringIndex(value)
+	if span == nil {
+		return "", fmt.Errorf("no number to adjust in '%s'", value)
+	}
+	current, err := parseLocaleNumber(value[span[0]:span[1]], loc)
+	if err != nil {
+		return "", err
+	}
+
+	next := current.Add(w.Step.Mul(decimal.NewFromInt(int64(steps))))
+	if w.Min != nil && next.LessThan(*w.Min) {
+		next = *w.Min
+	}
+	if w.Max != nil && next.GreaterThan(*w.Max) {
+		next = *w.Max
+	}
+
+	return value[:span[0]] + formatLocaleNumber(next, loc) + value[span[1]:], nil
+}
+
+// parseLocaleNumber parses a number literal written in loc's style.
+func parseLocaleNumber(literal string, loc lexer.NumberLocale) (decimal.Decimal, error) {
+	var sb strings.Builder
+	for _, r := range literal {
+		switch {
+		case r == loc.Decimal:
+			sb.WriteRune('.')
+		case r == '_' || slices.Contains(loc.Groups, r):
+			// Digit-group separator
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return decimal.NewFromString(sb.String())
+}
+
+// formatLocaleNumber writes d with loc's decimal mark and no grouping.
+func formatLocaleNumber(d decimal.Decimal, loc lexer.NumberLocale) string {
+	return strings.Replace(d.String(), ".", string(loc.Decimal), 1)
+}
+
+// AdjustWidget moves the global bound to a widget by steps (negative to
+// decrease) and returns its new source text. The document must be
+// re-evaluated afterwards, since every block may read the global.
+func (d *Document) AdjustWidget(name string, steps int) (string, error) {
+	fm := d.frontmatter
+	widget, ok := fm.Widget(name)
+	if !ok {
+		return "", fmt.Errorf("no widget for '%s'", name)
+	}
+
+	value, err := widget.Adjust(fm.Globals[name], steps, d.NumberLocale())
+	if err != nil {
+		return "", fmt.Errorf("widget '%s': %w", name, err)
+	}
+	fm.SetGlobal(name, value)
+	return value, nil
+}
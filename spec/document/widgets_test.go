@@ -0,0 +1,159 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+)
+
+func TestParseFrontmatter_Widgets(t *testing.T) {
+	source := `---
+globals:
+  rate: 0.05
+  budget: $5,000
+  plan: "basic"
+  annual: false
+widgets:
+  rate: {type: slider, min: 0, max: 0.2, step: 0.005}
+  budget: {type: number, min: 0, step: 100}
+  plan: {type: select, options: [basic, pro]}
+  annual: {type: toggle}
+---
+cost = budget * rate`
+
+	fm, _, err := ParseFrontmatter(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fm.Widgets) != 4 {
+		t.Fatalf("expected 4 widgets, got %d", len(fm.Widgets))
+	}
+
+	rate, ok := fm.Widget("rate")
+	if !ok || rate.Type != WidgetSlider || rate.Min.String() != "0" || rate.Max.String() != "0.2" || rate.Step.String() != "0.005" {
+		t.Errorf("rate widget = %+v", rate)
+	}
+	if budget, _ := fm.Widget("budget"); budget.Max != nil || budget.Step.String() != "100" {
+		t.Errorf("budget widget = %+v", budget)
+	}
+
+	parsed, _, err := ParseFrontmatter(fm.Serialize())
+	if err != nil {
+		t.Fatalf("failed to parse serialized frontmatter: %v\n%s", err, fm.Serialize())
+	}
+	if len(parsed.Widgets) != 4 || parsed.Widgets["plan"].Options[1] != "pro" || parsed.Widgets["rate"].Step.String() != "0.005" {
+		t.Errorf("widgets did not round-trip: %+v", parsed.Widgets)
+	}
+}
+
+func TestParseFrontmatter_InvalidWidgets(t *testing.T) {
+	tests := []struct {
+		name    string
+		widgets string
+		wantErr string
+	}{
+		{"not a global", "other: {type: number}", "must name a global"},
+		{"unknown type", "rate: {type: dial}", "unknown type"},
+		{"slider without bounds", "rate: {type: slider, min: 0}", "needs min and max"},
+		{"min above max", "rate: {type: number, min: 1, max: 0}", "min must be less than max"},
+		{"zero step", "rate: {type: number, step: 0}", "step must be positive"},
+		{"select without options", "rate: {type: select}", "needs options"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := "---\nglobals:\n  rate: 0.05\nwidgets:\n  " + tt.widgets + "\n---\n"
+			_, _, err := ParseFrontmatter(source)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWidgetAdjust(t *testing.T) {
+	fm, _, err := ParseFrontmatter(`---
+globals:
+  rate: 0.05
+  budget: $5,000
+  plan: basic
+  annual: false
+widgets:
+  rate: {type: slider, min: 0, max: 0.2, step: 0.005}
+  budget: {type: number, min: 0, step: 2500}
+  plan: {type: select, options: [basic, pro, team]}
+  annual: {type: toggle}
+---
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		steps int
+		want  string
+	}{
+		{"rate", "0.05", 1, "0.055"},
+		{"rate", "0.05", -2, "0.04"},
+		{"rate", "0.19", 5, "0.2"},
+		{"rate", "0.01", -5, "0"},
+		{"budget", "$5,000", 1, "$7500"},
+		{"budget", "$5,000", -3, "$0"},
+		{"budget", "5000 EUR", 1, "7500 EUR"},
+		{"plan", "basic", 1, "pro"},
+		{"plan", "team", 1, "team"},
+		{"plan", "pro", -1, "basic"},
+		{"annual", "false", 1, "true"},
+		{"annual", "true", -1, "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			w, _ := fm.Widget(tt.name)
+			got, err := w.Adjust(tt.value, tt.steps, lexer.LocaleUS)
+			if err != nil {
+				t.Fatalf("Adjust() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Adjust(%q, %d) = %q, want %q", tt.value, tt.steps, got, tt.want)
+			}
+		})
+	}
+
+	w, _ := fm.Widget("budget")
+	if got, _ := w.Adjust("1.000,5 EUR", 1, lexer.LocaleDE); got != "3500,5 EUR" {
+		t.Errorf("Adjust under de-DE = %q, want 3500,5 EUR", got)
+	}
+	if _, err := w.Adjust("lots", 1, lexer.LocaleUS); err == nil {
+		t.Error("expected error adjusting a value without a number")
+	}
+}
+
+func TestDocumentAdjustWidget(t *testing.T) {
+	doc, err := NewDocument(`---
+globals:
+  rate: 0.05
+widgets:
+  rate: {type: slider, min: 0, max: 0.2, step: 0.005}
+---
+cost = 1000 * rate
+`)
+	if err != nil {
+		t.Fatalf("NewDocument() error = %v", err)
+	}
+
+	value, err := doc.AdjustWidget("rate", 2)
+	if err != nil {
+		t.Fatalf("AdjustWidget() error = %v", err)
+	}
+	if value != "0.06" || doc.GetFrontmatter().Globals["rate"] != "0.06" {
+		t.Errorf("rate = %q (frontmatter %q), want 0.06", value, doc.GetFrontmatter().Globals["rate"])
+	}
+
+	if _, err := doc.AdjustWidget("cost", 1); err == nil {
+		t.Error("expected error for a variable without a widget")
+	}
+}
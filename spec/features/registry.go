@@ -144,6 +144,134 @@ func getFunctions() []Feature {
 			Aliases:     []string{"square root of"},
 			Example:     "sqrt(16) → 4",
 		},
+		{
+			Name:        "sum",
+			Category:    CategoryFunction,
+			Syntax:      "sum(a, b, c, ...)",
+			Description: "Calculate the total of numbers",
+			Aliases:     []string{},
+			Example:     "sum(10, 20, 30) → 60",
+		},
+		{
+			Name:        "min",
+			Category:    CategoryFunction,
+			Syntax:      "min(a, b, c, ...)",
+			Description: "Find the smallest number",
+			Aliases:     []string{"minimum"},
+			Example:     "min(10, 20, 30) → 10",
+		},
+		{
+			Name:        "max",
+			Category:    CategoryFunction,
+			Syntax:      "max(a, b, c, ...)",
+			Description: "Find the largest number",
+			Aliases:     []string{"maximum"},
+			Example:     "max(10, 20, 30) → 30",
+		},
+		{
+			Name:        "median",
+			Category:    CategoryFunction,
+			Syntax:      "median(a, b, c, ...)",
+			Description: "Find the middle value of numbers",
+			Aliases:     []string{},
+			Example:     "median(1, 5, 3) → 3",
+		},
+		{
+			Name:        "stdev",
+			Category:    CategoryFunction,
+			Syntax:      "stdev(a, b, c, ...)",
+			Description: "Calculate the sample standard deviation",
+			Aliases:     []string{"standard deviation"},
+			Example:     "stdev(2, 4, 4, 4, 5, 5, 7, 9) → 2.14",
+		},
+		{
+			Name:        "fv",
+			Category:    CategoryFunction,
+			Syntax:      "fv(rate, periods, payment, pv)",
+			Description: "Future value of a present value and regular payments at a per-period rate",
+			Aliases:     []string{},
+			Example:     "fv(5%/12, 120, $100, $1000) → $17175.24",
+		},
+		{
+			Name:        "pmt",
+			Category:    CategoryFunction,
+			Syntax:      "pmt(rate, nper, pv)",
+			Description: "Payment per period that pays off a loan",
+			Aliases:     []string{},
+			Example:     "pmt(6%/12, 360, $300000) → $1798.65",
+		},
+		{
+			Name:        "amortize",
+			Category:    CategoryFunction,
+			Syntax:      "amortize(principal, rate, years)",
+			Description: "Monthly loan schedule of payment, interest, principal, and balance at a yearly rate; index rows as loan[12].balance",
+			Aliases:     []string{},
+			Example:     "amortize($300000, 6%, 30)[60].balance → $279.16K",
+		},
+		{
+			Name:        "npv",
+			Category:    CategoryFunction,
+			Syntax:      "npv(rate, cashflows)",
+			Description: "Net present value of cash flows one period apart, the first today",
+			Aliases:     []string{},
+			Example:     "npv(10%, [-$1000, $300, $400, $500]) → $-21.04",
+		},
+		{
+			Name:        "irr",
+			Category:    CategoryFunction,
+			Syntax:      "irr(cashflows)",
+			Description: "Internal rate of return: the rate at which npv() is zero",
+			Aliases:     []string{},
+			Example:     "irr([-1000, 300, 400, 500]) → 8.896339%",
+		},
+		{
+			Name:        "cagr",
+			Category:    CategoryFunction,
+			Syntax:      "cagr(start, end, years)",
+			Description: "Compound annual growth rate from start to end over a number of years or a duration",
+			Aliases:     []string{},
+			Example:     "cagr($100, $200, 5 years) → 14.869835%",
+		},
+		{
+			Name:        "grow",
+			Category:    CategoryFunction,
+			Syntax:      "grow(value, rate, time)",
+			Description: "Value after growing at a rate per period, like 5% per year, for a duration",
+			Aliases:     []string{},
+			Example:     "grow($100, 5% per year, 3 years) → $115.76",
+		},
+		{
+			Name:        "compound",
+			Category:    CategoryFunction,
+			Syntax:      "compound(value, rate, periods)",
+			Description: "Value after compounding at a rate per period for a number of periods",
+			Aliases:     []string{},
+			Example:     "compound($1000, 5%, 3) → $1157.63",
+		},
+		{
+			Name:        "adjust",
+			Category:    CategoryFunction,
+			Syntax:      "adjust(amount from year to year)",
+			Description: "Amount in one year's prices restated in another's, using the cpi: frontmatter",
+			Aliases:     []string{},
+			Example:     "adjust($50000 from 2015 to 2025) → $67.82K (real 2025)",
+		},
+		{
+			Name:        "normal",
+			Category:    CategoryFunction,
+			Syntax:      "normal(mean, sd)",
+			Description: "Uncertain value normally distributed around a mean; arithmetic on it is sampled",
+			Aliases:     []string{},
+			Example:     "normal($100, $15) → ~$99.61 (p5 $75.28, p95 $123.45)",
+		},
+		{
+			Name:        "uniform",
+			Category:    CategoryFunction,
+			Syntax:      "uniform(low, high)",
+			Description: "Uncertain value equally likely anywhere from low to high; arithmetic on it is sampled",
+			Aliases:     []string{},
+			Example:     "uniform(50, 80) → ~64.99 (p5 51.48, p95 78.58)",
+		},
 		{
 			Name:        "accumulate",
 			Category:    CategoryFunction,
@@ -224,6 +352,22 @@ func getFunctions() []Feature {
 			Aliases:     []string{},
 			Example:     "compress(1 GB, gzip) → 333 MB",
 		},
+		{
+			Name:        "lookup",
+			Category:    CategoryFunction,
+			Syntax:      "lookup(file, key_column, key, value_column)",
+			Description: "Value from the CSV row with a key",
+			Aliases:     []string{},
+			Example:     `lookup("prices.csv", sku, "A-100", price) → $12.50`,
+		},
+		{
+			Name:        "jsonpath",
+			Category:    CategoryFunction,
+			Syntax:      "jsonpath(file, path)",
+			Description: "Value at a path in a JSON file",
+			Aliases:     []string{},
+			Example:     `jsonpath("rates.json", "$.usd.eur") → 0.92`,
+		},
 	}
 }
 
@@ -318,6 +462,62 @@ func getDateFeatures() []Feature {
 			Aliases:     []string{},
 			Example:     "7 days from Dec 25",
 		},
+		{
+			Name:        "business days",
+			Category:    CategoryDate,
+			Syntax:      "N business days",
+			Description: "Duration in weekdays, skipping frontmatter holidays",
+			Aliases:     []string{"business day", "workday", "workdays"},
+			Example:     "Jan 16 2026 + 10 business days",
+		},
+		{
+			Name:        "workdays",
+			Category:    CategoryDate,
+			Syntax:      "workdays between date and date",
+			Description: "Count business days between two dates, inclusive",
+			Aliases:     []string{"business days between", "workdays(from, to, calendar)"},
+			Example:     "workdays between Jan 5 and Jan 16 → 10 business day",
+		},
+		{
+			Name:        "every",
+			Category:    CategoryDate,
+			Syntax:      "every period [from date]",
+			Description: "Recurring schedule of dates",
+			Aliases:     []string{"every 2nd Friday", "every other week", "every 15th", "every month on the 1st", "schedule"},
+			Example:     "paydays = every 2nd Friday from Jan 9 2026",
+		},
+		{
+			Name:        "next",
+			Category:    CategoryDate,
+			Syntax:      "next schedule [after date]",
+			Description: "Next date of a schedule, after today by default",
+			Aliases:     []string{"next(schedule, after)"},
+			Example:     "next payday after Jun 1 2026 → Friday, June 12, 2026",
+		},
+		{
+			Name:        "count between",
+			Category:    CategoryDate,
+			Syntax:      "count schedule between date and date",
+			Description: "Count a schedule's dates between two dates, inclusive",
+			Aliases:     []string{"count(schedule, from, to)", "occurrences(schedule, from, to)", "schedule in Q3"},
+			Example:     "count paydays between Mar 1 and Jun 30",
+		},
+		{
+			Name:        "time",
+			Category:    CategoryDate,
+			Syntax:      "H:MM[:SS] [AM|PM] [zone]",
+			Description: "Time of day today, in UTC or a time zone",
+			Aliases:     []string{"3pm", "10:30AM", "9:00 Tokyo", "3pm EST", "10:30 UTC-7", "14:00 Europe/Paris"},
+			Example:     "standup = 9:00 America/Chicago",
+		},
+		{
+			Name:        "in zone",
+			Category:    CategoryDate,
+			Syntax:      "time in zone",
+			Description: "Convert a time to another time zone",
+			Aliases:     []string{"time in city", "time in IANA name"},
+			Example:     "3pm EST in Tokyo → 05:00:00 JST",
+		},
 	}
 }
 
@@ -520,6 +720,30 @@ func getKeywords() []Feature {
 			Aliases:     []string{},
 			Example:     "15% of 200 → 30",
 		},
+		{
+			Name:        "off",
+			Category:    CategoryKeyword,
+			Syntax:      "X% off value",
+			Description: "Take a percentage off a value",
+			Aliases:     []string{},
+			Example:     "15% off $200 → $170.00",
+		},
+		{
+			Name:        "increase",
+			Category:    CategoryKeyword,
+			Syntax:      "increase value by X%",
+			Description: "Increase or decrease a value by a percentage",
+			Aliases:     []string{"decrease"},
+			Example:     "increase 100 by 10% → 110",
+		},
+		{
+			Name:        "change",
+			Category:    CategoryKeyword,
+			Syntax:      "change from a to b in %",
+			Description: "Percentage change from one value to another",
+			Aliases:     []string{},
+			Example:     "change from 80 to 100 in % → 25%",
+		},
 		{
 			Name:        "per",
 			Category:    CategoryKeyword,
@@ -0,0 +1,109 @@
+// Package fuzzy provides edit-distance matching for "did you mean" suggestions.
+//
+// It has no dependencies on other CalcMark packages so that the lexer, units,
+// and semantic layers can all share a single implementation.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+)
+
+// Distance returns the Levenshtein edit distance between a and b,
+// counting insertions, deletions, and substitutions of runes.
+// Comparison is case-sensitive; callers normalize case when appropriate.
+//
+// Performance: O(len(a) * len(b)) time, O(len(b)) space.
+func Distance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution
+			)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// MaxDistance returns the largest edit distance considered a plausible typo
+// for an input of the given length: 1 for very short inputs, growing to a
+// third of the input length.
+func MaxDistance(input string) int {
+	return max(1, len([]rune(input))/3)
+}
+
+// Closest returns up to limit candidates closest to input, ordered by
+// increasing edit distance (ties broken alphabetically). Matching is
+// case-insensitive, so "usd" suggests "USD". Candidates further than
+// MaxDistance(input) are excluded, as is the input itself. Duplicate
+// candidates are returned once.
+func Closest(input string, candidates []string, limit int) []string {
+	if input == "" || limit <= 0 {
+		return nil
+	}
+
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	lowerInput := strings.ToLower(input)
+	maxDist := MaxDistance(input)
+	seen := make(map[string]bool, len(candidates))
+	var matches []match
+
+	for _, c := range candidates {
+		if seen[c] || c == input {
+			continue
+		}
+		seen[c] = true
+
+		d := Distance(lowerInput, strings.ToLower(c))
+		if d > maxDist {
+			continue
+		}
+		matches = append(matches, match{candidate: c, distance: d})
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.candidate, b.candidate)
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.candidate
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"USD", "USD", 0},
+		{"USX", "USD", 1},
+		{"kitten", "sitting", 3},
+		{"meter", "metre", 2},
+		{"€ur", "eur", 1}, // rune-aware
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"USD", "EUR", "GBP", "UAH", "USN", "AUD"}
+
+	tests := []struct {
+		name  string
+		input string
+		limit int
+		want  []string
+	}{
+		{"single typo", "USX", 3, []string{"USD", "USN"}},
+		{"case insensitive", "eux", 3, []string{"EUR"}},
+		{"limit applied", "USX", 1, []string{"USD"}},
+		{"no close match", "ZZZ", 3, []string{}},
+		{"exact match excluded", "USD", 3, []string{"USN"}},
+		{"case-only difference", "usd", 3, []string{"USD", "USN"}},
+		{"empty input", "", 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Closest(tt.input, candidates, tt.limit)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Closest(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestLongerInputAllowsMoreEdits(t *testing.T) {
+	got := Closest("kilometrs", []string{"kilometers", "kilograms", "meters"}, 3)
+	if len(got) == 0 || got[0] != "kilometers" {
+		t.Errorf("Closest(kilometrs) = %v, want kilometers first", got)
+	}
+}
@@ -111,6 +111,10 @@ var TimeUnits = map[string]string{
 	"day":  "day",
 	"days": "day",
 
+	// Business days, counted on the document's holiday calendar
+	"workday":  "business day",
+	"workdays": "business day",
+
 	// Weeks
 	"week":  "week",
 	"weeks": "week",
@@ -125,3 +129,26 @@ var TimeUnits = map[string]string{
 	"yr":    "year",
 	"yrs":   "year",
 }
+
+// TimeUnitPhrases maps two-word time units to canonical forms
+// Performance: O(1) lookup via map
+var TimeUnitPhrases = map[string]string{
+	"business day":  "business day",
+	"business days": "business day",
+}
+
+// WeekdayNames maps weekday abbreviations and full names to canonical
+// weekday names, for schedules such as "every 2nd Friday"
+// Performance: O(1) lookup via map
+var WeekdayNames = map[string]string{
+	"mon": "Monday", "monday": "Monday",
+	"tue": "Tuesday", "tues": "Tuesday", "tuesday": "Tuesday",
+	"wed": "Wednesday", "wednesday": "Wednesday",
+	"thu": "Thursday", "thur": "Thursday", "thurs": "Thursday", "thursday": "Thursday",
+	"fri": "Friday", "friday": "Friday",
+	"sat": "Saturday", "saturday": "Saturday",
+	"sun": "Sunday", "sunday": "Sunday",
+}
+
+// OrdinalSuffixes are the suffixes of ordinal numbers: 1st, 2nd, 3rd, 4th
+var OrdinalSuffixes = map[string]bool{"st": true, "nd": true, "rd": true, "th": true}
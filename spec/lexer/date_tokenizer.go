@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"slices"
 	"strings"
 	"unicode"
 )
@@ -9,13 +10,11 @@ import (
 // Returns token type and true if matched, otherwise 0 and false
 // Performance: O(1) map lookups
 func (l *Lexer) tryReadDateKeyword() (TokenType, bool) {
-	startPos := l.pos
-
 	// Try simple keywords first (today, tomorrow, yesterday)
 	word := l.peekWord()
 	if tokenType, ok := DateKeywords[strings.ToLower(word)]; ok {
 		// Consume the word
-		l.pos = startPos + len([]rune(word))
+		l.advanceBy(len([]rune(word)))
 		return tokenType, true
 	}
 
@@ -23,7 +22,7 @@ func (l *Lexer) tryReadDateKeyword() (TokenType, bool) {
 	twoWords := l.peekTwoWords()
 	if tokenType, ok := RelativeDateKeywords[strings.ToLower(twoWords)]; ok {
 		// Consume both words
-		l.pos = startPos + len([]rune(twoWords))
+		l.advanceBy(len([]rune(twoWords)))
 		return tokenType, true
 	}
 
@@ -162,15 +161,21 @@ func (l *Lexer) readNumberString() string {
 	return string(num)
 }
 
-// tryReadTimeUnit attempts to read a time unit (day, week, month, etc.)
-// Returns canonical unit name and true if matched, otherwise empty string and false
-// Performance: O(1) map lookup
-func (l *Lexer) tryReadTimeUnit() (string, bool) {
+// tryReadTimeUnit attempts to read a time unit (day, week, business days, etc.)
+// Returns canonical unit name, its length in runes, and true if matched,
+// otherwise empty string, 0, and false
+// Performance: O(1) map lookups
+func (l *Lexer) tryReadTimeUnit() (string, int, bool) {
+	if words := l.peekTwoWords(); strings.Contains(words, " ") {
+		if unit, ok := TimeUnitPhrases[strings.ToLower(words)]; ok {
+			return unit, len([]rune(words)), true
+		}
+	}
 	word := l.peekWord()
 	if unit, ok := TimeUnits[strings.ToLower(word)]; ok {
-		return unit, true
+		return unit, len([]rune(word)), true
 	}
-	return "", false
+	return "", 0, false
 }
 
 // readDurationLiteral reads a duration literal: "2 days", "3 weeks and 4 days"
@@ -201,16 +206,13 @@ func (l *Lexer) readDurationLiteral() Token {
 		l.skipWhitespace()
 
 		// Read time unit
-		unit, ok := l.tryReadTimeUnit()
+		unit, width, ok := l.tryReadTimeUnit()
 		if !ok {
 			return l.errorToken("expected time unit (day, week, month, year)")
 		}
 
-		// Consume unit word
-		unitWord := l.peekWord()
-		for i := 0; i < len([]rune(unitWord)); i++ {
-			l.advance()
-		}
+		// Consume unit words
+		l.advanceBy(width)
 
 		terms = append(terms, term{value, unit})
 
@@ -259,3 +261,130 @@ func (l *Lexer) errorToken(message string) Token {
 		EndPos:   l.pos,
 	}
 }
+
+// tryReadTimeLiteral reads a time of day with an optional zone:
+// "10:30", "10:30:45 PM", "3pm", "3pm EST", "9:00 Tokyo", "14:30 UTC+5:30".
+// The value is "hour:minute:second:period:zone", with empty parts for those
+// not written; the zone is an IANA name or a UTC offset ("UTC-7"). It
+// reports false, consuming nothing, if the input isn't a time.
+func (l *Lexer) tryReadTimeLiteral() (Token, bool) {
+	start := l.save()
+	hour := l.readNumberString()
+	if len(hour) > 2 {
+		l.restore(start)
+		return Token{}, false
+	}
+
+	var minute, second, period string
+	if l.currentChar() == ':' && unicode.IsDigit(l.peek(1)) && unicode.IsDigit(l.peek(2)) && !unicode.IsDigit(l.peek(3)) {
+		l.advance()
+		minute = l.readNumberString()
+		if l.currentChar() == ':' && unicode.IsDigit(l.peek(1)) && unicode.IsDigit(l.peek(2)) && !unicode.IsDigit(l.peek(3)) {
+			l.advance()
+			second = l.readNumberString()
+		}
+		afterMinutes := l.save()
+		l.skipWhitespace()
+		if period = l.tryReadPeriod(); period == "" {
+			l.restore(afterMinutes)
+		}
+	} else if period = l.tryReadPeriod(); period != "" {
+		minute = "00" // "3pm": the period must follow the hour directly
+	} else {
+		l.restore(start)
+		return Token{}, false
+	}
+
+	beforeZone := l.save()
+	l.skipWhitespace()
+	zone := l.tryReadTimeZone()
+	if zone == "" {
+		l.restore(beforeZone)
+	}
+
+	return Token{
+		Type:         TIME_LITERAL,
+		Value:        strings.Join([]string{hour, minute, second, period, zone}, ":"),
+		OriginalText: string(l.text[start.pos:l.pos]),
+		Line:         start.line,
+		Column:       start.column,
+		StartPos:     start.pos,
+		EndPos:       l.pos,
+	}, true
+}
+
+// tryReadPeriod reads "AM" or "PM" in any case, returning it uppercase, or
+// "" if the next word isn't one.
+func (l *Lexer) tryReadPeriod() string {
+	word := strings.ToUpper(l.peekWord())
+	if word != "AM" && word != "PM" {
+		return ""
+	}
+	l.advanceBy(2)
+	return word
+}
+
+// tryReadTimeZone reads a zone after a time: a UTC offset ("UTC-7",
+// "GMT+5:30") or a name LookupTimeZone knows. It returns the offset as
+// written with "UTC", or the IANA name, or "" if there is no zone.
+func (l *Lexer) tryReadTimeZone() string {
+	start := l.save()
+	name := l.readZoneName()
+	if (name == "UTC" || name == "GMT") && (l.currentChar() == '+' || l.currentChar() == '-') && unicode.IsDigit(l.peek(1)) {
+		offset := []rune{l.currentChar()}
+		l.advance()
+		offset = append(offset, []rune(l.readNumberString())...)
+		if l.currentChar() == ':' && unicode.IsDigit(l.peek(1)) {
+			l.advance()
+			offset = append(offset, ':')
+			offset = append(offset, []rune(l.readNumberString())...)
+		}
+		return "UTC" + string(offset)
+	}
+	if zone, ok := LookupTimeZone(name); ok && name != "" {
+		return zone
+	}
+	l.restore(start)
+	return ""
+}
+
+// readZoneName reads a word that may name a time zone: letters and
+// underscores, with slashes and hyphens between them for IANA names such
+// as "America/Port-au-Prince".
+func (l *Lexer) readZoneName() string {
+	var name []rune
+	for {
+		char := l.currentChar()
+		switch {
+		case unicode.IsLetter(char) || char == '_':
+		case (char == '/' || char == '-') && len(name) > 0 && unicode.IsLetter(l.peek(1)) &&
+			(char == '/' || slices.Contains(name, '/')):
+		default:
+			return string(name)
+		}
+		name = append(name, char)
+		l.advance()
+	}
+}
+
+// tryReadTimeZoneName reads a time zone name as a TIMEZONE token whose value
+// is the IANA name. It reports false, consuming nothing, if the next word
+// isn't a zone LookupTimeZone knows.
+func (l *Lexer) tryReadTimeZoneName() (Token, bool) {
+	start := l.save()
+	name := l.readZoneName()
+	zone, ok := LookupTimeZone(name)
+	if !ok || l.isIdentifierChar(l.currentChar(), false) {
+		l.restore(start)
+		return Token{}, false
+	}
+	return Token{
+		Type:         TIMEZONE,
+		Value:        zone,
+		OriginalText: name,
+		Line:         start.line,
+		Column:       start.column,
+		StartPos:     start.pos,
+		EndPos:       l.pos,
+	}, true
+}
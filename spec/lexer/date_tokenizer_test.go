@@ -1,9 +1,11 @@
 package lexer_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
 )
 
 // TestDateKeywordTokenization tests that date keywords are recognized
@@ -97,6 +99,10 @@ func TestDurationLiteralTokenization(t *testing.T) {
 		{"1 month", "1:month"},
 		{"2 weeks and 3 days", "2:week:3:day"},
 		{"1 year and 6 months", "1:year:6:month"},
+		{"10 business days", "10:business day"},
+		{"1 Business Day", "1:business day"},
+		{"3 workdays", "3:business day"},
+		{"2 weeks and 1 business day", "2:week:1:business day"},
 	}
 
 	for _, tt := range tests {
@@ -124,3 +130,106 @@ func TestDurationLiteralTokenization(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkdaysBetweenTokenization(t *testing.T) {
+	for _, input := range []string{"workdays between Jan 5 and Feb 20", "Business Days Between today and Feb 20"} {
+		t.Run(input, func(t *testing.T) {
+			tokens, err := lexer.NewLexer(input).Tokenize()
+			if err != nil {
+				t.Fatalf("Tokenize(%q) error = %v", input, err)
+			}
+			if tokens[0].Type != lexer.FUNC_WORKDAYS || tokens[0].OriginalText != input[:strings.Index(input, "ween")+4] {
+				t.Errorf("first token = %v %q, want FUNC_WORKDAYS", tokens[0].Type, tokens[0].OriginalText)
+			}
+			if tokens[2].Type != lexer.AND {
+				t.Errorf("third token = %v, want AND", tokens[2].Type)
+			}
+		})
+	}
+}
+
+// TestScheduleKeywords tests that "every", "next", and "count" become
+// schedule keywords only where a schedule phrase follows.
+func TestScheduleKeywords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  lexer.TokenType
+	}{
+		{"every 2nd Friday", lexer.SCHEDULE_EVERY},
+		{"every other Friday", lexer.SCHEDULE_EVERY},
+		{"every 15th", lexer.SCHEDULE_EVERY},
+		{"every month", lexer.SCHEDULE_EVERY},
+		{"every 2 weeks", lexer.SCHEDULE_EVERY},
+		{"every + 1", lexer.IDENTIFIER},
+		{"every = 5", lexer.IDENTIFIER},
+		{"next payday", lexer.SCHEDULE_NEXT},
+		{"next * 2", lexer.IDENTIFIER},
+		{"next week", lexer.DATE_NEXT_WEEK},
+		{"count paydays between Mar 1 and Jun 30", lexer.SCHEDULE_COUNT},
+		{"count every Friday between Mar 1 and Jun 30", lexer.SCHEDULE_COUNT},
+		{"count + 1", lexer.IDENTIFIER},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens, err := lexer.NewLexer(tt.input).Tokenize()
+			if err != nil {
+				t.Fatalf("Tokenize(%q) error = %v", tt.input, err)
+			}
+			if tokens[0].Type != tt.want {
+				t.Errorf("first token = %v, want %v", tokens[0].Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeLiteralTokenization(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []lexer.TokenType
+		value string
+	}{
+		{"10:30AM", []lexer.TokenType{lexer.TIME_LITERAL}, "10:30::AM:"},
+		{"3pm EST in PST", []lexer.TokenType{lexer.TIME_LITERAL, lexer.IN, lexer.TIMEZONE}, "3:00::PM:EST"},
+		{"9:00 Tokyo", []lexer.TokenType{lexer.TIME_LITERAL}, "9:00:::Asia/Tokyo"},
+		{"10:30:45 PM UTC+5:30", []lexer.TokenType{lexer.TIME_LITERAL}, "10:30:45:PM:UTC+5:30"},
+		{"14:30 in america/new_york", []lexer.TokenType{lexer.TIME_LITERAL, lexer.IN, lexer.TIMEZONE}, "14:30:::"},
+		{"100 USD in EUR", []lexer.TokenType{lexer.QUANTITY, lexer.IN, lexer.CURRENCY_CODE}, "100:USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens, err := lexer.NewLexer(tt.input).Tokenize()
+			if err != nil {
+				t.Fatalf("Tokenize(%q) error = %v", tt.input, err)
+			}
+			for i, want := range tt.want {
+				if tokens[i].Type != want {
+					t.Errorf("token %d = %v, want %v", i, tokens[i].Type, want)
+				}
+			}
+			if tokens[0].Value != tt.value {
+				t.Errorf("first token value = %q, want %q", tokens[0].Value, tt.value)
+			}
+		})
+	}
+}
+
+// TestTimeZoneAbbreviationsLoad tests that every abbreviation names a zone
+// types.LoadTimeZone knows, fixed offsets for standard and daylight times.
+func TestTimeZoneAbbreviationsLoad(t *testing.T) {
+	for abbrev, zone := range lexer.TimeZoneAbbreviations {
+		location, err := types.LoadTimeZone(zone)
+		if err != nil {
+			t.Errorf("%s: %v", abbrev, err)
+			continue
+		}
+		fixed := abbrev != "UTC" && abbrev != "GMT" && zone == abbrev
+		if want := strings.HasSuffix(abbrev, "ST") || strings.HasSuffix(abbrev, "DT"); fixed != want {
+			t.Errorf("%s maps to %s; standard and daylight times should be fixed", abbrev, zone)
+		}
+		if fixed && location.String() != abbrev {
+			t.Errorf("%s loads as %s", abbrev, location)
+		}
+	}
+}
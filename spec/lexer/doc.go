@@ -30,7 +30,7 @@
 //   - Numbers: integers, decimals, with thousands separators (1,000 or 1_000)
 //   - Multipliers: k, M, B, T (e.g., "1.5M" → 1,500,000)
 //   - Percentages: 20% → 0.20
-//   - Currency: $, €, £, ¥ and ISO 4217 codes (USD, EUR, etc.)
+//   - Currency: $, €, £, ¥, ₿ and ISO 4217 codes (USD, EUR, etc.)
 //   - Identifiers: Unicode-aware including emoji
 //   - Operators: +, -, *, /, %, ^, =, ==, !=, <, >, <=, >=
 //   - Keywords: Reserved words and boolean values
@@ -48,6 +48,12 @@
 //	20%         // Percentage (0.20)
 //	1.2e6       // Scientific notation (1,200,000)
 //
+// NewLexerWithLocale reads numbers in another locale's style, such as
+// 1.000,50 (LocaleDE) or 1 000,50 (LocaleFR). In comma-decimal locales a
+// comma followed by a digit is a decimal mark, so list arguments need a
+// space after the comma: avg(1,5, 2). Token values are always normalized
+// to the 1000.50 form.
+//
 // # Currency Recognition
 //
 // Currency can be specified with symbols or ISO 4217 codes:
@@ -94,4 +100,9 @@
 //   - Identifier: ~900ns
 //
 // See benchmark_test.go in the parser package for detailed metrics.
+//
+// Identifier, unit, and currency values are interned (see [Intern]), so
+// repeated references to a name share one string across all documents.
+// [SymbolFor] returns a name's entry in the same table as a [Symbol],
+// which the interpreter keys its variables by.
 package lexer
@@ -0,0 +1,67 @@
+package lexer
+
+import (
+	"sync"
+	"sync/atomic"
+	"unique"
+)
+
+// Symbol is an interned name. Two symbols are equal exactly when their
+// names are, and comparing or hashing one costs a pointer's worth rather
+// than the name's bytes, so maps keyed by Symbol, such as the
+// interpreter's variables, look names up without rehashing them.
+type Symbol struct {
+	handle unique.Handle[string]
+}
+
+// Name returns the symbol's name, "" for the zero Symbol.
+func (s Symbol) Name() string {
+	if s.IsZero() {
+		return ""
+	}
+	return s.handle.Value()
+}
+
+// IsZero reports whether s is the zero Symbol, which names nothing.
+func (s Symbol) IsZero() bool {
+	return s == Symbol{}
+}
+
+// maxSymbols is the most names the symbol table keeps. Past it, names
+// are still interned but only for as long as something refers to them,
+// so that a long-running server fed endless distinct names doesn't grow
+// the table without bound.
+const maxSymbols = 1 << 20
+
+// symbols is the process-wide symbol table, by name. Keeping each Symbol
+// keeps its handle, and so its canonical string, alive between documents.
+var (
+	symbols     sync.Map // string -> Symbol
+	symbolCount atomic.Int64
+)
+
+// SymbolFor returns the symbol for name from the symbol table, adding it
+// if the table has room.
+func SymbolFor(name string) Symbol {
+	if s, ok := symbols.Load(name); ok {
+		return s.(Symbol)
+	}
+	s := Symbol{unique.Make(name)}
+	if symbolCount.Load() >= maxSymbols {
+		return s
+	}
+	if existing, loaded := symbols.LoadOrStore(s.Name(), s); loaded {
+		return existing.(Symbol)
+	}
+	symbolCount.Add(1)
+	return s
+}
+
+// Intern returns the canonical copy of s from the symbol table (see
+// SymbolFor). The lexer interns identifier, unit, and currency token
+// values, so a document that references the same variable thousands of
+// times keeps a single copy of its name, and comparing two interned
+// strings stops at their shared data pointer.
+func Intern(s string) string {
+	return SymbolFor(s).Name()
+}
@@ -0,0 +1,86 @@
+package lexer
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+func TestInternSharesIdentifierStorage(t *testing.T) {
+	first, err := NewLexer("rent = 5 kg + USD").Tokenize()
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	second, err := NewLexer("total = rent * 2 + USD").Tokenize()
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+
+	find := func(tokens []Token, typ TokenType, value string) Token {
+		t.Helper()
+		for _, tok := range tokens {
+			if tok.Type == typ && tok.Value == value {
+				return tok
+			}
+		}
+		t.Fatalf("no %s token %q", typ, value)
+		return Token{}
+	}
+
+	for _, want := range []struct {
+		typ   TokenType
+		value string
+	}{
+		{IDENTIFIER, "rent"},
+		{CURRENCY_CODE, "USD"},
+	} {
+		a := find(first, want.typ, want.value)
+		b := find(second, want.typ, want.value)
+		if unsafe.StringData(a.Value) != unsafe.StringData(b.Value) {
+			t.Errorf("%s %q not interned: tokens hold separate copies", want.typ, want.value)
+		}
+	}
+}
+
+func TestIntern(t *testing.T) {
+	a := Intern(string([]byte("budget")))
+	b := Intern(string([]byte("budget")))
+	if a != "budget" {
+		t.Errorf("Intern changed the value: %q", a)
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Error("Intern returned separate copies of equal strings")
+	}
+}
+
+// TestInternSurvivesGC tests that interned names stay in the table when
+// nothing else refers to them, so later documents share the same copy.
+func TestInternSurvivesGC(t *testing.T) {
+	first := unsafe.StringData(Intern(string([]byte("quarterly_budget"))))
+	runtime.GC()
+	runtime.GC()
+	second := unsafe.StringData(Intern(string([]byte("quarterly_budget"))))
+	if first != second {
+		t.Error("interned name was freed by the garbage collector")
+	}
+}
+
+func TestSymbolFor(t *testing.T) {
+	a := SymbolFor(string([]byte("rent")))
+	b := SymbolFor(string([]byte("rent")))
+	if a != b {
+		t.Error("equal names have different symbols")
+	}
+	if a == SymbolFor("rents") {
+		t.Error("different names have the same symbol")
+	}
+	if a.Name() != "rent" {
+		t.Errorf("Name() = %q, want rent", a.Name())
+	}
+	if !(Symbol{}).IsZero() || a.IsZero() {
+		t.Error("IsZero wrong")
+	}
+	if (Symbol{}).Name() != "" {
+		t.Error("zero Symbol has a name")
+	}
+}
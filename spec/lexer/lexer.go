@@ -15,6 +15,14 @@ var BooleanKeywords = map[string]bool{
 	"false": true,
 }
 
+// notUnits lists words that are never units, though they aren't keywords,
+// so a number before one stays a number: "change from 80 to 100 in %",
+// "increase 100 by 10%", "from 2015 to 2025" in adjust().
+var notUnits = map[string]bool{
+	"to": true,
+	"by": true,
+}
+
 // ReservedKeywords defines reserved keywords (Go spec compliant + future control flow).
 // This map is exported for grammar introspection.
 // See: https://go.dev/ref/spec#Keywords
@@ -70,16 +78,32 @@ type Lexer struct {
 	pos    int
 	line   int
 	column int
+	locale NumberLocale
 }
 
 // NewLexer creates a new lexer for the given text
 func NewLexer(text string) *Lexer {
-	return &Lexer{
+	return NewLexerWithLocale(text, LocaleUS)
+}
+
+// NewLexerWithLocale creates a lexer that reads number literals written in
+// loc's format, e.g. "1.000,50" with LocaleDE.
+//
+// A leading UTF-8 byte order mark is skipped, and the "\r" of a "\r\n"
+// line ending takes no column, so positions match the text as an editor
+// shows it.
+func NewLexerWithLocale(text string, loc NumberLocale) *Lexer {
+	l := &Lexer{
 		text:   []rune(text),
 		pos:    0,
 		line:   1,
 		column: 1,
+		locale: loc,
+	}
+	if l.currentChar() == '\ufeff' {
+		l.pos++ // Byte order mark; offsets still index text
 	}
+	return l
 }
 
 // currentChar returns the current character or 0 if at end
@@ -102,16 +126,41 @@ func (l *Lexer) peek(offset int) rune {
 // advance moves to the next character
 func (l *Lexer) advance() {
 	if l.pos < len(l.text) {
-		if l.text[l.pos] == '\n' {
+		switch {
+		case l.text[l.pos] == '\n':
 			l.line++
 			l.column = 1
-		} else {
+		case l.text[l.pos] == '\r' && l.peek(1) == '\n':
+			// Part of the line ending
+		default:
 			l.column++
 		}
 		l.pos++
 	}
 }
 
+// advanceBy moves n characters forward.
+func (l *Lexer) advanceBy(n int) {
+	for range n {
+		l.advance()
+	}
+}
+
+// lexState is a position in the input to backtrack to.
+type lexState struct {
+	pos, line, column int
+}
+
+// save returns the current position, for restore.
+func (l *Lexer) save() lexState {
+	return lexState{l.pos, l.line, l.column}
+}
+
+// restore backtracks to a position returned by save.
+func (l *Lexer) restore(s lexState) {
+	l.pos, l.line, l.column = s.pos, s.line, s.column
+}
+
 // skipWhitespace skips whitespace except newlines
 func (l *Lexer) skipWhitespace() {
 	for l.currentChar() == ' ' || l.currentChar() == '\t' || l.currentChar() == '\r' {
@@ -119,10 +168,27 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
-// isValidThousandsSeparator checks if comma/underscore at current position is a valid thousands separator
+// skipResultComment skips a "# = ..." result comment up to the end of
+// its line, reporting whether there was one at the current position.
+func (l *Lexer) skipResultComment() bool {
+	offset := 1
+	for l.peek(offset) == ' ' || l.peek(offset) == '\t' {
+		offset++
+	}
+	if l.peek(offset) != '=' {
+		return false
+	}
+	for l.currentChar() != '\n' && l.currentChar() != 0 {
+		l.advance()
+	}
+	return true
+}
+
+// isValidThousandsSeparator checks if a group separator (comma/underscore in the
+// default locale) at current position is a valid thousands separator
 // Returns true if followed by exactly 3 digits (and then non-digit or another separator)
 func (l *Lexer) isValidThousandsSeparator(separatorChar rune) bool {
-	if separatorChar != ',' && separatorChar != '_' {
+	if !l.isGroupSeparator(separatorChar) {
 		return false
 	}
 
@@ -152,7 +218,7 @@ func (l *Lexer) readNumber() Token {
 		if unicode.IsDigit(char) {
 			numStr.WriteRune(char)
 			l.advance()
-		} else if (char == ',' || char == '_') && l.isValidThousandsSeparator(char) {
+		} else if l.isValidThousandsSeparator(char) {
 			// Keep separators in the value for now, or strip them?
 			// BNF expects: _digit { _digit | '_' }
 			// So we should keep underscores if we want to match BNF exactly?
@@ -181,7 +247,7 @@ func (l *Lexer) readNumber() Token {
 			// `strconv` does NOT like commas.
 			// So we should probably strip commas in the Value.
 			l.advance() // Skip separator
-		} else if char == '.' {
+		} else if l.isDecimalMark(char) {
 			// Decimal point - handled in next loop
 			break
 		} else {
@@ -192,7 +258,8 @@ func (l *Lexer) readNumber() Token {
 	// Read decimal part - only consume the dot if followed by at least one digit.
 	// "2.5" is valid, but "2." is not (trailing dot without decimals is invalid).
 	// This ensures "2. Second" (markdown ordered list) doesn't tokenize as a calculation.
-	if l.currentChar() == '.' && unicode.IsDigit(l.peek(1)) {
+	// The value always uses '.', whatever the locale's decimal mark.
+	if l.isDecimalMark(l.currentChar()) && unicode.IsDigit(l.peek(1)) {
 		numStr.WriteRune('.')
 		l.advance()
 		for l.currentChar() != 0 {
@@ -290,7 +357,7 @@ func (l *Lexer) readNumber() Token {
 
 	// Check for unit after number (kg, meters, USD, apples, widgets, etc.)
 	if l.currentChar() == ' ' {
-		savedPos := l.pos
+		savedPos := l.save()
 		l.advance() // Skip space
 
 		// Try to read a unit identifier
@@ -301,15 +368,15 @@ func (l *Lexer) readNumber() Token {
 			// Don't treat reserved keywords as units (e.g., "5 and" should not be a quantity)
 			if _, isReserved := ReservedKeywords[strings.ToLower(unitStr)]; isReserved {
 				// This is a reserved keyword, not a unit - backtrack
-				l.pos = savedPos
-			} else if BooleanKeywords[strings.ToLower(unitStr)] {
-				// Boolean keyword, not a unit - backtrack
-				l.pos = savedPos
+				l.restore(savedPos)
+			} else if BooleanKeywords[strings.ToLower(unitStr)] || notUnits[strings.ToLower(unitStr)] {
+				// Boolean keyword or a word that's never a unit - backtrack
+				l.restore(savedPos)
 			} else {
 				// Check for multi-word units: "1 nautical mile", "5 metric tons", "10 square meters"
 				// Look ahead for a second identifier that might form a multi-word unit
 				if l.currentChar() == ' ' {
-					savedPos2 := l.pos
+					savedPos2 := l.save()
 					l.advance() // Skip second space
 
 					if l.isIdentifierChar(l.currentChar(), true) {
@@ -319,10 +386,10 @@ func (l *Lexer) readNumber() Token {
 						// Check if second word is also a reserved keyword
 						if _, isReserved := ReservedKeywords[strings.ToLower(secondWord)]; isReserved {
 							// Second word is reserved, backtrack
-							l.pos = savedPos2
+							l.restore(savedPos2)
 						} else if BooleanKeywords[strings.ToLower(secondWord)] {
 							// Second word is boolean, backtrack
-							l.pos = savedPos2
+							l.restore(savedPos2)
 						} else {
 							// Use canonical unit registry to check for multi-word units
 							if combined := units.IsMultiWordUnit(unitStr, secondWord); combined != "" {
@@ -332,7 +399,7 @@ func (l *Lexer) readNumber() Token {
 
 								// Check for third word (e.g., "meters per second", "kilometers per hour")
 								if l.currentChar() == ' ' {
-									savedPos3 := l.pos
+									savedPos3 := l.save()
 									l.advance() // Skip third space
 
 									if l.isIdentifierChar(l.currentChar(), true) {
@@ -342,10 +409,10 @@ func (l *Lexer) readNumber() Token {
 										// Check if third word is also a reserved keyword
 										if _, isReserved := ReservedKeywords[strings.ToLower(thirdWord)]; isReserved {
 											// Third word is reserved, backtrack
-											l.pos = savedPos3
+											l.restore(savedPos3)
 										} else if BooleanKeywords[strings.ToLower(thirdWord)] {
 											// Third word is boolean, backtrack
-											l.pos = savedPos3
+											l.restore(savedPos3)
 										} else {
 											// Check if this forms a valid 3-word unit
 											if combined3 := units.IsMultiWordUnit(unitStr, thirdWord); combined3 != "" {
@@ -353,23 +420,23 @@ func (l *Lexer) readNumber() Token {
 												unitStr = combined3
 											} else {
 												// Not a 3-word unit, backtrack third word
-												l.pos = savedPos3
+												l.restore(savedPos3)
 											}
 										}
 									} else {
-										l.pos = savedPos3
+										l.restore(savedPos3)
 									}
 								}
 
 								_ = isMultiWord // Mark as used
 							} else {
 								// Not a multi-word unit, backtrack
-								l.pos = savedPos2
+								l.restore(savedPos2)
 							}
 						}
 					} else {
 						// No second identifier, backtrack
-						l.pos = savedPos2
+						l.restore(savedPos2)
 					}
 				}
 
@@ -390,7 +457,7 @@ func (l *Lexer) readNumber() Token {
 				}
 			}
 		} else {
-			l.pos = savedPos
+			l.restore(savedPos)
 		}
 	}
 
@@ -418,7 +485,7 @@ func (l *Lexer) readCurrency() (Token, error) {
 
 	return Token{
 		Type:         CURRENCY_SYM,
-		Value:        string(l.text[startPos:l.pos]),
+		Value:        Intern(string(l.text[startPos:l.pos])),
 		OriginalText: string(l.text[startPos:l.pos]),
 		Line:         startLine,
 		Column:       startColumn,
@@ -452,7 +519,7 @@ func (l *Lexer) readCurrencyCodeQuantity() Token {
 	if !isValidCurrencyCode(code) {
 		return Token{
 			Type:     IDENTIFIER,
-			Value:    string(l.text[startPos:l.pos]),
+			Value:    Intern(string(l.text[startPos:l.pos])),
 			Line:     startLine,
 			Column:   startColumn,
 			StartPos: startPos,
@@ -465,7 +532,7 @@ func (l *Lexer) readCurrencyCodeQuantity() Token {
 		// No number after currency code - treat as identifier
 		return Token{
 			Type:     IDENTIFIER,
-			Value:    code,
+			Value:    Intern(code),
 			Line:     startLine,
 			Column:   startColumn,
 			StartPos: startPos,
@@ -485,12 +552,13 @@ func (l *Lexer) readCurrencyCodeQuantity() Token {
 			numStr.WriteRune(char)
 			l.advance()
 			lastWasSeparator = false
-		} else if char == '.' && !hasDecimal {
-			numStr.WriteRune(char)
+		} else if l.isDecimalMark(char) && !hasDecimal {
+			numStr.WriteRune('.')
 			l.advance()
 			hasDecimal = true
 			lastWasSeparator = false
-		} else if (char == ',' || char == '_') && !lastWasSeparator {
+		} else if l.isGroupSeparator(char) && !lastWasSeparator &&
+			(!unicode.IsSpace(char) || l.isValidThousandsSeparator(char)) {
 			// Thousands separator - validate later, skip for now
 			l.advance()
 			lastWasSeparator = true
@@ -592,6 +660,36 @@ func isValidCurrencyCode(s string) bool {
 }
 
 // readIdentifier reads an identifier (variable name)
+// readString reads quoted text, such as "prices.csv". The token's value is
+// the text between the quotes, which can't contain a quote or a newline.
+func (l *Lexer) readString() (Token, error) {
+	startPos, startLine, startCol := l.pos, l.line, l.column
+	l.advance() // opening quote
+
+	var text strings.Builder
+	for l.currentChar() != '"' {
+		if l.currentChar() == 0 || l.currentChar() == '\n' {
+			return Token{}, &LexerError{
+				Message: `Unterminated text: missing closing '"'`,
+				Line:    startLine,
+				Column:  startCol,
+			}
+		}
+		text.WriteRune(l.currentChar())
+		l.advance()
+	}
+	l.advance() // closing quote
+
+	return Token{
+		Type:     STRING,
+		Value:    text.String(),
+		Line:     startLine,
+		Column:   startCol,
+		StartPos: startPos,
+		EndPos:   l.pos,
+	}, nil
+}
+
 // Identifiers support any Unicode characters including emoji and international characters
 // NOTE: Spaces are NOT allowed in identifiers (this allows multi-token function names)
 // SPECIAL: Checks for currency code prefix (3 uppercase letters) before reading full identifier
@@ -654,7 +752,7 @@ func (l *Lexer) readIdentifier() Token {
 		isFirst = false
 	}
 
-	identStr := identifier.String()
+	identStr := Intern(identifier.String())
 	lowerIdent := strings.ToLower(identStr)
 	endPos := l.pos
 
@@ -739,7 +837,7 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 		}
 
 		// Currency - support multiple currency symbols
-		if char == '$' || char == '€' || char == '£' || char == '¥' {
+		if char == '$' || char == '€' || char == '£' || char == '¥' || char == '₿' {
 			token, err := l.readCurrency()
 			if err != nil {
 				return nil, err
@@ -750,21 +848,27 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 
 		// Number
 		if unicode.IsDigit(char) {
+			// Times of day: "10:30", "3pm EST"
+			if token, ok := l.tryReadTimeLiteral(); ok {
+				tokens = append(tokens, token)
+				continue
+			}
+
 			// Check if this starts a duration: NUMBER + UNIT
 			// Look ahead to see if followed by time unit
-			savedPos := l.pos
+			savedPos := l.save()
 			_ = l.readNumberString() // Read but don't use yet
 			l.skipWhitespace()
 
-			if _, ok := l.tryReadTimeUnit(); ok {
+			if _, _, ok := l.tryReadTimeUnit(); ok {
 				// This is a duration literal
-				l.pos = savedPos // Reset to start
+				l.restore(savedPos) // Reset to start
 				tokens = append(tokens, l.readDurationLiteral())
 				continue
 			}
 
 			// Not a duration, just a regular number
-			l.pos = savedPos
+			l.restore(savedPos)
 			tokens = append(tokens, l.readNumber())
 			continue
 		}
@@ -772,22 +876,30 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 		// Identifier or date/duration keywords (check before operators)
 		if l.isIdentifierChar(char, true) {
 			// Try date keywords first (today, this week, etc.)
-			startPos := l.pos
+			start := l.save()
 			if tokenType, ok := l.tryReadDateKeyword(); ok {
-				endPos := l.pos
+				startPos, endPos := start.pos, l.pos
 				keywordText := string(l.text[startPos:endPos])
 				tokens = append(tokens, Token{
 					Type:         tokenType,
 					Value:        keywordText, // Store actual keyword text, not token type
 					OriginalText: keywordText,
-					Line:         l.line,
-					Column:       l.column,
+					Line:         start.line,
+					Column:       start.column,
 					StartPos:     startPos,
 					EndPos:       endPos,
 				})
 				continue
 			}
 
+			// Time zone after "in": "3pm EST in PST", "in America/New_York"
+			if len(tokens) > 0 && tokens[len(tokens)-1].Type == IN {
+				if token, ok := l.tryReadTimeZoneName(); ok {
+					tokens = append(tokens, token)
+					continue
+				}
+			}
+
 			// Try month names (for date literals)
 			if _, ok := l.tryReadMonthName(); ok {
 				// This is a date literal
@@ -943,7 +1055,35 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 			continue
 		}
 
-		// Comma (for function arguments)
+		// Brackets (for list literals)
+		if char == '[' {
+			tokens = append(tokens, l.makeToken(LBRACKET, "[", 1))
+			l.advance()
+			continue
+		}
+
+		if char == ']' {
+			tokens = append(tokens, l.makeToken(RBRACKET, "]", 1))
+			l.advance()
+			// A column of an indexed table: schedule[12].balance
+			if l.currentChar() == '.' && l.isIdentifierChar(l.peek(1), true) {
+				tokens = append(tokens, l.makeToken(DOT, ".", 1))
+				l.advance()
+			}
+			continue
+		}
+
+		// Quoted text (for file names and paths passed to lookup() and jsonpath())
+		if char == '"' {
+			tok, err := l.readString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			continue
+		}
+
+		// Comma (for function arguments and list elements)
 		if char == ',' {
 			tokens = append(tokens, l.makeToken(COMMA, ",", 1))
 			l.advance()
@@ -981,6 +1121,12 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 			// @ not followed by identifier - fall through to unknown character error
 		}
 
+		// "# = ..." result comments, such as cm convert --provenance writes
+		// after each calculation, are skipped when they start a line
+		if char == '#' && (len(tokens) == 0 || tokens[len(tokens)-1].Type == NEWLINE) && l.skipResultComment() {
+			continue
+		}
+
 		// Octothorpe - not allowed mid-line in calculations
 		if char == '#' {
 			return nil, &LexerError{
@@ -1017,6 +1163,12 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 //
 //	"average" + "of" → FUNC_AVERAGE_OF
 //	"square" + "root" + "of" → FUNC_SQUARE_ROOT_OF
+//	"standard" + "deviation" + "of" → FUNC_STDEV_OF
+//	"workdays" + "between" → FUNC_WORKDAYS
+//	"business" + "days" + "between" → FUNC_WORKDAYS
+//
+// It also marks "every", "next", and "count" that start schedule phrases
+// (see scheduleKeyword).
 func combineMultiTokenFunctions(tokens []Token) []Token {
 	result := make([]Token, 0, len(tokens))
 	i := 0
@@ -1076,6 +1228,54 @@ func combineMultiTokenFunctions(tokens []Token) []Token {
 			}
 		}
 
+		// Check for "standard deviation of" (case insensitive)
+		if token.Type == IDENTIFIER && strings.ToLower(token.Value) == "standard" {
+			if i+2 < len(tokens) {
+				deviationToken := tokens[i+1]
+				ofToken := tokens[i+2]
+				if deviationToken.Type == IDENTIFIER && strings.ToLower(deviationToken.Value) == "deviation" &&
+					(ofToken.Type == OF || (ofToken.Type == IDENTIFIER && strings.ToLower(ofToken.Value) == "of")) {
+					result = append(result, Token{
+						Type:         FUNC_STDEV_OF,
+						Value:        "standard deviation of",
+						OriginalText: token.Value + " " + deviationToken.Value + " " + ofToken.Value,
+						Line:         token.Line,
+						Column:       token.Column,
+						StartPos:     token.StartPos,
+						EndPos:       ofToken.EndPos,
+					})
+					i += 3 // Skip all three tokens
+					continue
+				}
+			}
+		}
+
+		// Check for "workdays between" and "business days between" (case insensitive)
+		if n := workdaysPhraseLength(tokens[i:]); n > 0 {
+			last := tokens[i+n-1]
+			words := make([]string, n)
+			for j := range n {
+				words[j] = tokens[i+j].Value
+			}
+			result = append(result, Token{
+				Type:         FUNC_WORKDAYS,
+				Value:        "workdays between",
+				OriginalText: strings.Join(words, " "),
+				Line:         token.Line,
+				Column:       token.Column,
+				StartPos:     token.StartPos,
+				EndPos:       last.EndPos,
+			})
+			i += n
+			continue
+		}
+
+		// "every", "next", and "count" start schedule phrases only where a
+		// schedule follows, so they remain usable as variable names
+		if t := scheduleKeyword(tokens[i:]); t != token.Type {
+			token.Type = t
+		}
+
 		// No multi-token match, keep original token
 		result = append(result, token)
 		i++
@@ -1084,6 +1284,68 @@ func combineMultiTokenFunctions(tokens []Token) []Token {
 	return result
 }
 
+// workdaysPhraseLength returns how many tokens at the start of tokens spell
+// "workdays between" or "business days between", or 0 if they don't.
+func workdaysPhraseLength(tokens []Token) int {
+	for _, phrase := range [][]string{{"workdays", "between"}, {"business", "days", "between"}} {
+		if len(tokens) < len(phrase) {
+			continue
+		}
+		matched := true
+		for j, word := range phrase {
+			if tokens[j].Type != IDENTIFIER || strings.ToLower(tokens[j].Value) != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return len(phrase)
+		}
+	}
+	return 0
+}
+
+// scheduleKeyword returns the schedule keyword type of the token starting
+// tokens, or its own type if it doesn't start a schedule phrase:
+//
+//	every 2nd Friday, every other Friday, every 15th, every month, every 2 weeks
+//	next payday
+//	count paydays between ..., count every Friday between ...
+func scheduleKeyword(tokens []Token) TokenType {
+	if len(tokens) < 2 || tokens[0].Type != IDENTIFIER {
+		return tokens[0].Type
+	}
+	next := tokens[1]
+	word := strings.ToLower(next.Value)
+	switch strings.ToLower(tokens[0].Value) {
+	case "every":
+		switch next.Type {
+		case NUMBER, DURATION_LITERAL:
+			return SCHEDULE_EVERY
+		case IDENTIFIER:
+			if _, ok := WeekdayNames[word]; ok || word == "other" {
+				return SCHEDULE_EVERY
+			}
+			if _, ok := TimeUnits[word]; ok {
+				return SCHEDULE_EVERY
+			}
+		}
+	case "next":
+		if next.Type == IDENTIFIER {
+			return SCHEDULE_NEXT
+		}
+	case "count":
+		if next.Type == IDENTIFIER && word == "every" {
+			return SCHEDULE_COUNT
+		}
+		if next.Type == IDENTIFIER && len(tokens) > 2 &&
+			tokens[2].Type == IDENTIFIER && strings.ToLower(tokens[2].Value) == "between" {
+			return SCHEDULE_COUNT
+		}
+	}
+	return tokens[0].Type
+}
+
 // TokenizeOld scans the input string and returns a slice of tokens.
 // Deprecated: Use Tokenize() from adapter.go instead.
 func TokenizeOld(input string) ([]Token, error) {
@@ -36,6 +36,12 @@ func TestCurrencySymbols(t *testing.T) {
 			wantTokens: []TokenType{CURRENCY_SYM, NUMBER, EOF},
 			wantValues: []string{"¥", "100", ""},
 		},
+		{
+			name:       "bitcoin sign",
+			input:      "₿0.5",
+			wantTokens: []TokenType{CURRENCY_SYM, NUMBER, EOF},
+			wantValues: []string{"₿", "0.5", ""},
+		},
 		{
 			name:       "euro with thousands",
 			input:      "€5,000",
@@ -0,0 +1,66 @@
+package lexer
+
+import "testing"
+
+// TestLineEndingPositions tests that CRLF line endings and a byte order
+// mark don't shift token columns
+func TestLineEndingPositions(t *testing.T) {
+	want, err := NewLexer("x = 1\ny = x * 2\n").Tokenize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, input := range []string{"x = 1\r\ny = x * 2\r\n", "\ufeffx = 1\ny = x * 2\n"} {
+		got, err := NewLexer(input).Tokenize()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", input, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%q: got %d tokens, want %d", input, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Type != want[i].Type || got[i].Line != want[i].Line || got[i].Column != want[i].Column {
+				t.Errorf("%q: token %d = %s at %d:%d, want %s at %d:%d", input, i,
+					got[i].Type, got[i].Line, got[i].Column, want[i].Type, want[i].Line, want[i].Column)
+			}
+		}
+	}
+}
+
+// TestColumnsAfterLookahead tests that tokens after numbers, quantities,
+// and date keywords, which the lexer reads ahead of and backtracks, start
+// at their own column
+func TestColumnsAfterLookahead(t *testing.T) {
+	tokens, err := NewLexer("d = 5 meters in feet\nx = 100 + 2 and today + 1\n").Tokenize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string][2]int{"in": {1, 14}, "feet": {1, 17}, "+": {2, 9}, "2": {2, 11}, "and": {2, 13}, "today": {2, 17}, "1": {2, 25}}
+	for _, tok := range tokens {
+		if pos, ok := want[tok.Value]; ok {
+			if tok.Line != pos[0] || tok.Column != pos[1] {
+				t.Errorf("%q at %d:%d, want %d:%d", tok.Value, tok.Line, tok.Column, pos[0], pos[1])
+			}
+			delete(want, tok.Value)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("tokens not found: %v", want)
+	}
+}
+
+func TestColumnsCountTabsAsOne(t *testing.T) {
+	// Columns are characters, as in LSP; displays expand tabs themselves
+	tokens, err := NewLexer("\tx =\t5\n").Tokenize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]int{"x": 2, "=": 4, "5": 6}
+	for _, tok := range tokens {
+		if column, ok := want[tok.Value]; ok && tok.Column != column {
+			t.Errorf("%q at column %d, want %d", tok.Value, tok.Column, column)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package lexer
+
+import "testing"
+
+// TestLocaleNumbers tests that number literals follow the lexer's locale
+func TestLocaleNumbers(t *testing.T) {
+	tests := []struct {
+		name       string
+		locale     NumberLocale
+		input      string
+		wantTokens []TokenType
+		wantValues []string
+	}{
+		{"US default", LocaleUS, "1,000.50", []TokenType{NUMBER, EOF}, []string{"1000.50", ""}},
+		{"DE thousands and decimal", LocaleDE, "1.000,50", []TokenType{NUMBER, EOF}, []string{"1000.50", ""}},
+		{"DE decimal only", LocaleDE, "3,14", []TokenType{NUMBER, EOF}, []string{"3.14", ""}},
+		{"DE millions", LocaleDE, "1.234.567", []TokenType{NUMBER, EOF}, []string{"1234567", ""}},
+		{"FR space groups", LocaleFR, "1 000,50", []TokenType{NUMBER, EOF}, []string{"1000.50", ""}},
+		{"FR narrow no-break space", LocaleFR, "1 000,5", []TokenType{NUMBER, EOF}, []string{"1000.5", ""}},
+		{"CH apostrophe", LocaleCH, "1'000.50", []TokenType{NUMBER, EOF}, []string{"1000.50", ""}},
+		{
+			"DE argument separator needs a space",
+			LocaleDE, "avg(1,5, 2)",
+			[]TokenType{FUNC_AVG, LPAREN, NUMBER, COMMA, NUMBER, RPAREN, EOF},
+			[]string{"avg", "(", "1.5", ",", "2", ")", ""},
+		},
+		{
+			"FR spaces still separate operators",
+			LocaleFR, "2 000 + 1",
+			[]TokenType{NUMBER, PLUS, NUMBER, EOF},
+			[]string{"2000", "+", "1", ""},
+		},
+		{
+			"DE currency code quantity",
+			LocaleDE, "1.000,50 EUR",
+			[]TokenType{QUANTITY, EOF},
+			[]string{"1000.50:EUR", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := NewLexerWithLocale(tt.input, tt.locale).Tokenize()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tokens) != len(tt.wantTokens) {
+				t.Fatalf("got %d tokens %v, want %d", len(tokens), tokens, len(tt.wantTokens))
+			}
+			for i, tok := range tokens {
+				if tok.Type != tt.wantTokens[i] || tok.Value != tt.wantValues[i] {
+					t.Errorf("token %d = %s %q, want %s %q", i, tok.Type, tok.Value, tt.wantTokens[i], tt.wantValues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		tag     string
+		decimal rune
+		wantErr bool
+	}{
+		{"en-US", '.', false},
+		{"de-DE", ',', false},
+		{"de_AT", ',', false},
+		{"fr", ',', false},
+		{"de-CH", '.', false},
+		{"FR-ca", ',', false},
+		{"xx-YY", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			loc, err := LookupLocale(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupLocale(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if err == nil && loc.Decimal != tt.decimal {
+				t.Errorf("LookupLocale(%q).Decimal = %q, want %q", tt.tag, loc.Decimal, tt.decimal)
+			}
+			if err == nil && loc.Tag != tt.tag {
+				t.Errorf("LookupLocale(%q).Tag = %q", tt.tag, loc.Tag)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package lexer
+
+import "testing"
+
+func TestStringToken(t *testing.T) {
+	tokens, err := NewLexer(`lookup("price list.csv", sku, "A-100", price)`).Tokenize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []Token
+	for _, tok := range tokens {
+		if tok.Type == STRING {
+			texts = append(texts, tok)
+		}
+	}
+	if len(texts) != 2 || texts[0].Value != "price list.csv" || texts[1].Value != "A-100" {
+		t.Fatalf("STRING tokens = %+v", texts)
+	}
+	if first := texts[0]; first.Column != 8 || first.EndPos-first.StartPos != len(`"price list.csv"`) {
+		t.Errorf("first STRING at column %d, %d runes long", first.Column, first.EndPos-first.StartPos)
+	}
+
+	for _, input := range []string{`x = "open`, "x = \"split\nline\""} {
+		if _, err := NewLexer(input).Tokenize(); err == nil {
+			t.Errorf("%q: expected an unterminated text error", input)
+		}
+	}
+}
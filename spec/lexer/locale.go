@@ -0,0 +1,74 @@
+package lexer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NumberLocale describes how number literals are written in source text:
+// the decimal mark and the digit-group separators allowed between groups of
+// three digits. Underscore is accepted as a group separator in every locale.
+// Token values are always normalized to "1000.50" regardless of locale.
+type NumberLocale struct {
+	Tag     string // Locale tag it was looked up by, e.g. "de-DE"
+	Decimal rune   // Decimal mark: '.' or ','
+	Groups  []rune // Thousands separators: ',' or '.' or spaces
+}
+
+// Built-in number locales.
+var (
+	// LocaleUS writes 1,000.50 (the default).
+	LocaleUS = NumberLocale{Tag: "en-US", Decimal: '.', Groups: []rune{','}}
+
+	// LocaleDE writes 1.000,50 (German, Spanish, Italian, Dutch, ...).
+	LocaleDE = NumberLocale{Tag: "de-DE", Decimal: ',', Groups: []rune{'.'}}
+
+	// LocaleFR writes 1 000,50 with a space, no-break space, or narrow
+	// no-break space between groups (French, Nordic, Slavic, ...).
+	LocaleFR = NumberLocale{Tag: "fr-FR", Decimal: ',', Groups: []rune{' ', '\u00a0', '\u202f'}}
+
+	// LocaleCH writes 1'000.50 (Swiss German, Swiss Italian).
+	LocaleCH = NumberLocale{Tag: "de-CH", Decimal: '.', Groups: []rune{'\'', '\u2019'}}
+)
+
+// localeStyles maps full tags and bare languages (lowercase) to their number style.
+// Full tags take precedence, so "de-CH" differs from "de".
+var localeStyles = map[string]NumberLocale{
+	"en": LocaleUS, "ja": LocaleUS, "zh": LocaleUS, "ko": LocaleUS, "he": LocaleUS, "th": LocaleUS,
+
+	"de": LocaleDE, "es": LocaleDE, "it": LocaleDE, "nl": LocaleDE, "pt": LocaleDE,
+	"da": LocaleDE, "id": LocaleDE, "tr": LocaleDE, "el": LocaleDE, "ro": LocaleDE,
+
+	"fr": LocaleFR, "ru": LocaleFR, "pl": LocaleFR, "cs": LocaleFR, "sk": LocaleFR,
+	"sv": LocaleFR, "nb": LocaleFR, "no": LocaleFR, "fi": LocaleFR, "uk": LocaleFR,
+	"hu": LocaleFR, "bg": LocaleFR, "lt": LocaleFR, "lv": LocaleFR, "et": LocaleFR,
+
+	"de-ch": LocaleCH, "it-ch": LocaleCH, "fr-ch": LocaleFR, "es-mx": LocaleUS,
+}
+
+// LookupLocale returns the number locale for a tag like "de-DE", "fr_FR", or "de".
+// The returned locale's Tag is the tag as given.
+func LookupLocale(tag string) (NumberLocale, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	loc, ok := localeStyles[normalized]
+	if !ok {
+		language, _, _ := strings.Cut(normalized, "-")
+		loc, ok = localeStyles[language]
+	}
+	if !ok {
+		return NumberLocale{}, fmt.Errorf("unknown locale '%s'", tag)
+	}
+	loc.Tag = strings.TrimSpace(tag)
+	return loc, nil
+}
+
+// isGroupSeparator reports whether char separates digit groups in l's locale.
+func (l *Lexer) isGroupSeparator(char rune) bool {
+	return char == '_' || slices.Contains(l.locale.Groups, char)
+}
+
+// isDecimalMark reports whether char is l's locale decimal mark.
+func (l *Lexer) isDecimalMark(char rune) bool {
+	return char == l.locale.Decimal
+}
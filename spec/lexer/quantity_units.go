@@ -96,7 +96,7 @@ func IsQuantityUnit(unit string) bool {
 	}
 
 	// Currency symbols
-	if unit == "$" || unit == "€" || unit == "£" || unit == "¥" {
+	if unit == "$" || unit == "€" || unit == "£" || unit == "¥" || unit == "₿" {
 		return true
 	}
 
@@ -0,0 +1,76 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RetokenizeLine returns tokens with line (1-indexed) replaced by text,
+// re-lexing only that line. tokens must come from Tokenize over the whole
+// source, read with loc; tokens on other lines are reused with their
+// positions shifted, so the result matches tokenizing the edited source.
+//
+// A lexer error in text is reported at line.
+func RetokenizeLine(tokens []Token, line int, text string, loc NumberLocale) ([]Token, error) {
+	if strings.ContainsRune(text, '\n') {
+		return nil, fmt.Errorf("line %d: replacement text contains a newline", line)
+	}
+	if len(tokens) == 0 || tokens[len(tokens)-1].Type != EOF {
+		return nil, fmt.Errorf("tokens do not end with EOF")
+	}
+	eof := tokens[len(tokens)-1]
+	if line < 1 || line > eof.Line {
+		return nil, fmt.Errorf("line %d out of range 1-%d", line, eof.Line)
+	}
+
+	lineTokens, err := NewLexerWithLocale(text, loc).Tokenize()
+	if err != nil {
+		if le, ok := err.(*LexerError); ok {
+			le.Line = line
+		}
+		return nil, err
+	}
+	newEOF := lineTokens[len(lineTokens)-1]
+	lineTokens = lineTokens[:len(lineTokens)-1]
+
+	// The old line's tokens, ending with its newline unless it is the last
+	// line, are tokens[first:end]
+	first := 0
+	for first < len(tokens)-1 && tokens[first].Line < line {
+		first++
+	}
+	end := first
+	for end < len(tokens)-1 && tokens[end].Line == line {
+		end++
+	}
+
+	lineStart := 0
+	if first > 0 {
+		lineStart = tokens[first-1].EndPos // Previous line's newline
+	}
+
+	result := make([]Token, 0, len(tokens)-(end-first)+len(lineTokens))
+	result = append(result, tokens[:first]...)
+	for _, tok := range lineTokens {
+		tok.Line = line
+		tok.StartPos += lineStart
+		tok.EndPos += lineStart
+		result = append(result, tok)
+	}
+
+	if end > first && tokens[end-1].Type == NEWLINE {
+		newline := tokens[end-1]
+		delta := lineStart + len([]rune(text)) - newline.StartPos
+		newline.Column = newEOF.Column
+		for _, tok := range append([]Token{newline}, tokens[end:len(tokens)-1]...) {
+			tok.StartPos += delta
+			tok.EndPos += delta
+			result = append(result, tok)
+		}
+	}
+
+	if line == eof.Line {
+		eof.Column = newEOF.Column
+	}
+	return append(result, eof), nil
+}
@@ -0,0 +1,66 @@
+package lexer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRetokenizeLine(t *testing.T) {
+	source := "rent = $1,200\nfood = 400 per month\n\ntotal = average of rent, food\n"
+	tests := []struct {
+		name string
+		line int
+		text string
+	}{
+		{"longer line", 1, "rent = $1,250.50"},
+		{"shorter line", 2, "food = 4"},
+		{"blank line gains tokens", 3, "x = 2 * 3"},
+		{"line becomes blank", 2, ""},
+		{"multi-token function", 4, "total = square root of 16"},
+		{"trailing empty line", 5, "y = 1"},
+		{"unicode", 1, "rent = €1.200 + 5 😀"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := NewLexer(source).Tokenize()
+			if err != nil {
+				t.Fatalf("Tokenize failed: %v", err)
+			}
+			got, err := RetokenizeLine(tokens, tt.line, tt.text, LocaleUS)
+			if err != nil {
+				t.Fatalf("RetokenizeLine failed: %v", err)
+			}
+
+			lines := strings.Split(source, "\n")
+			lines[tt.line-1] = tt.text
+			want, err := NewLexer(strings.Join(lines, "\n")).Tokenize()
+			if err != nil {
+				t.Fatalf("Tokenize of edited source failed: %v", err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("RetokenizeLine:\n got %v\nwant %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRetokenizeLineErrors(t *testing.T) {
+	tokens, err := NewLexer("a = 1\nb = 2\n").Tokenize()
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+
+	if _, err := RetokenizeLine(tokens, 2, "b = 2 # note", LocaleUS); err == nil {
+		t.Error("expected lexer error")
+	} else if le, ok := err.(*LexerError); !ok || le.Line != 2 {
+		t.Errorf("error = %v, want a LexerError on line 2", err)
+	}
+	if _, err := RetokenizeLine(tokens, 1, "a = 1\nc = 3", LocaleUS); err == nil {
+		t.Error("expected error for text containing a newline")
+	}
+	if _, err := RetokenizeLine(tokens, 9, "c = 3", LocaleUS); err == nil {
+		t.Error("expected error for a line out of range")
+	}
+}
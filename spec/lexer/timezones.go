@@ -0,0 +1,97 @@
+package lexer
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	// Embed the IANA time zone database so zone names resolve the same way
+	// everywhere, including WASM and systems without zoneinfo files
+	_ "time/tzdata"
+)
+
+// TimeZoneAbbreviations maps common time zone abbreviations to zone
+// names. Those of a standard or daylight time, such as "EST" and "PDT",
+// are fixed offsets from UTC, and map to themselves for
+// types.LoadTimeZone: "3pm EST" is 3pm at UTC-5 even in July. The others
+// name a region, as "ET" does New York, and follow its daylight saving
+// time like a city. Matched case-sensitively, so they don't shadow units
+// such as "pt" (pints).
+// Performance: O(1) lookup via map
+var TimeZoneAbbreviations = map[string]string{
+	"UTC": "UTC", "GMT": "UTC",
+	"ET": "America/New_York", "EST": "EST", "EDT": "EDT",
+	"CT": "America/Chicago", "CST": "CST", "CDT": "CDT",
+	"MT": "America/Denver", "MST": "MST", "MDT": "MDT",
+	"PT": "America/Los_Angeles", "PST": "PST", "PDT": "PDT",
+	"AKST": "AKST", "AKDT": "AKDT", "HST": "HST",
+	"BST": "BST", "WET": "Europe/Lisbon",
+	"CET": "Europe/Paris", "CEST": "CEST",
+	"EET": "Europe/Athens", "EEST": "EEST",
+	"MSK": "Europe/Moscow", "IST": "IST",
+	"SGT": "Asia/Singapore", "HKT": "Asia/Hong_Kong",
+	"JST": "JST", "KST": "KST",
+	"AEST": "AEST", "AEDT": "AEDT",
+	"NZST": "NZST", "NZDT": "NZDT",
+}
+
+// TimeZoneCities maps city names, lowercase, to IANA zone names, for
+// "9:00 Tokyo". Multi-word cities are written with underscores, as in
+// IANA names: "New_York".
+// Performance: O(1) lookup via map
+var TimeZoneCities = map[string]string{
+	"new_york": "America/New_York", "chicago": "America/Chicago",
+	"denver": "America/Denver", "los_angeles": "America/Los_Angeles",
+	"san_francisco": "America/Los_Angeles", "seattle": "America/Los_Angeles",
+	"toronto": "America/Toronto", "vancouver": "America/Vancouver",
+	"mexico_city": "America/Mexico_City", "sao_paulo": "America/Sao_Paulo",
+	"london": "Europe/London", "dublin": "Europe/Dublin", "lisbon": "Europe/Lisbon",
+	"paris": "Europe/Paris", "berlin": "Europe/Berlin", "madrid": "Europe/Madrid",
+	"rome": "Europe/Rome", "amsterdam": "Europe/Amsterdam", "zurich": "Europe/Zurich",
+	"stockholm": "Europe/Stockholm", "athens": "Europe/Athens", "moscow": "Europe/Moscow",
+	"istanbul": "Europe/Istanbul", "dubai": "Asia/Dubai", "mumbai": "Asia/Kolkata",
+	"kolkata": "Asia/Kolkata", "delhi": "Asia/Kolkata", "bangkok": "Asia/Bangkok",
+	"singapore": "Asia/Singapore", "hong_kong": "Asia/Hong_Kong", "shanghai": "Asia/Shanghai",
+	"beijing": "Asia/Shanghai", "seoul": "Asia/Seoul", "tokyo": "Asia/Tokyo",
+	"sydney": "Australia/Sydney", "melbourne": "Australia/Melbourne",
+	"auckland": "Pacific/Auckland", "honolulu": "Pacific/Honolulu",
+	"johannesburg": "Africa/Johannesburg", "cairo": "Africa/Cairo", "lagos": "Africa/Lagos",
+}
+
+// LookupTimeZone returns the name types.LoadTimeZone takes for a time zone
+// written as an abbreviation ("PST", itself), a city ("Tokyo", its IANA
+// name), or an IANA name ("America/New_York", matched case-insensitively).
+func LookupTimeZone(name string) (string, bool) {
+	if zone, ok := TimeZoneAbbreviations[name]; ok {
+		return zone, true
+	}
+	if zone, ok := TimeZoneCities[strings.ToLower(name)]; ok {
+		return zone, true
+	}
+	if !strings.Contains(name, "/") {
+		return "", false
+	}
+	loc, err := time.LoadLocation(canonicalZoneName(name))
+	if err != nil {
+		return "", false
+	}
+	return loc.String(), true
+}
+
+// canonicalZoneName capitalizes each word of an IANA name the way the
+// database spells most zones: "america/new_york" → "America/New_York".
+func canonicalZoneName(name string) string {
+	if _, err := time.LoadLocation(name); err == nil {
+		return name
+	}
+	var b strings.Builder
+	prev := '/'
+	for _, r := range strings.ToLower(name) {
+		if prev == '/' || prev == '_' || prev == '-' {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
@@ -13,6 +13,7 @@ const (
 	QUANTITY           // Unified type for numbers with units (currency, measurements, etc.)
 	BOOLEAN
 	IDENTIFIER
+	STRING // "prices.csv" - quoted text, only as a function argument
 
 	// Currency (split for parser)
 	CURRENCY_SYM  // $
@@ -53,6 +54,8 @@ const (
 	// Grouping
 	LPAREN
 	RPAREN
+	LBRACKET // "[" - list literals like [10, 20, 30]
+	RBRACKET // "]"
 
 	// Punctuation
 	COMMA // ","
@@ -92,6 +95,13 @@ const (
 	// Multi-token function keywords (aliases)
 	FUNC_AVERAGE_OF     // "average of" → maps to "avg"
 	FUNC_SQUARE_ROOT_OF // "square root of" → maps to "sqrt"
+	FUNC_STDEV_OF       // "standard deviation of" → maps to "stdev"
+	FUNC_WORKDAYS       // "workdays between", "business days between" → maps to "workdays"
+
+	// Schedule keywords, recognized only before a schedule or its name
+	SCHEDULE_EVERY // "every" - schedules: "every 2nd Friday"
+	SCHEDULE_NEXT  // "next" - next occurrence: "next payday"
+	SCHEDULE_COUNT // "count" - occurrences: "count paydays between Mar 1 and Jun 30"
 
 	// Date keywords
 	DATE_TODAY     // "today"
@@ -112,6 +122,8 @@ const (
 	// Date/Duration literals (combined by lexer)
 	DATE_LITERAL     // "Dec 12", "December 25 2025"
 	DURATION_LITERAL // "2 days", "3 weeks and 4 days"
+	TIME_LITERAL     // "10:30AM", "3pm EST", "9:00 Tokyo", "14:30 UTC+5:30"
+	TIMEZONE         // Time zone after "in": "PST", "Tokyo", "America/New_York"
 
 	// Special
 	NEWLINE
@@ -124,6 +136,8 @@ func (tt TokenType) String() string {
 	switch tt {
 	case NUMBER:
 		return "NUMBER"
+	case STRING:
+		return "STRING"
 	case NUMBER_PERCENT:
 		return "NUMBER_PERCENT"
 	case NUMBER_K:
@@ -186,6 +200,10 @@ func (tt TokenType) String() string {
 		return "LPAREN"
 	case RPAREN:
 		return "RPAREN"
+	case LBRACKET:
+		return "LBRACKET"
+	case RBRACKET:
+		return "RBRACKET"
 	case COMMA:
 		return "COMMA"
 	case DOT:
@@ -242,6 +260,16 @@ func (tt TokenType) String() string {
 		return "FUNC_AVERAGE_OF"
 	case FUNC_SQUARE_ROOT_OF:
 		return "FUNC_SQUARE_ROOT_OF"
+	case FUNC_STDEV_OF:
+		return "FUNC_STDEV_OF"
+	case FUNC_WORKDAYS:
+		return "FUNC_WORKDAYS"
+	case SCHEDULE_EVERY:
+		return "SCHEDULE_EVERY"
+	case SCHEDULE_NEXT:
+		return "SCHEDULE_NEXT"
+	case SCHEDULE_COUNT:
+		return "SCHEDULE_COUNT"
 	case DATE_TODAY:
 		return "DATE_TODAY"
 	case DATE_TOMORROW:
@@ -270,6 +298,10 @@ func (tt TokenType) String() string {
 		return "DATE_LITERAL"
 	case DURATION_LITERAL:
 		return "DURATION_LITERAL"
+	case TIME_LITERAL:
+		return "TIME_LITERAL"
+	case TIMEZONE:
+		return "TIMEZONE"
 	case NEWLINE:
 		return "NEWLINE"
 	case EOF:
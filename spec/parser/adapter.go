@@ -2,6 +2,7 @@ package parser
 
 import (
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
 )
 
 // Parse parses CalcMark source code into an AST
@@ -10,3 +11,10 @@ func Parse(text string) ([]ast.Node, error) {
 	p := NewRecursiveDescentParser(text)
 	return p.Parse()
 }
+
+// ParseWithLocale parses CalcMark source whose number literals are written
+// in loc's format, e.g. "price = 1.000,50" with lexer.LocaleDE.
+func ParseWithLocale(text string, loc lexer.NumberLocale) ([]ast.Node, error) {
+	p := newRecursiveDescentParser(lexer.NewLexerWithLocale(text, loc), text)
+	return p.Parse()
+}
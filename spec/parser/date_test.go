@@ -3,6 +3,7 @@ package parser_test
 import (
 	"testing"
 
+	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/parser"
 )
 
@@ -161,3 +162,109 @@ func TestInvalidDates(t *testing.T) {
 		})
 	}
 }
+
+// TestWorkdaysBetweenParsing tests that "workdays between A and B" parses
+// to workdays(A, B), with "and" separating the dates.
+func TestWorkdaysBetweenParsing(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"workdays between", "n = workdays between Jan 5 and Feb 20\n"},
+		{"business days between", "n = business days between today and today + 2 weeks\n"},
+		{"function form", "n = workdays(Jan 5, Feb 20, UK)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			assign, ok := nodes[0].(*ast.Assignment)
+			if !ok {
+				t.Fatalf("expected *ast.Assignment, got %T", nodes[0])
+			}
+			call, ok := assign.Value.(*ast.FunctionCall)
+			if !ok || call.Name != "workdays" || len(call.Arguments) < 2 {
+				t.Fatalf("expected workdays(start, end), got %s", assign.Value)
+			}
+		})
+	}
+
+	if _, err := parser.Parse("n = workdays between Jan 5\n"); err == nil {
+		t.Error("expected an error without 'and'")
+	}
+}
+
+// TestScheduleParsing tests recurring schedule literals and the queries
+// over them.
+func TestScheduleParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"s = every Friday\n", "ScheduleLiteral(every 1 Friday)"},
+		{"s = every 2nd Friday\n", "ScheduleLiteral(every 2 Friday)"},
+		{"s = every other Friday\n", "ScheduleLiteral(every 2 Friday)"},
+		{"s = every 15th\n", "ScheduleLiteral(every 1 month on 15)"},
+		{"s = every 3rd month\n", "ScheduleLiteral(every 3 month)"},
+		{"s = every month on the 1st\n", "ScheduleLiteral(every 1 month on 1)"},
+		{"s = every 2 weeks from Jan 9 2026\n", "ScheduleLiteral(every 2 week from DateLiteral(January 9 2026))"},
+		{"s = every day starting today\n", "ScheduleLiteral(every 1 day from RelativeDateLiteral(today))"},
+		{"d = next payday\n", `FunctionCall("next", [Identifier("payday")])`},
+		{"d = next payday after Jun 1\n", `FunctionCall("next", [Identifier("payday") DateLiteral(June 1)])`},
+		{"n = count paydays between Mar 1 and Jun 30\n", `FunctionCall("count", [Identifier("paydays") DateLiteral(March 1) DateLiteral(June 30)])`},
+		{"q = paydays in Q3 2026\n", `FunctionCall("occurrences", [Identifier("paydays") DateLiteral(July 1 2026) DateLiteral(September 30 2026)])`},
+		{"q = paydays in q1\n", `FunctionCall("occurrences", [Identifier("paydays") DateLiteral(January 1) DateLiteral(March 31)])`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			assign, ok := nodes[0].(*ast.Assignment)
+			if !ok {
+				t.Fatalf("expected *ast.Assignment, got %T", nodes[0])
+			}
+			if got := assign.Value.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"s = every 2 Friday\n", "s = every other\n", "n = count paydays between Mar 1\n"} {
+		if _, err := parser.Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestTimeZoneParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"t = 10:30AM\n", "TimeLiteral(10:30 AM)"},
+		{"t = 9:00 Tokyo\n", "TimeLiteral(9:00 Asia/Tokyo)"},
+		{"t = 3pm EST in PST\n", "TimeZoneConversion(TimeLiteral(3:00 PM EST) in PST)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			assign, ok := nodes[0].(*ast.Assignment)
+			if !ok {
+				t.Fatalf("expected *ast.Assignment, got %T", nodes[0])
+			}
+			if got := assign.Value.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
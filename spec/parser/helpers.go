@@ -14,7 +14,7 @@ func isAllUppercase(s string) bool {
 // currencySymbols is a pre-computed set of currency symbols.
 // Package-level to avoid allocation on every isCurrency call.
 var currencySymbols = map[string]bool{
-	"$": true, "€": true, "£": true, "¥": true,
+	"$": true, "€": true, "£": true, "¥": true, "₿": true,
 }
 
 // isCurrency checks if a unit string is a currency code or symbol
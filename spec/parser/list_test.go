@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+)
+
+// TestParseListLiteral tests bracketed list literals
+func TestParseListLiteral(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantElements int
+	}{
+		{"numbers", "prices = [10, 20, 30]\n", 3},
+		{"single element", "x = [42]\n", 1},
+		{"currencies", "x = [$10, $20]\n", 2},
+		{"expressions", "x = [1 + 2, y * 3]\n", 2},
+		{"nested", "x = [1, [2, 3]]\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v, want nil", tt.input, err)
+			}
+
+			assign, ok := nodes[0].(*ast.Assignment)
+			if !ok {
+				t.Fatalf("Parse(%q) returned %T, want *ast.Assignment", tt.input, nodes[0])
+			}
+
+			list, ok := assign.Value.(*ast.ListLiteral)
+			if !ok {
+				t.Fatalf("Parse(%q) value is %T, want *ast.ListLiteral", tt.input, assign.Value)
+			}
+
+			if len(list.Elements) != tt.wantElements {
+				t.Errorf("Parse(%q) got %d elements, want %d", tt.input, len(list.Elements), tt.wantElements)
+			}
+		})
+	}
+}
+
+// TestParseListLiteralInFunctionCall tests lists passed as function arguments
+func TestParseListLiteralInFunctionCall(t *testing.T) {
+	nodes, err := Parse("sum([1, 2], 3)\n")
+	if err != nil {
+		t.Fatalf("Parse error = %v, want nil", err)
+	}
+
+	funcCall, ok := nodes[0].(*ast.FunctionCall)
+	if !ok {
+		t.Fatalf("got %T, want *ast.FunctionCall", nodes[0])
+	}
+	if len(funcCall.Arguments) != 2 {
+		t.Fatalf("got %d arguments, want 2", len(funcCall.Arguments))
+	}
+	if _, ok := funcCall.Arguments[0].(*ast.ListLiteral); !ok {
+		t.Errorf("first argument is %T, want *ast.ListLiteral", funcCall.Arguments[0])
+	}
+}
+
+// TestParseListLiteralErrors tests malformed list literals
+func TestParseListLiteralErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty list", "x = []\n"},
+		{"unclosed", "x = [1, 2\n"},
+		{"double comma", "x = [1,, 2]\n"},
+		{"trailing comma", "x = [1, 2,]\n"},
+		{"stray close", "x = 1]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.input); err == nil {
+				t.Errorf("Parse(%q) expected error, got nil", tt.input)
+			}
+		})
+	}
+}
+
+// TestParseIndex tests indexing lists and table rows
+func TestParseIndex(t *testing.T) {
+	nodes, err := Parse("x = loan[n + 1].balance * 2\n")
+	if err != nil {
+		t.Fatalf("Parse error = %v, want nil", err)
+	}
+	product, ok := nodes[0].(*ast.Assignment).Value.(*ast.BinaryOp)
+	if !ok {
+		t.Fatalf("value is %T, want *ast.BinaryOp", nodes[0].(*ast.Assignment).Value)
+	}
+	index, ok := product.Left.(*ast.IndexExpr)
+	if !ok {
+		t.Fatalf("left operand is %T, want *ast.IndexExpr", product.Left)
+	}
+	if _, ok := index.Index.(*ast.BinaryOp); !ok || index.Field != "balance" {
+		t.Errorf("index = %v, want loan[n + 1].balance", index)
+	}
+}
@@ -101,3 +101,86 @@ func TestNaturalSyntaxRateFunctions(t *testing.T) {
 		})
 	}
 }
+
+// TestNaturalSyntaxAdjust checks "adjust(x from 2015 to 2025)" reads as
+// adjust(x, 2015, 2025), with "to" not taken as a unit of 2015.
+func TestNaturalSyntaxAdjust(t *testing.T) {
+	tests := []struct {
+		input string
+		from  string // The from year's literal, "" if it isn't one
+	}{
+		{"adjust($50,000 from 2015 to 2025)\n", "2015"},
+		{"adjust(salary from start to start + 10)\n", ""},
+		{"adjust(salary, 2015, 2025)\n", "2015"},
+	}
+	for _, tt := range tests {
+		nodes, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.input, err)
+		}
+		fc, ok := nodes[0].(*ast.FunctionCall)
+		if !ok || fc.Name != "adjust" || len(fc.Arguments) != 3 {
+			t.Fatalf("%q: got %v, want adjust() of 3 arguments", tt.input, nodes[0])
+		}
+		if year, ok := fc.Arguments[1].(*ast.NumberLiteral); tt.from != "" && (!ok || year.Value != tt.from) {
+			t.Errorf("%q: from year %v, want %s", tt.input, fc.Arguments[1], tt.from)
+		}
+	}
+
+	for _, input := range []string{
+		"adjust($100 from 2015)\n",
+		"adjust($100 from 2015 until 2025)\n",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("%q: expected a parse error", input)
+		}
+	}
+}
+
+// TestNaturalSyntaxPercentages checks the percentage phrases read as calls
+// to the functions behind them.
+func TestNaturalSyntaxPercentages(t *testing.T) {
+	tests := []struct {
+		input string
+		name  string
+	}{
+		{"15% off $200\n", "decrease"},
+		{"increase 100 by 10%\n", "increase"},
+		{"decrease price by 5%\n", "decrease"},
+		{"change from 80 to 100 in %\n", "percent_change"},
+		{"change from $80 to old + $20 in %\n", "percent_change"},
+	}
+	for _, tt := range tests {
+		nodes, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.input, err)
+		}
+		fc, ok := nodes[0].(*ast.FunctionCall)
+		if !ok || fc.Name != tt.name || len(fc.Arguments) != 2 {
+			t.Errorf("%q: got %v, want %s() of 2 arguments", tt.input, nodes[0], tt.name)
+		}
+	}
+
+	// The words stay variable names where no phrase follows
+	for _, input := range []string{"change * 2\n", "increase + 1\n", "off\n"} {
+		nodes, err := Parse(input)
+		if err != nil {
+			t.Fatalf("%q: %v", input, err)
+		}
+		if _, ok := nodes[0].(*ast.FunctionCall); ok {
+			t.Errorf("%q: got %v, want a variable", input, nodes[0])
+		}
+	}
+
+	for _, input := range []string{
+		"increase 100 to 10%\n",
+		"increase $100 by 10\n",
+		"decrease 80 kg by 0.25\n",
+		"change from 80 to 100\n",
+		"change from 80 until 100 in %\n",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("%q: expected a parse error", input)
+		}
+	}
+}
@@ -7,8 +7,11 @@ import (
 
 // parseNaturalLanguageFunction parses natural language function syntax.
 // NaturalLanguageFunction → "average of" ArgumentList | "square root of" Expression
+//
+//	| "standard deviation of" ArgumentList
+//	| "workdays between" Additive "and" Additive
 func (p *RecursiveDescentParser) parseNaturalLanguageFunction() (ast.Node, error) {
-	funcToken := p.previous() // FUNC_AVERAGE_OF or FUNC_SQUARE_ROOT_OF
+	funcToken := p.previous() // FUNC_AVERAGE_OF, FUNC_SQUARE_ROOT_OF, FUNC_STDEV_OF, or FUNC_WORKDAYS
 
 	// Map to canonical function name
 	var funcName string
@@ -18,6 +21,10 @@ func (p *RecursiveDescentParser) parseNaturalLanguageFunction() (ast.Node, error
 		funcName = "avg"
 	case lexer.FUNC_SQUARE_ROOT_OF:
 		funcName = "sqrt"
+	case lexer.FUNC_STDEV_OF:
+		funcName = "stdev"
+	case lexer.FUNC_WORKDAYS:
+		return p.parseWorkdaysBetween()
 	default:
 		return nil, p.error("unexpected natural language function")
 	}
@@ -34,7 +41,7 @@ func (p *RecursiveDescentParser) parseNaturalLanguageFunction() (ast.Node, error
 		}, nil
 	}
 
-	// For "average of", parse comma-separated list (no parentheses!)
+	// For "average of" and "standard deviation of", parse comma-separated list (no parentheses!)
 	var args []ast.Node
 
 	// Parse first argument
@@ -58,3 +65,24 @@ func (p *RecursiveDescentParser) parseNaturalLanguageFunction() (ast.Node, error
 		Arguments: args,
 	}, nil
 }
+
+// parseWorkdaysBetween parses the dates of "workdays between Jan 5 and
+// Feb 20" into workdays(Jan 5, Feb 20). The dates are parsed above the
+// logical operators, so "and" separates them rather than combining them.
+func (p *RecursiveDescentParser) parseWorkdaysBetween() (ast.Node, error) {
+	start, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(lexer.AND, "expected 'and' between the dates of 'workdays between'"); err != nil {
+		return nil, err
+	}
+	end, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.FunctionCall{
+		Name:      "workdays",
+		Arguments: []ast.Node{start, end},
+	}, nil
+}
@@ -160,6 +160,39 @@ func TestParseSquareRootOfFunction(t *testing.T) {
 	}
 }
 
+// TestParseFunctionAliases tests that the other names of functions in the
+// feature registry parse to their canonical functions
+func TestParseFunctionAliases(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantName string
+		wantArgs int
+	}{
+		{"minimum(8, 3, 5)", "min", 3},
+		{"maximum(8, 3)", "max", 2},
+		{"standard deviation of 2, 4, 4, 5", "stdev", 4},
+		{"Standard Deviation of 1, 3", "stdev", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			nodes, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v, want nil", tt.input, err)
+			}
+
+			funcCall, ok := nodes[0].(*ast.FunctionCall)
+			if !ok {
+				t.Fatalf("Parse(%q) returned %T, want *ast.FunctionCall", tt.input, nodes[0])
+			}
+			if funcCall.Name != tt.wantName || len(funcCall.Arguments) != tt.wantArgs {
+				t.Errorf("Parse(%q) = %s with %d arguments, want %s with %d",
+					tt.input, funcCall.Name, len(funcCall.Arguments), tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
 func TestParseFunctionInAssignment(t *testing.T) {
 	tests := []struct {
 		name     string
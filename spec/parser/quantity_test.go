@@ -3,6 +3,7 @@ package parser_test
 import (
 	"testing"
 
+	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/parser"
 )
 
@@ -246,3 +247,17 @@ func BenchmarkMultiplierWithUnit(b *testing.B) {
 		}
 	}
 }
+
+// TestBareCurrencyCode tests that a currency code alone is one unit of
+// it, for baskets like "0.6 * USD + 0.4 * EUR"
+func TestBareCurrencyCode(t *testing.T) {
+	nodes, err := parser.Parse("basket = 0.6 * USD + 0.4 * EUR\n")
+	if err != nil {
+		t.Fatalf("Parse error = %v", err)
+	}
+	sum := nodes[0].(*ast.Assignment).Value.(*ast.BinaryOp)
+	unit, ok := sum.Right.(*ast.BinaryOp).Right.(*ast.CurrencyUnit)
+	if !ok || unit.Code != "EUR" {
+		t.Errorf("right operand = %v, want CurrencyUnit(EUR)", sum.Right)
+	}
+}
@@ -12,6 +12,7 @@ import (
 // as that "/" should be parsed as part of the capacity syntax (e.g., "2 TB/disk").
 func (p *RecursiveDescentParser) parseCapacityValue() (ast.Node, error) {
 	// Parse the base expression
+	start := p.peek()
 	left, err := p.parseExponent()
 	if err != nil {
 		return nil, err
@@ -58,6 +59,7 @@ func (p *RecursiveDescentParser) parseCapacityValue() (ast.Node, error) {
 				Operator: "/",
 				Left:     left,
 				Right:    right,
+				Range:    spanRange(start, p.previous()),
 			}
 			continue
 		}
@@ -72,6 +74,7 @@ func (p *RecursiveDescentParser) parseCapacityValue() (ast.Node, error) {
 			Operator: "*",
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}
 	}
 
@@ -25,7 +25,11 @@ type RecursiveDescentParser struct {
 
 // NewRecursiveDescentParser creates a new parser for the given source text.
 func NewRecursiveDescentParser(source string) *RecursiveDescentParser {
-	lex := lexer.NewLexer(source)
+	return newRecursiveDescentParser(lexer.NewLexer(source), source)
+}
+
+// newRecursiveDescentParser creates a parser over the tokens of lex.
+func newRecursiveDescentParser(lex *lexer.Lexer, source string) *RecursiveDescentParser {
 	tokens, err := lex.Tokenize()
 	if err != nil {
 		// If tokenization fails, create parser with just EOF token
@@ -143,6 +147,15 @@ func (p *RecursiveDescentParser) error(message string) error {
 	return p.errorAt(p.peek(), message)
 }
 
+// spanRange returns the source range from the start of first to the end
+// of last, tokens on the same line.
+func spanRange(first, last lexer.Token) *ast.Range {
+	return &ast.Range{
+		Start: ast.Position{Line: first.Line, Column: first.Column},
+		End:   ast.Position{Line: last.Line, Column: last.Column + last.EndPos - last.StartPos},
+	}
+}
+
 // errorAt creates a parse error at the given token's position.
 func (p *RecursiveDescentParser) errorAt(tok lexer.Token, message string) error {
 	return &ParseError{
@@ -242,6 +255,7 @@ func (p *RecursiveDescentParser) parseAssignment() (ast.Node, error) {
 	return &ast.Assignment{
 		Name:  string(name.Value),
 		Value: value,
+		Range: spanRange(name, p.previous()),
 	}, nil
 }
 
@@ -305,6 +319,7 @@ func (p *RecursiveDescentParser) parseExpression() (ast.Node, error) {
 // parseOr parses OR expressions.
 // Or → And ( 'or' And )*
 func (p *RecursiveDescentParser) parseOr() (ast.Node, error) {
+	start := p.peek()
 	left, err := p.parseAnd()
 	if err != nil {
 		return nil, err
@@ -320,6 +335,7 @@ func (p *RecursiveDescentParser) parseOr() (ast.Node, error) {
 			Operator: "or",
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}
 	}
 
@@ -329,6 +345,7 @@ func (p *RecursiveDescentParser) parseOr() (ast.Node, error) {
 // parseAnd parses AND expressions.
 // And → Comparison ( 'and' Comparison )*
 func (p *RecursiveDescentParser) parseAnd() (ast.Node, error) {
+	start := p.peek()
 	left, err := p.parseComparison()
 	if err != nil {
 		return nil, err
@@ -344,6 +361,7 @@ func (p *RecursiveDescentParser) parseAnd() (ast.Node, error) {
 			Operator: "and",
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}
 	}
 
@@ -353,6 +371,7 @@ func (p *RecursiveDescentParser) parseAnd() (ast.Node, error) {
 // parseComparison parses comparison operators.
 // Comparison → Additive ( ('=='|'!='|'>'|'<'|'>='|'<=') Additive )*
 func (p *RecursiveDescentParser) parseComparison() (ast.Node, error) {
+	start := p.peek()
 	left, err := p.parseAdditive()
 	if err != nil {
 		return nil, err
@@ -369,6 +388,7 @@ func (p *RecursiveDescentParser) parseComparison() (ast.Node, error) {
 			Operator: string(op.Value),
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}
 	}
 
@@ -378,6 +398,7 @@ func (p *RecursiveDescentParser) parseComparison() (ast.Node, error) {
 // parseAdditive parses addition and subtraction.
 // Additive → Multiplicative ( ('+'|'-') Multiplicative )*
 func (p *RecursiveDescentParser) parseAdditive() (ast.Node, error) {
+	start := p.peek()
 	left, err := p.parseMultiplicative()
 	if err != nil {
 		return nil, err
@@ -394,6 +415,7 @@ func (p *RecursiveDescentParser) parseAdditive() (ast.Node, error) {
 			Operator: string(op.Value),
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}
 	}
 
@@ -416,6 +438,7 @@ func (p *RecursiveDescentParser) parseAdditive() (ast.Node, error) {
 // parseMultiplicative parses multiplication, division, modulus, and unit conversions.
 // Multiplicative → Exponent ( ('*'|'/'|'%') Exponent )* ('in' UNIT)?
 func (p *RecursiveDescentParser) parseMultiplicative() (ast.Node, error) {
+	start := p.peek()
 	left, err := p.parseExponent()
 	if err != nil {
 		return nil, err
@@ -453,6 +476,7 @@ func (p *RecursiveDescentParser) parseMultiplicative() (ast.Node, error) {
 			Operator: string(op.Value),
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}
 	}
 
@@ -629,7 +653,24 @@ func (p *RecursiveDescentParser) parseMultiplicative() (ast.Node, error) {
 	// Check for unit conversion: "10 meters in feet" or "10 feet in nautical miles"
 	// Also handles rate unit conversion: "10 m/s in inch/s"
 	// Also handles currency conversion: "100 USD in EUR"
-	if p.match(lexer.IN) {
+	// "in %" ends "change from 80 to 100 in %" rather than converting
+	if !p.checkInPercent() && p.match(lexer.IN) {
+		// Time zone conversion: "3pm EST in PST"
+		if p.match(lexer.TIMEZONE) {
+			zone := p.previous()
+			return &ast.TimeZoneConversion{
+				Time:      left,
+				Zone:      zone.Value,
+				ZoneRange: spanRange(zone, zone),
+				Range:     &ast.Range{},
+			}, nil
+		}
+
+		// Schedule query: "paydays in Q3"
+		if occurrences, ok := p.parseQuarter(left); ok {
+			return occurrences, nil
+		}
+
 		if !p.match(lexer.IDENTIFIER) && !p.match(lexer.CURRENCY_CODE) {
 			return nil, p.error("expected unit name or currency code after 'in'")
 		}
@@ -644,6 +685,7 @@ func (p *RecursiveDescentParser) parseMultiplicative() (ast.Node, error) {
 				targetUnitName = multiWordUnit
 			}
 		}
+		targetRange := spanRange(targetUnit, p.previous())
 
 		// Check for rate target unit: "in inch/s" or "in inch per second"
 		var targetTimeUnit string
@@ -673,6 +715,7 @@ func (p *RecursiveDescentParser) parseMultiplicative() (ast.Node, error) {
 			Quantity:       left,
 			TargetUnit:     targetUnitName,
 			TargetTimeUnit: targetTimeUnit,
+			TargetRange:    targetRange,
 			Range:          &ast.Range{},
 		}, nil
 	}
@@ -683,6 +726,7 @@ func (p *RecursiveDescentParser) parseMultiplicative() (ast.Node, error) {
 // parseExponent parses exponentiation (right-associative).
 // Exponent → Unary ('^' Exponent)?
 func (p *RecursiveDescentParser) parseExponent() (ast.Node, error) {
+	start := p.peek()
 	left, err := p.parseUnary()
 	if err != nil {
 		return nil, err
@@ -699,6 +743,7 @@ func (p *RecursiveDescentParser) parseExponent() (ast.Node, error) {
 			Operator: string(op.Value),
 			Left:     left,
 			Right:    right,
+			Range:    spanRange(start, p.previous()),
 		}, nil
 	}
 
@@ -750,6 +795,13 @@ func (p *RecursiveDescentParser) parseUnary() (ast.Node, error) {
 		return nil, err
 	}
 
+	// Indexing: "prices[2]", "schedule[12].balance"
+	for p.check(lexer.LBRACKET) {
+		if result, err = p.parseIndex(result); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check for "as napkin" postfix (higher precedence than unary operators)
 	// Need to check for "as" identifier followed by "napkin" keyword
 	// This ensures "-47 as napkin" parses as "napkin(-47)" not "-(napkin(47))"
@@ -768,6 +820,38 @@ func (p *RecursiveDescentParser) parseUnary() (ast.Node, error) {
 	return result, nil
 }
 
+// parseIndex parses an index after the target it indexes.
+// Index → '[' Expression ']' ('.' IDENTIFIER)?
+func (p *RecursiveDescentParser) parseIndex(target ast.Node) (ast.Node, error) {
+	bracket := p.advance() // '['
+	if err := p.enterDepth(); err != nil {
+		return nil, err
+	}
+	defer p.exitDepth()
+
+	index, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(lexer.RBRACKET, "expected ']' after index"); err != nil {
+		return nil, err
+	}
+
+	var field string
+	if p.match(lexer.DOT) {
+		if !p.match(lexer.IDENTIFIER) {
+			return nil, p.error("expected column name after '.'")
+		}
+		field = string(p.previous().Value)
+	}
+	return &ast.IndexExpr{
+		Target: target,
+		Index:  index,
+		Field:  field,
+		Range:  spanRange(bracket, p.previous()),
+	}, nil
+}
+
 // parsePrimary parses primary expressions (atomic values and higher precedence constructs).
 // Primary → NUMBER | BOOLEAN | IDENTIFIER | FUNCTION | CURRENCY | '(' Expression ')' | ...
 func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
@@ -797,6 +881,24 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 			}, nil
 		}
 
+		// "PERCENTAGE off expression" (e.g., "15% off $200") reads as
+		// decrease($200, 15%)
+		if tok.Type == lexer.NUMBER_PERCENT && p.check(lexer.IDENTIFIER) && strings.EqualFold(p.peek().Value, "off") {
+			p.advance() // consume "off"
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return &ast.FunctionCall{
+				Name: "decrease",
+				Arguments: []ast.Node{value, &ast.NumberLiteral{
+					Value:      string(tok.Value),
+					SourceText: string(tok.OriginalText),
+				}},
+				Range: spanRange(tok, p.previous()),
+			}, nil
+		}
+
 		// Check if followed by a unit identifier: "10 meters", "50% coverage", etc.
 		// IMPORTANT: Don't consume KEYWORDS like "downtime", "over" that have special meaning.
 		// But DO allow arbitrary units for rates ("cars per day", "requests per second").
@@ -873,6 +975,7 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 			Symbol:     string(currencyTok.Value),
 			Value:      string(numberTok.Value),
 			SourceText: string(currencyTok.OriginalText) + string(numberTok.OriginalText),
+			Range:      spanRange(currencyTok, numberTok),
 		}, nil
 	}
 
@@ -886,12 +989,12 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 		}
 
 		// Check if it's a currency (unit is a currency code or symbol)
-		unit := parts[1]
+		unit := lexer.Intern(parts[1])
 		if isCurrency(unit) {
 			return &ast.CurrencyLiteral{
 				Value:  parts[0],
 				Symbol: unit,
-				Range:  &ast.Range{},
+				Range:  spanRange(tok, tok),
 			}, nil
 		}
 
@@ -899,7 +1002,7 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 		return &ast.QuantityLiteral{
 			Value: parts[0],
 			Unit:  unit,
-			Range: &ast.Range{},
+			Range: spanRange(tok, tok),
 		}, nil
 	}
 
@@ -928,7 +1031,7 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 		}
 
 		value := parts[0]
-		unit := parts[1]
+		unit := lexer.Intern(parts[1])
 
 		// Check if unit is a 3-letter uppercase code (currency)
 		// Syntactic check only - semantic validation happens later
@@ -950,16 +1053,39 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 		}, nil
 	}
 
+	// Bare currency codes: one unit of the currency, for weighted
+	// baskets like "0.6 * USD + 0.4 * EUR"
+	if p.match(lexer.CURRENCY_CODE) {
+		tok := p.previous()
+		return &ast.CurrencyUnit{
+			Code:  string(tok.Value),
+			Range: spanRange(tok, tok),
+		}, nil
+	}
+
 	// Function calls: avg(...), sqrt(...)
 	if p.match(lexer.FUNC_AVG, lexer.FUNC_SQRT) {
 		return p.parseFunctionCall()
 	}
 
-	// Natural language functions: "average of", "square root of"
-	if p.match(lexer.FUNC_AVERAGE_OF, lexer.FUNC_SQUARE_ROOT_OF) {
+	// Natural language functions: "average of", "square root of",
+	// "standard deviation of", "workdays between"
+	if p.match(lexer.FUNC_AVERAGE_OF, lexer.FUNC_SQUARE_ROOT_OF, lexer.FUNC_STDEV_OF, lexer.FUNC_WORKDAYS) {
 		return p.parseNaturalLanguageFunction()
 	}
 
+	// Schedules: "every 2nd Friday", and queries: "next payday",
+	// "count paydays between Mar 1 and Jun 30"
+	if p.match(lexer.SCHEDULE_EVERY) {
+		return p.parseSchedule()
+	}
+	if p.match(lexer.SCHEDULE_NEXT) {
+		return p.parseNextOccurrence()
+	}
+	if p.match(lexer.SCHEDULE_COUNT) {
+		return p.parseCountBetween()
+	}
+
 	// Date keywords: today, tomorrow, yesterday, this/next/last week/month/year
 	if p.match(lexer.DATE_TODAY, lexer.DATE_TOMORROW, lexer.DATE_YESTERDAY,
 		lexer.DATE_THIS_WEEK, lexer.DATE_THIS_MONTH, lexer.DATE_THIS_YEAR,
@@ -972,6 +1098,11 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 		}, nil
 	}
 
+	// Time literals: "10:30AM", "3pm EST", "14:30 UTC+5:30"
+	if p.match(lexer.TIME_LITERAL) {
+		return p.parseTimeLiteral()
+	}
+
 	// Date literals: "Dec 12", "December 25 2025"
 	if p.match(lexer.DATE_LITERAL) {
 		tok := p.previous()
@@ -1020,6 +1151,7 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 				Operator: "+",
 				Left:     baseDate,
 				Right:    durationNode,
+				Range:    spanRange(tok, p.previous()),
 			}, nil
 		}
 
@@ -1046,6 +1178,11 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 		return expr, nil
 	}
 
+	// List literal: "[10, 20, 30]"
+	if p.match(lexer.LBRACKET) {
+		return p.parseListLiteral()
+	}
+
 	// Identifiers (variables or function calls)
 	if p.match(lexer.IDENTIFIER) {
 		name := p.previous()
@@ -1056,8 +1193,13 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 			return p.parseFunctionCall()
 		}
 
+		// Percentage phrases: "increase 100 by 10%", "change from 80 to 100 in %"
+		if call, ok, err := p.parsePercentPhrase(name); ok || err != nil {
+			return call, err
+		}
+
 		// Otherwise it's just a variable reference
-		return &ast.Identifier{Name: string(name.Value)}, nil
+		return &ast.Identifier{Name: string(name.Value), Symbol: lexer.SymbolFor(name.Value), Range: spanRange(name, name)}, nil
 	}
 
 	// Number followed by identifier/unit: "100 meters", "5 kg"
@@ -1073,6 +1215,10 @@ func (p *RecursiveDescentParser) parsePrimary() (ast.Node, error) {
 // FunctionCall → FUNC_NAME '(' ArgumentList ')'
 func (p *RecursiveDescentParser) parseFunctionCall() (ast.Node, error) {
 	funcName := p.previous() // Already consumed by match()
+	funcNameStr := string(funcName.Value)
+	if canonical, ok := functionAliases[funcNameStr]; ok {
+		funcNameStr = canonical
+	}
 
 	if _, err := p.consume(lexer.LPAREN, "expected '(' after function name"); err != nil {
 		return nil, err
@@ -1085,21 +1231,31 @@ func (p *RecursiveDescentParser) parseFunctionCall() (ast.Node, error) {
 	if p.check(lexer.RPAREN) {
 		p.advance()
 		return &ast.FunctionCall{
-			Name:      string(funcName.Value),
+			Name:      funcNameStr,
 			Arguments: args,
+			Range:     spanRange(funcName, p.previous()),
 		}, nil
 	}
 
 	// Parse first argument
-	arg, err := p.parseExpression()
+	arg, err := p.parseArgument()
 	if err != nil {
 		return nil, err
 	}
 	args = append(args, arg)
 
+	// adjust(amount from 2015 to 2025) reads as adjust(amount, 2015, 2025)
+	if funcNameStr == "adjust" && p.match(lexer.FROM) {
+		years, err := p.parseFromToYears()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, years...)
+	}
+
 	// Parse remaining arguments
 	for p.match(lexer.COMMA) {
-		arg, err := p.parseExpression()
+		arg, err := p.parseArgument()
 		if err != nil {
 			return nil, err
 		}
@@ -1111,7 +1267,6 @@ func (p *RecursiveDescentParser) parseFunctionCall() (ast.Node, error) {
 	}
 
 	// Validate argument counts based on function
-	funcNameStr := string(funcName.Value)
 	if funcNameStr == "avg" && len(args) == 0 {
 		return nil, p.error("avg() requires at least 1 argument")
 	}
@@ -1127,9 +1282,160 @@ func (p *RecursiveDescentParser) parseFunctionCall() (ast.Node, error) {
 	return &ast.FunctionCall{
 		Name:      funcNameStr,
 		Arguments: args,
+		Range:     spanRange(funcName, p.previous()),
 	}, nil
 }
 
+// functionAliases maps other names of built-in functions to their
+// canonical names: minimum(1, 2) calls min.
+var functionAliases = map[string]string{
+	"maximum": "max",
+	"minimum": "min",
+}
+
+// parsePercentPhrase parses the percentage phrases that start with a word,
+// after the word, reporting false if name doesn't start one. The words stay
+// usable as variable names: they start a phrase only where an operand or
+// "from" follows, which would otherwise be a syntax error. A number after
+// "by" must be written as a percentage.
+//
+//	Increase → ('increase'|'decrease') Expression 'by' Expression
+//	Change   → 'change' 'from' Expression 'to' Expression 'in' '%'
+func (p *RecursiveDescentParser) parsePercentPhrase(name lexer.Token) (ast.Node, bool, error) {
+	word := strings.ToLower(name.Value)
+	switch {
+	case (word == "increase" || word == "decrease") && p.startsOperand():
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, true, err
+		}
+		if !p.check(lexer.IDENTIFIER) || !strings.EqualFold(p.peek().Value, "by") {
+			return nil, true, p.error(fmt.Sprintf("expected 'by' after the value to %s", word))
+		}
+		p.advance()
+		percent, err := p.parseExpression()
+		if err != nil {
+			return nil, true, err
+		}
+		// "increase $100 by 10" is a slip for 10%, not an increase of 1000%
+		if num, ok := percent.(*ast.NumberLiteral); ok && !strings.HasSuffix(num.SourceText, "%") {
+			return nil, true, p.error(fmt.Sprintf("expected a percentage like %s%% after 'by', got %s", num.SourceText, num.SourceText))
+		}
+		return &ast.FunctionCall{
+			Name:      word,
+			Arguments: []ast.Node{value, percent},
+			Range:     spanRange(name, p.previous()),
+		}, true, nil
+
+	case word == "change" && p.match(lexer.FROM):
+		from, err := p.parseExpression()
+		if err != nil {
+			return nil, true, err
+		}
+		if !p.check(lexer.IDENTIFIER) || !strings.EqualFold(p.peek().Value, "to") {
+			return nil, true, p.error("expected 'to' after 'change from' value")
+		}
+		p.advance()
+		to, err := p.parseExpression()
+		if err != nil {
+			return nil, true, err
+		}
+		if !p.checkInPercent() {
+			return nil, true, p.error("expected 'in %' after 'change from ... to' value")
+		}
+		p.advance() // "in"
+		p.advance() // "%"
+		return &ast.FunctionCall{
+			Name:      "percent_change",
+			Arguments: []ast.Node{from, to},
+			Range:     spanRange(name, p.previous()),
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+// startsOperand reports whether the current token can begin an operand.
+func (p *RecursiveDescentParser) startsOperand() bool {
+	switch p.peek().Type {
+	case lexer.NUMBER, lexer.NUMBER_K, lexer.NUMBER_M, lexer.NUMBER_B, lexer.NUMBER_T,
+		lexer.NUMBER_PERCENT, lexer.NUMBER_SCI, lexer.QUANTITY, lexer.CURRENCY_SYM,
+		lexer.CURRENCY_CODE, lexer.IDENTIFIER, lexer.LPAREN, lexer.LBRACKET,
+		lexer.FUNC_AVG, lexer.FUNC_SQRT, lexer.FUNC_AVERAGE_OF, lexer.FUNC_SQUARE_ROOT_OF,
+		lexer.FUNC_STDEV_OF:
+		return true
+	}
+	return false
+}
+
+// checkInPercent reports whether the next tokens are "in %".
+func (p *RecursiveDescentParser) checkInPercent() bool {
+	return p.check(lexer.IN) && p.peekAhead(1).Type == lexer.MODULUS
+}
+
+// parseFromToYears parses the years of adjust(amount from 2015 to 2025)
+// after "from".
+// FromTo → Expression 'to' Expression
+func (p *RecursiveDescentParser) parseFromToYears() ([]ast.Node, error) {
+	from, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(lexer.IDENTIFIER) || !strings.EqualFold(p.peek().Value, "to") {
+		return nil, p.error("expected 'to' after the year adjust() converts from")
+	}
+	p.advance()
+	to, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return []ast.Node{from, to}, nil
+}
+
+// parseArgument parses a function argument.
+// Argument → STRING | Expression
+func (p *RecursiveDescentParser) parseArgument() (ast.Node, error) {
+	if p.match(lexer.STRING) {
+		tok := p.previous()
+		return &ast.StringLiteral{Value: tok.Value, Range: spanRange(tok, tok)}, nil
+	}
+	return p.parseExpression()
+}
+
+// parseListLiteral parses a list literal after the opening bracket.
+// ListLiteral → '[' Expression (',' Expression)* ']'
+func (p *RecursiveDescentParser) parseListLiteral() (ast.Node, error) {
+	// Security: track nesting depth for nested lists
+	if err := p.enterDepth(); err != nil {
+		return nil, err
+	}
+	defer p.exitDepth()
+
+	lbracket := p.previous()
+	if p.check(lexer.RBRACKET) {
+		return nil, p.error("list must contain at least one element")
+	}
+
+	var elements []ast.Node
+	for {
+		elem, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, elem)
+
+		if !p.match(lexer.COMMA) {
+			break
+		}
+	}
+
+	rbracket, err := p.consume(lexer.RBRACKET, "expected ']' after list elements")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ListLiteral{Elements: elements, Range: spanRange(lbracket, rbracket)}, nil
+}
+
 // parseFromTarget parses the target of a "from" expression.
 // Valid targets: today, tomorrow, yesterday, or date literals (Dec 25, Dec 25 2025)
 func (p *RecursiveDescentParser) parseFromTarget() (ast.Node, error) {
@@ -1195,6 +1501,10 @@ func isNaturalSyntaxKeyword(ident string) bool {
 		return true
 	case "as":
 		return true // Used in "as napkin" conversion syntax
+	case "to":
+		return true // Used in "change from 80 to 100 in %" and "adjust(x from 2015 to 2025)"
+	case "by":
+		return true // Used in "increase 100 by 10%"
 	default:
 		return false
 	}
@@ -161,3 +161,39 @@ func TestFrontmatterAssignment(t *testing.T) {
 		})
 	}
 }
+
+// TestStatementRanges tests that assignments and operators span their
+// source, so diagnostics on them have a position.
+func TestStatementRanges(t *testing.T) {
+	nodes, err := parser.Parse("x = 1\ntotal = 5 kg + 3 m\n2 ^ 3 > 7\nxs = [1, 2]\n")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if len(nodes) != 4 {
+		t.Fatalf("expected 4 statements, got %d", len(nodes))
+	}
+
+	assign, ok := nodes[1].(*ast.Assignment)
+	if !ok {
+		t.Fatalf("expected an assignment, got %T", nodes[1])
+	}
+	tests := []struct {
+		name string
+		node ast.Node
+		want ast.Range
+	}{
+		{"assignment", assign, ast.Range{Start: ast.Position{Line: 2, Column: 1}, End: ast.Position{Line: 2, Column: 19}}},
+		{"addition", assign.Value, ast.Range{Start: ast.Position{Line: 2, Column: 9}, End: ast.Position{Line: 2, Column: 19}}},
+		{"comparison", nodes[2], ast.Range{Start: ast.Position{Line: 3, Column: 1}, End: ast.Position{Line: 3, Column: 10}}},
+		{"power", nodes[2].(*ast.ComparisonOp).Left, ast.Range{Start: ast.Position{Line: 3, Column: 1}, End: ast.Position{Line: 3, Column: 6}}},
+		{"list", nodes[3].(*ast.Assignment).Value, ast.Range{Start: ast.Position{Line: 4, Column: 6}, End: ast.Position{Line: 4, Column: 12}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.node.GetRange()
+			if got == nil || *got != tt.want {
+				t.Errorf("range = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+)
+
+// ReparseStatement updates stmts, parsed from tokens, for an edit that
+// replaces line (1-indexed) with text. Only the edited line is re-lexed
+// and parsed; the other statements and tokens are reused. It returns the
+// updated statements and the tokens to pass to the next call.
+//
+// Statements never span lines, so the edit replaces, adds, or removes at
+// most one statement.
+func ReparseStatement(stmts []ast.Node, tokens []lexer.Token, line int, text string, loc lexer.NumberLocale) ([]ast.Node, []lexer.Token, error) {
+	index, had := statementIndex(tokens, line)
+	if total, _ := statementIndex(tokens, math.MaxInt); total != len(stmts) {
+		return nil, nil, fmt.Errorf("%d statements do not match tokens for %d", len(stmts), total)
+	}
+
+	newTokens, err := lexer.RetokenizeLine(tokens, line, text, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(newTokens) > MaxTokenCount {
+		return nil, nil, &SecurityError{
+			Message: fmt.Sprintf("token count exceeds security limit: %d tokens (max %d)", len(newTokens), MaxTokenCount),
+			Limit:   "MaxTokenCount",
+			Actual:  len(newTokens),
+		}
+	}
+
+	var lineTokens []lexer.Token
+	for _, tok := range newTokens {
+		if tok.Line == line && tok.Type != lexer.NEWLINE && tok.Type != lexer.EOF {
+			lineTokens = append(lineTokens, tok)
+		}
+	}
+	p := &RecursiveDescentParser{
+		tokens:   append(lineTokens, lexer.Token{Type: lexer.EOF, Line: line}),
+		source:   text,
+		maxDepth: MaxNestingDepth,
+	}
+	parsed, err := p.parseProgram()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	end := index
+	if had {
+		end++
+	}
+	return slices.Concat(stmts[:index], parsed, stmts[end:]), newTokens, nil
+}
+
+// statementIndex returns how many lines before line hold a statement, and
+// whether line itself does.
+func statementIndex(tokens []lexer.Token, line int) (int, bool) {
+	count, last := 0, 0
+	for _, tok := range tokens {
+		if tok.Type == lexer.NEWLINE || tok.Type == lexer.EOF || tok.Line == last {
+			continue
+		}
+		if tok.Line >= line {
+			return count, tok.Line == line
+		}
+		count++
+		last = tok.Line
+	}
+	return count, false
+}
@@ -0,0 +1,102 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestReparseStatement(t *testing.T) {
+	source := "a = 1\nb = a + 2\n\nc = b * 3\n"
+	tests := []struct {
+		name string
+		line int
+		text string
+	}{
+		{"replace", 2, "b = a + 20"},
+		{"add", 3, "x = 5"},
+		{"remove", 2, ""},
+		{"last line", 5, "d = c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := lexer.NewLexer(source).Tokenize()
+			if err != nil {
+				t.Fatalf("Tokenize failed: %v", err)
+			}
+			stmts, err := parser.Parse(source)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			got, _, err := parser.ReparseStatement(stmts, tokens, tt.line, tt.text, lexer.LocaleUS)
+			if err != nil {
+				t.Fatalf("ReparseStatement failed: %v", err)
+			}
+
+			lines := strings.Split(source, "\n")
+			lines[tt.line-1] = tt.text
+			want, err := parser.Parse(strings.Join(lines, "\n"))
+			if err != nil {
+				t.Fatalf("Parse of edited source failed: %v", err)
+			}
+			if statementStrings(got) != statementStrings(want) {
+				t.Errorf("ReparseStatement = %s, want %s", statementStrings(got), statementStrings(want))
+			}
+			// Unedited statements are reused, not reparsed
+			if tt.line > 1 && got[0] != stmts[0] {
+				t.Error("statement before the edit was reparsed")
+			}
+		})
+	}
+}
+
+func TestReparseStatementChained(t *testing.T) {
+	source := "a = 1\nb = 2\n"
+	tokens, _ := lexer.NewLexer(source).Tokenize()
+	stmts, _ := parser.Parse(source)
+
+	// Tokens returned by one call feed the next
+	var err error
+	for _, text := range []string{"b = 2 +", "b = 2 + a", "b = 2 + a * 10"} {
+		stmts, tokens, err = parser.ReparseStatement(stmts, tokens, 2, text, lexer.LocaleUS)
+		if text == "b = 2 +" {
+			if err == nil {
+				t.Fatal("expected parse error for incomplete line")
+			}
+			if pe, ok := err.(*parser.ParseError); !ok || pe.Line != 2 {
+				t.Errorf("error = %v, want a ParseError on line 2", err)
+			}
+			tokens, _ = lexer.NewLexer(source).Tokenize()
+			stmts, _ = parser.Parse(source)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ReparseStatement(%q) failed: %v", text, err)
+		}
+	}
+	if got := statementStrings(stmts); got != statementStrings(mustParse(t, "a = 1\nb = 2 + a * 10\n")) {
+		t.Errorf("statements = %s", got)
+	}
+}
+
+func mustParse(t *testing.T, source string) []ast.Node {
+	t.Helper()
+	nodes, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return nodes
+}
+
+func statementStrings(nodes []ast.Node) string {
+	strs := make([]string, len(nodes))
+	for i, node := range nodes {
+		strs[i] = node.String()
+	}
+	return strings.Join(strs, "; ")
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/lexer"
+)
+
+// parseSchedule parses a recurring schedule after "every".
+//
+//	Schedule → "every" Period ("on" "the" Ordinal)? (("from" | "starting") Unary)?
+//	Period   → Weekday                      every Friday
+//	         | "other" (Weekday | Unit)     every other Friday, every other month
+//	         | Ordinal Weekday              every 2nd Friday (every 2 weeks)
+//	         | Ordinal Unit                 every 3rd month
+//	         | Ordinal                      every 15th (of the month)
+//	         | Unit                         every month
+//	         | DURATION_LITERAL             every 2 weeks
+func (p *RecursiveDescentParser) parseSchedule() (ast.Node, error) {
+	first := p.previous() // SCHEDULE_EVERY
+	node := &ast.ScheduleLiteral{Every: "1"}
+
+	switch {
+	case p.match(lexer.DURATION_LITERAL):
+		parts := strings.Split(p.previous().Value, ":")
+		if len(parts) != 2 {
+			return nil, p.error("a schedule repeats every one period, such as 'every 2 weeks'")
+		}
+		node.Every, node.Unit = parts[0], parts[1]
+	case p.match(lexer.NUMBER):
+		n := p.previous().Value
+		if !p.check(lexer.IDENTIFIER) || !lexer.OrdinalSuffixes[strings.ToLower(p.peek().Value)] {
+			return nil, p.error("expected an ordinal such as '2nd' or '15th' after 'every'")
+		}
+		p.advance()
+		switch {
+		case p.matchWeekday(node), p.matchScheduleUnit(node):
+			node.Every = n
+		default:
+			node.Unit, node.Day = "month", n
+		}
+	case p.check(lexer.IDENTIFIER) && strings.EqualFold(p.peek().Value, "other"):
+		p.advance()
+		if !p.matchWeekday(node) && !p.matchScheduleUnit(node) {
+			return nil, p.error("expected a weekday or 'day', 'week', 'month', or 'year' after 'every other'")
+		}
+		node.Every = "2"
+	case p.matchWeekday(node), p.matchScheduleUnit(node):
+	default:
+		return nil, p.error("expected a weekday, ordinal, or period after 'every'")
+	}
+
+	// "every month on the 15th"
+	if p.check(lexer.IDENTIFIER) && strings.EqualFold(p.peek().Value, "on") {
+		p.advance()
+		if p.check(lexer.IDENTIFIER) && strings.EqualFold(p.peek().Value, "the") {
+			p.advance()
+		}
+		day, err := p.consume(lexer.NUMBER, "expected a day of the month after 'on'")
+		if err != nil {
+			return nil, err
+		}
+		if p.check(lexer.IDENTIFIER) && lexer.OrdinalSuffixes[strings.ToLower(p.peek().Value)] {
+			p.advance()
+		}
+		node.Day = day.Value
+	}
+
+	if p.check(lexer.FROM) || (p.check(lexer.IDENTIFIER) && strings.EqualFold(p.peek().Value, "starting")) {
+		p.advance()
+		start, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		node.Start = start
+	}
+
+	node.Range = spanRange(first, p.previous())
+	node.SourceText = p.sourceText(first, p.previous())
+	return node, nil
+}
+
+// matchWeekday consumes a weekday name into node.
+func (p *RecursiveDescentParser) matchWeekday(node *ast.ScheduleLiteral) bool {
+	if !p.check(lexer.IDENTIFIER) {
+		return false
+	}
+	weekday, ok := lexer.WeekdayNames[strings.ToLower(p.peek().Value)]
+	if !ok {
+		return false
+	}
+	p.advance()
+	node.Weekday = weekday
+	return true
+}
+
+// matchScheduleUnit consumes a day, week, month, or year unit into node.
+func (p *RecursiveDescentParser) matchScheduleUnit(node *ast.ScheduleLiteral) bool {
+	if !p.check(lexer.IDENTIFIER) {
+		return false
+	}
+	switch unit := lexer.TimeUnits[strings.ToLower(p.peek().Value)]; unit {
+	case "day", "week", "month", "year":
+		p.advance()
+		node.Unit = unit
+		return true
+	}
+	return false
+}
+
+// parseNextOccurrence parses "next payday" and "next payday after Jun 1"
+// into next(payday) and next(payday, Jun 1).
+func (p *RecursiveDescentParser) parseNextOccurrence() (ast.Node, error) {
+	schedule, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	args := []ast.Node{schedule}
+	if p.check(lexer.IDENTIFIER) && strings.EqualFold(p.peek().Value, "after") {
+		p.advance()
+		after, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, after)
+	}
+	return &ast.FunctionCall{Name: "next", Arguments: args}, nil
+}
+
+// parseCountBetween parses "count paydays between Mar 1 and Jun 30" into
+// count(paydays, Mar 1, Jun 30). As with "workdays between", the dates are
+// parsed above the logical operators so "and" separates them.
+func (p *RecursiveDescentParser) parseCountBetween() (ast.Node, error) {
+	schedule, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(lexer.IDENTIFIER) || !strings.EqualFold(p.peek().Value, "between") {
+		return nil, p.error("expected 'between' after the schedule in 'count ... between'")
+	}
+	p.advance()
+	start, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(lexer.AND, "expected 'and' between the dates of 'count ... between'"); err != nil {
+		return nil, err
+	}
+	end, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.FunctionCall{Name: "count", Arguments: []ast.Node{schedule, start, end}}, nil
+}
+
+// quarterPattern matches quarter names: Q1, q3
+var quarterPattern = regexp.MustCompile(`^[Qq][1-4]$`)
+
+// parseQuarter parses the quarter of "paydays in Q3" or "paydays in Q3 2026"
+// after "in", into occurrences(paydays, Jul 1, Sep 30). Without a year, the
+// quarter is in the current year. It reports false if the next token isn't
+// a quarter, leaving it for unit conversion.
+func (p *RecursiveDescentParser) parseQuarter(schedule ast.Node) (ast.Node, bool) {
+	if !p.check(lexer.IDENTIFIER) || !quarterPattern.MatchString(p.peek().Value) {
+		return nil, false
+	}
+	quarter, _ := strconv.Atoi(p.advance().Value[1:])
+
+	var year *string
+	if p.check(lexer.NUMBER) && len(p.peek().Value) == 4 {
+		y := p.advance().Value
+		year = &y
+	}
+
+	first := time.Month(3*quarter - 2).String()
+	last := time.Month(3 * quarter).String()
+	lastDay := map[int]string{1: "31", 2: "30", 3: "30", 4: "31"}[quarter]
+	return &ast.FunctionCall{
+		Name: "occurrences",
+		Arguments: []ast.Node{
+			schedule,
+			&ast.DateLiteral{Month: first, Day: "1", Year: year, SourceText: first + " 1"},
+			&ast.DateLiteral{Month: last, Day: lastDay, Year: year, SourceText: last + " " + lastDay},
+		},
+	}, true
+}
+
+// sourceText returns the source from the start of first to the end of last.
+func (p *RecursiveDescentParser) sourceText(first, last lexer.Token) string {
+	if first.StartPos < 0 || last.EndPos > len(p.source) || first.StartPos > last.EndPos {
+		return ""
+	}
+	return p.source[first.StartPos:last.EndPos]
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+)
+
+// parseTimeLiteral builds a TimeLiteral from a TIME_LITERAL token, whose
+// value is "hour:minute:second:period:zone" (see the lexer). The zone is an
+// IANA name or a UTC offset such as "UTC-7" or "UTC+5:30".
+func (p *RecursiveDescentParser) parseTimeLiteral() (ast.Node, error) {
+	tok := p.previous()
+	parts := strings.SplitN(tok.Value, ":", 5)
+	if len(parts) != 5 {
+		return nil, p.errorAt(tok, "invalid time format")
+	}
+
+	node := &ast.TimeLiteral{
+		Hour:       parts[0],
+		Minute:     parts[1],
+		SourceText: tok.OriginalText,
+		Range:      spanRange(tok, tok),
+	}
+	if parts[2] != "" {
+		node.Second = &parts[2]
+	}
+	if parts[3] != "" {
+		node.Period = &parts[3]
+	}
+
+	zone := parts[4]
+	if offset, ok := strings.CutPrefix(zone, "UTC"); ok && offset != "" {
+		hours, minutes, hasMinutes := strings.Cut(offset[1:], ":")
+		node.UTCOffset = &ast.UTCOffset{Sign: offset[:1], Hours: hours}
+		if hasMinutes {
+			node.UTCOffset.Minutes = &minutes
+		}
+	} else {
+		node.Zone = zone
+	}
+	return node, nil
+}
@@ -35,10 +35,12 @@ const (
 	TOKEN_LTE // <=
 
 	// Delimiters
-	TOKEN_LPAREN // (
-	TOKEN_RPAREN // )
-	TOKEN_COMMA  // ,
-	TOKEN_COLON  // :
+	TOKEN_LPAREN   // (
+	TOKEN_RPAREN   // )
+	TOKEN_LBRACKET // [
+	TOKEN_RBRACKET // ]
+	TOKEN_COMMA    // ,
+	TOKEN_COLON    // :
 
 	// Date/Time keywords
 	TOKEN_TODAY
@@ -124,6 +126,8 @@ var tokenNames = map[TokenType]string{
 	TOKEN_LTE:             "<=",
 	TOKEN_LPAREN:          "(",
 	TOKEN_RPAREN:          ")",
+	TOKEN_LBRACKET:        "[",
+	TOKEN_RBRACKET:        "]",
 	TOKEN_COMMA:           ",",
 	TOKEN_COLON:           ":",
 	TOKEN_TODAY:           "today",
@@ -1,15 +1,25 @@
 package semantic
 
 import (
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/fuzzy"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/CalcMark/go-calcmark/spec/units"
 )
 
 // Checker performs semantic validation on AST nodes.
 type Checker struct {
 	env         *Environment
 	diagnostics []Diagnostic
+	complexity  ComplexityLimits
+	naming      NamingRules
+	functions   map[string]FunctionSignature // Custom functions, by name
+	inferred    map[string]TypeInfo          // Types inferred for variables assigned while checking
 }
 
 // NewChecker creates a new semantic checker with an empty environment.
@@ -17,6 +27,7 @@ func NewChecker() *Checker {
 	return &Checker{
 		env:         NewEnvironment(),
 		diagnostics: make([]Diagnostic, 0),
+		complexity:  DefaultComplexityLimits,
 	}
 }
 
@@ -26,6 +37,7 @@ func NewCheckerWithEnv(env *Environment) *Checker {
 	return &Checker{
 		env:         env,
 		diagnostics: make([]Diagnostic, 0),
+		complexity:  DefaultComplexityLimits,
 	}
 }
 
@@ -34,6 +46,8 @@ func NewCheckerWithEnv(env *Environment) *Checker {
 func (c *Checker) Check(nodes []ast.Node) []Diagnostic {
 	for _, node := range nodes {
 		c.checkNode(node)
+		c.checkComplexity(node)
+		c.checkNaming(node)
 	}
 	return c.diagnostics
 }
@@ -62,24 +76,49 @@ func (c *Checker) checkNode(node ast.Node) {
 	case *ast.FunctionCall:
 		c.checkFunctionCall(n)
 	// Literals don't need semantic checking (they're syntactically valid)
-	case *ast.NumberLiteral, *ast.CurrencyLiteral, *ast.BooleanLiteral:
+	case *ast.NumberLiteral, *ast.BooleanLiteral:
 		// No semantic checks needed for simple literals
+	case *ast.CurrencyLiteral:
+		c.checkCurrencyLiteral(n)
+	case *ast.CurrencyUnit:
+		if !ValidateCurrencyCode(n.Code) {
+			c.addDiagnostic(*CreateInvalidCurrencyDiagnostic(n.Code, n))
+		}
 	case *ast.DateLiteral:
 		c.checkDateLiteral(n) // USER REQUIREMENT: Validate dates
 	case *ast.RelativeDateLiteral:
 		// Validated by lexer/parser
 	case *ast.TimeLiteral, *ast.DurationLiteral:
 		// Validated at parse time
+	case *ast.ScheduleLiteral:
+		c.checkExpression(n.Start)
 	case *ast.QuantityLiteral:
 		c.checkQuantityLiteral(n)
 	case *ast.RateLiteral:
 		c.checkRateLiteral(n)
 	case *ast.UnitConversion:
 		c.checkUnitConversion(n)
+	case *ast.TimeZoneConversion:
+		c.checkExpression(n.Time)
 	case *ast.NapkinConversion:
 		c.checkNapkinConversion(n)
 	case *ast.PercentageOf:
 		c.checkPercentageOf(n)
+	case *ast.ListLiteral:
+		for _, elem := range n.Elements {
+			c.checkExpression(elem)
+		}
+	case *ast.IndexExpr:
+		c.checkExpression(n.Target)
+		c.checkExpression(n.Index)
+	case *ast.StringLiteral:
+		// Data functions check their own; any other is misplaced
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagTypeMismatch,
+			Message:  "Quoted text can only name a file, column, key, or path in lookup() and jsonpath()",
+			Range:    n.Range,
+		})
 	}
 }
 
@@ -89,9 +128,17 @@ func (c *Checker) checkAssignment(a *ast.Assignment) {
 	c.checkExpression(a.Value)
 
 	// Record the variable in the environment
-	// We don't know the actual type yet (that's the interpreter's job),
-	// but we mark it as defined
+	// We don't know the actual value yet (that's the interpreter's job),
+	// but we mark it as defined, with its type when it can be inferred
 	c.env.Set(a.Name, nil)
+	if c.inferred == nil {
+		c.inferred = make(map[string]TypeInfo)
+	}
+	if info, ok := c.inferType(a.Value); ok {
+		c.inferred[a.Name] = info
+	} else {
+		delete(c.inferred, a.Name)
+	}
 }
 
 // checkFrontmatterAssignment validates frontmatter variable assignments.
@@ -117,7 +164,7 @@ func (c *Checker) checkBinaryOp(b *ast.BinaryOp) {
 
 	// USER REQUIREMENT: Check unit compatibility for addition/subtraction
 	if b.Operator == "+" || b.Operator == "-" {
-		c.checkUnitCompatibility(b.Left, b.Right)
+		c.checkUnitCompatibility(b)
 	}
 
 	// Division by zero check (if right operand is a literal zero)
@@ -134,6 +181,8 @@ func (c *Checker) checkBinaryOp(b *ast.BinaryOp) {
 		}
 	}
 
+	c.checkPrecision(b)
+
 	// Note: Full type compatibility checking requires type inference,
 	// which we'll implement in the interpreter. The semantic checker
 	// focuses on obvious errors like undefined variables and invalid currency codes.
@@ -156,11 +205,14 @@ func (c *Checker) checkIdentifier(id *ast.Identifier) {
 	if !c.env.Has(id.Name) {
 		// Check if it's a boolean keyword (true, false, yes, no, etc.)
 		if !isBooleanKeyword(id.Name) {
+			suggestions := fuzzy.Closest(id.Name, slices.Sorted(maps.Keys(c.env.GetAllVariables())), maxSuggestions)
 			c.addDiagnostic(Diagnostic{
-				Severity: Error, // ERROR: undefined variables block evaluation
-				Code:     DiagUndefinedVariable,
-				Message:  `Undefined variable "` + id.Name + `"`,
-				Range:    id.Range,
+				Severity:    Error, // ERROR: undefined variables block evaluation
+				Code:        DiagUndefinedVariable,
+				Message:     `Undefined variable "` + id.Name + `"`,
+				Range:       id.Range,
+				Suggestions: suggestions,
+				Fixes:       undefinedVariableFixes(id, suggestions),
 			})
 		}
 	}
@@ -186,6 +238,24 @@ func (c *Checker) checkFunctionCall(f *ast.FunctionCall) {
 			c.checkExpression(f.Arguments[0])
 		}
 		return
+	case "increase", "decrease", "percent_change":
+		c.checkPercentageFunction(f)
+		return
+	case "workdays":
+		c.checkWorkdays(f)
+		return
+	case "lookup", "jsonpath":
+		c.checkDataFunction(f)
+		return
+	case "fv", "pmt", "npv", "irr", "amortize", "cagr", "grow", "compound", "adjust":
+		c.checkFinanceFunction(f)
+		return
+	case "normal", "uniform":
+		c.checkDistributionFunction(f)
+		return
+	case "next", "count", "occurrences":
+		c.checkScheduleQuery(f)
+		return
 	case "capacity":
 		// capacity(demand, capacity_per_unit, unit_identifier, buffer?)
 		// First two arguments are expressions, third is an identifier, fourth (optional) is expression
@@ -201,7 +271,7 @@ func (c *Checker) checkFunctionCall(f *ast.FunctionCall) {
 		}
 		// Check for base mixing between demand and capacity units
 		if len(f.Arguments) >= 2 {
-			c.checkDataSizeBaseMixingForCapacity(f.Arguments[0], f.Arguments[1])
+			c.checkDataSizeBaseMixingForCapacity(f)
 		}
 		return
 	}
@@ -211,7 +281,253 @@ func (c *Checker) checkFunctionCall(f *ast.FunctionCall) {
 		c.checkExpression(arg)
 	}
 
-	// Function existence is checked by the parser, so we don't need to validate it here
+	if minArgs, ok := aggregateFunctionMinArgs[f.Name]; ok {
+		c.checkAggregateFunction(f, minArgs)
+		return
+	}
+
+	c.checkCallee(f)
+}
+
+// aggregateFunctionMinArgs maps variadic aggregate functions to their minimum argument count.
+var aggregateFunctionMinArgs = map[string]int{
+	"avg":    1,
+	"sum":    1,
+	"min":    1,
+	"max":    1,
+	"median": 1,
+	"stdev":  2,
+}
+
+// checkAggregateFunction validates argument count and unit mixing for
+// variadic aggregate functions (avg, sum, min, max, median, stdev).
+func (c *Checker) checkAggregateFunction(f *ast.FunctionCall, minArgs int) {
+	// List arguments are flattened at runtime, so count their elements instead
+	var values []ast.Node
+	count := 0
+	countKnown := true
+	for _, arg := range f.Arguments {
+		switch a := arg.(type) {
+		case *ast.ListLiteral:
+			values = append(values, a.Elements...)
+			count += len(a.Elements)
+		case *ast.Identifier:
+			// A variable may hold a list; its size is only known if the
+			// environment was pre-populated with evaluated values
+			val, _ := c.env.Get(a.Name)
+			if list, ok := val.(*types.List); ok {
+				count += list.Len()
+			} else if val == nil {
+				countKnown = false
+			} else {
+				count++
+			}
+		default:
+			values = append(values, arg)
+			count++
+		}
+	}
+
+	if countKnown && count < minArgs {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  fmt.Sprintf("%s() requires at least %d argument(s)", f.Name, minArgs),
+			Range:    f.Range,
+		})
+		return
+	}
+
+	// Mixed currencies are allowed but the result drops to a plain number
+	firstUnit := ""
+	for _, arg := range values {
+		unit := getNodeUnit(arg)
+		if unit == "" {
+			continue
+		}
+		if firstUnit == "" {
+			firstUnit = unit
+			continue
+		}
+		if unit != firstUnit {
+			c.addDiagnostic(Diagnostic{
+				Severity: Hint,
+				Code:     DiagMixedUnits,
+				Message:  "mixed units in " + f.Name + "()",
+				Detailed: fmt.Sprintf(
+					"%s() was called with both %s and %s. The result will be a plain number without units.",
+					f.Name, firstUnit, unit),
+				Range: f.Range,
+				Fixes: convertUnitsFixes(values, firstUnit),
+			})
+			return
+		}
+	}
+}
+
+// financeFunctionArgs maps the finance, growth, and inflation functions to
+// their argument counts and the position of their rate, -1 for none. npv()
+// and irr() take any number of cash flows, so they have no maximum.
+var financeFunctionArgs = map[string]struct {
+	min, max int
+	usage    string
+	rate     int
+}{
+	"fv":  {4, 4, "4 arguments (rate, periods, payment, pv)", 0},
+	"pmt": {3, 3, "3 arguments (rate, nper, pv)", 0},
+	"npv": {2, -1, "a rate and at least one cash flow", 0},
+	"irr": {1, -1, "cash flows", -1},
+
+	"amortize": {3, 3, "3 arguments (principal, rate, years)", 1},
+
+	"cagr":     {3, 3, "3 arguments (start, end, years)", -1},
+	"grow":     {3, 3, "3 arguments (value, rate, time)", 1},
+	"compound": {3, 3, "3 arguments (value, rate, periods)", 1},
+
+	"adjust": {3, 3, "3 arguments (amount, from year, to year), or adjust(amount from 2015 to 2025)", -1},
+}
+
+// financeRateDetails explains the rate of the functions whose rate isn't
+// per period.
+var financeRateDetails = map[string]string{
+	"amortize": "The rate is yearly, without units: amortize($300000, 6%, 30) for 6% a year over 30 years.",
+	"grow":     "The rate is a percentage, with its period: grow($100, 5% per year, 3 years).",
+}
+
+// checkFinanceFunction validates the finance, growth, and inflation
+// functions: their argument count, and that the rate, if they take one, is
+// a dimensionless percentage rather than money or a unit. grow()'s may also
+// be per a period of time, as in 5% per year.
+func (c *Checker) checkFinanceFunction(f *ast.FunctionCall) {
+	for _, arg := range f.Arguments {
+		c.checkExpression(arg)
+	}
+
+	args := financeFunctionArgs[f.Name]
+	if len(f.Arguments) < args.min || (args.max >= 0 && len(f.Arguments) > args.max) {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  fmt.Sprintf("%s() requires %s", f.Name, args.usage),
+			Range:    f.Range,
+		})
+		return
+	}
+	if args.rate < 0 {
+		return
+	}
+
+	rate := f.Arguments[args.rate]
+	if r, ok := rate.(*ast.RateLiteral); ok && f.Name == "grow" {
+		rate = r.Amount // 5% per year: the percentage is checked
+	}
+	detailed, ok := financeRateDetails[f.Name]
+	if !ok {
+		detailed = fmt.Sprintf("The rate is per period, without units: %s(5%%/12, ...) for 5%% a year paid monthly.", f.Name)
+	}
+	if kind := c.dimensionKind(rate); kind != "" && (f.Name != "grow" || kind != "a rate with units") {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagTypeMismatch,
+			Message:  fmt.Sprintf("%s() rate must be a percentage, not %s", f.Name, kind),
+			Detailed: detailed,
+			Range:    rate.GetRange(),
+		})
+	}
+}
+
+// distributionFunctionUsage describes the arguments of the distribution
+// functions, which take exactly two.
+var distributionFunctionUsage = map[string]string{
+	"normal":  "2 arguments (mean, standard deviation)",
+	"uniform": "2 arguments (low, high)",
+}
+
+// checkDistributionFunction validates normal() and uniform(): their
+// argument count. Units are checked when the samples are drawn.
+func (c *Checker) checkDistributionFunction(f *ast.FunctionCall) {
+	for _, arg := range f.Arguments {
+		c.checkExpression(arg)
+	}
+	if len(f.Arguments) != 2 {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  fmt.Sprintf("%s() requires %s", f.Name, distributionFunctionUsage[f.Name]),
+			Range:    f.Range,
+		})
+	}
+}
+
+// dimensionKind describes the value a node has when it is not a plain
+// number, such as "a currency", or returns "" for plain numbers and nodes
+// whose type isn't known before evaluation.
+func (c *Checker) dimensionKind(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.CurrencyLiteral:
+		return "a currency"
+	case *ast.CurrencyUnit:
+		return "a currency basket"
+	case *ast.QuantityLiteral:
+		return "a quantity"
+	case *ast.RateLiteral:
+		return "a rate with units"
+	case *ast.DurationLiteral:
+		return "a duration"
+	case *ast.DateLiteral, *ast.RelativeDateLiteral:
+		return "a date"
+	case *ast.TimeLiteral:
+		return "a time"
+	case *ast.BooleanLiteral:
+		return "a boolean"
+	case *ast.ListLiteral:
+		return "a list"
+	case *ast.FunctionCall:
+		if n.Name == "amortize" {
+			return "a table"
+		}
+		return ""
+	case *ast.BinaryOp:
+		// Scaling keeps the dimension: $5 / 12 is still money
+		if n.Operator == "*" || n.Operator == "/" {
+			if kind := c.dimensionKind(n.Left); kind != "" {
+				return kind
+			}
+			if n.Operator == "*" {
+				return c.dimensionKind(n.Right)
+			}
+		}
+		return ""
+	case *ast.Identifier:
+		// Only known when the environment holds evaluated values
+		val, _ := c.env.Get(n.Name)
+		switch val.(type) {
+		case nil, *types.Number:
+			return ""
+		case *types.Currency:
+			return "a currency"
+		case *types.Quantity:
+			return "a quantity"
+		case *types.Rate:
+			return "a rate with units"
+		case *types.Duration:
+			return "a duration"
+		case *types.Date:
+			return "a date"
+		case *types.Boolean:
+			return "a boolean"
+		case *types.List:
+			return "a list"
+		case *types.Basket:
+			return "a currency basket"
+		case *types.Table:
+			return "a table"
+		default:
+			return ""
+		}
+	default:
+		return ""
+	}
 }
 
 // checkQuantityLiteral validates quantity literals.
@@ -235,7 +551,37 @@ func (c *Checker) checkUnitConversion(u *ast.UnitConversion) {
 	if u.Quantity != nil {
 		c.checkExpression(u.Quantity)
 	}
-	// Target unit validity is checked at runtime by the interpreter
+
+	// Only flag targets when the source is a standard unit: user-defined
+	// units and data sizes are resolved by the interpreter at runtime.
+	if _, known := units.NormalizeUnitName(getNodeUnit(u.Quantity)); !known {
+		return
+	}
+	if _, known := units.NormalizeUnitName(u.TargetUnit); known || IsDataSizeUnit(u.TargetUnit) {
+		return
+	}
+	if ValidateCurrencyCode(u.TargetUnit) {
+		return // Incompatible, not unknown - reported by the interpreter
+	}
+
+	suggestions := units.SuggestUnits(u.TargetUnit, maxSuggestions)
+	c.addDiagnostic(Diagnostic{
+		Severity:    Error,
+		Code:        DiagUnknownUnit,
+		Message:     fmt.Sprintf("unknown unit %q.%s", u.TargetUnit, didYouMean(suggestions)),
+		Range:       u.Range,
+		Suggestions: suggestions,
+		Fixes:       replacementFixes(u.TargetRange, suggestions),
+	})
+}
+
+// checkCurrencyLiteral hints when a currency-like code (e.g., "USX") is not
+// a recognized ISO 4217 currency, with suggestions for the closest codes.
+func (c *Checker) checkCurrencyLiteral(cl *ast.CurrencyLiteral) {
+	if ValidateCurrencyCode(cl.Symbol) {
+		return
+	}
+	c.addDiagnostic(*CreateInvalidCurrencyDiagnostic(cl.Symbol, cl))
 }
 
 // checkNapkinConversion validates napkin conversions (e.g., "1234567 as napkin").
@@ -257,6 +603,52 @@ func (c *Checker) checkPercentageOf(p *ast.PercentageOf) {
 	}
 }
 
+// percentageFunctionUsage describes the arguments of the percentage
+// functions, which take exactly two.
+var percentageFunctionUsage = map[string]string{
+	"increase":       "2 arguments (value, percentage), or increase value by 10%",
+	"decrease":       "2 arguments (value, percentage), or decrease value by 10%",
+	"percent_change": "2 arguments (from, to), or change from 80 to 100 in %",
+}
+
+// checkPercentageFunction validates increase(), decrease(), and
+// percent_change(): their argument count, and that the percentage
+// increase() and decrease() take isn't money or a quantity.
+func (c *Checker) checkPercentageFunction(f *ast.FunctionCall) {
+	for _, arg := range f.Arguments {
+		c.checkExpression(arg)
+	}
+	if len(f.Arguments) != 2 {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  fmt.Sprintf("%s() requires %s", f.Name, percentageFunctionUsage[f.Name]),
+			Range:    f.Range,
+		})
+		return
+	}
+	if f.Name == "percent_change" {
+		return
+	}
+
+	var kind string
+	switch f.Arguments[1].(type) {
+	case *ast.CurrencyLiteral:
+		kind = "a currency"
+	case *ast.QuantityLiteral:
+		kind = "a quantity"
+	default:
+		return
+	}
+	c.addDiagnostic(Diagnostic{
+		Severity: Error,
+		Code:     DiagTypeMismatch,
+		Message:  fmt.Sprintf("%s() takes a percentage, not %s", f.Name, kind),
+		Detailed: fmt.Sprintf("The percentage is a plain number: %s $200 by 10%%.", f.Name),
+		Range:    f.Arguments[1].GetRange(),
+	})
+}
+
 // addDiagnostic adds a diagnostic to the checker's list.
 func (c *Checker) addDiagnostic(d Diagnostic) {
 	c.diagnostics = append(c.diagnostics, d)
@@ -278,10 +670,11 @@ func (c *Checker) GetEnvironment() *Environment {
 	return c.env
 }
 
-// checkDataSizeBaseMixingForCapacity checks if demand and capacity units mix binary and decimal bases.
-func (c *Checker) checkDataSizeBaseMixingForCapacity(demand, capacity ast.Node) {
-	demandUnit := getNodeUnit(demand)
-	capacityUnit := getNodeUnit(capacity)
+// checkDataSizeBaseMixingForCapacity checks if a capacity call's demand and
+// capacity units mix binary and decimal bases.
+func (c *Checker) checkDataSizeBaseMixingForCapacity(f *ast.FunctionCall) {
+	demandUnit := getNodeUnit(f.Arguments[0])
+	capacityUnit := getNodeUnit(f.Arguments[1])
 
-	c.checkDataSizeBaseMixingForUnits(demandUnit, capacityUnit)
+	c.checkDataSizeBaseMixingForUnits(demandUnit, capacityUnit, f.Range)
 }
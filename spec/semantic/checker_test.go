@@ -1,6 +1,7 @@
 package semantic
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
@@ -61,6 +62,9 @@ func TestCurrencyValidation(t *testing.T) {
 		{"JPY", true},
 		{"$", true},     // Symbol
 		{"€", true},     // Symbol
+		{"₿", true},     // Symbol
+		{"BTC", true},   // Cryptocurrency
+		{"ETH", true},   // Cryptocurrency
 		{"XYZ", false},  // Invalid code
 		{"usd", false},  // Lowercase
 		{"US", false},   // Too short
@@ -88,6 +92,7 @@ func TestNormalizeCurrencySymbol(t *testing.T) {
 		{"€", "EUR", true},
 		{"£", "GBP", true},
 		{"¥", "JPY", true},
+		{"₿", "BTC", true},
 		{"USD", "USD", false},
 		{"GBP", "GBP", false},
 	}
@@ -262,3 +267,270 @@ func TestFunctionCallArgumentValidation(t *testing.T) {
 		}
 	}
 }
+
+// TestAggregateFunctionArgumentCount tests minimum argument counts for aggregate functions
+func TestAggregateFunctionArgumentCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		funcName  string
+		argCount  int
+		wantError bool
+	}{
+		{"sum with no args", "sum", 0, true},
+		{"sum with one arg", "sum", 1, false},
+		{"median with no args", "median", 0, true},
+		{"stdev with one arg", "stdev", 1, true},
+		{"stdev with two args", "stdev", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]ast.Node, tt.argCount)
+			for i := range args {
+				args[i] = &ast.NumberLiteral{Value: "1"}
+			}
+
+			checker := NewChecker()
+			diagnostics := checker.Check([]ast.Node{&ast.FunctionCall{
+				Name:      tt.funcName,
+				Arguments: args,
+				Range:     &ast.Range{},
+			}})
+
+			gotError := false
+			for _, d := range diagnostics {
+				if d.Code == DiagInvalidArgumentCount && d.Severity == Error {
+					gotError = true
+				}
+			}
+			if gotError != tt.wantError {
+				t.Errorf("got argument count error = %v, want %v (diagnostics: %v)", gotError, tt.wantError, diagnostics)
+			}
+		})
+	}
+}
+
+// TestAggregateFunctionMixedUnits tests the hint for mixed units in aggregate functions
+func TestAggregateFunctionMixedUnits(t *testing.T) {
+	checker := NewChecker()
+
+	// sum($100, €200)
+	diagnostics := checker.Check([]ast.Node{&ast.FunctionCall{
+		Name: "sum",
+		Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "100", Symbol: "$"},
+			&ast.CurrencyLiteral{Value: "200", Symbol: "€"},
+		},
+		Range: &ast.Range{},
+	}})
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d", len(diagnostics))
+	}
+	if diagnostics[0].Code != DiagMixedUnits || diagnostics[0].Severity != Hint {
+		t.Errorf("Expected %s hint, got %s %s", DiagMixedUnits, diagnostics[0].Code, diagnostics[0].Severity)
+	}
+}
+
+// TestInvalidCurrencySuggestions tests that unknown currency codes carry structured suggestions
+func TestInvalidCurrencySuggestions(t *testing.T) {
+	checker := NewChecker()
+
+	// 100 USX
+	diagnostics := checker.Check([]ast.Node{&ast.CurrencyLiteral{Value: "100", Symbol: "USX", Range: &ast.Range{}}})
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d", len(diagnostics))
+	}
+	d := diagnostics[0]
+	if d.Code != DiagInvalidCurrencyCode || d.Severity != Hint {
+		t.Errorf("Expected %s hint, got %s %s", DiagInvalidCurrencyCode, d.Code, d.Severity)
+	}
+	if len(d.Suggestions) > 3 || !slices.Contains(d.Suggestions, "USD") {
+		t.Errorf("Expected up to 3 suggestions including USD, got %v", d.Suggestions)
+	}
+
+	// Valid codes produce no diagnostic
+	diagnostics = NewChecker().Check([]ast.Node{&ast.CurrencyLiteral{Value: "100", Symbol: "EUR"}})
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics for EUR, got %v", diagnostics)
+	}
+}
+
+// TestUnknownUnitSuggestions tests the unknown unit error for conversion targets
+func TestUnknownUnitSuggestions(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		target     string
+		wantError  bool
+		suggestion string
+	}{
+		{"typo in target", "meters", "metrs", true, "meters"},
+		{"known target", "meters", "feet", false, ""},
+		{"data size target", "meters", "GB", false, ""},
+		{"currency target", "meters", "USD", false, ""},
+		{"user-defined source", "widgets", "gadgets", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker()
+			diagnostics := checker.Check([]ast.Node{&ast.UnitConversion{
+				Quantity:   &ast.QuantityLiteral{Value: "10", Unit: tt.source},
+				TargetUnit: tt.target,
+				Range:      &ast.Range{},
+			}})
+
+			var found *Diagnostic
+			for i := range diagnostics {
+				if diagnostics[i].Code == DiagUnknownUnit {
+					found = &diagnostics[i]
+				}
+			}
+			if (found != nil) != tt.wantError {
+				t.Fatalf("got unknown unit error = %v, want %v (diagnostics: %v)", found != nil, tt.wantError, diagnostics)
+			}
+			if found != nil && !slices.Contains(found.Suggestions, tt.suggestion) {
+				t.Errorf("Expected suggestions to contain %q, got %v", tt.suggestion, found.Suggestions)
+			}
+		})
+	}
+}
+
+// TestFinanceFunctionRate tests that finance rates must be dimensionless
+func TestFinanceFunctionRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		funcCall *ast.FunctionCall
+		wantCode string
+	}{
+		{"percentage rate", &ast.FunctionCall{Name: "pmt", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "0.05"}, &ast.NumberLiteral{Value: "12"}, &ast.CurrencyLiteral{Value: "1000", Symbol: "$"},
+		}}, ""},
+		{"currency rate", &ast.FunctionCall{Name: "fv", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "5", Symbol: "$"}, &ast.NumberLiteral{Value: "10"}, &ast.NumberLiteral{Value: "100"}, &ast.NumberLiteral{Value: "0"},
+		}}, DiagTypeMismatch},
+		{"scaled currency rate", &ast.FunctionCall{Name: "npv", Arguments: []ast.Node{
+			&ast.BinaryOp{Operator: "/", Left: &ast.CurrencyLiteral{Value: "6", Symbol: "$"}, Right: &ast.NumberLiteral{Value: "12"}},
+			&ast.NumberLiteral{Value: "100"},
+		}}, DiagTypeMismatch},
+		{"quantity rate", &ast.FunctionCall{Name: "pmt", Arguments: []ast.Node{
+			&ast.QuantityLiteral{Value: "5", Unit: "GB"}, &ast.NumberLiteral{Value: "12"}, &ast.NumberLiteral{Value: "1000"},
+		}}, DiagTypeMismatch},
+		{"pmt missing pv", &ast.FunctionCall{Name: "pmt", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "0.05"}, &ast.NumberLiteral{Value: "12"},
+		}}, DiagInvalidArgumentCount},
+		{"npv without cash flows", &ast.FunctionCall{Name: "npv", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "0.05"},
+		}}, DiagInvalidArgumentCount},
+		{"irr of a list", &ast.FunctionCall{Name: "irr", Arguments: []ast.Node{
+			&ast.ListLiteral{Elements: []ast.Node{&ast.NumberLiteral{Value: "-100"}, &ast.NumberLiteral{Value: "110"}}},
+		}}, ""},
+		{"grow percentage per year", &ast.FunctionCall{Name: "grow", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "100", Symbol: "$"},
+			&ast.RateLiteral{Amount: &ast.NumberLiteral{Value: "0.05"}, PerUnit: "year"},
+			&ast.DurationLiteral{Value: "3", Unit: "years"},
+		}}, ""},
+		{"grow money per year", &ast.FunctionCall{Name: "grow", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "100", Symbol: "$"},
+			&ast.RateLiteral{Amount: &ast.CurrencyLiteral{Value: "5", Symbol: "$"}, PerUnit: "year"},
+			&ast.DurationLiteral{Value: "3", Unit: "years"},
+		}}, DiagTypeMismatch},
+		{"compound quantity rate", &ast.FunctionCall{Name: "compound", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "100"}, &ast.QuantityLiteral{Value: "5", Unit: "GB"}, &ast.NumberLiteral{Value: "3"},
+		}}, DiagTypeMismatch},
+		{"cagr without years", &ast.FunctionCall{Name: "cagr", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "100"}, &ast.NumberLiteral{Value: "200"},
+		}}, DiagInvalidArgumentCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.funcCall.Range = &ast.Range{}
+			diagnostics := NewChecker().Check([]ast.Node{tt.funcCall})
+
+			if tt.wantCode == "" {
+				if len(diagnostics) != 0 {
+					t.Errorf("expected no diagnostics, got %v", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Code != tt.wantCode || diagnostics[0].Severity != Error {
+				t.Errorf("expected one %s error, got %v", tt.wantCode, diagnostics)
+			}
+		})
+	}
+}
+
+func TestDistributionFunctionArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		funcCall *ast.FunctionCall
+		wantCode string
+	}{
+		{"normal", &ast.FunctionCall{Name: "normal", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "100", Symbol: "$"}, &ast.CurrencyLiteral{Value: "15", Symbol: "$"},
+		}}, ""},
+		{"normal without sd", &ast.FunctionCall{Name: "normal", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "100"},
+		}}, DiagInvalidArgumentCount},
+		{"uniform with three", &ast.FunctionCall{Name: "uniform", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "1"}, &ast.NumberLiteral{Value: "2"}, &ast.NumberLiteral{Value: "3"},
+		}}, DiagInvalidArgumentCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.funcCall.Range = &ast.Range{}
+			diagnostics := NewChecker().Check([]ast.Node{tt.funcCall})
+
+			if tt.wantCode == "" && len(diagnostics) != 0 {
+				t.Errorf("expected no diagnostics, got %v", diagnostics)
+			}
+			if tt.wantCode != "" && (len(diagnostics) != 1 || diagnostics[0].Code != tt.wantCode) {
+				t.Errorf("expected one %s error, got %v", tt.wantCode, diagnostics)
+			}
+		})
+	}
+}
+
+// TestPercentageFunctions tests the argument checks of increase(),
+// decrease(), and percent_change()
+func TestPercentageFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		funcCall *ast.FunctionCall
+		wantCode string // "" for no diagnostics
+	}{
+		{"increase by a percentage", &ast.FunctionCall{Name: "increase", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "200", Symbol: "$"}, &ast.NumberLiteral{Value: "0.1"},
+		}}, ""},
+		{"decrease by money", &ast.FunctionCall{Name: "decrease", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "200", Symbol: "$"}, &ast.CurrencyLiteral{Value: "5", Symbol: "$", Range: &ast.Range{}},
+		}}, DiagTypeMismatch},
+		{"percent_change of money", &ast.FunctionCall{Name: "percent_change", Arguments: []ast.Node{
+			&ast.CurrencyLiteral{Value: "80", Symbol: "$"}, &ast.CurrencyLiteral{Value: "100", Symbol: "$"},
+		}}, ""},
+		{"percent_change without an end", &ast.FunctionCall{Name: "percent_change", Arguments: []ast.Node{
+			&ast.NumberLiteral{Value: "80"},
+		}}, DiagInvalidArgumentCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.funcCall.Range = &ast.Range{}
+			diagnostics := NewChecker().Check([]ast.Node{tt.funcCall})
+
+			if tt.wantCode == "" {
+				if len(diagnostics) != 0 {
+					t.Errorf("expected no diagnostics, got %v", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Code != tt.wantCode || diagnostics[0].Severity != Error {
+				t.Errorf("expected one %s error, got %v", tt.wantCode, diagnostics)
+			}
+		})
+	}
+}
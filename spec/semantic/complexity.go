@@ -0,0 +1,144 @@
+package semantic
+
+import (
+	"fmt"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+)
+
+// ComplexityLimits sets the thresholds above which an expression gets a
+// DiagComplexExpression hint. A zero limit disables that check.
+type ComplexityLimits struct {
+	// MaxDepth is the deepest allowed nesting of operations. Chains of the
+	// same operator (a + b + c) count as one level.
+	MaxDepth int
+	// MaxOperands is the most literals and variable references allowed.
+	MaxOperands int
+}
+
+// DefaultComplexityLimits are the thresholds used by NewChecker.
+var DefaultComplexityLimits = ComplexityLimits{
+	MaxDepth:    4,
+	MaxOperands: 8,
+}
+
+// SetComplexityLimits overrides the complexity thresholds for this checker.
+func (c *Checker) SetComplexityLimits(limits ComplexityLimits) {
+	c.complexity = limits
+}
+
+// checkComplexity hints when a statement's expression is deep or has many
+// operands, suggesting extraction into named intermediate variables.
+func (c *Checker) checkComplexity(node ast.Node) {
+	expr := node
+	switch n := node.(type) {
+	case *ast.Assignment:
+		expr = n.Value
+	case *ast.FrontmatterAssignment:
+		return
+	}
+
+	depth := expressionDepth(expr, "")
+	operands := countOperands(expr)
+
+	tooDeep := c.complexity.MaxDepth > 0 && depth > c.complexity.MaxDepth
+	tooMany := c.complexity.MaxOperands > 0 && operands > c.complexity.MaxOperands
+	if !tooDeep && !tooMany {
+		return
+	}
+
+	c.addDiagnostic(Diagnostic{
+		Severity: Hint,
+		Code:     DiagComplexExpression,
+		Message:  fmt.Sprintf("complex expression (depth %d, %d operands)", depth, operands),
+		Detailed: "This expression is hard to read at a glance. Consider extracting " +
+			"parts into named intermediate variables, e.g. subtotal = price * quantity.",
+		Range: node.GetRange(),
+	})
+}
+
+// expressionDepth returns how many levels of operations are nested in node.
+// Literals and identifiers are depth 0. A binary operator continuing a chain
+// of the same operator (parentOp) does not add a level.
+func expressionDepth(node ast.Node, parentOp string) int {
+	switch n := node.(type) {
+	case *ast.Expression:
+		return expressionDepth(n.Expr, parentOp)
+	case *ast.BinaryOp:
+		level := 1
+		if n.Operator == parentOp {
+			level = 0
+		}
+		return level + max(expressionDepth(n.Left, n.Operator), expressionDepth(n.Right, n.Operator))
+	case *ast.ComparisonOp:
+		return 1 + max(expressionDepth(n.Left, ""), expressionDepth(n.Right, ""))
+	case *ast.UnaryOp:
+		return 1 + expressionDepth(n.Operand, "")
+	case *ast.FunctionCall:
+		return 1 + maxDepth(n.Arguments)
+	case *ast.ListLiteral:
+		return 1 + maxDepth(n.Elements)
+	case *ast.IndexExpr:
+		return 1 + max(expressionDepth(n.Target, ""), expressionDepth(n.Index, ""))
+	case *ast.UnitConversion:
+		return 1 + expressionDepth(n.Quantity, "")
+	case *ast.TimeZoneConversion:
+		return 1 + expressionDepth(n.Time, "")
+	case *ast.NapkinConversion:
+		return 1 + expressionDepth(n.Expression, "")
+	case *ast.PercentageOf:
+		return 1 + max(expressionDepth(n.Percentage, ""), expressionDepth(n.Value, ""))
+	default:
+		return 0
+	}
+}
+
+// maxDepth returns the greatest expressionDepth among nodes.
+func maxDepth(nodes []ast.Node) int {
+	deepest := 0
+	for _, n := range nodes {
+		deepest = max(deepest, expressionDepth(n, ""))
+	}
+	return deepest
+}
+
+// countOperands counts the literals and variable references in node.
+func countOperands(node ast.Node) int {
+	switch n := node.(type) {
+	case nil:
+		return 0
+	case *ast.Expression:
+		return countOperands(n.Expr)
+	case *ast.BinaryOp:
+		return countOperands(n.Left) + countOperands(n.Right)
+	case *ast.ComparisonOp:
+		return countOperands(n.Left) + countOperands(n.Right)
+	case *ast.UnaryOp:
+		return countOperands(n.Operand)
+	case *ast.FunctionCall:
+		return sumOperands(n.Arguments)
+	case *ast.ListLiteral:
+		return sumOperands(n.Elements)
+	case *ast.IndexExpr:
+		return countOperands(n.Target) + countOperands(n.Index)
+	case *ast.UnitConversion:
+		return countOperands(n.Quantity)
+	case *ast.TimeZoneConversion:
+		return countOperands(n.Time)
+	case *ast.NapkinConversion:
+		return countOperands(n.Expression)
+	case *ast.PercentageOf:
+		return countOperands(n.Percentage) + countOperands(n.Value)
+	default:
+		return 1
+	}
+}
+
+// sumOperands counts operands across several nodes.
+func sumOperands(nodes []ast.Node) int {
+	total := 0
+	for _, n := range nodes {
+		total += countOperands(n)
+	}
+	return total
+}
@@ -0,0 +1,67 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestComplexityHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		limits   ComplexityLimits
+		wantHint bool
+	}{
+		{"simple", "x = a + b\n", DefaultComplexityLimits, false},
+		{"long same-operator chain is shallow", "x = a + b + c + d + e\n", DefaultComplexityLimits, false},
+		{"too many operands", "x = a + b + c + d + e + f + g + h + i\n", DefaultComplexityLimits, true},
+		{"too deep", "x = sqrt(((a + b) * c - d) / e)\n", DefaultComplexityLimits, true},
+		{"bare expression", "sqrt(((1 + 2) * 3 - 4) / 5)\n", DefaultComplexityLimits, true},
+		{"custom depth", "x = (a + b) * c\n", ComplexityLimits{MaxDepth: 1}, true},
+		{"disabled", "x = a + b + c + d + e + f + g + h + i\n", ComplexityLimits{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			checker := NewChecker()
+			checker.SetComplexityLimits(tt.limits)
+			for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"} {
+				checker.GetEnvironment().Set(name, nil)
+			}
+
+			var hint *Diagnostic
+			diagnostics := checker.Check(nodes)
+			for i := range diagnostics {
+				if diagnostics[i].Code == DiagComplexExpression {
+					hint = &diagnostics[i]
+				}
+			}
+			if (hint != nil) != tt.wantHint {
+				t.Fatalf("got complex expression hint = %v, want %v (diagnostics: %v)", hint != nil, tt.wantHint, diagnostics)
+			}
+			if hint != nil && hint.Severity != Hint {
+				t.Errorf("Expected Hint severity, got %v", hint.Severity)
+			}
+		})
+	}
+}
+
+func TestExpressionDepthAndOperands(t *testing.T) {
+	nodes, err := parser.Parse("(a + b) * (c - d) / e\n")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	if got := expressionDepth(nodes[0], ""); got != 3 {
+		t.Errorf("expressionDepth = %d, want 3", got)
+	}
+	if got := countOperands(nodes[0]); got != 5 {
+		t.Errorf("countOperands = %d, want 5", got)
+	}
+}
@@ -2,18 +2,31 @@ package semantic
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/fuzzy"
+	"github.com/CalcMark/go-calcmark/spec/types"
 	"golang.org/x/text/currency"
 )
 
+// maxSuggestions is the number of "did you mean" suggestions attached to a diagnostic.
+const maxSuggestions = 3
+
+var (
+	currencyCodesOnce sync.Once
+	currencyCodes     []string
+)
+
 // Known currency symbols that map to ISO codes
 var knownSymbols = map[string]bool{
 	"$": true,
 	"€": true,
 	"£": true,
 	"¥": true,
+	"₿": true,
 }
 
 // ValidateCurrencyCodeWithDiagnostic validates a currency code and returns enhanced diagnostic
@@ -25,6 +38,7 @@ func ValidateCurrencyCodeWithDiagnostic(code string) (valid bool, diag *Diagnost
 
 	// USER REQUIREMENT: Enhanced diagnostic with short + detailed + link to source
 	sourceLink := "https://github.com/CalcMark/go-calcmark/blob/main/spec/semantic/currency.go"
+	suggestions := SuggestCurrencyCodes(code)
 	return false, &Diagnostic{
 		Severity: Warning, // Warning not error - can still use as user-defined unit
 		Code:     DiagInvalidCurrencyCode,
@@ -32,19 +46,25 @@ func ValidateCurrencyCodeWithDiagnostic(code string) (valid bool, diag *Diagnost
 		Detailed: fmt.Sprintf(
 			"%s is not a known currency. You can still use %s as a "+
 				"user-defined unit but you should not expect currency math to "+
-				"work with it. See the currency validation code at: %s",
-			code, code, sourceLink),
-		Link: sourceLink,
+				"work with it.%s See the currency validation code at: %s",
+			code, code, didYouMean(suggestions), sourceLink),
+		Link:        sourceLink,
+		Suggestions: suggestions,
 	}
 }
 
-// ValidateCurrencyCode checks if a currency code is valid (ISO 4217 or common symbol)
+// ValidateCurrencyCode checks if a currency code is valid (ISO 4217, cryptocurrency, or common symbol)
 func ValidateCurrencyCode(code string) bool {
 	// Known symbols are always valid (they map to real currencies)
 	if knownSymbols[code] {
 		return true
 	}
 
+	// Cryptocurrencies are not in ISO 4217 but are registered explicitly
+	if types.IsCryptoCurrency(code) {
+		return true
+	}
+
 	// Must be exactly 3 uppercase letters
 	if len(code) != 3 {
 		return false
@@ -80,17 +100,48 @@ func CreateInvalidCurrencyDiagnostic(code string, node ast.Node) *Diagnostic {
 		rng = node.GetRange()
 	}
 
+	suggestions := SuggestCurrencyCodes(code)
 	return &Diagnostic{
 		Severity: Hint,
 		Code:     DiagInvalidCurrencyCode,
 		Message: fmt.Sprintf(
 			`"%s" is not a valid ISO 4217 currency code. `+
 				`If you meant to use a unit of measurement, this is fine. `+
-				`Otherwise, check the currency code.`,
-			code,
+				`Otherwise, check the currency code.%s`,
+			code, didYouMean(suggestions),
 		),
-		Range: rng,
+		Range:       rng,
+		Suggestions: suggestions,
+		Fixes:       replacementFixes(codeRange(rng, code), suggestions),
+	}
+}
+
+// SuggestCurrencyCodes returns up to three valid ISO 4217 codes closest to code.
+// Returns nil if no valid code is a plausible match.
+//
+// Examples: "USX" -> ["USD"], "EUE" -> ["EUR"]
+func SuggestCurrencyCodes(code string) []string {
+	currencyCodesOnce.Do(func() {
+		// Current tender currencies only; historical codes are not useful fixes
+		for it := currency.Query(); it.Next(); {
+			iso := it.Unit().String()
+			if ValidateCurrencyCode(iso) && !slices.Contains(currencyCodes, iso) {
+				currencyCodes = append(currencyCodes, iso)
+			}
+		}
+		slices.Sort(currencyCodes)
+	})
+
+	return fuzzy.Closest(code, currencyCodes, maxSuggestions)
+}
+
+// didYouMean formats suggestions as a trailing prose sentence for messages.
+// Returns "" when there are no suggestions.
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
 	}
+	return fmt.Sprintf(" Did you mean %s?", strings.Join(suggestions, ", "))
 }
 
 // CreateIncompatibleCurrenciesDiagnostic creates an ERROR diagnostic for incompatible currency operations.
@@ -124,6 +175,8 @@ func NormalizeCurrencySymbol(symbolOrCode string) (string, bool) {
 		return "GBP", true
 	case "¥":
 		return "JPY", true
+	case "₿":
+		return "BTC", true
 	}
 
 	// If it's uppercase 3 letters, it's likely already a code
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/fuzzy"
 	"github.com/CalcMark/go-calcmark/spec/lexer"
+	"github.com/CalcMark/go-calcmark/spec/types"
 )
 
 // checkDateLiteral validates date literals
@@ -88,6 +90,80 @@ func (c *Checker) checkDateLiteral(node *ast.DateLiteral) {
 
 // Helper functions for date validation
 
+// checkWorkdays validates workdays(start, end, calendar?): its argument
+// count and that the optional calendar names a built-in holiday calendar.
+func (c *Checker) checkWorkdays(f *ast.FunctionCall) {
+	if len(f.Arguments) < 2 || len(f.Arguments) > 3 {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  "workdays() requires 2 or 3 arguments (start, end, calendar?)",
+			Range:    f.Range,
+		})
+		return
+	}
+	c.checkExpression(f.Arguments[0])
+	c.checkExpression(f.Arguments[1])
+	if len(f.Arguments) < 3 {
+		return
+	}
+
+	// The calendar is a name, not a variable
+	id, ok := f.Arguments[2].(*ast.Identifier)
+	if !ok {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagUnknownCalendar,
+			Message:  fmt.Sprintf("workdays() calendar must be a name: %s", strings.Join(types.HolidayRegions(), ", ")),
+			Range:    f.Arguments[2].GetRange(),
+		})
+		return
+	}
+	if _, known := types.LookupHolidayRegion(id.Name); known {
+		return
+	}
+	suggestions := fuzzy.Closest(id.Name, types.HolidayRegions(), maxSuggestions)
+	c.addDiagnostic(Diagnostic{
+		Severity:    Error,
+		Code:        DiagUnknownCalendar,
+		Message:     fmt.Sprintf("unknown holiday calendar %q.%s", id.Name, didYouMean(suggestions)),
+		Detailed:    fmt.Sprintf("Built-in calendars: %s", strings.Join(types.HolidayRegions(), ", ")),
+		Range:       id.Range,
+		Suggestions: suggestions,
+		Fixes:       replacementFixes(id.Range, suggestions),
+	})
+}
+
+// checkScheduleQuery validates next(schedule, after?), count(schedule, from, to),
+// and occurrences(schedule, from, to). The schedule may be named in the
+// singular, as in "next payday" for paydays.
+func (c *Checker) checkScheduleQuery(f *ast.FunctionCall) {
+	if len(f.Arguments) == 0 || len(f.Arguments) > 3 || (f.Name == "next") != (len(f.Arguments) < 3) {
+		message := fmt.Sprintf("%s() requires 3 arguments (schedule, from, to)", f.Name)
+		if f.Name == "next" {
+			message = "next() requires 1 or 2 arguments (schedule, after?)"
+		}
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  message,
+			Range:    f.Range,
+		})
+		return
+	}
+	for _, arg := range f.Arguments[1:] {
+		c.checkExpression(arg)
+	}
+	if id, ok := f.Arguments[0].(*ast.Identifier); ok {
+		for _, name := range types.ScheduleNames(id.Name) {
+			if c.env.Has(name) {
+				return
+			}
+		}
+	}
+	c.checkExpression(f.Arguments[0])
+}
+
 // monthNameToNumber converts a month name to its number (1-12).
 // Uses lexer.MonthNames as the single source of truth for month name recognition.
 func monthNameToNumber(name string) int {
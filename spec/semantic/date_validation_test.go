@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/parser"
 	"github.com/CalcMark/go-calcmark/spec/semantic"
 )
 
@@ -262,3 +263,74 @@ func TestDateValidation_ShortMonthNames(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+// TestWorkdaysValidation tests workdays() argument checks: a known holiday
+// calendar passes, and the wrong argument count is an error.
+func TestWorkdaysValidation(t *testing.T) {
+	tests := []struct {
+		input string
+		code  string // "" for no diagnostic
+	}{
+		{"n = workdays(Jan 5, Feb 20)\n", ""},
+		{"n = workdays(Jan 5, Feb 20, UK)\n", ""},
+		{"n = workdays between Jan 5 and Feb 20\n", ""},
+		{"n = workdays(Jan 5, Feb 20, narnia)\n", semantic.DiagUnknownCalendar},
+		{"n = workdays(Jan 5)\n", semantic.DiagInvalidArgumentCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			diagnostics := semantic.NewChecker().Check(nodes)
+			if tt.code == "" {
+				if len(diagnostics) > 0 {
+					t.Errorf("expected no diagnostics, got %v", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Code != tt.code {
+				t.Errorf("expected one %s diagnostic, got %v", tt.code, diagnostics)
+			}
+		})
+	}
+}
+
+// TestScheduleQueryValidation tests that schedule queries find a schedule
+// named in the singular, and check their arguments.
+func TestScheduleQueryValidation(t *testing.T) {
+	paydays := "paydays = every 2nd Friday\n"
+	tests := []struct {
+		input string
+		code  string // "" for no diagnostic
+	}{
+		{paydays + "d = next payday\n", ""},
+		{paydays + "n = count paydays between Mar 1 and Jun 30\n", ""},
+		{paydays + "q = paydays in Q3\n", ""},
+		{"s = every week from start\n", semantic.DiagUndefinedVariable},
+		{"d = next payday\n", semantic.DiagUndefinedVariable},
+		{paydays + "d = next(paydays, Jan 1, Feb 1)\n", semantic.DiagInvalidArgumentCount},
+		{paydays + "n = count(paydays, Jan 1)\n", semantic.DiagInvalidArgumentCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			diagnostics := semantic.NewChecker().Check(nodes)
+			if tt.code == "" {
+				if len(diagnostics) > 0 {
+					t.Errorf("expected no diagnostics, got %v", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Code != tt.code {
+				t.Errorf("expected one %s diagnostic, got %v", tt.code, diagnostics)
+			}
+		})
+	}
+}
@@ -37,6 +37,14 @@ type Diagnostic struct {
 	Detailed string     // Detailed explanation with context and guidance
 	Link     string     // Optional documentation link for more information
 	Range    *ast.Range // Location in source code
+
+	// Suggestions lists likely intended replacements (closest first), e.g.
+	// valid currency codes or unit names, so editors can offer quick fixes.
+	Suggestions []string
+
+	// Fixes are edits that resolve the diagnostic, best first, for editors
+	// to offer as quick fixes. Empty when the source position is unknown.
+	Fixes []Fix
 }
 
 // DiagnosticCode constants for all diagnostic types
@@ -50,6 +58,7 @@ const (
 	DiagInvalidDateOperation = "invalid_date_operation"
 	DiagUnsupportedUnit      = "unsupported_unit"
 	DiagIncompatibleUnits    = "incompatible_units"
+	DiagUnknownUnit          = "unknown_unit"
 
 	// Date diagnostics (USER REQUIREMENT)
 	DiagInvalidDate     = "invalid_date"
@@ -57,13 +66,25 @@ const (
 	DiagInvalidDay      = "invalid_day"
 	DiagInvalidYear     = "invalid_year"
 	DiagInvalidLeapYear = "invalid_leap_year"
+	DiagUnknownCalendar = "unknown_calendar"
 
 	// Variable diagnostics
 	DiagUndefinedVariable = "undefined_variable"
 
 	// Arithmetic diagnostics
 	DiagDivisionByZero = "division_by_zero"
+	DiagPrecisionLoss  = "precision_loss"
+	DiagOverflow       = "overflow"
 
 	// Data size unit hints
 	DiagMixedBaseUnits = "mixed_base_units"
+
+	// Function diagnostics
+	DiagUndefinedFunction    = "undefined_function"
+	DiagInvalidArgumentCount = "invalid_argument_count"
+	DiagMixedUnits           = "mixed_units"
+
+	// Readability hints
+	DiagComplexExpression = "complex_expression"
+	DiagNamingConvention  = "naming_convention"
 )
@@ -40,6 +40,8 @@
 //   - DiagInvalidCurrency: Unknown currency code
 //   - DiagTypeMismatch: Type error in operation
 //   - DiagDivisionByZero: Division or modulus by zero
+//   - DiagPrecisionLoss: Result silently rounded (e.g., "1 / 3", "$100 / 3")
+//   - DiagOverflow: Result too large for floating point (e.g., "2 ^ 10000")
 //
 // # Severity Levels
 //
@@ -47,6 +49,16 @@
 //   - Warning: Valid syntax but may cause runtime issues
 //   - Hint: Style suggestions for improvement
 //
+// # Quick Fixes
+//
+// Where the correction is clear, a diagnostic carries Fixes: text edits an
+// editor can offer and apply (Fix.Apply) without further input, such as
+// changing a misspelled currency code or unit to a suggestion, renaming an
+// undefined variable to a similar defined one, defining it on the line
+// above, or converting mixed units with "in". Edit positions are lines and
+// columns of the checked source, so diagnostics without a source position
+// have no fixes.
+//
 // # Unit Validation
 //
 // The semantic checker validates unit compatibility:
@@ -0,0 +1,164 @@
+package semantic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+)
+
+// Fix is a machine-applicable correction attached to a diagnostic, such as
+// replacing a misspelled currency code. Editors offer fixes as quick fixes
+// (code actions) and apply them with Apply.
+type Fix struct {
+	Title string     // Short description for a menu, e.g. `Change to "USD"`
+	Edits []TextEdit // Non-overlapping edits, applied together
+}
+
+// TextEdit replaces the text in Range with NewText. A range whose start and
+// end are equal inserts NewText there. Positions are 1-indexed lines and
+// columns (in runes) of the checked source, as in the AST; the end is
+// exclusive.
+type TextEdit struct {
+	Range   ast.Range
+	NewText string
+}
+
+// replaceFix returns a fix replacing the text at r with text.
+func replaceFix(title string, r *ast.Range, text string) Fix {
+	return Fix{Title: title, Edits: []TextEdit{{Range: *r, NewText: text}}}
+}
+
+// insertFix returns a fix inserting text at p.
+func insertFix(title string, p ast.Position, text string) Fix {
+	return Fix{Title: title, Edits: []TextEdit{{Range: ast.Range{Start: p, End: p}, NewText: text}}}
+}
+
+// hasPosition reports whether r records a place in the source. The parser
+// leaves some ranges zero.
+func hasPosition(r *ast.Range) bool {
+	return r != nil && r.Start.Line > 0 && r.Start.Column > 0
+}
+
+// undefinedVariableFixes offers renaming a reference to each similarly
+// named variable, then defining the variable on the line above.
+func undefinedVariableFixes(id *ast.Identifier, similar []string) []Fix {
+	if !hasPosition(id.Range) {
+		return nil
+	}
+	fixes := replacementFixes(id.Range, similar)
+	above := ast.Position{Line: id.Range.Start.Line, Column: 1}
+	return append(fixes, insertFix(fmt.Sprintf("Define %q above", id.Name), above, id.Name+" = 0\n"))
+}
+
+// replacementFixes offers replacing the text at r with each suggestion.
+func replacementFixes(r *ast.Range, suggestions []string) []Fix {
+	if !hasPosition(r) {
+		return nil
+	}
+	var fixes []Fix
+	for _, s := range suggestions {
+		fixes = append(fixes, replaceFix(fmt.Sprintf("Change to %q", s), r, s))
+	}
+	return fixes
+}
+
+// codeRange returns the range of the currency code ending a currency
+// literal at r ("100 USX"), or nil if r is unknown.
+func codeRange(r *ast.Range, code string) *ast.Range {
+	if !hasPosition(r) {
+		return nil
+	}
+	start := r.End
+	start.Column -= utf8.RuneCountInString(code)
+	return &ast.Range{Start: start, End: r.End}
+}
+
+// convertUnitsFixes offers converting every quantity literal among values
+// to unit with "in", when all of them can be.
+func convertUnitsFixes(values []ast.Node, unit string) []Fix {
+	kind := GetQuantityType(unit)
+	if kind == QuantityUnknown {
+		return nil
+	}
+	fix := Fix{Title: "Convert to " + unit}
+	for _, v := range values {
+		other := getNodeUnit(v)
+		if other == "" || other == unit {
+			continue
+		}
+		q, ok := v.(*ast.QuantityLiteral)
+		if !ok || !hasPosition(q.Range) || GetQuantityType(other) != kind {
+			return nil
+		}
+		fix.Edits = append(fix.Edits, TextEdit{Range: ast.Range{Start: q.Range.End, End: q.Range.End}, NewText: " in " + unit})
+	}
+	if len(fix.Edits) == 0 {
+		return nil
+	}
+	return []Fix{fix}
+}
+
+// Apply returns source with the fix's edits made. It fails if an edit is
+// outside source or edits overlap.
+func (f Fix) Apply(source string) (string, error) {
+	type span struct {
+		start, end int
+		text       string
+	}
+	spans := make([]span, 0, len(f.Edits))
+	for _, e := range f.Edits {
+		start, ok := offset(source, e.Range.Start)
+		if !ok {
+			return "", fmt.Errorf("edit start %s is outside the source", e.Range.Start)
+		}
+		end, ok := offset(source, e.Range.End)
+		if !ok || end < start {
+			return "", fmt.Errorf("edit end %s is outside the source", e.Range.End)
+		}
+		spans = append(spans, span{start, end, e.NewText})
+	}
+
+	slices.SortFunc(spans, func(a, b span) int { return a.start - b.start })
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.start < pos {
+			return "", fmt.Errorf("overlapping edits in fix %q", f.Title)
+		}
+		b.WriteString(source[pos:s.start])
+		b.WriteString(s.text)
+		pos = s.end
+	}
+	b.WriteString(source[pos:])
+	return b.String(), nil
+}
+
+// offset returns the byte offset of p in source. A column one past the end
+// of a line is its end.
+func offset(source string, p ast.Position) (int, bool) {
+	if p.Line < 1 || p.Column < 1 {
+		return 0, false
+	}
+	start := 0
+	for line := 1; line < p.Line; line++ {
+		i := strings.IndexByte(source[start:], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		start += i + 1
+	}
+	column := 1
+	for i, r := range source[start:] {
+		if column == p.Column {
+			return start + i, true
+		}
+		if r == '\n' {
+			return 0, false
+		}
+		column++
+	}
+	return len(source), column == p.Column
+}
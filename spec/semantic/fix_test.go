@@ -0,0 +1,157 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestDiagnosticFixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		code   string
+		titles []string
+		fixed  string // Source after applying the first fix
+	}{
+		{
+			name:   "misspelled variable",
+			input:  "a = 1\nb = totl + 1\n",
+			code:   DiagUndefinedVariable,
+			titles: []string{`Change to "total"`, `Define "totl" above`},
+			fixed:  "a = 1\nb = total + 1\n",
+		},
+		{
+			name:   "undefined variable",
+			input:  "b = 2 * rate\n",
+			code:   DiagUndefinedVariable,
+			titles: []string{`Define "rate" above`},
+			fixed:  "rate = 0\nb = 2 * rate\n",
+		},
+		{
+			name:   "misspelled function",
+			input:  "x = medain(1, 2)\n",
+			code:   DiagUndefinedFunction,
+			titles: []string{`Change to "median"`},
+			fixed:  "x = median(1, 2)\n",
+		},
+		{
+			name:   "currency code",
+			input:  "price = 100 EUE\n",
+			code:   DiagInvalidCurrencyCode,
+			titles: []string{`Change to "EUR"`},
+			fixed:  "price = 100 EUR\n",
+		},
+		{
+			name:   "unknown unit",
+			input:  "d = 5 meters in feetz\n",
+			code:   DiagUnknownUnit,
+			titles: []string{`Change to "feet"`},
+			fixed:  "d = 5 meters in feet\n",
+		},
+		{
+			name:   "holiday calendar",
+			input:  "n = workdays(Jan 5, Feb 20, usa)\n",
+			code:   DiagUnknownCalendar,
+			titles: []string{`Change to "US"`},
+			fixed:  "n = workdays(Jan 5, Feb 20, US)\n",
+		},
+		{
+			name:   "mixed units",
+			input:  "total = sum(5 meters, 3 feet, 2 meters, 10 cm)\n",
+			code:   DiagMixedUnits,
+			titles: []string{"Convert to meters"},
+			fixed:  "total = sum(5 meters, 3 feet in meters, 2 meters, 10 cm in meters)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			checker := NewChecker()
+			checker.GetEnvironment().Set("total", types.NewNumber(decimal.NewFromInt(1)))
+
+			var diag *Diagnostic
+			diagnostics := checker.Check(nodes)
+			for i := range diagnostics {
+				if diagnostics[i].Code == tt.code {
+					diag = &diagnostics[i]
+				}
+			}
+			if diag == nil {
+				t.Fatalf("expected a %s diagnostic, got %v", tt.code, diagnostics)
+			}
+
+			var titles []string
+			for _, f := range diag.Fixes {
+				titles = append(titles, f.Title)
+			}
+			if len(titles) < len(tt.titles) {
+				t.Fatalf("fixes = %q, want at least %q", titles, tt.titles)
+			}
+			for i, want := range tt.titles {
+				if titles[i] != want {
+					t.Errorf("fix %d = %q, want %q", i, titles[i], want)
+				}
+			}
+
+			fixed, err := diag.Fixes[0].Apply(tt.input)
+			if err != nil {
+				t.Fatalf("Apply error: %v", err)
+			}
+			if fixed != tt.fixed {
+				t.Errorf("fixed source = %q, want %q", fixed, tt.fixed)
+			}
+			if _, err := parser.Parse(fixed); err != nil {
+				t.Errorf("fixed source doesn't parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestFixWithoutPosition(t *testing.T) {
+	nodes := []ast.Node{&ast.Identifier{Name: "missing"}}
+	diagnostics := NewChecker().Check(nodes)
+	if len(diagnostics) != 1 || diagnostics[0].Fixes != nil {
+		t.Errorf("expected a diagnostic without fixes, got %+v", diagnostics)
+	}
+}
+
+func TestFixApply(t *testing.T) {
+	edit := func(l1, c1, l2, c2 int, text string) TextEdit {
+		return TextEdit{Range: ast.Range{Start: ast.Position{Line: l1, Column: c1}, End: ast.Position{Line: l2, Column: c2}}, NewText: text}
+	}
+	source := "€5 → x\nyz\n"
+
+	tests := []struct {
+		name    string
+		edits   []TextEdit
+		want    string
+		wantErr bool
+	}{
+		{"replace after multibyte runes", []TextEdit{edit(1, 6, 1, 7, "y")}, "€5 → y\nyz\n", false},
+		{"insert at line end", []TextEdit{edit(2, 3, 2, 3, "!")}, "€5 → x\nyz!\n", false},
+		{"span lines", []TextEdit{edit(1, 6, 2, 2, "")}, "€5 → z\n", false},
+		{"several edits", []TextEdit{edit(2, 1, 2, 1, "<"), edit(1, 1, 1, 2, "$")}, "$5 → x\n<yz\n", false},
+		{"overlapping", []TextEdit{edit(1, 1, 1, 3, ""), edit(1, 2, 1, 4, "")}, "", true},
+		{"past line end", []TextEdit{edit(2, 5, 2, 5, "")}, "", true},
+		{"past last line", []TextEdit{edit(4, 1, 4, 1, "")}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fix{Title: tt.name, Edits: tt.edits}.Apply(source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Apply = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package semantic
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/fuzzy"
+)
+
+// builtinFunctions are the functions the language defines, sorted.
+// Custom functions can't take their names.
+var builtinFunctions = []string{
+	"accumulate", "adjust", "amortize", "average", "avg", "cagr",
+	"capacity", "compound", "compress", "convert_rate", "count",
+	"decrease", "downtime", "fv", "grow", "increase", "irr", "jsonpath",
+	"lookup", "max", "median", "min", "next", "normal", "npv",
+	"occurrences", "percent_change", "pmt", "read", "rtt", "seek", "sqrt",
+	"stdev", "sum", "throughput", "transfer_time", "uniform", "workdays",
+}
+
+// IsBuiltinFunction reports whether name is a function the language defines.
+func IsBuiltinFunction(name string) bool {
+	_, found := slices.BinarySearch(builtinFunctions, name)
+	return found
+}
+
+// FunctionSignature describes the parameters of a custom function, one
+// an embedding application adds to the language, so calls to it are
+// checked before evaluation like calls to built-in functions.
+type FunctionSignature struct {
+	Params   []string // Parameter names, e.g. weight and zone
+	Variadic bool     // The last parameter repeats; at least one is required
+}
+
+// Usage returns how a call to name looks, e.g. "shipping_cost(weight, zone)"
+// or "tiered(amount, rates...)".
+func (s FunctionSignature) Usage(name string) string {
+	params := strings.Join(s.Params, ", ")
+	if s.Variadic {
+		params += "..."
+	}
+	return name + "(" + params + ")"
+}
+
+// accepts reports whether a call may pass n arguments.
+func (s FunctionSignature) accepts(n int) bool {
+	if s.Variadic {
+		return n >= len(s.Params)
+	}
+	return n == len(s.Params)
+}
+
+// SetFunctions declares the custom functions documents may call, keyed by
+// name. Calls to them are checked against their signatures, and calls to
+// functions that are neither custom nor built-in are errors.
+func (c *Checker) SetFunctions(functions map[string]FunctionSignature) {
+	c.functions = functions
+}
+
+// checkCallee validates that a call to a function without special
+// checks names a known function with as many arguments as it takes.
+func (c *Checker) checkCallee(f *ast.FunctionCall) {
+	if IsBuiltinFunction(f.Name) {
+		return
+	}
+	if sig, ok := c.functions[f.Name]; ok {
+		if !sig.accepts(len(f.Arguments)) {
+			c.addDiagnostic(Diagnostic{
+				Severity: Error,
+				Code:     DiagInvalidArgumentCount,
+				Message:  fmt.Sprintf("%s() requires %s, as in %s", f.Name, argumentCount(sig), sig.Usage(f.Name)),
+				Range:    f.Range,
+			})
+		}
+		return
+	}
+
+	known := slices.Concat(builtinFunctions, slices.Sorted(maps.Keys(c.functions)))
+	suggestions := fuzzy.Closest(f.Name, known, maxSuggestions)
+	c.addDiagnostic(Diagnostic{
+		Severity:    Error,
+		Code:        DiagUndefinedFunction,
+		Message:     `Undefined function "` + f.Name + `"`,
+		Range:       f.Range,
+		Suggestions: suggestions,
+		Fixes:       replacementFixes(functionNameRange(f), suggestions),
+	})
+}
+
+// argumentCount describes how many arguments sig takes.
+func argumentCount(sig FunctionSignature) string {
+	n := len(sig.Params)
+	switch {
+	case sig.Variadic:
+		return fmt.Sprintf("at least %d argument(s)", n)
+	case n == 1:
+		return "exactly 1 argument"
+	default:
+		return fmt.Sprintf("exactly %d arguments", n)
+	}
+}
+
+// functionNameRange returns the range of the name starting a call at
+// f's range, or nil if the range is unknown.
+func functionNameRange(f *ast.FunctionCall) *ast.Range {
+	if !hasPosition(f.Range) {
+		return nil
+	}
+	start := f.Range.Start
+	end := ast.Position{Line: start.Line, Column: start.Column + utf8.RuneCountInString(f.Name)}
+	return &ast.Range{Start: start, End: end}
+}
+
+// dataFunctions are the built-in functions that read reference data from
+// files, by the parameters they take.
+var dataFunctions = map[string]FunctionSignature{
+	"lookup":   {Params: []string{"file", "key_column", "key", "value_column"}},
+	"jsonpath": {Params: []string{"file", "path"}},
+}
+
+// checkDataFunction validates a call to lookup() or jsonpath(): the file
+// and JSON path are quoted text, columns are quoted text or names, and
+// a lookup key is quoted text or an expression.
+func (c *Checker) checkDataFunction(f *ast.FunctionCall) {
+	sig := dataFunctions[f.Name]
+	if !sig.accepts(len(f.Arguments)) {
+		c.addDiagnostic(Diagnostic{
+			Severity: Error,
+			Code:     DiagInvalidArgumentCount,
+			Message:  fmt.Sprintf("%s() requires %s, as in %s", f.Name, argumentCount(sig), sig.Usage(f.Name)),
+			Range:    f.Range,
+		})
+		return
+	}
+
+	for i, arg := range f.Arguments {
+		param := sig.Params[i]
+		_, quoted := arg.(*ast.StringLiteral)
+		switch param {
+		case "file", "path":
+			if !quoted {
+				c.addDiagnostic(Diagnostic{
+					Severity: Error,
+					Code:     DiagTypeMismatch,
+					Message:  fmt.Sprintf("%s() %s must be quoted text, as in %s", f.Name, param, dataExample(param)),
+					Range:    arg.GetRange(),
+				})
+			}
+		case "key_column", "value_column":
+			if _, name := arg.(*ast.Identifier); !quoted && !name {
+				c.addDiagnostic(Diagnostic{
+					Severity: Error,
+					Code:     DiagTypeMismatch,
+					Message:  fmt.Sprintf("%s() %s must be a column name", f.Name, param),
+					Range:    arg.GetRange(),
+				})
+			}
+		default:
+			if !quoted {
+				c.checkExpression(arg)
+			}
+		}
+	}
+}
+
+// dataExample shows a data function's quoted argument.
+func dataExample(param string) string {
+	if param == "path" {
+		return `"$.rates.usd"`
+	}
+	return `"prices.csv"`
+}
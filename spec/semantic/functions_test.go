@@ -0,0 +1,71 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestCustomFunctions(t *testing.T) {
+	functions := map[string]FunctionSignature{
+		"tiered": {Params: []string{"amount", "rates"}, Variadic: true},
+	}
+	tests := []struct {
+		input string
+		code  string // Expected diagnostic, empty for none
+	}{
+		{"x = tiered(100, 1, 2, 3)\n", ""},
+		{"x = tiered(100, 1)\n", ""},
+		{"x = tiered(100)\n", DiagInvalidArgumentCount},
+		{"x = tierd(100, 1)\n", DiagUndefinedFunction},
+		{"x = sum(1, 2)\n", ""},
+	}
+	for _, tt := range tests {
+		nodes, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.input, err)
+		}
+		checker := NewChecker()
+		checker.SetFunctions(functions)
+		diags := checker.Check(nodes)
+		switch {
+		case tt.code == "" && len(diags) > 0:
+			t.Errorf("%q: unexpected %v", tt.input, diags)
+		case tt.code != "" && (len(diags) != 1 || diags[0].Code != tt.code):
+			t.Errorf("%q: diagnostics = %v, want %s", tt.input, diags, tt.code)
+		}
+	}
+
+	if got := functions["tiered"].Usage("tiered"); got != "tiered(amount, rates...)" {
+		t.Errorf("Usage = %q", got)
+	}
+}
+
+func TestDataFunctions(t *testing.T) {
+	tests := []struct {
+		input string
+		code  string // Expected diagnostic, empty for none
+	}{
+		{"x = lookup(\"prices.csv\", sku, \"A-100\", price)\n", ""},
+		{"x = lookup(\"prices.csv\", \"sku\", 7, \"price\")\n", ""},
+		{"x = jsonpath(\"rates.json\", \"$.usd.eur\") * 2\n", ""},
+		{"x = lookup(\"prices.csv\", sku, \"A-100\")\n", DiagInvalidArgumentCount},
+		{"x = lookup(prices, sku, \"A-100\", price)\n", DiagTypeMismatch},
+		{"x = lookup(\"prices.csv\", sku, missing, price)\n", DiagUndefinedVariable},
+		{"x = jsonpath(\"rates.json\", usd)\n", DiagTypeMismatch},
+		{"x = sum(\"prices.csv\")\n", DiagTypeMismatch},
+	}
+	for _, tt := range tests {
+		nodes, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.input, err)
+		}
+		diags := NewChecker().Check(nodes)
+		switch {
+		case tt.code == "" && len(diags) > 0:
+			t.Errorf("%q: unexpected %v", tt.input, diags)
+		case tt.code != "" && (len(diags) != 1 || diags[0].Code != tt.code):
+			t.Errorf("%q: diagnostics = %v, want %s", tt.input, diags, tt.code)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package semantic
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/types"
+)
+
+// NamingRules are optional variable naming conventions, reported as
+// DiagNamingConvention hints. The zero value checks nothing.
+type NamingRules struct {
+	// SnakeCase requires lowercase words separated by underscores (monthly_cost).
+	SnakeCase bool
+	// NoSingleLetter rejects one-character names other than AllowedSingleLetter.
+	NoSingleLetter bool
+	// AllowedSingleLetter lists the one-character names NoSingleLetter permits.
+	AllowedSingleLetter []string
+	// CurrencySuffix requires currency-valued variables to end in their
+	// lowercase ISO code (price_usd, rent_eur).
+	CurrencySuffix bool
+}
+
+// DefaultSingleLetterNames are the conventional loop and axis names allowed
+// when single-letter names are otherwise rejected.
+var DefaultSingleLetterNames = []string{"i", "x"}
+
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// SetNamingRules enables naming convention hints for this checker.
+func (c *Checker) SetNamingRules(rules NamingRules) {
+	c.naming = rules
+}
+
+// checkNaming hints when an assigned variable's name breaks the configured rules.
+func (c *Checker) checkNaming(node ast.Node) {
+	a, ok := node.(*ast.Assignment)
+	if !ok {
+		return
+	}
+	name := a.Name
+
+	if c.naming.SnakeCase && !snakeCasePattern.MatchString(name) {
+		c.addNamingHint(a, fmt.Sprintf("%q is not snake_case", name),
+			fmt.Sprintf("Use lowercase words separated by underscores, e.g. %s.", toSnakeCase(name)))
+	}
+
+	if c.naming.NoSingleLetter && len([]rune(name)) == 1 && !slices.Contains(c.naming.AllowedSingleLetter, name) {
+		c.addNamingHint(a, fmt.Sprintf("single-letter name %q", name),
+			"Use a descriptive name so the calculation reads on its own.")
+	}
+
+	if c.naming.CurrencySuffix {
+		if code := c.currencyOf(name); code != "" {
+			want := "_" + strings.ToLower(code)
+			if !strings.HasSuffix(strings.ToLower(name), want) {
+				c.addNamingHint(a, fmt.Sprintf("currency variable %q should end in %s", name, want),
+					fmt.Sprintf("Suffix currency values with their code, e.g. %s%s.", name, want))
+			}
+		}
+	}
+}
+
+// addNamingHint records a naming convention hint for an assignment.
+func (c *Checker) addNamingHint(a *ast.Assignment, message, detailed string) {
+	c.addDiagnostic(Diagnostic{
+		Severity: Hint,
+		Code:     DiagNamingConvention,
+		Message:  message,
+		Detailed: detailed,
+		Range:    a.Range,
+	})
+}
+
+// currencyOf returns the ISO code of the currency an assigned variable
+// holds, from the type the checker inferred for it, or "" if it isn't
+// known to hold money.
+func (c *Checker) currencyOf(name string) string {
+	if info, ok := c.inferred[name]; ok && info.Kind == TypeCurrency {
+		return info.Type.(*types.Currency).Code
+	}
+	return ""
+}
+
+// toSnakeCase suggests a snake_case spelling of name (monthlyCost → monthly_cost).
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == ' ':
+			b.WriteRune('_')
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && runes[i-1] != '_' && !(runes[i-1] >= 'A' && runes[i-1] <= 'Z') {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r + ('a' - 'A'))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
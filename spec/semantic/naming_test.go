@@ -0,0 +1,82 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestNamingRules(t *testing.T) {
+	strict := NamingRules{
+		SnakeCase:           true,
+		NoSingleLetter:      true,
+		AllowedSingleLetter: DefaultSingleLetterNames,
+		CurrencySuffix:      true,
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		rules     NamingRules
+		wantHints int
+	}{
+		{"rules off by default", "monthlyCost = $100\n", NamingRules{}, 0},
+		{"snake case ok", "monthly_cost = 100\n", strict, 0},
+		{"camel case", "monthlyCost = 100\n", strict, 1},
+		{"allowed single letter", "x = 5\n", strict, 0},
+		{"single letter", "n = 5\n", strict, 1},
+		{"currency suffix ok", "rent_usd = $1500\n", strict, 0},
+		{"currency suffix missing", "rent = $1500\n", strict, 1},
+		{"currency suffix wrong code", "rent_usd = 1500 EUR\n", strict, 1},
+		{"currency inferred through expression", "total = $10 * 3 + $5\n", strict, 1},
+		{"currency from defined variable", "total = price * 2\n", strict, 1},
+		{"non-currency value", "count = 12\n", strict, 0},
+		{"ratio of currencies", "ratio = $10 / $5\n", strict, 0},
+		{"currency from earlier assignment", "rent_usd = $1500\nmonthly = rent_usd / 12\n", strict, 1},
+		{"currency converted", "price_eur = price in EUR\n", strict, 0},
+		{"percentage of currency", "tip = 15% of price\n", strict, 1},
+		{"function result not inferred", "months = round(price)\n", strict, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			checker := NewChecker()
+			checker.SetNamingRules(tt.rules)
+			checker.GetEnvironment().Set("price", types.NewCurrency(decimal.NewFromInt(10), "$"))
+
+			hints := 0
+			for _, d := range checker.Check(nodes) {
+				if d.Code == DiagNamingConvention {
+					if d.Severity != Hint {
+						t.Errorf("Expected Hint severity, got %v", d.Severity)
+					}
+					hints++
+				}
+			}
+			if hints != tt.wantHints {
+				t.Errorf("got %d naming hints, want %d", hints, tt.wantHints)
+			}
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"monthlyCost": "monthly_cost",
+		"MonthlyCost": "monthly_cost",
+		"taxRATE":     "tax_rate",
+		"already_ok":  "already_ok",
+	}
+	for input, want := range tests {
+		if got := toSnakeCase(input); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package semantic
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+// overflowMagnitude is the largest power of ten a result may reach before
+// DiagOverflow: the limit of float64, which spreadsheets, JSON consumers,
+// and JavaScript use for numbers.
+const overflowMagnitude = 308
+
+// constant is the value of an operand known without evaluating the
+// statement: a literal, or a variable the environment has a value for.
+type constant struct {
+	value   decimal.Decimal
+	code    string // ISO currency code, "" for plain numbers
+	literal bool   // Written out in full, with no variables
+}
+
+// String formats k for messages, e.g. "100 USD".
+func (k constant) String() string {
+	if k.code == "" {
+		return k.value.String()
+	}
+	return k.value.String() + " " + k.code
+}
+
+// checkPrecision warns when a binary operation on known values will be
+// silently rounded or overflow: divisions with a repeating result, currency
+// divisions finer than the currency's decimal places, and huge powers.
+func (c *Checker) checkPrecision(b *ast.BinaryOp) {
+	left, ok := c.constantOf(b.Left)
+	if !ok {
+		return
+	}
+	right, ok := c.constantOf(b.Right)
+	if !ok {
+		return
+	}
+
+	switch b.Operator {
+	case "/":
+		if right.value.IsZero() {
+			return // Reported as DiagDivisionByZero
+		}
+		c.checkDivision(b, left, right)
+	case "^":
+		c.checkPower(b, left, right)
+	}
+}
+
+// checkDivision warns about a quotient that can't be held exactly. Plain
+// numbers are only checked when both operands are literals, since dividing
+// variables (annual / 12) is usually meant to round.
+func (c *Checker) checkDivision(b *ast.BinaryOp, left, right constant) {
+	quotient := new(big.Rat).Quo(left.value.Rat(), right.value.Rat())
+	approx := approximate(quotient)
+
+	if left.code != "" && (right.code == "" || right.code == left.code) {
+		places := types.CurrencyDecimals(left.code)
+		scaled := new(big.Rat).Mul(quotient, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)))
+		if scaled.IsInt() {
+			return
+		}
+		shown := decimal.NewFromBigRat(quotient, places+1).Round(places).StringFixed(places)
+		c.addDiagnostic(Diagnostic{
+			Severity: Warning,
+			Code:     DiagPrecisionLoss,
+			Message: fmt.Sprintf("%s / %s ≈ %s %s is finer than %s's %d decimal places; shown as %s",
+				left, right, approx, left.code, left.code, places, shown),
+			Detailed: "The result is kept in full but displayed rounded, so displayed amounts may not " +
+				"add up to the total. Round explicitly, or give the remainder to one share.",
+			Range: b.Range,
+		})
+		return
+	}
+
+	if !left.literal || !right.literal || left.code != right.code || !repeats(quotient) {
+		return
+	}
+	c.addDiagnostic(Diagnostic{
+		Severity: Warning,
+		Code:     DiagPrecisionLoss,
+		Message: fmt.Sprintf("%s / %s ≈ %s repeats forever and is rounded to %d decimal places",
+			left.value, right.value, approx, decimal.DivisionPrecision),
+		Detailed: "Decimal numbers can't hold a repeating fraction exactly, so the result is rounded. " +
+			"Multiplying it back (1 / 3 * 3) may not give the original value.",
+		Range: b.Range,
+	})
+}
+
+// checkPower warns about a power whose magnitude overflows, or underflows
+// past the smallest floating-point number.
+func (c *Checker) checkPower(b *ast.BinaryOp, base, exponent constant) {
+	magnitude := powerMagnitude(base.value, exponent.value)
+	switch {
+	case magnitude > overflowMagnitude:
+		c.addDiagnostic(Diagnostic{
+			Severity: Warning,
+			Code:     DiagOverflow,
+			Message: fmt.Sprintf("%s ^ %s ≈ 10^%.0f overflows numbers beyond 10^%d",
+				base.value, exponent.value, magnitude, overflowMagnitude),
+			Detailed: "CalcMark computes the exact value, but it is slow to compute and becomes " +
+				"infinity in spreadsheets, JSON, and the web editor.",
+			Range: b.Range,
+		})
+	case magnitude < -overflowMagnitude:
+		c.addDiagnostic(Diagnostic{
+			Severity: Warning,
+			Code:     DiagPrecisionLoss,
+			Message: fmt.Sprintf("%s ^ %s ≈ 10^%.0f is rounded to 0 beyond %d decimal places",
+				base.value, exponent.value, magnitude, overflowMagnitude),
+			Detailed: "The result is too small to show and becomes 0 in spreadsheets, JSON, and the web editor.",
+			Range:    b.Range,
+		})
+	}
+}
+
+// powerMagnitude returns the power of ten base^exponent is about, or 0 when
+// the result stays at or near 1 whatever the exponent.
+func powerMagnitude(base, exponent decimal.Decimal) float64 {
+	b := base.Abs().InexactFloat64()
+	if b == 0 || b == 1 || math.IsInf(b, 0) {
+		return 0
+	}
+	return exponent.InexactFloat64() * math.Log10(b)
+}
+
+// constantOf returns the value of node when it is a number or currency
+// literal, a variable holding one, or sums and products of these.
+func (c *Checker) constantOf(node ast.Node) (constant, bool) {
+	switch n := node.(type) {
+	case *ast.NumberLiteral:
+		value, ok := literalValue(n.Value)
+		return constant{value: value, literal: true}, ok
+	case *ast.CurrencyLiteral:
+		value, ok := literalValue(n.Value)
+		return constant{value: value, code: types.NormalizeCurrencyCode(n.Symbol), literal: true}, ok
+	case *ast.Identifier:
+		switch v, _ := c.env.Get(n.Name); v := v.(type) {
+		case *types.Number:
+			return constant{value: v.Value}, true
+		case *types.Currency:
+			return constant{value: v.Value, code: v.Code}, true
+		}
+	case *ast.Expression:
+		return c.constantOf(n.Expr)
+	case *ast.UnaryOp:
+		operand, ok := c.constantOf(n.Operand)
+		if n.Operator == "-" {
+			operand.value = operand.value.Neg()
+		}
+		return operand, ok && (n.Operator == "-" || n.Operator == "+")
+	case *ast.BinaryOp:
+		left, ok := c.constantOf(n.Left)
+		if !ok {
+			return constant{}, false
+		}
+		right, ok := c.constantOf(n.Right)
+		if !ok {
+			return constant{}, false
+		}
+		result := constant{literal: left.literal && right.literal}
+		switch {
+		case (n.Operator == "+" || n.Operator == "-") && left.code == right.code:
+			result.code = left.code
+			result.value = left.value.Add(right.value)
+			if n.Operator == "-" {
+				result.value = left.value.Sub(right.value)
+			}
+		case n.Operator == "*" && (left.code == "" || right.code == ""):
+			result.code = left.code + right.code
+			result.value = left.value.Mul(right.value)
+		default:
+			return constant{}, false
+		}
+		return result, true
+	}
+	return constant{}, false
+}
+
+// literalValue parses a number literal's normalized value, expanding
+// multiplier suffixes (1.5k) and percentages (20%) as the interpreter does.
+func literalValue(s string) (decimal.Decimal, bool) {
+	multiplier := int32(0)
+	if !strings.ContainsAny(s, "eE") && s != "" {
+		switch s[len(s)-1] {
+		case '%':
+			multiplier = -2
+		case 'k', 'K':
+			multiplier = 3
+		case 'M':
+			multiplier = 6
+		case 'B':
+			multiplier = 9
+		case 'T':
+			multiplier = 12
+		}
+		if multiplier != 0 {
+			s = s[:len(s)-1]
+		}
+	}
+	value, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return value.Shift(multiplier), true
+}
+
+// repeats reports whether q has no finite decimal expansion, which is when
+// its reduced denominator has a prime factor other than 2 and 5.
+func repeats(q *big.Rat) bool {
+	den := new(big.Int).Set(q.Denom())
+	rem := new(big.Int)
+	for _, p := range []*big.Int{big.NewInt(2), big.NewInt(5)} {
+		for {
+			quo, r := new(big.Int).QuoRem(den, p, rem)
+			if r.Sign() != 0 {
+				break
+			}
+			den = quo
+		}
+	}
+	return den.Cmp(big.NewInt(1)) != 0
+}
+
+// approximate formats q to six significant digits, for messages.
+func approximate(q *big.Rat) string {
+	f, _ := q.Float64()
+	return fmt.Sprintf("%.6g", f)
+}
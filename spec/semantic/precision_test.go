@@ -0,0 +1,73 @@
+package semantic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/parser"
+	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestPrecisionWarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		code    string // "" for no warning
+		message string // Substring of the message
+	}{
+		{"repeating division", "x = 1 / 3\n", DiagPrecisionLoss, "1 / 3 ≈ 0.333333 repeats"},
+		{"multiplier suffix", "x = 1k / 7\n", DiagPrecisionLoss, "1000 / 7 ≈ 142.857"},
+		{"terminating division", "x = 10 / 4\n", "", ""},
+		{"fraction of tenths", "x = 1 / 8\n", "", ""},
+		{"folded operands", "x = (2 + 4) / (1.5 * 2)\n", "", ""},
+		{"folded repeating", "x = (2 + 4) / (3.5 * 2)\n", DiagPrecisionLoss, "6 / 7"},
+		{"variable divisor", "x = 1 / n\n", "", ""},
+		{"currency split", "x = $100 / 3\n", DiagPrecisionLoss, "100 USD / 3 ≈ 33.3333 USD is finer than USD's 2 decimal places; shown as 33.33"},
+		{"currency in cents", "x = $1 / 8\n", DiagPrecisionLoss, "shown as 0.13"},
+		{"currency exact", "x = $100 / 4\n", "", ""},
+		{"currency variable", "x = total / 3\n", DiagPrecisionLoss, "90.01 USD / 3"},
+		{"currency by currency", "x = €100 / €3\n", DiagPrecisionLoss, "100 EUR / 3 EUR ≈ 33.3333 EUR"},
+		{"huge power", "x = 2 ^ 10000\n", DiagOverflow, "2 ^ 10000 ≈ 10^3010"},
+		{"large power within range", "x = 10 ^ 300\n", "", ""},
+		{"tiny power", "x = 0.1 ^ 1000\n", DiagPrecisionLoss, "≈ 10^-1000"},
+		{"power of one", "x = 1 ^ 100000\n", "", ""},
+		{"variable power", "x = 10 ^ n\n", DiagOverflow, "10 ^ 400"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			checker := NewChecker()
+			checker.GetEnvironment().Set("n", types.NewNumber(decimal.NewFromInt(400)))
+			checker.GetEnvironment().Set("total", types.NewCurrency(decimal.RequireFromString("90.01"), "$"))
+
+			var found []Diagnostic
+			for _, d := range checker.Check(nodes) {
+				if d.Code == DiagPrecisionLoss || d.Code == DiagOverflow {
+					found = append(found, d)
+				}
+			}
+			if tt.code == "" {
+				if len(found) > 0 {
+					t.Fatalf("expected no precision warning, got %v", found)
+				}
+				return
+			}
+			if len(found) != 1 {
+				t.Fatalf("expected one %s warning, got %v", tt.code, found)
+			}
+			d := found[0]
+			if d.Code != tt.code || d.Severity != Warning {
+				t.Errorf("got %s %s, want %s WARNING", d.Code, d.Severity, tt.code)
+			}
+			if !strings.Contains(d.Message, tt.message) {
+				t.Errorf("message %q does not contain %q", d.Message, tt.message)
+			}
+		})
+	}
+}
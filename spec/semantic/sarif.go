@@ -0,0 +1,152 @@
+package semantic
+
+import "slices"
+
+// SARIF (Static Analysis Results Interchange Format) 2.1.0 output, read by
+// CI systems and code review tools to annotate source files. Only the
+// parts of the format CalcMark diagnostics use are modeled.
+
+// SARIFVersion and SARIFSchema identify the SARIF format produced by ToSARIF.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is a SARIF log file, ready to marshal as JSON.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun holds the results of one run of a tool.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool that produced a run.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver names the tool and the rules its results refer to.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules,omitempty"`
+}
+
+// SARIFRule describes a diagnostic code.
+type SARIFRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri,omitempty"`
+}
+
+// SARIFResult is one diagnostic.
+type SARIFResult struct {
+	RuleID     string          `json:"ruleId,omitempty"`
+	RuleIndex  *int            `json:"ruleIndex,omitempty"`
+	Level      string          `json:"level"` // "error", "warning", or "note"
+	Message    SARIFMessage    `json:"message"`
+	Locations  []SARIFLocation `json:"locations,omitempty"`
+	Properties *SARIFProperty  `json:"properties,omitempty"`
+}
+
+// SARIFMessage is a result's message, with an optional longer markdown form.
+type SARIFMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// SARIFLocation places a result in a file.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a region of an artifact.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation identifies a file by URI, usually relative to the
+// repository root.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a 1-indexed line and column range.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// SARIFProperty holds CalcMark-specific result data.
+type SARIFProperty struct {
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ToSARIF converts diagnostics found in the file at uri to a SARIF log with
+// one run. Diagnostic ranges are taken as lines and columns of that file;
+// diagnostics without a range are reported on the file as a whole. Codes
+// become rule IDs, Detailed the markdown message, and Hint the "note" level.
+func ToSARIF(uri string, diagnostics []Diagnostic) *SARIFLog {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           "calcmark",
+			InformationURI: "https://github.com/CalcMark/go-calcmark",
+		}},
+		Results: []SARIFResult{},
+	}
+
+	for _, d := range diagnostics {
+		result := SARIFResult{
+			RuleID:  d.Code,
+			Level:   sarifLevel(d.Severity),
+			Message: SARIFMessage{Text: d.Message, Markdown: d.Detailed},
+		}
+		if d.Code != "" {
+			i := slices.IndexFunc(run.Tool.Driver.Rules, func(r SARIFRule) bool { return r.ID == d.Code })
+			if i < 0 {
+				i = len(run.Tool.Driver.Rules)
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, SARIFRule{ID: d.Code, HelpURI: d.Link})
+			}
+			result.RuleIndex = &i
+		}
+
+		location := SARIFLocation{PhysicalLocation: SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: uri},
+		}}
+		if r := d.Range; r != nil && r.Start.Line > 0 {
+			region := &SARIFRegion{StartLine: r.Start.Line, StartColumn: r.Start.Column}
+			if r.End.Line >= r.Start.Line {
+				region.EndLine, region.EndColumn = r.End.Line, r.End.Column
+			}
+			location.PhysicalLocation.Region = region
+		}
+		result.Locations = []SARIFLocation{location}
+
+		if len(d.Suggestions) > 0 {
+			result.Properties = &SARIFProperty{Suggestions: d.Suggestions}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return &SARIFLog{Schema: SARIFSchema, Version: SARIFVersion, Runs: []SARIFRun{run}}
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(s Severity) string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,82 @@
+package semantic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CalcMark/go-calcmark/spec/ast"
+	"github.com/CalcMark/go-calcmark/spec/parser"
+)
+
+func TestToSARIF(t *testing.T) {
+	nodes, err := parser.Parse("total = rent + 5\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	diags := NewChecker().Check(nodes)
+	diags = append(diags,
+		Diagnostic{Severity: Hint, Code: DiagNamingConvention, Message: "use snake_case", Suggestions: []string{"monthly_cost"}},
+		Diagnostic{Severity: Error, Code: DiagUndefinedVariable, Message: "again",
+			Range: &ast.Range{Start: ast.Position{Line: 2, Column: 5}, End: ast.Position{Line: 2, Column: 9}}},
+	)
+
+	log := ToSARIF("docs/budget.cm", diags)
+	if log.Version != SARIFVersion || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != len(diags) {
+		t.Fatalf("got %d results, want %d", len(run.Results), len(diags))
+	}
+
+	first := run.Results[0]
+	if first.RuleID != DiagUndefinedVariable || first.Level != "error" || *first.RuleIndex != 0 {
+		t.Errorf("first result = %+v", first)
+	}
+	loc := first.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "docs/budget.cm" {
+		t.Errorf("uri = %q", loc.ArtifactLocation.URI)
+	}
+
+	hint := run.Results[len(diags)-2]
+	if hint.Level != "note" || hint.Properties == nil || hint.Properties.Suggestions[0] != "monthly_cost" {
+		t.Errorf("hint result = %+v", hint)
+	}
+	if hint.Locations[0].PhysicalLocation.Region != nil {
+		t.Error("a diagnostic without a range should have no region")
+	}
+
+	// Codes become rules once each, in order of first use
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[1].ID != DiagNamingConvention {
+		t.Errorf("rules = %+v", run.Tool.Driver.Rules)
+	}
+	last := run.Results[len(diags)-1]
+	if *last.RuleIndex != 0 {
+		t.Errorf("repeated code should reuse rule 0, got %d", *last.RuleIndex)
+	}
+	want := SARIFRegion{StartLine: 2, StartColumn: 5, EndLine: 2, EndColumn: 9}
+	if region := last.Locations[0].PhysicalLocation.Region; region == nil || *region != want {
+		t.Errorf("region = %+v, want %+v", region, want)
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, want := range []string{`"$schema":"` + SARIFSchema + `"`, `"version":"2.1.0"`, `"startLine":2`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestToSARIFEmpty(t *testing.T) {
+	data, err := json.Marshal(ToSARIF("a.cm", nil))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"results":[]`) {
+		t.Errorf("an empty run must still list results: %s", data)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/CalcMark/go-calcmark/spec/ast"
 	"github.com/CalcMark/go-calcmark/spec/types"
+	"github.com/shopspring/decimal"
 )
 
 // TypeInfo represents type information for a node.
@@ -26,6 +27,116 @@ const (
 	TypeQuantity
 )
 
+// inferType returns the type an expression evaluates to, when the checker
+// can tell without evaluating it. Variables have the types inferred for
+// their assignments, or those of the values the environment was given.
+// Function calls, and operations mixing types beyond the rules of
+// CheckTypeCompatibility, are unknown. Currency types carry their code,
+// with a zero value.
+func (c *Checker) inferType(node ast.Node) (TypeInfo, bool) {
+	switch n := node.(type) {
+	case *ast.NumberLiteral:
+		return TypeInfo{Kind: TypeNumber}, true
+	case *ast.CurrencyLiteral:
+		return currencyType(types.NormalizeCurrencyCode(n.Symbol)), true
+	case *ast.QuantityLiteral:
+		return TypeInfo{Kind: TypeQuantity}, true
+	case *ast.DurationLiteral:
+		return TypeInfo{Kind: TypeDuration}, true
+	case *ast.DateLiteral, *ast.RelativeDateLiteral:
+		return TypeInfo{Kind: TypeDate}, true
+	case *ast.TimeLiteral:
+		return TypeInfo{Kind: TypeTime}, true
+	case *ast.BooleanLiteral, *ast.ComparisonOp:
+		return TypeInfo{Kind: TypeBoolean}, true
+	case *ast.Identifier:
+		if info, ok := c.inferred[n.Name]; ok {
+			return info, true
+		}
+		val, _ := c.env.Get(n.Name)
+		return typeInfoOf(val)
+	case *ast.Expression:
+		return c.inferType(n.Expr)
+	case *ast.UnaryOp:
+		if n.Operator == "-" || n.Operator == "+" {
+			return c.inferType(n.Operand)
+		}
+	case *ast.PercentageOf:
+		return c.inferType(n.Value)
+	case *ast.UnitConversion:
+		// Converting money to another currency: price in EUR
+		if from, ok := c.inferType(n.Quantity); ok && from.Kind == TypeCurrency && ValidateCurrencyCode(n.TargetUnit) {
+			return currencyType(types.NormalizeCurrencyCode(n.TargetUnit)), true
+		}
+	case *ast.BinaryOp:
+		left, ok := c.inferType(n.Left)
+		if !ok {
+			return TypeInfo{}, false
+		}
+		right, ok := c.inferType(n.Right)
+		if !ok {
+			return TypeInfo{}, false
+		}
+		return binaryType(left, right, n.Operator)
+	}
+	return TypeInfo{}, false
+}
+
+// binaryType returns the type of an arithmetic operation on numbers and
+// currencies, or false for other types and incompatible operands.
+func binaryType(left, right TypeInfo, operator string) (TypeInfo, bool) {
+	switch {
+	case left.Kind == TypeNumber && right.Kind == TypeNumber:
+		return left, true
+	case left.Kind == TypeCurrency && right.Kind == TypeNumber:
+		if operator == "+" || operator == "-" || operator == "*" || operator == "/" {
+			return left, true
+		}
+	case left.Kind == TypeNumber && right.Kind == TypeCurrency:
+		if operator == "*" {
+			return right, true
+		}
+	case left.Kind == TypeCurrency && right.Kind == TypeCurrency:
+		if left.Type.(*types.Currency).Code != right.Type.(*types.Currency).Code {
+			return TypeInfo{}, false
+		}
+		switch operator {
+		case "+", "-":
+			return left, true
+		case "/":
+			return TypeInfo{Kind: TypeNumber}, true // A ratio
+		}
+	}
+	return TypeInfo{}, false
+}
+
+// typeInfoOf returns the type of an evaluated value, or false for nil and
+// values of kinds TypeKind doesn't name.
+func typeInfoOf(val types.Type) (TypeInfo, bool) {
+	switch v := val.(type) {
+	case *types.Number:
+		return TypeInfo{Kind: TypeNumber}, true
+	case *types.Currency:
+		return currencyType(v.Code), true
+	case *types.Quantity:
+		return TypeInfo{Kind: TypeQuantity}, true
+	case *types.Duration:
+		return TypeInfo{Kind: TypeDuration}, true
+	case *types.Date:
+		return TypeInfo{Kind: TypeDate}, true
+	case *types.Time:
+		return TypeInfo{Kind: TypeTime}, true
+	case *types.Boolean:
+		return TypeInfo{Kind: TypeBoolean}, true
+	}
+	return TypeInfo{}, false
+}
+
+// currencyType returns the type of a currency with an ISO code.
+func currencyType(code string) TypeInfo {
+	return TypeInfo{Type: types.NewCurrency(decimal.Zero, code), Kind: TypeCurrency}
+}
+
 // CheckTypeCompatibility validates that an operation is type-compatible.
 // Returns an error diagnostic if incompatible, nil if compatible.
 func CheckTypeCompatibility(left, right TypeInfo, operator string, r *ast.Range) *Diagnostic {
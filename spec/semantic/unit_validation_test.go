@@ -37,7 +37,7 @@ func TestUnitCompatibility_IncompatibleUnits(t *testing.T) {
 					Unit:  tt.unit2,
 					Range: &ast.Range{},
 				},
-				Range: &ast.Range{},
+				Range: &ast.Range{Start: ast.Position{Line: 1, Column: 5}},
 			}
 
 			diagnostics := checker.Check([]ast.Node{binOp})
@@ -54,6 +54,9 @@ func TestUnitCompatibility_IncompatibleUnits(t *testing.T) {
 				if d.Severity != semantic.Error {
 					t.Errorf("Expected ERROR severity, got %s", d.Severity)
 				}
+				if d.Range != binOp.Range {
+					t.Errorf("Expected the operation's range, got %+v", d.Range)
+				}
 				// USER REQUIREMENT: Detailed message explaining incompatibility
 				if d.Detailed == "" {
 					t.Error("Expected detailed message about incompatible units")
@@ -214,19 +217,32 @@ func TestDataSizeBaseMixing(t *testing.T) {
 		hintParts []string // Parts expected in the detailed message
 	}{
 		{
-			name:      "GB + Mbps (binary + decimal)",
-			unit1:     "GB",
+			name:      "GiB + Mbps (binary + decimal)",
+			unit1:     "GiB",
 			unit2:     "Mbps",
 			wantHint:  true,
 			hintParts: []string{"binary", "decimal", "1024", "1000"},
 		},
 		{
-			name:      "MB + Gbps",
-			unit1:     "MB",
+			name:      "MiB + Gbps",
+			unit1:     "MiB",
 			unit2:     "Gbps",
 			wantHint:  true,
 			hintParts: []string{"binary", "decimal"},
 		},
+		{
+			name:     "GB + Mbps (both decimal)",
+			unit1:    "GB",
+			unit2:    "Mbps",
+			wantHint: false,
+		},
+		{
+			name:      "GiB + MB",
+			unit1:     "GiB",
+			unit2:     "MB",
+			wantHint:  true,
+			hintParts: []string{"binary", "decimal"},
+		},
 		{
 			name:      "GiB + Kbps",
 			unit1:     "GiB",
@@ -235,7 +251,7 @@ func TestDataSizeBaseMixing(t *testing.T) {
 			hintParts: []string{"binary", "decimal"},
 		},
 		{
-			name:     "GB + MB (both binary)",
+			name:     "GB + MB (both decimal)",
 			unit1:    "GB",
 			unit2:    "MB",
 			wantHint: false,
@@ -330,13 +346,11 @@ func TestDataSizeBaseClassification(t *testing.T) {
 		{"GiB", semantic.DataSizeBaseBinary},
 		{"TiB", semantic.DataSizeBaseBinary},
 
-		// Ambiguous units treated as binary
-		{"KB", semantic.DataSizeBaseBinary},
-		{"MB", semantic.DataSizeBaseBinary},
-		{"GB", semantic.DataSizeBaseBinary},
-		{"TB", semantic.DataSizeBaseBinary},
-
-		// Decimal/network units
+		// Decimal (SI) units
+		{"KB", semantic.DataSizeBaseDecimal},
+		{"MB", semantic.DataSizeBaseDecimal},
+		{"GB", semantic.DataSizeBaseDecimal},
+		{"TB", semantic.DataSizeBaseDecimal},
 		{"Kbps", semantic.DataSizeBaseDecimal},
 		{"Mbps", semantic.DataSizeBaseDecimal},
 		{"Gbps", semantic.DataSizeBaseDecimal},
@@ -146,9 +146,9 @@ func AreUnitsCompatible(unit1, unit2 string) bool {
 
 // checkUnitCompatibility validates unit compatibility in binary operations
 // USER REQUIREMENT: "10 meters + 5 kg" must produce error
-func (c *Checker) checkUnitCompatibility(left, right ast.Node) {
-	leftUnit := getNodeUnit(left)
-	rightUnit := getNodeUnit(right)
+func (c *Checker) checkUnitCompatibility(b *ast.BinaryOp) {
+	leftUnit := getNodeUnit(b.Left)
+	rightUnit := getNodeUnit(b.Right)
 
 	if !AreUnitsCompatible(leftUnit, rightUnit) {
 		leftType := GetQuantityType(leftUnit)
@@ -161,15 +161,17 @@ func (c *Checker) checkUnitCompatibility(left, right ast.Node) {
 			Detailed: fmt.Sprintf(
 				"Cannot add %s (%s) to %s (%s) - incompatible unit types",
 				leftUnit, leftType, rightUnit, rightType),
+			Range: b.Range,
 		})
 	}
 
 	// Check for mixing binary and decimal data size units
-	c.checkDataSizeBaseMixingForUnits(leftUnit, rightUnit)
+	c.checkDataSizeBaseMixingForUnits(leftUnit, rightUnit, b.Range)
 }
 
-// checkDataSizeBaseMixingForUnits emits a hint if two units mix binary and decimal bases.
-func (c *Checker) checkDataSizeBaseMixingForUnits(unit1, unit2 string) {
+// checkDataSizeBaseMixingForUnits emits a hint, at rng, if two units mix
+// binary and decimal bases.
+func (c *Checker) checkDataSizeBaseMixingForUnits(unit1, unit2 string, rng *ast.Range) {
 	if unit1 == "" || unit2 == "" {
 		return
 	}
@@ -181,6 +183,7 @@ func (c *Checker) checkDataSizeBaseMixingForUnits(unit1, unit2 string) {
 			Code:     DiagMixedBaseUnits,
 			Message:  "mixing binary and decimal data units",
 			Detailed: message,
+			Range:    rng,
 		})
 	}
 }
@@ -208,7 +211,7 @@ const (
 	DataSizeBaseNone DataSizeBase = iota
 	// DataSizeBaseBinary indicates 1024-based units (KiB, MiB, GiB, etc.)
 	DataSizeBaseBinary
-	// DataSizeBaseDecimal indicates 1000-based units (kbps, Mbps, Gbps, Kbit, etc.)
+	// DataSizeBaseDecimal indicates 1000-based units (KB, MB, Mbps, Gbps, Kbit, etc.)
 	DataSizeBaseDecimal
 )
 
@@ -226,9 +229,12 @@ func GetDataSizeBase(unit string) DataSizeBase {
 		"kibibit": true, "mebibit": true, "gibibit": true, "tebibit": true,
 	}
 
-	// Decimal/network units: kbit, Mbit, Gbps, Mbps, etc.
-	// These are unambiguously 1000-based (SI prefixes for networking)
+	// Decimal units: KB, MB, GB, Kbit, Mbit, Gbps, Mbps, etc.
+	// These are 1000-based (SI prefixes)
 	decimalUnits := map[string]bool{
+		"kb": true, "mb": true, "gb": true, "tb": true, "pb": true, "eb": true,
+		"kilobyte": true, "megabyte": true, "gigabyte": true, "terabyte": true,
+		"petabyte": true, "exabyte": true,
 		"kbit": true, "mbit": true, "gbit": true, "tbit": true,
 		"bps": true, "kbps": true, "mbps": true, "gbps": true, "tbps": true,
 		"kilobit": true, "megabit": true, "gigabit": true, "terabit": true,
@@ -250,21 +256,6 @@ func GetDataSizeBase(unit string) DataSizeBase {
 		return DataSizeBaseNone
 	}
 
-	// Ambiguous units: KB, MB, GB, TB, PB
-	// In this implementation, we treat KB/MB/GB/TB as binary (1024-based)
-	// to match common usage in computing contexts, but they're
-	// technically ambiguous. We classify them as binary to emit hints
-	// when mixed with explicitly decimal units like Mbps.
-	ambiguousBinaryUnits := map[string]bool{
-		"kb": true, "mb": true, "gb": true, "tb": true, "pb": true, "eb": true,
-		"kilobyte": true, "megabyte": true, "gigabyte": true, "terabyte": true,
-		"petabyte": true, "exabyte": true,
-	}
-
-	if ambiguousBinaryUnits[lower] {
-		return DataSizeBaseBinary
-	}
-
 	return DataSizeBaseNone
 }
 
@@ -300,7 +291,7 @@ func checkDataSizeBaseMixing(unit1, unit2 string) (bool, string) {
 
 		return true, fmt.Sprintf(
 			"Mixing %s (binary, 1024-based) with %s (decimal, 1000-based). "+
-				"Binary units like KB/MB/GB use powers of 1024, while network units like Mbps use powers of 1000. "+
+				"Binary units like KiB/MiB/GiB use powers of 1024, while KB/MB/GB and network units like Mbps use powers of 1000. "+
 				"This may cause unexpected results.",
 			binaryUnit, decimalUnit)
 	}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Basket is a weighted sum of currencies, such as an index defined as
+// "0.6 * USD + 0.3 * EUR + 0.1 * GBP" or a multi-currency exposure like
+// "$5000 + 2000 * EUR". It has no single value until converted into one
+// currency at the document's exchange rates.
+type Basket struct {
+	Weights []BasketWeight // In order of first appearance; none zero
+}
+
+// BasketWeight is the amount of one currency in a basket.
+type BasketWeight struct {
+	Code   string // ISO 4217 code
+	Amount decimal.Decimal
+}
+
+// NewBasket creates a basket holding amount of the currency code.
+func NewBasket(code string, amount decimal.Decimal) *Basket {
+	return (&Basket{}).add(code, amount)
+}
+
+// BasketOf returns the basket holding just c.
+func BasketOf(c *Currency) *Basket {
+	return NewBasket(c.Code, c.Value)
+}
+
+// Add returns the basket holding b's currencies and other's.
+func (b *Basket) Add(other *Basket) *Basket {
+	sum := &Basket{Weights: append([]BasketWeight(nil), b.Weights...)}
+	for _, w := range other.Weights {
+		sum = sum.add(w.Code, w.Amount)
+	}
+	return sum
+}
+
+// Scale returns b with every weight multiplied by factor.
+func (b *Basket) Scale(factor decimal.Decimal) *Basket {
+	scaled := &Basket{}
+	for _, w := range b.Weights {
+		scaled = scaled.add(w.Code, w.Amount.Mul(factor))
+	}
+	return scaled
+}
+
+// DivRound returns b with every weight divided by divisor, which must not
+// be zero, rounded to precision places.
+func (b *Basket) DivRound(divisor decimal.Decimal, precision int32) *Basket {
+	divided := &Basket{}
+	for _, w := range b.Weights {
+		divided = divided.add(w.Code, w.Amount.DivRound(divisor, precision))
+	}
+	return divided
+}
+
+// add returns b with amount more of code, dropping the weight if it
+// comes to zero.
+func (b *Basket) add(code string, amount decimal.Decimal) *Basket {
+	weights := append([]BasketWeight(nil), b.Weights...)
+	for i, w := range weights {
+		if w.Code == code {
+			if total := w.Amount.Add(amount); total.IsZero() {
+				weights = append(weights[:i], weights[i+1:]...)
+			} else {
+				weights[i].Amount = total
+			}
+			return &Basket{Weights: weights}
+		}
+	}
+	if !amount.IsZero() {
+		weights = append(weights, BasketWeight{Code: code, Amount: amount})
+	}
+	return &Basket{Weights: weights}
+}
+
+// String returns the weights as a sum: "0.6 USD + 0.3 EUR + 0.1 GBP".
+func (b *Basket) String() string {
+	if len(b.Weights) == 0 {
+		return "0"
+	}
+	var sb strings.Builder
+	for i, w := range b.Weights {
+		amount := w.Amount
+		switch {
+		case i == 0 && amount.IsNegative():
+			sb.WriteString("-")
+			amount = amount.Neg()
+		case i > 0 && amount.IsNegative():
+			sb.WriteString(" - ")
+			amount = amount.Neg()
+		case i > 0:
+			sb.WriteString(" + ")
+		}
+		sb.WriteString(amount.String() + " " + w.Code)
+	}
+	return sb.String()
+}
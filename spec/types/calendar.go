@@ -0,0 +1,273 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxBusinessDays bounds how far AddBusinessDays steps, about 380 years,
+// so a mistyped count can't stall evaluation.
+const MaxBusinessDays = 100000
+
+// Calendar decides which dates are business days: Monday to Friday, except
+// holidays. Holidays come from named regions (see HolidayRegions), dates
+// that recur every year, and one-off dates. A nil Calendar has no holidays.
+type Calendar struct {
+	regions []string
+	annual  map[monthDay]bool
+	dates   map[time.Time]bool
+}
+
+// monthDay is a date that recurs every year, such as December 24.
+type monthDay struct {
+	month time.Month
+	day   int
+}
+
+// NewCalendar returns a calendar with no holidays.
+func NewCalendar() *Calendar {
+	return &Calendar{annual: make(map[monthDay]bool), dates: make(map[time.Time]bool)}
+}
+
+// NewRegionCalendar returns a calendar with the holidays of a named region.
+func NewRegionCalendar(region string) (*Calendar, error) {
+	c := NewCalendar()
+	if err := c.AddRegion(region); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// AddRegion adds the holidays of a named region, matched case-insensitively.
+func (c *Calendar) AddRegion(region string) error {
+	name, ok := LookupHolidayRegion(region)
+	if !ok {
+		return fmt.Errorf("unknown holiday calendar '%s' (known: %s)", region, strings.Join(HolidayRegions(), ", "))
+	}
+	if !slices.Contains(c.regions, name) {
+		c.regions = append(c.regions, name)
+	}
+	return nil
+}
+
+// AddDate adds a one-off holiday.
+func (c *Calendar) AddDate(d *Date) {
+	c.dates[d.Time] = true
+}
+
+// AddAnnual adds a holiday on the same month and day every year.
+func (c *Calendar) AddAnnual(month time.Month, day int) {
+	c.annual[monthDay{month, day}] = true
+}
+
+// Regions returns the canonical names of the calendar's regions.
+func (c *Calendar) Regions() []string {
+	if c == nil {
+		return nil
+	}
+	return c.regions
+}
+
+// IsHoliday reports whether d is one of the calendar's holidays.
+func (c *Calendar) IsHoliday(d *Date) bool {
+	return c.holidays(d.Time.Year())[d.Time]
+}
+
+// IsBusinessDay reports whether d is a weekday that isn't a holiday.
+func (c *Calendar) IsBusinessDay(d *Date) bool {
+	return !isWeekend(d.Time) && !c.IsHoliday(d)
+}
+
+// AddBusinessDays returns the date n business days after d, or before it
+// if n is negative. d itself isn't counted, so Friday + 1 business day is
+// the following Monday. Adding 0 returns d.
+func (c *Calendar) AddBusinessDays(d *Date, n int) (*Date, error) {
+	if n > MaxBusinessDays || n < -MaxBusinessDays {
+		return nil, fmt.Errorf("cannot add %d business days: at most %d", n, MaxBusinessDays)
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	years := make(map[int]map[time.Time]bool)
+	t := d.Time
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.isBusinessDay(t, years) {
+			n--
+		}
+	}
+	return &Date{Time: t}, nil
+}
+
+// BusinessDaysBetween counts the business days from one date to another,
+// including both, as spreadsheet NETWORKDAYS functions do. The count is
+// negative when to is before from.
+func (c *Calendar) BusinessDaysBetween(from, to *Date) int {
+	start, end, sign := from.Time, to.Time, 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	years := make(map[int]map[time.Time]bool)
+	count := 0
+	for t := start; !t.After(end); t = t.AddDate(0, 0, 1) {
+		if c.isBusinessDay(t, years) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// isBusinessDay is IsBusinessDay for loops, memoizing each year's holidays.
+func (c *Calendar) isBusinessDay(t time.Time, years map[int]map[time.Time]bool) bool {
+	if isWeekend(t) {
+		return false
+	}
+	holidays, ok := years[t.Year()]
+	if !ok {
+		holidays = c.holidays(t.Year())
+		years[t.Year()] = holidays
+	}
+	return !holidays[t]
+}
+
+// holidays returns the calendar's holidays in a year, at midnight UTC.
+func (c *Calendar) holidays(year int) map[time.Time]bool {
+	result := make(map[time.Time]bool)
+	if c == nil {
+		return result
+	}
+	for _, region := range c.regions {
+		// A holiday observed on another day can cross into the next or
+		// previous year, as when January 1 is a Saturday
+		for y := year - 1; y <= year+1; y++ {
+			for _, t := range holidayRegions[region](y) {
+				if t.Year() == year {
+					result[t] = true
+				}
+			}
+		}
+	}
+	for md := range c.annual {
+		t := time.Date(year, md.month, md.day, 0, 0, 0, 0, time.UTC)
+		if t.Month() == md.month { // Skips February 29 outside leap years
+			result[t] = true
+		}
+	}
+	for t := range c.dates {
+		if t.Year() == year {
+			result[t] = true
+		}
+	}
+	return result
+}
+
+// holidayRegions maps region names to the public holidays they observe in
+// a year, computed from current rules.
+var holidayRegions = map[string]func(year int) []time.Time{
+	"UK": ukHolidays,
+	"US": usHolidays,
+}
+
+// HolidayRegions returns the names of the built-in holiday calendars, sorted.
+func HolidayRegions() []string {
+	return slices.Sorted(maps.Keys(holidayRegions))
+}
+
+// LookupHolidayRegion returns the canonical name of a built-in holiday
+// calendar, matched case-insensitively.
+func LookupHolidayRegion(name string) (string, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	_, ok := holidayRegions[name]
+	return name, ok
+}
+
+// usHolidays returns US federal holidays, moved to the Friday before or
+// Monday after when they fall on a weekend.
+func usHolidays(year int) []time.Time {
+	observed := func(t time.Time) time.Time {
+		switch t.Weekday() {
+		case time.Saturday:
+			return t.AddDate(0, 0, -1)
+		case time.Sunday:
+			return t.AddDate(0, 0, 1)
+		}
+		return t
+	}
+	days := []time.Time{
+		observed(date(year, time.January, 1)),
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Presidents' Day
+		nthWeekday(year, time.May, time.Monday, -1),       // Memorial Day
+		observed(date(year, time.July, 4)),                // Independence Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.October, time.Monday, 2),    // Columbus Day
+		observed(date(year, time.November, 11)),           // Veterans Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving
+		observed(date(year, time.December, 25)),
+	}
+	if year >= 2021 {
+		days = append(days, observed(date(year, time.June, 19))) // Juneteenth
+	}
+	return days
+}
+
+// ukHolidays returns bank holidays in England and Wales. One falling on a
+// weekend moves to the next weekday that isn't already a holiday, so
+// Christmas on a Saturday is observed on Monday and Boxing Day on Tuesday.
+func ukHolidays(year int) []time.Time {
+	easter := easterSunday(year)
+	days := []time.Time{
+		easter.AddDate(0, 0, -2), // Good Friday
+		easter.AddDate(0, 0, 1),  // Easter Monday
+		nthWeekday(year, time.May, time.Monday, 1),
+		nthWeekday(year, time.May, time.Monday, -1),
+		nthWeekday(year, time.August, time.Monday, -1),
+	}
+	for _, t := range []time.Time{date(year, time.January, 1), date(year, time.December, 25), date(year, time.December, 26)} {
+		for isWeekend(t) || slices.Contains(days, t) {
+			t = t.AddDate(0, 0, 1)
+		}
+		days = append(days, t)
+	}
+	return days
+}
+
+// easterSunday returns the date of Easter in the Gregorian calendar, by
+// the anonymous Gregorian algorithm (Meeus/Jones/Butcher).
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// nthWeekday returns the nth given weekday of a month, or the last if n
+// is -1.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := date(year, month, 1)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
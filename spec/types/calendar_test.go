@@ -0,0 +1,125 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func mustDate(t *testing.T, year, month, day int) *Date {
+	t.Helper()
+	d, err := NewDate(year, month, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestRegionHolidays(t *testing.T) {
+	tests := []struct {
+		region  string
+		year    int
+		want    []string // Holidays, as 2006-01-02
+		notWant []string
+	}{
+		{"US", 2026, []string{"2026-01-01", "2026-01-19", "2026-02-16", "2026-05-25", "2026-06-19", "2026-07-03", "2026-09-07", "2026-10-12", "2026-11-11", "2026-11-26", "2026-12-25"}, []string{"2026-07-04"}},
+		{"US", 2022, []string{"2021-12-31"}, []string{"2022-01-03"}}, // Jan 1 2022 was a Saturday
+		{"US", 2019, nil, []string{"2019-06-19"}},                    // Before Juneteenth
+		{"UK", 2026, []string{"2026-01-01", "2026-04-03", "2026-04-06", "2026-05-04", "2026-05-25", "2026-08-31", "2026-12-25", "2026-12-28"}, []string{"2026-12-26"}},
+		{"UK", 2021, []string{"2021-12-27", "2021-12-28"}, nil}, // Christmas on a Saturday
+		{"UK", 2022, []string{"2022-01-03", "2022-12-26", "2022-12-27"}, nil},
+		{"uk", 2024, []string{"2024-03-29", "2024-04-01"}, nil}, // Easter
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			c, err := NewRegionCalendar(tt.region)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				day, _ := time.Parse(time.DateOnly, s)
+				if !c.IsHoliday(NewDateFromTime(day)) {
+					t.Errorf("%s: expected %s to be a holiday", tt.region, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				day, _ := time.Parse(time.DateOnly, s)
+				if c.IsHoliday(NewDateFromTime(day)) {
+					t.Errorf("%s: expected %s not to be a holiday", tt.region, s)
+				}
+			}
+		})
+	}
+
+	if _, err := NewRegionCalendar("Narnia"); err == nil {
+		t.Error("expected an error for an unknown region")
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	us, _ := NewRegionCalendar("US")
+	custom := NewCalendar()
+	custom.AddAnnual(time.December, 24)
+	custom.AddDate(mustDate(t, 2026, 1, 2))
+
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		from     *Date
+		days     int
+		want     *Date
+	}{
+		{"weekends only", nil, mustDate(t, 2026, 1, 16), 1, mustDate(t, 2026, 1, 19)},
+		{"zero", nil, mustDate(t, 2026, 1, 17), 0, mustDate(t, 2026, 1, 17)},
+		{"over a holiday", us, mustDate(t, 2026, 1, 16), 1, mustDate(t, 2026, 1, 20)},
+		{"backwards", us, mustDate(t, 2026, 1, 20), -1, mustDate(t, 2026, 1, 16)},
+		{"two weeks", nil, mustDate(t, 2026, 3, 2), 10, mustDate(t, 2026, 3, 16)},
+		{"annual holiday", custom, mustDate(t, 2027, 12, 23), 1, mustDate(t, 2027, 12, 27)},
+		{"one-off holiday", custom, mustDate(t, 2026, 1, 1), 1, mustDate(t, 2026, 1, 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.calendar.AddBusinessDays(tt.from, tt.days)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("AddBusinessDays(%s, %d) = %s, want %s", tt.from.ShortString(), tt.days, got.ShortString(), tt.want.ShortString())
+			}
+		})
+	}
+
+	if _, err := NewCalendar().AddBusinessDays(mustDate(t, 2026, 1, 1), MaxBusinessDays+1); err == nil {
+		t.Error("expected an error beyond MaxBusinessDays")
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	us, _ := NewRegionCalendar("US")
+	leap := NewCalendar()
+	leap.AddAnnual(time.February, 29)
+
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		from, to *Date
+		want     int
+	}{
+		{"same weekday", nil, mustDate(t, 2026, 1, 5), mustDate(t, 2026, 1, 5), 1},
+		{"weekend day", nil, mustDate(t, 2026, 1, 3), mustDate(t, 2026, 1, 3), 0},
+		{"one week", nil, mustDate(t, 2026, 1, 5), mustDate(t, 2026, 1, 11), 5},
+		{"with holidays", us, mustDate(t, 2026, 1, 5), mustDate(t, 2026, 2, 20), 33},
+		{"reversed", us, mustDate(t, 2026, 2, 20), mustDate(t, 2026, 1, 5), -33},
+		{"Feb 29 outside leap years", leap, mustDate(t, 2027, 2, 1), mustDate(t, 2027, 3, 31), 43},
+		{"Feb 29 in a leap year", leap, mustDate(t, 2028, 2, 28), mustDate(t, 2028, 3, 1), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calendar.BusinessDaysBetween(tt.from, tt.to); got != tt.want {
+				t.Errorf("BusinessDaysBetween(%s, %s) = %d, want %d", tt.from.ShortString(), tt.to.ShortString(), got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
@@ -12,6 +13,11 @@ type Currency struct {
 	Value  decimal.Decimal
 	Symbol string // Display symbol: "$", "€", "£", "¥", or ISO code like "USD"
 	Code   string // Normalized ISO 4217 code: "USD", "EUR", "GBP", "JPY"
+
+	// RealYear is the year whose prices a real amount is in, as adjust()
+	// restates amounts for inflation; zero for a nominal amount, in the
+	// prices of its own time.
+	RealYear int
 }
 
 // SymbolToCode maps currency symbols to their ISO 4217 codes.
@@ -21,10 +27,11 @@ var SymbolToCode = map[string]string{
 	"€": "EUR",
 	"£": "GBP",
 	"¥": "JPY",
+	"₿": "BTC",
 }
 
 // NewCurrency creates a new Currency with the given value and symbol/code.
-// If symbolOrCode is a known symbol ($, €, £, ¥), it's mapped to the ISO code.
+// If symbolOrCode is a known symbol ($, €, £, ¥, ₿), it's mapped to the ISO code.
 // Otherwise, it's used as both the symbol and code.
 func NewCurrency(value decimal.Decimal, symbolOrCode string) *Currency {
 	symbol := symbolOrCode
@@ -56,11 +63,27 @@ func NewCurrencyFromString(s string, symbolOrCode string) (*Currency, error) {
 // For display purposes, formats the value with standard decimal formatting.
 func (c *Currency) String() string {
 	// Format with appropriate decimals (most currencies use 2)
-	formatted := c.Value.StringFixed(2)
+	places := CurrencyDecimals(c.Code)
+	if places <= 2 {
+		return fmt.Sprintf("%s%s", c.Symbol, c.Value.StringFixed(places)) + c.RealLabel()
+	}
 
-	// Remove trailing zeros after decimal point, but keep at least 2 decimals for cents
-	// This ensures $1.00 but also allows $1.99
-	return fmt.Sprintf("%s%s", c.Symbol, formatted)
+	// Finer-grained currencies (₿0.00012345) round to their own precision,
+	// then drop trailing zeros but keep at least 2 decimals: ₿1.50, not ₿1.50000000
+	formatted := c.Value.Round(places).String()
+	if _, fraction, _ := strings.Cut(formatted, "."); len(fraction) < 2 {
+		formatted = c.Value.StringFixed(2)
+	}
+	return fmt.Sprintf("%s%s", c.Symbol, formatted) + c.RealLabel()
+}
+
+// RealLabel returns the annotation a real amount's display ends with, e.g.
+// " (real 2025)", or "" for a nominal amount.
+func (c *Currency) RealLabel() string {
+	if c.RealYear == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (real %d)", c.RealYear)
 }
 
 // IsSameCurrency checks if two currencies have the same ISO code.
@@ -76,6 +99,51 @@ var CodeToSymbol = map[string]string{
 	"EUR": "€",
 	"GBP": "£",
 	"JPY": "¥",
+	"BTC": "₿",
+}
+
+// CryptoCurrencies lists the recognized cryptocurrency codes and their
+// default display precision (decimal places of the smallest unit shown).
+// They are not ISO 4217 codes, so they are registered here explicitly.
+var CryptoCurrencies = map[string]int32{
+	"BTC": 8,  // Satoshi
+	"BCH": 8,  // Bitcoin Cash
+	"LTC": 8,  // Litecoin
+	"ETH": 18, // Wei
+	"SOL": 9,  // Lamport
+	"XRP": 6,  // Drop
+	"ADA": 6,  // Lovelace
+	"DOT": 10, // Planck
+	"XMR": 12, // Piconero
+}
+
+// currencyDecimals overrides the display precision of specific codes.
+// Codes not listed here use their CryptoCurrencies precision, or 2.
+var currencyDecimals = map[string]int32{}
+
+// IsCryptoCurrency reports whether code is a recognized cryptocurrency code.
+func IsCryptoCurrency(code string) bool {
+	_, ok := CryptoCurrencies[code]
+	return ok
+}
+
+// CurrencyDecimals returns the number of decimal places shown for a currency code.
+// Fiat currencies use 2; cryptocurrencies use their registered precision (8 for BTC).
+func CurrencyDecimals(code string) int32 {
+	if places, ok := currencyDecimals[code]; ok {
+		return places
+	}
+	if places, ok := CryptoCurrencies[code]; ok {
+		return places
+	}
+	return 2
+}
+
+// SetCurrencyDecimals overrides the display precision for a currency code,
+// e.g. SetCurrencyDecimals("ETH", 6) to show ETH to 6 places instead of 18.
+// Not safe for concurrent use; call during startup configuration.
+func SetCurrencyDecimals(code string, places int32) {
+	currencyDecimals[NormalizeCurrencyCode(code)] = places
 }
 
 // IsCurrencyCode checks if a string is a known currency code or symbol.
@@ -88,7 +156,7 @@ func IsCurrencyCode(s string) bool {
 	if _, ok := CodeToSymbol[s]; ok {
 		return true
 	}
-	return false
+	return IsCryptoCurrency(s)
 }
 
 // NormalizeCurrencyCode converts a symbol or code to its ISO code.
@@ -0,0 +1,160 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sample counts for distributions.
+const (
+	// DefaultSamples is how many samples a distribution draws unless the
+	// document's frontmatter sets samples.
+	DefaultSamples = 1000
+
+	// MaxSamples caps the samples per distribution, so a document can't
+	// make every arithmetic operation allocate without bound.
+	MaxSamples = 100_000
+)
+
+// Distribution is an uncertain value, such as normal(100, 15), held as
+// random samples. Arithmetic applies to each sample in turn, pairing the
+// samples of two distributions in draw order, so results carry the
+// uncertainty of their inputs (Monte Carlo simulation).
+//
+// Samples are numbers, currencies, quantities, or durations, all of the
+// same kind and unit.
+type Distribution struct {
+	Samples []Type
+
+	sortOnce sync.Once
+	sorted   []decimal.Decimal // Sample values in ascending order, for percentiles
+}
+
+// NewDistribution creates a Distribution from samples in draw order.
+func NewDistribution(samples []Type) *Distribution {
+	return &Distribution{Samples: samples}
+}
+
+// Len returns the number of samples.
+func (d *Distribution) Len() int {
+	return len(d.Samples)
+}
+
+// Mean returns the mean of the samples, in their kind and unit.
+func (d *Distribution) Mean() Type {
+	if len(d.Samples) == 0 {
+		return nil
+	}
+	sum := decimal.Zero
+	for _, s := range d.Samples {
+		sum = sum.Add(SampleValue(s))
+	}
+	return WithSampleValue(d.Samples[0], sum.Div(decimal.NewFromInt(int64(len(d.Samples)))))
+}
+
+// Percentile returns the sample at percentile p (0 to 100) by the
+// nearest-rank method, in the samples' kind and unit.
+func (d *Distribution) Percentile(p float64) Type {
+	if len(d.Samples) == 0 {
+		return nil
+	}
+	d.sortOnce.Do(func() {
+		d.sorted = make([]decimal.Decimal, len(d.Samples))
+		for i, s := range d.Samples {
+			d.sorted[i] = SampleValue(s)
+		}
+		slices.SortFunc(d.sorted, decimal.Decimal.Cmp)
+	})
+	rank := int(math.Ceil(p / 100 * float64(len(d.sorted))))
+	return WithSampleValue(d.Samples[0], d.sorted[max(0, min(rank-1, len(d.sorted)-1))])
+}
+
+// DistributionSummary describes a distribution by its mean and the
+// percentiles results show, rounded for display.
+type DistributionSummary struct {
+	Samples int
+	Mean    Type
+	P5      Type
+	P50     Type
+	P95     Type
+}
+
+// Summary returns the mean and the 5th, 50th, and 95th percentiles.
+func (d *Distribution) Summary() DistributionSummary {
+	round := func(t Type) Type {
+		if t == nil {
+			return nil
+		}
+		return WithSampleValue(t, roundSummary(SampleValue(t)))
+	}
+	return DistributionSummary{
+		Samples: len(d.Samples),
+		Mean:    round(d.Mean()),
+		P5:      round(d.Percentile(5)),
+		P50:     round(d.Percentile(50)),
+		P95:     round(d.Percentile(95)),
+	}
+}
+
+// String returns the mean and the range 90% of samples fall in:
+// "~100.2 (p5 75.4, p95 124.9)".
+func (d *Distribution) String() string {
+	if len(d.Samples) == 0 {
+		return "~? (no samples)"
+	}
+	s := d.Summary()
+	return fmt.Sprintf("~%s (p5 %s, p95 %s)", s.Mean, s.P5, s.P95)
+}
+
+// roundSummary rounds a summary value to two decimal places, or to four
+// significant digits when it is smaller than one.
+func roundSummary(v decimal.Decimal) decimal.Decimal {
+	if v.IsZero() || v.Abs().GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return v.Round(2)
+	}
+	f, _ := v.Abs().Float64()
+	return v.Round(int32(3 - math.Floor(math.Log10(f))))
+}
+
+// IsSample reports whether t can be a distribution's sample.
+func IsSample(t Type) bool {
+	switch t.(type) {
+	case *Number, *Currency, *Quantity, *Duration:
+		return true
+	}
+	return false
+}
+
+// SampleValue returns the magnitude of a sample, without its unit.
+// It returns zero for values that can't be samples (see IsSample).
+func SampleValue(t Type) decimal.Decimal {
+	switch v := t.(type) {
+	case *Number:
+		return v.Value
+	case *Currency:
+		return v.Value
+	case *Quantity:
+		return v.Value
+	case *Duration:
+		return v.Value
+	}
+	return decimal.Zero
+}
+
+// WithSampleValue returns a value of like's kind and unit with magnitude v.
+// It returns a Number for values that can't be samples (see IsSample).
+func WithSampleValue(like Type, v decimal.Decimal) Type {
+	switch l := like.(type) {
+	case *Currency:
+		return &Currency{Value: v, Symbol: l.Symbol, Code: l.Code}
+	case *Quantity:
+		return NewQuantity(v, l.Unit)
+	case *Duration:
+		return &Duration{Value: v, Unit: l.Unit}
+	}
+	return NewNumber(v)
+}
@@ -12,6 +12,7 @@
 //   - Time: Time of day with timezone support
 //   - Duration: Time durations (e.g., "5 days", "3 hours")
 //   - Boolean: True/false values
+//   - List: Ordered collections of values (e.g., [10, 20, 30])
 //
 // # Number Type
 //
@@ -20,6 +21,11 @@
 //	num := types.NewNumber(decimal.NewFromFloat(3.14159))
 //	fmt.Println(num.String()) // "3.14159"
 //
+// Add, Sub, Mul, and Div compute on int64 coefficients when both operands
+// fit, falling back to decimal arithmetic with identical results:
+//
+//	total := price.Mul(qty).Add(shipping)
+//
 // Supports thousands separators:
 //
 //	1,000
@@ -32,8 +38,10 @@
 //	usd := types.NewCurrency(decimal.NewFromInt(100), "$")
 //	eur := types.NewCurrencyFromCode(decimal.NewFromInt(50), "EUR")
 //
-// Supported symbols: $, €, £, ¥
+// Supported symbols: $, €, £, ¥, ₿
 // Supports ISO 4217 currency codes (e.g., USD, EUR, GBP, JPY)
+// and cryptocurrency codes (BTC, ETH, ...), which display at their own
+// precision (8 places for BTC); see CryptoCurrencies.
 //
 // # Quantity Type
 //
@@ -55,6 +63,12 @@
 //	nextWeek := today.AddDays(7)
 //	daysBetween := today.DaysBetween(nextWeek) // 7
 //
+// A Calendar skips weekends and holidays when counting business days:
+//
+//	us, _ := types.NewRegionCalendar("US")
+//	due, _ := us.AddBusinessDays(today, 10)
+//	workdays := us.BusinessDaysBetween(today, due)
+//
 // # Time Type
 //
 // Time of day with timezone support:
@@ -83,6 +97,19 @@
 //   - Currency + Currency (different) → Number (drops units)
 //   - Quantity + Quantity (compatible) → Quantity (first unit wins)
 //
+// # Structured Values
+//
+// Every type's ToValue returns a Value, its structured form: a Kind, the
+// display string, decimals as strings at full precision, and the parts of
+// dates, times, lists, schedules, and distributions. Frontends serialize
+// results through it rather than switching on their types:
+//
+//	v := result.ToValue()
+//	if v.Kind == types.KindCurrency {
+//		fmt.Println(v.Decimal, v.CurrencyCode) // "12.5 USD"
+//	}
+//	data, err := v.ToJSON()
+//
 // # Performance
 //
 // All type operations are designed for speed and use efficient decimal
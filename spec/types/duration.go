@@ -7,12 +7,18 @@ import (
 )
 
 // Duration represents a time duration with a specific unit.
-// Supports: days, hours, minutes, seconds, weeks, months, years.
+// Supports: days, hours, minutes, seconds, weeks, months, years, and
+// business days.
 type Duration struct {
 	Value decimal.Decimal
-	Unit  string // "days", "hours", "minutes", "seconds", "weeks", "months", "years"
+	Unit  string // "days", "hours", "minutes", "seconds", "weeks", "months", "years", "business day"
 }
 
+// BusinessDayUnit is the unit of durations counted in business days. Date
+// arithmetic skips weekends and holidays for them (see Calendar); elsewhere
+// a business day counts as one day.
+const BusinessDayUnit = "business day"
+
 // DurationToSeconds provides conversion factors to seconds (approximate for months/years).
 // Uses int64 for whole-second units. Milliseconds are handled separately in conversion functions.
 var DurationToSeconds = map[string]int64{
@@ -30,26 +36,32 @@ var DurationToSeconds = map[string]int64{
 	"months":  2592000,  // 30 days
 	"year":    31536000, // 365 days
 	"years":   31536000, // 365 days
+
+	// A business day is a day of work, for rates such as $500/day
+	BusinessDayUnit: 86400,
+	"business days": 86400,
 }
 
 // durationToSecondsDecimal provides decimal conversion factors including sub-second units.
 var durationToSecondsDecimal = map[string]decimal.Decimal{
-	"millisecond":  decimal.NewFromFloat(0.001),
-	"milliseconds": decimal.NewFromFloat(0.001),
-	"second":       decimal.NewFromInt(1),
-	"seconds":      decimal.NewFromInt(1),
-	"minute":       decimal.NewFromInt(60),
-	"minutes":      decimal.NewFromInt(60),
-	"hour":         decimal.NewFromInt(3600),
-	"hours":        decimal.NewFromInt(3600),
-	"day":          decimal.NewFromInt(86400),
-	"days":         decimal.NewFromInt(86400),
-	"week":         decimal.NewFromInt(604800),
-	"weeks":        decimal.NewFromInt(604800),
-	"month":        decimal.NewFromInt(2592000),
-	"months":       decimal.NewFromInt(2592000),
-	"year":         decimal.NewFromInt(31536000),
-	"years":        decimal.NewFromInt(31536000),
+	"millisecond":   decimal.NewFromFloat(0.001),
+	"milliseconds":  decimal.NewFromFloat(0.001),
+	"second":        decimal.NewFromInt(1),
+	"seconds":       decimal.NewFromInt(1),
+	"minute":        decimal.NewFromInt(60),
+	"minutes":       decimal.NewFromInt(60),
+	"hour":          decimal.NewFromInt(3600),
+	"hours":         decimal.NewFromInt(3600),
+	"day":           decimal.NewFromInt(86400),
+	"days":          decimal.NewFromInt(86400),
+	"week":          decimal.NewFromInt(604800),
+	"weeks":         decimal.NewFromInt(604800),
+	"month":         decimal.NewFromInt(2592000),
+	"months":        decimal.NewFromInt(2592000),
+	"year":          decimal.NewFromInt(31536000),
+	"years":         decimal.NewFromInt(31536000),
+	BusinessDayUnit: decimal.NewFromInt(86400),
+	"business days": decimal.NewFromInt(86400),
 }
 
 // isValidDurationUnit checks if the unit is a valid duration unit.
@@ -80,6 +92,11 @@ func NewDurationFromString(s string, unit string) (*Duration, error) {
 	return NewDuration(value, unit)
 }
 
+// IsBusinessDays reports whether the duration counts business days.
+func (d *Duration) IsBusinessDays() bool {
+	return d.Unit == BusinessDayUnit || d.Unit == "business days"
+}
+
 // String returns the string representation.
 func (d *Duration) String() string {
 	return fmt.Sprintf("%s %s", d.Value.String(), d.Unit)
@@ -0,0 +1,28 @@
+package types
+
+import "strings"
+
+// List represents an ordered collection of values (e.g., [10, 20, 30]).
+// Lists are primarily used as arguments to aggregate functions like sum() and avg().
+type List struct {
+	Elements []Type
+}
+
+// NewList creates a new List with the given elements.
+func NewList(elements []Type) *List {
+	return &List{Elements: elements}
+}
+
+// Len returns the number of elements in the list.
+func (l *List) Len() int {
+	return len(l.Elements)
+}
+
+// String returns the elements in bracket notation: "[10, 20, 30]".
+func (l *List) String() string {
+	parts := make([]string, len(l.Elements))
+	for i, elem := range l.Elements {
+		parts[i] = elem.String()
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -1,16 +1,58 @@
 package types
 
-import "github.com/shopspring/decimal"
+import (
+	"math"
+	"math/bits"
+
+	"github.com/shopspring/decimal"
+)
 
 // Number represents an arbitrary-precision decimal number.
 // It uses the shopspring/decimal package for accurate decimal arithmetic.
+//
+// Numbers made by NewNumber also keep Value's coefficient as an int64 when
+// it fits, so Add, Sub, and Mul on two such numbers can use machine
+// arithmetic. Results are the same decimals either way: an operation whose
+// result would overflow an int64 falls back to decimal arithmetic.
 type Number struct {
 	Value decimal.Decimal
+
+	coef    int64 // Value's coefficient, when small
+	exp     int32 // Value's exponent, when small
+	small   bool
+	percent bool // Displayed as a percentage, 25% for 0.25
 }
 
+// maxSmallDigits is the most digits a coefficient can have and still be
+// kept as an int64.
+const maxSmallDigits = 18
+
 // NewNumber creates a new Number from a decimal.Decimal value.
 func NewNumber(value decimal.Decimal) *Number {
-	return &Number{Value: value}
+	n := &Number{Value: value}
+	if value.NumDigits() <= maxSmallDigits {
+		n.coef, n.exp, n.small = value.CoefficientInt64(), value.Exponent(), true
+	}
+	return n
+}
+
+// NewPercentage creates a Number displayed as a percentage: 0.25 shows as
+// 25%. It is a plain number otherwise, and arithmetic on it gives plain
+// numbers.
+func NewPercentage(value decimal.Decimal) *Number {
+	n := NewNumber(value)
+	n.percent = true
+	return n
+}
+
+// IsPercentage reports whether n is displayed as a percentage.
+func (n *Number) IsPercentage() bool {
+	return n.percent
+}
+
+// newSmallNumber creates a Number from an int64 coefficient and exponent.
+func newSmallNumber(coef int64, exp int32) *Number {
+	return &Number{Value: decimal.New(coef, exp), coef: coef, exp: exp, small: true}
 }
 
 // NewNumberFromString creates a Number from a string representation.
@@ -20,7 +62,168 @@ func NewNumberFromString(s string) (*Number, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Number{Value: value}, nil
+	return NewNumber(value), nil
+}
+
+// Add returns n + m.
+func (n *Number) Add(m *Number) *Number {
+	if a, b, exp, ok := alignSmall(n, m); ok {
+		if sum, ok := addInt64(a, b); ok {
+			return newSmallNumber(sum, exp)
+		}
+	}
+	return NewNumber(n.Value.Add(m.Value))
+}
+
+// Sub returns n - m.
+func (n *Number) Sub(m *Number) *Number {
+	if a, b, exp, ok := alignSmall(n, m); ok && b != math.MinInt64 {
+		if diff, ok := addInt64(a, -b); ok {
+			return newSmallNumber(diff, exp)
+		}
+	}
+	return NewNumber(n.Value.Sub(m.Value))
+}
+
+// Mul returns n * m.
+func (n *Number) Mul(m *Number) *Number {
+	if n.small && m.small {
+		exp := int64(n.exp) + int64(m.exp)
+		if product, ok := mulInt64(n.coef, m.coef); ok && exp >= math.MinInt32 && exp <= math.MaxInt32 {
+			return newSmallNumber(product, int32(exp))
+		}
+	}
+	return NewNumber(n.Value.Mul(m.Value))
+}
+
+// Div returns n / m rounded to decimal.DivisionPrecision places, as
+// decimal.Decimal.Div does. Like Div, it panics if m is zero.
+func (n *Number) Div(m *Number) *Number {
+	return n.DivRound(m, int32(decimal.DivisionPrecision))
+}
+
+// DivRound returns n / m rounded to precision places, as
+// decimal.Decimal.DivRound does. It panics if m is zero.
+func (n *Number) DivRound(m *Number, precision int32) *Number {
+	if n.small && m.small && m.coef != 0 && n.coef != math.MinInt64 && m.coef != math.MinInt64 {
+		if q, ok := divSmall(n.coef, n.exp, m.coef, m.exp, precision); ok {
+			return newSmallNumber(q, -precision)
+		}
+	}
+	return NewNumber(n.Value.DivRound(m.Value, precision))
+}
+
+// divSmall divides a×10^ea by b×10^eb, giving the coefficient of the
+// quotient at exponent -precision rounded half away from zero, if every
+// intermediate fits in 64 bits.
+func divSmall(a int64, ea int32, b int64, eb int32, precision int32) (int64, bool) {
+	ua, ub := uint64(abs64(a)), uint64(abs64(b))
+
+	// Scale the dividend or divisor so the quotient has exponent -precision
+	e := int64(ea) - int64(eb) + int64(precision)
+	var quo, rem uint64
+	if e >= 0 {
+		if e >= int64(len(pow10)) {
+			return 0, false
+		}
+		hi, lo := bits.Mul64(ua, pow10[e])
+		if hi >= ub {
+			return 0, false
+		}
+		quo, rem = bits.Div64(hi, lo, ub)
+	} else {
+		if -e >= int64(len(pow10)) {
+			return 0, false
+		}
+		hi, scaled := bits.Mul64(ub, pow10[-e])
+		if hi != 0 {
+			return 0, false
+		}
+		ub = scaled
+		quo, rem = ua/ub, ua%ub
+	}
+
+	if quo >= math.MaxInt64 {
+		return 0, false // Rounding up could overflow
+	}
+	if rem >= ub-rem {
+		quo++
+	}
+	if (a < 0) != (b < 0) {
+		return -int64(quo), true
+	}
+	return int64(quo), true
+}
+
+// pow10 holds the powers of ten that fit in a uint64.
+var pow10 = func() []uint64 {
+	p := []uint64{1}
+	for range 19 {
+		p = append(p, p[len(p)-1]*10)
+	}
+	return p
+}()
+
+// abs64 returns |v|; v must not be math.MinInt64.
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// alignSmall returns the coefficients of two small numbers scaled to their
+// smaller exponent, as decimal addition does, if they fit in an int64.
+func alignSmall(n, m *Number) (a, b int64, exp int32, ok bool) {
+	if !n.small || !m.small {
+		return 0, 0, 0, false
+	}
+	a, b, exp = n.coef, m.coef, n.exp
+	switch {
+	case n.exp > m.exp:
+		a, ok = scaleInt64(a, int64(n.exp)-int64(m.exp))
+		exp = m.exp
+	case m.exp > n.exp:
+		b, ok = scaleInt64(b, int64(m.exp)-int64(n.exp))
+	default:
+		ok = true
+	}
+	return a, b, exp, ok
+}
+
+// scaleInt64 returns v * 10^digits if it fits in an int64.
+func scaleInt64(v, digits int64) (int64, bool) {
+	if digits > maxSmallDigits {
+		return 0, v == 0
+	}
+	for range digits {
+		var ok bool
+		if v, ok = mulInt64(v, 10); !ok {
+			return 0, false
+		}
+	}
+	return v, true
+}
+
+// addInt64 returns a + b if it does not overflow.
+func addInt64(a, b int64) (int64, bool) {
+	sum := a + b
+	if (a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// mulInt64 returns a * b if it does not overflow.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
 }
 
 // String returns the string representation of the number.
@@ -64,6 +267,10 @@ func typeName(t Type) string {
 		return "Duration"
 	case *Rate:
 		return "Rate"
+	case *List:
+		return "List"
+	case *Schedule:
+		return "Schedule"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// TestNumberArithmetic checks that the small-value fast path gives exactly
+// the decimals (coefficient and exponent) that decimal arithmetic does.
+func TestNumberArithmetic(t *testing.T) {
+	values := []string{
+		"0", "1", "-1", "7", "-3", "0.5", "2.25", "-0.001", "100", "1e5", "1.5e-12",
+		"999999999999999999", "-999999999999999999", "123456789.123456789",
+		"0.333333333333333333", "1e30", "9223372036854775807", "12345678901234567890",
+	}
+	rng := rand.New(rand.NewSource(1))
+	for range 200 {
+		values = append(values, decimal.New(rng.Int63n(2_000_000)-1_000_000, int32(rng.Intn(12)-8)).String())
+	}
+
+	ops := []struct {
+		name string
+		fast func(a, b *Number) *Number
+		slow func(a, b decimal.Decimal) decimal.Decimal
+	}{
+		{"+", (*Number).Add, decimal.Decimal.Add},
+		{"-", (*Number).Sub, decimal.Decimal.Sub},
+		{"*", (*Number).Mul, decimal.Decimal.Mul},
+		{"/", (*Number).Div, decimal.Decimal.Div},
+	}
+
+	for _, op := range ops {
+		for i, x := range values {
+			for _, y := range values[max(0, i-20):min(len(values), i+20)] {
+				a, b := mustNumber(t, x), mustNumber(t, y)
+				if op.name == "/" && b.Value.IsZero() {
+					continue
+				}
+				got := op.fast(a, b)
+				want := op.slow(a.Value, b.Value)
+				if !got.Value.Equal(want) || got.Value.Exponent() != want.Exponent() {
+					t.Fatalf("%s %s %s = %s (exp %d), want %s (exp %d)",
+						x, op.name, y, got.Value, got.Value.Exponent(), want, want.Exponent())
+				}
+				// Results chain into further fast operations
+				if again := got.Add(a); !again.Value.Equal(want.Add(a.Value)) {
+					t.Fatalf("(%s %s %s) + %s = %s, want %s", x, op.name, y, x, again.Value, want.Add(a.Value))
+				}
+			}
+		}
+	}
+}
+
+func TestNumberDivPrecision(t *testing.T) {
+	saved := decimal.DivisionPrecision
+	defer func() { decimal.DivisionPrecision = saved }()
+
+	a, b := mustNumber(t, "2"), mustNumber(t, "3")
+	for _, precision := range []int{0, 4, 16, 40} {
+		decimal.DivisionPrecision = precision
+		got, want := a.Div(b).Value, a.Value.Div(b.Value)
+		if !got.Equal(want) || got.Exponent() != want.Exponent() {
+			t.Errorf("precision %d: 2/3 = %s, want %s", precision, got, want)
+		}
+	}
+}
+
+// TestNumberLiteralFallback checks that Numbers built without NewNumber
+// still work; they just take the decimal path.
+func TestNumberLiteralFallback(t *testing.T) {
+	a := &Number{Value: decimal.NewFromInt(6)}
+	if got := a.Mul(mustNumber(t, "7")).String(); got != "42" {
+		t.Errorf("6 * 7 = %s, want 42", got)
+	}
+}
+
+func mustNumber(t *testing.T, s string) *Number {
+	t.Helper()
+	n, err := NewNumberFromString(s)
+	if err != nil {
+		t.Fatalf("NewNumberFromString(%q) error = %v", s, err)
+	}
+	return n
+}
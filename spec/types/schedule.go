@@ -0,0 +1,211 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences bounds how many dates one schedule query expands to, so a
+// daily schedule over centuries can't exhaust memory.
+const MaxOccurrences = 10000
+
+// Schedule is a recurring set of dates, such as every 2nd Friday or the
+// 15th of every month. Without a Start, a schedule extends forever in both
+// directions; alternating schedules then count from the start of 1970, so
+// the same document always picks the same weeks.
+type Schedule struct {
+	Every   int          // Interval between occurrences, in Unit; at least 1
+	Unit    string       // "day", "week", "month", or "year"
+	Weekday time.Weekday // Day of weekly schedules, when OnDay is set
+	OnDay   bool         // Weekly schedules fall on Weekday
+	Day     int          // Day of the month for monthly schedules, 0 for Start's; months too short use their last day
+	Start   *Date        // First occurrence on or after this date, or nil for no start
+}
+
+// scheduleEpoch anchors schedules without a start: a Monday, January 5 1970.
+var scheduleEpoch = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+// NewSchedule returns a schedule repeating every n units, starting on start
+// (nil for none). Unit is a duration unit such as "week" or "months".
+func NewSchedule(n int, unit string, start *Date) (*Schedule, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("a schedule must repeat at least every 1 %s, got %d", unit, n)
+	}
+	canonical, ok := scheduleUnits[strings.ToLower(unit)]
+	if !ok {
+		return nil, fmt.Errorf("cannot repeat every %s: use days, weeks, months, or years", unit)
+	}
+	return &Schedule{Every: n, Unit: canonical, Start: start}, nil
+}
+
+var scheduleUnits = map[string]string{
+	"day": "day", "days": "day",
+	"week": "week", "weeks": "week",
+	"month": "month", "months": "month",
+	"year": "year", "years": "year",
+}
+
+// String describes the schedule in CalcMark syntax, e.g.
+// "every 2nd Friday from Jan 9 2026".
+func (s *Schedule) String() string {
+	var b strings.Builder
+	b.WriteString("every ")
+	switch {
+	case s.OnDay && s.Every == 1:
+		b.WriteString(s.Weekday.String())
+	case s.OnDay:
+		fmt.Fprintf(&b, "%s %s", Ordinal(s.Every), s.Weekday)
+	case s.Unit == "month" && s.Day > 0 && s.Every == 1:
+		b.WriteString(Ordinal(s.Day))
+	case s.Every == 1:
+		b.WriteString(s.Unit)
+	default:
+		fmt.Fprintf(&b, "%d %ss", s.Every, s.Unit)
+	}
+	if s.Unit == "month" && s.Day > 0 && (s.Every > 1 || s.OnDay) {
+		fmt.Fprintf(&b, " on the %s", Ordinal(s.Day))
+	}
+	if s.Start != nil {
+		b.WriteString(" from ")
+		b.WriteString(s.Start.Time.Format("Jan 2 2006"))
+	}
+	return b.String()
+}
+
+// Ordinal writes n as an English ordinal: 1st, 2nd, 3rd, 4th, 11th, 22nd.
+func Ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// Next returns the first occurrence after d.
+func (s *Schedule) Next(d *Date) *Date {
+	return &Date{Time: s.occurrence(s.indexFrom(d.Time.AddDate(0, 0, 1)))}
+}
+
+// Between returns the occurrences from one date to another, including both.
+// It fails rather than return more than MaxOccurrences dates.
+func (s *Schedule) Between(from, to *Date) ([]*Date, error) {
+	var dates []*Date
+	for k := s.indexFrom(from.Time); ; k++ {
+		t := s.occurrence(k)
+		if t.After(to.Time) {
+			return dates, nil
+		}
+		if len(dates) == MaxOccurrences {
+			return nil, fmt.Errorf("%s has more than %d dates between %s and %s",
+				s, MaxOccurrences, from.ShortString(), to.ShortString())
+		}
+		dates = append(dates, &Date{Time: t})
+	}
+}
+
+// occurrence returns the schedule's kth date, counted from its anchor.
+func (s *Schedule) occurrence(k int) time.Time {
+	switch s.Unit {
+	case "month", "year":
+		month := s.anchorMonth() + k*s.months()
+		return clampDay(month/12, time.Month(month%12+1), s.monthDay())
+	default:
+		return s.anchorDay().AddDate(0, 0, k*s.days())
+	}
+}
+
+// indexFrom returns the index of the first occurrence on or after t, and
+// on or after the start.
+func (s *Schedule) indexFrom(t time.Time) int {
+	var k int
+	switch s.Unit {
+	case "month", "year":
+		k = floorDiv(t.Year()*12+int(t.Month())-1-s.anchorMonth(), s.months())
+	default:
+		k = floorDiv(int((t.Unix()-s.anchorDay().Unix())/86400), s.days())
+	}
+	for s.occurrence(k).Before(t) || (s.Start != nil && s.occurrence(k).Before(s.Start.Time)) {
+		k++
+	}
+	return k
+}
+
+// anchorDay is the occurrence daily and weekly schedules count from.
+func (s *Schedule) anchorDay() time.Time {
+	anchor := scheduleEpoch
+	if s.Start != nil {
+		anchor = s.Start.Time
+	}
+	if s.OnDay {
+		anchor = anchor.AddDate(0, 0, (int(s.Weekday)-int(anchor.Weekday())+7)%7)
+	}
+	return anchor
+}
+
+// anchorMonth is the month, counted from year 0, that monthly and yearly
+// schedules count from.
+func (s *Schedule) anchorMonth() int {
+	anchor := scheduleEpoch
+	if s.Start != nil {
+		anchor = s.Start.Time
+	}
+	return anchor.Year()*12 + int(anchor.Month()) - 1
+}
+
+func (s *Schedule) monthDay() int {
+	switch {
+	case s.Day > 0:
+		return s.Day
+	case s.Start != nil:
+		return s.Start.Time.Day()
+	}
+	return 1
+}
+
+func (s *Schedule) days() int {
+	if s.Unit == "week" {
+		return 7 * s.Every
+	}
+	return s.Every
+}
+
+func (s *Schedule) months() int {
+	if s.Unit == "year" {
+		return 12 * s.Every
+	}
+	return s.Every
+}
+
+// clampDay returns the given day of a month, or the month's last day if it
+// is shorter.
+func clampDay(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(year, month, min(day, last), 0, 0, 0, 0, time.UTC)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// ScheduleNames returns the variable names a schedule may be called by in
+// queries, given the name used: itself, then plurals, so "next payday"
+// finds paydays and "next delivery" finds deliveries.
+func ScheduleNames(name string) []string {
+	names := []string{name, name + "s", name + "es"}
+	if stem, ok := strings.CutSuffix(name, "y"); ok {
+		names = append(names, stem+"ies")
+	}
+	return names
+}
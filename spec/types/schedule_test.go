@@ -0,0 +1,131 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	friday := func(every int, start *Date) *Schedule {
+		s, _ := NewSchedule(every, "week", start)
+		s.Weekday, s.OnDay = time.Friday, true
+		return s
+	}
+	monthly := func(day int) *Schedule {
+		s, _ := NewSchedule(1, "month", nil)
+		s.Day = day
+		return s
+	}
+	yearly, _ := NewSchedule(1, "year", mustDate(t, 2020, 2, 29))
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		after    *Date
+		want     string
+	}{
+		{"every Friday", friday(1, nil), mustDate(t, 2026, 1, 5), "2026-01-09"},
+		{"strictly after", friday(1, nil), mustDate(t, 2026, 1, 9), "2026-01-16"},
+		{"every 2nd Friday from start", friday(2, mustDate(t, 2026, 1, 9)), mustDate(t, 2026, 1, 10), "2026-01-23"},
+		{"before the start", friday(2, mustDate(t, 2026, 1, 9)), mustDate(t, 2025, 6, 1), "2026-01-09"},
+		{"start isn't the weekday", friday(2, mustDate(t, 2026, 1, 5)), mustDate(t, 2026, 1, 1), "2026-01-09"},
+		{"15th", monthly(15), mustDate(t, 2026, 1, 31), "2026-02-15"},
+		{"31st in a short month", monthly(31), mustDate(t, 2026, 2, 1), "2026-02-28"},
+		{"31st after a short month", monthly(31), mustDate(t, 2026, 2, 28), "2026-03-31"},
+		{"leap day", yearly, mustDate(t, 2026, 1, 1), "2026-02-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(tt.after).Time.Format(time.DateOnly); got != tt.want {
+				t.Errorf("Next(%s) = %s, want %s", tt.after.ShortString(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleBetween(t *testing.T) {
+	paydays, _ := NewSchedule(2, "week", mustDate(t, 2026, 1, 9))
+	paydays.Weekday, paydays.OnDay = time.Friday, true
+
+	dates, err := paydays.Between(mustDate(t, 2026, 3, 1), mustDate(t, 2026, 6, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range dates {
+		got = append(got, d.Time.Format("Jan 2"))
+	}
+	want := "Mar 6, Mar 20, Apr 3, Apr 17, May 1, May 15, May 29, Jun 12, Jun 26"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("Between = %s, want %s", strings.Join(got, ", "), want)
+	}
+
+	// Both ends are included
+	dates, _ = paydays.Between(mustDate(t, 2026, 3, 6), mustDate(t, 2026, 3, 20))
+	if len(dates) != 2 {
+		t.Errorf("expected both ends to count, got %d dates", len(dates))
+	}
+
+	daily, _ := NewSchedule(1, "day", nil)
+	if _, err := daily.Between(mustDate(t, 1900, 1, 1), mustDate(t, 2100, 1, 1)); err == nil {
+		t.Errorf("expected an error beyond %d dates", MaxOccurrences)
+	}
+}
+
+func TestScheduleString(t *testing.T) {
+	weekly := func(every int, day time.Weekday) *Schedule {
+		s, _ := NewSchedule(every, "week", nil)
+		s.Weekday, s.OnDay = day, true
+		return s
+	}
+	onDay := func(every, day int) *Schedule {
+		s, _ := NewSchedule(every, "months", nil)
+		s.Day = day
+		return s
+	}
+	fortnightly, _ := NewSchedule(2, "weeks", mustDate(t, 2026, 1, 9))
+	daily, _ := NewSchedule(1, "day", nil)
+
+	tests := []struct {
+		schedule *Schedule
+		want     string
+	}{
+		{weekly(1, time.Friday), "every Friday"},
+		{weekly(2, time.Friday), "every 2nd Friday"},
+		{onDay(1, 15), "every 15th"},
+		{onDay(3, 1), "every 3 months on the 1st"},
+		{fortnightly, "every 2 weeks from Jan 9 2026"},
+		{daily, "every day"},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+
+	if _, err := NewSchedule(0, "week", nil); err == nil {
+		t.Error("expected an error for every 0 weeks")
+	}
+	if _, err := NewSchedule(1, "hour", nil); err == nil {
+		t.Error("expected an error for every hour")
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 111: "111th"} {
+		if got := Ordinal(n); got != want {
+			t.Errorf("Ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestScheduleNames(t *testing.T) {
+	if got := strings.Join(ScheduleNames("delivery"), " "); got != "delivery deliverys deliveryes deliveries" {
+		t.Errorf("ScheduleNames(delivery) = %s", got)
+	}
+	if got := ScheduleNames("payday"); got[1] != "paydays" {
+		t.Errorf("ScheduleNames(payday) = %v, want paydays second", got)
+	}
+}
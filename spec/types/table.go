@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Table is rows of values under named columns, such as the periods of an
+// amortization schedule. Indexing a table, as in schedule[12], gives a
+// one-row table, and naming a column, as in schedule[12].balance, gives
+// the value in it.
+type Table struct {
+	Columns []string
+	Rows    [][]Type // Each with a value per column
+}
+
+// NewTable creates a table with the given columns and rows.
+func NewTable(columns []string, rows [][]Type) *Table {
+	return &Table{Columns: columns, Rows: rows}
+}
+
+// Len returns the number of rows in the table.
+func (t *Table) Len() int {
+	return len(t.Rows)
+}
+
+// Column returns the position of the column named name.
+func (t *Table) Column(name string) (int, bool) {
+	i := slices.Index(t.Columns, name)
+	return i, i >= 0
+}
+
+// String describes a one-row table by its values, "period 12, balance
+// $295,000.00", and a larger one by its shape, "360 rows of period,
+// payment, interest, principal, balance".
+func (t *Table) String() string {
+	if len(t.Rows) == 1 {
+		parts := make([]string, len(t.Columns))
+		for i, column := range t.Columns {
+			parts[i] = column + " " + t.Rows[0][i].String()
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprintf("%d rows of %s", len(t.Rows), strings.Join(t.Columns, ", "))
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,15 @@ func NewTime(hour, minute, second int, isPM bool, utcOffsetMinutes int) (*Time,
 		hour = 0
 	}
 
+	// Use a reference date (Jan 1, 2000) since we only care about time
+	return NewTimeIn(hour, minute, second, OffsetZone(utcOffsetMinutes), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+// NewTimeIn creates a Time from a 24-hour clock time in a time zone, on the
+// given day, whose daylight saving rules then apply: 3pm in New York is
+// UTC-4 on a July day and UTC-5 on a January one. second is -1 if not
+// specified.
+func NewTimeIn(hour, minute, second int, location *time.Location, day time.Time) (*Time, error) {
 	// Validate ranges
 	if hour < 0 || hour > 23 {
 		return nil, fmt.Errorf("invalid hour: %d (must be 0-23)", hour)
@@ -40,15 +50,95 @@ func NewTime(hour, minute, second int, isPM bool, utcOffsetMinutes int) (*Time,
 		second = 0
 	}
 
-	// Create timezone location
-	location := time.FixedZone("", utcOffsetMinutes*60)
-
-	// Use a reference date (Jan 1, 2000) since we only care about time
-	t := time.Date(2000, 1, 1, hour, minute, second, 0, location)
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, location)
 
 	return &Time{Time: t}, nil
 }
 
+// Hour24 converts an hour written with a period ("AM" or "PM") to the
+// 24-hour clock: 12 AM is 0 and 3 PM is 15. An hour written without a
+// period is already on the 24-hour clock.
+func Hour24(hour int, period string) (int, error) {
+	switch strings.ToUpper(period) {
+	case "":
+		return hour, nil
+	case "AM", "PM":
+		if hour < 1 || hour > 12 {
+			return 0, fmt.Errorf("invalid hour: %d %s (must be 1-12)", hour, period)
+		}
+		hour %= 12
+		if strings.EqualFold(period, "PM") {
+			hour += 12
+		}
+		return hour, nil
+	}
+	return 0, fmt.Errorf("invalid period %q: use AM or PM", period)
+}
+
+// fixedZones are the abbreviations of standard and daylight times, each a
+// fixed offset from UTC whatever the date: EST is UTC-5 even in July, when
+// New York keeps EDT.
+var fixedZones = map[string]*time.Location{
+	"EST": time.FixedZone("EST", -5*3600), "EDT": time.FixedZone("EDT", -4*3600),
+	"CST": time.FixedZone("CST", -6*3600), "CDT": time.FixedZone("CDT", -5*3600),
+	"MST": time.FixedZone("MST", -7*3600), "MDT": time.FixedZone("MDT", -6*3600),
+	"PST": time.FixedZone("PST", -8*3600), "PDT": time.FixedZone("PDT", -7*3600),
+	"AKST": time.FixedZone("AKST", -9*3600), "AKDT": time.FixedZone("AKDT", -8*3600),
+	"HST":  time.FixedZone("HST", -10*3600),
+	"BST":  time.FixedZone("BST", 1*3600),
+	"CEST": time.FixedZone("CEST", 2*3600),
+	"EEST": time.FixedZone("EEST", 3*3600),
+	"IST":  time.FixedZone("IST", 5*3600+30*60),
+	"JST":  time.FixedZone("JST", 9*3600), "KST": time.FixedZone("KST", 9*3600),
+	"AEST": time.FixedZone("AEST", 10*3600), "AEDT": time.FixedZone("AEDT", 11*3600),
+	"NZST": time.FixedZone("NZST", 12*3600), "NZDT": time.FixedZone("NZDT", 13*3600),
+}
+
+// LoadTimeZone returns the location of an IANA time zone name such as
+// "Asia/Tokyo", or of a standard or daylight time abbreviation such as
+// "PST", which is a fixed offset. "UTC" names a zone shown as UTC, unlike
+// times written without one.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "UTC" {
+		return time.FixedZone("UTC", 0), nil
+	}
+	if location, ok := fixedZones[name]; ok {
+		return location, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return location, nil
+}
+
+// In returns the same instant in another time zone. The day can change:
+// 9:00 in Tokyo is 00:00 UTC, and 8:00 is 23:00 UTC the day before.
+func (t *Time) In(location *time.Location) *Time {
+	return &Time{Time: t.Time.In(location)}
+}
+
+// OffsetZone returns a zone at a fixed offset from UTC in minutes, named
+// as written: "UTC-7", "UTC+5:30". Offset 0 is UTC, for times written
+// without a zone.
+func OffsetZone(minutes int) *time.Location {
+	if minutes == 0 {
+		return time.UTC
+	}
+	return time.FixedZone(offsetName(minutes), minutes*60)
+}
+
+func offsetName(minutes int) string {
+	sign := "+"
+	if minutes < 0 {
+		sign, minutes = "-", -minutes
+	}
+	if minutes%60 != 0 {
+		return fmt.Sprintf("UTC%s%d:%02d", sign, minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("UTC%s%d", sign, minutes/60)
+}
+
 // String returns a human-readable time representation.
 func (t *Time) String() string {
 	// Format based on whether it has timezone info
@@ -1,9 +1,14 @@
 package types
 
 // Type is the interface that all CalcMark value types implement.
-// All types can be converted to a human-readable string representation.
+// All types can be converted to a human-readable string representation
+// and to a structured Value.
 type Type interface {
 	// String returns a human-readable representation of the value.
 	// This is used for display purposes and debugging.
 	String() string
+
+	// ToValue returns the value's structured form, for serializing it
+	// or reading its parts without a type switch.
+	ToValue() Value
 }
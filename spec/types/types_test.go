@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -49,6 +50,10 @@ func TestCurrency(t *testing.T) {
 		{"USD code", "100", "USD", "USD100.00", "USD"},
 		{"GBP code", "50", "GBP", "GBP50.00", "GBP"},
 		{"custom code", "25", "CAD", "CAD25.00", "CAD"},
+		{"bitcoin symbol", "0.5", "₿", "₿0.50", "BTC"},
+		{"bitcoin satoshis", "0.00012345", "BTC", "BTC0.00012345", "BTC"},
+		{"bitcoin rounds to satoshi", "0.123456789", "BTC", "BTC0.12345679", "BTC"},
+		{"ether keeps wei", "1.000000000000000001", "ETH", "ETH1.000000000000000001", "ETH"},
 	}
 
 	for _, tt := range tests {
@@ -82,6 +87,32 @@ func TestCurrencyComparison(t *testing.T) {
 	}
 }
 
+// TestCurrencyDecimals tests per-currency display precision overrides
+func TestCurrencyDecimals(t *testing.T) {
+	if got := CurrencyDecimals("USD"); got != 2 {
+		t.Errorf("CurrencyDecimals(USD) = %d, want 2", got)
+	}
+	if got := CurrencyDecimals("BTC"); got != 8 {
+		t.Errorf("CurrencyDecimals(BTC) = %d, want 8", got)
+	}
+
+	SetCurrencyDecimals("ETH", 4)
+	defer delete(currencyDecimals, "ETH")
+
+	eth := NewCurrency(decimal.RequireFromString("0.123456"), "ETH")
+	if got := eth.String(); got != "ETH0.1235" {
+		t.Errorf("Currency.String() = %v, want ETH0.1235", got)
+	}
+
+	SetCurrencyDecimals("JPY", 0)
+	defer delete(currencyDecimals, "JPY")
+
+	yen := NewCurrency(decimal.NewFromInt(5000), "¥")
+	if got := yen.String(); got != "¥5000" {
+		t.Errorf("Currency.String() = %v, want ¥5000", got)
+	}
+}
+
 // TestBoolean tests the Boolean type
 func TestBoolean(t *testing.T) {
 	trueVal := NewBoolean(true)
@@ -95,6 +126,23 @@ func TestBoolean(t *testing.T) {
 	}
 }
 
+// TestList tests the List type
+func TestList(t *testing.T) {
+	list := NewList([]Type{
+		NewNumber(decimal.NewFromInt(10)),
+		NewNumber(decimal.NewFromInt(20)),
+	})
+	if list.Len() != 2 {
+		t.Errorf("List.Len() = %d, want 2", list.Len())
+	}
+	if list.String() != "[10, 20]" {
+		t.Errorf("List.String() = %v, want '[10, 20]'", list.String())
+	}
+	if typeName(list) != "List" {
+		t.Errorf("typeName(list) = %v, want 'List'", typeName(list))
+	}
+}
+
 // TestDate tests the Date type
 func TestDate(t *testing.T) {
 	tests := []struct {
@@ -177,6 +225,63 @@ func TestTime(t *testing.T) {
 	}
 }
 
+// TestTimeZones tests 12-hour conversion and times in named and fixed zones
+func TestTimeZones(t *testing.T) {
+	for _, tt := range []struct {
+		hour    int
+		period  string
+		want    int
+		wantErr bool
+	}{
+		{12, "AM", 0, false}, {12, "PM", 12, false}, {3, "pm", 15, false},
+		{12, "", 12, false}, {0, "AM", 0, true}, {13, "PM", 0, true},
+	} {
+		got, err := Hour24(tt.hour, tt.period)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("Hour24(%d, %q) = %d, %v; want %d, wantErr %v", tt.hour, tt.period, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if got := OffsetZone(330).String(); got != "UTC+5:30" {
+		t.Errorf("OffsetZone(330) = %q, want UTC+5:30", got)
+	}
+	if got := OffsetZone(-420).String(); got != "UTC-7" {
+		t.Errorf("OffsetZone(-420) = %q, want UTC-7", got)
+	}
+
+	// Named zones follow daylight saving on the time's day
+	newYork, err := LoadTimeZone("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadTimeZone() error = %v", err)
+	}
+	for day, want := range map[time.Month]string{time.January: "20:00:00 UTC", time.July: "19:00:00 UTC"} {
+		tm, err := NewTimeIn(15, 0, 0, newYork, time.Date(2026, day, 15, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("NewTimeIn() error = %v", err)
+		}
+		if got := tm.In(time.FixedZone("UTC", 0)).String(); got != want {
+			t.Errorf("3pm New York in %s = %s, want %s", day, got, want)
+		}
+	}
+
+	// Standard and daylight time abbreviations are fixed offsets
+	est, err := LoadTimeZone("EST")
+	if err != nil {
+		t.Fatalf("LoadTimeZone(EST) error = %v", err)
+	}
+	tm, err := NewTimeIn(15, 0, 0, est, time.Date(2026, time.July, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("NewTimeIn() error = %v", err)
+	}
+	if got := tm.In(time.FixedZone("UTC", 0)).String(); got != "20:00:00 UTC" {
+		t.Errorf("3pm EST in July = %s, want 20:00:00 UTC", got)
+	}
+
+	if _, err := LoadTimeZone("Mars/Olympus_Mons"); err == nil {
+		t.Error("LoadTimeZone() expected error for unknown zone")
+	}
+}
+
 // TestDuration tests the Duration type
 func TestDuration(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,242 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kind names a value's type in Value.
+type Kind string
+
+const (
+	KindNumber       Kind = "number"
+	KindCurrency     Kind = "currency"
+	KindQuantity     Kind = "quantity"
+	KindRate         Kind = "rate"
+	KindDuration     Kind = "duration"
+	KindDate         Kind = "date"
+	KindTime         Kind = "time"
+	KindBoolean      Kind = "boolean"
+	KindList         Kind = "list"
+	KindSchedule     Kind = "schedule"
+	KindDistribution Kind = "distribution"
+	KindBasket       Kind = "basket"
+	KindTable        Kind = "table"
+)
+
+// Value is the structured form of a value, for embedders that serialize
+// or inspect results: every type's ToValue returns one, so frontends
+// describe values the same way without switching on their types.
+// Decimals are strings, keeping full precision that float64 would lose.
+type Value struct {
+	Kind    Kind   `json:"kind"`
+	Display string `json:"display"`           // As String returns it
+	Decimal string `json:"decimal,omitempty"` // The amount of numbers, currencies, quantities, durations, and rates
+	Unit    string `json:"unit,omitempty"`    // Quantity or duration unit, or a rate's amount unit
+
+	PerUnit        string `json:"per_unit,omitempty"`        // A rate's time unit, e.g. "hour"
+	CurrencyCode   string `json:"currency_code,omitempty"`   // ISO 4217 code, e.g. "USD"
+	CurrencySymbol string `json:"currency_symbol,omitempty"` // As written, e.g. "$"
+	RealYear       int    `json:"real_year,omitempty"`       // Year of a real currency amount's prices
+
+	Boolean      *bool              `json:"boolean,omitempty"`
+	Date         *DateParts         `json:"date,omitempty"`
+	Time         *TimeParts         `json:"time,omitempty"`
+	Items        []Value            `json:"items,omitempty"` // A list's elements, or a basket's weights
+	Schedule     *ScheduleParts     `json:"schedule,omitempty"`
+	Distribution *DistributionParts `json:"distribution,omitempty"`
+	Table        *TableParts        `json:"table,omitempty"`
+}
+
+// DateParts is a calendar date.
+type DateParts struct {
+	Year  int `json:"year"`
+	Month int `json:"month"` // 1 to 12
+	Day   int `json:"day"`
+}
+
+// String returns the date in ISO 8601 form, e.g. "2025-03-01".
+func (p DateParts) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", p.Year, p.Month, p.Day)
+}
+
+// TimeParts is a time of day and its offset from UTC.
+type TimeParts struct {
+	Hour             int `json:"hour"` // 0 to 23
+	Minute           int `json:"minute"`
+	Second           int `json:"second"`
+	UTCOffsetMinutes int `json:"utc_offset_minutes"`
+}
+
+// String returns the time in ISO 8601 form without its offset, e.g. "15:04:05".
+func (p TimeParts) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", p.Hour, p.Minute, p.Second)
+}
+
+// ScheduleParts is a recurring schedule.
+type ScheduleParts struct {
+	Every   int        `json:"every"`
+	Unit    string     `json:"unit"`              // "day", "week", "month", or "year"
+	Weekday string     `json:"weekday,omitempty"` // Day of weekly schedules, e.g. "Monday"
+	Day     int        `json:"day,omitempty"`     // Day of the month for monthly schedules
+	Start   *DateParts `json:"start,omitempty"`
+}
+
+// DistributionParts summarizes a distribution, whose Value describes its
+// mean. Decimals are in the mean's unit, rounded as results show them.
+type DistributionParts struct {
+	Samples int    `json:"samples"`
+	Mean    string `json:"mean"`
+	P5      string `json:"p5"`
+	P50     string `json:"p50"`
+	P95     string `json:"p95"`
+}
+
+// TableParts is a table's columns and the values in each row.
+type TableParts struct {
+	Columns []string  `json:"columns"`
+	Rows    [][]Value `json:"rows"`
+}
+
+// ToMap returns v as the map its JSON form decodes to, for bridges such
+// as WASM's js.ValueOf that take plain maps.
+func (v Value) ToMap() map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil // Unreachable: Value holds only strings, numbers, and bools
+	}
+	var m map[string]any
+	_ = json.Unmarshal(data, &m)
+	return m
+}
+
+// ToJSON returns v's JSON form.
+func (v Value) ToJSON() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// ToValue describes n.
+func (n *Number) ToValue() Value {
+	return Value{Kind: KindNumber, Display: n.String(), Decimal: n.Value.String()}
+}
+
+// ToValue describes c.
+func (c *Currency) ToValue() Value {
+	return Value{
+		Kind:           KindCurrency,
+		Display:        c.String(),
+		Decimal:        c.Value.String(),
+		CurrencyCode:   c.Code,
+		CurrencySymbol: c.Symbol,
+		RealYear:       c.RealYear,
+	}
+}
+
+// ToValue describes q.
+func (q *Quantity) ToValue() Value {
+	return Value{Kind: KindQuantity, Display: q.String(), Decimal: q.Value.String(), Unit: q.Unit}
+}
+
+// ToValue describes r.
+func (r *Rate) ToValue() Value {
+	return Value{
+		Kind:    KindRate,
+		Display: r.String(),
+		Decimal: r.Amount.Value.String(),
+		Unit:    r.Amount.Unit,
+		PerUnit: r.PerUnit,
+	}
+}
+
+// ToValue describes d.
+func (d *Duration) ToValue() Value {
+	return Value{Kind: KindDuration, Display: d.String(), Decimal: d.Value.String(), Unit: d.Unit}
+}
+
+// ToValue describes d.
+func (d *Date) ToValue() Value {
+	parts := dateParts(d)
+	return Value{Kind: KindDate, Display: d.String(), Date: &parts}
+}
+
+// ToValue describes t.
+func (t *Time) ToValue() Value {
+	_, offset := t.Time.Zone()
+	return Value{Kind: KindTime, Display: t.String(), Time: &TimeParts{
+		Hour:             t.Time.Hour(),
+		Minute:           t.Time.Minute(),
+		Second:           t.Time.Second(),
+		UTCOffsetMinutes: offset / 60,
+	}}
+}
+
+// ToValue describes b.
+func (b *Boolean) ToValue() Value {
+	value := b.Value
+	return Value{Kind: KindBoolean, Display: b.String(), Boolean: &value}
+}
+
+// ToValue describes l and its elements.
+func (l *List) ToValue() Value {
+	items := make([]Value, len(l.Elements))
+	for i, e := range l.Elements {
+		items[i] = e.ToValue()
+	}
+	return Value{Kind: KindList, Display: l.String(), Items: items}
+}
+
+// ToValue describes s.
+func (s *Schedule) ToValue() Value {
+	parts := &ScheduleParts{Every: s.Every, Unit: s.Unit, Day: s.Day}
+	if s.OnDay {
+		parts.Weekday = s.Weekday.String()
+	}
+	if s.Start != nil {
+		start := dateParts(s.Start)
+		parts.Start = &start
+	}
+	return Value{Kind: KindSchedule, Display: s.String(), Schedule: parts}
+}
+
+// ToValue describes d by its mean, with its summary.
+func (d *Distribution) ToValue() Value {
+	if d.Len() == 0 {
+		return Value{Kind: KindDistribution, Display: d.String()}
+	}
+	s := d.Summary()
+	v := d.Mean().ToValue()
+	v.Kind, v.Display = KindDistribution, d.String()
+	v.Distribution = &DistributionParts{
+		Samples: s.Samples,
+		Mean:    SampleValue(s.Mean).String(),
+		P5:      SampleValue(s.P5).String(),
+		P50:     SampleValue(s.P50).String(),
+		P95:     SampleValue(s.P95).String(),
+	}
+	return v
+}
+
+// ToValue describes b, with a currency item for each weight.
+func (b *Basket) ToValue() Value {
+	items := make([]Value, len(b.Weights))
+	for i, w := range b.Weights {
+		items[i] = NewCurrency(w.Amount, GetCurrencySymbol(w.Code)).ToValue()
+	}
+	return Value{Kind: KindBasket, Display: b.String(), Items: items}
+}
+
+// ToValue describes t with every row's values.
+func (t *Table) ToValue() Value {
+	rows := make([][]Value, len(t.Rows))
+	for i, row := range t.Rows {
+		rows[i] = make([]Value, len(row))
+		for j, v := range row {
+			rows[i][j] = v.ToValue()
+		}
+	}
+	return Value{Kind: KindTable, Display: t.String(), Table: &TableParts{Columns: t.Columns, Rows: rows}}
+}
+
+func dateParts(d *Date) DateParts {
+	return DateParts{Year: d.Time.Year(), Month: int(d.Time.Month()), Day: d.Time.Day()}
+}
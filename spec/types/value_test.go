@@ -0,0 +1,112 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestToValue(t *testing.T) {
+	date, _ := NewDate(2025, 3, 1)
+	clock, _ := NewTime(3, 4, 5, true, -300)
+	minutes, _ := NewDuration(decimal.NewFromInt(90), "minute")
+	rate := NewRate(NewQuantity(decimal.NewFromInt(5), "MB"), "second")
+
+	tests := []struct {
+		name  string
+		value Type
+		check func(t *testing.T, v Value)
+	}{
+		{"number", NewNumber(decimal.RequireFromString("1234567890.123456789")), func(t *testing.T, v Value) {
+			if v.Kind != KindNumber || v.Decimal != "1234567890.123456789" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"currency", NewCurrency(decimal.RequireFromString("12.50"), "$"), func(t *testing.T, v Value) {
+			if v.Kind != KindCurrency || v.Decimal != "12.5" || v.CurrencyCode != "USD" || v.CurrencySymbol != "$" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"quantity", NewQuantity(decimal.NewFromInt(5), "kg"), func(t *testing.T, v Value) {
+			if v.Kind != KindQuantity || v.Decimal != "5" || v.Unit != "kg" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"rate", rate, func(t *testing.T, v Value) {
+			if v.Kind != KindRate || v.Decimal != "5" || v.Unit != "MB" || v.PerUnit != "second" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"duration", minutes, func(t *testing.T, v Value) {
+			if v.Kind != KindDuration || v.Decimal != "90" || v.Unit != "minute" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"date", date, func(t *testing.T, v Value) {
+			if v.Kind != KindDate || v.Date == nil || v.Date.String() != "2025-03-01" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"time", clock, func(t *testing.T, v Value) {
+			if v.Kind != KindTime || v.Time == nil || v.Time.String() != "15:04:05" || v.Time.UTCOffsetMinutes != -300 {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"boolean", NewBoolean(false), func(t *testing.T, v Value) {
+			if v.Kind != KindBoolean || v.Boolean == nil || *v.Boolean {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"list", NewList([]Type{NewNumber(decimal.NewFromInt(1)), NewQuantity(decimal.NewFromInt(2), "m")}), func(t *testing.T, v Value) {
+			if v.Kind != KindList || len(v.Items) != 2 || v.Items[1].Unit != "m" {
+				t.Errorf("got %+v", v)
+			}
+		}},
+		{"distribution", NewDistribution([]Type{
+			NewCurrency(decimal.NewFromInt(10), "$"),
+			NewCurrency(decimal.NewFromInt(20), "$"),
+		}), func(t *testing.T, v Value) {
+			if v.Kind != KindDistribution || v.CurrencyCode != "USD" || v.Distribution == nil || v.Distribution.Samples != 2 {
+				t.Errorf("got %+v", v)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.value.ToValue()
+			if v.Display != tt.value.String() {
+				t.Errorf("Display = %q, want %q", v.Display, tt.value.String())
+			}
+			tt.check(t, v)
+		})
+	}
+}
+
+func TestValueToMapMatchesJSON(t *testing.T) {
+	v := NewCurrency(decimal.RequireFromString("0.1"), "EUR").ToValue()
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	var want map[string]any
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+	got := v.ToMap()
+	if len(got) != len(want) {
+		t.Fatalf("ToMap() = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("ToMap()[%q] = %v, want %v", key, got[key], value)
+		}
+	}
+	if got["decimal"] != "0.1" || got["currency_code"] != "EUR" {
+		t.Errorf("ToMap() = %v", got)
+	}
+	if _, ok := got["date"]; ok {
+		t.Error("ToMap() includes fields that don't apply to currencies")
+	}
+}
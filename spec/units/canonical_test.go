@@ -183,3 +183,32 @@ func TestAllUnitsHaveAliases(t *testing.T) {
 
 // NOTE: Unit conversion tests would go in spec/interpreter or spec/semantic
 // since conversion is semantic, not lexical/syntactic
+
+// TestSuggestUnits tests fuzzy "did you mean" suggestions for unit typos
+func TestSuggestUnits(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // must appear in suggestions
+	}{
+		{"metrs", "meters"},
+		{"kilogrm", "kilogram"},
+		{"galons", "gallons"},
+		{"fet", "feet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := units.SuggestUnits(tt.input, 3)
+			if len(got) > 3 {
+				t.Errorf("SuggestUnits(%q) returned %d suggestions, want at most 3", tt.input, len(got))
+			}
+			if !slices.Contains(got, tt.want) {
+				t.Errorf("SuggestUnits(%q) = %v, want it to contain %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if got := units.SuggestUnits("zzzzzzzz", 3); len(got) != 0 {
+		t.Errorf("SuggestUnits(zzzzzzzz) = %v, want none", got)
+	}
+}
@@ -0,0 +1,48 @@
+package units
+
+import "strings"
+
+// Compound (derived) units are written numerator/denominator, e.g. "kg/L"
+// or "km/h". Rates with a time denominator have their own type (types.Rate);
+// these helpers cover the unit algebra shared by both.
+
+// CompoundSeparator joins the numerator and denominator of a compound unit.
+const CompoundSeparator = "/"
+
+// Compound returns the compound unit numerator/denominator, using standard
+// symbols for known units. Example: Compound("kilograms", "liters") → "kg/L".
+func Compound(numerator, denominator string) string {
+	if denominator == "" {
+		return numerator
+	}
+	return symbolOrName(numerator) + CompoundSeparator + symbolOrName(denominator)
+}
+
+// SplitCompound splits a compound unit into numerator and denominator.
+// Returns ok=false for simple units. Example: "kg/L" → ("kg", "L", true).
+func SplitCompound(unit string) (numerator, denominator string, ok bool) {
+	numerator, denominator, ok = strings.Cut(unit, CompoundSeparator)
+	if !ok || numerator == "" || denominator == "" {
+		return unit, "", false
+	}
+	return numerator, denominator, true
+}
+
+// SameUnit reports whether two unit names refer to the same unit,
+// accounting for aliases and symbols (e.g., "liters" and "L").
+func SameUnit(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	ca, okA := NormalizeUnitName(a)
+	cb, okB := NormalizeUnitName(b)
+	return okA && okB && ca == cb
+}
+
+// symbolOrName returns the standard symbol for a known unit, or the name as-is.
+func symbolOrName(unit string) string {
+	if symbol, ok := GetUnitSymbol(unit); ok {
+		return symbol
+	}
+	return unit
+}
@@ -0,0 +1,54 @@
+package units
+
+import "testing"
+
+func TestCompound(t *testing.T) {
+	tests := []struct {
+		numerator, denominator string
+		want                   string
+	}{
+		{"kilograms", "liters", "kg/l"},
+		{"meters", "kilograms", "m/kg"},
+		{"$", "kg", "$/kg"},
+		{"widgets", "crate", "widgets/crate"},
+		{"kg", "", "kg"},
+	}
+
+	for _, tt := range tests {
+		if got := Compound(tt.numerator, tt.denominator); got != tt.want {
+			t.Errorf("Compound(%q, %q) = %q, want %q", tt.numerator, tt.denominator, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCompound(t *testing.T) {
+	num, den, ok := SplitCompound("kg/l")
+	if !ok || num != "kg" || den != "l" {
+		t.Errorf("SplitCompound(kg/l) = %q, %q, %v", num, den, ok)
+	}
+	if _, _, ok := SplitCompound("kg"); ok {
+		t.Error("SplitCompound(kg) should not be compound")
+	}
+	if _, _, ok := SplitCompound("/kg"); ok {
+		t.Error("SplitCompound(/kg) should not be compound")
+	}
+}
+
+func TestSameUnit(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"liters", "l", true},
+		{"kg", "kilograms", true},
+		{"widgets", "Widgets", true},
+		{"kg", "g", false},
+		{"widgets", "gadgets", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameUnit(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameUnit(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
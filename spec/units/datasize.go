@@ -0,0 +1,132 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DataSizeBase distinguishes SI (1000-based) from IEC (1024-based) prefixes.
+type DataSizeBase int
+
+const (
+	// DataSizeUnprefixed is a bare bit or byte, valid in either system.
+	DataSizeUnprefixed DataSizeBase = iota
+	// DataSizeSI is 1000-based: kbit, Mbit, Mbps (networking).
+	DataSizeSI
+	// DataSizeIEC is 1024-based: KiB, MiB, GiB.
+	DataSizeIEC
+)
+
+// DataSizeUnit describes one data-size unit in terms of bits.
+type DataSizeUnit struct {
+	Symbol    string          // Display symbol: "MiB", "Mbit", "Mbps"
+	Bits      decimal.Decimal // Number of bits in one unit
+	Base      DataSizeBase    // SI or IEC prefix (DataSizeUnprefixed for bit/byte)
+	Bytes     bool            // Byte-based (B) rather than bit-based (b)
+	PerSecond bool            // Bandwidth unit (bits per second)
+}
+
+// dataSizePrecision is the number of decimal places kept by ConvertDataSize.
+const dataSizePrecision = 40
+
+// dataSizeUnits maps lowercase names, symbols, and plurals to their unit.
+//
+// KB, MB, GB, TB, PB, and EB are SI, powers of 1000 bytes as in storage and
+// networking; KiB, MiB, GiB, TiB, PiB, and EiB are the powers of 1024.
+// Bandwidth units (bps, kbps, Mbps, Gbps, Tbps) are always SI.
+var dataSizeUnits = buildDataSizeUnits()
+
+func buildDataSizeUnits() map[string]DataSizeUnit {
+	table := make(map[string]DataSizeUnit)
+	add := func(u DataSizeUnit, names ...string) {
+		table[strings.ToLower(u.Symbol)] = u
+		for _, name := range names {
+			table[strings.ToLower(name)] = u
+		}
+	}
+
+	bit := decimal.NewFromInt(1)
+	byteBits := decimal.NewFromInt(8)
+	thousand := decimal.NewFromInt(1000)
+	kibi := decimal.NewFromInt(1024)
+
+	add(DataSizeUnit{Symbol: "bit", Bits: bit}, "bits")
+	add(DataSizeUnit{Symbol: "bytes", Bits: byteBits, Bytes: true}, "b", "byte")
+	add(DataSizeUnit{Symbol: "bps", Bits: bit, Base: DataSizeSI, PerSecond: true})
+
+	prefixes := []struct {
+		si, iec string // Symbol prefixes: "k", "Ki"
+		siName  string // Name prefixes: "kilo", "kibi"
+		iecName string
+		siBytes string // Byte symbol: "KB"
+	}{
+		{"k", "Ki", "kilo", "kibi", "KB"},
+		{"M", "Mi", "mega", "mebi", "MB"},
+		{"G", "Gi", "giga", "gibi", "GB"},
+		{"T", "Ti", "tera", "tebi", "TB"},
+		{"P", "Pi", "peta", "pebi", "PB"},
+		{"E", "Ei", "exa", "exbi", "EB"},
+	}
+
+	siFactor, iecFactor := decimal.NewFromInt(1), decimal.NewFromInt(1)
+	for _, p := range prefixes {
+		siFactor = siFactor.Mul(thousand)
+		iecFactor = iecFactor.Mul(kibi)
+
+		add(DataSizeUnit{Symbol: p.si + "bit", Bits: siFactor, Base: DataSizeSI},
+			p.siName+"bit", p.siName+"bits")
+		add(DataSizeUnit{Symbol: p.si + "bps", Bits: siFactor, Base: DataSizeSI, PerSecond: true})
+		add(DataSizeUnit{Symbol: p.iec + "B", Bits: iecFactor.Mul(byteBits), Base: DataSizeIEC, Bytes: true},
+			p.iecName+"byte", p.iecName+"bytes")
+		add(DataSizeUnit{Symbol: p.iec + "bit", Bits: iecFactor, Base: DataSizeIEC},
+			p.iecName+"bit", p.iecName+"bits")
+		add(DataSizeUnit{Symbol: p.siBytes, Bits: siFactor.Mul(byteBits), Base: DataSizeSI, Bytes: true},
+			p.siName+"byte", p.siName+"bytes")
+	}
+
+	return table
+}
+
+// LookupDataSize returns the data-size unit for a name or symbol (case-insensitive).
+func LookupDataSize(unit string) (DataSizeUnit, bool) {
+	u, ok := dataSizeUnits[strings.ToLower(strings.TrimSpace(unit))]
+	return u, ok
+}
+
+// IsBandwidthUnit reports whether unit is a bits-per-second unit (Mbps, Gbps, ...).
+func IsBandwidthUnit(unit string) bool {
+	u, ok := LookupDataSize(unit)
+	return ok && u.PerSecond
+}
+
+// BandwidthDataUnit returns the data unit a bandwidth unit counts per
+// second: "Mbps" → "Mbit", "bps" → "bit". Other units are returned as-is.
+func BandwidthDataUnit(unit string) string {
+	u, ok := LookupDataSize(unit)
+	if !ok || !u.PerSecond {
+		return unit
+	}
+	return strings.TrimSuffix(u.Symbol, "ps") + "it"
+}
+
+// ConvertDataSize converts value between data-size units exactly.
+// Bandwidth units convert only to other bandwidth units.
+// Example: ConvertDataSize(1, "GiB", "MiB") → 1024.
+func ConvertDataSize(value decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	src, ok := LookupDataSize(from)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unknown data size unit: %s", from)
+	}
+	dst, ok := LookupDataSize(to)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unknown data size unit: %s", to)
+	}
+	if src.PerSecond != dst.PerSecond {
+		return decimal.Zero, fmt.Errorf("cannot convert %s to %s (data size vs bandwidth)", from, to)
+	}
+	// Factors are products of 2s and 10s, so the quotient always terminates;
+	// the extra places keep it exact where the default 16 would round
+	return value.Mul(src.Bits).DivRound(dst.Bits, dataSizePrecision), nil
+}
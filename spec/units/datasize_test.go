@@ -0,0 +1,99 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConvertDataSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		from, to string
+		want     string
+	}{
+		{"1", "GiB", "MB", "1073.741824"},
+		{"1", "GiB", "MiB", "1024"},
+		{"1", "GB", "MB", "1000"},
+		{"1", "MB", "bytes", "1000000"},
+		{"1", "KiB", "bytes", "1024"},
+		{"1", "byte", "bits", "8"},
+		{"1", "Gbit", "Mbit", "1000"},
+		{"1", "MB", "Mbit", "8"},
+		{"1", "MiB", "Mbit", "8.388608"},
+		{"1", "Gbps", "Mbps", "1000"},
+		{"1", "gibibyte", "mebibytes", "1024"},
+		{"1", "Gbit", "MB", "125"},
+		{"1", "Gbit", "MiB", "119.2092895507812500"},
+	}
+
+	for _, tt := range tests {
+		got, err := ConvertDataSize(decimal.RequireFromString(tt.value), tt.from, tt.to)
+		if err != nil {
+			t.Errorf("ConvertDataSize(%s %s → %s): %v", tt.value, tt.from, tt.to, err)
+			continue
+		}
+		if !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("ConvertDataSize(%s %s → %s) = %s, want %s", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvertDataSizeErrors(t *testing.T) {
+	if _, err := ConvertDataSize(decimal.NewFromInt(1), "GB", "Mbps"); err == nil {
+		t.Error("expected error converting data size to bandwidth")
+	}
+	if _, err := ConvertDataSize(decimal.NewFromInt(1), "GB", "meters"); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
+
+func TestLookupDataSize(t *testing.T) {
+	tests := []struct {
+		unit  string
+		base  DataSizeBase
+		bytes bool
+	}{
+		{"MiB", DataSizeIEC, true},
+		{"mebibytes", DataSizeIEC, true},
+		{"MB", DataSizeSI, true},
+		{"megabytes", DataSizeSI, true},
+		{"Mbit", DataSizeSI, false},
+		{"Mibit", DataSizeIEC, false},
+		{"bytes", DataSizeUnprefixed, true},
+	}
+
+	for _, tt := range tests {
+		u, ok := LookupDataSize(tt.unit)
+		if !ok {
+			t.Errorf("LookupDataSize(%q) not found", tt.unit)
+			continue
+		}
+		if u.Base != tt.base || u.Bytes != tt.bytes {
+			t.Errorf("LookupDataSize(%q) = %+v", tt.unit, u)
+		}
+	}
+}
+
+func TestBandwidthUnits(t *testing.T) {
+	tests := []struct {
+		unit      string
+		bandwidth bool
+		data      string
+	}{
+		{"Mbps", true, "Mbit"},
+		{"gbps", true, "Gbit"},
+		{"bps", true, "bit"},
+		{"MB", false, "MB"},
+		{"meters", false, "meters"},
+	}
+
+	for _, tt := range tests {
+		if got := IsBandwidthUnit(tt.unit); got != tt.bandwidth {
+			t.Errorf("IsBandwidthUnit(%q) = %v, want %v", tt.unit, got, tt.bandwidth)
+		}
+		if got := BandwidthDataUnit(tt.unit); got != tt.data {
+			t.Errorf("BandwidthDataUnit(%q) = %q, want %q", tt.unit, got, tt.data)
+		}
+	}
+}
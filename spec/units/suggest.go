@@ -0,0 +1,36 @@
+package units
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/CalcMark/go-calcmark/spec/fuzzy"
+)
+
+var (
+	unitNamesOnce sync.Once
+	unitNames     []string
+)
+
+// SuggestUnits returns up to limit known unit names (canonical names,
+// symbols, and aliases) that are close to input, closest first.
+// Returns nil if nothing is a plausible match.
+//
+// Examples: "metrs" -> ["meters"], "kilogrm" -> ["kilogram"]
+func SuggestUnits(input string, limit int) []string {
+	unitNamesOnce.Do(func() {
+		seen := make(map[string]bool)
+		for _, unit := range StandardUnits {
+			for _, name := range append([]string{unit.Canonical, unit.Symbol}, unit.Aliases...) {
+				if name != "" && !seen[name] {
+					seen[name] = true
+					unitNames = append(unitNames, name)
+				}
+			}
+		}
+		// Map iteration order is random; sort so ties resolve deterministically
+		slices.Sort(unitNames)
+	})
+
+	return fuzzy.Closest(input, unitNames, limit)
+}